@@ -2,13 +2,19 @@ package agent
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"metamorph/internal/agent/tools"
 	"metamorph/internal/logger"
+	"metamorph/internal/metrics"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
 )
 
 // LoopProtection holds settings for preventing infinite loops
@@ -45,6 +51,9 @@ func NewLoopProtection() LoopProtection {
 	}
 }
 
+// DefaultMaxToolInputSize is the largest tool input, in bytes, accepted when no limit is configured
+const DefaultMaxToolInputSize = 1 << 20
+
 // Agent represents a Claude-powered conversational agent with tool usage
 type Agent struct {
 	client         *anthropic.Client
@@ -53,16 +62,42 @@ type Agent struct {
 	model          string
 	maxTokens      int64
 	loopProtection LoopProtection
+	idleTimeout    time.Duration
+	maxInputSize   int
+	readOnly       bool
+	sessionID      string
+	messagePrefix  string
+	messageSuffix  string
+	maxTurns       int
+	turns          int
+	toolCallDelay  time.Duration
+	lastToolCall   time.Time
+	workingDir     string
+	apiBaseline    *tools.APIBaseline
+	setupErr       error
+	rateLimit      RateLimitStatus
+	rateLimitMutex sync.Mutex
 }
 
 // Config holds configuration options for creating a new Agent
 type Config struct {
-	Client         *anthropic.Client
-	GetUserMessage func() (string, bool)
-	Tools          []tools.ToolDefinition
-	Model          string
-	MaxTokens      int64
-	LoopProtection *LoopProtection // Optional custom loop protection settings
+	Client          *anthropic.Client
+	GetUserMessage  func() (string, bool)
+	Tools           []tools.ToolDefinition
+	Model           string
+	MaxTokens       int64
+	LoopProtection  *LoopProtection // Optional custom loop protection settings
+	IdleTimeout     time.Duration   // Optional idle timeout while waiting for user input (0 disables it)
+	MaxInputSize    int             // Optional maximum tool input size in bytes (defaults to DefaultMaxToolInputSize)
+	ReadOnly        bool            // Refuse all mutating tool calls when true
+	SessionID       string          // Optional session identifier attached to tool logs (generated if empty)
+	MessagePrefix   string          // Optional text prepended to every user message
+	MessageSuffix   string          // Optional text appended to every user message
+	MaxTurns        int             // Optional maximum number of user turns per session (0 means unlimited)
+	ToolCallDelay   time.Duration   // Optional minimum delay between consecutive tool executions (0 disables it)
+	AllowedTools    []string        // Optional allowlist of tool names; any other tool is never registered
+	StrictAllowlist bool            // Fail Run when AllowedTools names a tool that does not exist
+	WorkingDir      string          // Optional directory relative tool paths resolve against (defaults to the process CWD)
 }
 
 // New creates a new Agent with the provided configuration
@@ -85,22 +120,121 @@ func New(config Config) *Agent {
 			Msg("Using custom loop protection settings")
 	}
 
+	sessionID := config.SessionID
+	if sessionID == "" {
+		sessionID = newSessionID()
+	}
+
+	maxInputSize := config.MaxInputSize
+	if maxInputSize <= 0 {
+		maxInputSize = DefaultMaxToolInputSize
+	}
+
+	// Only vetted tools are registered when an allowlist is configured
+	agentTools := config.Tools
+	var setupErr error
+	if len(config.AllowedTools) > 0 {
+		var excluded, unknown []string
+		agentTools, excluded, unknown = filterAllowedTools(config.Tools, config.AllowedTools)
+		if len(excluded) > 0 {
+			log.Info().
+				Strs("excluded", excluded).
+				Int("registered", len(agentTools)).
+				Msg("Excluded tools not in the allowlist")
+		}
+		// A misspelled entry silently disables the tool it meant to allow
+		if len(unknown) > 0 {
+			log.Warn().Strs("unknown", unknown).Msg("Allowlist names tools that do not exist")
+			if config.StrictAllowlist {
+				setupErr = allowlistError(unknown)
+			}
+		}
+	}
+
+	// Resolve tool paths against the configured directory without changing the process CWD.
+	// The root travels in each tool call's context, so agents with different roots don't interfere.
+	var workingDir string
+	if config.WorkingDir != "" && setupErr == nil {
+		workingDir, setupErr = tools.ResolveWorkspaceRoot(config.WorkingDir)
+		if setupErr == nil {
+			log.Debug().Str("workingDir", workingDir).Msg("Using configured working directory")
+		}
+	}
+
+	// Snapshotting the sources before the first edit is only worth it if the agent can compare against them
+	var apiBaseline *tools.APIBaseline
+	for _, tool := range agentTools {
+		if tool.Name == tools.CheckAPIStabilityToolDefinition.Name {
+			apiBaseline = tools.NewAPIBaseline()
+			break
+		}
+	}
+
 	return &Agent{
 		client:         config.Client,
 		getUserMessage: config.GetUserMessage,
-		tools:          config.Tools,
+		tools:          agentTools,
 		model:          config.Model,
 		maxTokens:      config.MaxTokens,
 		loopProtection: loopProtection,
+		idleTimeout:    config.IdleTimeout,
+		maxInputSize:   maxInputSize,
+		readOnly:       config.ReadOnly,
+		sessionID:      sessionID,
+		messagePrefix:  config.MessagePrefix,
+		messageSuffix:  config.MessageSuffix,
+		maxTurns:       config.MaxTurns,
+		toolCallDelay:  config.ToolCallDelay,
+		workingDir:     workingDir,
+		apiBaseline:    apiBaseline,
+		setupErr:       setupErr,
+	}
+}
+
+// newSessionID generates a random version 4 UUID identifying an agent session
+func newSessionID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("session-%d", time.Now().UnixNano())
 	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// SessionID returns the identifier of this agent's session
+func (a *Agent) SessionID() string {
+	return a.sessionID
 }
 
 // Run starts the agent's conversation loop
 func (a *Agent) Run(ctx context.Context) error {
+	if a.setupErr != nil {
+		return a.setupErr
+	}
+
 	conversation := []anthropic.MessageParam{}
-	logger.Get().Info().Msg("Starting chat with Claude (use 'ctrl-c' to quit)")
+	ctx = logger.WithSessionID(ctx, a.sessionID)
+	ctx = tools.WithWorkspaceRoot(ctx, a.workingDir)
+	logger.FromContext(ctx).Info().Msg("Starting chat with Claude (use 'ctrl-c' to quit)")
 
 	a.loopProtection.SessionStartTime = time.Now()
+	tools.ResetConversationState()
+	if a.apiBaseline != nil {
+		ctx = tools.WithAPIBaseline(ctx, a.apiBaseline)
+		a.apiBaseline.Reset()
+		defer a.apiBaseline.Reset()
+	}
+
+	// Remember when the previous session ended for changed_since, then record this one's end
+	if err := tools.LoadPreviousSession(ctx); err != nil {
+		logger.FromContext(ctx).Warn().Err(err).Msg("Failed to load the previous session time")
+	}
+	defer func() {
+		if err := tools.RecordSessionEnd(ctx); err != nil {
+			logger.FromContext(ctx).Warn().Err(err).Msg("Failed to record the session end time")
+		}
+	}()
 
 	readUserInput := true
 	for {
@@ -114,26 +248,37 @@ func (a *Agent) Run(ctx context.Context) error {
 		}
 
 		if readUserInput {
+			// Check conversation turn limit
+			if a.maxTurns > 0 && a.turns >= a.maxTurns {
+				logger.Get().Warn().
+					Int("turns", a.turns).
+					Int("limit", a.maxTurns).
+					Msg("Conversation turn limit reached. Please restart the agent to continue.")
+				break
+			}
+
 			a.loopProtection.ConsecutiveToolUses = 0
 			a.loopProtection.LastToolName = ""
 			a.loopProtection.SameToolCallCount = 0
 
-			if !a.readUserInputToConversation(&conversation) {
+			if !a.readUserInputToConversation(ctx, &conversation) {
 				break
 			}
+			a.turns++
 		}
 
-		message, err := a.generateResponse(ctx, conversation)
+		message, err := a.generateResponseWithRetry(ctx, conversation)
 		if err != nil {
 			return err
 		}
 
 		conversation = append(conversation, message.ToParam())
+		tools.RecordTokenUsage(inputTokens(message.Usage), message.Usage.OutputTokens)
 
 		// Process any tool uses and add results to conversation
-		readUserInput, err = a.processToolUsages(message, &conversation)
+		readUserInput, err = a.processToolUsages(ctx, message, &conversation)
 		if err != nil {
-			logger.Get().Error().Err(err).Msg("Error processing tool usage")
+			logger.Get().Error().Err(logger.RedactError(err)).Msg("Error processing tool usage")
 			readUserInput = true
 		}
 	}
@@ -142,22 +287,72 @@ func (a *Agent) Run(ctx context.Context) error {
 }
 
 // readUserInputToConversation prompts for and adds user input to the conversation
-// Returns false if input reading fails
-func (a *Agent) readUserInputToConversation(conversation *[]anthropic.MessageParam) bool {
+// Returns false if input reading fails or the idle timeout expires
+func (a *Agent) readUserInputToConversation(ctx context.Context, conversation *[]anthropic.MessageParam) bool {
 	fmt.Print("\u001b[94mYou\u001b[0m: ") // Keep this as fmt.Print for better UX
-	userInput, ok := a.getUserMessage()
+	userInput, ok := a.readUserMessage(ctx)
 	if !ok {
 		return false
 	}
 
-	userMessage := anthropic.NewUserMessage(anthropic.NewTextBlock(userInput))
+	tools.RecordUserRequest(userInput)
+	userMessage := anthropic.NewUserMessage(anthropic.NewTextBlock(a.wrapUserInput(userInput)))
 	*conversation = append(*conversation, userMessage)
 	return true
 }
 
+// wrapUserInput surrounds the user's input with the configured prefix and suffix
+func (a *Agent) wrapUserInput(userInput string) string {
+	if a.messagePrefix != "" {
+		userInput = a.messagePrefix + "\n\n" + userInput
+	}
+	if a.messageSuffix != "" {
+		userInput = userInput + "\n\n" + a.messageSuffix
+	}
+	return userInput
+}
+
+// readUserMessage reads the next user message, giving up after the idle timeout
+// or when the context is cancelled. Without an idle timeout it blocks as before.
+func (a *Agent) readUserMessage(ctx context.Context) (string, bool) {
+	if a.idleTimeout <= 0 {
+		return a.getUserMessage()
+	}
+
+	type userMessage struct {
+		text string
+		ok   bool
+	}
+
+	// Buffered so the reader goroutine can finish even if nobody is listening anymore
+	messages := make(chan userMessage, 1)
+	go func() {
+		text, ok := a.getUserMessage()
+		messages <- userMessage{text: text, ok: ok}
+	}()
+
+	timer := time.NewTimer(a.idleTimeout)
+	defer timer.Stop()
+
+	select {
+	case msg := <-messages:
+		return msg.text, msg.ok
+	case <-timer.C:
+		fmt.Println()
+		logger.Get().Warn().
+			Dur("idleTimeout", a.idleTimeout).
+			Msg("No user input received within idle timeout. Exiting.")
+		return "", false
+	case <-ctx.Done():
+		fmt.Println()
+		logger.Get().Warn().Err(ctx.Err()).Msg("Context cancelled while waiting for user input")
+		return "", false
+	}
+}
+
 // processToolUsages handles any tool uses in the message
 // Returns whether user input should be read next (true) or not (false) and any errors
-func (a *Agent) processToolUsages(message *anthropic.Message, conversation *[]anthropic.MessageParam) (bool, error) {
+func (a *Agent) processToolUsages(ctx context.Context, message *anthropic.Message, conversation *[]anthropic.MessageParam) (bool, error) {
 	toolResults := []anthropic.ContentBlockParamUnion{}
 
 	hasToolUses := false
@@ -168,8 +363,17 @@ func (a *Agent) processToolUsages(message *anthropic.Message, conversation *[]an
 		case "tool_use":
 			hasToolUses = true
 
+			// Benign tools don't count toward the consecutive and same-tool limits;
+			// unknown tools and any call that may modify the workspace do, to stay on the safe side
+			countsTowardLimit := true
+			if toolDef, found := a.findTool(content.Name); found {
+				countsTowardLimit = toolDef.CountsTowardLoopLimit || tools.IsMutatingToolCall(content.Name, content.Input)
+			}
+
 			// Check loop protection limits
-			a.loopProtection.ConsecutiveToolUses++
+			if countsTowardLimit {
+				a.loopProtection.ConsecutiveToolUses++
+			}
 			a.loopProtection.ToolUseCount++
 
 			// Check consecutive tool use limit
@@ -201,25 +405,32 @@ func (a *Agent) processToolUsages(message *anthropic.Message, conversation *[]an
 				a.loopProtection.ToolUseCount = 1
 			}
 
-			// Check same tool call limit
-			if a.loopProtection.LastToolName == content.Name {
-				a.loopProtection.SameToolCallCount++
-				if a.loopProtection.SameToolCallCount >= a.loopProtection.MaxSameToolCalls {
-					err := fmt.Errorf("too many consecutive calls to the same tool: %s (%d calls)",
-						content.Name, a.loopProtection.SameToolCallCount)
-					logger.Get().Error().
-						Str("tool", content.Name).
-						Int("callCount", a.loopProtection.SameToolCallCount).
-						Int("limit", a.loopProtection.MaxSameToolCalls).
-						Msg("Same tool call limit exceeded")
-					return true, err
+			// Check same tool call limit (benign calls neither extend nor break a run)
+			if countsTowardLimit {
+				if a.loopProtection.LastToolName == content.Name {
+					a.loopProtection.SameToolCallCount++
+					if a.loopProtection.SameToolCallCount >= a.loopProtection.MaxSameToolCalls {
+						err := fmt.Errorf("too many consecutive calls to the same tool: %s (%d calls)",
+							content.Name, a.loopProtection.SameToolCallCount)
+						logger.Get().Error().
+							Str("tool", content.Name).
+							Int("callCount", a.loopProtection.SameToolCallCount).
+							Int("limit", a.loopProtection.MaxSameToolCalls).
+							Msg("Same tool call limit exceeded")
+						return true, err
+					}
+				} else {
+					a.loopProtection.LastToolName = content.Name
+					a.loopProtection.SameToolCallCount = 1
 				}
-			} else {
-				a.loopProtection.LastToolName = content.Name
-				a.loopProtection.SameToolCallCount = 1
 			}
 
-			result := a.executeTool(content.ID, content.Name, content.Input)
+			// Pace bursts of tool calls instead of tripping the rate limits
+			if err := a.waitForToolCallDelay(ctx); err != nil {
+				return true, err
+			}
+
+			result := a.executeTool(ctx, content.ID, content.Name, content.Input)
 			toolResults = append(toolResults, result)
 		}
 	}
@@ -234,26 +445,107 @@ func (a *Agent) processToolUsages(message *anthropic.Message, conversation *[]an
 }
 
 // executeTool runs the specified tool and returns its result
-func (a *Agent) executeTool(id, name string, input json.RawMessage) anthropic.ContentBlockParamUnion {
+func (a *Agent) executeTool(ctx context.Context, id, name string, input json.RawMessage) anthropic.ContentBlockParamUnion {
+	response, err := a.runTool(ctx, name, input)
+	if err != nil {
+		return anthropic.NewToolResultBlock(id, err.Error(), true)
+	}
+	return anthropic.NewToolResultBlock(id, response, false)
+}
+
+// runTool applies the agent's checks to a tool call and runs it. It is also the path
+// macro steps take, so they get the same limits, logging and metrics as direct calls.
+func (a *Agent) runTool(ctx context.Context, name string, input json.RawMessage) (string, error) {
+	log := logger.FromContext(ctx)
+
 	toolDef, found := a.findTool(name)
 	if !found {
-		logger.Get().Error().
+		log.Error().
 			Str("tool", name).
 			Msg("Tool not found")
-		return anthropic.NewToolResultBlock(id, "tool not found", true)
+		return "", fmt.Errorf("tool not found: %s", name)
+	}
+	// Count the call before any rejection so error counts never exceed invocation counts
+	metrics.Get().RecordToolInvocation(name)
+
+	// Reject oversized inputs before they reach the tool
+	if len(input) > a.maxInputSize {
+		log.Error().
+			Str("tool", name).
+			Int("inputSize", len(input)).
+			Int("limit", a.maxInputSize).
+			Msg("Tool input exceeds size limit")
+		metrics.Get().RecordToolError(name)
+		return "", fmt.Errorf("tool input too large: %d bytes exceeds the limit of %d bytes", len(input), a.maxInputSize)
+	}
+
+	// Report every missing required field at once instead of the tool's first complaint
+	if missing := tools.MissingRequiredFields(toolDef, input); len(missing) > 0 {
+		log.Warn().
+			Str("tool", name).
+			Strs("missing", missing).
+			Msg("Tool input is missing required fields")
+		metrics.Get().RecordToolError(name)
+		return "", fmt.Errorf("missing required field(s) for %s: %s (required: %s)",
+			name, strings.Join(missing, ", "), strings.Join(toolDef.RequiredFields(), ", "))
+	}
+
+	// Macros must not reach tools that are not registered on this agent
+	if disallowed := a.disallowedMacroSteps(name, input); len(disallowed) > 0 {
+		log.Warn().
+			Str("tool", name).
+			Strs("disallowed", disallowed).
+			Msg("Refusing macro that calls unregistered tools")
+		metrics.Get().RecordToolError(name)
+		return "", fmt.Errorf("macro calls tool(s) not available in this session: %s", strings.Join(disallowed, ", "))
+	}
+
+	// Refuse anything that could modify the workspace in read-only mode
+	if a.readOnly && tools.IsMutatingToolCall(name, input) {
+		log.Warn().
+			Str("tool", name).
+			Msg("Refusing mutating tool call in read-only mode")
+		metrics.Get().RecordToolError(name)
+		return "", fmt.Errorf("read-only mode: %s would modify the workspace and is disabled for this session", name)
+	}
+
+	// Remember the exported API before the session's first edit so check_api_stability can compare against it
+	if a.apiBaseline != nil && tools.IsMutatingToolCall(name, input) {
+		a.apiBaseline.Snapshot(ctx)
 	}
 
-	log := logger.Get()
 	log.Info().
 		Str("tool", name).
-		RawJSON("input", input).
+		RawJSON("input", logger.RedactJSON(input)).
 		Msg("Executing tool")
-	response, err := toolDef.Function(input)
+	response, err := toolDef.Function(tools.WithToolRunner(ctx, a.runTool), input)
 	if err != nil {
-		return anthropic.NewToolResultBlock(id, err.Error(), true)
+		log.Warn().
+			Str("tool", name).
+			Err(logger.RedactError(err)).
+			Msg("Tool returned an error")
+		metrics.Get().RecordToolError(name)
+		return "", err
 	}
+	if event := log.Debug(); event.Enabled() {
+		event.Str("tool", name).
+			Str("output", truncateForLog(logger.Redact(response))).
+			Msg("Tool finished")
+	}
+	tools.RecordToolCall(ctx, name, input)
 
-	return anthropic.NewToolResultBlock(id, response, false)
+	return response, nil
+}
+
+// maxLoggedOutput is the number of bytes of tool output included in the debug log
+const maxLoggedOutput = 2000
+
+// truncateForLog shortens tool output for the debug log
+func truncateForLog(output string) string {
+	if len(output) <= maxLoggedOutput {
+		return output
+	}
+	return output[:maxLoggedOutput] + "... (truncated)"
 }
 
 // findTool searches for a tool by name
@@ -270,12 +562,78 @@ func (a *Agent) findTool(name string) (tools.ToolDefinition, bool) {
 func (a *Agent) generateResponse(ctx context.Context, conversation []anthropic.MessageParam) (*anthropic.Message, error) {
 	anthropicTools := a.prepareToolDefinitions()
 
-	return a.client.Messages.New(ctx, anthropic.MessageNewParams{
+	if err := a.waitForRateLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	var response *http.Response
+	message, err := a.client.Messages.New(ctx, anthropic.MessageNewParams{
 		Model:     a.model,
 		MaxTokens: a.maxTokens,
 		Messages:  conversation,
 		Tools:     anthropicTools,
-	})
+	}, option.WithMiddleware(countRetries), option.WithResponseInto(&response))
+	a.updateRateLimit(ctx, response)
+	if err != nil {
+		return nil, classifyAPIError(err)
+	}
+
+	metrics.Get().RecordTokens(inputTokens(message.Usage), message.Usage.OutputTokens)
+	return message, nil
+}
+
+// inputTokens returns the full size of a request, counting cached prompt tokens too
+func inputTokens(usage anthropic.Usage) int64 {
+	return usage.InputTokens + usage.CacheCreationInputTokens + usage.CacheReadInputTokens
+}
+
+// maxAPIRetries is how many times a transient API failure is retried beyond the client's own retries
+const maxAPIRetries = 3
+
+// apiRetryDelay is the base delay before retrying a transient API failure, multiplied by the attempt
+const apiRetryDelay = 5 * time.Second
+
+// generateResponseWithRetry calls generateResponse, retrying rate-limited, overloaded, and network
+// failures with a growing delay. Authentication and invalid-request errors are returned immediately.
+func (a *Agent) generateResponseWithRetry(ctx context.Context, conversation []anthropic.MessageParam) (*anthropic.Message, error) {
+	for attempt := 1; ; attempt++ {
+		message, err := a.generateResponse(ctx, conversation)
+		if err == nil {
+			return message, nil
+		}
+
+		apiErr, ok := err.(*ErrAPI)
+		if !ok || !apiErr.Retryable() || attempt > maxAPIRetries {
+			if ok && apiErr.Category == APIErrorAuth {
+				logger.FromContext(ctx).Error().Err(err).Msg("Authentication with the Anthropic API failed; check ANTHROPIC_API_KEY")
+			}
+			return nil, err
+		}
+
+		delay := time.Duration(attempt) * apiRetryDelay
+		logger.FromContext(ctx).Warn().
+			Err(err).
+			Str("category", string(apiErr.Category)).
+			Int("attempt", attempt).
+			Dur("delay", delay).
+			Msg("Transient API error, retrying")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// countRetries is a client middleware that records every retried API request
+func countRetries(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+	if retry := req.Header.Get("X-Stainless-Retry-Count"); retry != "" && retry != "0" {
+		metrics.Get().RecordAPIRetry()
+	}
+	return next(req)
 }
 
 // prepareToolDefinitions converts local tool definitions to Anthropic format
@@ -0,0 +1,62 @@
+package agent
+
+import (
+	"fmt"
+	"metamorph/internal/agent/tools"
+	"sort"
+	"strings"
+)
+
+// filterAllowedTools keeps the tools named in allowed, in their original order. It returns
+// the names of the tools it dropped and the allowlist entries that match no tool.
+func filterAllowedTools(toolDefs []tools.ToolDefinition, allowed []string) ([]tools.ToolDefinition, []string, []string) {
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		if name = strings.TrimSpace(name); name != "" {
+			allowedSet[name] = true
+		}
+	}
+
+	kept := make([]tools.ToolDefinition, 0, len(toolDefs))
+	known := make(map[string]bool, len(toolDefs))
+	var excluded []string
+	for _, tool := range toolDefs {
+		known[tool.Name] = true
+		if allowedSet[tool.Name] {
+			kept = append(kept, tool)
+		} else {
+			excluded = append(excluded, tool.Name)
+		}
+	}
+
+	var unknown []string
+	for name := range allowedSet {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(excluded)
+	sort.Strings(unknown)
+	return kept, excluded, unknown
+}
+
+// disallowedMacroSteps returns the tools a macro call would run that are not registered on the agent
+func (a *Agent) disallowedMacroSteps(name string, input []byte) []string {
+	if name != "macro" {
+		return nil
+	}
+	var disallowed []string
+	seen := map[string]bool{}
+	for _, step := range tools.MacroStepTools(input) {
+		if _, found := a.findTool(step); !found && !seen[step] {
+			seen[step] = true
+			disallowed = append(disallowed, step)
+		}
+	}
+	return disallowed
+}
+
+// allowlistError describes the allowlist entries a strict allowlist rejects because no tool has that name
+func allowlistError(unknown []string) error {
+	return fmt.Errorf("allowlist names unknown tool(s): %s", strings.Join(unknown, ", "))
+}
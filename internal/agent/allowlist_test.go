@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"metamorph/internal/agent/tools"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFilterAllowedTools(t *testing.T) {
+	toolDefs := []tools.ToolDefinition{echoTool("read"), echoTool("write"), echoTool("delete")}
+
+	kept, excluded, unknown := filterAllowedTools(toolDefs, []string{" delete ", "read", "", "reed"})
+	var names []string
+	for _, tool := range kept {
+		names = append(names, tool.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"read", "delete"}) {
+		t.Errorf("kept = %v, want read and delete in their original order", names)
+	}
+	if !reflect.DeepEqual(excluded, []string{"write"}) {
+		t.Errorf("excluded = %v, want write", excluded)
+	}
+	if !reflect.DeepEqual(unknown, []string{"reed"}) {
+		t.Errorf("unknown = %v, want reed", unknown)
+	}
+}
+
+func TestNewNeverRegistersDisallowedTools(t *testing.T) {
+	a, ctx := newWorkspaceAgent(t, Config{
+		Tools:        []tools.ToolDefinition{echoTool("read"), echoTool("write")},
+		AllowedTools: []string{"read"},
+	})
+
+	if _, found := a.findTool("write"); found {
+		t.Fatal("disallowed tool was registered")
+	}
+	if len(a.tools) != 1 || a.tools[0].Name != "read" {
+		t.Errorf("tools = %v, want only read", a.tools)
+	}
+	if _, err := a.runTool(ctx, "write", json.RawMessage(`{}`)); err == nil || !strings.Contains(err.Error(), "tool not found") {
+		t.Errorf("expected the disallowed tool to be unavailable, got %v", err)
+	}
+}
+
+func TestStrictAllowlistRejectsUnknownNames(t *testing.T) {
+	toolDefs := []tools.ToolDefinition{echoTool("read"), echoTool("write")}
+
+	// Dropping tools outside the list is the point of an allowlist, so it is not an error
+	if a := New(Config{Tools: toolDefs, AllowedTools: []string{"read"}, StrictAllowlist: true}); a.setupErr != nil {
+		t.Errorf("unexpected setup error: %v", a.setupErr)
+	}
+	if a := New(Config{Tools: toolDefs, AllowedTools: []string{"read", "raed"}}); a.setupErr != nil {
+		t.Errorf("a lenient allowlist should only warn about unknown names, got %v", a.setupErr)
+	}
+
+	a := New(Config{Tools: toolDefs, AllowedTools: []string{"read", "raed"}, StrictAllowlist: true})
+	if err := a.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "unknown tool(s): raed") {
+		t.Errorf("Run = %v, want the unknown allowlist entry reported", err)
+	}
+}
+
+func TestAllowlistBlocksMacroSteps(t *testing.T) {
+	a, ctx := newWorkspaceAgent(t, Config{
+		Tools:        []tools.ToolDefinition{tools.MacroToolDefinition, tools.FileEditorToolDefinition},
+		AllowedTools: []string{"macro"},
+	})
+	macro := tools.Macro{
+		Name: "test_allowlist_write",
+		Steps: []tools.MacroStep{
+			{Tool: "file_editor", Input: map[string]interface{}{"path": "out.txt", "mode": "create", "content": "x"}},
+		},
+	}
+	if err := tools.RegisterMacros([]tools.Macro{macro}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := a.runTool(ctx, "macro", json.RawMessage(`{"name": "test_allowlist_write"}`))
+	if err == nil || !strings.Contains(err.Error(), "not available in this session: file_editor") {
+		t.Errorf("expected the macro to be refused, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(a.workingDir, "out.txt")); !os.IsNotExist(err) {
+		t.Error("the refused macro wrote its file")
+	}
+}
@@ -1,7 +1,14 @@
 package agent
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/anthropics/anthropic-sdk-go"
 )
 
 // ErrLoopProtection indicates that a loop protection limit was reached
@@ -44,3 +51,81 @@ type ErrToolNotFound struct {
 func (e *ErrToolNotFound) Error() string {
 	return fmt.Sprintf("tool not found: %s", e.ToolName)
 }
+
+// APIErrorCategory groups Anthropic API failures by how the agent should react to them
+type APIErrorCategory string
+
+const (
+	APIErrorAuth           APIErrorCategory = "auth"
+	APIErrorInvalidRequest APIErrorCategory = "invalid_request"
+	APIErrorRateLimit      APIErrorCategory = "rate_limit"
+	APIErrorOverloaded     APIErrorCategory = "overloaded"
+	APIErrorNetwork        APIErrorCategory = "network"
+	APIErrorUnknown        APIErrorCategory = "unknown"
+)
+
+// ErrAPI indicates a failed call to the Anthropic API
+type ErrAPI struct {
+	Category   APIErrorCategory
+	StatusCode int
+	Err        error
+}
+
+func (e *ErrAPI) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("anthropic API error (%s, status %d): %v", e.Category, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("anthropic API error (%s): %v", e.Category, e.Err)
+}
+
+func (e *ErrAPI) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the request may succeed if sent again later
+func (e *ErrAPI) Retryable() bool {
+	switch e.Category {
+	case APIErrorRateLimit, APIErrorOverloaded, APIErrorNetwork:
+		return true
+	}
+	return false
+}
+
+// classifyAPIError wraps an error returned by the Anthropic client in an ErrAPI
+// describing its category. A nil error stays nil.
+func classifyAPIError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *ErrAPI
+	if errors.As(err, &apiErr) {
+		return err
+	}
+
+	classified := &ErrAPI{Category: APIErrorUnknown, Err: err}
+
+	var sdkErr *anthropic.Error
+	var netErr net.Error
+	switch {
+	case errors.As(err, &sdkErr):
+		classified.StatusCode = sdkErr.StatusCode
+		switch {
+		case sdkErr.StatusCode == http.StatusUnauthorized || sdkErr.StatusCode == http.StatusForbidden:
+			classified.Category = APIErrorAuth
+		case sdkErr.StatusCode == http.StatusTooManyRequests:
+			classified.Category = APIErrorRateLimit
+		case sdkErr.StatusCode >= 500:
+			// 529 is Anthropic's overloaded status; other 5xx errors are similarly transient
+			classified.Category = APIErrorOverloaded
+		case sdkErr.StatusCode >= 400:
+			classified.Category = APIErrorInvalidRequest
+		}
+	case errors.Is(err, context.Canceled):
+		// Cancellation is deliberate, not a failure worth retrying
+	case errors.As(err, &netErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, context.DeadlineExceeded):
+		classified.Category = APIErrorNetwork
+	}
+
+	return classified
+}
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClassifyAPIErrorStatusCodes(t *testing.T) {
+	tests := []struct {
+		status    int
+		want      APIErrorCategory
+		retryable bool
+	}{
+		{http.StatusUnauthorized, APIErrorAuth, false},
+		{http.StatusForbidden, APIErrorAuth, false},
+		{http.StatusBadRequest, APIErrorInvalidRequest, false},
+		{http.StatusTooManyRequests, APIErrorRateLimit, true},
+		{529, APIErrorOverloaded, true},
+		{http.StatusInternalServerError, APIErrorOverloaded, true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"type": "error", "error": {"type": "test_error", "message": "failed"}}`))
+			})
+			a := New(Config{Client: client, Model: "claude-test", MaxTokens: 16})
+
+			_, err := a.generateResponse(context.Background(), nil)
+			var apiErr *ErrAPI
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected an ErrAPI, got %v", err)
+			}
+			if apiErr.Category != tt.want || apiErr.StatusCode != tt.status || apiErr.Retryable() != tt.retryable {
+				t.Errorf("got category %s, status %d, retryable %v", apiErr.Category, apiErr.StatusCode, apiErr.Retryable())
+			}
+		})
+	}
+}
+
+func TestClassifyAPIErrorTransportFailures(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want APIErrorCategory
+	}{
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, APIErrorNetwork},
+		{"unexpected eof", fmt.Errorf("reading body: %w", io.ErrUnexpectedEOF), APIErrorNetwork},
+		{"deadline", context.DeadlineExceeded, APIErrorNetwork},
+		{"canceled", context.Canceled, APIErrorUnknown},
+		{"other", errors.New("boom"), APIErrorUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var apiErr *ErrAPI
+			if err := classifyAPIError(tt.err); !errors.As(err, &apiErr) || apiErr.Category != tt.want {
+				t.Errorf("classifyAPIError(%v) = %v, want category %s", tt.err, err, tt.want)
+			}
+			if !errors.Is(apiErr, tt.err) {
+				t.Error("classified error should unwrap to the original")
+			}
+		})
+	}
+
+	if classifyAPIError(nil) != nil {
+		t.Error("a nil error should stay nil")
+	}
+	wrapped := &ErrAPI{Category: APIErrorAuth, Err: errors.New("denied")}
+	if classifyAPIError(wrapped) != error(wrapped) {
+		t.Error("an already classified error should be returned as is")
+	}
+}
+
+func TestGenerateResponseWithRetryAbortsOnAuth(t *testing.T) {
+	var requests atomic.Int32
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"type": "error", "error": {"type": "authentication_error", "message": "invalid x-api-key"}}`))
+	})
+	a := New(Config{Client: client, Model: "claude-test", MaxTokens: 16})
+
+	_, err := a.generateResponseWithRetry(context.Background(), nil)
+	var apiErr *ErrAPI
+	if !errors.As(err, &apiErr) || apiErr.Category != APIErrorAuth {
+		t.Fatalf("expected an auth error, got %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("auth failure was sent %d times, want 1", n)
+	}
+}
+
+func TestGenerateResponseWithRetryStopsWaitingOnDeadline(t *testing.T) {
+	var requests atomic.Int32
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(529)
+	})
+	a := New(Config{Client: client, Model: "claude-test", MaxTokens: 16})
+
+	// The overloaded response is retried after apiRetryDelay, which outlasts the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err := a.generateResponseWithRetry(ctx, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want the retry wait to end at the deadline", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("sent %d requests, want 1 before the deadline", n)
+	}
+}
@@ -0,0 +1,170 @@
+package agent
+
+import (
+	"context"
+	"metamorph/internal/logger"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// lowRateLimitFraction is the remaining share of a rate limit below which requests are delayed
+const lowRateLimitFraction = 0.05
+
+// maxRateLimitBackoff caps how long the agent waits for a rate limit window to reset
+const maxRateLimitBackoff = 60 * time.Second
+
+// RateLimitWindow describes one rate limit reported by the API
+type RateLimitWindow struct {
+	Limit     int64     // Maximum allowed in the current window
+	Remaining int64     // Amount left in the current window
+	Reset     time.Time // When the window resets
+}
+
+// low reports whether the remaining budget has fallen below lowRateLimitFraction of the limit
+func (w RateLimitWindow) low() bool {
+	return w.Limit > 0 && float64(w.Remaining) < float64(w.Limit)*lowRateLimitFraction
+}
+
+// RateLimitStatus holds the latest rate limit values returned by the Messages API
+type RateLimitStatus struct {
+	Requests     RateLimitWindow
+	Tokens       RateLimitWindow
+	InputTokens  RateLimitWindow
+	OutputTokens RateLimitWindow
+	UpdatedAt    time.Time // Zero until a response with rate limit headers has been received
+}
+
+// parseRateLimitHeaders reads the anthropic-ratelimit-* response headers.
+// The second return value is false when the response carried none of them.
+func parseRateLimitHeaders(header http.Header) (RateLimitStatus, bool) {
+	status := RateLimitStatus{}
+	found := false
+
+	for name, window := range map[string]*RateLimitWindow{
+		"requests":      &status.Requests,
+		"tokens":        &status.Tokens,
+		"input-tokens":  &status.InputTokens,
+		"output-tokens": &status.OutputTokens,
+	} {
+		prefix := "anthropic-ratelimit-" + name + "-"
+		if value := header.Get(prefix + "limit"); value != "" {
+			window.Limit, _ = strconv.ParseInt(value, 10, 64)
+			found = true
+		}
+		if value := header.Get(prefix + "remaining"); value != "" {
+			window.Remaining, _ = strconv.ParseInt(value, 10, 64)
+			found = true
+		}
+		if value := header.Get(prefix + "reset"); value != "" {
+			window.Reset, _ = time.Parse(time.RFC3339, value)
+		}
+	}
+
+	if found {
+		status.UpdatedAt = time.Now()
+	}
+	return status, found
+}
+
+// RateLimitStatus returns the rate limit values from the most recent API response
+func (a *Agent) RateLimitStatus() RateLimitStatus {
+	a.rateLimitMutex.Lock()
+	defer a.rateLimitMutex.Unlock()
+	return a.rateLimit
+}
+
+// updateRateLimit records the rate limit headers of resp and logs the remaining budget
+func (a *Agent) updateRateLimit(ctx context.Context, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	status, ok := parseRateLimitHeaders(resp.Header)
+	if !ok {
+		return
+	}
+
+	a.rateLimitMutex.Lock()
+	a.rateLimit = status
+	a.rateLimitMutex.Unlock()
+
+	event := logger.FromContext(ctx).Debug()
+	if status.Requests.low() || status.Tokens.low() {
+		event = logger.FromContext(ctx).Warn()
+	}
+	event.
+		Int64("requestsRemaining", status.Requests.Remaining).
+		Int64("requestsLimit", status.Requests.Limit).
+		Int64("tokensRemaining", status.Tokens.Remaining).
+		Int64("tokensLimit", status.Tokens.Limit).
+		Msg("API rate limit status")
+}
+
+// rateLimitBackoff returns how long to wait before the next request, which is
+// non-zero only while a remaining budget is low and its window has not yet reset
+func (a *Agent) rateLimitBackoff() time.Duration {
+	status := a.RateLimitStatus()
+
+	var wait time.Duration
+	for _, window := range []RateLimitWindow{status.Requests, status.Tokens, status.InputTokens, status.OutputTokens} {
+		if !window.low() || window.Reset.IsZero() {
+			continue
+		}
+		if until := time.Until(window.Reset); until > wait {
+			wait = until
+		}
+	}
+
+	if wait > maxRateLimitBackoff {
+		wait = maxRateLimitBackoff
+	}
+	return wait
+}
+
+// waitForRateLimit sleeps for the rate limit backoff, returning early if ctx is cancelled
+func (a *Agent) waitForRateLimit(ctx context.Context) error {
+	wait := a.rateLimitBackoff()
+	if wait <= 0 {
+		return nil
+	}
+
+	logger.FromContext(ctx).Warn().
+		Dur("wait", wait).
+		Msg("Rate limit budget is low, backing off before the next request")
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// waitForToolCallDelay sleeps until the configured delay has passed since the previous tool
+// execution, returning early if ctx is cancelled
+func (a *Agent) waitForToolCallDelay(ctx context.Context) error {
+	if a.toolCallDelay <= 0 {
+		return nil
+	}
+
+	if !a.lastToolCall.IsZero() {
+		if wait := a.toolCallDelay - time.Since(a.lastToolCall); wait > 0 {
+			logger.FromContext(ctx).Debug().
+				Dur("wait", wait).
+				Msg("Pacing tool calls")
+
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	a.lastToolCall = time.Now()
+	return nil
+}
@@ -0,0 +1,169 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"metamorph/internal/agent/tools"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+)
+
+// mockMessageResponse is a minimal Messages API reply ending the turn
+const mockMessageResponse = `{
+	"id": "msg_test",
+	"type": "message",
+	"role": "assistant",
+	"model": "claude-test",
+	"content": [{"type": "text", "text": "done"}],
+	"stop_reason": "end_turn",
+	"usage": {"input_tokens": 10, "output_tokens": 2}
+}`
+
+// newMockClient returns a client whose requests are answered by handler
+func newMockClient(t *testing.T, handler http.HandlerFunc) *anthropic.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client := anthropic.NewClient(
+		option.WithBaseURL(server.URL),
+		option.WithAPIKey("test-key"),
+		option.WithMaxRetries(0),
+	)
+	return &client
+}
+
+func TestGenerateResponseParsesRateLimitHeaders(t *testing.T) {
+	reset := time.Now().Add(30 * time.Second).UTC().Truncate(time.Second)
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("anthropic-ratelimit-requests-limit", "50")
+		w.Header().Set("anthropic-ratelimit-requests-remaining", "49")
+		w.Header().Set("anthropic-ratelimit-requests-reset", reset.Format(time.RFC3339))
+		w.Header().Set("anthropic-ratelimit-tokens-limit", "100000")
+		w.Header().Set("anthropic-ratelimit-tokens-remaining", "1200")
+		w.Header().Set("anthropic-ratelimit-tokens-reset", reset.Format(time.RFC3339))
+		w.Header().Set("anthropic-ratelimit-output-tokens-limit", "8000")
+		w.Header().Set("anthropic-ratelimit-output-tokens-remaining", "7990")
+		w.Write([]byte(mockMessageResponse))
+	})
+	a := New(Config{Client: client, Model: "claude-test", MaxTokens: 16})
+
+	if !a.RateLimitStatus().UpdatedAt.IsZero() {
+		t.Fatal("expected no rate limit status before the first request")
+	}
+	if _, err := a.generateResponse(context.Background(), nil); err != nil {
+		t.Fatalf("generateResponse: %v", err)
+	}
+
+	status := a.RateLimitStatus()
+	if status.UpdatedAt.IsZero() {
+		t.Fatal("rate limit status was not updated")
+	}
+	if status.Requests.Limit != 50 || status.Requests.Remaining != 49 || !status.Requests.Reset.Equal(reset) {
+		t.Errorf("requests = %+v", status.Requests)
+	}
+	if status.Tokens.Limit != 100000 || status.Tokens.Remaining != 1200 {
+		t.Errorf("tokens = %+v", status.Tokens)
+	}
+	if status.OutputTokens.Remaining != 7990 || status.InputTokens.Limit != 0 {
+		t.Errorf("output %+v input %+v", status.OutputTokens, status.InputTokens)
+	}
+
+	// 1200 of 100000 tokens is below the low-budget threshold, so the next request waits for the reset
+	if wait := a.rateLimitBackoff(); wait <= 0 || wait > maxRateLimitBackoff {
+		t.Errorf("backoff = %v, want a wait until the token window resets", wait)
+	}
+}
+
+func TestParseRateLimitHeadersMissing(t *testing.T) {
+	if _, ok := parseRateLimitHeaders(http.Header{"Content-Type": []string{"application/json"}}); ok {
+		t.Error("expected no rate limit status without the headers")
+	}
+}
+
+func TestRateLimitBackoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		window RateLimitWindow
+		want   bool
+	}{
+		{"plenty left", RateLimitWindow{Limit: 100, Remaining: 50, Reset: time.Now().Add(time.Minute)}, false},
+		{"low budget", RateLimitWindow{Limit: 100, Remaining: 1, Reset: time.Now().Add(10 * time.Second)}, true},
+		{"low budget already reset", RateLimitWindow{Limit: 100, Remaining: 1, Reset: time.Now().Add(-time.Second)}, false},
+		{"low budget without reset", RateLimitWindow{Limit: 100, Remaining: 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New(Config{})
+			a.rateLimit = RateLimitStatus{Requests: tt.window}
+			if wait := a.rateLimitBackoff(); (wait > 0) != tt.want {
+				t.Errorf("backoff = %v, want a wait: %v", wait, tt.want)
+			}
+		})
+	}
+
+	a := New(Config{})
+	a.rateLimit = RateLimitStatus{Tokens: RateLimitWindow{Limit: 100, Remaining: 0, Reset: time.Now().Add(time.Hour)}}
+	if wait := a.rateLimitBackoff(); wait != maxRateLimitBackoff {
+		t.Errorf("backoff = %v, want it capped at %v", wait, maxRateLimitBackoff)
+	}
+}
+
+func TestToolCallDelayPacesExecutions(t *testing.T) {
+	var calls []time.Time
+	recorder := tools.ToolDefinition{
+		Name:        "record",
+		InputSchema: tools.GenerateSchema[struct{}](),
+		Function: func(ctx context.Context, input json.RawMessage) (string, error) {
+			calls = append(calls, time.Now())
+			return "ok", nil
+		},
+	}
+	const delay = 50 * time.Millisecond
+	a, ctx := newWorkspaceAgent(t, Config{Tools: []tools.ToolDefinition{recorder}, ToolCallDelay: delay})
+
+	var conversation []anthropic.MessageParam
+	if _, err := a.processToolUsages(ctx, toolUseMessage("record", "{}", "{}", "{}"), &conversation); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("got %d calls, want 3", len(calls))
+	}
+	// The delay is measured from just before each call starts, not from when the tool function
+	// runs, so allow for the scheduling jitter between the two
+	const tolerance = 5 * time.Millisecond
+	for i := 1; i < len(calls); i++ {
+		if gap := calls[i].Sub(calls[i-1]); gap < delay-tolerance {
+			t.Errorf("gap before call %d = %v, want at least %v", i+1, gap, delay-tolerance)
+		}
+	}
+}
+
+func TestToolCallDelayDisabled(t *testing.T) {
+	a := New(Config{})
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := a.waitForToolCallDelay(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("disabled delay still waited %v", elapsed)
+	}
+}
+
+func TestToolCallDelayHonorsCancellation(t *testing.T) {
+	a := New(Config{ToolCallDelay: time.Hour})
+	a.lastToolCall = time.Now()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := a.waitForToolCallDelay(ctx); err != context.Canceled {
+		t.Errorf("got %v, want the wait to end on cancellation", err)
+	}
+}
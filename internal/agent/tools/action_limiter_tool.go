@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sync"
@@ -24,7 +25,7 @@ It helps prevent the agent from getting stuck in loops or making too many rapid
 
 // ActionLimiterInput defines the input parameters for the action_limiter tool
 type ActionLimiterInput struct {
-	Action     string `json:"action" jsonschema_description:"The action being performed (e.g., 'edit_file', 'create_file')"`
+	Action     string `json:"action" jsonschema_required:"true" jsonschema_description:"The action being performed (e.g., 'edit_file', 'create_file')"`
 	Target     string `json:"target,omitempty" jsonschema_description:"The target of the action (e.g., file path)"`
 	CheckOnly  bool   `json:"check_only,omitempty" jsonschema_description:"If true, only check limits without recording the action"`
 	ResetState bool   `json:"reset_state,omitempty" jsonschema_description:"If true, reset all counters and state"`
@@ -46,6 +47,13 @@ type ActionStats struct {
 	LastTarget        string                    `json:"last_target"`
 }
 
+// Limits enforced by checkLimits
+const (
+	maxSessionActions     = 50 // actions per session
+	maxTargetActions      = 10 // actions on one target
+	maxConsecutiveActions = 5  // identical actions in a row
+)
+
 var (
 	stats         ActionStats
 	statsMutex    sync.Mutex
@@ -79,19 +87,19 @@ func checkLimits() (bool, string) {
 	}
 
 	// Check total actions limit (e.g., 50 actions per session)
-	if stats.TotalActions >= 50 {
-		return true, "Total action limit exceeded (50 actions)"
+	if stats.TotalActions >= maxSessionActions {
+		return true, fmt.Sprintf("Total action limit exceeded (%d actions)", maxSessionActions)
 	}
 
 	// Check for too many actions on the same target (e.g., 10 edits to the same file)
 	for target, count := range stats.ActionsByTarget {
-		if count >= 10 {
+		if count >= maxTargetActions {
 			return true, fmt.Sprintf("Too many actions (%d) on the same target: %s", count, target)
 		}
 	}
 
 	// Check for too many consecutive identical actions
-	if stats.ConsecutiveSame >= 5 {
+	if stats.ConsecutiveSame >= maxConsecutiveActions {
 		return true, fmt.Sprintf("Too many consecutive identical actions (%d): %s on %s",
 			stats.ConsecutiveSame, stats.LastAction, stats.LastTarget)
 	}
@@ -165,7 +173,7 @@ func resetStats() {
 }
 
 // ActionLimiter implements the action_limiter tool functionality
-func ActionLimiter(input json.RawMessage) (string, error) {
+func ActionLimiter(ctx context.Context, input json.RawMessage) (string, error) {
 	actionLimiterInput := ActionLimiterInput{}
 	err := json.Unmarshal(input, &actionLimiterInput)
 	if err != nil {
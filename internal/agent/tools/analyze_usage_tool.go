@@ -0,0 +1,202 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// defaultCouplingThreshold is the reference count at which an import is flagged as heavily coupled
+const defaultCouplingThreshold = 10
+
+// AnalyzeUsageToolDefinition defines the analyze_usage tool
+var AnalyzeUsageToolDefinition = ToolDefinition{
+	Name: "analyze_usage",
+	Description: `Count how heavily a Go file uses each of its imports.
+For every import the tool reports how many qualified references (pkg.Name) the file makes and which
+identifiers it uses, classifies the import as stdlib, external or intra-module, flags unused imports,
+and flags packages referenced at least 'coupling_threshold' times (default 10) as heavily coupled.
+Blank (_) and dot (.) imports are listed but not counted. Unaliased imports are matched by the package
+name implied by their path, as goimports does.`,
+	InputSchema: AnalyzeUsageInputSchema,
+	Function:    AnalyzeUsage,
+}
+
+// AnalyzeUsageInput defines the input parameters for the analyze_usage tool
+type AnalyzeUsageInput struct {
+	Path              string `json:"path" jsonschema_required:"true" jsonschema_description:"Go file to analyze"`
+	ModulePrefix      string `json:"module_prefix,omitempty" jsonschema_description:"Import path prefix of intra-module packages. Defaults to the module path in the nearest go.mod."`
+	CouplingThreshold int    `json:"coupling_threshold,omitempty" jsonschema_description:"Reference count at which an import is flagged as heavily coupled. Defaults to 10."`
+}
+
+// AnalyzeUsageInputSchema is the JSON schema for the analyze_usage tool
+var AnalyzeUsageInputSchema = GenerateSchema[AnalyzeUsageInput]()
+
+// ImportUsage describes how one import is used in the file
+type ImportUsage struct {
+	Path        string         `json:"path"`
+	Name        string         `json:"name"`
+	Kind        string         `json:"kind"`
+	References  int            `json:"references"`
+	Identifiers map[string]int `json:"identifiers,omitempty"`
+	Unused      bool           `json:"unused,omitempty"`
+	Coupled     bool           `json:"heavily_coupled,omitempty"`
+	Note        string         `json:"note,omitempty"`
+}
+
+// AnalyzeUsageOutput represents the structured output of the analyze_usage tool
+type AnalyzeUsageOutput struct {
+	Path            string        `json:"path"`
+	Imports         []ImportUsage `json:"imports"`
+	TotalReferences int           `json:"total_references"`
+	Unused          []string      `json:"unused,omitempty"`
+	HeavilyCoupled  []string      `json:"heavily_coupled,omitempty"`
+}
+
+// importKinds names the groups returned by importGroup
+var importKinds = [...]string{"stdlib", "external", "internal"}
+
+// AnalyzeUsage implements the analyze_usage tool functionality
+func AnalyzeUsage(ctx context.Context, input json.RawMessage) (string, error) {
+	usageInput := AnalyzeUsageInput{}
+	err := DecodeInput(input, &usageInput)
+	if err != nil {
+		return "", err
+	}
+
+	if usageInput.Path == "" {
+		return "", fmt.Errorf("path parameter is required")
+	}
+	usageInput.Path, err = ResolvePath(ctx, usageInput.Path)
+	if err != nil {
+		return "", err
+	}
+	if filepath.Ext(usageInput.Path) != ".go" {
+		return "", fmt.Errorf("%s is not a Go file", usageInput.Path)
+	}
+
+	threshold := usageInput.CouplingThreshold
+	if threshold <= 0 {
+		threshold = defaultCouplingThreshold
+	}
+
+	modulePrefix := strings.TrimSuffix(usageInput.ModulePrefix, "/")
+	if modulePrefix == "" {
+		modulePrefix, _ = nearestModulePath(filepath.Dir(usageInput.Path))
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, usageInput.Path, nil, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", usageInput.Path, err)
+	}
+
+	output := AnalyzeUsageOutput{
+		Path:    usageInput.Path,
+		Imports: []ImportUsage{},
+	}
+
+	byName := map[string]*ImportUsage{}
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return "", fmt.Errorf("invalid import path %s: %w", spec.Path.Value, err)
+		}
+		usage := ImportUsage{
+			Path: importPath,
+			Name: assumedPackageName(importPath),
+			Kind: importKinds[importGroup(importPath, modulePrefix)],
+		}
+		if spec.Name != nil {
+			usage.Name = spec.Name.Name
+		}
+		switch usage.Name {
+		case "_":
+			usage.Note = "blank import for side effects"
+		case ".":
+			usage.Note = "dot import; references are not counted"
+		}
+		output.Imports = append(output.Imports, usage)
+	}
+	for i := range output.Imports {
+		if name := output.Imports[i].Name; name != "_" && name != "." {
+			byName[name] = &output.Imports[i]
+		}
+	}
+
+	// Qualifiers that resolve to no local object refer to imported packages
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := sel.X.(*ast.Ident)
+		if !ok || ident.Obj != nil {
+			return true
+		}
+		if usage, found := byName[ident.Name]; found {
+			usage.References++
+			if usage.Identifiers == nil {
+				usage.Identifiers = map[string]int{}
+			}
+			usage.Identifiers[sel.Sel.Name]++
+			output.TotalReferences++
+		}
+		return true
+	})
+
+	for i := range output.Imports {
+		usage := &output.Imports[i]
+		if usage.Note != "" {
+			continue
+		}
+		if usage.References == 0 {
+			usage.Unused = true
+			output.Unused = append(output.Unused, usage.Path)
+		}
+		if usage.References >= threshold {
+			usage.Coupled = true
+			output.HeavilyCoupled = append(output.HeavilyCoupled, usage.Path)
+		}
+	}
+
+	// Most heavily used imports first
+	sort.SliceStable(output.Imports, func(i, j int) bool {
+		return output.Imports[i].References > output.Imports[j].References
+	})
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// assumedPackageName guesses the package name of an unaliased import from its path: the last
+// element, skipping a major version suffix such as /v2, without a go- prefix and cut at the first
+// character that cannot appear in an identifier (so gopkg.in/yaml.v3 becomes yaml)
+func assumedPackageName(importPath string) string {
+	base := path.Base(importPath)
+	if strings.HasPrefix(base, "v") {
+		if _, err := strconv.Atoi(base[1:]); err == nil && path.Dir(importPath) != "." {
+			base = path.Base(path.Dir(importPath))
+		}
+	}
+	base = strings.TrimPrefix(base, "go-")
+	if i := strings.IndexFunc(base, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}); i >= 0 {
+		base = base[:i]
+	}
+	return base
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const analyzeUsageFixture = `package app
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	zlog "github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+
+	"example.com/app/internal/store"
+)
+
+func Run(r chi.Router) error {
+	name := strings.TrimSpace(strings.ToLower(" App "))
+	zlog.Info().Msg(fmt.Sprintf("starting %s", name))
+	zlog.Debug().Msg(fmt.Sprint(yaml.Marshal(name)))
+	return store.Open(name)
+}
+
+func local() {
+	store := struct{ Save func() }{}
+	store.Save()
+}
+`
+
+func TestAnalyzeUsageCountsReferences(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+	writeTestFile(t, dir, "app.go", analyzeUsageFixture)
+
+	var output AnalyzeUsageOutput
+	callTool(t, ctx, AnalyzeUsage, AnalyzeUsageInput{Path: "app.go", CouplingThreshold: 2}, &output)
+
+	byPath := map[string]ImportUsage{}
+	for _, usage := range output.Imports {
+		byPath[usage.Path] = usage
+	}
+	tests := []struct {
+		path        string
+		name        string
+		kind        string
+		identifiers map[string]int
+	}{
+		{"fmt", "fmt", "stdlib", map[string]int{"Sprintf": 1, "Sprint": 1}},
+		{"strings", "strings", "stdlib", map[string]int{"TrimSpace": 1, "ToLower": 1}},
+		{"os", "os", "stdlib", nil},
+		{"github.com/go-chi/chi/v5", "chi", "external", map[string]int{"Router": 1}},
+		{"github.com/rs/zerolog/log", "zlog", "external", map[string]int{"Info": 1, "Debug": 1}},
+		{"gopkg.in/yaml.v3", "yaml", "external", map[string]int{"Marshal": 1}},
+		{"example.com/app/internal/store", "store", "internal", map[string]int{"Open": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			usage := byPath[tt.path]
+			if usage.Name != tt.name || usage.Kind != tt.kind {
+				t.Errorf("got name %q kind %q, want %q %q", usage.Name, usage.Kind, tt.name, tt.kind)
+			}
+			if !reflect.DeepEqual(usage.Identifiers, tt.identifiers) {
+				t.Errorf("identifiers = %v, want %v", usage.Identifiers, tt.identifiers)
+			}
+			references := 0
+			for _, count := range tt.identifiers {
+				references += count
+			}
+			if usage.References != references {
+				t.Errorf("references = %d, want %d", usage.References, references)
+			}
+		})
+	}
+
+	if output.TotalReferences != 9 {
+		t.Errorf("total references = %d, want 9", output.TotalReferences)
+	}
+	if !reflect.DeepEqual(output.Unused, []string{"os"}) {
+		t.Errorf("unused = %v, want os", output.Unused)
+	}
+	if !reflect.DeepEqual(output.HeavilyCoupled, []string{"fmt", "strings", "github.com/rs/zerolog/log"}) {
+		t.Errorf("heavily coupled = %v", output.HeavilyCoupled)
+	}
+	if embed := byPath["embed"]; embed.Unused || !strings.Contains(embed.Note, "blank import") {
+		t.Errorf("embed = %+v, want a blank import that is not flagged unused", embed)
+	}
+	if output.Imports[0].References != 2 {
+		t.Errorf("imports should be sorted by references, first is %+v", output.Imports[0])
+	}
+}
+
+func TestAssumedPackageName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"fmt", "fmt"},
+		{"net/http", "http"},
+		{"github.com/go-chi/chi/v5", "chi"},
+		{"gopkg.in/yaml.v3", "yaml"},
+		{"github.com/mattn/go-sqlite3", "sqlite3"},
+		{"github.com/google/go-cmp/cmp", "cmp"},
+		{"example.com/my-lib", "my"},
+	}
+	for _, tt := range tests {
+		if got := assumedPackageName(tt.path); got != tt.want {
+			t.Errorf("assumedPackageName(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
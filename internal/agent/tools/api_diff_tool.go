@@ -0,0 +1,401 @@
+package tools
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// APIDiffToolDefinition defines the api_diff tool
+var APIDiffToolDefinition = ToolDefinition{
+	Name: "api_diff",
+	Description: `Compare the exported API of the Go packages in the working tree with a git ref.
+Each public package (internal, testdata and main packages are skipped) is type-checked at both points
+and its exported functions, variables, constants, types, struct fields and methods are compared.
+Removed symbols and changed signatures are breaking, as are methods added to an exported interface;
+other additions are compatible. Returns the added, removed and changed symbols and the semver bump
+they require ('major', 'minor' or 'patch').`,
+	InputSchema: APIDiffInputSchema,
+	Function:    APIDiff,
+}
+
+// APIDiffInput defines the input parameters for the api_diff tool
+type APIDiffInput struct {
+	Ref  string `json:"ref" jsonschema_required:"true" jsonschema_description:"Git ref to compare the working tree against" jsonschema_example:"v1.2.0"`
+	Path string `json:"path,omitempty" jsonschema_description:"Module directory to compare. Defaults to the current directory."`
+}
+
+// APIDiffInputSchema is the JSON schema for the api_diff tool
+var APIDiffInputSchema = GenerateSchema[APIDiffInput]()
+
+// APIChange describes one exported symbol that differs between the ref and the working tree
+type APIChange struct {
+	Package  string `json:"package"`
+	Symbol   string `json:"symbol"`
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+	Breaking bool   `json:"breaking"`
+}
+
+// APIDiffOutput represents the structured output of the api_diff tool
+type APIDiffOutput struct {
+	Ref           string      `json:"ref"`
+	Packages      int         `json:"packages"`
+	Added         []APIChange `json:"added"`
+	Removed       []APIChange `json:"removed"`
+	Changed       []APIChange `json:"changed"`
+	Breaking      int         `json:"breaking"`
+	SuggestedBump string      `json:"suggested_bump"`
+}
+
+// APIDiff implements the api_diff tool functionality
+func APIDiff(ctx context.Context, input json.RawMessage) (string, error) {
+	diffInput := APIDiffInput{}
+	err := DecodeInput(input, &diffInput)
+	if err != nil {
+		return "", err
+	}
+
+	if diffInput.Ref == "" {
+		return "", fmt.Errorf("ref parameter is required")
+	}
+	if strings.HasPrefix(diffInput.Ref, "-") || strings.ContainsAny(diffInput.Ref, ": \t\n") {
+		return "", fmt.Errorf("invalid ref: %q", diffInput.Ref)
+	}
+
+	root := workspaceDir(ctx)
+	if diffInput.Path != "" {
+		root, err = ResolvePath(ctx, diffInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultGitTimeout)
+	defer cancel()
+
+	tmpDir, err := os.MkdirTemp("", "metamorph-apidiff-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldRoot, err := extractGitTree(ctx, root, diffInput.Ref, tmpDir)
+	if err != nil {
+		return "", err
+	}
+
+	before, err := exportedAPI(ctx, oldRoot, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the API at %s: %w", diffInput.Ref, err)
+	}
+	after, err := exportedAPI(ctx, root, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the API of the working tree: %w", err)
+	}
+
+	output := APIDiffOutput{Ref: diffInput.Ref}
+	output.Packages, output.Added, output.Removed, output.Changed = compareAPI(before, after)
+	for _, changes := range [][]APIChange{output.Added, output.Removed, output.Changed} {
+		for _, change := range changes {
+			if change.Breaking {
+				output.Breaking++
+			}
+		}
+	}
+
+	switch {
+	case output.Breaking > 0:
+		output.SuggestedBump = "major"
+	case len(output.Added) > 0:
+		output.SuggestedBump = "minor"
+	default:
+		output.SuggestedBump = "patch"
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// compareAPI compares two sets of exported symbols and returns the number of packages involved
+// and the added, removed and changed symbols, each sorted by package and symbol
+func compareAPI(before, after map[string]apiSymbol) (int, []APIChange, []APIChange, []APIChange) {
+	added, removed, changed := []APIChange{}, []APIChange{}, []APIChange{}
+	packages := map[string]bool{}
+	for key, symbol := range before {
+		packages[symbol.pkg] = true
+		current, ok := after[key]
+		switch {
+		case !ok:
+			removed = append(removed, APIChange{Package: symbol.pkg, Symbol: symbol.name, Before: symbol.decl, Breaking: true})
+		case current.signature != symbol.signature:
+			changed = append(changed, APIChange{Package: symbol.pkg, Symbol: symbol.name, Before: symbol.decl, After: current.decl, Breaking: true})
+		}
+	}
+	for key, symbol := range after {
+		packages[symbol.pkg] = true
+		if _, ok := before[key]; !ok {
+			// Every implementation of an existing interface lacks a newly added method
+			_, interfaceExisted := before[symbol.pkg+"."+symbol.owner]
+			breaking := symbol.interfaceMethod && interfaceExisted
+			added = append(added, APIChange{Package: symbol.pkg, Symbol: symbol.name, After: symbol.decl, Breaking: breaking})
+		}
+	}
+
+	for _, changes := range [][]APIChange{added, removed, changed} {
+		sort.Slice(changes, func(i, j int) bool {
+			if changes[i].Package != changes[j].Package {
+				return changes[i].Package < changes[j].Package
+			}
+			return changes[i].Symbol < changes[j].Symbol
+		})
+	}
+	return len(packages), added, removed, changed
+}
+
+// extractGitTree writes the tree of the repository containing dir at ref into dest and
+// returns the directory in dest corresponding to dir
+func extractGitTree(ctx context.Context, dir, ref, dest string) (string, error) {
+	prefixCmd := gitCommand(ctx, "rev-parse", "--show-prefix")
+	prefixCmd.Dir = dir
+	prefix, err := prefixCmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s is not inside a git repository: %w", dir, err)
+	}
+
+	verifyCmd := gitCommand(ctx, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	verifyCmd.Dir = dir
+	if output, err := verifyCmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("unknown ref %q: %s", ref, strings.TrimSpace(string(output)))
+	}
+
+	archiveCmd := gitCommand(ctx, "archive", "--format=tar", ref)
+	archiveCmd.Dir = dir
+	stdout, err := archiveCmd.StdoutPipe()
+	if err != nil {
+		return "", fmt.Errorf("failed to run git archive: %w", err)
+	}
+	if err := archiveCmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to run git archive: %w", err)
+	}
+
+	extractErr := extractTar(stdout, dest)
+	// Drain the rest of the archive so git does not block on a full pipe
+	io.Copy(io.Discard, stdout)
+	if err := archiveCmd.Wait(); err != nil {
+		return "", fmt.Errorf("git archive %s failed: %w", ref, err)
+	}
+	if extractErr != nil {
+		return "", extractErr
+	}
+
+	return filepath.Join(dest, filepath.FromSlash(strings.TrimSpace(string(prefix)))), nil
+}
+
+// extractTar writes the regular files and directories of a tar stream beneath dest
+func extractTar(r io.Reader, dest string) error {
+	reader := tar.NewReader(r)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		target, err := SafeJoin(dest, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", target, err)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", target, err)
+			}
+			_, copyErr := io.Copy(file, reader)
+			closeErr := file.Close()
+			if copyErr != nil || closeErr != nil {
+				return fmt.Errorf("failed to write %s: %w", target, errors.Join(copyErr, closeErr))
+			}
+		}
+	}
+}
+
+// apiSymbol is one exported declaration of a package
+type apiSymbol struct {
+	pkg             string
+	name            string
+	decl            string
+	signature       string // decl without parameter names, which callers do not depend on
+	owner           string // type declaring a field or method
+	interfaceMethod bool
+}
+
+// exportedAPI type-checks every public package under root, and internal packages too if
+// includePrivate is set, and returns their exported symbols keyed by package directory and name
+func exportedAPI(ctx context.Context, root string, includePrivate bool) (map[string]apiSymbol, error) {
+	files, err := goFilesUnder(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := map[string]bool{}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		relDir, err := filepath.Rel(root, filepath.Dir(file))
+		if err != nil {
+			continue
+		}
+		relDir = filepath.ToSlash(relDir)
+		if isPrivatePackageDir(relDir) && !includePrivate {
+			continue
+		}
+		dirs[relDir] = true
+	}
+
+	symbols := map[string]apiSymbol{}
+	for relDir := range dirs {
+		fset := token.NewFileSet()
+		pkg, err := typeCheckDir(ctx, fset, filepath.Join(root, filepath.FromSlash(relDir)))
+		if err != nil || pkg.Name() == "main" {
+			continue
+		}
+		for _, symbol := range packageSymbols(relDir, pkg) {
+			if symbol.signature == "" {
+				symbol.signature = symbol.decl
+			}
+			symbols[symbol.pkg+"."+symbol.name] = symbol
+		}
+	}
+	return symbols, nil
+}
+
+// isPrivatePackageDir reports whether a package directory is not importable by other modules
+func isPrivatePackageDir(relDir string) bool {
+	for _, element := range strings.Split(relDir, "/") {
+		if element == "internal" || element == "testdata" {
+			return true
+		}
+	}
+	return false
+}
+
+// packageSymbols lists the exported declarations of pkg, including exported struct fields and
+// the exported methods of named types and interfaces
+func packageSymbols(relDir string, pkg *types.Package) []apiSymbol {
+	qualifier := func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Path()
+	}
+
+	var symbols []apiSymbol
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+
+		typeName, ok := obj.(*types.TypeName)
+		if !ok {
+			symbol := apiSymbol{pkg: relDir, name: name, decl: types.ObjectString(obj, qualifier)}
+			if fn, ok := obj.(*types.Func); ok {
+				symbol.signature = "func " + name + unnamedSignature(fn.Type().(*types.Signature), qualifier)
+			}
+			symbols = append(symbols, symbol)
+			continue
+		}
+
+		decl := "type " + name + " " + types.TypeString(typeName.Type().Underlying(), qualifier)
+		if typeName.IsAlias() {
+			decl = "type " + name + " = " + types.TypeString(typeName.Type(), qualifier)
+		}
+		switch underlying := typeName.Type().Underlying().(type) {
+		case *types.Struct:
+			decl = "type " + name + " struct"
+			for i := 0; i < underlying.NumFields(); i++ {
+				field := underlying.Field(i)
+				if field.Exported() {
+					symbols = append(symbols, apiSymbol{
+						pkg:   relDir,
+						name:  name + "." + field.Name(),
+						decl:  "field " + field.Name() + " " + types.TypeString(field.Type(), qualifier),
+						owner: name,
+					})
+				}
+			}
+		case *types.Interface:
+			decl = "type " + name + " interface"
+			for i := 0; i < underlying.NumMethods(); i++ {
+				method := underlying.Method(i)
+				symbols = append(symbols, apiSymbol{
+					pkg:             relDir,
+					name:            name + "." + method.Name(),
+					decl:            "method " + method.Name() + strings.TrimPrefix(types.TypeString(method.Type(), qualifier), "func"),
+					signature:       "method " + method.Name() + unnamedSignature(method.Type().(*types.Signature), qualifier),
+					owner:           name,
+					interfaceMethod: true,
+				})
+			}
+		}
+		symbols = append(symbols, apiSymbol{pkg: relDir, name: name, decl: decl})
+
+		if _, isInterface := typeName.Type().Underlying().(*types.Interface); isInterface || typeName.IsAlias() {
+			continue
+		}
+		methodSet := types.NewMethodSet(types.NewPointer(typeName.Type()))
+		for i := 0; i < methodSet.Len(); i++ {
+			method := methodSet.At(i).Obj().(*types.Func)
+			if !method.Exported() {
+				continue
+			}
+			symbols = append(symbols, apiSymbol{
+				pkg:       relDir,
+				name:      name + "." + method.Name(),
+				decl:      types.ObjectString(method, qualifier),
+				signature: "method " + method.Name() + unnamedSignature(method.Type().(*types.Signature), qualifier),
+				owner:     name,
+			})
+		}
+	}
+	return symbols
+}
+
+// unnamedSignature renders the parameters and results of sig without their names
+func unnamedSignature(sig *types.Signature, qualifier types.Qualifier) string {
+	unnamed := func(tuple *types.Tuple) *types.Tuple {
+		vars := make([]*types.Var, tuple.Len())
+		for i := range vars {
+			vars[i] = types.NewVar(token.NoPos, nil, "", tuple.At(i).Type())
+		}
+		return types.NewTuple(vars...)
+	}
+	stripped := types.NewSignatureType(nil, nil, nil, unnamed(sig.Params()), unnamed(sig.Results()), sig.Variadic())
+	return strings.TrimPrefix(types.TypeString(stripped, qualifier), "func")
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const apiDiffBase = `package lib
+
+const Version = "1"
+
+type Client struct{ Addr string }
+
+func (c *Client) Get(key string) (string, error) { return "", nil }
+
+type Store interface {
+	Load(key string) string
+}
+
+func Helper(x int) int { return x }
+`
+
+// writeAPIDiffRepo commits apiDiffBase with private and main packages and returns the commit
+func writeAPIDiffRepo(t *testing.T, dir string) string {
+	t.Helper()
+	initTestRepo(t, dir)
+	testGoModule(t, dir, "example.com/lib")
+	writeTestFile(t, dir, "lib.go", apiDiffBase)
+	writeTestFile(t, dir, "internal/priv/priv.go", "package priv\n\nfunc Exported() {}\n")
+	writeTestFile(t, dir, "cmd/tool/main.go", "package main\n\nfunc Run() {}\n\nfunc main() {}\n")
+	return commitTestFiles(t, dir, "v1")
+}
+
+func TestAPIDiffReportsBreakingChanges(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeAPIDiffRepo(t, dir)
+	runTestGit(t, dir, "tag", "v1.0.0")
+
+	writeTestFile(t, dir, "lib.go", `package lib
+
+type Client struct {
+	Addr    string
+	Timeout int
+}
+
+func New() *Client { return &Client{} }
+
+func (c *Client) Get(key string, timeout int) (string, error) { return "", nil }
+
+type Store interface {
+	Load(key string) string
+	Save(key string)
+}
+
+func Helper(renamed int) int { return renamed }
+`)
+	// Private and main packages are not public API
+	writeTestFile(t, dir, "internal/priv/priv.go", "package priv\n")
+	writeTestFile(t, dir, "cmd/tool/main.go", "package main\n\nfunc main() {}\n")
+
+	var output APIDiffOutput
+	callTool(t, ctx, APIDiff, APIDiffInput{Ref: "v1.0.0"}, &output)
+
+	symbols := func(changes []APIChange) []string {
+		var names []string
+		for _, change := range changes {
+			names = append(names, change.Symbol)
+		}
+		return names
+	}
+	if got := symbols(output.Changed); !reflect.DeepEqual(got, []string{"Client.Get"}) {
+		t.Errorf("changed = %v, want only Client.Get (renaming a parameter is not a change)", got)
+	}
+	if got := symbols(output.Removed); !reflect.DeepEqual(got, []string{"Version"}) {
+		t.Errorf("removed = %v, want Version", got)
+	}
+	if got := symbols(output.Added); !reflect.DeepEqual(got, []string{"Client.Timeout", "New", "Store.Save"}) {
+		t.Errorf("added = %v", got)
+	}
+
+	get := output.Changed[0]
+	if !get.Breaking || !strings.Contains(get.Before, "Get(key string)") || !strings.Contains(get.After, "timeout int") {
+		t.Errorf("Client.Get = %+v, want a breaking signature change", get)
+	}
+	for _, change := range output.Added {
+		if wantBreaking := change.Symbol == "Store.Save"; change.Breaking != wantBreaking {
+			t.Errorf("%s breaking = %v, want %v", change.Symbol, change.Breaking, wantBreaking)
+		}
+	}
+	if output.Breaking != 3 || output.SuggestedBump != "major" || output.Packages != 1 {
+		t.Errorf("breaking %d bump %q packages %d, want 3 major 1", output.Breaking, output.SuggestedBump, output.Packages)
+	}
+}
+
+func TestAPIDiffSuggestedBump(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	base := writeAPIDiffRepo(t, dir)
+
+	var output APIDiffOutput
+	callTool(t, ctx, APIDiff, APIDiffInput{Ref: base}, &output)
+	if output.SuggestedBump != "patch" || len(output.Added)+len(output.Removed)+len(output.Changed) != 0 {
+		t.Errorf("got %+v, want no changes against the same tree", output)
+	}
+
+	writeTestFile(t, dir, "extra.go", "package lib\n\nfunc Extra() {}\n")
+	callTool(t, ctx, APIDiff, APIDiffInput{Ref: base}, &output)
+	if output.SuggestedBump != "minor" || output.Breaking != 0 {
+		t.Errorf("got %+v, want a compatible addition", output)
+	}
+}
+
+func TestAPIDiffInvalidRefs(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeAPIDiffRepo(t, dir)
+
+	for _, ref := range []string{"missing-tag", "--output=x", "HEAD:lib.go"} {
+		if _, err := APIDiff(ctx, mustMarshal(t, APIDiffInput{Ref: ref})); err == nil {
+			t.Errorf("ref %q: expected an error", ref)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// APIFingerprintToolDefinition defines the api_fingerprint tool
+var APIFingerprintToolDefinition = ToolDefinition{
+	Name: "api_fingerprint",
+	Description: `Compute a stable fingerprint of a Go package's exported API.
+The package is type-checked and its exported functions, variables, constants, types, struct fields
+and methods are hashed by name and signature, so the fingerprint ignores function bodies, comments,
+parameter names, unexported code and declaration order. Compare fingerprints across edits to tell
+whether the public contract changed; pass 'previous' to have the comparison done for you.`,
+	InputSchema: APIFingerprintInputSchema,
+	Function:    APIFingerprint,
+}
+
+// APIFingerprintInput defines the input parameters for the api_fingerprint tool
+type APIFingerprintInput struct {
+	Path           string `json:"path,omitempty" jsonschema_description:"Package directory. Defaults to the current directory."`
+	Previous       string `json:"previous,omitempty" jsonschema_description:"Fingerprint from an earlier call to compare against"`
+	IncludeSymbols bool   `json:"include_symbols,omitempty" jsonschema_description:"If true, also return the symbols the fingerprint is computed from"`
+}
+
+// APIFingerprintInputSchema is the JSON schema for the api_fingerprint tool
+var APIFingerprintInputSchema = GenerateSchema[APIFingerprintInput]()
+
+// APIFingerprintOutput represents the structured output of the api_fingerprint tool
+type APIFingerprintOutput struct {
+	Path        string   `json:"path"`
+	Package     string   `json:"package"`
+	Fingerprint string   `json:"fingerprint"`
+	SymbolCount int      `json:"symbol_count"`
+	Changed     *bool    `json:"changed,omitempty"`
+	Symbols     []string `json:"symbols,omitempty"`
+}
+
+// APIFingerprint implements the api_fingerprint tool functionality
+func APIFingerprint(ctx context.Context, input json.RawMessage) (string, error) {
+	fingerprintInput := APIFingerprintInput{}
+	err := DecodeInput(input, &fingerprintInput)
+	if err != nil {
+		return "", err
+	}
+
+	dir := workspaceDir(ctx)
+	if fingerprintInput.Path != "" {
+		dir, err = ResolvePath(ctx, fingerprintInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	pkg, err := typeCheckDir(ctx, token.NewFileSet(), dir)
+	if err != nil {
+		return "", err
+	}
+	var symbols []string
+	for _, symbol := range packageSymbols(".", pkg) {
+		signature := symbol.signature
+		if signature == "" {
+			signature = symbol.decl
+		}
+		symbols = append(symbols, symbol.name+"\t"+signature)
+	}
+	sort.Strings(symbols)
+
+	output := APIFingerprintOutput{
+		Path:        dir,
+		Package:     pkg.Name(),
+		Fingerprint: apiFingerprint(pkg.Name(), symbols),
+		SymbolCount: len(symbols),
+	}
+	if fingerprintInput.Previous != "" {
+		changed := fingerprintInput.Previous != output.Fingerprint
+		output.Changed = &changed
+	}
+	if fingerprintInput.IncludeSymbols {
+		output.Symbols = []string{}
+		for _, symbol := range symbols {
+			output.Symbols = append(output.Symbols, strings.Replace(symbol, "\t", ": ", 1))
+		}
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// apiFingerprint hashes a package name and its sorted exported symbols
+func apiFingerprint(pkgName string, symbols []string) string {
+	hasher := sha256.New()
+	hasher.Write([]byte("package " + pkgName + "\n"))
+	for _, symbol := range symbols {
+		hasher.Write([]byte(symbol + "\n"))
+	}
+	return "sha256:" + hex.EncodeToString(hasher.Sum(nil))
+}
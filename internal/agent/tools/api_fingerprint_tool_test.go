@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+const fingerprintSource = `package shapes
+
+// Area returns the area of a rectangle
+func Area(width, height int) int {
+	return width * height
+}
+
+type Rect struct {
+	Width, Height int
+	label         string
+}
+
+func (r Rect) Area() int { return Area(r.Width, r.Height) }
+
+func helper() {}
+`
+
+func TestAPIFingerprintIgnoresBodies(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/shapes")
+	writeTestFile(t, dir, "shapes.go", fingerprintSource)
+
+	var base APIFingerprintOutput
+	callTool(t, ctx, APIFingerprint, APIFingerprintInput{IncludeSymbols: true}, &base)
+	if base.Package != "shapes" || !strings.HasPrefix(base.Fingerprint, "sha256:") || base.Changed != nil {
+		t.Errorf("got %+v", base)
+	}
+	if base.SymbolCount != len(base.Symbols) || strings.Contains(strings.Join(base.Symbols, "\n"), "helper") {
+		t.Errorf("symbols = %q, want only exported ones", base.Symbols)
+	}
+
+	// Bodies, comments, parameter names, unexported code and order don't count
+	writeTestFile(t, dir, "shapes.go", `package shapes
+
+type Rect struct {
+	Width, Height int
+	name          string
+}
+
+func (r Rect) Area() int { return r.Width * r.Height }
+
+func Area(w, h int) int { return h * w }
+
+func other() {}
+`)
+	var unchanged APIFingerprintOutput
+	callTool(t, ctx, APIFingerprint, APIFingerprintInput{Previous: base.Fingerprint}, &unchanged)
+	if unchanged.Fingerprint != base.Fingerprint || unchanged.Changed == nil || *unchanged.Changed {
+		t.Errorf("fingerprint changed after a body-only edit: %+v", unchanged)
+	}
+}
+
+func TestAPIFingerprintDetectsSignatureChange(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/shapes")
+	writeTestFile(t, dir, "shapes.go", fingerprintSource)
+
+	var base APIFingerprintOutput
+	callTool(t, ctx, APIFingerprint, APIFingerprintInput{}, &base)
+
+	for name, source := range map[string]string{
+		"parameter type": strings.Replace(fingerprintSource, "func Area(width, height int) int", "func Area(width, height float64) int", 1),
+		"new method":     fingerprintSource + "\nfunc (r Rect) Perimeter() int { return 0 }\n",
+		"exported field": strings.Replace(fingerprintSource, "label ", "Label ", 1),
+	} {
+		writeTestFile(t, dir, "shapes.go", source)
+		var output APIFingerprintOutput
+		callTool(t, ctx, APIFingerprint, APIFingerprintInput{Previous: base.Fingerprint}, &output)
+		if output.Fingerprint == base.Fingerprint || output.Changed == nil || !*output.Changed {
+			t.Errorf("%s: fingerprint did not change: %+v", name, output)
+		}
+	}
+}
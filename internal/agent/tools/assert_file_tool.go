@@ -0,0 +1,531 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AssertFileToolDefinition defines the assert_file tool
+var AssertFileToolDefinition = ToolDefinition{
+	Name: "assert_file",
+	Description: `Check a file against a list of conditions and report whether each passes, e.g. to verify an
+edit before moving on. Condition types:
+- 'contains' / 'not_contains': the file does or does not match the regular expression 'pattern'
+- 'line_count': the number of lines is between 'min' and 'max' (a 'max' of 0 means no limit)
+- 'valid_json' / 'valid_yaml': the file parses as JSON or YAML
+- 'json_schema': the file, parsed as JSON or as YAML for .yaml and .yml files, matches the JSON
+  schema given inline in 'schema' or in the file 'schema_path'. Supported keywords: type, enum,
+  const, properties, required, additionalProperties, items, min/maxItems, uniqueItems,
+  min/maxLength, pattern, minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf,
+  allOf, anyOf, oneOf, not and local $ref.
+The file passes only if every condition does.`,
+	InputSchema: AssertFileInputSchema,
+	Function:    AssertFile,
+}
+
+// AssertCondition is one check to run against the file
+type AssertCondition struct {
+	Type       string                 `json:"type" jsonschema_required:"true" jsonschema_description:"Condition type: contains, not_contains, line_count, valid_json, valid_yaml or json_schema" jsonschema_example:"contains"`
+	Pattern    string                 `json:"pattern,omitempty" jsonschema_description:"Regular expression for contains and not_contains" jsonschema_example:"func New\\("`
+	Min        int                    `json:"min,omitempty" jsonschema_description:"Minimum line count for line_count"`
+	Max        int                    `json:"max,omitempty" jsonschema_description:"Maximum line count for line_count; 0 means no limit"`
+	Schema     map[string]interface{} `json:"schema,omitempty" jsonschema_description:"Inline JSON schema for json_schema"`
+	SchemaPath string                 `json:"schema_path,omitempty" jsonschema_description:"File containing the JSON schema for json_schema"`
+}
+
+// AssertFileInput defines the input parameters for the assert_file tool
+type AssertFileInput struct {
+	Path       string            `json:"path" jsonschema_required:"true" jsonschema_description:"File to check"`
+	Conditions []AssertCondition `json:"conditions" jsonschema_required:"true" jsonschema_description:"Conditions the file must meet"`
+}
+
+// AssertFileInputSchema is the JSON schema for the assert_file tool
+var AssertFileInputSchema = GenerateSchema[AssertFileInput]()
+
+// AssertResult is the outcome of one condition
+type AssertResult struct {
+	Condition string   `json:"condition"`
+	Passed    bool     `json:"passed"`
+	Message   string   `json:"message"`
+	Details   []string `json:"details,omitempty"`
+}
+
+// AssertFileOutput represents the structured output of the assert_file tool
+type AssertFileOutput struct {
+	Path    string         `json:"path"`
+	Passed  bool           `json:"passed"`
+	Results []AssertResult `json:"results"`
+	Message string         `json:"message"`
+}
+
+// maxSchemaViolations caps how many schema violations are reported for one condition
+const maxSchemaViolations = 20
+
+// AssertFile implements the assert_file tool functionality
+func AssertFile(ctx context.Context, input json.RawMessage) (string, error) {
+	assertInput := AssertFileInput{}
+	err := DecodeInput(input, &assertInput)
+	if err != nil {
+		return "", err
+	}
+
+	if assertInput.Path == "" {
+		return "", fmt.Errorf("path parameter is required")
+	}
+	if len(assertInput.Conditions) == 0 {
+		return "", fmt.Errorf("at least one condition is required")
+	}
+	path, err := ResolvePath(ctx, assertInput.Path)
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	output := AssertFileOutput{Path: path, Passed: true, Results: []AssertResult{}}
+	failed := 0
+	for i, condition := range assertInput.Conditions {
+		result, err := checkAssertCondition(ctx, path, content, condition)
+		if err != nil {
+			return "", fmt.Errorf("condition %d (%s): %w", i+1, condition.Type, err)
+		}
+		if !result.Passed {
+			output.Passed = false
+			failed++
+		}
+		output.Results = append(output.Results, result)
+	}
+
+	if output.Passed {
+		output.Message = fmt.Sprintf("All %d condition(s) passed.", len(output.Results))
+	} else {
+		output.Message = fmt.Sprintf("%d of %d condition(s) failed.", failed, len(output.Results))
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// checkAssertCondition evaluates one condition against the file content. Errors are reserved
+// for conditions that are themselves invalid, such as a bad pattern or schema.
+func checkAssertCondition(ctx context.Context, path string, content []byte, condition AssertCondition) (AssertResult, error) {
+	result := AssertResult{Condition: condition.Type}
+	switch condition.Type {
+	case "contains", "not_contains":
+		if condition.Pattern == "" {
+			return result, fmt.Errorf("pattern is required")
+		}
+		re, err := regexp.Compile(condition.Pattern)
+		if err != nil {
+			return result, fmt.Errorf("invalid pattern: %w", err)
+		}
+		result.Condition += " " + condition.Pattern
+		loc := re.FindIndex(content)
+		if loc == nil {
+			result.Passed = condition.Type == "not_contains"
+			result.Message = "no match"
+			return result, nil
+		}
+		line := strings.Count(string(content[:loc[0]]), "\n") + 1
+		result.Passed = condition.Type == "contains"
+		result.Message = fmt.Sprintf("first match on line %d: %q", line, truncateString(string(content[loc[0]:loc[1]]), 80))
+
+	case "line_count":
+		if condition.Min < 0 || condition.Max < 0 || (condition.Max > 0 && condition.Max < condition.Min) {
+			return result, fmt.Errorf("invalid range %d-%d", condition.Min, condition.Max)
+		}
+		lines := strings.Count(string(content), "\n")
+		if len(content) > 0 && content[len(content)-1] != '\n' {
+			lines++
+		}
+		result.Passed = lines >= condition.Min && (condition.Max == 0 || lines <= condition.Max)
+		if condition.Max > 0 {
+			result.Message = fmt.Sprintf("%d line(s), expected %d to %d", lines, condition.Min, condition.Max)
+		} else {
+			result.Message = fmt.Sprintf("%d line(s), expected at least %d", lines, condition.Min)
+		}
+
+	case "valid_json":
+		var value interface{}
+		if err := json.Unmarshal(content, &value); err != nil {
+			result.Message = err.Error()
+			return result, nil
+		}
+		result.Passed = true
+		result.Message = "valid JSON"
+
+	case "valid_yaml":
+		var value interface{}
+		if err := yaml.Unmarshal(content, &value); err != nil {
+			result.Message = err.Error()
+			return result, nil
+		}
+		result.Passed = true
+		result.Message = "valid YAML"
+
+	case "json_schema":
+		schema, err := loadAssertSchema(ctx, condition)
+		if err != nil {
+			return result, err
+		}
+		document, err := parseJSONOrYAML(path, content)
+		if err != nil {
+			result.Message = err.Error()
+			return result, nil
+		}
+		violations := validateJSONSchema(schema, schema, document, "")
+		result.Passed = len(violations) == 0
+		if result.Passed {
+			result.Message = "matches the schema"
+			return result, nil
+		}
+		result.Message = fmt.Sprintf("%d schema violation(s)", len(violations))
+		if len(violations) > maxSchemaViolations {
+			violations = append(violations[:maxSchemaViolations], fmt.Sprintf("... and %d more", len(violations)-maxSchemaViolations))
+		}
+		result.Details = violations
+
+	default:
+		return result, fmt.Errorf("unknown condition type %q", condition.Type)
+	}
+	return result, nil
+}
+
+// loadAssertSchema returns the inline schema of condition or reads it from schema_path
+func loadAssertSchema(ctx context.Context, condition AssertCondition) (interface{}, error) {
+	switch {
+	case condition.Schema != nil && condition.SchemaPath != "":
+		return nil, fmt.Errorf("set either schema or schema_path, not both")
+	case condition.Schema != nil:
+		return normalizeJSONValue(condition.Schema)
+	case condition.SchemaPath == "":
+		return nil, fmt.Errorf("schema or schema_path is required")
+	}
+	path, err := ResolvePath(ctx, condition.SchemaPath)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	schema, err := parseJSONOrYAML(path, content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return schema, nil
+}
+
+// parseJSONOrYAML parses content as YAML for .yaml and .yml files and as JSON otherwise, and
+// returns it in the form encoding/json produces
+func parseJSONOrYAML(path string, content []byte) (interface{}, error) {
+	var value interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(content, &value); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		return normalizeJSONValue(value)
+	default:
+		if err := json.Unmarshal(content, &value); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return value, nil
+	}
+}
+
+// normalizeJSONValue round-trips value through JSON so numbers are float64 and maps are
+// map[string]interface{}
+func normalizeJSONValue(value interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("value is not representable as JSON: %w", err)
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(encoded, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// validateJSONSchema returns the ways value, at the JSON pointer path, violates schema. root is
+// the top-level schema that local $refs are resolved against.
+func validateJSONSchema(root, schema, value interface{}, path string) []string {
+	at := path
+	if at == "" {
+		at = "/"
+	}
+	switch schema := schema.(type) {
+	case bool:
+		if !schema {
+			return []string{at + ": no value is allowed here"}
+		}
+		return nil
+	case map[string]interface{}:
+		return validateSchemaObject(root, schema, value, path, at)
+	default:
+		return []string{at + ": schema is not an object or boolean"}
+	}
+}
+
+// validateSchemaObject applies the keywords of one schema object
+func validateSchemaObject(root interface{}, schema map[string]interface{}, value interface{}, path, at string) []string {
+	var violations []string
+	fail := func(format string, args ...interface{}) {
+		violations = append(violations, at+": "+fmt.Sprintf(format, args...))
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := resolveSchemaRef(root, ref)
+		if err != nil {
+			fail("%v", err)
+			return violations
+		}
+		violations = append(violations, validateJSONSchema(root, target, value, path)...)
+	}
+
+	if types, ok := schema["type"]; ok {
+		var allowed []string
+		switch types := types.(type) {
+		case string:
+			allowed = []string{types}
+		case []interface{}:
+			for _, t := range types {
+				if s, ok := t.(string); ok {
+					allowed = append(allowed, s)
+				}
+			}
+		}
+		matched := false
+		for _, t := range allowed {
+			matched = matched || jsonTypeMatches(t, value)
+		}
+		if !matched {
+			fail("expected %s, got %s", strings.Join(allowed, " or "), jsonTypeName(value))
+			return violations
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, option := range enum {
+			found = found || jsonEqual(option, value)
+		}
+		if !found {
+			fail("%s is not one of the allowed values", compactJSON(value))
+		}
+	}
+	if constant, ok := schema["const"]; ok && !jsonEqual(constant, value) {
+		fail("expected %s, got %s", compactJSON(constant), compactJSON(value))
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if name, ok := name.(string); ok {
+					if _, present := value[name]; !present {
+						fail("missing required property %q", name)
+					}
+				}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child := path + "/" + strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+			if propertySchema, ok := properties[name]; ok {
+				violations = append(violations, validateJSONSchema(root, propertySchema, value[name], child)...)
+				continue
+			}
+			if additional, ok := schema["additionalProperties"]; ok {
+				if allowed, isBool := additional.(bool); isBool && !allowed {
+					fail("property %q is not allowed", name)
+					continue
+				}
+				violations = append(violations, validateJSONSchema(root, additional, value[name], child)...)
+			}
+		}
+
+	case []interface{}:
+		if items, ok := schema["items"]; ok {
+			for i, item := range value {
+				violations = append(violations, validateJSONSchema(root, items, item, fmt.Sprintf("%s/%d", path, i))...)
+			}
+		}
+		if limit, ok := schemaNumber(schema, "minItems"); ok && float64(len(value)) < limit {
+			fail("expected at least %g item(s), got %d", limit, len(value))
+		}
+		if limit, ok := schemaNumber(schema, "maxItems"); ok && float64(len(value)) > limit {
+			fail("expected at most %g item(s), got %d", limit, len(value))
+		}
+		if unique, _ := schema["uniqueItems"].(bool); unique {
+			for i := range value {
+				for j := i + 1; j < len(value); j++ {
+					if jsonEqual(value[i], value[j]) {
+						fail("items %d and %d are equal", i, j)
+					}
+				}
+			}
+		}
+
+	case string:
+		length := float64(utf8.RuneCountInString(value))
+		if limit, ok := schemaNumber(schema, "minLength"); ok && length < limit {
+			fail("expected at least %g character(s), got %g", limit, length)
+		}
+		if limit, ok := schemaNumber(schema, "maxLength"); ok && length > limit {
+			fail("expected at most %g character(s), got %g", limit, length)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				fail("invalid pattern %q in schema: %v", pattern, err)
+			} else if !re.MatchString(value) {
+				fail("%q does not match pattern %q", truncateString(value, 80), pattern)
+			}
+		}
+
+	case float64:
+		if limit, ok := schemaNumber(schema, "minimum"); ok && value < limit {
+			fail("%g is less than the minimum %g", value, limit)
+		}
+		if limit, ok := schemaNumber(schema, "maximum"); ok && value > limit {
+			fail("%g is greater than the maximum %g", value, limit)
+		}
+		if limit, ok := schemaNumber(schema, "exclusiveMinimum"); ok && value <= limit {
+			fail("%g is not greater than %g", value, limit)
+		}
+		if limit, ok := schemaNumber(schema, "exclusiveMaximum"); ok && value >= limit {
+			fail("%g is not less than %g", value, limit)
+		}
+		if divisor, ok := schemaNumber(schema, "multipleOf"); ok && divisor > 0 {
+			if quotient := value / divisor; quotient != math.Trunc(quotient) {
+				fail("%g is not a multiple of %g", value, divisor)
+			}
+		}
+	}
+
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			violations = append(violations, validateJSONSchema(root, sub, value, path)...)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, sub := range anyOf {
+			matched = matched || len(validateJSONSchema(root, sub, value, path)) == 0
+		}
+		if !matched {
+			fail("does not match any schema in anyOf")
+		}
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		matches := 0
+		for _, sub := range oneOf {
+			if len(validateJSONSchema(root, sub, value, path)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			fail("matches %d schemas in oneOf, expected exactly 1", matches)
+		}
+	}
+	if not, ok := schema["not"]; ok && len(validateJSONSchema(root, not, value, path)) == 0 {
+		fail("matches a schema it must not match")
+	}
+	return violations
+}
+
+// resolveSchemaRef resolves a local reference such as #/$defs/item against the root schema
+func resolveSchemaRef(root interface{}, ref string) (interface{}, error) {
+	if ref != "#" && !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("only local $refs are supported, got %q", ref)
+	}
+	current := root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("$ref %q does not resolve", ref)
+		}
+		if current, ok = object[token]; !ok {
+			return nil, fmt.Errorf("$ref %q does not resolve", ref)
+		}
+	}
+	return current, nil
+}
+
+// schemaNumber returns the numeric schema keyword key, if present
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	number, ok := schema[key].(float64)
+	return number, ok
+}
+
+// jsonTypeMatches reports whether value is of the JSON schema type name
+func jsonTypeMatches(name string, value interface{}) bool {
+	switch name {
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonTypeName(value) == name
+	}
+}
+
+// jsonTypeName returns the JSON schema type of a decoded JSON value
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// jsonEqual reports whether two decoded JSON values are equal
+func jsonEqual(a, b interface{}) bool {
+	return compactJSON(a) == compactJSON(b)
+}
+
+// compactJSON renders a decoded JSON value; encoding/json sorts map keys, so equal values render alike
+func compactJSON(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+const assertConfigJSON = `{
+  "name": "agent",
+  "port": 8080,
+  "tags": ["a", "b"]
+}
+`
+
+var assertConfigSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"name", "port"},
+	"properties": map[string]interface{}{
+		"name": map[string]interface{}{"type": "string", "minLength": 1},
+		"port": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 65535},
+		"tags": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "uniqueItems": true},
+	},
+	"additionalProperties": false,
+}
+
+func TestAssertFileConditions(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "config.json", assertConfigJSON)
+	writeTestFile(t, dir, "bad.json", `{"name": "agent",`)
+	writeTestFile(t, dir, "config.yaml", "name: agent\nport: 70000\nextra: true\n")
+	writeTestFile(t, dir, "bad.yaml", "name: [unclosed\n")
+	writeTestFile(t, dir, "schema.json", string(mustMarshal(t, assertConfigSchema)))
+
+	tests := []struct {
+		name      string
+		path      string
+		condition AssertCondition
+		passed    bool
+		message   string
+	}{
+		{"contains", "config.json", AssertCondition{Type: "contains", Pattern: `"port": \d+`}, true, "first match on line 3"},
+		{"contains missing", "config.json", AssertCondition{Type: "contains", Pattern: `"host"`}, false, "no match"},
+		{"not_contains", "config.json", AssertCondition{Type: "not_contains", Pattern: `"host"`}, true, "no match"},
+		{"not_contains found", "config.json", AssertCondition{Type: "not_contains", Pattern: `tags`}, false, "first match on line 4"},
+		{"line_count in range", "config.json", AssertCondition{Type: "line_count", Min: 5, Max: 5}, true, "5 line(s)"},
+		{"line_count too short", "config.json", AssertCondition{Type: "line_count", Min: 10}, false, "expected at least 10"},
+		{"line_count too long", "config.json", AssertCondition{Type: "line_count", Max: 3}, false, "expected 0 to 3"},
+		{"valid_json", "config.json", AssertCondition{Type: "valid_json"}, true, "valid JSON"},
+		{"valid_json broken", "bad.json", AssertCondition{Type: "valid_json"}, false, "unexpected end"},
+		{"valid_yaml", "config.yaml", AssertCondition{Type: "valid_yaml"}, true, "valid YAML"},
+		{"valid_yaml broken", "bad.yaml", AssertCondition{Type: "valid_yaml"}, false, "yaml"},
+		{"json_schema inline", "config.json", AssertCondition{Type: "json_schema", Schema: assertConfigSchema}, true, "matches the schema"},
+		{"json_schema from file", "config.json", AssertCondition{Type: "json_schema", SchemaPath: "schema.json"}, true, "matches the schema"},
+		{"json_schema yaml mismatch", "config.yaml", AssertCondition{Type: "json_schema", SchemaPath: "schema.json"}, false, "2 schema violation(s)"},
+		{"json_schema unparsable", "bad.json", AssertCondition{Type: "json_schema", Schema: assertConfigSchema}, false, "invalid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output AssertFileOutput
+			callTool(t, ctx, AssertFile, AssertFileInput{Path: tt.path, Conditions: []AssertCondition{tt.condition}}, &output)
+			if len(output.Results) != 1 {
+				t.Fatalf("got %d results, want 1", len(output.Results))
+			}
+			result := output.Results[0]
+			if result.Passed != tt.passed || output.Passed != tt.passed {
+				t.Errorf("passed = %v (file %v), want %v: %+v", result.Passed, output.Passed, tt.passed, result)
+			}
+			if !strings.Contains(result.Message, tt.message) {
+				t.Errorf("message = %q, want it to contain %q", result.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestAssertFileSchemaViolations(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "config.json", `{"name": "", "port": 8080.5, "tags": ["a", "a"], "extra": 1}`)
+
+	var output AssertFileOutput
+	callTool(t, ctx, AssertFile, AssertFileInput{
+		Path:       "config.json",
+		Conditions: []AssertCondition{{Type: "json_schema", Schema: assertConfigSchema}},
+	}, &output)
+
+	details := strings.Join(output.Results[0].Details, "\n")
+	for _, want := range []string{"/name", "/port", "/tags", "extra"} {
+		if !strings.Contains(details, want) {
+			t.Errorf("violations do not mention %s:\n%s", want, details)
+		}
+	}
+}
+
+func TestAssertFileReportsEveryCondition(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "config.json", assertConfigJSON)
+
+	var output AssertFileOutput
+	callTool(t, ctx, AssertFile, AssertFileInput{
+		Path: "config.json",
+		Conditions: []AssertCondition{
+			{Type: "valid_json"},
+			{Type: "contains", Pattern: "missing"},
+			{Type: "line_count", Max: 100},
+		},
+	}, &output)
+
+	if output.Passed || len(output.Results) != 3 {
+		t.Fatalf("got %+v, want 3 results and an overall failure", output)
+	}
+	if !output.Results[0].Passed || output.Results[1].Passed || !output.Results[2].Passed {
+		t.Errorf("got results %+v, want only the second to fail", output.Results)
+	}
+	if output.Message != "1 of 3 condition(s) failed." {
+		t.Errorf("message = %q", output.Message)
+	}
+}
+
+func TestAssertFileInvalidInput(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "config.json", assertConfigJSON)
+
+	tests := []struct {
+		name  string
+		input AssertFileInput
+	}{
+		{"no path", AssertFileInput{Conditions: []AssertCondition{{Type: "valid_json"}}}},
+		{"no conditions", AssertFileInput{Path: "config.json"}},
+		{"missing file", AssertFileInput{Path: "missing.json", Conditions: []AssertCondition{{Type: "valid_json"}}}},
+		{"unknown type", AssertFileInput{Path: "config.json", Conditions: []AssertCondition{{Type: "is_go"}}}},
+		{"no pattern", AssertFileInput{Path: "config.json", Conditions: []AssertCondition{{Type: "contains"}}}},
+		{"bad pattern", AssertFileInput{Path: "config.json", Conditions: []AssertCondition{{Type: "contains", Pattern: "("}}}},
+		{"bad range", AssertFileInput{Path: "config.json", Conditions: []AssertCondition{{Type: "line_count", Min: 5, Max: 2}}}},
+		{"no schema", AssertFileInput{Path: "config.json", Conditions: []AssertCondition{{Type: "json_schema"}}}},
+		{"both schemas", AssertFileInput{Path: "config.json", Conditions: []AssertCondition{{Type: "json_schema", Schema: assertConfigSchema, SchemaPath: "schema.json"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := AssertFile(ctx, mustMarshal(t, tt.input)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CallGraphToolDefinition defines the call_graph tool
+var CallGraphToolDefinition = ToolDefinition{
+	Name: "call_graph",
+	Description: `Show what a Go function calls and what calls it within the module.
+Every package under 'path' is type-checked and calls are resolved statically, so a call through
+an interface is reported as a call to the interface method rather than to its implementations.
+Name the function as 'Func', 'Type.Method', or qualified by package ('tools.GoRename' or a full
+import path). Returns adjacency lists of callees and callers out to 'depth' levels (default 1),
+plus the location of every function in them.`,
+	InputSchema:           CallGraphInputSchema,
+	Function:              CallGraph,
+	CountsTowardLoopLimit: true,
+}
+
+// CallGraphInput defines the input parameters for the call_graph tool
+type CallGraphInput struct {
+	Function        string `json:"function" jsonschema_required:"true" jsonschema_description:"Function to analyze: 'Func', 'Type.Method', or 'pkg.Func'" jsonschema_example:"tools.GoRename"`
+	Path            string `json:"path,omitempty" jsonschema_description:"Module root to analyze. Defaults to the current directory."`
+	Depth           int    `json:"depth,omitempty" jsonschema_description:"How many levels of callers and callees to follow (default 1, max 10)"`
+	Direction       string `json:"direction,omitempty" jsonschema_description:"'both' (default), 'callees', or 'callers'"`
+	IncludeExternal bool   `json:"include_external,omitempty" jsonschema_description:"Include calls to functions outside the module, such as the standard library"`
+}
+
+// CallGraphInputSchema is the JSON schema for the call_graph tool
+var CallGraphInputSchema = GenerateSchema[CallGraphInput]()
+
+// CallGraphOutput represents the structured output of the call_graph tool
+type CallGraphOutput struct {
+	Function  string              `json:"function"`
+	Depth     int                 `json:"depth"`
+	Callees   map[string][]string `json:"callees,omitempty"`
+	Callers   map[string][]string `json:"callers,omitempty"`
+	Locations map[string]string   `json:"locations"`
+}
+
+// callGraphFunc is a function or method declared in the module
+type callGraphFunc struct {
+	pkgName  string
+	pkgPath  string
+	local    string
+	location string
+}
+
+// callGraph holds the static call edges between functions keyed by display name
+type callGraph struct {
+	funcs   map[string]*callGraphFunc
+	callees map[string]map[string]bool
+	callers map[string]map[string]bool
+}
+
+// CallGraph implements the call_graph tool functionality
+func CallGraph(ctx context.Context, input json.RawMessage) (string, error) {
+	graphInput := CallGraphInput{}
+	if err := DecodeInput(input, &graphInput); err != nil {
+		return "", err
+	}
+
+	if graphInput.Function == "" {
+		return "", fmt.Errorf("function parameter is required")
+	}
+
+	depth := graphInput.Depth
+	if depth <= 0 {
+		depth = 1
+	}
+	if depth > 10 {
+		depth = 10
+	}
+
+	direction := graphInput.Direction
+	if direction == "" {
+		direction = "both"
+	}
+	if direction != "both" && direction != "callees" && direction != "callers" {
+		return "", fmt.Errorf("invalid direction: %s. Must be 'both', 'callees', or 'callers'", direction)
+	}
+
+	root := workspaceDir(ctx)
+	var err error
+	if graphInput.Path != "" {
+		root, err = ResolvePath(ctx, graphInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	graph, err := buildCallGraph(ctx, root, graphInput.IncludeExternal)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := graph.lookup(graphInput.Function)
+	if err != nil {
+		return "", err
+	}
+
+	output := CallGraphOutput{
+		Function:  target,
+		Depth:     depth,
+		Locations: map[string]string{},
+	}
+	if direction != "callers" {
+		output.Callees = walkCallGraph(graph.callees, target, depth)
+	}
+	if direction != "callees" {
+		output.Callers = walkCallGraph(graph.callers, target, depth)
+	}
+
+	for _, adjacency := range []map[string][]string{output.Callees, output.Callers} {
+		for from, to := range adjacency {
+			for _, name := range append([]string{from}, to...) {
+				if fn, ok := graph.funcs[name]; ok && fn.location != "" {
+					output.Locations[name] = fn.location
+				}
+			}
+		}
+	}
+	if fn, ok := graph.funcs[target]; ok {
+		output.Locations[target] = fn.location
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// buildCallGraph type-checks every package under root and records the calls made
+// from each function body. Calls inside function literals belong to the enclosing function.
+func buildCallGraph(ctx context.Context, root string, includeExternal bool) (*callGraph, error) {
+	modulePath, err := readModulePath(root)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	packages, err := parseModulePackages(ctx, fset, root, modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &callGraph{
+		funcs:   map[string]*callGraphFunc{},
+		callees: map[string]map[string]bool{},
+		callers: map[string]map[string]bool{},
+	}
+	inModule := func(pkgPath string) bool {
+		return pkgPath == modulePath || strings.HasPrefix(pkgPath, modulePath+"/")
+	}
+
+	moduleImports := newModuleImporter(fset, packages)
+	for _, pkg := range packages {
+		info := &types.Info{
+			Defs: map[*ast.Ident]types.Object{},
+			Uses: map[*ast.Ident]types.Object{},
+		}
+		config := types.Config{Importer: moduleImports, Error: func(error) {}}
+		if checked, _ := config.Check(pkg.importPath, fset, pkg.files, info); checked == nil {
+			continue
+		}
+
+		for _, file := range pkg.files {
+			for _, decl := range file.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok {
+					continue
+				}
+				fn, ok := info.Defs[funcDecl.Name].(*types.Func)
+				if !ok {
+					continue
+				}
+
+				caller := graph.add(fn, modulePath)
+				position := fset.Position(funcDecl.Name.Pos())
+				location := position.Filename
+				if rel, err := filepath.Rel(root, location); err == nil {
+					location = filepath.ToSlash(rel)
+				}
+				graph.funcs[caller].location = fmt.Sprintf("%s:%d", location, position.Line)
+
+				if funcDecl.Body == nil {
+					continue
+				}
+				ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+					call, ok := node.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+					var ident *ast.Ident
+					switch fun := ast.Unparen(call.Fun).(type) {
+					case *ast.Ident:
+						ident = fun
+					case *ast.SelectorExpr:
+						ident = fun.Sel
+					case *ast.IndexExpr:
+						// Explicitly instantiated generic function
+						if id, ok := fun.X.(*ast.Ident); ok {
+							ident = id
+						} else if sel, ok := fun.X.(*ast.SelectorExpr); ok {
+							ident = sel.Sel
+						}
+					}
+					if ident == nil {
+						return true
+					}
+					callee, ok := info.Uses[ident].(*types.Func)
+					if !ok || callee.Pkg() == nil || (!includeExternal && !inModule(callee.Pkg().Path())) {
+						return true
+					}
+					graph.link(caller, graph.add(callee, modulePath))
+					return true
+				})
+			}
+		}
+	}
+
+	return graph, nil
+}
+
+// add registers fn and returns its display name: the import path relative to the module,
+// the receiver type for methods, and the function name, e.g. 'internal/agent.Agent.Run'
+func (g *callGraph) add(fn *types.Func, modulePath string) string {
+	local := fn.Name()
+	if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+		recv := sig.Recv().Type()
+		if ptr, ok := recv.(*types.Pointer); ok {
+			recv = ptr.Elem()
+		}
+		if named, ok := recv.(*types.Named); ok {
+			local = named.Obj().Name() + "." + local
+		}
+	}
+
+	// External test packages share the import path of the package they test
+	pkgPath := strings.TrimSuffix(fn.Pkg().Path(), "_test")
+	display := pkgPath
+	if pkgPath != modulePath && strings.HasPrefix(pkgPath, modulePath+"/") {
+		display = strings.TrimPrefix(pkgPath, modulePath+"/")
+	}
+	name := display + "." + local
+
+	if _, ok := g.funcs[name]; !ok {
+		g.funcs[name] = &callGraphFunc{
+			pkgName: fn.Pkg().Name(),
+			pkgPath: pkgPath,
+			local:   local,
+		}
+	}
+	return name
+}
+
+// link records a call from caller to callee
+func (g *callGraph) link(caller, callee string) {
+	if g.callees[caller] == nil {
+		g.callees[caller] = map[string]bool{}
+	}
+	if g.callers[callee] == nil {
+		g.callers[callee] = map[string]bool{}
+	}
+	g.callees[caller][callee] = true
+	g.callers[callee][caller] = true
+}
+
+// lookup resolves a user-supplied function name to a single display name
+func (g *callGraph) lookup(function string) (string, error) {
+	var matches []string
+	for name, fn := range g.funcs {
+		if fn.location == "" {
+			continue
+		}
+		if function == name || function == fn.local || function == fn.pkgName+"."+fn.local || function == fn.pkgPath+"."+fn.local {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("function %s not found in the module", function)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("function %s is ambiguous; qualify it with its package: %s", function, strings.Join(matches, ", "))
+}
+
+// walkCallGraph follows edges breadth-first from start up to depth levels and
+// returns the adjacency list of every function visited
+func walkCallGraph(edges map[string]map[string]bool, start string, depth int) map[string][]string {
+	adjacency := map[string][]string{}
+	visited := map[string]bool{start: true}
+	frontier := []string{start}
+
+	for level := 0; level < depth && len(frontier) > 0; level++ {
+		var next []string
+		for _, name := range frontier {
+			neighbors := make([]string, 0, len(edges[name]))
+			for neighbor := range edges[name] {
+				neighbors = append(neighbors, neighbor)
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					next = append(next, neighbor)
+				}
+			}
+			sort.Strings(neighbors)
+			adjacency[name] = neighbors
+		}
+		sort.Strings(next)
+		frontier = next
+	}
+	return adjacency
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeCallGraphFixture writes a module where Handle calls validate and Store.Save,
+// and Save in turn calls encode
+func writeCallGraphFixture(t *testing.T, dir string) {
+	t.Helper()
+	testGoModule(t, dir, "example.com/svc")
+	writeTestFile(t, dir, "main.go", `package main
+
+import (
+	"strings"
+
+	"example.com/svc/store"
+)
+
+func main() {
+	Handle("a")
+}
+
+func Handle(input string) error {
+	if err := validate(input); err != nil {
+		return err
+	}
+	s := &store.Store{}
+	return s.Save(strings.ToUpper(input))
+}
+
+func validate(input string) error { return nil }
+
+func unrelated() { validate("x") }
+`)
+	writeTestFile(t, dir, "store/store.go", `package store
+
+type Store struct{ data []byte }
+
+func (s *Store) Save(value string) error {
+	s.data = encode(value)
+	return nil
+}
+
+func encode(value string) []byte { return []byte(value) }
+`)
+}
+
+func TestCallGraphDirectCallees(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeCallGraphFixture(t, dir)
+
+	var output CallGraphOutput
+	callTool(t, ctx, CallGraph, CallGraphInput{Function: "Handle", Direction: "callees"}, &output)
+
+	want := map[string][]string{
+		"example.com/svc.Handle": {"example.com/svc.validate", "store.Store.Save"},
+	}
+	if !reflect.DeepEqual(output.Callees, want) {
+		t.Errorf("callees = %v, want %v", output.Callees, want)
+	}
+	if len(output.Callers) != 0 {
+		t.Errorf("callers should be omitted for direction callees: %v", output.Callers)
+	}
+	if output.Locations["store.Store.Save"] != "store/store.go:5" {
+		t.Errorf("locations = %v", output.Locations)
+	}
+}
+
+func TestCallGraphDepthAndCallers(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeCallGraphFixture(t, dir)
+
+	var output CallGraphOutput
+	callTool(t, ctx, CallGraph, CallGraphInput{Function: "Handle", Direction: "callees", Depth: 2}, &output)
+	if got := output.Callees["store.Store.Save"]; !reflect.DeepEqual(got, []string{"store.encode"}) {
+		t.Errorf("second-level callees of Save = %v, want store.encode", got)
+	}
+
+	callTool(t, ctx, CallGraph, CallGraphInput{Function: "validate", Direction: "callers"}, &output)
+	want := []string{"example.com/svc.Handle", "example.com/svc.unrelated"}
+	if got := output.Callers["example.com/svc.validate"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("callers of validate = %v, want %v", got, want)
+	}
+}
+
+func TestCallGraphExternalCalls(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeCallGraphFixture(t, dir)
+
+	var output CallGraphOutput
+	callTool(t, ctx, CallGraph, CallGraphInput{Function: "Handle", Direction: "callees", IncludeExternal: true}, &output)
+	if !strings.Contains(strings.Join(output.Callees["example.com/svc.Handle"], " "), "strings.ToUpper") {
+		t.Errorf("callees = %v, want the standard library call included", output.Callees)
+	}
+}
+
+func TestCallGraphUnknownFunction(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeCallGraphFixture(t, dir)
+
+	if _, err := CallGraph(ctx, mustMarshal(t, CallGraphInput{Function: "Missing"})); err == nil {
+		t.Error("expected an error for an unknown function")
+	}
+}
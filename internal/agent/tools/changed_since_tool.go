@@ -0,0 +1,218 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChangedSinceToolDefinition defines the changed_since tool
+var ChangedSinceToolDefinition = ToolDefinition{
+	Name: "changed_since",
+	Description: `List the files modified after a point in time, newest first.
+'since' is an RFC3339 timestamp or 'last session', meaning the end of the previous agent session in
+this workspace, which answers "what changed since we last talked". Hidden directories, vendor and
+paths excluded by .metamorphignore are skipped.`,
+	InputSchema: ChangedSinceInputSchema,
+	Function:    ChangedSince,
+}
+
+// ChangedSinceInput defines the input parameters for the changed_since tool
+type ChangedSinceInput struct {
+	Since      string `json:"since" jsonschema_required:"true" jsonschema_description:"RFC3339 timestamp, or 'last session'" jsonschema_example:"2024-05-01T09:00:00Z"`
+	Path       string `json:"path,omitempty" jsonschema_description:"Directory to search. Defaults to the current directory."`
+	MaxResults int    `json:"max_results,omitempty" jsonschema_description:"Maximum number of files to return. Defaults to 200."`
+}
+
+// ChangedSinceInputSchema is the JSON schema for the changed_since tool
+var ChangedSinceInputSchema = GenerateSchema[ChangedSinceInput]()
+
+// ChangedFile is a file modified after the requested time
+type ChangedFile struct {
+	Path     string `json:"path"`
+	Modified string `json:"modified"`
+	Size     int64  `json:"size"`
+}
+
+// ChangedSinceOutput represents the structured output of the changed_since tool
+type ChangedSinceOutput struct {
+	Since     string        `json:"since"`
+	Files     []ChangedFile `json:"files"`
+	Total     int           `json:"total"`
+	Truncated bool          `json:"truncated,omitempty"`
+}
+
+// defaultChangedSinceResults is the number of files returned when max_results is not set
+const defaultChangedSinceResults = 200
+
+var (
+	// previousSessionEnd is when the previous session in the workspace ended, loaded at the start
+	// of this session; zero if none was recorded
+	previousSessionEnd   time.Time
+	previousSessionMutex sync.Mutex
+)
+
+// sessionTimesFile returns the file recording when the last session in each workspace ended
+func sessionTimesFile() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "metamorph", "sessions.json"), nil
+}
+
+// loadSessionTimes reads the recorded session end times, keyed by absolute workspace path
+func loadSessionTimes() (map[string]time.Time, error) {
+	file, err := sessionTimesFile()
+	if err != nil {
+		return nil, err
+	}
+	times := map[string]time.Time{}
+	content, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return times, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &times); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return times, nil
+}
+
+// LoadPreviousSession remembers when the previous session in the workspace ended, so that
+// changed_since can use it after this session records its own end
+func LoadPreviousSession(ctx context.Context) error {
+	root, err := filepath.Abs(workspaceDir(ctx))
+	if err != nil {
+		return err
+	}
+	times, err := loadSessionTimes()
+	if err != nil {
+		return err
+	}
+
+	previousSessionMutex.Lock()
+	defer previousSessionMutex.Unlock()
+	previousSessionEnd = times[root]
+	return nil
+}
+
+// RecordSessionEnd records now as the end of the session in the workspace
+func RecordSessionEnd(ctx context.Context) error {
+	root, err := filepath.Abs(workspaceDir(ctx))
+	if err != nil {
+		return err
+	}
+	times, err := loadSessionTimes()
+	if err != nil {
+		return err
+	}
+	times[root] = time.Now()
+
+	file, err := sessionTimesFile()
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(times, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, content, 0644)
+}
+
+// ChangedSince implements the changed_since tool functionality
+func ChangedSince(ctx context.Context, input json.RawMessage) (string, error) {
+	changedInput := ChangedSinceInput{}
+	err := DecodeInput(input, &changedInput)
+	if err != nil {
+		return "", err
+	}
+
+	var since time.Time
+	switch strings.ToLower(strings.TrimSpace(changedInput.Since)) {
+	case "":
+		return "", fmt.Errorf("since parameter is required")
+	case "last session", "last_session":
+		previousSessionMutex.Lock()
+		since = previousSessionEnd
+		previousSessionMutex.Unlock()
+		if since.IsZero() {
+			return "", fmt.Errorf("no previous session was recorded for this workspace; pass an RFC3339 timestamp instead")
+		}
+	default:
+		since, err = time.Parse(time.RFC3339, strings.TrimSpace(changedInput.Since))
+		if err != nil {
+			return "", fmt.Errorf("invalid since %q: expected an RFC3339 timestamp such as 2024-05-01T09:00:00Z or 'last session'", changedInput.Since)
+		}
+	}
+
+	root := workspaceDir(ctx)
+	if changedInput.Path != "" {
+		root, err = ResolvePath(ctx, changedInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+	maxResults := changedInput.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultChangedSinceResults
+	}
+
+	files := []ChangedFile{}
+	modTimes := map[string]time.Time{}
+	ignoreRules := LoadIgnoreRules(workspaceDir(ctx))
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if ignoreRules.IgnoredPath(path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || !info.ModTime().After(since) {
+			return nil
+		}
+		files = append(files, ChangedFile{Path: path, Modified: info.ModTime().Format(time.RFC3339), Size: info.Size()})
+		modTimes[path] = info.ModTime()
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return modTimes[files[i].Path].After(modTimes[files[j].Path])
+	})
+
+	output := ChangedSinceOutput{Since: since.Format(time.RFC3339), Files: files, Total: len(files)}
+	if len(files) > maxResults {
+		output.Files = files[:maxResults]
+		output.Truncated = true
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
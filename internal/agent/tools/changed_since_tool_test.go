@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// touchTestFile writes a file in dir and sets its modification time
+func touchTestFile(t *testing.T, dir, name string, modified time.Time) string {
+	t.Helper()
+	path := writeTestFile(t, dir, name, name)
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// changedPaths returns the paths of files relative to dir
+func changedPaths(t *testing.T, dir string, files []ChangedFile) []string {
+	t.Helper()
+	var paths []string
+	for _, file := range files {
+		rel, err := filepath.Rel(dir, file.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	return paths
+}
+
+func TestChangedSinceReturnsNewerFiles(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	since := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	touchTestFile(t, dir, "old.go", since.Add(-time.Hour))
+	touchTestFile(t, dir, "pkg/newer.go", since.Add(time.Hour))
+	touchTestFile(t, dir, "newest.go", since.Add(2*time.Hour))
+	touchTestFile(t, dir, ".git/HEAD", since.Add(time.Hour))
+	touchTestFile(t, dir, "vendor/dep/dep.go", since.Add(time.Hour))
+	touchTestFile(t, dir, "build/out.txt", since.Add(time.Hour))
+	writeTestFile(t, dir, ".metamorphignore", "build/\n")
+	if err := os.Chtimes(filepath.Join(dir, ".metamorphignore"), since, since); err != nil {
+		t.Fatal(err)
+	}
+
+	var output ChangedSinceOutput
+	callTool(t, ctx, ChangedSince, ChangedSinceInput{Since: since.Format(time.RFC3339)}, &output)
+	if got := changedPaths(t, dir, output.Files); !slices.Equal(got, []string{"newest.go", "pkg/newer.go"}) {
+		t.Errorf("changed = %v, want the newer files, newest first", got)
+	}
+
+	output = ChangedSinceOutput{}
+	callTool(t, ctx, ChangedSince, ChangedSinceInput{Since: since.Format(time.RFC3339), MaxResults: 1}, &output)
+	if output.Total != 2 || !output.Truncated || len(output.Files) != 1 {
+		t.Errorf("got %+v, want one of two files", output)
+	}
+}
+
+func TestChangedSinceLastSession(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Cleanup(func() { previousSessionEnd = time.Time{} })
+
+	if err := LoadPreviousSession(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ChangedSince(ctx, mustMarshal(t, ChangedSinceInput{Since: "last session"})); err == nil {
+		t.Error("expected an error without a previous session")
+	}
+
+	touchTestFile(t, dir, "before.go", time.Now().Add(-time.Hour))
+	if err := RecordSessionEnd(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadPreviousSession(ctx); err != nil {
+		t.Fatal(err)
+	}
+	touchTestFile(t, dir, "after.go", time.Now().Add(time.Minute))
+
+	var output ChangedSinceOutput
+	callTool(t, ctx, ChangedSince, ChangedSinceInput{Since: "last session"}, &output)
+	if got := changedPaths(t, dir, output.Files); !slices.Equal(got, []string{"after.go"}) {
+		t.Errorf("changed = %v, want only the file touched after the session ended", got)
+	}
+}
+
+func TestChangedSinceInvalidTimestamp(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	for _, since := range []string{"", "yesterday", "2024-05-01"} {
+		if _, err := ChangedSince(ctx, mustMarshal(t, ChangedSinceInput{Since: since})); err == nil {
+			t.Errorf("since %q: expected an error", since)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CheckAPIStabilityToolDefinition defines the check_api_stability tool
+var CheckAPIStabilityToolDefinition = ToolDefinition{
+	Name: "check_api_stability",
+	Description: `Check whether this session's edits changed the exported API of the Go packages in the workspace.
+A copy of the Go sources is taken before the first edit of the session; this tool compares every
+exported function, type, field, method, variable and constant in it with the current code and reports
+added, removed and changed symbols, flagging the breaking ones. Internal packages are included unless 'public_only' is
+set. Set 'path' to limit the report to packages below a directory, and 'reset' to accept the current
+API as the new baseline after intended changes.`,
+	InputSchema: CheckAPIStabilityInputSchema,
+	Function:    CheckAPIStability,
+}
+
+// CheckAPIStabilityInput defines the input parameters for the check_api_stability tool
+type CheckAPIStabilityInput struct {
+	Path       string `json:"path,omitempty" jsonschema_description:"Only report packages in or below this directory. Defaults to the whole workspace."`
+	PublicOnly bool   `json:"public_only,omitempty" jsonschema_description:"If true, ignore internal and testdata packages"`
+	Reset      bool   `json:"reset,omitempty" jsonschema_description:"If true, replace the baseline with the current API after reporting"`
+}
+
+// CheckAPIStabilityInputSchema is the JSON schema for the check_api_stability tool
+var CheckAPIStabilityInputSchema = GenerateSchema[CheckAPIStabilityInput]()
+
+// CheckAPIStabilityOutput represents the structured output of the check_api_stability tool
+type CheckAPIStabilityOutput struct {
+	Stable   bool        `json:"stable"`
+	Packages int         `json:"packages"`
+	Added    []APIChange `json:"added"`
+	Removed  []APIChange `json:"removed"`
+	Changed  []APIChange `json:"changed"`
+	Breaking int         `json:"breaking"`
+	Message  string      `json:"message,omitempty"`
+}
+
+// APIBaseline holds a copy of the Go sources of a workspace root as they were before a
+// session's first edit. Copying is quick; the copy is only type-checked when
+// check_api_stability runs. Each agent keeps its own baseline and passes it to the tool
+// through the context with WithAPIBaseline.
+type APIBaseline struct {
+	mutex sync.Mutex
+	dir   string
+	root  string
+	err   error
+}
+
+// apiBaselineKey is the context key under which the session's API baseline is stored
+type apiBaselineKey struct{}
+
+// apiBaselineModuleFiles are copied along with the Go sources so the copy resolves imports like
+// the workspace does
+var apiBaselineModuleFiles = []string{"go.mod", "go.sum", "go.work", "go.work.sum"}
+
+// NewAPIBaseline returns an empty baseline; the first Snapshot fills it
+func NewAPIBaseline() *APIBaseline {
+	return &APIBaseline{}
+}
+
+// WithAPIBaseline returns a copy of ctx whose check_api_stability calls compare against baseline
+func WithAPIBaseline(ctx context.Context, baseline *APIBaseline) context.Context {
+	return context.WithValue(ctx, apiBaselineKey{}, baseline)
+}
+
+// Snapshot records the Go sources of the workspace in ctx as the baseline. It does nothing if a
+// baseline for the workspace was already taken, so the agent calls it before every mutating
+// tool call.
+func (b *APIBaseline) Snapshot(ctx context.Context) {
+	root := workspaceDir(ctx)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.root == root && (b.dir != "" || b.err != nil) {
+		return
+	}
+	b.replace(ctx, root)
+}
+
+// Reset discards the baseline so the next edit takes a new one
+func (b *APIBaseline) Reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.dir != "" {
+		os.RemoveAll(b.dir)
+	}
+	b.dir, b.root, b.err = "", "", nil
+}
+
+// replace copies the current sources of root as the new baseline. The caller must hold b.mutex.
+func (b *APIBaseline) replace(ctx context.Context, root string) {
+	if b.dir != "" {
+		os.RemoveAll(b.dir)
+	}
+	b.root = root
+	b.dir, b.err = copyGoSources(ctx, root)
+}
+
+// copyGoSources copies the non-test Go files and module files of root into a new temporary
+// directory, preserving their layout
+func copyGoSources(ctx context.Context, root string) (string, error) {
+	files, err := goFilesUnder(ctx, root)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range apiBaselineModuleFiles {
+		if info, err := os.Stat(filepath.Join(root, name)); err == nil && info.Mode().IsRegular() {
+			files = append(files, filepath.Join(root, name))
+		}
+	}
+
+	tmpDir, err := os.MkdirTemp("", "metamorph-apibaseline-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			continue
+		}
+		dst := filepath.Join(tmpDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			os.RemoveAll(tmpDir)
+			return "", fmt.Errorf("failed to create directory for %s: %w", rel, err)
+		}
+		if err := copyFile(file, dst); err != nil {
+			os.RemoveAll(tmpDir)
+			return "", fmt.Errorf("failed to copy %s: %w", rel, err)
+		}
+	}
+	return tmpDir, nil
+}
+
+// CheckAPIStability implements the check_api_stability tool functionality
+func CheckAPIStability(ctx context.Context, input json.RawMessage) (string, error) {
+	checkInput := CheckAPIStabilityInput{}
+	err := DecodeInput(input, &checkInput)
+	if err != nil {
+		return "", err
+	}
+
+	root := workspaceDir(ctx)
+	prefix := ""
+	if checkInput.Path != "" {
+		dir, err := ResolvePath(ctx, checkInput.Path)
+		if err != nil {
+			return "", err
+		}
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve workspace root: %w", err)
+		}
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", checkInput.Path, err)
+		}
+		rel, err := filepath.Rel(absRoot, absDir)
+		if err != nil || !withinDir(absRoot, absDir) {
+			return "", fmt.Errorf("%s is outside the workspace root", checkInput.Path)
+		}
+		if rel != "." {
+			prefix = filepath.ToSlash(rel)
+		}
+	}
+
+	apiBaseline, _ := ctx.Value(apiBaselineKey{}).(*APIBaseline)
+	if apiBaseline == nil {
+		return "", fmt.Errorf("no API baseline is tracked for this session")
+	}
+
+	// Hold the lock throughout so a concurrent edit cannot replace the baseline being read
+	apiBaseline.mutex.Lock()
+	defer apiBaseline.mutex.Unlock()
+
+	output := CheckAPIStabilityOutput{Stable: true, Added: []APIChange{}, Removed: []APIChange{}, Changed: []APIChange{}}
+	switch {
+	case apiBaseline.root == root && apiBaseline.err != nil:
+		return "", fmt.Errorf("failed to snapshot the sources before the first edit: %w", apiBaseline.err)
+	case apiBaseline.root != root || apiBaseline.dir == "":
+		apiBaseline.replace(ctx, root)
+		output.Message = "No files have been edited yet this session; took the baseline snapshot now."
+		return marshalAPIStabilityOutput(output)
+	}
+
+	baseline, err := prefixedAPI(ctx, apiBaseline.dir, prefix, checkInput.PublicOnly)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the baseline API: %w", err)
+	}
+	current, err := prefixedAPI(ctx, root, prefix, checkInput.PublicOnly)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the current API: %w", err)
+	}
+
+	output.Packages, output.Added, output.Removed, output.Changed = compareAPI(baseline, current)
+	for _, changes := range [][]APIChange{output.Added, output.Removed, output.Changed} {
+		for _, change := range changes {
+			if change.Breaking {
+				output.Breaking++
+			}
+		}
+	}
+	output.Stable = output.Breaking == 0
+	if !output.Stable {
+		output.Message = "Edits changed the exported API in ways that can break callers; revert them unless the change is intended."
+	}
+
+	if checkInput.Reset {
+		apiBaseline.replace(ctx, root)
+		if apiBaseline.err != nil {
+			return "", fmt.Errorf("failed to take the new baseline: %w", apiBaseline.err)
+		}
+	}
+
+	return marshalAPIStabilityOutput(output)
+}
+
+// prefixedAPI returns the exported API of the packages in or below the directory prefix of
+// root, keyed by their directory relative to root. Only the packages under prefix are
+// type-checked; a missing directory has no API.
+func prefixedAPI(ctx context.Context, root, prefix string, publicOnly bool) (map[string]apiSymbol, error) {
+	dir := filepath.Join(root, filepath.FromSlash(prefix))
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return map[string]apiSymbol{}, nil
+	}
+	symbols, err := exportedAPI(ctx, dir, true)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixed := map[string]apiSymbol{}
+	for _, symbol := range symbols {
+		symbol.pkg = path.Join(prefix, symbol.pkg)
+		if publicOnly && isPrivatePackageDir(symbol.pkg) {
+			continue
+		}
+		prefixed[symbol.pkg+"."+symbol.name] = symbol
+	}
+	return prefixed, nil
+}
+
+// marshalAPIStabilityOutput renders the check_api_stability output as indented JSON
+func marshalAPIStabilityOutput(output CheckAPIStabilityOutput) (string, error) {
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+	return string(jsonOutput), nil
+}
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// apiChangeSymbols returns the symbol of each change in order
+func apiChangeSymbols(changes []APIChange) []string {
+	var names []string
+	for _, change := range changes {
+		names = append(names, change.Symbol)
+	}
+	return names
+}
+
+func TestCheckAPIStabilityDetectsSignatureChange(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/lib")
+	writeTestFile(t, dir, "lib.go", apiDiffBase)
+	writeTestFile(t, dir, "internal/priv/priv.go", "package priv\n\nfunc Exported() {}\n")
+	baseline := NewAPIBaseline()
+	t.Cleanup(baseline.Reset)
+	ctx = WithAPIBaseline(ctx, baseline)
+
+	baseline.Snapshot(ctx)
+	writeTestFile(t, dir, "lib.go", strings.Replace(apiDiffBase, "func Helper(x int) int", "func Helper(x int, y int) int", 1))
+	writeTestFile(t, dir, "internal/priv/priv.go", "package priv\n")
+	// Later snapshots keep the first baseline
+	baseline.Snapshot(ctx)
+
+	var output CheckAPIStabilityOutput
+	callTool(t, ctx, CheckAPIStability, CheckAPIStabilityInput{}, &output)
+	if output.Stable || output.Breaking != 2 {
+		t.Errorf("stable %v with %d breaking changes, want 2", output.Stable, output.Breaking)
+	}
+	if got := apiChangeSymbols(output.Changed); !reflect.DeepEqual(got, []string{"Helper"}) {
+		t.Errorf("changed = %v, want Helper", got)
+	}
+	if len(output.Changed) == 1 && (!strings.Contains(output.Changed[0].Before, "x int") || !strings.Contains(output.Changed[0].After, "y int")) {
+		t.Errorf("change = %+v", output.Changed[0])
+	}
+	if got := apiChangeSymbols(output.Removed); !reflect.DeepEqual(got, []string{"Exported"}) {
+		t.Errorf("removed = %v, want the internal package's Exported", got)
+	}
+
+	output = CheckAPIStabilityOutput{}
+	callTool(t, ctx, CheckAPIStability, CheckAPIStabilityInput{PublicOnly: true, Reset: true}, &output)
+	if output.Breaking != 1 || len(output.Removed) != 0 {
+		t.Errorf("public only: %+v, want just the Helper change", output)
+	}
+
+	// After a reset the current API is the baseline
+	output = CheckAPIStabilityOutput{}
+	callTool(t, ctx, CheckAPIStability, CheckAPIStabilityInput{}, &output)
+	if !output.Stable || len(output.Changed)+len(output.Added)+len(output.Removed) != 0 {
+		t.Errorf("after reset: %+v, want no changes", output)
+	}
+}
+
+func TestCheckAPIStabilityWithoutEdits(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/lib")
+	writeTestFile(t, dir, "lib.go", apiDiffBase)
+
+	if _, err := CheckAPIStability(ctx, mustMarshal(t, CheckAPIStabilityInput{})); err == nil {
+		t.Error("expected an error without a tracked baseline")
+	}
+
+	baseline := NewAPIBaseline()
+	t.Cleanup(baseline.Reset)
+	ctx = WithAPIBaseline(ctx, baseline)
+
+	var output CheckAPIStabilityOutput
+	callTool(t, ctx, CheckAPIStability, CheckAPIStabilityInput{}, &output)
+	if !output.Stable || !strings.Contains(output.Message, "baseline snapshot now") {
+		t.Errorf("got %+v, want the baseline taken on the first check", output)
+	}
+
+	writeTestFile(t, dir, "lib.go", apiDiffBase+"\nfunc Added() {}\n")
+	output = CheckAPIStabilityOutput{}
+	callTool(t, ctx, CheckAPIStability, CheckAPIStabilityInput{}, &output)
+	if !output.Stable || !reflect.DeepEqual(apiChangeSymbols(output.Added), []string{"Added"}) {
+		t.Errorf("got %+v, want an added symbol that is not breaking", output)
+	}
+}
+
+func TestCheckAPIStabilityPath(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/lib")
+	writeTestFile(t, dir, "a/a.go", "package a\n\nfunc A() {}\n")
+	writeTestFile(t, dir, "b/b.go", "package b\n\nfunc B() {}\n")
+	baseline := NewAPIBaseline()
+	t.Cleanup(baseline.Reset)
+	ctx = WithAPIBaseline(ctx, baseline)
+	baseline.Snapshot(ctx)
+
+	writeTestFile(t, dir, "b/b.go", "package b\n\nfunc B(n int) {}\n")
+
+	var output CheckAPIStabilityOutput
+	callTool(t, ctx, CheckAPIStability, CheckAPIStabilityInput{Path: "a"}, &output)
+	if !output.Stable || output.Packages != 1 {
+		t.Errorf("path a: %+v, want package b left out", output)
+	}
+	if _, err := CheckAPIStability(ctx, mustMarshal(t, CheckAPIStabilityInput{Path: ".."})); err == nil {
+		t.Error("expected an error for a path outside the workspace")
+	}
+}
@@ -0,0 +1,199 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CheckPrereqsToolDefinition defines the check_prereqs tool
+var CheckPrereqsToolDefinition = ToolDefinition{
+	Name: "check_prereqs",
+	Description: `Check that the external binaries the tools rely on are installed: whether each is on PATH,
+its version, and which tools need it. Required binaries (go, gofmt) must be present at a supported
+version; optional ones (git, make, gopls, staticcheck, hadolint) only degrade the tools that use
+them. Use it when a tool fails with an error about a missing command.`,
+	InputSchema: CheckPrereqsInputSchema,
+	Function:    CheckPrereqs,
+}
+
+// CheckPrereqsInput defines the input parameters for the check_prereqs tool
+type CheckPrereqsInput struct{}
+
+// CheckPrereqsInputSchema is the JSON schema for the check_prereqs tool
+var CheckPrereqsInputSchema = GenerateSchema[CheckPrereqsInput]()
+
+// PrerequisiteStatus describes whether one external binary is usable
+type PrerequisiteStatus struct {
+	Name       string   `json:"name"`
+	Required   bool     `json:"required"`
+	Status     string   `json:"status"` // ok, missing or outdated
+	Path       string   `json:"path,omitempty"`
+	Version    string   `json:"version,omitempty"`
+	MinVersion string   `json:"min_version,omitempty"`
+	UsedBy     []string `json:"used_by"`
+	Message    string   `json:"message,omitempty"`
+}
+
+// CheckPrereqsOutput represents the structured output of the check_prereqs tool
+type CheckPrereqsOutput struct {
+	Ready         bool                 `json:"ready"`
+	Prerequisites []PrerequisiteStatus `json:"prerequisites"`
+	Message       string               `json:"message"`
+}
+
+// prerequisite is an external binary that tools run
+type prerequisite struct {
+	name        string
+	required    bool
+	versionArgs []string // empty when the binary can't report its version
+	minVersion  string
+	usedBy      []string
+}
+
+// prerequisites are the binaries checked at startup, required ones first
+var prerequisites = []prerequisite{
+	// go.mod files with toolchain lines need Go 1.21 or later
+	{name: "go", required: true, versionArgs: []string{"version"}, minVersion: "1.21",
+		usedBy: []string{"go_command", "go_test", "go_bench", "go_verify", "tidy_preview"}},
+	{name: "gofmt", required: true,
+		usedBy: []string{"go_format", "format_changed"}},
+	{name: "git", versionArgs: []string{"--version"}, minVersion: "2.20",
+		usedBy: []string{"git_operations", "suggest_commit_message", "gen_changelog", "api_diff", "format_changed"}},
+	{name: "make", versionArgs: []string{"--version"},
+		usedBy: []string{"make"}},
+	{name: "gopls", versionArgs: []string{"version"},
+		usedBy: []string{"go_rename"}},
+	{name: "staticcheck", versionArgs: []string{"-version"},
+		usedBy: []string{"find_dead_code"}},
+	{name: "hadolint", versionArgs: []string{"--version"},
+		usedBy: []string{"dockerfile_check"}},
+}
+
+// prereqVersionTimeout bounds how long a binary may take to report its version
+const prereqVersionTimeout = 10 * time.Second
+
+// versionPattern matches the first dotted version number in a version banner
+var versionPattern = regexp.MustCompile(`\d+\.\d+(\.\d+)?`)
+
+// CheckPrereqs implements the check_prereqs tool functionality
+func CheckPrereqs(ctx context.Context, input json.RawMessage) (string, error) {
+	checkInput := CheckPrereqsInput{}
+	err := DecodeInput(input, &checkInput)
+	if err != nil {
+		return "", err
+	}
+
+	statuses, err := Preflight(ctx)
+	output := CheckPrereqsOutput{Ready: err == nil, Prerequisites: statuses}
+	problems := 0
+	for _, status := range statuses {
+		if status.Status != "ok" {
+			problems++
+		}
+	}
+	switch {
+	case err != nil:
+		output.Message = err.Error()
+	case problems > 0:
+		output.Message = fmt.Sprintf("All required binaries are installed; %d optional one(s) are missing or outdated, so the tools using them are limited.", problems)
+	default:
+		output.Message = "All binaries are installed."
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// Preflight checks the binaries tools depend on. It returns the status of each, and an error
+// naming the required binaries that are missing or older than supported.
+func Preflight(ctx context.Context) ([]PrerequisiteStatus, error) {
+	statuses := []PrerequisiteStatus{}
+	var failed []string
+	for _, prereq := range prerequisites {
+		status := checkPrerequisite(ctx, prereq)
+		if status.Required && status.Status != "ok" {
+			failed = append(failed, status.Message)
+		}
+		statuses = append(statuses, status)
+	}
+	if len(failed) > 0 {
+		return statuses, fmt.Errorf("required binaries are unavailable: %s", strings.Join(failed, "; "))
+	}
+	return statuses, nil
+}
+
+// checkPrerequisite looks prereq up on PATH and, if it has a minimum version, checks its version
+func checkPrerequisite(ctx context.Context, prereq prerequisite) PrerequisiteStatus {
+	status := PrerequisiteStatus{
+		Name:       prereq.name,
+		Required:   prereq.required,
+		Status:     "ok",
+		MinVersion: prereq.minVersion,
+		UsedBy:     prereq.usedBy,
+	}
+
+	path, err := exec.LookPath(prereq.name)
+	if err != nil {
+		status.Status = "missing"
+		status.Message = fmt.Sprintf("%s not found in PATH (used by %s)", prereq.name, strings.Join(prereq.usedBy, ", "))
+		return status
+	}
+	status.Path = path
+	if len(prereq.versionArgs) == 0 {
+		return status
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, prereqVersionTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path, prereq.versionArgs...)
+	cmd.WaitDelay = backgroundStopTimeout
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		status.Version = versionPattern.FindString(string(out))
+	}
+
+	if prereq.minVersion == "" {
+		return status
+	}
+	if status.Version == "" {
+		status.Message = fmt.Sprintf("could not determine the version of %s", prereq.name)
+		return status
+	}
+	if compareVersions(status.Version, prereq.minVersion) < 0 {
+		status.Status = "outdated"
+		status.Message = fmt.Sprintf("%s %s is older than the supported %s", prereq.name, status.Version, prereq.minVersion)
+	}
+	return status
+}
+
+// compareVersions compares dotted numeric versions such as 1.21 and 1.21.3, treating missing
+// components as zero
+func compareVersions(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		var x, y int
+		if i < len(aParts) {
+			x, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			y, _ = strconv.Atoi(bParts[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
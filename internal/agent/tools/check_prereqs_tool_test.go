@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeBinaries replaces PATH with a directory of shell scripts printing the given output
+func fakeBinaries(t *testing.T, scripts map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, output := range scripts {
+		script := "#!/bin/sh\necho '" + output + "'\n"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+}
+
+// prereqStatuses returns the status of each prerequisite by name
+func prereqStatuses(statuses []PrerequisiteStatus) map[string]PrerequisiteStatus {
+	byName := map[string]PrerequisiteStatus{}
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+	return byName
+}
+
+func TestPreflightWarnsAboutOptionalBinaries(t *testing.T) {
+	fakeBinaries(t, map[string]string{
+		"go":    "go version go1.22.3 linux/amd64",
+		"gofmt": "",
+		"git":   "git version 2.10.0",
+	})
+
+	statuses, err := Preflight(context.Background())
+	if err != nil {
+		t.Fatalf("Preflight: %v", err)
+	}
+	byName := prereqStatuses(statuses)
+	if got := byName["go"]; got.Status != "ok" || got.Version != "1.22.3" {
+		t.Errorf("go = %+v", got)
+	}
+	if got := byName["git"]; got.Status != "outdated" || !strings.Contains(got.Message, "older than the supported 2.20") {
+		t.Errorf("git = %+v, want it reported as outdated", got)
+	}
+	if got := byName["staticcheck"]; got.Status != "missing" || !strings.Contains(got.Message, "find_dead_code") {
+		t.Errorf("staticcheck = %+v, want it reported as missing", got)
+	}
+
+	var output CheckPrereqsOutput
+	callTool(t, context.Background(), CheckPrereqs, CheckPrereqsInput{}, &output)
+	if !output.Ready || !strings.Contains(output.Message, "optional one(s)") {
+		t.Errorf("got %+v, want ready with optional binaries missing", output)
+	}
+}
+
+func TestPreflightFailsOnMissingRequiredBinary(t *testing.T) {
+	fakeBinaries(t, map[string]string{"gofmt": ""})
+
+	_, err := Preflight(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "go not found in PATH (used by go_command") {
+		t.Fatalf("got %v, want the missing go binary reported", err)
+	}
+
+	var output CheckPrereqsOutput
+	callTool(t, context.Background(), CheckPrereqs, CheckPrereqsInput{}, &output)
+	if output.Ready || prereqStatuses(output.Prerequisites)["go"].Status != "missing" {
+		t.Errorf("got %+v, want not ready", output)
+	}
+}
+
+func TestPreflightFailsOnOutdatedGo(t *testing.T) {
+	fakeBinaries(t, map[string]string{"go": "go version go1.19.5 linux/amd64", "gofmt": ""})
+
+	if _, err := Preflight(context.Background()); err == nil || !strings.Contains(err.Error(), "go 1.19.5 is older than the supported 1.21") {
+		t.Errorf("got %v, want the outdated go reported", err)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.21", "1.21.0", 0},
+		{"1.21.3", "1.21", 1},
+		{"1.9", "1.21", -1},
+		{"2.0", "1.99.9", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
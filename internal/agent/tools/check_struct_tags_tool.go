@@ -0,0 +1,382 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CheckStructTagsToolDefinition defines the check_struct_tags tool
+var CheckStructTagsToolDefinition = ToolDefinition{
+	Name: "check_struct_tags",
+	Description: `Check the struct tags in a Go package for mistakes that silently break encoding.
+Reports tags that do not follow the 'key:"value"' syntax, keys repeated within one tag, two fields
+of a struct that encode to the same name, unknown json and yaml options (such as 'omitemtpy'), and
+structs that mix naming styles for one key (for example snake_case and camelCase json names).
+Each issue includes the file, line, struct, and field.`,
+	InputSchema: CheckStructTagsInputSchema,
+	Function:    CheckStructTags,
+}
+
+// CheckStructTagsInput defines the input parameters for the check_struct_tags tool
+type CheckStructTagsInput struct {
+	Path string `json:"path,omitempty" jsonschema_description:"Package directory or Go file to check. Defaults to the current directory."`
+	Keys string `json:"keys,omitempty" jsonschema_description:"Comma-separated tag keys whose names are checked for duplicates and naming style. Defaults to 'json,yaml,xml,db,toml,mapstructure'." jsonschema_example:"json,db"`
+}
+
+// CheckStructTagsInputSchema is the JSON schema for the check_struct_tags tool
+var CheckStructTagsInputSchema = GenerateSchema[CheckStructTagsInput]()
+
+// StructTagIssue is a problem found in a struct tag
+type StructTagIssue struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Struct string `json:"struct"`
+	Field  string `json:"field"`
+	Tag    string `json:"tag,omitempty"`
+	Issue  string `json:"issue"`
+}
+
+// CheckStructTagsOutput represents the structured output of the check_struct_tags tool
+type CheckStructTagsOutput struct {
+	FilesChecked   int              `json:"files_checked"`
+	StructsChecked int              `json:"structs_checked"`
+	Issues         []StructTagIssue `json:"issues"`
+}
+
+// defaultStructTagKeys are the tag keys whose names are compared by default
+const defaultStructTagKeys = "json,yaml,xml,db,toml,mapstructure"
+
+// knownTagOptions lists the valid options of tag keys whose options are checked
+var knownTagOptions = map[string]map[string]bool{
+	"json": {"omitempty": true, "omitzero": true, "string": true},
+	"yaml": {"omitempty": true, "flow": true, "inline": true},
+}
+
+// CheckStructTags implements the check_struct_tags tool functionality
+func CheckStructTags(ctx context.Context, input json.RawMessage) (string, error) {
+	checkInput := CheckStructTagsInput{}
+	err := json.Unmarshal(input, &checkInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	path := workspaceDir(ctx)
+	if checkInput.Path != "" {
+		path, err = ResolvePath(ctx, checkInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to access %s: %w", path, err)
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.go"))
+		if err != nil {
+			return "", fmt.Errorf("failed to list Go files: %w", err)
+		}
+		if len(files) == 0 {
+			return "", fmt.Errorf("no Go files found in %s", path)
+		}
+	}
+
+	keysSpec := checkInput.Keys
+	if keysSpec == "" {
+		keysSpec = defaultStructTagKeys
+	}
+	var keys []string
+	for _, key := range strings.Split(keysSpec, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	output := CheckStructTagsOutput{Issues: []StructTagIssue{}}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		parsed, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		output.FilesChecked++
+
+		// Name structs after their type declaration; nested anonymous structs have none
+		names := map[*ast.StructType]string{}
+		ast.Inspect(parsed, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.TypeSpec); ok {
+				if structType, ok := spec.Type.(*ast.StructType); ok {
+					names[structType] = spec.Name.Name
+				}
+			}
+			return true
+		})
+
+		ast.Inspect(parsed, func(n ast.Node) bool {
+			structType, ok := n.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			name, ok := names[structType]
+			if !ok {
+				name = "(anonymous)"
+			}
+			output.StructsChecked++
+			output.Issues = append(output.Issues, checkStructTags(fset, name, structType, keys)...)
+			return true
+		})
+	}
+
+	sort.SliceStable(output.Issues, func(i, j int) bool {
+		a, b := output.Issues[i], output.Issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// taggedField is a struct field with a well-formed tag
+type taggedField struct {
+	name string
+	line int
+	tags []structTagPair
+}
+
+// checkStructTags reports the tag issues of one struct
+func checkStructTags(fset *token.FileSet, structName string, structType *ast.StructType, keys []string) []StructTagIssue {
+	var issues []StructTagIssue
+	var fields []taggedField
+
+	for _, field := range structType.Fields.List {
+		position := fset.Position(field.Pos())
+		fieldName := fieldDisplayName(field)
+		issue := func(tag, message string) {
+			issues = append(issues, StructTagIssue{
+				File:   position.Filename,
+				Line:   position.Line,
+				Struct: structName,
+				Field:  fieldName,
+				Tag:    tag,
+				Issue:  message,
+			})
+		}
+
+		if field.Tag == nil {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			issue(field.Tag.Value, "tag is not a valid string literal")
+			continue
+		}
+
+		pairs, err := parseStructTag(tag)
+		if err != nil {
+			issue(tag, err.Error())
+			continue
+		}
+
+		seen := map[string]bool{}
+		for _, pair := range pairs {
+			if seen[pair.key] {
+				issue(tag, fmt.Sprintf("duplicate tag key %q", pair.key))
+			}
+			seen[pair.key] = true
+
+			if options, ok := knownTagOptions[pair.key]; ok {
+				parts := strings.Split(pair.value, ",")
+				for _, option := range parts[1:] {
+					if option != "" && !options[option] {
+						issue(tag, fmt.Sprintf("unknown %s option %q", pair.key, option))
+					}
+				}
+			}
+		}
+
+		// Embedded fields are flattened by most encoders, so only named fields are compared
+		for _, name := range field.Names {
+			fields = append(fields, taggedField{name: name.Name, line: position.Line, tags: pairs})
+		}
+	}
+
+	for _, key := range keys {
+		issues = append(issues, checkTagNames(fset, structName, structType, fields, key)...)
+	}
+	return issues
+}
+
+// checkTagNames reports fields of one struct that share an encoded name under key, or whose
+// name does not follow the naming style used by most of the struct's fields
+func checkTagNames(fset *token.FileSet, structName string, structType *ast.StructType, fields []taggedField, key string) []StructTagIssue {
+	var issues []StructTagIssue
+	file := fset.Position(structType.Pos()).Filename
+
+	type namedField struct {
+		field taggedField
+		name  string
+		style string
+	}
+	var named []namedField
+	styleCounts := map[string]int{}
+	for _, field := range fields {
+		for _, pair := range field.tags {
+			if pair.key != key {
+				continue
+			}
+			name, _, _ := strings.Cut(pair.value, ",")
+			if name == "-" || name == "" {
+				continue
+			}
+			style := tagNameStyle(name)
+			named = append(named, namedField{field: field, name: name, style: style})
+			if style != "" {
+				styleCounts[style]++
+			}
+		}
+	}
+
+	firstUse := map[string]string{}
+	for _, n := range named {
+		if other, ok := firstUse[n.name]; ok {
+			issues = append(issues, StructTagIssue{
+				File:   file,
+				Line:   n.field.line,
+				Struct: structName,
+				Field:  n.field.name,
+				Issue:  fmt.Sprintf("%s name %q is also used by field %s", key, n.name, other),
+			})
+			continue
+		}
+		firstUse[n.name] = n.field.name
+	}
+
+	if len(styleCounts) < 2 {
+		return issues
+	}
+	majority := ""
+	for style, count := range styleCounts {
+		if count > styleCounts[majority] || (count == styleCounts[majority] && style < majority) {
+			majority = style
+		}
+	}
+	for _, n := range named {
+		if n.style != "" && n.style != majority {
+			issues = append(issues, StructTagIssue{
+				File:   file,
+				Line:   n.field.line,
+				Struct: structName,
+				Field:  n.field.name,
+				Issue:  fmt.Sprintf("%s name %q is %s while most fields in %s use %s", key, n.name, n.style, structName, majority),
+			})
+		}
+	}
+	return issues
+}
+
+// tagNameStyle classifies an encoded name as snake_case, kebab-case, camelCase, or PascalCase.
+// Single lowercase words fit every style and return "".
+func tagNameStyle(name string) string {
+	hasUpper := strings.IndexFunc(name, unicode.IsUpper) >= 0
+	switch {
+	case strings.Contains(name, "_") && !hasUpper:
+		return "snake_case"
+	case strings.Contains(name, "-") && !hasUpper:
+		return "kebab-case"
+	case strings.ContainsAny(name, "_-"):
+		return "mixed"
+	case unicode.IsUpper([]rune(name)[0]):
+		return "PascalCase"
+	case hasUpper:
+		return "camelCase"
+	}
+	return ""
+}
+
+// structTagPair is one key:"value" pair of a struct tag
+type structTagPair struct {
+	key   string
+	value string
+}
+
+// parseStructTag splits a tag into its key:"value" pairs following the reflect.StructTag
+// conventions, returning an error describing the first syntax problem
+func parseStructTag(tag string) ([]structTagPair, error) {
+	var pairs []structTagPair
+	for {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			return pairs, nil
+		}
+
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 {
+			return nil, fmt.Errorf("bad syntax for struct tag key")
+		}
+		if i+1 >= len(tag) || tag[i] != ':' {
+			return nil, fmt.Errorf("bad syntax for struct tag pair: expected ':' after key %q", tag[:i])
+		}
+		if tag[i+1] != '"' {
+			return nil, fmt.Errorf("bad syntax for struct tag value of %q: the value must be quoted", tag[:i])
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		// Scan to the closing quote, skipping escapes
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return nil, fmt.Errorf("bad syntax for struct tag value of %q: missing closing quote", key)
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			return nil, fmt.Errorf("bad syntax for struct tag value of %q: %v", key, err)
+		}
+		tag = tag[i+1:]
+		if tag != "" && tag[0] != ' ' {
+			return nil, fmt.Errorf("bad syntax for struct tag: pairs must be separated by a space after %q", key)
+		}
+
+		pairs = append(pairs, structTagPair{key: key, value: value})
+	}
+}
+
+// fieldDisplayName returns the field's names, or its type for an embedded field
+func fieldDisplayName(field *ast.Field) string {
+	if len(field.Names) == 0 {
+		return strings.TrimPrefix(types.ExprString(field.Type), "*")
+	}
+	names := make([]string, len(field.Names))
+	for i, name := range field.Names {
+		names[i] = name.Name
+	}
+	return strings.Join(names, ", ")
+}
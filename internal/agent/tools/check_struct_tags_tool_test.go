@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckStructTagsFlagsIssues(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "model.go", "package model\n\n"+
+		"type User struct {\n"+
+		"\tID      int    `json:\"id\"`\n"+
+		"\tName    string `json:name`\n"+
+		"\tEmail   string `json:\"email\" json:\"mail\"`\n"+
+		"\tCreated string `json:\"created_at,omitemtpy\"`\n"+
+		"\tUpdated string `json:\"updatedAt\"`\n"+
+		"\tDeleted string `json:\"deleted_at\"`\n"+
+		"\tAlias   string `json:\"id\"`\n"+
+		"\tSecret  string `json:\"-\"`\n"+
+		"}\n\n"+
+		"type Clean struct {\n"+
+		"\tFirstName string `json:\"first_name\" db:\"first_name\"`\n"+
+		"}\n")
+
+	var output CheckStructTagsOutput
+	callTool(t, ctx, CheckStructTags, CheckStructTagsInput{}, &output)
+
+	if output.FilesChecked != 1 || output.StructsChecked != 2 {
+		t.Errorf("checked %d files and %d structs, want 1 and 2", output.FilesChecked, output.StructsChecked)
+	}
+
+	tests := []struct {
+		field string
+		line  int
+		issue string
+	}{
+		{"Name", 5, "must be quoted"},
+		{"Email", 6, `duplicate tag key "json"`},
+		{"Created", 7, `unknown json option "omitemtpy"`},
+		{"Updated", 8, "is camelCase while most fields in User use snake_case"},
+		{"Alias", 10, `json name "id" is also used by field ID`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			for _, issue := range output.Issues {
+				if issue.Field == tt.field && strings.Contains(issue.Issue, tt.issue) {
+					if issue.Line != tt.line || issue.Struct != "User" || !strings.HasSuffix(issue.File, "model.go") {
+						t.Errorf("issue = %+v, want User at model.go:%d", issue, tt.line)
+					}
+					return
+				}
+			}
+			t.Errorf("no issue for %s containing %q in %+v", tt.field, tt.issue, output.Issues)
+		})
+	}
+
+	for _, issue := range output.Issues {
+		if issue.Struct == "Clean" || issue.Field == "Secret" || issue.Field == "ID" {
+			t.Errorf("unexpected issue %+v", issue)
+		}
+	}
+}
+
+func TestCheckStructTagsKeys(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "row.go", "package model\n\n"+
+		"type Row struct {\n"+
+		"\tUserID  int `json:\"user_id\" db:\"userId\"`\n"+
+		"\tOrderID int `json:\"order_id\" db:\"order_id\"`\n"+
+		"}\n")
+
+	var output CheckStructTagsOutput
+	callTool(t, ctx, CheckStructTags, CheckStructTagsInput{Path: "row.go", Keys: "json"}, &output)
+	if len(output.Issues) != 0 {
+		t.Errorf("issues = %+v, want db names ignored when only json is checked", output.Issues)
+	}
+
+	callTool(t, ctx, CheckStructTags, CheckStructTagsInput{Path: "row.go", Keys: "db"}, &output)
+	if len(output.Issues) != 1 || !strings.Contains(output.Issues[0].Issue, "db name") {
+		t.Errorf("issues = %+v, want one db naming style issue", output.Issues)
+	}
+}
+
+func TestParseStructTag(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    int
+		wantErr bool
+	}{
+		{`json:"name,omitempty" db:"name"`, 2, false},
+		{`json:"a\"b"`, 1, false},
+		{``, 0, false},
+		{`json:"name"db:"name"`, 0, true},
+		{`json:"name`, 0, true},
+		{`json`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			pairs, err := parseStructTag(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error: %v", err, tt.wantErr)
+			}
+			if len(pairs) != tt.want {
+				t.Errorf("got %d pairs, want %d", len(pairs), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+)
+
+// CheckTemplatesToolDefinition defines the check_templates tool
+var CheckTemplatesToolDefinition = ToolDefinition{
+	Name: "check_templates",
+	Description: `Parse Go text/template and html/template files and report syntax errors with their line.
+Each file is parsed as an html template when its name ends in .html, .htm or .gohtml (or contains
+'.html.', as in page.html.tmpl) and as a text template otherwise, unless 'engine' is set.
+Set 'execute' to also render every template against 'data' with missing map keys treated as
+errors, which catches runtime problems such as misspelled fields. All files are loaded into one
+set during execution so {{template "name"}} references between them resolve. Names listed in
+'funcs' are defined as stub functions so templates using custom functions still parse.`,
+	InputSchema: CheckTemplatesInputSchema,
+	Function:    CheckTemplates,
+}
+
+// CheckTemplatesInput defines the input parameters for the check_templates tool
+type CheckTemplatesInput struct {
+	Paths   []string               `json:"paths" jsonschema_required:"true" jsonschema_description:"Template files or glob patterns to check" jsonschema_example:"[\"templates/*.tmpl\"]"`
+	Engine  string                 `json:"engine,omitempty" jsonschema_description:"'text' or 'html' to parse every file with that package instead of inferring it from the file name"`
+	Execute bool                   `json:"execute,omitempty" jsonschema_description:"If true, render each template against 'data' to find runtime errors"`
+	Data    map[string]interface{} `json:"data,omitempty" jsonschema_description:"Sample data the templates are executed against, e.g. {\"Title\": \"Home\"}"`
+	Funcs   []string               `json:"funcs,omitempty" jsonschema_description:"Names of custom template functions to stub so templates using them parse" jsonschema_example:"[\"upper\", \"formatDate\"]"`
+}
+
+// CheckTemplatesInputSchema is the JSON schema for the check_templates tool
+var CheckTemplatesInputSchema = GenerateSchema[CheckTemplatesInput]()
+
+// TemplateCheckResult is the outcome of checking one template file
+type TemplateCheckResult struct {
+	Path       string `json:"path"`
+	Engine     string `json:"engine"`
+	Valid      bool   `json:"valid"`
+	ParseError string `json:"parse_error,omitempty"`
+	ExecError  string `json:"exec_error,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Output     string `json:"output,omitempty"`
+}
+
+// CheckTemplatesOutput represents the structured output of the check_templates tool
+type CheckTemplatesOutput struct {
+	Checked int                   `json:"checked"`
+	Invalid int                   `json:"invalid"`
+	Results []TemplateCheckResult `json:"results"`
+}
+
+// maxTemplateOutput caps the rendered output returned for each executed template
+const maxTemplateOutput = 2000
+
+// templateSetName names the empty root that the checked templates are associated with, so that
+// no file shares its name
+const templateSetName = "check_templates"
+
+// templateLinePattern extracts the line from errors such as 'template: page.html:12: unexpected ...'
+var templateLinePattern = regexp.MustCompile(`template: [^:]+:(\d+)`)
+
+// CheckTemplates implements the check_templates tool functionality
+func CheckTemplates(ctx context.Context, input json.RawMessage) (string, error) {
+	checkInput := CheckTemplatesInput{}
+	err := DecodeInput(input, &checkInput)
+	if err != nil {
+		return "", err
+	}
+
+	if len(checkInput.Paths) == 0 {
+		return "", fmt.Errorf("paths parameter is required")
+	}
+	if checkInput.Engine != "" && checkInput.Engine != "text" && checkInput.Engine != "html" {
+		return "", fmt.Errorf("invalid engine: %s. Must be 'text' or 'html'", checkInput.Engine)
+	}
+
+	var files []string
+	for _, pattern := range checkInput.Paths {
+		resolved, err := ResolvePath(ctx, pattern)
+		if err != nil {
+			return "", err
+		}
+		matches, err := filepath.Glob(resolved)
+		if err != nil {
+			return "", fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return "", fmt.Errorf("no files match %s", pattern)
+		}
+		files = append(files, matches...)
+	}
+
+	funcs := map[string]interface{}{}
+	for _, name := range checkInput.Funcs {
+		funcs[name] = func(args ...interface{}) string { return "" }
+	}
+
+	contents := map[string]string{}
+	output := CheckTemplatesOutput{Results: []TemplateCheckResult{}}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		contents[file] = string(content)
+
+		result := TemplateCheckResult{Path: file, Engine: templateEngine(file, checkInput.Engine), Valid: true}
+		if _, err := parseTemplate(result.Engine, filepath.Base(file), string(content), funcs); err != nil {
+			result.Valid = false
+			result.ParseError = err.Error()
+			result.Line = templateErrorLine(err)
+		}
+		output.Results = append(output.Results, result)
+	}
+
+	if checkInput.Execute {
+		filesByEngine := map[string][]string{}
+		for _, result := range output.Results {
+			if result.Valid {
+				filesByEngine[result.Engine] = append(filesByEngine[result.Engine], result.Path)
+			}
+		}
+		for i := range output.Results {
+			result := &output.Results[i]
+			if !result.Valid {
+				continue
+			}
+			rendered, err := executeTemplateSet(result.Engine, result.Path, filesByEngine[result.Engine], contents, funcs, checkInput.Data)
+			if err != nil {
+				result.Valid = false
+				result.ExecError = err.Error()
+				result.Line = templateErrorLine(err)
+				continue
+			}
+			if len(rendered) > maxTemplateOutput {
+				rendered = rendered[:maxTemplateOutput] + "\n... (truncated)"
+			}
+			result.Output = rendered
+		}
+	}
+
+	output.Checked = len(output.Results)
+	for _, result := range output.Results {
+		if !result.Valid {
+			output.Invalid++
+		}
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// templateEngine picks 'text' or 'html' for a file, honoring an explicit engine
+func templateEngine(path, engine string) string {
+	if engine != "" {
+		return engine
+	}
+	name := strings.ToLower(filepath.Base(path))
+	switch filepath.Ext(name) {
+	case ".html", ".htm", ".gohtml":
+		return "html"
+	}
+	if strings.Contains(name, ".html.") {
+		return "html"
+	}
+	return "text"
+}
+
+// executableTemplate is the part of text/template and html/template used to render a template
+type executableTemplate interface {
+	ExecuteTemplate(w io.Writer, name string, data interface{}) error
+}
+
+// parseTemplate parses content as a template called name with the requested engine
+func parseTemplate(engine, name, content string, funcs map[string]interface{}) (executableTemplate, error) {
+	if engine == "html" {
+		return htmltemplate.New(name).Option("missingkey=error").Funcs(funcs).Parse(content)
+	}
+	return texttemplate.New(name).Option("missingkey=error").Funcs(funcs).Parse(content)
+}
+
+// executeTemplateSet loads files, which all use engine, into one set and renders the template of path
+func executeTemplateSet(engine, path string, files []string, contents map[string]string, funcs map[string]interface{}, data map[string]interface{}) (string, error) {
+	name := filepath.Base(path)
+	var set executableTemplate
+
+	if engine == "html" {
+		root := htmltemplate.New(templateSetName).Option("missingkey=error").Funcs(funcs)
+		for _, file := range files {
+			if _, err := root.New(filepath.Base(file)).Parse(contents[file]); err != nil {
+				return "", err
+			}
+		}
+		set = root
+	} else {
+		root := texttemplate.New(templateSetName).Option("missingkey=error").Funcs(funcs)
+		for _, file := range files {
+			if _, err := root.New(filepath.Base(file)).Parse(contents[file]); err != nil {
+				return "", err
+			}
+		}
+		set = root
+	}
+
+	var rendered strings.Builder
+	if err := set.ExecuteTemplate(&rendered, name, data); err != nil {
+		return "", err
+	}
+	return rendered.String(), nil
+}
+
+// templateErrorLine returns the line reported in a template error, or 0 if there is none
+func templateErrorLine(err error) int {
+	matches := templateLinePattern.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return 0
+	}
+	line, _ := strconv.Atoi(matches[1])
+	return line
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckTemplatesParseErrors(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "templates/valid.tmpl", "Hello {{.Name}}\n{{range .Items}}- {{.}}\n{{end}}")
+	writeTestFile(t, dir, "templates/broken.tmpl", "line one\nline two {{if .Ready}}\nno end\n")
+	writeTestFile(t, dir, "templates/custom.tmpl", "{{upper .Name}}")
+
+	var output CheckTemplatesOutput
+	callTool(t, ctx, CheckTemplates, CheckTemplatesInput{Paths: []string{"templates/*.tmpl"}, Funcs: []string{"upper"}}, &output)
+
+	if output.Checked != 3 || output.Invalid != 1 {
+		t.Fatalf("checked %d, invalid %d, want 3 and 1: %+v", output.Checked, output.Invalid, output.Results)
+	}
+	for _, result := range output.Results {
+		broken := strings.HasSuffix(result.Path, "broken.tmpl")
+		if result.Valid == broken || result.Engine != "text" {
+			t.Errorf("%s: valid %v engine %s", result.Path, result.Valid, result.Engine)
+		}
+		if broken && (result.Line != 4 || !strings.Contains(result.ParseError, "unexpected EOF")) {
+			t.Errorf("broken template = %+v, want an unexpected EOF at line 4", result)
+		}
+	}
+
+	// Without the stub, the custom function is a parse error
+	callTool(t, ctx, CheckTemplates, CheckTemplatesInput{Paths: []string{"templates/custom.tmpl"}}, &output)
+	if output.Invalid != 1 || !strings.Contains(output.Results[0].ParseError, `function "upper" not defined`) {
+		t.Errorf("got %+v, want the undefined function reported", output.Results)
+	}
+}
+
+func TestCheckTemplatesExecute(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "page.html", `<h1>{{.Title}}</h1>{{template "footer.html" .}}`)
+	writeTestFile(t, dir, "footer.html", `<p>{{.Owner}}</p>`)
+	writeTestFile(t, dir, "mail.txt", "Dear {{.Nmae}}")
+
+	var output CheckTemplatesOutput
+	callTool(t, ctx, CheckTemplates, CheckTemplatesInput{
+		Paths:   []string{"page.html", "footer.html", "mail.txt"},
+		Execute: true,
+		Data:    map[string]interface{}{"Title": "<Home>", "Owner": "me", "Name": "Ada"},
+	}, &output)
+
+	page, mail := output.Results[0], output.Results[2]
+	if !page.Valid || page.Engine != "html" || page.Output != "<h1>&lt;Home&gt;</h1><p>me</p>" {
+		t.Errorf("page = %+v, want escaped html with the footer included", page)
+	}
+	if mail.Valid || mail.Engine != "text" || mail.Line != 1 || !strings.Contains(mail.ExecError, "Nmae") {
+		t.Errorf("mail = %+v, want the misspelled key reported", mail)
+	}
+	if output.Invalid != 1 {
+		t.Errorf("invalid = %d, want 1", output.Invalid)
+	}
+}
+
+func TestTemplateEngine(t *testing.T) {
+	tests := []struct {
+		path   string
+		engine string
+		want   string
+	}{
+		{"page.html", "", "html"},
+		{"page.GOHTML", "", "html"},
+		{"page.html.tmpl", "", "html"},
+		{"mail.tmpl", "", "text"},
+		{"page.html", "text", "text"},
+	}
+	for _, tt := range tests {
+		if got := templateEngine(tt.path, tt.engine); got != tt.want {
+			t.Errorf("templateEngine(%q, %q) = %q, want %q", tt.path, tt.engine, got, tt.want)
+		}
+	}
+}
+
+func TestCheckTemplatesErrors(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+
+	if _, err := CheckTemplates(ctx, mustMarshal(t, CheckTemplatesInput{Paths: []string{"missing/*.tmpl"}})); err == nil {
+		t.Error("expected an error when no files match")
+	}
+	if _, err := CheckTemplates(ctx, mustMarshal(t, CheckTemplatesInput{Paths: []string{"x"}, Engine: "jinja"})); err == nil {
+		t.Error("expected an error for an unknown engine")
+	}
+}
@@ -0,0 +1,224 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ChmodToolDefinition defines the chmod tool
+var ChmodToolDefinition = ToolDefinition{
+	Name: "chmod",
+	Description: `Read or change the permission mode of a file or directory in the workspace.
+Without 'mode', the current mode is reported. With 'mode', it is applied and the old and new
+modes are reported. The mode is octal ('755', '0644') or symbolic like the chmod command
+('+x', 'u+x,go-w', 'a=r'). Use it to make a generated script executable.`,
+	InputSchema:           ChmodInputSchema,
+	Function:              Chmod,
+	CountsTowardLoopLimit: true,
+}
+
+// ChmodInput defines the input parameters for the chmod tool
+type ChmodInput struct {
+	Path string `json:"path" jsonschema_required:"true" jsonschema_description:"File or directory whose mode to read or change"`
+	Mode string `json:"mode,omitempty" jsonschema_description:"New mode, octal ('755') or symbolic ('u+x,go-w'). Leave empty to only read the current mode." jsonschema_example:"+x"`
+}
+
+// ChmodInputSchema is the JSON schema for the chmod tool
+var ChmodInputSchema = GenerateSchema[ChmodInput]()
+
+// ChmodOutput represents the structured output of the chmod tool
+type ChmodOutput struct {
+	Path        string `json:"path"`
+	Mode        string `json:"mode"`
+	Symbolic    string `json:"symbolic"`
+	OldMode     string `json:"old_mode,omitempty"`
+	OldSymbolic string `json:"old_symbolic,omitempty"`
+	Changed     bool   `json:"changed"`
+}
+
+// Chmod implements the chmod tool functionality
+func Chmod(ctx context.Context, input json.RawMessage) (string, error) {
+	chmodInput := ChmodInput{}
+	err := json.Unmarshal(input, &chmodInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if chmodInput.Path == "" {
+		return "", fmt.Errorf("path parameter is required")
+	}
+
+	path, err := ResolvePath(ctx, chmodInput.Path)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat '%s': %w", chmodInput.Path, err)
+	}
+
+	oldMode := info.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+	output := ChmodOutput{
+		Path:     chmodInput.Path,
+		Mode:     formatOctalMode(oldMode),
+		Symbolic: info.Mode().String(),
+	}
+
+	if chmodInput.Mode != "" {
+		newMode, err := parseFileMode(chmodInput.Mode, oldMode, info.IsDir())
+		if err != nil {
+			return "", err
+		}
+		if err := os.Chmod(path, newMode); err != nil {
+			return "", fmt.Errorf("failed to change mode of '%s': %w", chmodInput.Path, err)
+		}
+		if info, err = os.Stat(path); err != nil {
+			return "", fmt.Errorf("failed to stat '%s': %w", chmodInput.Path, err)
+		}
+
+		output.OldMode, output.OldSymbolic = output.Mode, output.Symbolic
+		output.Mode = formatOctalMode(info.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky))
+		output.Symbolic = info.Mode().String()
+		output.Changed = output.Mode != output.OldMode
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// Unix-style special mode bits as written in octal modes
+const (
+	unixSetuid = 04000
+	unixSetgid = 02000
+	unixSticky = 01000
+)
+
+// formatOctalMode renders the permission and special bits of mode as a 4-digit octal string
+func formatOctalMode(mode os.FileMode) string {
+	return fmt.Sprintf("%04o", toUnixMode(mode))
+}
+
+// toUnixMode converts the permission and special bits of mode to their Unix numeric form
+func toUnixMode(mode os.FileMode) uint32 {
+	bits := uint32(mode & os.ModePerm)
+	if mode&os.ModeSetuid != 0 {
+		bits |= unixSetuid
+	}
+	if mode&os.ModeSetgid != 0 {
+		bits |= unixSetgid
+	}
+	if mode&os.ModeSticky != 0 {
+		bits |= unixSticky
+	}
+	return bits
+}
+
+// fromUnixMode converts a Unix numeric mode to an os.FileMode
+func fromUnixMode(bits uint32) os.FileMode {
+	mode := os.FileMode(bits) & os.ModePerm
+	if bits&unixSetuid != 0 {
+		mode |= os.ModeSetuid
+	}
+	if bits&unixSetgid != 0 {
+		mode |= os.ModeSetgid
+	}
+	if bits&unixSticky != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
+
+// parseFileMode interprets spec as an octal mode or as comma-separated symbolic clauses
+// ('[ugoa]*[+-=][rwxXst]*') applied to current
+func parseFileMode(spec string, current os.FileMode, isDir bool) (os.FileMode, error) {
+	spec = strings.TrimSpace(spec)
+	if spec != "" && strings.Trim(spec, "01234567") == "" {
+		bits, err := strconv.ParseUint(spec, 8, 32)
+		if err != nil || bits > 07777 {
+			return 0, fmt.Errorf("invalid octal mode: %s", spec)
+		}
+		return fromUnixMode(uint32(bits)), nil
+	}
+
+	bits := toUnixMode(current)
+	for _, clause := range strings.Split(spec, ",") {
+		i := 0
+		var who uint32
+		for ; i < len(clause) && strings.IndexByte("ugoa", clause[i]) >= 0; i++ {
+			switch clause[i] {
+			case 'u':
+				who |= unixSetuid | 0700
+			case 'g':
+				who |= unixSetgid | 0070
+			case 'o':
+				who |= unixSticky | 0007
+			case 'a':
+				who |= 07777
+			}
+		}
+		if who == 0 {
+			who = 07777
+		}
+
+		if i == len(clause) {
+			return 0, fmt.Errorf("invalid symbolic mode: %q. Expected an operator '+', '-', or '='", clause)
+		}
+		// Several operators may follow one 'who', as in 'u-w+x'
+		for i < len(clause) {
+			op := clause[i]
+			if op != '+' && op != '-' && op != '=' {
+				return 0, fmt.Errorf("invalid symbolic mode: %q. Expected an operator '+', '-', or '='", clause)
+			}
+			i++
+
+			var perms uint32
+			for ; i < len(clause) && strings.IndexByte("+-=", clause[i]) < 0; i++ {
+				switch clause[i] {
+				case 'r':
+					perms |= 0444
+				case 'w':
+					perms |= 0222
+				case 'x':
+					perms |= 0111
+				case 'X':
+					// Execute only for directories or files already executable by someone
+					if isDir || bits&0111 != 0 {
+						perms |= 0111
+					}
+				case 's':
+					perms |= unixSetuid | unixSetgid
+				case 't':
+					perms |= unixSticky
+				default:
+					return 0, fmt.Errorf("invalid permission %q in mode %q. Must be one of r, w, x, X, s, t", clause[i], clause)
+				}
+			}
+
+			perms &= who
+			switch op {
+			case '+':
+				bits |= perms
+			case '-':
+				bits &^= perms
+			case '=':
+				// Directories keep their setuid and setgid bits unless named explicitly
+				reset := who
+				if isDir {
+					reset &^= unixSetuid | unixSetgid
+				}
+				bits = bits&^reset | perms
+			}
+		}
+	}
+
+	return fromUnixMode(bits), nil
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestChmodSetsExecutable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permission bits are not supported on Windows")
+	}
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "scripts/build.sh", "#!/bin/sh\n")
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var output ChmodOutput
+	callTool(t, ctx, Chmod, ChmodInput{Path: "scripts/build.sh", Mode: "0755"}, &output)
+
+	if output.OldMode != "0644" || output.Mode != "0755" || !output.Changed {
+		t.Errorf("got %+v, want 0644 changed to 0755", output)
+	}
+	if output.Symbolic != "-rwxr-xr-x" || output.OldSymbolic != "-rw-r--r--" {
+		t.Errorf("symbolic modes = %q and %q", output.OldSymbolic, output.Symbolic)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("file mode = %o, want 0755", info.Mode().Perm())
+	}
+}
+
+func TestChmodReadsMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permission bits are not supported on Windows")
+	}
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "notes.txt", "notes\n")
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var output ChmodOutput
+	callTool(t, ctx, Chmod, ChmodInput{Path: "notes.txt"}, &output)
+	if output.Mode != "0600" || output.Changed || output.OldMode != "" {
+		t.Errorf("got %+v, want the current mode only", output)
+	}
+}
+
+func TestParseFileMode(t *testing.T) {
+	tests := []struct {
+		spec    string
+		current os.FileMode
+		isDir   bool
+		want    os.FileMode
+	}{
+		{"755", 0644, false, 0755},
+		{"+x", 0644, false, 0755},
+		{"u+x", 0644, false, 0744},
+		{"go-w", 0666, false, 0644},
+		{"u-w+x", 0644, false, 0544},
+		{"a=r", 0755, false, 0444},
+		{"u=rwx,go=rx", 0600, false, 0755},
+		{"a+X", 0644, false, 0644},
+		{"a+X", 0644, true, 0755},
+		{"+t", 0755, true, 0755 | os.ModeSticky},
+		{"4755", 0644, false, 0755 | os.ModeSetuid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parseFileMode(tt.spec, tt.current, tt.isDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("parseFileMode(%q, %v) = %v, want %v", tt.spec, tt.current, got, tt.want)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"99", "17777", "u", "u+q", "x+"} {
+		if _, err := parseFileMode(invalid, 0644, false); err == nil {
+			t.Errorf("parseFileMode(%q): expected an error", invalid)
+		}
+	}
+}
+
+func TestChmodRejectsPathOutsideWorkspace(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	if _, err := Chmod(ctx, mustMarshal(t, ChmodInput{Path: "../outside.sh", Mode: "755"})); err == nil {
+		t.Error("expected a path outside the workspace to be rejected")
+	}
+}
@@ -0,0 +1,288 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CommitMessageToolDefinition defines the suggest_commit_message tool
+var CommitMessageToolDefinition = ToolDefinition{
+	Name: "suggest_commit_message",
+	Description: `Suggest a conventional-commit style message for the currently staged changes.
+The proposal (type, scope, summary, and body bullet points) is derived from 'git diff --cached'
+using simple rules based on the changed files and hunks. Stage your changes first, then refine
+the suggestion before committing.`,
+	InputSchema:           CommitMessageInputSchema,
+	Function:              SuggestCommitMessage,
+	CountsTowardLoopLimit: true,
+}
+
+// CommitMessageInput defines the input parameters for the suggest_commit_message tool
+type CommitMessageInput struct {
+	Type  string `json:"type,omitempty" jsonschema_description:"Optional commit type to use instead of the inferred one (feat, fix, refactor, docs, test, build, chore, ...)"`
+	Scope string `json:"scope,omitempty" jsonschema_description:"Optional scope to use instead of the inferred one"`
+}
+
+// CommitMessageInputSchema is the JSON schema for the suggest_commit_message tool
+var CommitMessageInputSchema = GenerateSchema[CommitMessageInput]()
+
+// CommitMessageOutput represents the structured output of the suggest_commit_message tool
+type CommitMessageOutput struct {
+	Type    string   `json:"type"`
+	Scope   string   `json:"scope,omitempty"`
+	Summary string   `json:"summary"`
+	Body    []string `json:"body"`
+	Message string   `json:"message"`
+}
+
+// stagedFile describes a single file in the staged diff
+type stagedFile struct {
+	Status    string
+	Path      string
+	Added     int
+	Deleted   int
+	Functions []string
+}
+
+// SuggestCommitMessage implements the suggest_commit_message tool functionality
+func SuggestCommitMessage(ctx context.Context, input json.RawMessage) (string, error) {
+	commitInput := CommitMessageInput{}
+	err := json.Unmarshal(input, &commitInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	files, err := readStagedFiles(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no staged changes found; stage files with git_operations 'add' first")
+	}
+
+	output := buildCommitMessage(files)
+	if commitInput.Type != "" {
+		output.Type = commitInput.Type
+	}
+	if commitInput.Scope != "" {
+		output.Scope = commitInput.Scope
+	}
+	output.Message = formatCommitMessage(output)
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// readStagedFiles collects status, line counts, and touched functions for staged files
+func readStagedFiles(ctx context.Context) ([]stagedFile, error) {
+	statusOutput, err := gitCommand(ctx, "diff", "--cached", "--name-status", "--no-renames").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %s, %w", string(statusOutput), err)
+	}
+
+	filesByPath := make(map[string]*stagedFile)
+	var files []*stagedFile
+	for _, line := range strings.Split(strings.TrimSpace(string(statusOutput)), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 {
+			continue
+		}
+		file := &stagedFile{Status: fields[0][:1], Path: fields[len(fields)-1]}
+		filesByPath[file.Path] = file
+		files = append(files, file)
+	}
+
+	numstatOutput, err := gitCommand(ctx, "diff", "--cached", "--numstat", "--no-renames").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %s, %w", string(numstatOutput), err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(numstatOutput)), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			continue
+		}
+		if file, ok := filesByPath[fields[2]]; ok {
+			// Binary files report '-' for both counts
+			file.Added, _ = strconv.Atoi(fields[0])
+			file.Deleted, _ = strconv.Atoi(fields[1])
+		}
+	}
+
+	diffOutput, err := gitCommand(ctx, "diff", "--cached", "--no-renames", "-U0").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %s, %w", string(diffOutput), err)
+	}
+	collectHunkFunctions(string(diffOutput), filesByPath)
+
+	result := make([]stagedFile, 0, len(files))
+	for _, file := range files {
+		result = append(result, *file)
+	}
+	return result, nil
+}
+
+// hunkFunctionPattern captures the function context git prints after a hunk header
+var hunkFunctionPattern = regexp.MustCompile(`^@@ [^@]+ @@ (?:func (?:\([^)]*\) )?)?([A-Za-z_][A-Za-z0-9_]*)`)
+
+// collectHunkFunctions records the enclosing function names of each hunk
+func collectHunkFunctions(diff string, filesByPath map[string]*stagedFile) {
+	var current *stagedFile
+	seen := make(map[string]bool)
+
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "+++ ") {
+			name := strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			current = filesByPath[name]
+			continue
+		}
+		if current == nil || !strings.HasPrefix(line, "@@") {
+			continue
+		}
+		if matches := hunkFunctionPattern.FindStringSubmatch(line); matches != nil {
+			key := current.Path + "\x00" + matches[1]
+			if !seen[key] {
+				seen[key] = true
+				current.Functions = append(current.Functions, matches[1])
+			}
+		}
+	}
+}
+
+// buildCommitMessage derives the commit type, scope, summary, and body from staged files
+func buildCommitMessage(files []stagedFile) CommitMessageOutput {
+	output := CommitMessageOutput{
+		Type:  inferCommitType(files),
+		Scope: inferCommitScope(files),
+		Body:  []string{},
+	}
+
+	if len(files) == 1 {
+		file := files[0]
+		output.Summary = fmt.Sprintf("%s %s", statusVerb(file.Status, false), path.Base(file.Path))
+	} else {
+		counts := make(map[string]int)
+		for _, file := range files {
+			counts[file.Status]++
+		}
+		var parts []string
+		for _, status := range []string{"A", "M", "D"} {
+			if counts[status] > 0 {
+				parts = append(parts, fmt.Sprintf("%s %d file(s)", statusVerb(status, len(parts) > 0), counts[status]))
+			}
+		}
+		output.Summary = strings.Join(parts, ", ")
+	}
+
+	for _, file := range files {
+		bullet := fmt.Sprintf("%s %s (+%d/-%d)", statusVerb(file.Status, false), file.Path, file.Added, file.Deleted)
+		if len(file.Functions) > 0 && file.Status != "A" {
+			bullet += ": touches " + strings.Join(file.Functions, ", ")
+		}
+		output.Body = append(output.Body, bullet)
+	}
+
+	return output
+}
+
+// inferCommitType picks a conventional commit type from the kinds of files changed
+func inferCommitType(files []stagedFile) string {
+	allMatch := func(predicate func(stagedFile) bool) bool {
+		for _, file := range files {
+			if !predicate(file) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case allMatch(func(f stagedFile) bool { return strings.HasSuffix(f.Path, "_test.go") }):
+		return "test"
+	case allMatch(func(f stagedFile) bool { return strings.HasSuffix(f.Path, ".md") || strings.HasPrefix(f.Path, "docs/") }):
+		return "docs"
+	case allMatch(func(f stagedFile) bool {
+		base := path.Base(f.Path)
+		return base == "go.mod" || base == "go.sum" || base == "Makefile" || base == "Dockerfile"
+	}):
+		return "build"
+	case allMatch(func(f stagedFile) bool { return strings.HasPrefix(f.Path, ".github/") }):
+		return "ci"
+	case allMatch(func(f stagedFile) bool { return f.Status == "D" }):
+		return "chore"
+	}
+
+	for _, file := range files {
+		if file.Status == "A" && !strings.HasSuffix(file.Path, "_test.go") {
+			return "feat"
+		}
+	}
+	return "refactor"
+}
+
+// inferCommitScope uses the deepest directory shared by all changed files
+func inferCommitScope(files []stagedFile) string {
+	common := path.Dir(files[0].Path)
+	for _, file := range files[1:] {
+		dir := path.Dir(file.Path)
+		for common != "." && dir != common && !strings.HasPrefix(dir, common+"/") {
+			common = path.Dir(common)
+		}
+	}
+
+	if common == "." {
+		if len(files) == 1 {
+			return strings.TrimSuffix(path.Base(files[0].Path), path.Ext(files[0].Path))
+		}
+		return ""
+	}
+	return path.Base(common)
+}
+
+// statusVerb returns the verb describing a git name-status code
+func statusVerb(status string, lowercase bool) string {
+	verbs := map[string]string{"A": "Add", "M": "Update", "D": "Remove", "T": "Change type of"}
+	verb, ok := verbs[status]
+	if !ok {
+		verb = "Update"
+	}
+	if lowercase {
+		return strings.ToLower(verb)
+	}
+	return verb
+}
+
+// formatCommitMessage renders the proposal as a complete commit message
+func formatCommitMessage(output CommitMessageOutput) string {
+	var b strings.Builder
+	b.WriteString(output.Type)
+	if output.Scope != "" {
+		fmt.Fprintf(&b, "(%s)", output.Scope)
+	}
+	fmt.Fprintf(&b, ": %s", lowercaseFirst(output.Summary))
+
+	if len(output.Body) > 0 {
+		b.WriteString("\n\n")
+		for _, bullet := range output.Body {
+			fmt.Fprintf(&b, "- %s\n", bullet)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// lowercaseFirst lowercases the first letter of s
+func lowercaseFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,91 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSuggestCommitMessageForAddedFile(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "README.md", "# app\n")
+	commitTestFiles(t, dir, "initial commit")
+
+	writeTestFile(t, dir, "internal/cache/cache.go", "package cache\n\n// Get returns nothing yet\nfunc Get() {}\n")
+	runTestGit(t, dir, "add", "-A")
+
+	var output CommitMessageOutput
+	callTool(t, ctx, SuggestCommitMessage, CommitMessageInput{}, &output)
+
+	if output.Type != "feat" || output.Scope != "cache" {
+		t.Errorf("got type %q scope %q, want feat(cache)", output.Type, output.Scope)
+	}
+	if output.Summary != "Add cache.go" {
+		t.Errorf("summary = %q, want \"Add cache.go\"", output.Summary)
+	}
+	if len(output.Body) != 1 || !strings.Contains(output.Body[0], "internal/cache/cache.go (+4/-0)") {
+		t.Errorf("body doesn't reference the added file: %v", output.Body)
+	}
+	if !strings.HasPrefix(output.Message, "feat(cache): add cache.go\n\n- Add internal/cache/cache.go") {
+		t.Errorf("unexpected message:\n%s", output.Message)
+	}
+}
+
+func TestSuggestCommitMessageForModifiedFiles(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "pkg/a.go", "package pkg\n\nfunc A() int {\n\treturn 1\n}\n")
+	writeTestFile(t, dir, "pkg/a_test.go", "package pkg\n")
+	commitTestFiles(t, dir, "initial commit")
+
+	writeTestFile(t, dir, "pkg/a.go", "package pkg\n\nfunc A() int {\n\treturn 2\n}\n")
+	writeTestFile(t, dir, "pkg/a_test.go", "package pkg\n\n// tests\n")
+	runTestGit(t, dir, "add", "-A")
+
+	var output CommitMessageOutput
+	callTool(t, ctx, SuggestCommitMessage, CommitMessageInput{Type: "fix"}, &output)
+
+	if output.Type != "fix" || output.Scope != "pkg" {
+		t.Errorf("got type %q scope %q, want the fix override and pkg scope", output.Type, output.Scope)
+	}
+	if output.Summary != "Update 2 file(s)" {
+		t.Errorf("summary = %q", output.Summary)
+	}
+	if !strings.Contains(strings.Join(output.Body, "\n"), "Update pkg/a.go (+1/-1): touches A") {
+		t.Errorf("body doesn't name the touched function: %v", output.Body)
+	}
+}
+
+func TestInferCommitType(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []stagedFile
+		want  string
+	}{
+		{"tests only", []stagedFile{{Status: "A", Path: "a_test.go"}}, "test"},
+		{"docs only", []stagedFile{{Status: "M", Path: "README.md"}, {Status: "A", Path: "docs/guide.txt"}}, "docs"},
+		{"module files", []stagedFile{{Status: "M", Path: "go.mod"}, {Status: "M", Path: "go.sum"}}, "build"},
+		{"workflows", []stagedFile{{Status: "M", Path: ".github/workflows/ci.yml"}}, "ci"},
+		{"deletions", []stagedFile{{Status: "D", Path: "old.go"}}, "chore"},
+		{"new source", []stagedFile{{Status: "A", Path: "new.go"}, {Status: "M", Path: "old.go"}}, "feat"},
+		{"modified source", []stagedFile{{Status: "M", Path: "old.go"}}, "refactor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inferCommitType(tt.files); got != tt.want {
+				t.Errorf("inferCommitType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestCommitMessageNothingStaged(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "main.go", "package main\n")
+	commitTestFiles(t, dir, "initial commit")
+
+	if _, err := SuggestCommitMessage(ctx, []byte(`{}`)); err == nil || !strings.Contains(err.Error(), "no staged changes") {
+		t.Errorf("expected a no staged changes error, got %v", err)
+	}
+}
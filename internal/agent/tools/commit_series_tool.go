@@ -0,0 +1,245 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CommitSeriesToolDefinition defines the commit_series tool
+var CommitSeriesToolDefinition = ToolDefinition{
+	Name: "commit_series",
+	Description: `Create a series of commits, one per group of files, so logically distinct changes are not
+lumped into one commit. Each group lists the files to commit and the message to use; the commits are
+made in order, each containing exactly its own files as they are in the working tree. Changes to
+other files, staged or not, are left uncommitted. Every file must exist or be deleted, have changes,
+and belong to only one group; all groups are validated before anything is committed. Set
+'lint_messages' to check each message with the lint_commit_message rules first. Returns the hash of
+each commit created.`,
+	InputSchema:           CommitSeriesInputSchema,
+	Function:              CommitSeries,
+	CountsTowardLoopLimit: true,
+}
+
+// CommitGroup is one commit of a series
+type CommitGroup struct {
+	Files   []string `json:"files" jsonschema_required:"true" jsonschema_description:"Files to include in this commit"`
+	Message string   `json:"message" jsonschema_required:"true" jsonschema_description:"Commit message"`
+}
+
+// CommitSeriesInput defines the input parameters for the commit_series tool
+type CommitSeriesInput struct {
+	Commits      []CommitGroup `json:"commits" jsonschema_required:"true" jsonschema_description:"Commits to create, in order"`
+	LintMessages bool          `json:"lint_messages,omitempty" jsonschema_description:"If true, check every message against the lint_commit_message rules before committing anything"`
+}
+
+// CommitSeriesInputSchema is the JSON schema for the commit_series tool
+var CommitSeriesInputSchema = GenerateSchema[CommitSeriesInput]()
+
+// SeriesCommit is a commit created by commit_series
+type SeriesCommit struct {
+	Hash    string   `json:"hash"`
+	Subject string   `json:"subject"`
+	Files   []string `json:"files"`
+}
+
+// CommitSeriesOutput represents the structured output of the commit_series tool
+type CommitSeriesOutput struct {
+	Commits []SeriesCommit `json:"commits"`
+	Message string         `json:"message"`
+}
+
+// CommitSeries implements the commit_series tool functionality
+func CommitSeries(ctx context.Context, input json.RawMessage) (string, error) {
+	seriesInput := CommitSeriesInput{}
+	err := DecodeInput(input, &seriesInput)
+	if err != nil {
+		return "", err
+	}
+
+	if len(seriesInput.Commits) == 0 {
+		return "", fmt.Errorf("at least one commit is required")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultGitTimeout)
+	defer cancel()
+
+	topLevel, err := gitCommand(ctx, "rev-parse", "--show-toplevel").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository: %s, %w", strings.TrimSpace(string(topLevel)), err)
+	}
+	repoRoot, err := filepath.EvalSymlinks(strings.TrimSpace(string(topLevel)))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve repository root: %w", err)
+	}
+	changed, err := changedRepoFiles(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// Validate every group before committing anything
+	groups := make([][]string, len(seriesInput.Commits))
+	owner := map[string]int{}
+	var problems []string
+	for i, group := range seriesInput.Commits {
+		if strings.TrimSpace(group.Message) == "" {
+			problems = append(problems, fmt.Sprintf("commit %d: message is required", i+1))
+		} else if seriesInput.LintMessages {
+			if err := commitLintError(lintCommitMessage(LintCommitMessageInput{Message: group.Message})); err != nil {
+				problems = append(problems, fmt.Sprintf("commit %d: %v", i+1, err))
+			}
+		}
+		if len(group.Files) == 0 {
+			problems = append(problems, fmt.Sprintf("commit %d: no files listed", i+1))
+		}
+		for _, file := range group.Files {
+			relPath, err := repoRelativePath(ctx, repoRoot, file)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("commit %d: %v", i+1, err))
+				continue
+			}
+			if previous, found := owner[relPath]; found {
+				problems = append(problems, fmt.Sprintf("commit %d: %s is already part of commit %d", i+1, file, previous+1))
+				continue
+			}
+			info, statErr := os.Stat(filepath.Join(repoRoot, filepath.FromSlash(relPath)))
+			switch {
+			case statErr == nil && info.IsDir():
+				problems = append(problems, fmt.Sprintf("commit %d: %s is a directory; list its changed files instead", i+1, file))
+				continue
+			case !changed[relPath] && statErr != nil:
+				problems = append(problems, fmt.Sprintf("commit %d: %s does not exist", i+1, file))
+				continue
+			case !changed[relPath]:
+				problems = append(problems, fmt.Sprintf("commit %d: %s has no changes", i+1, file))
+				continue
+			}
+			owner[relPath] = i
+			groups[i] = append(groups[i], relPath)
+		}
+	}
+	if len(problems) > 0 {
+		return "", fmt.Errorf("nothing was committed:\n%s", strings.Join(problems, "\n"))
+	}
+
+	output := CommitSeriesOutput{Commits: []SeriesCommit{}}
+	for i, group := range seriesInput.Commits {
+		hash, err := commitFiles(ctx, repoRoot, groups[i], group.Message)
+		if err != nil {
+			return "", fmt.Errorf("commit %d failed after %d of %d commit(s) were created%s: %w",
+				i+1, len(output.Commits), len(seriesInput.Commits), seriesHashes(output.Commits), err)
+		}
+		subject, _, _ := strings.Cut(strings.TrimSpace(group.Message), "\n")
+		output.Commits = append(output.Commits, SeriesCommit{Hash: hash, Subject: subject, Files: groups[i]})
+	}
+	output.Message = fmt.Sprintf("Created %d commit(s).", len(output.Commits))
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// changedRepoFiles returns the repository-relative paths with staged, unstaged or untracked
+// changes, including deleted files and both sides of renames
+func changedRepoFiles(ctx context.Context) (map[string]bool, error) {
+	out, err := gitCommand(ctx, "status", "--porcelain=v1", "-z", "--untracked-files=all").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %s, %w", strings.TrimSpace(string(out)), err)
+	}
+	changed := map[string]bool{}
+	entries := strings.Split(string(out), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		changed[entry[3:]] = true
+		// Renames and copies are followed by their original path
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+			if i < len(entries) {
+				changed[entries[i]] = true
+			}
+		}
+	}
+	return changed, nil
+}
+
+// repoRelativePath resolves a workspace path to a slash-separated path relative to repoRoot
+func repoRelativePath(ctx context.Context, repoRoot, file string) (string, error) {
+	path, err := ResolvePath(ctx, file)
+	if err != nil {
+		return "", err
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	// Resolve symlinks in the directory only, so a deleted file still maps to its path
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		path = filepath.Join(dir, filepath.Base(path))
+	}
+	relPath, err := filepath.Rel(repoRoot, path)
+	if err != nil || relPath == "." || strings.HasPrefix(relPath, "..") {
+		return "", fmt.Errorf("%s is not inside the repository", file)
+	}
+	return filepath.ToSlash(relPath), nil
+}
+
+// commitFiles stages files and commits exactly those files, leaving anything else that is
+// staged in the index, and returns the new commit's hash
+func commitFiles(ctx context.Context, repoRoot string, files []string, message string) (string, error) {
+	// git add rejects deleted files that are no longer in the index, so remove those instead
+	var present, deleted []string
+	for _, file := range files {
+		if _, err := os.Lstat(filepath.Join(repoRoot, filepath.FromSlash(file))); err == nil {
+			present = append(present, file)
+		} else {
+			deleted = append(deleted, file)
+		}
+	}
+	if len(present) > 0 {
+		add := gitCommand(ctx, append([]string{"add", "-A", "--"}, present...)...)
+		add.Dir = repoRoot
+		if out, err := add.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("git add failed: %s, %w", strings.TrimSpace(string(out)), err)
+		}
+	}
+	if len(deleted) > 0 {
+		remove := gitCommand(ctx, append([]string{"rm", "--cached", "--quiet", "--ignore-unmatch", "--"}, deleted...)...)
+		remove.Dir = repoRoot
+		if out, err := remove.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("git rm failed: %s, %w", strings.TrimSpace(string(out)), err)
+		}
+	}
+
+	commit := gitCommand(ctx, append([]string{"commit", "--only", "-m", message, "--"}, files...)...)
+	commit.Dir = repoRoot
+	if out, err := commit.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git commit failed: %s, %w", strings.TrimSpace(string(out)), err)
+	}
+
+	head, err := gitCommand(ctx, "rev-parse", "HEAD").CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %s, %w", strings.TrimSpace(string(head)), err)
+	}
+	return strings.TrimSpace(string(head)), nil
+}
+
+// seriesHashes lists the hashes of the commits created so far for an error message
+func seriesHashes(commits []SeriesCommit) string {
+	if len(commits) == 0 {
+		return ""
+	}
+	var hashes []string
+	for _, commit := range commits {
+		hashes = append(hashes, commit.Hash)
+	}
+	return " (" + strings.Join(hashes, ", ") + ")"
+}
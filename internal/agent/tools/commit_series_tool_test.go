@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// commitFilesAt lists the files changed by commit in dir
+func commitFilesAt(t *testing.T, dir, commit string) []string {
+	t.Helper()
+	return strings.Fields(runTestGit(t, dir, "diff-tree", "--no-commit-id", "--name-only", "-r", commit))
+}
+
+func TestCommitSeriesCreatesOneCommitPerGroup(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "a.go", "package a\n")
+	writeTestFile(t, dir, "old.txt", "old\n")
+	writeTestFile(t, dir, "README.md", "# readme\n")
+	base := commitTestFiles(t, dir, "initial")
+
+	writeTestFile(t, dir, "a.go", "package a\n\nfunc A() {}\n")
+	writeTestFile(t, dir, "docs/guide.md", "# guide\n")
+	runTestGit(t, dir, "rm", "-q", "old.txt")
+	writeTestFile(t, dir, "README.md", "# readme\n\nmore\n")
+
+	var output CommitSeriesOutput
+	callTool(t, ctx, CommitSeries, CommitSeriesInput{Commits: []CommitGroup{
+		{Files: []string{"a.go", "old.txt"}, Message: "Add A and drop old.txt"},
+		{Files: []string{"docs/guide.md"}, Message: "Add guide\n\nDescribe the basics."},
+	}}, &output)
+
+	if len(output.Commits) != 2 {
+		t.Fatalf("got %d commits, want 2: %+v", len(output.Commits), output)
+	}
+	first, second := output.Commits[0], output.Commits[1]
+	if got := commitFilesAt(t, dir, first.Hash); !reflect.DeepEqual(got, []string{"a.go", "old.txt"}) {
+		t.Errorf("first commit has files %v", got)
+	}
+	if got := commitFilesAt(t, dir, second.Hash); !reflect.DeepEqual(got, []string{"docs/guide.md"}) {
+		t.Errorf("second commit has files %v", got)
+	}
+	if parent := runTestGit(t, dir, "rev-parse", first.Hash+"^"); parent != base {
+		t.Errorf("first commit's parent is %s, want %s", parent, base)
+	}
+	if head := runTestGit(t, dir, "rev-parse", "HEAD"); head != second.Hash {
+		t.Errorf("HEAD is %s, want %s", head, second.Hash)
+	}
+	if second.Subject != "Add guide" {
+		t.Errorf("subject = %q", second.Subject)
+	}
+
+	// Files outside every group stay uncommitted
+	if status := runTestGit(t, dir, "status", "--porcelain"); status != "M README.md" {
+		t.Errorf("status = %q, want only README.md modified", status)
+	}
+}
+
+func TestCommitSeriesLeavesOtherStagedChanges(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "a.txt", "a\n")
+	writeTestFile(t, dir, "b.txt", "b\n")
+	commitTestFiles(t, dir, "initial")
+
+	writeTestFile(t, dir, "a.txt", "a2\n")
+	writeTestFile(t, dir, "b.txt", "b2\n")
+	runTestGit(t, dir, "add", "b.txt")
+
+	var output CommitSeriesOutput
+	callTool(t, ctx, CommitSeries, CommitSeriesInput{Commits: []CommitGroup{{Files: []string{"a.txt"}, Message: "Update a"}}}, &output)
+
+	if got := commitFilesAt(t, dir, output.Commits[0].Hash); !reflect.DeepEqual(got, []string{"a.txt"}) {
+		t.Errorf("commit has files %v, want only a.txt", got)
+	}
+	if staged := runTestGit(t, dir, "diff", "--cached", "--name-only"); staged != "b.txt" {
+		t.Errorf("staged files = %q, want b.txt still staged", staged)
+	}
+}
+
+func TestCommitSeriesValidatesBeforeCommitting(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "a.txt", "a\n")
+	writeTestFile(t, dir, "clean.txt", "clean\n")
+	base := commitTestFiles(t, dir, "initial")
+	writeTestFile(t, dir, "a.txt", "a2\n")
+
+	tests := []struct {
+		name    string
+		input   CommitSeriesInput
+		problem string
+	}{
+		{"no commits", CommitSeriesInput{}, "at least one commit"},
+		{"missing file", CommitSeriesInput{Commits: []CommitGroup{
+			{Files: []string{"a.txt"}, Message: "Update a"},
+			{Files: []string{"missing.txt"}, Message: "Add missing"},
+		}}, "missing.txt does not exist"},
+		{"unchanged file", CommitSeriesInput{Commits: []CommitGroup{{Files: []string{"clean.txt"}, Message: "Touch clean"}}}, "clean.txt has no changes"},
+		{"file in two groups", CommitSeriesInput{Commits: []CommitGroup{
+			{Files: []string{"a.txt"}, Message: "Update a"},
+			{Files: []string{"a.txt"}, Message: "Update a again"},
+		}}, "already part of commit 1"},
+		{"no message", CommitSeriesInput{Commits: []CommitGroup{{Files: []string{"a.txt"}}}}, "message is required"},
+		{"no files", CommitSeriesInput{Commits: []CommitGroup{{Message: "Empty"}}}, "no files listed"},
+		{"lint", CommitSeriesInput{LintMessages: true, Commits: []CommitGroup{{Files: []string{"a.txt"}, Message: "updated a."}}}, "commit 1:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CommitSeries(ctx, mustMarshal(t, tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.problem) {
+				t.Fatalf("got error %v, want one mentioning %q", err, tt.problem)
+			}
+			if head := runTestGit(t, dir, "rev-parse", "HEAD"); head != base {
+				t.Errorf("a commit was created despite the error")
+			}
+		})
+	}
+}
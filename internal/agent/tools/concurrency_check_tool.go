@@ -0,0 +1,344 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConcurrencyCheckToolDefinition defines the concurrency_check tool
+var ConcurrencyCheckToolDefinition = ToolDefinition{
+	Name: "concurrency_check",
+	Description: `Statically check Go code for common goroutine and context misuse.
+Reports goroutines started with 'go func() {...}()' that loop forever with no way to stop (no
+return, break, or receive from a Done channel inside the loop), context.Context values stored in
+struct fields, and cancel functions from context.WithCancel, WithTimeout or WithDeadline that are
+discarded or never deferred (cancel functions handed to other code are assumed to be managed there).
+'path' may be a file or a directory, which is checked recursively. Each issue has a file and line.`,
+	InputSchema: ConcurrencyCheckInputSchema,
+	Function:    ConcurrencyCheck,
+}
+
+// ConcurrencyCheckInput defines the input parameters for the concurrency_check tool
+type ConcurrencyCheckInput struct {
+	Path         string `json:"path,omitempty" jsonschema_description:"Go file or directory to check. Defaults to the current directory."`
+	IncludeTests bool   `json:"include_tests,omitempty" jsonschema_description:"If true, also check _test.go files"`
+}
+
+// ConcurrencyCheckInputSchema is the JSON schema for the concurrency_check tool
+var ConcurrencyCheckInputSchema = GenerateSchema[ConcurrencyCheckInput]()
+
+// ConcurrencyIssue is a potential goroutine or context misuse
+type ConcurrencyIssue struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function,omitempty"`
+	Kind     string `json:"kind"`
+	Issue    string `json:"issue"`
+}
+
+// ConcurrencyCheckOutput represents the structured output of the concurrency_check tool
+type ConcurrencyCheckOutput struct {
+	FilesChecked int                `json:"files_checked"`
+	Issues       []ConcurrencyIssue `json:"issues"`
+}
+
+// cancelFuncConstructors lists the context functions that return a cancel function second
+var cancelFuncConstructors = map[string]bool{
+	"WithCancel":        true,
+	"WithCancelCause":   true,
+	"WithTimeout":       true,
+	"WithTimeoutCause":  true,
+	"WithDeadline":      true,
+	"WithDeadlineCause": true,
+}
+
+// ConcurrencyCheck implements the concurrency_check tool functionality
+func ConcurrencyCheck(ctx context.Context, input json.RawMessage) (string, error) {
+	checkInput := ConcurrencyCheckInput{}
+	err := DecodeInput(input, &checkInput)
+	if err != nil {
+		return "", err
+	}
+
+	root := workspaceDir(ctx)
+	if checkInput.Path != "" {
+		root, err = ResolvePath(ctx, checkInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	files, err := goFilesUnder(ctx, root)
+	if err != nil {
+		return "", err
+	}
+
+	output := ConcurrencyCheckOutput{Issues: []ConcurrencyIssue{}}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") && !checkInput.IncludeTests {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		output.FilesChecked++
+		output.Issues = append(output.Issues, checkConcurrency(fset, file)...)
+	}
+
+	sort.SliceStable(output.Issues, func(i, j int) bool {
+		a, b := output.Issues[i], output.Issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// checkConcurrency reports the concurrency issues in one file
+func checkConcurrency(fset *token.FileSet, file *ast.File) []ConcurrencyIssue {
+	contextName := ""
+	for _, spec := range file.Imports {
+		if path, _ := strconv.Unquote(spec.Path.Value); path == "context" {
+			contextName = "context"
+			if spec.Name != nil {
+				contextName = spec.Name.Name
+			}
+		}
+	}
+
+	var issues []ConcurrencyIssue
+	report := func(node ast.Node, function, kind, message string) {
+		position := fset.Position(node.Pos())
+		issues = append(issues, ConcurrencyIssue{
+			File:     position.Filename,
+			Line:     position.Line,
+			Function: function,
+			Kind:     kind,
+			Issue:    message,
+		})
+	}
+
+	// isContextType reports whether expr is context.Context
+	isContextType := func(expr ast.Expr) bool {
+		sel, ok := expr.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Context" {
+			return false
+		}
+		ident, ok := sel.X.(*ast.Ident)
+		return ok && contextName != "" && ident.Name == contextName
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		if structType, ok := spec.Type.(*ast.StructType); ok {
+			for _, field := range structType.Fields.List {
+				if isContextType(field.Type) {
+					report(field, "", "context_in_struct", fmt.Sprintf(
+						"struct %s stores a context.Context in field %s; pass the context as a parameter instead",
+						spec.Name.Name, fieldDisplayName(field)))
+				}
+			}
+		}
+		return true
+	})
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+		name := funcDecl.Name.Name
+		if receiver := receiverTypeName(funcDecl); receiver != "" {
+			name = receiver + "." + name
+		}
+
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.GoStmt:
+				lit, ok := node.Call.Fun.(*ast.FuncLit)
+				if !ok {
+					return true
+				}
+				if loop := unstoppableLoop(lit.Body); loop != nil {
+					report(node, name, "goroutine_leak", fmt.Sprintf(
+						"goroutine loops forever (line %d) without a return, break or receive from a Done channel; it can never be stopped",
+						fset.Position(loop.Pos()).Line))
+				}
+
+			case *ast.AssignStmt:
+				cancel := cancelFuncIdent(node, contextName)
+				if cancel == nil {
+					return true
+				}
+				if cancel.Name == "_" {
+					report(node, name, "discarded_cancel",
+						"the cancel function is discarded, so the context's resources are never released")
+				} else if !cancelReleased(funcDecl.Body, node, cancel.Name) {
+					report(node, name, "missing_cancel", fmt.Sprintf(
+						"%s is never deferred or handed off; add 'defer %s()' so it runs on every return path", cancel.Name, cancel.Name))
+				}
+			}
+			return true
+		})
+	}
+
+	return issues
+}
+
+// cancelFuncIdent returns the identifier receiving the cancel function when assign calls one of
+// the context.With* constructors, or nil otherwise
+func cancelFuncIdent(assign *ast.AssignStmt, contextName string) *ast.Ident {
+	if contextName == "" || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 {
+		return nil
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !cancelFuncConstructors[sel.Sel.Name] {
+		return nil
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != contextName {
+		return nil
+	}
+	ident, _ := assign.Lhs[1].(*ast.Ident)
+	return ident
+}
+
+// cancelReleased reports whether the cancel function named name, assigned by assign, is deferred
+// or used other than by a plain call (returned, passed, stored), which hands its release elsewhere
+func cancelReleased(body *ast.BlockStmt, assign *ast.AssignStmt, name string) bool {
+	released := false
+	plainCalls := map[*ast.Ident]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.DeferStmt:
+			// Both 'defer cancel()' and a deferred closure calling cancel release it
+			if usesIdent(node.Call, name) {
+				released = true
+			}
+			return false
+		case *ast.CallExpr:
+			if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == name {
+				plainCalls[ident] = true
+			}
+		case *ast.Ident:
+			if node.Name == name && node.Pos() > assign.End() && !plainCalls[node] {
+				released = true
+			}
+		}
+		return !released
+	})
+	return released
+}
+
+// usesIdent reports whether node refers to an identifier called name
+func usesIdent(node ast.Node, name string) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Name == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// unstoppableLoop returns the first condition-less for loop in body, outside nested function
+// literals, that has no return, no break leaving it and no receive from a Done channel
+func unstoppableLoop(body *ast.BlockStmt) *ast.ForStmt {
+	var found *ast.ForStmt
+	ast.Inspect(body, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt:
+			if node.Cond == nil && !loopCanExit(node) {
+				found = node
+				return false
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// loopCanExit reports whether loop contains a return, a goto or labeled break, an unlabeled break
+// that applies to loop itself, a call to os.Exit or panic, or a receive from a Done() channel
+func loopCanExit(loop *ast.ForStmt) bool {
+	canExit := false
+
+	var walk func(n ast.Node, breakTargetsLoop bool)
+	walk = func(n ast.Node, breakTargetsLoop bool) {
+		ast.Inspect(n, func(child ast.Node) bool {
+			if canExit {
+				return false
+			}
+			switch node := child.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				canExit = true
+			case *ast.BranchStmt:
+				if node.Tok == token.GOTO || (node.Tok == token.BREAK && (node.Label != nil || breakTargetsLoop)) {
+					canExit = true
+				}
+			case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+				// An unlabeled break inside these statements leaves them, not the loop
+				if child != n {
+					walk(child, false)
+					return false
+				}
+			case *ast.UnaryExpr:
+				if node.Op == token.ARROW && isDoneCall(node.X) {
+					canExit = true
+				}
+			case *ast.CallExpr:
+				if ident, ok := node.Fun.(*ast.Ident); ok && ident.Name == "panic" {
+					canExit = true
+				}
+				if sel, ok := node.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Exit" {
+					if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "os" {
+						canExit = true
+					}
+				}
+			}
+			return true
+		})
+	}
+	walk(loop.Body, true)
+	return canExit
+}
+
+// isDoneCall reports whether expr is a call such as ctx.Done() or stop.Done()
+func isDoneCall(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Done"
+}
@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+const concurrencyFixture = `package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type Worker struct {
+	ctx   context.Context
+	queue chan string
+}
+
+func (w *Worker) Start() {
+	go func() { // leak
+		for {
+			fmt.Println(<-w.queue)
+		}
+	}()
+}
+
+func (w *Worker) StartWithContext(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case item := <-w.queue:
+				fmt.Println(item)
+			}
+		}
+	}()
+}
+
+func (w *Worker) Drain() {
+	go func() {
+		for {
+			item, ok := <-w.queue
+			if !ok {
+				break
+			}
+			fmt.Println(item)
+		}
+	}()
+}
+
+func (w *Worker) SwitchBreak() {
+	go func() { // switch leak
+		for {
+			switch <-w.queue {
+			case "stop":
+				break
+			}
+		}
+	}()
+}
+
+func Fetch(parent context.Context) {
+	ctx, cancel := context.WithTimeout(parent, time.Second) // not deferred
+	_ = ctx
+	cancel()
+}
+
+func Discard(parent context.Context) {
+	ctx, _ := context.WithCancel(parent) // discarded
+	_ = ctx
+}
+
+func Deferred(parent context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	_ = ctx
+}
+
+func HandedOff(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithDeadline(parent, time.Now())
+	return ctx, cancel
+}
+`
+
+// fixtureLine returns the 1-based line of src that contains marker
+func fixtureLine(t *testing.T, src, marker string) int {
+	t.Helper()
+	for i, line := range strings.Split(src, "\n") {
+		if strings.Contains(line, marker) {
+			return i + 1
+		}
+	}
+	t.Fatalf("marker %q not found", marker)
+	return 0
+}
+
+func TestConcurrencyCheckFlagsMisuse(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "worker.go", concurrencyFixture)
+	writeTestFile(t, dir, "worker_test.go", "package worker\n\nfunc spin() {\n\tgo func() {\n\t\tfor {\n\t\t}\n\t}()\n}\n")
+
+	var output ConcurrencyCheckOutput
+	callTool(t, ctx, ConcurrencyCheck, ConcurrencyCheckInput{}, &output)
+
+	want := []struct {
+		kind     string
+		function string
+		marker   string
+	}{
+		{"context_in_struct", "", "ctx   context.Context"},
+		{"goroutine_leak", "Worker.Start", "// leak"},
+		{"goroutine_leak", "Worker.SwitchBreak", "// switch leak"},
+		{"missing_cancel", "Fetch", "// not deferred"},
+		{"discarded_cancel", "Discard", "// discarded"},
+	}
+	if output.FilesChecked != 1 || len(output.Issues) != len(want) {
+		t.Fatalf("checked %d files, issues = %+v, want %d issues", output.FilesChecked, output.Issues, len(want))
+	}
+	for i, w := range want {
+		issue := output.Issues[i]
+		if issue.Kind != w.kind || issue.Function != w.function || issue.Line != fixtureLine(t, concurrencyFixture, w.marker) {
+			t.Errorf("issue %d = %+v, want %s in %q at the line with %q", i, issue, w.kind, w.function, w.marker)
+		}
+	}
+	if !strings.Contains(output.Issues[1].Issue, "loops forever") {
+		t.Errorf("leak message = %q", output.Issues[1].Issue)
+	}
+}
+
+func TestConcurrencyCheckIncludeTests(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "worker_test.go", "package worker\n\nfunc spin() {\n\tgo func() {\n\t\tfor {\n\t\t}\n\t}()\n}\n")
+
+	var output ConcurrencyCheckOutput
+	callTool(t, ctx, ConcurrencyCheck, ConcurrencyCheckInput{Path: "worker_test.go", IncludeTests: true}, &output)
+	if len(output.Issues) != 1 || output.Issues[0].Kind != "goroutine_leak" || output.Issues[0].Line != 4 {
+		t.Errorf("issues = %+v, want the spinning goroutine in the test file", output.Issues)
+	}
+}
+
+func TestConcurrencyCheckAliasedContext(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "alias.go", "package p\n\nimport stdctx \"context\"\n\nfunc f(parent stdctx.Context) {\n\tctx, cancel := stdctx.WithCancel(parent)\n\t_ = ctx\n\tcancel()\n}\n")
+
+	var output ConcurrencyCheckOutput
+	callTool(t, ctx, ConcurrencyCheck, ConcurrencyCheckInput{Path: "alias.go"}, &output)
+	if len(output.Issues) != 1 || output.Issues[0].Kind != "missing_cancel" || output.Issues[0].Line != 6 {
+		t.Errorf("issues = %+v, want the cancel from the aliased package flagged", output.Issues)
+	}
+}
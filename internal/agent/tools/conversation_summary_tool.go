@@ -0,0 +1,197 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ConversationSummaryToolDefinition defines the conversation_summary tool
+var ConversationSummaryToolDefinition = ToolDefinition{
+	Name: "conversation_summary",
+	Description: `Summarize the current session to regain orientation without re-reading the history.
+Reports the number of user turns, tool calls per tool, the approximate token usage (the size of the
+most recent request and the total output so far), the files read and edited by successful tool
+calls, and the last few user requests.`,
+	InputSchema: ConversationSummaryInputSchema,
+	Function:    ConversationSummary,
+}
+
+// ConversationSummaryInput defines the input parameters for the conversation_summary tool
+type ConversationSummaryInput struct {
+	RecentRequests int `json:"recent_requests,omitempty" jsonschema_description:"Number of most recent user requests to include. Defaults to 3."`
+}
+
+// ConversationSummaryInputSchema is the JSON schema for the conversation_summary tool
+var ConversationSummaryInputSchema = GenerateSchema[ConversationSummaryInput]()
+
+// ConversationSummaryOutput represents the structured output of the conversation_summary tool
+type ConversationSummaryOutput struct {
+	Turns          int            `json:"turns"`
+	Duration       string         `json:"duration"`
+	ToolCalls      int            `json:"tool_calls"`
+	ToolCallCounts map[string]int `json:"tool_call_counts"`
+	ContextTokens  int64          `json:"context_tokens"`
+	OutputTokens   int64          `json:"output_tokens"`
+	FilesRead      []string       `json:"files_read"`
+	FilesEdited    []string       `json:"files_edited"`
+	RecentRequests []string       `json:"recent_requests"`
+}
+
+// maxSummarizedRequest caps the length of each user request in the summary
+const maxSummarizedRequest = 500
+
+// sessionState is what the agent has recorded about the current session
+type sessionState struct {
+	started        time.Time
+	requests       []string
+	toolCallCounts map[string]int
+	toolCalls      int
+	contextTokens  int64
+	outputTokens   int64
+	filesRead      map[string]bool
+	filesEdited    map[string]bool
+}
+
+var (
+	session      = newSessionState()
+	sessionMutex sync.Mutex
+)
+
+// newSessionState returns an empty session record starting now
+func newSessionState() sessionState {
+	return sessionState{
+		started:        time.Now(),
+		toolCallCounts: map[string]int{},
+		filesRead:      map[string]bool{},
+		filesEdited:    map[string]bool{},
+	}
+}
+
+// ResetConversationState starts recording a new session
+func ResetConversationState() {
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+	session = newSessionState()
+}
+
+// RecordUserRequest records a user turn and its text
+func RecordUserRequest(text string) {
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+	session.requests = append(session.requests, text)
+}
+
+// RecordTokenUsage records the token usage of one model response: inputTokens is the size of
+// the request, so the latest value approximates the current context
+func RecordTokenUsage(inputTokens, outputTokens int64) {
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+	session.contextTokens = inputTokens
+	session.outputTokens += outputTokens
+}
+
+// RecordToolCall records a successful tool call. The file named by its 'path' input (or
+// 'destination', for tools that write one) counts as edited when the call may modify the
+// workspace and as read otherwise; paths that aren't files after the call are ignored.
+func RecordToolCall(ctx context.Context, name string, input json.RawMessage) {
+	var fields struct {
+		Path        string `json:"path"`
+		Destination string `json:"destination"`
+	}
+	mutating := IsMutatingToolCall(name, input)
+
+	// The call already succeeded, so input that doesn't decode (e.g. a non-string 'path') just
+	// names no files; the call itself is still counted
+	var candidates []string
+	if err := DecodeInput(input, &fields); err == nil {
+		candidates = append(candidates, fields.Path)
+		if mutating {
+			candidates = append(candidates, fields.Destination)
+		}
+	}
+
+	var files []string
+	for _, value := range candidates {
+		if value == "" {
+			continue
+		}
+		path, err := ResolvePath(ctx, value)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			files = append(files, path)
+		}
+	}
+
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+	session.toolCalls++
+	session.toolCallCounts[name]++
+	for _, file := range files {
+		if mutating {
+			session.filesEdited[file] = true
+		} else {
+			session.filesRead[file] = true
+		}
+	}
+}
+
+// ConversationSummary implements the conversation_summary tool functionality
+func ConversationSummary(ctx context.Context, input json.RawMessage) (string, error) {
+	summaryInput := ConversationSummaryInput{}
+	err := DecodeInput(input, &summaryInput)
+	if err != nil {
+		return "", err
+	}
+
+	recent := summaryInput.RecentRequests
+	if recent <= 0 {
+		recent = 3
+	}
+
+	sessionMutex.Lock()
+	output := ConversationSummaryOutput{
+		Turns:          len(session.requests),
+		Duration:       time.Since(session.started).Round(time.Second).String(),
+		ToolCalls:      session.toolCalls,
+		ToolCallCounts: map[string]int{},
+		ContextTokens:  session.contextTokens,
+		OutputTokens:   session.outputTokens,
+		FilesRead:      sortedKeys(session.filesRead),
+		FilesEdited:    sortedKeys(session.filesEdited),
+		RecentRequests: []string{},
+	}
+	for name, count := range session.toolCallCounts {
+		output.ToolCallCounts[name] = count
+	}
+	for _, request := range session.requests[max(0, len(session.requests)-recent):] {
+		if runes := []rune(request); len(runes) > maxSummarizedRequest {
+			request = string(runes[:maxSummarizedRequest]) + "..."
+		}
+		output.RecentRequests = append(output.RecentRequests, request)
+	}
+	sessionMutex.Unlock()
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// sortedKeys returns the keys of set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
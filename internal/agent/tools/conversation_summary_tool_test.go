@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// callRecordedTool runs a tool and, like the agent, records the call only when it succeeds
+func callRecordedTool(t *testing.T, ctx context.Context, name string, fn func(context.Context, json.RawMessage) (string, error), v interface{}) {
+	t.Helper()
+	input := mustMarshal(t, v)
+	if _, err := fn(ctx, input); err == nil {
+		RecordToolCall(ctx, name, input)
+	}
+}
+
+func TestConversationSummaryTracksFiles(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	ResetConversationState()
+	t.Cleanup(ResetConversationState)
+	writeTestFile(t, dir, "main.go", "package main\n")
+	writeTestFile(t, dir, "README.md", "# app\n")
+
+	RecordUserRequest("add a config package")
+	callRecordedTool(t, ctx, "file_reader", ReadFileContent, FileReaderInput{Path: "README.md"})
+	callRecordedTool(t, ctx, "file_editor", EditFileContent, FileEditorInput{Path: "config/config.go", Mode: "create", Content: "package config\n"})
+	callRecordedTool(t, ctx, "file_editor", EditFileContent, FileEditorInput{Path: "main.go", Mode: "append", Content: "\nfunc main() {}\n"})
+	callRecordedTool(t, ctx, "file_editor", EditFileContent, FileEditorInput{Path: "main.go", Mode: "replace", OldStr: "func main() {}", NewStr: "func main() { run() }"})
+	// A failed edit isn't recorded
+	callRecordedTool(t, ctx, "file_editor", EditFileContent, FileEditorInput{Path: "missing.go", Mode: "replace", OldStr: "a", NewStr: "b"})
+	RecordTokenUsage(1200, 80)
+	RecordTokenUsage(1500, 40)
+
+	var output ConversationSummaryOutput
+	callTool(t, ctx, ConversationSummary, ConversationSummaryInput{}, &output)
+
+	wantEdited := []string{filepath.Join(dir, "config", "config.go"), filepath.Join(dir, "main.go")}
+	if !reflect.DeepEqual(output.FilesEdited, wantEdited) {
+		t.Errorf("files edited = %v, want %v", output.FilesEdited, wantEdited)
+	}
+	if !reflect.DeepEqual(output.FilesRead, []string{filepath.Join(dir, "README.md")}) {
+		t.Errorf("files read = %v, want README.md", output.FilesRead)
+	}
+	if output.ToolCalls != 4 || output.ToolCallCounts["file_editor"] != 3 || output.ToolCallCounts["file_reader"] != 1 {
+		t.Errorf("tool calls = %d %v", output.ToolCalls, output.ToolCallCounts)
+	}
+	if output.Turns != 1 || output.ContextTokens != 1500 || output.OutputTokens != 120 {
+		t.Errorf("turns %d, context %d, output %d tokens", output.Turns, output.ContextTokens, output.OutputTokens)
+	}
+}
+
+func TestConversationSummaryRecentRequests(t *testing.T) {
+	ResetConversationState()
+	t.Cleanup(ResetConversationState)
+	for _, request := range []string{"first", "second", "third", "fourth", strings.Repeat("x", maxSummarizedRequest+10)} {
+		RecordUserRequest(request)
+	}
+
+	var output ConversationSummaryOutput
+	callTool(t, context.Background(), ConversationSummary, ConversationSummaryInput{RecentRequests: 2}, &output)
+
+	if output.Turns != 5 || len(output.RecentRequests) != 2 || output.RecentRequests[0] != "fourth" {
+		t.Fatalf("got %d turns and recent requests %q", output.Turns, output.RecentRequests)
+	}
+	if last := output.RecentRequests[1]; len(last) != maxSummarizedRequest+3 || !strings.HasSuffix(last, "...") {
+		t.Errorf("long request was not truncated: %d characters", len(last))
+	}
+}
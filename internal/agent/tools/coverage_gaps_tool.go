@@ -0,0 +1,297 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CoverageGapsToolDefinition defines the coverage_gaps tool
+var CoverageGapsToolDefinition = ToolDefinition{
+	Name: "coverage_gaps",
+	Description: `Find the functions and files with the least test coverage.
+Runs 'go test -coverprofile' (or reads an existing profile) and reports every function whose
+statement coverage is below 'threshold' percent (default 50), worst first, along with per-file
+coverage and the total. Use it to decide where new tests are most needed.`,
+	InputSchema:           CoverageGapsInputSchema,
+	Function:              CoverageGaps,
+	CountsTowardLoopLimit: true,
+}
+
+// CoverageGapsInput defines the input parameters for the coverage_gaps tool
+type CoverageGapsInput struct {
+	Path       string  `json:"path,omitempty" jsonschema_description:"Package path or pattern to test. Defaults to './...'."`
+	Profile    string  `json:"profile,omitempty" jsonschema_description:"Existing coverage profile to analyze instead of running the tests"`
+	Threshold  float64 `json:"threshold,omitempty" jsonschema_description:"Report functions and files below this coverage percentage (default 50)"`
+	Limit      int     `json:"limit,omitempty" jsonschema_description:"Maximum number of functions to report (default 50)"`
+	WorkingDir string  `json:"working_dir,omitempty" jsonschema_description:"Working directory (defaults to current directory if empty)"`
+}
+
+// CoverageGapsInputSchema is the JSON schema for the coverage_gaps tool
+var CoverageGapsInputSchema = GenerateSchema[CoverageGapsInput]()
+
+// FunctionCoverage is the statement coverage of a single function
+type FunctionCoverage struct {
+	File     string  `json:"file"`
+	Line     int     `json:"line"`
+	Function string  `json:"function"`
+	Coverage float64 `json:"coverage"`
+}
+
+// FileCoverage is the statement coverage of a single file
+type FileCoverage struct {
+	File       string  `json:"file"`
+	Statements int     `json:"statements"`
+	Covered    int     `json:"covered"`
+	Coverage   float64 `json:"coverage"`
+}
+
+// CoverageGapsOutput represents the structured output of the coverage_gaps tool
+type CoverageGapsOutput struct {
+	TotalCoverage float64            `json:"total_coverage"`
+	Threshold     float64            `json:"threshold"`
+	TestsPassed   bool               `json:"tests_passed"`
+	Functions     []FunctionCoverage `json:"functions"`
+	Files         []FileCoverage     `json:"files"`
+	Truncated     bool               `json:"truncated,omitempty"`
+	TestOutput    string             `json:"test_output,omitempty"`
+}
+
+// CoverageGaps implements the coverage_gaps tool functionality
+func CoverageGaps(ctx context.Context, input json.RawMessage) (string, error) {
+	gapsInput := CoverageGapsInput{}
+	if err := DecodeInput(input, &gapsInput); err != nil {
+		return "", err
+	}
+
+	threshold := gapsInput.Threshold
+	if threshold <= 0 {
+		threshold = 50
+	}
+	limit := gapsInput.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	output := CoverageGapsOutput{Threshold: threshold, TestsPassed: true}
+
+	profile := gapsInput.Profile
+	if profile != "" {
+		var err error
+		profile, err = ResolvePath(ctx, profile)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		tmp, err := os.CreateTemp("", "coverage-*.out")
+		if err != nil {
+			return "", fmt.Errorf("failed to create coverage profile: %w", err)
+		}
+		tmp.Close()
+		defer os.Remove(tmp.Name())
+		profile = tmp.Name()
+
+		path := gapsInput.Path
+		if path == "" {
+			path = "./..."
+		}
+		result, err := RunGoCommand(ctx, "test", path, []string{"-coverprofile", profile}, gapsInput.WorkingDir)
+		if err != nil {
+			return "", err
+		}
+		if !result.Success {
+			// Failing tests still leave a profile for the packages that ran
+			output.TestsPassed = false
+			output.TestOutput = tailLines(result.Stdout+result.Stderr, 40)
+		}
+	}
+
+	files, err := parseCoverageProfile(profile)
+	if err != nil {
+		if !output.TestsPassed {
+			return "", fmt.Errorf("go test failed before writing a coverage profile:\n%s", output.TestOutput)
+		}
+		return "", err
+	}
+
+	result, err := RunGoCommand(ctx, "tool", "", []string{"cover", "-func=" + profile}, gapsInput.WorkingDir)
+	if err != nil {
+		return "", err
+	}
+	if !result.Success {
+		return "", fmt.Errorf("go tool cover failed: %s", strings.TrimSpace(result.Stderr))
+	}
+	functions, total := parseCoverFuncOutput(result.Stdout)
+	output.TotalCoverage = total
+
+	output.Functions = []FunctionCoverage{}
+	for _, fn := range functions {
+		if fn.Coverage < threshold {
+			output.Functions = append(output.Functions, fn)
+		}
+	}
+	sort.SliceStable(output.Functions, func(i, j int) bool {
+		a, b := output.Functions[i], output.Functions[j]
+		if a.Coverage != b.Coverage {
+			return a.Coverage < b.Coverage
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	if len(output.Functions) > limit {
+		output.Functions = output.Functions[:limit]
+		output.Truncated = true
+	}
+
+	output.Files = []FileCoverage{}
+	for _, file := range files {
+		if file.Coverage < threshold {
+			output.Files = append(output.Files, file)
+		}
+	}
+	sort.SliceStable(output.Files, func(i, j int) bool {
+		if output.Files[i].Coverage != output.Files[j].Coverage {
+			return output.Files[i].Coverage < output.Files[j].Coverage
+		}
+		return output.Files[i].File < output.Files[j].File
+	})
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// parseCoverageProfile reads a coverage profile and totals the statements of each file.
+// Blocks listed more than once, as happens with -coverpkg, count as covered if any run hit them.
+func parseCoverageProfile(path string) ([]FileCoverage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read coverage profile '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	type block struct {
+		statements int
+		covered    bool
+	}
+	blocks := map[string]map[string]*block{}
+
+	scanner := bufio.NewScanner(f)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		// Each line is "file:startLine.startCol,endLine.endCol numStatements count"
+		colon := strings.LastIndex(line, ":")
+		fields := strings.Fields(line[colon+1:])
+		if colon < 0 || len(fields) != 3 {
+			return nil, fmt.Errorf("invalid coverage profile line %d: %s", lineNumber, line)
+		}
+		statements, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid statement count on coverage profile line %d: %s", lineNumber, line)
+		}
+		count, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hit count on coverage profile line %d: %s", lineNumber, line)
+		}
+
+		file := line[:colon]
+		if blocks[file] == nil {
+			blocks[file] = map[string]*block{}
+		}
+		b, ok := blocks[file][fields[0]]
+		if !ok {
+			b = &block{statements: statements}
+			blocks[file][fields[0]] = b
+		}
+		b.covered = b.covered || count > 0
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read coverage profile '%s': %w", path, err)
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("coverage profile '%s' contains no coverage data", path)
+	}
+
+	var files []FileCoverage
+	for file, fileBlocks := range blocks {
+		coverage := FileCoverage{File: file}
+		for _, b := range fileBlocks {
+			coverage.Statements += b.statements
+			if b.covered {
+				coverage.Covered += b.statements
+			}
+		}
+		if coverage.Statements > 0 {
+			coverage.Coverage = roundPercent(float64(coverage.Covered) / float64(coverage.Statements) * 100)
+		}
+		files = append(files, coverage)
+	}
+	return files, nil
+}
+
+// parseCoverFuncOutput parses 'go tool cover -func' output into per-function coverage and the total
+func parseCoverFuncOutput(stdout string) ([]FunctionCoverage, float64) {
+	var functions []FunctionCoverage
+	var total float64
+
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(fields[len(fields)-1], "%"), 64)
+		if err != nil {
+			continue
+		}
+		if fields[0] == "total:" {
+			total = percent
+			continue
+		}
+
+		// The location is "file:line:"
+		location := strings.TrimSuffix(fields[0], ":")
+		file, lineText := location, ""
+		if idx := strings.LastIndex(location, ":"); idx >= 0 {
+			file, lineText = location[:idx], location[idx+1:]
+		}
+		lineNumber, _ := strconv.Atoi(lineText)
+		functions = append(functions, FunctionCoverage{
+			File:     filepath.ToSlash(file),
+			Line:     lineNumber,
+			Function: fields[1],
+			Coverage: percent,
+		})
+	}
+	return functions, total
+}
+
+// roundPercent rounds a percentage to one decimal place, as 'go tool cover' reports it
+func roundPercent(percent float64) float64 {
+	return float64(int64(percent*10+0.5)) / 10
+}
+
+// tailLines returns at most the last n lines of text
+func tailLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const cannedCoverProfile = `mode: set
+example.com/calc/calc.go:3.24,5.2 1 1
+example.com/calc/calc.go:7.24,9.2 1 0
+example.com/calc/calc.go:11.26,12.12 1 1
+example.com/calc/calc.go:12.12,14.3 1 0
+example.com/calc/calc.go:15.2,15.14 1 1
+example.com/calc/calc.go:7.24,9.2 1 1
+example.com/calc/format.go:3.30,5.2 2 0
+`
+
+const cannedCoverFuncOutput = `example.com/calc/calc.go:3:	Add		100.0%
+example.com/calc/calc.go:7:	Sub		100.0%
+example.com/calc/calc.go:11:	Divide		66.7%
+example.com/calc/format.go:3:	Format		0.0%
+total:				(statements)	57.1%
+`
+
+func TestParseCoverageProfile(t *testing.T) {
+	_, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "cover.out", cannedCoverProfile)
+
+	files, err := parseCoverageProfile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]FileCoverage{}
+	for _, file := range files {
+		got[file.File] = file
+	}
+	// The repeated Sub block is covered by its second listing
+	want := map[string]FileCoverage{
+		"example.com/calc/calc.go":   {File: "example.com/calc/calc.go", Statements: 5, Covered: 4, Coverage: 80},
+		"example.com/calc/format.go": {File: "example.com/calc/format.go", Statements: 2, Covered: 0, Coverage: 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("files = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseCoverageProfileErrors(t *testing.T) {
+	_, dir := newTestWorkspace(t)
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"empty", "mode: set\n", "contains no coverage data"},
+		{"malformed", "mode: set\nnot a profile line\n", "invalid coverage profile line 2"},
+		{"bad count", "mode: set\na.go:1.1,2.2 1 x\n", "invalid hit count"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, dir, tt.name+".out", tt.content)
+			if _, err := parseCoverageProfile(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCoverFuncOutput(t *testing.T) {
+	functions, total := parseCoverFuncOutput(cannedCoverFuncOutput)
+	if total != 57.1 {
+		t.Errorf("total = %v, want 57.1", total)
+	}
+	want := FunctionCoverage{File: "example.com/calc/calc.go", Line: 11, Function: "Divide", Coverage: 66.7}
+	if len(functions) != 4 || functions[2] != want {
+		t.Errorf("functions = %+v", functions)
+	}
+}
+
+func TestCoverageGapsFlagsLowCoverage(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/calc")
+	writeTestFile(t, dir, "calc.go", `package calc
+
+func Add(a, b int) int {
+	return a + b
+}
+
+func Divide(a, b int) int {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
+
+func Unused(a int) int {
+	a++
+	return a * 2
+}
+`)
+	writeTestFile(t, dir, "calc_test.go", `package calc
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	if Add(1, 2) != 3 || Divide(4, 2) != 2 {
+		t.Fatal("wrong result")
+	}
+}
+`)
+
+	var output CoverageGapsOutput
+	callTool(t, ctx, CoverageGaps, CoverageGapsInput{Threshold: 80, WorkingDir: dir}, &output)
+
+	if !output.TestsPassed {
+		t.Fatalf("tests failed: %s", output.TestOutput)
+	}
+	var names []string
+	for _, fn := range output.Functions {
+		names = append(names, fn.Function)
+	}
+	// Unused is worst at 0%, then Divide with its b == 0 branch untested; Add is fully covered
+	if want := []string{"Unused", "Divide"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("flagged functions = %v, want %v", names, want)
+	}
+	if len(output.Files) != 1 || output.Files[0].File != "example.com/calc/calc.go" {
+		t.Errorf("files = %+v", output.Files)
+	}
+}
@@ -0,0 +1,387 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DeadCodeToolDefinition defines the find_dead_code tool
+var DeadCodeToolDefinition = ToolDefinition{
+	Name: "find_dead_code",
+	Description: `Find top-level functions, types, variables, and constants that nothing in the module references.
+Uses staticcheck's U1000 check when staticcheck is installed; otherwise the module is type-checked
+with go/types and every package-level declaration without a reference (including from tests) is reported.
+Exported symbols are flagged with 'exported: true' because they may still be used outside the module.
+Methods are not reported since they may be needed to satisfy interfaces.`,
+	InputSchema:           DeadCodeInputSchema,
+	Function:              FindDeadCode,
+	CountsTowardLoopLimit: true,
+}
+
+// DeadCodeInput defines the input parameters for the find_dead_code tool
+type DeadCodeInput struct {
+	Path            string `json:"path,omitempty" jsonschema_description:"Module root directory. Defaults to the current directory."`
+	IncludeExported bool   `json:"include_exported,omitempty" jsonschema_description:"Also report unreferenced exported symbols (built-in analysis only)"`
+}
+
+// DeadCodeInputSchema is the JSON schema for the find_dead_code tool
+var DeadCodeInputSchema = GenerateSchema[DeadCodeInput]()
+
+// DeadCodeFinding describes one unreferenced declaration
+type DeadCodeFinding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Symbol   string `json:"symbol"`
+	Kind     string `json:"kind,omitempty"`
+	Exported bool   `json:"exported"`
+}
+
+// DeadCodeOutput represents the structured output of the find_dead_code tool
+type DeadCodeOutput struct {
+	Engine   string            `json:"engine"`
+	Findings []DeadCodeFinding `json:"findings"`
+	Note     string            `json:"note,omitempty"`
+}
+
+// FindDeadCode implements the find_dead_code tool functionality
+func FindDeadCode(ctx context.Context, input json.RawMessage) (string, error) {
+	deadInput := DeadCodeInput{}
+	err := json.Unmarshal(input, &deadInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	root := workspaceDir(ctx)
+	if deadInput.Path != "" {
+		root, err = ResolvePath(ctx, deadInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var output DeadCodeOutput
+	if _, lookErr := exec.LookPath("staticcheck"); lookErr == nil {
+		output, err = staticcheckDeadCode(ctx, root)
+	} else {
+		output, err = typesDeadCode(ctx, root, deadInput.IncludeExported)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// staticcheckLinePattern matches a U1000 diagnostic such as "a.go:12:6: func foo is unused (U1000)"
+var staticcheckLinePattern = regexp.MustCompile(`^(.+?):(\d+):\d+: (\w+) (\S+) is unused \(U1000\)$`)
+
+// staticcheckDeadCode runs staticcheck's unused-code check and parses its diagnostics
+func staticcheckDeadCode(ctx context.Context, root string) (DeadCodeOutput, error) {
+	cmd := exec.CommandContext(ctx, "staticcheck", "-checks", "U1000", "./...")
+	cmd.Dir = root
+	// staticcheck exits non-zero when it reports findings
+	stdout, _ := cmd.Output()
+
+	output := DeadCodeOutput{Engine: "staticcheck", Findings: []DeadCodeFinding{}}
+	scanner := bufio.NewScanner(strings.NewReader(string(stdout)))
+	for scanner.Scan() {
+		matches := staticcheckLinePattern.FindStringSubmatch(scanner.Text())
+		if matches == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(matches[2])
+		symbol := matches[4]
+		output.Findings = append(output.Findings, DeadCodeFinding{
+			File:     matches[1],
+			Line:     line,
+			Symbol:   symbol,
+			Kind:     matches[3],
+			Exported: ast.IsExported(symbol[strings.LastIndex(symbol, ".")+1:]),
+		})
+	}
+	return output, nil
+}
+
+// deadCodePackage is a group of files type-checked together
+type deadCodePackage struct {
+	importPath string
+	files      []*ast.File
+	testFiles  map[*ast.File]bool
+}
+
+// typesDeadCode type-checks every package under root and reports package-level
+// declarations that no package in the module refers to
+func typesDeadCode(ctx context.Context, root string, includeExported bool) (DeadCodeOutput, error) {
+	modulePath, err := readModulePath(root)
+	if err != nil {
+		return DeadCodeOutput{}, err
+	}
+
+	fset := token.NewFileSet()
+	packages, err := parseModulePackages(ctx, fset, root, modulePath)
+	if err != nil {
+		return DeadCodeOutput{}, err
+	}
+
+	// Objects are compared by package path and name since each package is checked separately
+	used := map[string]bool{}
+	type declaration struct {
+		key     string
+		name    string
+		kind    string
+		pos     token.Pos
+		isTest  bool
+		pkgName string
+	}
+	var declarations []declaration
+
+	moduleImports := newModuleImporter(fset, packages)
+	for _, pkg := range packages {
+		info := &types.Info{Uses: map[*ast.Ident]types.Object{}}
+		config := types.Config{Importer: moduleImports, Error: func(error) {}}
+		checked, _ := config.Check(pkg.importPath, fset, pkg.files, info)
+
+		for _, obj := range info.Uses {
+			if obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope() {
+				used[obj.Pkg().Path()+"."+obj.Name()] = true
+			}
+		}
+
+		if checked == nil {
+			continue
+		}
+		for _, file := range pkg.files {
+			for _, decl := range file.Decls {
+				for _, name := range declaredNames(decl) {
+					declarations = append(declarations, declaration{
+						key:     strings.TrimSuffix(pkg.importPath, "_test") + "." + name.ident.Name,
+						name:    name.ident.Name,
+						kind:    name.kind,
+						pos:     name.ident.Pos(),
+						isTest:  pkg.testFiles[file],
+						pkgName: file.Name.Name,
+					})
+				}
+			}
+		}
+	}
+
+	output := DeadCodeOutput{
+		Engine:   "go/types",
+		Findings: []DeadCodeFinding{},
+		Note:     "Install staticcheck for more precise results. Exported symbols may be used outside this module.",
+	}
+	for _, decl := range declarations {
+		exported := ast.IsExported(decl.name)
+		if used[decl.key] || decl.name == "_" || decl.isTest || (exported && !includeExported) {
+			continue
+		}
+		if decl.kind == "func" && (decl.name == "init" || (decl.name == "main" && decl.pkgName == "main")) {
+			continue
+		}
+
+		position := fset.Position(decl.pos)
+		file := position.Filename
+		if rel, err := filepath.Rel(root, file); err == nil {
+			file = rel
+		}
+		output.Findings = append(output.Findings, DeadCodeFinding{
+			File:     filepath.ToSlash(file),
+			Line:     position.Line,
+			Symbol:   decl.name,
+			Kind:     decl.kind,
+			Exported: exported,
+		})
+	}
+	sort.Slice(output.Findings, func(i, j int) bool {
+		if output.Findings[i].File != output.Findings[j].File {
+			return output.Findings[i].File < output.Findings[j].File
+		}
+		return output.Findings[i].Line < output.Findings[j].Line
+	})
+
+	return output, nil
+}
+
+// moduleImporter type-checks packages of the module from the parsed sources on demand,
+// deferring to the source importer for the standard library and dependencies
+type moduleImporter struct {
+	fset     *token.FileSet
+	sources  map[string][]*ast.File
+	checked  map[string]*types.Package
+	fallback types.Importer
+}
+
+// newModuleImporter creates a moduleImporter for the non-test files of packages
+func newModuleImporter(fset *token.FileSet, packages []*deadCodePackage) *moduleImporter {
+	m := &moduleImporter{
+		fset:     fset,
+		sources:  map[string][]*ast.File{},
+		checked:  map[string]*types.Package{},
+		fallback: importer.ForCompiler(fset, "source", nil),
+	}
+	for _, pkg := range packages {
+		for _, file := range pkg.files {
+			if !pkg.testFiles[file] {
+				m.sources[pkg.importPath] = append(m.sources[pkg.importPath], file)
+			}
+		}
+	}
+	return m
+}
+
+// Import implements types.Importer
+func (m *moduleImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := m.checked[path]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("import cycle through %s", path)
+		}
+		return pkg, nil
+	}
+
+	files, ok := m.sources[path]
+	if !ok {
+		return m.fallback.Import(path)
+	}
+
+	m.checked[path] = nil
+	config := types.Config{Importer: m, Error: func(error) {}}
+	pkg, _ := config.Check(path, m.fset, files, nil)
+	if pkg == nil {
+		delete(m.checked, path)
+		return nil, fmt.Errorf("failed to type-check %s", path)
+	}
+	m.checked[path] = pkg
+	return pkg, nil
+}
+
+// declaredName is a package-level identifier and the kind of declaration introducing it
+type declaredName struct {
+	ident *ast.Ident
+	kind  string
+}
+
+// declaredNames returns the package-level names introduced by decl, excluding methods
+func declaredNames(decl ast.Decl) []declaredName {
+	var names []declaredName
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil {
+			names = append(names, declaredName{ident: d.Name, kind: "func"})
+		}
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, declaredName{ident: s.Name, kind: "type"})
+			case *ast.ValueSpec:
+				kind := "var"
+				if d.Tok == token.CONST {
+					kind = "const"
+				}
+				for _, name := range s.Names {
+					names = append(names, declaredName{ident: name, kind: kind})
+				}
+			}
+		}
+	}
+	return names
+}
+
+// readModulePath returns the module path declared in root/go.mod
+func readModulePath(root string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.mod in %s: %w", root, err)
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), nil
+		}
+	}
+	return "", fmt.Errorf("no module directive found in %s", filepath.Join(root, "go.mod"))
+}
+
+// parseModulePackages parses every package under root, grouping external test files
+// (package foo_test) separately from the package they test
+func parseModulePackages(ctx context.Context, fset *token.FileSet, root, modulePath string) ([]*deadCodePackage, error) {
+	ignoreRules := LoadIgnoreRules(workspaceDir(ctx))
+	var packages []*deadCodePackage
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		name := info.Name()
+		if relPath != "." && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor" || ignoreRules.IgnoredPath(path, true)) {
+			return filepath.SkipDir
+		}
+
+		importPath := modulePath
+		if relPath != "." {
+			importPath = modulePath + "/" + filepath.ToSlash(relPath)
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		groups := map[string]*deadCodePackage{}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+				continue
+			}
+			file, err := parser.ParseFile(fset, filepath.Join(path, entry.Name()), nil, 0)
+			if err != nil {
+				continue
+			}
+
+			groupPath := importPath
+			if strings.HasSuffix(file.Name.Name, "_test") {
+				groupPath = importPath + "_test"
+			}
+			group, ok := groups[groupPath]
+			if !ok {
+				group = &deadCodePackage{importPath: groupPath, testFiles: map[*ast.File]bool{}}
+				groups[groupPath] = group
+				packages = append(packages, group)
+			}
+			group.files = append(group.files, file)
+			if strings.HasSuffix(entry.Name(), "_test.go") {
+				group.testFiles[file] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	return packages, nil
+}
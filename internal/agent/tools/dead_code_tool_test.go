@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"testing"
+)
+
+// writeDeadCodeFixture writes a module with one clearly unused unexported function
+func writeDeadCodeFixture(t *testing.T, dir string) {
+	t.Helper()
+	testGoModule(t, dir, "example.com/dead")
+	writeTestFile(t, dir, "main.go", `package main
+
+import "example.com/dead/util"
+
+func main() {
+	println(util.Double(helper()))
+}
+
+func helper() int { return 1 }
+
+func unusedHelper() int { return 2 }
+
+type server struct{}
+
+func (s server) unusedMethod() {}
+`)
+	writeTestFile(t, dir, "util/util.go", `package util
+
+func Double(n int) int { return n * 2 }
+
+func Triple(n int) int { return n * 3 }
+
+func onlyTested() bool { return true }
+`)
+	writeTestFile(t, dir, "util/util_test.go", `package util
+
+import "testing"
+
+func TestOnlyTested(t *testing.T) {
+	if !onlyTested() {
+		t.Fatal("unexpected")
+	}
+}
+`)
+}
+
+func TestTypesDeadCodeFlagsUnusedFunction(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeDeadCodeFixture(t, dir)
+
+	output, err := typesDeadCode(ctx, dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// unusedMethod is never reported since methods may satisfy interfaces
+	want := []DeadCodeFinding{
+		{File: "main.go", Line: 11, Symbol: "unusedHelper", Kind: "func"},
+	}
+	if len(output.Findings) != len(want) {
+		t.Fatalf("findings = %+v, want %+v", output.Findings, want)
+	}
+	for i := range want {
+		if output.Findings[i] != want[i] {
+			t.Errorf("finding %d = %+v, want %+v", i, output.Findings[i], want[i])
+		}
+	}
+}
+
+func TestTypesDeadCodeIncludeExported(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeDeadCodeFixture(t, dir)
+
+	output, err := typesDeadCode(ctx, dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var triple *DeadCodeFinding
+	for i := range output.Findings {
+		switch output.Findings[i].Symbol {
+		case "Triple":
+			triple = &output.Findings[i]
+		case "Double", "onlyTested", "helper", "main":
+			t.Errorf("%s is referenced and shouldn't be reported", output.Findings[i].Symbol)
+		}
+	}
+	if triple == nil || !triple.Exported || triple.File != "util/util.go" {
+		t.Errorf("expected Triple to be reported as exported, got %+v", output.Findings)
+	}
+}
@@ -0,0 +1,225 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DepDiagramToolDefinition defines the dep_diagram tool
+var DepDiagramToolDefinition = ToolDefinition{
+	Name: "dep_diagram",
+	Description: `Generate a package dependency diagram for a Go module.
+Builds the package import graph with 'go list' and renders it as Mermaid ('mermaid', default)
+or Graphviz DOT ('dot') text that can be pasted into documentation or rendered by the user.
+Set 'internal_only' to show only imports between packages of the module, or 'exclude_std'
+to drop standard library packages while keeping third-party dependencies.`,
+	InputSchema: DepDiagramInputSchema,
+	Function:    DepDiagram,
+}
+
+// DepDiagramInput defines the input parameters for the dep_diagram tool
+type DepDiagramInput struct {
+	Pattern      string `json:"pattern,omitempty" jsonschema_description:"Package pattern to graph. Defaults to './...'."`
+	Format       string `json:"format,omitempty" jsonschema_description:"Output format: 'mermaid' (default) or 'dot'"`
+	InternalOnly bool   `json:"internal_only,omitempty" jsonschema_description:"Only include imports between packages of the listed module(s)"`
+	ExcludeStd   bool   `json:"exclude_std,omitempty" jsonschema_description:"Exclude standard library packages"`
+	WorkingDir   string `json:"working_dir,omitempty" jsonschema_description:"Working directory (defaults to current directory if empty)"`
+}
+
+// DepDiagramInputSchema is the JSON schema for the dep_diagram tool
+var DepDiagramInputSchema = GenerateSchema[DepDiagramInput]()
+
+// DepEdge is a single import from one package to another
+type DepEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// DepDiagramOutput represents the structured output of the dep_diagram tool
+type DepDiagramOutput struct {
+	Format   string    `json:"format"`
+	Packages int       `json:"packages"`
+	Edges    []DepEdge `json:"edges"`
+	Diagram  string    `json:"diagram"`
+}
+
+// listedPackage is the subset of 'go list -json' output used to build the graph
+type listedPackage struct {
+	ImportPath string
+	Standard   bool
+	Imports    []string
+	Module     *struct {
+		Path string
+	}
+}
+
+// DepDiagram implements the dep_diagram tool functionality
+func DepDiagram(ctx context.Context, input json.RawMessage) (string, error) {
+	diagramInput := DepDiagramInput{}
+	err := json.Unmarshal(input, &diagramInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	pattern := diagramInput.Pattern
+	if pattern == "" {
+		pattern = "./..."
+	}
+
+	format := diagramInput.Format
+	if format == "" {
+		format = "mermaid"
+	}
+	if format != "mermaid" && format != "dot" {
+		return "", fmt.Errorf("invalid format: %s. Must be 'mermaid' or 'dot'", format)
+	}
+
+	result, err := RunGoCommand(ctx, "list", pattern, []string{"-json"}, diagramInput.WorkingDir)
+	if err != nil {
+		return "", err
+	}
+	if !result.Success {
+		return "", fmt.Errorf("go list failed: %s", strings.TrimSpace(result.Stderr))
+	}
+
+	packages, err := parseListedPackages(result.Stdout)
+	if err != nil {
+		return "", err
+	}
+
+	edges, modulePrefix := buildDepEdges(packages, diagramInput.InternalOnly, diagramInput.ExcludeStd)
+
+	output := DepDiagramOutput{
+		Format:   format,
+		Packages: len(packages),
+		Edges:    edges,
+	}
+	if format == "dot" {
+		output.Diagram = renderDotDiagram(edges, modulePrefix)
+	} else {
+		output.Diagram = renderMermaidDiagram(edges, modulePrefix)
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// parseListedPackages decodes the concatenated JSON objects printed by 'go list -json'
+func parseListedPackages(stdout string) ([]listedPackage, error) {
+	var packages []listedPackage
+	decoder := json.NewDecoder(strings.NewReader(stdout))
+	for {
+		var pkg listedPackage
+		if err := decoder.Decode(&pkg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse go list output: %w", err)
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+// buildDepEdges returns the sorted import edges of the listed packages after filtering,
+// along with the module path prefix used to shorten labels
+func buildDepEdges(packages []listedPackage, internalOnly, excludeStd bool) ([]DepEdge, string) {
+	modules := map[string]bool{}
+	for _, pkg := range packages {
+		if pkg.Module != nil {
+			modules[pkg.Module.Path] = true
+		}
+	}
+	inModule := func(importPath string) bool {
+		for module := range modules {
+			if importPath == module || strings.HasPrefix(importPath, module+"/") {
+				return true
+			}
+		}
+		return false
+	}
+	// Standard library import paths have no dot in their first element
+	isStd := func(importPath string) bool {
+		return !strings.Contains(strings.SplitN(importPath, "/", 2)[0], ".") && !inModule(importPath)
+	}
+
+	edges := []DepEdge{}
+	for _, pkg := range packages {
+		for _, imp := range pkg.Imports {
+			if imp == "C" || (internalOnly && !inModule(imp)) || (excludeStd && isStd(imp)) {
+				continue
+			}
+			edges = append(edges, DepEdge{From: pkg.ImportPath, To: imp})
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+
+	modulePrefix := ""
+	if len(modules) == 1 {
+		for module := range modules {
+			modulePrefix = module + "/"
+		}
+	}
+	return edges, modulePrefix
+}
+
+// depNodeLabel shortens a package path by removing the module prefix
+func depNodeLabel(importPath, modulePrefix string) string {
+	if modulePrefix != "" && strings.HasPrefix(importPath, modulePrefix) {
+		return strings.TrimPrefix(importPath, modulePrefix)
+	}
+	return importPath
+}
+
+// depNodeIDs assigns stable identifiers to every package appearing in edges
+func depNodeIDs(edges []DepEdge) ([]string, map[string]string) {
+	ids := map[string]string{}
+	var nodes []string
+	for _, edge := range edges {
+		for _, node := range []string{edge.From, edge.To} {
+			if _, ok := ids[node]; !ok {
+				ids[node] = fmt.Sprintf("n%d", len(nodes))
+				nodes = append(nodes, node)
+			}
+		}
+	}
+	return nodes, ids
+}
+
+// renderMermaidDiagram renders edges as a Mermaid flowchart
+func renderMermaidDiagram(edges []DepEdge, modulePrefix string) string {
+	nodes, ids := depNodeIDs(edges)
+
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, node := range nodes {
+		fmt.Fprintf(&b, "    %s[\"%s\"]\n", ids[node], depNodeLabel(node, modulePrefix))
+	}
+	for _, edge := range edges {
+		fmt.Fprintf(&b, "    %s --> %s\n", ids[edge.From], ids[edge.To])
+	}
+	return b.String()
+}
+
+// renderDotDiagram renders edges as a Graphviz DOT digraph
+func renderDotDiagram(edges []DepEdge, modulePrefix string) string {
+	var b strings.Builder
+	b.WriteString("digraph deps {\n    rankdir=LR;\n    node [shape=box];\n")
+	for _, edge := range edges {
+		fmt.Fprintf(&b, "    %q -> %q;\n", depNodeLabel(edge.From, modulePrefix), depNodeLabel(edge.To, modulePrefix))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
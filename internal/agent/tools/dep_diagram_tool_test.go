@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeDepFixture writes a module where cmd/app imports internal/store, which imports fmt
+func writeDepFixture(t *testing.T, dir string) {
+	t.Helper()
+	testGoModule(t, dir, "example.com/app")
+	writeTestFile(t, dir, "cmd/app/main.go", `package main
+
+import (
+	"os"
+
+	"example.com/app/internal/store"
+)
+
+func main() {
+	store.Save()
+	os.Exit(0)
+}
+`)
+	writeTestFile(t, dir, "internal/store/store.go", `package store
+
+import "fmt"
+
+func Save() { fmt.Println("saved") }
+`)
+}
+
+func TestDepDiagramDot(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeDepFixture(t, dir)
+
+	var output DepDiagramOutput
+	callTool(t, ctx, DepDiagram, DepDiagramInput{Format: "dot", WorkingDir: dir}, &output)
+
+	if output.Packages != 2 {
+		t.Errorf("packages = %d, want 2", output.Packages)
+	}
+	for _, want := range []string{
+		"digraph deps {",
+		`"cmd/app" -> "internal/store";`,
+		`"cmd/app" -> "os";`,
+		`"internal/store" -> "fmt";`,
+	} {
+		if !strings.Contains(output.Diagram, want) {
+			t.Errorf("diagram is missing %s:\n%s", want, output.Diagram)
+		}
+	}
+}
+
+func TestDepDiagramInternalOnlyMermaid(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeDepFixture(t, dir)
+
+	var output DepDiagramOutput
+	callTool(t, ctx, DepDiagram, DepDiagramInput{InternalOnly: true, WorkingDir: dir}, &output)
+
+	want := []DepEdge{{From: "example.com/app/cmd/app", To: "example.com/app/internal/store"}}
+	if !slices.Equal(output.Edges, want) {
+		t.Errorf("edges = %v, want %v", output.Edges, want)
+	}
+	wantDiagram := "graph LR\n    n0[\"cmd/app\"]\n    n1[\"internal/store\"]\n    n0 --> n1\n"
+	if output.Format != "mermaid" || output.Diagram != wantDiagram {
+		t.Errorf("got %s diagram:\n%s", output.Format, output.Diagram)
+	}
+}
+
+func TestBuildDepEdgesExcludeStd(t *testing.T) {
+	module := &struct{ Path string }{Path: "example.com/app"}
+	packages := []listedPackage{
+		{ImportPath: "example.com/app", Module: module, Imports: []string{"C", "fmt", "github.com/rs/zerolog", "example.com/app/util"}},
+	}
+
+	edges, prefix := buildDepEdges(packages, false, true)
+	want := []DepEdge{
+		{From: "example.com/app", To: "example.com/app/util"},
+		{From: "example.com/app", To: "github.com/rs/zerolog"},
+	}
+	if !slices.Equal(edges, want) || prefix != "example.com/app/" {
+		t.Errorf("got edges %v and prefix %q, want %v", edges, prefix, want)
+	}
+}
+
+func TestDepDiagramRejectsUnknownFormat(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	if _, err := DepDiagram(ctx, mustMarshal(t, DepDiagramInput{Format: "svg"})); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,219 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DirDiffToolDefinition defines the diff_dirs tool
+var DirDiffToolDefinition = ToolDefinition{
+	Name: "diff_dirs",
+	Description: `Compare two directory trees and report how they differ.
+Returns files only in the first directory, files only in the second, and files present in both
+whose contents differ (compared by SHA-256 hash). Useful for before/after snapshots of a refactor.
+Set 'show_diff' to include a short line diff for each differing text file.`,
+	InputSchema: DirDiffInputSchema,
+	Function:    DiffDirs,
+}
+
+// DirDiffInput defines the input parameters for the diff_dirs tool
+type DirDiffInput struct {
+	DirA     string `json:"dir_a" jsonschema_required:"true" jsonschema_description:"First directory (e.g. the 'before' snapshot)"`
+	DirB     string `json:"dir_b" jsonschema_required:"true" jsonschema_description:"Second directory (e.g. the 'after' snapshot)"`
+	Ignore   string `json:"ignore,omitempty" jsonschema_description:"Optional glob; files whose name or relative path matches are skipped (e.g. '*.log')"`
+	ShowDiff bool   `json:"show_diff,omitempty" jsonschema_description:"If true, include a short line diff for each modified file"`
+}
+
+// DirDiffInputSchema is the JSON schema for the diff_dirs tool
+var DirDiffInputSchema = GenerateSchema[DirDiffInput]()
+
+// DirDiffModified describes a file present in both trees with different content
+type DirDiffModified struct {
+	Path  string `json:"path"`
+	HashA string `json:"hash_a"`
+	HashB string `json:"hash_b"`
+	Diff  string `json:"diff,omitempty"`
+}
+
+// DirDiffOutput represents the structured output of the diff_dirs tool
+type DirDiffOutput struct {
+	OnlyInA   []string          `json:"only_in_a"`
+	OnlyInB   []string          `json:"only_in_b"`
+	Modified  []DirDiffModified `json:"modified"`
+	Identical int               `json:"identical"`
+}
+
+// DiffDirs implements the diff_dirs tool functionality
+func DiffDirs(ctx context.Context, input json.RawMessage) (string, error) {
+	diffInput := DirDiffInput{}
+	err := json.Unmarshal(input, &diffInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if diffInput.DirA == "" || diffInput.DirB == "" {
+		return "", fmt.Errorf("dir_a and dir_b are required")
+	}
+
+	diffInput.DirA, err = ResolvePath(ctx, diffInput.DirA)
+	if err != nil {
+		return "", err
+	}
+
+	diffInput.DirB, err = ResolvePath(ctx, diffInput.DirB)
+	if err != nil {
+		return "", err
+	}
+	if diffInput.Ignore != "" {
+		if _, err := filepath.Match(diffInput.Ignore, ""); err != nil {
+			return "", fmt.Errorf("invalid ignore glob: %w", err)
+		}
+	}
+
+	filesA, err := hashTree(ctx, diffInput.DirA, diffInput.Ignore)
+	if err != nil {
+		return "", err
+	}
+	filesB, err := hashTree(ctx, diffInput.DirB, diffInput.Ignore)
+	if err != nil {
+		return "", err
+	}
+
+	output := DirDiffOutput{
+		OnlyInA:  []string{},
+		OnlyInB:  []string{},
+		Modified: []DirDiffModified{},
+	}
+
+	for path, hashA := range filesA {
+		hashB, ok := filesB[path]
+		switch {
+		case !ok:
+			output.OnlyInA = append(output.OnlyInA, path)
+		case hashA == hashB:
+			output.Identical++
+		default:
+			modified := DirDiffModified{Path: path, HashA: hashA, HashB: hashB}
+			if diffInput.ShowDiff {
+				modified.Diff = fileLineDiff(filepath.Join(diffInput.DirA, path), filepath.Join(diffInput.DirB, path), path)
+			}
+			output.Modified = append(output.Modified, modified)
+		}
+	}
+	for path := range filesB {
+		if _, ok := filesA[path]; !ok {
+			output.OnlyInB = append(output.OnlyInB, path)
+		}
+	}
+
+	sort.Strings(output.OnlyInA)
+	sort.Strings(output.OnlyInB)
+	sort.Slice(output.Modified, func(i, j int) bool { return output.Modified[i].Path < output.Modified[j].Path })
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// hashTree returns the SHA-256 hash of every regular file under root, keyed by relative path.
+// Paths excluded by .metamorphignore or matching the ignore glob are skipped.
+func hashTree(ctx context.Context, root, ignore string) (map[string]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	hashes := make(map[string]string)
+	ignoreRules := ignoreRulesFor(ctx, root)
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != root && ignoreRules.IgnoredPath(path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if ignore != "" && relPath != "." {
+			nameMatch, _ := filepath.Match(ignore, info.Name())
+			pathMatch, _ := filepath.Match(ignore, relPath)
+			if nameMatch || pathMatch {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		hash, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		hashes[relPath] = hash
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	return hashes, nil
+}
+
+// hashFile returns the hex-encoded SHA-256 hash of a file's contents
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// fileLineDiff returns a short line diff between two files, or "" for binary or unreadable files
+func fileLineDiff(pathA, pathB, label string) string {
+	contentA, errA := os.ReadFile(pathA)
+	contentB, errB := os.ReadFile(pathB)
+	if errA != nil || errB != nil || isBinary(contentA) || isBinary(contentB) {
+		return ""
+	}
+	return sampleLineDiff(label, string(contentA), string(contentB), 10)
+}
+
+// isBinary reports whether content looks like binary data
+func isBinary(content []byte) bool {
+	for _, b := range content {
+		if b == 0 {
+			return true
+		}
+	}
+	return false
+}
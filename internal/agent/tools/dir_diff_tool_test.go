@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffDirs(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "before/same.go", "package same\n")
+	writeTestFile(t, dir, "before/removed.go", "package removed\n")
+	writeTestFile(t, dir, "before/pkg/changed.go", "package pkg\n\nconst Version = 1\n")
+	writeTestFile(t, dir, "before/debug.log", "old log\n")
+	writeTestFile(t, dir, "after/same.go", "package same\n")
+	writeTestFile(t, dir, "after/added.go", "package added\n")
+	writeTestFile(t, dir, "after/pkg/changed.go", "package pkg\n\nconst Version = 2\n")
+	writeTestFile(t, dir, "after/debug.log", "new log\n")
+
+	var output DirDiffOutput
+	callTool(t, ctx, DiffDirs, DirDiffInput{DirA: "before", DirB: "after", Ignore: "*.log", ShowDiff: true}, &output)
+
+	if !reflect.DeepEqual(output.OnlyInA, []string{"removed.go"}) {
+		t.Errorf("only_in_a = %v, want [removed.go]", output.OnlyInA)
+	}
+	if !reflect.DeepEqual(output.OnlyInB, []string{"added.go"}) {
+		t.Errorf("only_in_b = %v, want [added.go]", output.OnlyInB)
+	}
+	if len(output.Modified) != 1 || output.Modified[0].Path != "pkg/changed.go" {
+		t.Fatalf("modified = %+v, want only pkg/changed.go", output.Modified)
+	}
+	if output.Modified[0].HashA == output.Modified[0].HashB {
+		t.Error("modified file reports identical hashes")
+	}
+	if diff := output.Modified[0].Diff; !strings.Contains(diff, "Version = 1") || !strings.Contains(diff, "Version = 2") {
+		t.Errorf("diff doesn't show the changed line:\n%s", diff)
+	}
+	if output.Identical != 1 {
+		t.Errorf("identical = %d, want 1 (the ignored log must not count)", output.Identical)
+	}
+}
+
+func TestDiffDirsHonorsIgnoreFile(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, MetamorphIgnoreFile, "build/\n*.tmp\n")
+	writeTestFile(t, dir, "before/main.go", "package main\n")
+	writeTestFile(t, dir, "before/build/app", "old binary")
+	writeTestFile(t, dir, "after/main.go", "package main\n")
+	writeTestFile(t, dir, "after/build/app", "new binary")
+	writeTestFile(t, dir, "after/scratch.tmp", "notes")
+
+	var output DirDiffOutput
+	callTool(t, ctx, DiffDirs, DirDiffInput{DirA: "before", DirB: "after"}, &output)
+
+	if len(output.OnlyInA) != 0 || len(output.OnlyInB) != 0 || len(output.Modified) != 0 || output.Identical != 1 {
+		t.Errorf("got %+v, want only main.go compared", output)
+	}
+}
+
+func TestDiffDirsErrors(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "a/file.txt", "a")
+	writeTestFile(t, dir, "plain.txt", "not a directory")
+
+	tests := []struct {
+		name  string
+		input DirDiffInput
+	}{
+		{"missing directory", DirDiffInput{DirA: "a", DirB: "missing"}},
+		{"file instead of directory", DirDiffInput{DirA: "a", DirB: "plain.txt"}},
+		{"invalid glob", DirDiffInput{DirA: "a", DirB: "a", Ignore: "["}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DiffDirs(ctx, mustMarshal(t, tt.input)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
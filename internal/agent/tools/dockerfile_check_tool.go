@@ -0,0 +1,345 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DockerfileCheckToolDefinition defines the dockerfile_check tool
+var DockerfileCheckToolDefinition = ToolDefinition{
+	Name: "dockerfile_check",
+	Description: `Lint a Dockerfile for common problems and return structured findings.
+Built-in checks follow hadolint's rule numbers: untagged or 'latest' base images (DL3006, DL3007),
+apt-get installs without -y, --no-install-recommends, or list cleanup (DL3014, DL3015, DL3009),
+'cd' in RUN (DL3003), ADD for local files (DL3020), MAINTAINER (DL4000), repeated CMD or
+ENTRYPOINT (DL4003, DL4004), and a final stage running as root (DL3002). When hadolint is
+installed its findings are merged in; without it the built-in checks still run.`,
+	InputSchema: DockerfileCheckInputSchema,
+	Function:    DockerfileCheck,
+}
+
+// DockerfileCheckInput defines the input parameters for the dockerfile_check tool
+type DockerfileCheckInput struct {
+	Path         string `json:"path,omitempty" jsonschema_description:"Path to the Dockerfile. Defaults to 'Dockerfile'."`
+	SkipHadolint bool   `json:"skip_hadolint,omitempty" jsonschema_description:"If true, only run the built-in checks even when hadolint is installed"`
+}
+
+// DockerfileCheckInputSchema is the JSON schema for the dockerfile_check tool
+var DockerfileCheckInputSchema = GenerateSchema[DockerfileCheckInput]()
+
+// DockerfileFinding is a single issue found in a Dockerfile
+type DockerfileFinding struct {
+	Line     int    `json:"line"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Source   string `json:"source"`
+}
+
+// DockerfileCheckOutput represents the structured output of the dockerfile_check tool
+type DockerfileCheckOutput struct {
+	Path         string              `json:"path"`
+	Stages       int                 `json:"stages"`
+	HadolintUsed bool                `json:"hadolint_used"`
+	Findings     []DockerfileFinding `json:"findings"`
+}
+
+// dockerInstruction is one logical Dockerfile instruction with continuations joined
+type dockerInstruction struct {
+	Line    int
+	Command string
+	Args    string
+}
+
+// dockerfileInstructions lists the instructions recognized by the Dockerfile parser
+var dockerfileInstructions = map[string]bool{
+	"FROM": true, "RUN": true, "CMD": true, "LABEL": true, "MAINTAINER": true, "EXPOSE": true,
+	"ENV": true, "ADD": true, "COPY": true, "ENTRYPOINT": true, "VOLUME": true, "USER": true,
+	"WORKDIR": true, "ARG": true, "ONBUILD": true, "STOPSIGNAL": true, "HEALTHCHECK": true, "SHELL": true,
+}
+
+// aptInstallPattern matches an apt-get or apt install command
+var aptInstallPattern = regexp.MustCompile(`\bapt(-get)?\s+(\S+\s+)*install\b`)
+
+// runCdPattern matches a 'cd' command at the start of a RUN shell command
+var runCdPattern = regexp.MustCompile(`(^|&&|;|\|\|)\s*cd\s`)
+
+// DockerfileCheck implements the dockerfile_check tool functionality
+func DockerfileCheck(ctx context.Context, input json.RawMessage) (string, error) {
+	checkInput := DockerfileCheckInput{}
+	err := json.Unmarshal(input, &checkInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if checkInput.Path == "" {
+		checkInput.Path = "Dockerfile"
+	}
+	checkInput.Path, err = ResolvePath(ctx, checkInput.Path)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(checkInput.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file '%s': %w", checkInput.Path, err)
+	}
+
+	instructions := parseDockerfile(string(content))
+	output := DockerfileCheckOutput{Path: checkInput.Path}
+	output.Findings = checkDockerfile(instructions)
+	for _, instruction := range instructions {
+		if instruction.Command == "FROM" {
+			output.Stages++
+		}
+	}
+
+	if _, lookErr := exec.LookPath("hadolint"); lookErr == nil && !checkInput.SkipHadolint {
+		hadolintFindings, err := runHadolint(ctx, checkInput.Path)
+		if err != nil {
+			return "", err
+		}
+		output.HadolintUsed = true
+		output.Findings = mergeDockerfileFindings(output.Findings, hadolintFindings)
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// parseDockerfile splits content into instructions, joining backslash continuations
+// and skipping comments, blank lines, and parser directives
+func parseDockerfile(content string) []dockerInstruction {
+	var instructions []dockerInstruction
+	var current *dockerInstruction
+
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") || (trimmed == "" && current == nil) {
+			continue
+		}
+
+		continued := strings.HasSuffix(trimmed, "\\")
+		trimmed = strings.TrimSpace(strings.TrimSuffix(trimmed, "\\"))
+
+		if current == nil {
+			command, args, _ := strings.Cut(trimmed, " ")
+			current = &dockerInstruction{Line: i + 1, Command: strings.ToUpper(command), Args: strings.TrimSpace(args)}
+		} else if trimmed != "" {
+			current.Args = strings.TrimSpace(current.Args + " " + trimmed)
+		}
+
+		if !continued {
+			instructions = append(instructions, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		instructions = append(instructions, *current)
+	}
+	return instructions
+}
+
+// checkDockerfile runs the built-in checks over the parsed instructions
+func checkDockerfile(instructions []dockerInstruction) []DockerfileFinding {
+	findings := []DockerfileFinding{}
+	add := func(line int, rule, severity, message string) {
+		findings = append(findings, DockerfileFinding{Line: line, Rule: rule, Severity: severity, Message: message, Source: "builtin"})
+	}
+
+	stageNames := map[string]bool{}
+	var lastUser *dockerInstruction
+	var cmds, entrypoints []int
+	finalFrom := 0
+
+	checkRepeated := func(lines []int, rule, name string) {
+		for j := 1; j < len(lines); j++ {
+			add(lines[j], rule, "error", fmt.Sprintf("Multiple %s instructions found; only the last one in a stage takes effect", name))
+		}
+	}
+
+	for i := range instructions {
+		instruction := instructions[i]
+		switch instruction.Command {
+		case "FROM":
+			checkRepeated(cmds, "DL4003", "CMD")
+			checkRepeated(entrypoints, "DL4004", "ENTRYPOINT")
+			cmds, entrypoints, lastUser = nil, nil, nil
+			finalFrom = instruction.Line
+
+			fields := strings.Fields(instruction.Args)
+			image := ""
+			for _, field := range fields {
+				if !strings.HasPrefix(field, "--") {
+					image = field
+					break
+				}
+			}
+			// Earlier stages, scratch, build args, and digests need no tag
+			skip := image == "" || image == "scratch" || strings.ContainsAny(image, "$@") || stageNames[strings.ToLower(image)]
+			if len(fields) >= 3 && strings.EqualFold(fields[len(fields)-2], "AS") {
+				stageNames[strings.ToLower(fields[len(fields)-1])] = true
+			}
+			if skip {
+				continue
+			}
+			name := image[strings.LastIndex(image, "/")+1:]
+			tag := ""
+			if idx := strings.LastIndex(name, ":"); idx >= 0 {
+				tag = name[idx+1:]
+			}
+			switch tag {
+			case "":
+				add(instruction.Line, "DL3006", "warning", fmt.Sprintf("Always tag the version of an image explicitly: %s", image))
+			case "latest":
+				add(instruction.Line, "DL3007", "warning", fmt.Sprintf("Using latest is prone to errors if the image ever updates; pin a version instead of %s", image))
+			}
+
+		case "RUN":
+			script := instruction.Args
+			if aptInstallPattern.MatchString(script) {
+				if !strings.Contains(script, " -y") && !strings.Contains(script, "--yes") && !strings.Contains(script, "--assume-yes") {
+					add(instruction.Line, "DL3014", "warning", "Use the -y switch to avoid manual input: apt-get -y install <package>")
+				}
+				if !strings.Contains(script, "--no-install-recommends") {
+					add(instruction.Line, "DL3015", "info", "Avoid additional packages by specifying --no-install-recommends")
+				}
+				if !strings.Contains(script, "rm -rf /var/lib/apt/lists") {
+					add(instruction.Line, "DL3009", "info", "Delete the apt-get lists after installing something: rm -rf /var/lib/apt/lists/*")
+				}
+			}
+			if runCdPattern.MatchString(script) {
+				add(instruction.Line, "DL3003", "warning", "Use WORKDIR to switch to a directory instead of cd")
+			}
+
+		case "ADD":
+			sources := strings.Fields(instruction.Args)
+			if len(sources) > 1 {
+				sources = sources[:len(sources)-1]
+			}
+			local := true
+			for _, source := range sources {
+				if strings.HasPrefix(source, "--") {
+					continue
+				}
+				if strings.Contains(source, "://") || isArchiveName(source) {
+					local = false
+				}
+			}
+			if local {
+				add(instruction.Line, "DL3020", "error", "Use COPY instead of ADD for files and folders")
+			}
+
+		case "MAINTAINER":
+			add(instruction.Line, "DL4000", "error", "MAINTAINER is deprecated; use LABEL maintainer=... instead")
+
+		case "CMD":
+			cmds = append(cmds, instruction.Line)
+
+		case "ENTRYPOINT":
+			entrypoints = append(entrypoints, instruction.Line)
+
+		case "USER":
+			lastUser = &instructions[i]
+
+		default:
+			if !dockerfileInstructions[instruction.Command] {
+				add(instruction.Line, "DL1000", "error", fmt.Sprintf("Unknown instruction: %s", instruction.Command))
+			}
+		}
+	}
+	checkRepeated(cmds, "DL4003", "CMD")
+	checkRepeated(entrypoints, "DL4004", "ENTRYPOINT")
+
+	if finalFrom > 0 {
+		if lastUser == nil {
+			add(finalFrom, "DL3002", "warning", "The final stage has no USER instruction, so the container runs as root")
+		} else if user := strings.SplitN(lastUser.Args, ":", 2)[0]; user == "root" || user == "0" {
+			add(lastUser.Line, "DL3002", "warning", "Last USER should not be root")
+		}
+	}
+
+	sortDockerfileFindings(findings)
+	return findings
+}
+
+// isArchiveName reports whether source looks like a local archive that ADD would extract
+func isArchiveName(source string) bool {
+	for _, ext := range []string{".tar", ".tar.gz", ".tgz", ".tar.bz2", ".tbz2", ".tar.xz", ".txz"} {
+		if strings.HasSuffix(source, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// hadolintResult is one entry of 'hadolint --format json' output
+type hadolintResult struct {
+	Line    int    `json:"line"`
+	Code    string `json:"code"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// runHadolint runs hadolint on path and converts its JSON output into findings
+func runHadolint(ctx context.Context, path string) ([]DockerfileFinding, error) {
+	cmd := exec.CommandContext(ctx, "hadolint", "--no-fail", "--format", "json", path)
+	stdout, err := cmd.Output()
+	if err != nil && len(stdout) == 0 {
+		return nil, fmt.Errorf("hadolint failed: %w", err)
+	}
+
+	var results []hadolintResult
+	if err := json.Unmarshal(stdout, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse hadolint output: %w", err)
+	}
+
+	findings := make([]DockerfileFinding, 0, len(results))
+	for _, result := range results {
+		findings = append(findings, DockerfileFinding{
+			Line:     result.Line,
+			Rule:     result.Code,
+			Severity: result.Level,
+			Message:  result.Message,
+			Source:   "hadolint",
+		})
+	}
+	return findings, nil
+}
+
+// mergeDockerfileFindings combines built-in and hadolint findings, preferring hadolint's
+// report when both flag the same rule on the same line
+func mergeDockerfileFindings(builtin, hadolint []DockerfileFinding) []DockerfileFinding {
+	seen := map[string]bool{}
+	merged := append([]DockerfileFinding{}, hadolint...)
+	for _, finding := range hadolint {
+		seen[fmt.Sprintf("%d:%s", finding.Line, finding.Rule)] = true
+	}
+	for _, finding := range builtin {
+		if !seen[fmt.Sprintf("%d:%s", finding.Line, finding.Rule)] {
+			merged = append(merged, finding)
+		}
+	}
+	sortDockerfileFindings(merged)
+	return merged
+}
+
+// sortDockerfileFindings orders findings by line, then rule
+func sortDockerfileFindings(findings []DockerfileFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Rule < findings[j].Rule
+	})
+}
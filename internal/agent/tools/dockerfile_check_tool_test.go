@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"testing"
+)
+
+// dockerfileRules returns the built-in findings of the check as "line rule" keys
+func dockerfileRules(t *testing.T, content string) (DockerfileCheckOutput, map[string]bool) {
+	t.Helper()
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "Dockerfile", content)
+
+	var output DockerfileCheckOutput
+	callTool(t, ctx, DockerfileCheck, DockerfileCheckInput{SkipHadolint: true}, &output)
+
+	rules := make(map[string]bool)
+	for _, finding := range output.Findings {
+		if finding.Source != "builtin" {
+			t.Errorf("unexpected %s finding with hadolint skipped", finding.Source)
+		}
+		rules[finding.Rule] = true
+	}
+	return output, rules
+}
+
+func TestDockerfileCheckFlagsLatestTag(t *testing.T) {
+	output, _ := dockerfileRules(t, "FROM golang:latest\nWORKDIR /app\nUSER app\nCMD [\"./app\"]\n")
+
+	if output.HadolintUsed || output.Stages != 1 {
+		t.Errorf("got %+v", output)
+	}
+	if len(output.Findings) != 1 {
+		t.Fatalf("findings = %+v, want only the latest tag", output.Findings)
+	}
+	if got := output.Findings[0]; got.Rule != "DL3007" || got.Line != 1 || got.Severity != "warning" {
+		t.Errorf("finding = %+v, want DL3007 on line 1", got)
+	}
+}
+
+func TestDockerfileCheckRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"untagged image", "FROM ubuntu\nUSER app\n", "DL3006"},
+		{"apt without -y", "FROM ubuntu:22.04\nRUN apt-get install --no-install-recommends curl && rm -rf /var/lib/apt/lists/*\nUSER app\n", "DL3014"},
+		{"apt without cleanup", "FROM ubuntu:22.04\nRUN apt-get update && \\\n    apt-get install -y --no-install-recommends curl\nUSER app\n", "DL3009"},
+		{"cd in run", "FROM ubuntu:22.04\nRUN cd /src && make\nUSER app\n", "DL3003"},
+		{"maintainer", "FROM ubuntu:22.04\nMAINTAINER someone\nUSER app\n", "DL4000"},
+		{"no user", "FROM ubuntu:22.04\nCMD [\"sh\"]\n", "DL3002"},
+		{"root user", "FROM ubuntu:22.04\nUSER root\n", "DL3002"},
+		{"repeated cmd", "FROM ubuntu:22.04\nUSER app\nCMD a\nCMD b\n", "DL4003"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, rules := dockerfileRules(t, tt.content)
+			if !rules[tt.want] {
+				t.Errorf("expected %s, got %+v", tt.want, output.Findings)
+			}
+		})
+	}
+}
+
+func TestDockerfileCheckMultiStageUsesFinalUser(t *testing.T) {
+	// The build stage may run as root as long as the final stage does not
+	output, rules := dockerfileRules(t, `FROM golang:1.22 AS build
+RUN go build -o /app .
+
+FROM gcr.io/distroless/static:nonroot
+COPY --from=build /app /app
+USER nonroot
+ENTRYPOINT ["/app"]
+`)
+	if output.Stages != 2 || len(rules) != 0 {
+		t.Errorf("got %d stages and findings %+v, want a clean two-stage build", output.Stages, output.Findings)
+	}
+}
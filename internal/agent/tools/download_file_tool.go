@@ -0,0 +1,222 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"metamorph/internal/logger"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// DownloadFileToolDefinition defines the download_file tool
+var DownloadFileToolDefinition = ToolDefinition{
+	Name: "download_file",
+	Description: `Download a file from an http(s) URL into the workspace, e.g. a schema or config template.
+The response is written to 'path' only if it completes within the timeout and size limit; existing
+files are never replaced unless 'overwrite' is set. Loopback, private and link-local addresses are
+refused, including after redirects, unless 'allow_private' is set. Returns the bytes written, the
+content type and the final URL.`,
+	InputSchema:           DownloadFileInputSchema,
+	Function:              DownloadFile,
+	CountsTowardLoopLimit: true,
+}
+
+// DownloadFileInput defines the input parameters for the download_file tool
+type DownloadFileInput struct {
+	URL            string `json:"url" jsonschema_required:"true" jsonschema_description:"http or https URL to download" jsonschema_example:"https://json.schemastore.org/golangci-lint.json"`
+	Path           string `json:"path" jsonschema_required:"true" jsonschema_description:"Workspace path to write the file to"`
+	Overwrite      bool   `json:"overwrite,omitempty" jsonschema_description:"If true, replace the file if it already exists"`
+	AllowPrivate   bool   `json:"allow_private,omitempty" jsonschema_description:"If true, allow loopback, private and link-local addresses"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema_description:"Deadline for the whole download. Defaults to 30, capped at 300."`
+	MaxBytes       int64  `json:"max_bytes,omitempty" jsonschema_description:"Largest response accepted. Defaults to 10 MiB, capped at 100 MiB."`
+}
+
+// DownloadFileInputSchema is the JSON schema for the download_file tool
+var DownloadFileInputSchema = GenerateSchema[DownloadFileInput]()
+
+// DownloadFileOutput represents the structured output of the download_file tool
+type DownloadFileOutput struct {
+	Path         string `json:"path"`
+	URL          string `json:"url"`
+	BytesWritten int64  `json:"bytes_written"`
+	ContentType  string `json:"content_type,omitempty"`
+}
+
+const (
+	defaultDownloadTimeout  = 30 * time.Second
+	maxDownloadTimeout      = 300 * time.Second
+	defaultDownloadMaxBytes = 10 << 20
+	maxDownloadMaxBytes     = 100 << 20
+	maxDownloadRedirects    = 10
+)
+
+// DownloadFile implements the download_file tool functionality
+func DownloadFile(ctx context.Context, input json.RawMessage) (string, error) {
+	downloadInput := DownloadFileInput{}
+	err := DecodeInput(input, &downloadInput)
+	if err != nil {
+		return "", err
+	}
+
+	if downloadInput.URL == "" {
+		return "", fmt.Errorf("url parameter is required")
+	}
+	if err := checkDownloadURL(downloadInput.URL); err != nil {
+		return "", err
+	}
+	if downloadInput.Path == "" {
+		return "", fmt.Errorf("path parameter is required")
+	}
+	target, err := ResolvePath(ctx, downloadInput.Path)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(target); err == nil {
+		if info.IsDir() {
+			return "", fmt.Errorf("%s is a directory", target)
+		}
+		if !downloadInput.Overwrite {
+			return "", fmt.Errorf("%s already exists; set overwrite to replace it", target)
+		}
+	}
+
+	timeout := defaultDownloadTimeout
+	if downloadInput.TimeoutSeconds > 0 {
+		timeout = min(time.Duration(downloadInput.TimeoutSeconds)*time.Second, maxDownloadTimeout)
+	}
+	maxBytes := int64(defaultDownloadMaxBytes)
+	if downloadInput.MaxBytes > 0 {
+		maxBytes = min(downloadInput.MaxBytes, maxDownloadMaxBytes)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadInput.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	logger.FromContext(ctx).Debug().
+		Str("url", logger.Redact(downloadInput.URL)).
+		Str("path", target).
+		Msg("Downloading file")
+
+	resp, err := downloadClient(downloadInput.AllowPrivate).Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("download timed out after %s", timeout)
+		}
+		return "", fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download failed: server returned %s", resp.Status)
+	}
+	if resp.ContentLength > maxBytes {
+		return "", fmt.Errorf("response is %d bytes, more than the %d byte limit", resp.ContentLength, maxBytes)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory for %s: %w", target, err)
+	}
+	// Download next to the target and rename it into place, so a failed download leaves no partial file
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".download-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, io.LimitReader(resp.Body, maxBytes+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("download timed out after %s", timeout)
+		}
+		return "", fmt.Errorf("failed to write download: %w", err)
+	}
+	if written > maxBytes {
+		return "", fmt.Errorf("response exceeds the %d byte limit", maxBytes)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return "", fmt.Errorf("failed to set permissions: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", fmt.Errorf("failed to move download into place: %w", err)
+	}
+
+	output := DownloadFileOutput{
+		Path:         target,
+		URL:          resp.Request.URL.String(),
+		BytesWritten: written,
+		ContentType:  resp.Header.Get("Content-Type"),
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// checkDownloadURL accepts only absolute http and https URLs
+func checkDownloadURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", rawURL, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("unsupported url scheme %q: only http and https are allowed", parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("invalid url %q: missing host", rawURL)
+	}
+	return nil
+}
+
+// downloadClient returns an HTTP client that rechecks the scheme of every redirect and, unless
+// allowPrivate is set, refuses to connect to non-public addresses. The check runs on the resolved
+// address of each connection, so host names resolving to private addresses are caught too.
+func downloadClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, conn syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("refusing to connect to non-public address %s; set allow_private to permit it", host)
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		// No proxy, so the address check applies to the server itself
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxDownloadRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxDownloadRedirects)
+			}
+			return checkDownloadURL(req.URL.String())
+		},
+	}
+}
+
+// isPublicIP reports whether ip is a globally routable unicast address
+func isPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast()
+}
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newDownloadServer serves a JSON schema at /schema.json, redirects /moved to it and
+// serves a large body at /large
+func newDownloadServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/schema.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"type": "object"}`))
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/schema.json", http.StatusFound)
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 2048)))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDownloadFile(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	server := newDownloadServer(t)
+
+	var output DownloadFileOutput
+	callTool(t, ctx, DownloadFile, DownloadFileInput{URL: server.URL + "/moved", Path: "schemas/config.json", AllowPrivate: true}, &output)
+
+	if content := readTestFile(t, filepath.Join(dir, "schemas", "config.json")); content != `{"type": "object"}` {
+		t.Errorf("content = %q", content)
+	}
+	if output.BytesWritten != 18 || output.ContentType != "application/json" || output.URL != server.URL+"/schema.json" {
+		t.Errorf("output = %+v", output)
+	}
+}
+
+func TestDownloadFileOverwriteProtection(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	server := newDownloadServer(t)
+	path := writeTestFile(t, dir, "config.json", "mine")
+
+	input := DownloadFileInput{URL: server.URL + "/schema.json", Path: "config.json", AllowPrivate: true}
+	if _, err := DownloadFile(ctx, mustMarshal(t, input)); err == nil || !strings.Contains(err.Error(), "overwrite") {
+		t.Fatalf("got %v, want the existing file protected", err)
+	}
+	if content := readTestFile(t, path); content != "mine" {
+		t.Errorf("existing file was changed to %q", content)
+	}
+
+	input.Overwrite = true
+	callTool(t, ctx, DownloadFile, input, nil)
+	if content := readTestFile(t, path); content != `{"type": "object"}` {
+		t.Errorf("content after overwrite = %q", content)
+	}
+}
+
+func TestDownloadFileRefusals(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	server := newDownloadServer(t)
+
+	tests := []struct {
+		name  string
+		input DownloadFileInput
+		want  string
+	}{
+		{"private address", DownloadFileInput{URL: server.URL + "/schema.json", Path: "a.json"}, "non-public address"},
+		{"scheme", DownloadFileInput{URL: "file:///etc/passwd", Path: "a.json"}, "scheme"},
+		{"size limit", DownloadFileInput{URL: server.URL + "/large", Path: "a.json", AllowPrivate: true, MaxBytes: 1024}, "limit"},
+		{"not found", DownloadFileInput{URL: server.URL + "/missing", Path: "a.json", AllowPrivate: true}, "404"},
+		{"outside workspace", DownloadFileInput{URL: server.URL + "/schema.json", Path: "../a.json", AllowPrivate: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DownloadFile(ctx, mustMarshal(t, tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error mentioning %q", err, tt.want)
+			}
+		})
+	}
+
+	// Refused downloads leave nothing behind, not even the temporary file
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("workspace contains %v after refused downloads", entries)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for address, want := range map[string]bool{
+		"93.184.216.34": true,
+		"127.0.0.1":     false,
+		"10.1.2.3":      false,
+		"192.168.0.1":   false,
+		"169.254.1.1":   false,
+		"::1":           false,
+		"fd00::1":       false,
+	} {
+		if got := isPublicIP(net.ParseIP(address)); got != want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", address, got, want)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// EncodingToolDefinition defines the encoding tool
+var EncodingToolDefinition = ToolDefinition{
+	Name: "encoding",
+	Description: `Detect the text encoding of a file and whether it starts with a byte order mark (BOM).
+Recognizes UTF-8, UTF-16 and UTF-32 (little and big endian, with or without a BOM for UTF-16);
+other content that isn't valid UTF-8 is reported as iso-8859-1. Set 'convert' to rewrite the file
+as UTF-8 without a BOM, which is what the other file tools expect. Binary files are never converted.`,
+	InputSchema:           EncodingInputSchema,
+	Function:              CheckEncoding,
+	CountsTowardLoopLimit: true,
+}
+
+// EncodingInput defines the input parameters for the encoding tool
+type EncodingInput struct {
+	Path    string `json:"path" jsonschema_required:"true" jsonschema_description:"Path of the file to inspect"`
+	Convert bool   `json:"convert,omitempty" jsonschema_description:"If true, rewrite the file as UTF-8 without a BOM"`
+}
+
+// EncodingInputSchema is the JSON schema for the encoding tool
+var EncodingInputSchema = GenerateSchema[EncodingInput]()
+
+// EncodingOutput represents the structured output of the encoding tool
+type EncodingOutput struct {
+	Path      string `json:"path"`
+	Encoding  string `json:"encoding"`
+	BOM       bool   `json:"bom"`
+	Converted bool   `json:"converted"`
+	Message   string `json:"message,omitempty"`
+}
+
+// byteOrderMarks lists the BOM of each encoding, longest first so UTF-32LE isn't taken for UTF-16LE
+var byteOrderMarks = []struct {
+	Encoding string
+	Mark     []byte
+}{
+	{"utf-32le", []byte{0xFF, 0xFE, 0x00, 0x00}},
+	{"utf-32be", []byte{0x00, 0x00, 0xFE, 0xFF}},
+	{"utf-8", []byte{0xEF, 0xBB, 0xBF}},
+	{"utf-16le", []byte{0xFF, 0xFE}},
+	{"utf-16be", []byte{0xFE, 0xFF}},
+}
+
+// CheckEncoding implements the encoding tool functionality
+func CheckEncoding(ctx context.Context, input json.RawMessage) (string, error) {
+	encodingInput := EncodingInput{}
+	err := DecodeInput(input, &encodingInput)
+	if err != nil {
+		return "", err
+	}
+
+	if encodingInput.Path == "" {
+		return "", fmt.Errorf("path parameter is required")
+	}
+	encodingInput.Path, err = ResolvePath(ctx, encodingInput.Path)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(encodingInput.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat file '%s': %w", encodingInput.Path, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", encodingInput.Path)
+	}
+	content, err := os.ReadFile(encodingInput.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file '%s': %w", encodingInput.Path, err)
+	}
+
+	encoding, bom := detectEncoding(content)
+	output := EncodingOutput{Path: encodingInput.Path, Encoding: encoding, BOM: bom}
+
+	if encodingInput.Convert {
+		switch {
+		case encoding == "binary":
+			output.Message = "the file looks binary and was left unchanged"
+		case encoding == "utf-8" && !bom:
+			output.Message = "the file is already UTF-8 without a BOM"
+		default:
+			converted, err := decodeToUTF8(content, encoding, bom)
+			if err != nil {
+				return "", err
+			}
+			if err := os.WriteFile(encodingInput.Path, converted, info.Mode().Perm()); err != nil {
+				return "", fmt.Errorf("failed to write file: %w", err)
+			}
+			recordReadHash(encodingInput.Path, converted)
+			output.Converted = true
+			output.Message = fmt.Sprintf("converted from %s to UTF-8", encoding)
+			if bom {
+				output.Message += " and removed the BOM"
+			}
+		}
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// detectEncoding guesses the encoding of content and reports whether it starts with a BOM.
+// The result is one of utf-8, utf-16le, utf-16be, utf-32le, utf-32be, iso-8859-1 or binary.
+func detectEncoding(content []byte) (string, bool) {
+	for _, bom := range byteOrderMarks {
+		if bytes.HasPrefix(content, bom.Mark) {
+			return bom.Encoding, true
+		}
+	}
+
+	if encoding := unmarkedUTF16(content); encoding != "" {
+		return encoding, false
+	}
+	if isBinary(content) {
+		return "binary", false
+	}
+	if utf8.Valid(content) {
+		return "utf-8", false
+	}
+	return "iso-8859-1", false
+}
+
+// unmarkedUTF16 recognizes UTF-16 text without a BOM by the zero high bytes of ASCII characters:
+// it returns utf-16le when most odd bytes are zero and no even byte is, utf-16be for the reverse,
+// and "" otherwise
+func unmarkedUTF16(content []byte) string {
+	if len(content) < 2 || len(content)%2 != 0 {
+		return ""
+	}
+	var evenZeros, oddZeros int
+	for i, b := range content {
+		if b != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			evenZeros++
+		} else {
+			oddZeros++
+		}
+	}
+	units := len(content) / 2
+	switch {
+	case evenZeros == 0 && oddZeros*2 > units:
+		return "utf-16le"
+	case oddZeros == 0 && evenZeros*2 > units:
+		return "utf-16be"
+	}
+	return ""
+}
+
+// decodeToUTF8 converts content from encoding to UTF-8, dropping the BOM when it has one
+func decodeToUTF8(content []byte, encoding string, bom bool) ([]byte, error) {
+	if bom {
+		for _, mark := range byteOrderMarks {
+			if mark.Encoding == encoding {
+				content = content[len(mark.Mark):]
+				break
+			}
+		}
+	}
+
+	var b strings.Builder
+	switch encoding {
+	case "utf-8":
+		return content, nil
+	case "utf-16le", "utf-16be":
+		if len(content)%2 != 0 {
+			return nil, fmt.Errorf("invalid %s content: odd number of bytes", encoding)
+		}
+		var order binary.ByteOrder = binary.LittleEndian
+		if encoding == "utf-16be" {
+			order = binary.BigEndian
+		}
+		units := make([]uint16, len(content)/2)
+		for i := range units {
+			units[i] = order.Uint16(content[2*i:])
+		}
+		for _, r := range utf16.Decode(units) {
+			b.WriteRune(r)
+		}
+	case "utf-32le", "utf-32be":
+		if len(content)%4 != 0 {
+			return nil, fmt.Errorf("invalid %s content: length is not a multiple of 4", encoding)
+		}
+		var order binary.ByteOrder = binary.LittleEndian
+		if encoding == "utf-32be" {
+			order = binary.BigEndian
+		}
+		for i := 0; i < len(content); i += 4 {
+			// WriteRune replaces invalid code points with U+FFFD
+			b.WriteRune(rune(order.Uint32(content[i:])))
+		}
+	case "iso-8859-1":
+		// Every ISO-8859-1 byte is the code point of the same value
+		for _, c := range content {
+			b.WriteRune(rune(c))
+		}
+	default:
+		return nil, fmt.Errorf("cannot convert %s content", encoding)
+	}
+	return []byte(b.String()), nil
+}
+
+// encodingWarning returns a warning for content that isn't plain UTF-8, or "" if it is
+func encodingWarning(content []byte) string {
+	encoding, bom := detectEncoding(content)
+	switch {
+	case encoding == "binary":
+		return ""
+	case encoding == "utf-8" && bom:
+		return "file starts with a UTF-8 byte order mark; use the encoding tool with convert to remove it"
+	case encoding != "utf-8":
+		return fmt.Sprintf("file is not valid UTF-8 (detected %s); use the encoding tool with convert before editing it", encoding)
+	}
+	return ""
+}
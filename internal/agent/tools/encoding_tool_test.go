@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// encodeUTF16 encodes text as UTF-16 in the given byte order, after the optional prefix
+func encodeUTF16(text string, order binary.AppendByteOrder, prefix ...byte) []byte {
+	encoded := append([]byte(nil), prefix...)
+	for _, unit := range utf16.Encode([]rune(text)) {
+		encoded = order.AppendUint16(encoded, unit)
+	}
+	return encoded
+}
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		encoding string
+		bom      bool
+	}{
+		{"utf-8", []byte("héllo\n"), "utf-8", false},
+		{"utf-8 BOM", []byte("\xEF\xBB\xBFhello\n"), "utf-8", true},
+		{"utf-16le BOM", encodeUTF16("hello\n", binary.LittleEndian, 0xFF, 0xFE), "utf-16le", true},
+		{"utf-16be BOM", encodeUTF16("hello\n", binary.BigEndian, 0xFE, 0xFF), "utf-16be", true},
+		{"utf-16le unmarked", encodeUTF16("hello\n", binary.LittleEndian), "utf-16le", false},
+		{"utf-16be unmarked", encodeUTF16("hello\n", binary.BigEndian), "utf-16be", false},
+		{"utf-32le BOM", []byte{0xFF, 0xFE, 0x00, 0x00, 'h', 0, 0, 0}, "utf-32le", true},
+		{"iso-8859-1", []byte("caf\xe9\n"), "iso-8859-1", false},
+		{"binary", []byte{0x7F, 'E', 'L', 'F', 0x02, 0x01, 0x01, 0x00, 0x00}, "binary", false},
+		{"empty", nil, "utf-8", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoding, bom := detectEncoding(tt.content)
+			if encoding != tt.encoding || bom != tt.bom {
+				t.Errorf("detectEncoding = %s (bom %v), want %s (bom %v)", encoding, bom, tt.encoding, tt.bom)
+			}
+		})
+	}
+}
+
+func TestEncodingConvertsUTF16(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, encodeUTF16("héllo wörld €\r\n", binary.LittleEndian, 0xFF, 0xFE), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var detected EncodingOutput
+	callTool(t, ctx, CheckEncoding, EncodingInput{Path: "notes.txt"}, &detected)
+	if detected.Encoding != "utf-16le" || !detected.BOM || detected.Converted {
+		t.Errorf("got %+v, want utf-16le with a BOM, unconverted", detected)
+	}
+
+	var converted EncodingOutput
+	callTool(t, ctx, CheckEncoding, EncodingInput{Path: "notes.txt", Convert: true}, &converted)
+	if !converted.Converted || !strings.Contains(converted.Message, "removed the BOM") {
+		t.Errorf("got %+v, want the file converted", converted)
+	}
+	if got := readTestFile(t, path); got != "héllo wörld €\r\n" {
+		t.Errorf("converted content = %q", got)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("converted file mode = %v, %v, want 0600 kept", info.Mode().Perm(), err)
+	}
+
+	var again EncodingOutput
+	callTool(t, ctx, CheckEncoding, EncodingInput{Path: "notes.txt", Convert: true}, &again)
+	if again.Converted || again.Encoding != "utf-8" {
+		t.Errorf("got %+v, want UTF-8 left alone", again)
+	}
+}
+
+func TestEncodingConvertsLatin1AndSkipsBinary(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	latin1 := writeTestFile(t, dir, "latin1.txt", "caf\xe9\n")
+	binaryFile := writeTestFile(t, dir, "data.bin", "\x00\x01\x02")
+
+	callTool(t, ctx, CheckEncoding, EncodingInput{Path: "latin1.txt", Convert: true}, nil)
+	if got := readTestFile(t, latin1); got != "café\n" {
+		t.Errorf("converted content = %q, want café", got)
+	}
+
+	var output EncodingOutput
+	callTool(t, ctx, CheckEncoding, EncodingInput{Path: "data.bin", Convert: true}, &output)
+	if output.Converted || output.Encoding != "binary" || readTestFile(t, binaryFile) != "\x00\x01\x02" {
+		t.Errorf("got %+v, want the binary file left unchanged", output)
+	}
+}
+
+func TestFileReaderWarnsAboutEncoding(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "bom.txt", "\xEF\xBB\xBFhello\n")
+	writeTestFile(t, dir, "plain.txt", "hello\n")
+
+	content, err := ReadFileContent(ctx, mustMarshal(t, FileReaderInput{Path: "bom.txt"}))
+	if err != nil || !strings.HasPrefix(content, "Warning: file starts with a UTF-8 byte order mark") {
+		t.Errorf("read = %q, %v, want a BOM warning", content, err)
+	}
+	content, err = ReadFileContent(ctx, mustMarshal(t, FileReaderInput{Path: "plain.txt"}))
+	if err != nil || strings.Contains(content, "Warning") {
+		t.Errorf("read = %q, %v, want no warning for plain UTF-8", content, err)
+	}
+}
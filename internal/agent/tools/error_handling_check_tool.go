@@ -0,0 +1,451 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrorHandlingCheckToolDefinition defines the error_handling_check tool
+var ErrorHandlingCheckToolDefinition = ToolDefinition{
+	Name: "error_handling_check",
+	Description: `Statically check Go code for error-handling anti-patterns.
+Reports errors discarded with '_' or by calling a function without using its error result
+(ignored_error), 'if err != nil' branches that are empty (empty_error_branch) or only log and carry
+on (logged_and_ignored), 'err' declared in an inner scope that shadows an outer error variable read
+after it (error_shadowing), and naked returns in error branches of functions with named results that
+don't return the checked error (naked_return). Packages are type-checked to know which values are
+errors. 'path' may be a file or a directory, which is checked recursively.`,
+	InputSchema: ErrorHandlingCheckInputSchema,
+	Function:    ErrorHandlingCheck,
+}
+
+// ErrorHandlingCheckInput defines the input parameters for the error_handling_check tool
+type ErrorHandlingCheckInput struct {
+	Path         string `json:"path,omitempty" jsonschema_description:"Go file or directory to check. Defaults to the current directory."`
+	IncludeTests bool   `json:"include_tests,omitempty" jsonschema_description:"If true, also check _test.go files"`
+}
+
+// ErrorHandlingCheckInputSchema is the JSON schema for the error_handling_check tool
+var ErrorHandlingCheckInputSchema = GenerateSchema[ErrorHandlingCheckInput]()
+
+// ErrorHandlingIssue is a place where an error may be lost
+type ErrorHandlingIssue struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function,omitempty"`
+	Pattern  string `json:"pattern"`
+	Issue    string `json:"issue"`
+}
+
+// ErrorHandlingCheckOutput represents the structured output of the error_handling_check tool
+type ErrorHandlingCheckOutput struct {
+	FilesChecked int                  `json:"files_checked"`
+	Issues       []ErrorHandlingIssue `json:"issues"`
+}
+
+// errorType is the predeclared error interface
+var errorType = types.Universe.Lookup("error").Type()
+
+// uncheckedErrorFuncs lists functions and methods whose error result is conventionally ignored
+var uncheckedErrorFuncs = map[string]bool{
+	"fmt.Print":                      true,
+	"fmt.Printf":                     true,
+	"fmt.Println":                    true,
+	"fmt.Fprint":                     true,
+	"fmt.Fprintf":                    true,
+	"fmt.Fprintln":                   true,
+	"(*strings.Builder).Write":       true,
+	"(*strings.Builder).WriteByte":   true,
+	"(*strings.Builder).WriteRune":   true,
+	"(*strings.Builder).WriteString": true,
+	"(*bytes.Buffer).Write":          true,
+	"(*bytes.Buffer).WriteByte":      true,
+	"(*bytes.Buffer).WriteRune":      true,
+	"(*bytes.Buffer).WriteString":    true,
+}
+
+// logFuncNames are the names of functions and methods that log or print a message
+var logFuncNames = map[string]bool{
+	"Print": true, "Printf": true, "Println": true,
+	"Log": true, "Logf": true, "Msg": true, "Msgf": true, "Send": true,
+	"Debug": true, "Debugf": true, "Info": true, "Infof": true,
+	"Warn": true, "Warnf": true, "Warning": true, "Warningf": true,
+	"Error": true, "Errorf": true,
+}
+
+// ErrorHandlingCheck implements the error_handling_check tool functionality
+func ErrorHandlingCheck(ctx context.Context, input json.RawMessage) (string, error) {
+	checkInput := ErrorHandlingCheckInput{}
+	err := DecodeInput(input, &checkInput)
+	if err != nil {
+		return "", err
+	}
+
+	root := workspaceDir(ctx)
+	if checkInput.Path != "" {
+		root, err = ResolvePath(ctx, checkInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	files, err := goFilesUnder(ctx, root)
+	if err != nil {
+		return "", err
+	}
+
+	// Files are type-checked together per directory and package clause
+	fset := token.NewFileSet()
+	packages := map[string][]*ast.File{}
+	var keys []string
+	output := ErrorHandlingCheckOutput{Issues: []ErrorHandlingIssue{}}
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") && !checkInput.IncludeTests {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		output.FilesChecked++
+		key := filepath.Dir(path) + " " + file.Name.Name
+		if _, found := packages[key]; !found {
+			keys = append(keys, key)
+		}
+		packages[key] = append(packages[key], file)
+	}
+
+	imports := importer.ForCompiler(fset, "source", nil)
+	for _, key := range keys {
+		info := &types.Info{
+			Types:  map[ast.Expr]types.TypeAndValue{},
+			Defs:   map[*ast.Ident]types.Object{},
+			Uses:   map[*ast.Ident]types.Object{},
+			Scopes: map[ast.Node]*types.Scope{},
+		}
+		// Type errors are tolerated so incomplete code can still be checked
+		config := types.Config{Importer: imports, Error: func(error) {}}
+		config.Check(packages[key][0].Name.Name, fset, packages[key], info)
+		for _, file := range packages[key] {
+			output.Issues = append(output.Issues, checkErrorHandling(fset, file, info)...)
+		}
+	}
+
+	sort.SliceStable(output.Issues, func(i, j int) bool {
+		a, b := output.Issues[i], output.Issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// checkErrorHandling reports the error-handling issues in one type-checked file
+func checkErrorHandling(fset *token.FileSet, file *ast.File, info *types.Info) []ErrorHandlingIssue {
+	var issues []ErrorHandlingIssue
+	report := func(node ast.Node, function, pattern, message string) {
+		position := fset.Position(node.Pos())
+		issues = append(issues, ErrorHandlingIssue{
+			File:     position.Filename,
+			Line:     position.Line,
+			Function: function,
+			Pattern:  pattern,
+			Issue:    message,
+		})
+	}
+
+	// isError reports whether expr is an error value; without type information, identifiers
+	// named err are assumed to be
+	isError := func(expr ast.Expr) bool {
+		if t := info.TypeOf(expr); t != nil {
+			return types.Identical(t, errorType)
+		}
+		ident, ok := expr.(*ast.Ident)
+		return ok && ident.Name == "err"
+	}
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+		name := funcDecl.Name.Name
+		if receiver := receiverTypeName(funcDecl); receiver != "" {
+			name = receiver + "." + name
+		}
+		namedError := namedErrorResult(funcDecl, info)
+
+		ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.AssignStmt:
+				for _, index := range discardedErrors(node, info) {
+					switch {
+					case len(node.Rhs) == 1 && len(node.Lhs) > 1:
+						report(node, name, "ignored_error", fmt.Sprintf("error result %d of %s is assigned to _ and never handled", index+1, callName(fset, node.Rhs[0])))
+					case isCall(node.Rhs[index]):
+						report(node, name, "ignored_error", fmt.Sprintf("the error returned by %s is assigned to _ and never handled", callName(fset, node.Rhs[index])))
+					default:
+						report(node, name, "ignored_error", fmt.Sprintf("%s is assigned to _ and never handled", exprString(fset, node.Rhs[index])))
+					}
+				}
+
+			case *ast.ExprStmt:
+				call, ok := node.X.(*ast.CallExpr)
+				if !ok || uncheckedErrorFuncs[calleeName(call, info)] {
+					return true
+				}
+				if returnsError(info.TypeOf(call)) {
+					report(node, name, "ignored_error", fmt.Sprintf("the error returned by %s is not checked", callName(fset, call)))
+				}
+
+			case *ast.IfStmt:
+				checked := checkedError(node.Cond, isError)
+				if checked == nil {
+					return true
+				}
+				switch {
+				case len(node.Body.List) == 0 && node.Else == nil:
+					report(node, name, "empty_error_branch", fmt.Sprintf("the %s != nil branch is empty, so the error is dropped", exprString(fset, checked)))
+				case node.Else == nil && onlyLogs(node.Body):
+					report(node, name, "logged_and_ignored", fmt.Sprintf("the %s != nil branch only logs the error and carries on as if it succeeded", exprString(fset, checked)))
+				}
+				if namedError == nil {
+					return true
+				}
+				checkedObj := info.ObjectOf(identOf(checked))
+				ast.Inspect(node.Body, func(n ast.Node) bool {
+					if _, ok := n.(*ast.FuncLit); ok {
+						return false
+					}
+					ret, ok := n.(*ast.ReturnStmt)
+					if ok && len(ret.Results) == 0 && (checkedObj == nil || checkedObj != namedError) {
+						report(ret, name, "naked_return", fmt.Sprintf(
+							"naked return in the %s != nil branch returns %s, which may not hold the error",
+							exprString(fset, checked), namedError.Name()))
+					}
+					return true
+				})
+			}
+			return true
+		})
+
+		for _, issue := range shadowedErrors(funcDecl, info) {
+			report(issue.ident, name, "error_shadowing", fmt.Sprintf(
+				"%s declared here shadows the %s declared on line %d, which is read after this scope ends without seeing this value",
+				issue.ident.Name, issue.ident.Name, fset.Position(issue.outer.Pos()).Line))
+		}
+	}
+
+	return issues
+}
+
+// discardedErrors returns the indexes of the error values an assignment assigns to _
+func discardedErrors(assign *ast.AssignStmt, info *types.Info) []int {
+	var values []types.Type
+	switch {
+	case len(assign.Rhs) == 1 && len(assign.Lhs) > 1:
+		tuple, ok := info.TypeOf(assign.Rhs[0]).(*types.Tuple)
+		if !ok {
+			return nil
+		}
+		for i := 0; i < tuple.Len(); i++ {
+			values = append(values, tuple.At(i).Type())
+		}
+	case len(assign.Rhs) == len(assign.Lhs):
+		for _, rhs := range assign.Rhs {
+			values = append(values, info.TypeOf(rhs))
+		}
+	}
+
+	var indexes []int
+	for i, lhs := range assign.Lhs {
+		if ident, ok := lhs.(*ast.Ident); ok && ident.Name == "_" && i < len(values) && values[i] != nil && types.Identical(values[i], errorType) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// returnsError reports whether a call result of type t is or includes an error
+func returnsError(t types.Type) bool {
+	if tuple, ok := t.(*types.Tuple); ok {
+		for i := 0; i < tuple.Len(); i++ {
+			if types.Identical(tuple.At(i).Type(), errorType) {
+				return true
+			}
+		}
+		return false
+	}
+	return t != nil && types.Identical(t, errorType)
+}
+
+// calleeName returns the qualified name of the function or method a call invokes, such as
+// fmt.Println or (*bytes.Buffer).WriteString, or "" if it is unknown
+func calleeName(call *ast.CallExpr, info *types.Info) string {
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	}
+	if ident == nil {
+		return ""
+	}
+	fn, ok := info.ObjectOf(ident).(*types.Func)
+	if !ok {
+		return ""
+	}
+	return fn.FullName()
+}
+
+// callName renders the function a call expression invokes, e.g. os.Remove
+func callName(fset *token.FileSet, expr ast.Expr) string {
+	if call, ok := expr.(*ast.CallExpr); ok {
+		return exprString(fset, call.Fun)
+	}
+	return exprString(fset, expr)
+}
+
+// isCall reports whether expr is a function call
+func isCall(expr ast.Expr) bool {
+	_, ok := expr.(*ast.CallExpr)
+	return ok
+}
+
+// checkedError returns the error compared in a condition of the form 'x != nil'
+func checkedError(cond ast.Expr, isError func(ast.Expr) bool) ast.Expr {
+	binary, ok := cond.(*ast.BinaryExpr)
+	if !ok || binary.Op != token.NEQ {
+		return nil
+	}
+	if ident, ok := binary.Y.(*ast.Ident); !ok || ident.Name != "nil" {
+		return nil
+	}
+	if !isError(binary.X) {
+		return nil
+	}
+	return binary.X
+}
+
+// identOf returns expr if it is an identifier, or nil
+func identOf(expr ast.Expr) *ast.Ident {
+	ident, _ := expr.(*ast.Ident)
+	return ident
+}
+
+// onlyLogs reports whether every statement in body is a call to a logging or printing function
+func onlyLogs(body *ast.BlockStmt) bool {
+	if len(body.List) == 0 {
+		return false
+	}
+	for _, stmt := range body.List {
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			return false
+		}
+		call, ok := expr.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !logFuncNames[sel.Sel.Name] {
+			return false
+		}
+	}
+	return true
+}
+
+// namedErrorResult returns the named error result of a function, or nil if it has none
+func namedErrorResult(funcDecl *ast.FuncDecl, info *types.Info) types.Object {
+	if funcDecl.Type.Results == nil {
+		return nil
+	}
+	for _, field := range funcDecl.Type.Results.List {
+		for _, name := range field.Names {
+			if obj := info.Defs[name]; obj != nil && types.Identical(obj.Type(), errorType) {
+				return obj
+			}
+		}
+	}
+	return nil
+}
+
+// shadowedError is an error variable declared in an inner scope over an outer one
+type shadowedError struct {
+	ident *ast.Ident
+	outer types.Object
+}
+
+// shadowedErrors finds error variables declared with := or var in a block nested in the
+// function that shadow an error variable of the function, when the outer variable is next read,
+// not assigned, after the block ends: a sign the inner assignment was meant to update it
+func shadowedErrors(funcDecl *ast.FuncDecl, info *types.Info) []shadowedError {
+	funcScope := info.Scopes[funcDecl.Type]
+	if funcScope == nil {
+		return nil
+	}
+
+	var declared []*ast.Ident
+	assigned := map[*ast.Ident]bool{}
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					assigned[ident] = true
+					if node.Tok == token.DEFINE {
+						declared = append(declared, ident)
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			declared = append(declared, node.Names...)
+		}
+		return true
+	})
+
+	var shadowed []shadowedError
+	for _, ident := range declared {
+		variable, ok := info.Defs[ident].(*types.Var)
+		if !ok || !types.Identical(variable.Type(), errorType) || variable.Parent() == nil || variable.Parent() == funcScope {
+			continue
+		}
+		_, outer := variable.Parent().Parent().LookupParent(variable.Name(), ident.Pos())
+		if outer == nil || outer.Pkg() == nil || outer.Parent() == outer.Pkg().Scope() {
+			continue
+		}
+		if _, ok := outer.(*types.Var); !ok || !types.Identical(outer.Type(), errorType) {
+			continue
+		}
+
+		// The outer variable must be read before anything assigns it again
+		var next *ast.Ident
+		scopeEnd := variable.Parent().End()
+		for use, usedObj := range info.Uses {
+			if usedObj == outer && use.Pos() > scopeEnd && use.Pos() < funcDecl.Body.End() && (next == nil || use.Pos() < next.Pos()) {
+				next = use
+			}
+		}
+		if next != nil && !assigned[next] {
+			shadowed = append(shadowed, shadowedError{ident: ident, outer: outer})
+		}
+	}
+	return shadowed
+}
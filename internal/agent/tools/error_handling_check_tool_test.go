@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const errorHandlingFixture = `package app
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+func cleanup(path string) {
+	_ = os.Remove(path)
+	os.Remove(path)
+	fmt.Println("removed")
+	var b strings.Builder
+	b.WriteString("ok")
+}
+
+func open(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+	}
+	defer f.Close()
+	if err := f.Sync(); err != nil {
+		log.Printf("sync: %v", err)
+	}
+	_, _ = f.Stat()
+}
+
+func handled(path string) error {
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("remove: %w", err)
+	}
+	return nil
+}
+
+func shadowed(paths []string) error {
+	var err error
+	for _, path := range paths {
+		_, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+	}
+	return err
+}
+
+func named(path string) (n int, err error) {
+	if _, statErr := os.Stat(path); statErr != nil {
+		return
+	}
+	return 1, nil
+}
+`
+
+func TestErrorHandlingCheck(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+	writeTestFile(t, dir, "app.go", errorHandlingFixture)
+	writeTestFile(t, dir, "app_test.go", "package app\n\nimport \"os\"\n\nfunc helper() { os.Remove(\"x\") }\n")
+
+	var output ErrorHandlingCheckOutput
+	callTool(t, ctx, ErrorHandlingCheck, ErrorHandlingCheckInput{}, &output)
+
+	var got []string
+	for _, issue := range output.Issues {
+		got = append(got, fmt.Sprintf("%d %s %s", issue.Line, issue.Function, issue.Pattern))
+	}
+	want := []string{
+		"11 cleanup ignored_error",
+		"12 cleanup ignored_error",
+		"20 open empty_error_branch",
+		"23 open logged_and_ignored",
+		"26 open ignored_error",
+		"39 shadowed error_shadowing",
+		"49 named naked_return",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %q, want %q", got, want)
+	}
+	if output.FilesChecked != 1 {
+		t.Errorf("checked %d files, want the test file skipped", output.FilesChecked)
+	}
+	if len(output.Issues) > 0 && output.Issues[0].Issue != "the error returned by os.Remove is assigned to _ and never handled" {
+		t.Errorf("issue = %q", output.Issues[0].Issue)
+	}
+
+	output = ErrorHandlingCheckOutput{}
+	callTool(t, ctx, ErrorHandlingCheck, ErrorHandlingCheckInput{IncludeTests: true}, &output)
+	if output.FilesChecked != 2 || len(output.Issues) != len(want)+1 {
+		t.Errorf("with tests: %d files and %d issues, want the test file's ignored error too", output.FilesChecked, len(output.Issues))
+	}
+}
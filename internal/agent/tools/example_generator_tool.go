@@ -0,0 +1,234 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExampleGeneratorToolDefinition defines the gen_examples tool
+var ExampleGeneratorToolDefinition = ToolDefinition{
+	Name: "gen_examples",
+	Description: `Scaffold a runnable Example function documenting an exported Go function or method.
+The example is named Example<Func> (Example<Type>_<Method> for methods) and is appended to the
+corresponding _test.go file (created if it doesn't exist). It declares a zero-valued variable for
+each parameter, calls the function, prints the results with fmt.Println and ends with an
+'// Output:' stub. Replace the arguments with realistic values and fill in the expected output.
+Set 'suffix' to add a second example for the same function. Set 'run' to compile and run it.`,
+	InputSchema:           ExampleGeneratorInputSchema,
+	Function:              GenerateExample,
+	CountsTowardLoopLimit: true,
+}
+
+// ExampleGeneratorInput defines the input parameters for the gen_examples tool
+type ExampleGeneratorInput struct {
+	Path     string `json:"path" jsonschema_required:"true" jsonschema_description:"Path to the Go source file containing the function"`
+	Function string `json:"function" jsonschema_required:"true" jsonschema_description:"Name of the exported function, or 'Type.Method' for a method" jsonschema_example:"Parser.Parse"`
+	Suffix   string `json:"suffix,omitempty" jsonschema_description:"Optional lowercase suffix distinguishing several examples of one function" jsonschema_example:"empty"`
+	Run      bool   `json:"run,omitempty" jsonschema_description:"If true, run the generated example with 'go test -run' after writing it"`
+}
+
+// ExampleGeneratorInputSchema is the JSON schema for the gen_examples tool
+var ExampleGeneratorInputSchema = GenerateSchema[ExampleGeneratorInput]()
+
+// ExampleGeneratorOutput represents the structured output of the gen_examples tool
+type ExampleGeneratorOutput struct {
+	TestFile    string       `json:"test_file"`
+	ExampleName string       `json:"example_name"`
+	Created     bool         `json:"created"`
+	Code        string       `json:"code"`
+	RunResult   *RunGoOutput `json:"run_result,omitempty"`
+	RunMessage  string       `json:"run_message,omitempty"`
+}
+
+// exampleSuffixPattern matches a valid example suffix, which must start with a lowercase letter
+var exampleSuffixPattern = regexp.MustCompile(`^[a-z][A-Za-z0-9_]*$`)
+
+// qualifierPattern matches the package qualifier of a type such as 'io.Reader'
+var qualifierPattern = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\.`)
+
+// GenerateExample implements the gen_examples tool functionality
+func GenerateExample(ctx context.Context, input json.RawMessage) (string, error) {
+	genInput := ExampleGeneratorInput{}
+	err := DecodeInput(input, &genInput)
+	if err != nil {
+		return "", err
+	}
+
+	if genInput.Path == "" {
+		return "", fmt.Errorf("path cannot be empty")
+	}
+	genInput.Path, err = ResolvePath(ctx, genInput.Path)
+	if err != nil {
+		return "", err
+	}
+	if genInput.Function == "" {
+		return "", fmt.Errorf("function cannot be empty")
+	}
+	if strings.HasSuffix(genInput.Path, "_test.go") {
+		return "", fmt.Errorf("path must be a non-test Go source file")
+	}
+	if genInput.Suffix != "" && !exampleSuffixPattern.MatchString(genInput.Suffix) {
+		return "", fmt.Errorf("invalid suffix %q: it must start with a lowercase letter", genInput.Suffix)
+	}
+
+	target, err := findTestTarget(genInput.Path, genInput.Function)
+	if err != nil {
+		return "", err
+	}
+	if !token.IsExported(target.Name) || (target.ReceiverType != "" && !token.IsExported(target.ReceiverType)) {
+		return "", fmt.Errorf("%s is not exported; examples document exported API", genInput.Function)
+	}
+
+	exampleName := "Example" + target.Name
+	if target.ReceiverType != "" {
+		exampleName = "Example" + target.ReceiverType + "_" + target.Name
+	}
+	if genInput.Suffix != "" {
+		exampleName += "_" + genInput.Suffix
+	}
+
+	imports, err := signatureImports(genInput.Path, target)
+	if err != nil {
+		return "", err
+	}
+	code := renderExample(exampleName, target)
+	if len(target.Results) > 0 || target.HasError {
+		imports = append([]string{"fmt"}, imports...)
+	}
+
+	testFile := strings.TrimSuffix(genInput.Path, ".go") + "_test.go"
+	created, err := writeTestFunc(testFile, exampleName, target.Package, code, imports)
+	if err != nil {
+		return "", err
+	}
+
+	output := ExampleGeneratorOutput{
+		TestFile:    testFile,
+		ExampleName: exampleName,
+		Created:     created,
+		Code:        code,
+	}
+
+	if genInput.Run {
+		result, err := RunGoCommand(ctx, "test", ".", []string{"-run", "^" + exampleName + "$"}, filepath.Dir(genInput.Path))
+		if err != nil {
+			output.RunMessage = fmt.Sprintf("Failed to run example: %v", err)
+		} else {
+			output.RunResult = &result
+		}
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// renderExample produces the source of an Example function calling target with zero values
+func renderExample(exampleName string, target testTarget) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "func %s() {\n", exampleName)
+	b.WriteString("\t// TODO: Replace the zero values with realistic arguments.\n")
+	if target.Receiver != "" || len(target.Params) > 0 {
+		b.WriteString("\tvar (\n")
+		if target.Receiver != "" {
+			// A value receiver is addressable, so pointer methods can be called on it too
+			fmt.Fprintf(&b, "\t\treceiver %s\n", strings.TrimPrefix(target.Receiver, "*"))
+		}
+		for _, p := range target.Params {
+			fmt.Fprintf(&b, "\t\t%s %s\n", p.Name, p.Type)
+		}
+		b.WriteString("\t)\n")
+	}
+
+	var results []string
+	for i := range target.Results {
+		results = append(results, fmt.Sprintf("got%d", i))
+	}
+	if target.HasError {
+		results = append(results, "err")
+	}
+	b.WriteString("\t")
+	if len(results) > 0 {
+		b.WriteString(strings.Join(results, ", ") + " := ")
+	}
+
+	callee := target.Name
+	if target.Receiver != "" {
+		callee = "receiver." + target.Name
+	}
+	var callArgs []string
+	for _, p := range target.Params {
+		arg := p.Name
+		if p.Variadic {
+			arg += "..."
+		}
+		callArgs = append(callArgs, arg)
+	}
+	fmt.Fprintf(&b, "%s(%s)\n", callee, strings.Join(callArgs, ", "))
+
+	if target.HasError {
+		b.WriteString("\tif err != nil {\n\t\tfmt.Println(\"error:\", err)\n\t\treturn\n\t}\n")
+	}
+	if len(target.Results) > 0 {
+		fmt.Fprintf(&b, "\tfmt.Println(%s)\n", strings.Join(results[:len(target.Results)], ", "))
+	}
+
+	b.WriteString("\t// Output:\n}\n")
+	return b.String()
+}
+
+// signatureImports returns the import paths of the packages referenced by the receiver,
+// parameter and result types of target, as imported by the file at filePath
+func signatureImports(filePath string, target testTarget) ([]string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, nil, parser.ImportsOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	byName := map[string]string{}
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := assumedPackageName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		byName[name] = importPath
+	}
+
+	types := []string{target.Receiver}
+	for _, p := range append(append([]testParam{}, target.Params...), target.Results...) {
+		types = append(types, p.Type)
+	}
+
+	needed := map[string]bool{}
+	for _, typ := range types {
+		for _, match := range qualifierPattern.FindAllStringSubmatch(typ, -1) {
+			if importPath, ok := byName[match[1]]; ok {
+				needed[importPath] = true
+			}
+		}
+	}
+
+	imports := make([]string, 0, len(needed))
+	for importPath := range needed {
+		imports = append(imports, importPath)
+	}
+	sort.Strings(imports)
+	return imports, nil
+}
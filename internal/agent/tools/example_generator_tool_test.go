@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+const exampleFixture = `package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// Store holds cached values
+type Store struct{ items map[string]string }
+
+// Put stores value under key until ttl passes
+func Put(s *Store, key string, ttl time.Duration) (bool, error) {
+	if s == nil {
+		return false, errors.New("nil store")
+	}
+	return true, nil
+}
+
+// Clear removes every value
+func (s *Store) Clear() { s.items = nil }
+
+// Keys lists the keys with any of the prefixes
+func (s *Store) Keys(prefixes ...string) []string { return nil }
+
+func evict(s *Store) {}
+`
+
+func TestGenerateExampleForFunction(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/cache")
+	writeTestFile(t, dir, "cache.go", exampleFixture)
+
+	var output ExampleGeneratorOutput
+	callTool(t, ctx, GenerateExample, ExampleGeneratorInput{Path: "cache.go", Function: "Put"}, &output)
+
+	if output.ExampleName != "ExamplePut" || !output.Created {
+		t.Errorf("got %q created=%v, want a new ExamplePut", output.ExampleName, output.Created)
+	}
+	for _, want := range []string{"s   *Store", "ttl time.Duration", "got0, err := Put(s, key, ttl)", "fmt.Println(got0)", "// Output:"} {
+		if !strings.Contains(readTestFile(t, output.TestFile), want) {
+			t.Errorf("example is missing %q:\n%s", want, readTestFile(t, output.TestFile))
+		}
+	}
+
+	// The scaffold prints output its empty Output stub doesn't expect, so only compile it
+	result, err := RunGoCommand(ctx, "test", ".", []string{"-run", "^$"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Success {
+		t.Errorf("generated example doesn't compile: %s", result.Stderr)
+	}
+}
+
+func TestGenerateExampleForMethods(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/cache")
+	writeTestFile(t, dir, "cache.go", exampleFixture)
+
+	var keys ExampleGeneratorOutput
+	callTool(t, ctx, GenerateExample, ExampleGeneratorInput{Path: "cache.go", Function: "Store.Keys", Suffix: "prefixes"}, &keys)
+	if keys.ExampleName != "ExampleStore_Keys_prefixes" || !strings.Contains(keys.Code, "receiver.Keys(prefixes...)") {
+		t.Errorf("got %q:\n%s", keys.ExampleName, keys.Code)
+	}
+
+	// A method without results prints nothing, so the empty Output stub passes as generated
+	var clear ExampleGeneratorOutput
+	callTool(t, ctx, GenerateExample, ExampleGeneratorInput{Path: "cache.go", Function: "Store.Clear", Run: true}, &clear)
+	if clear.Created || clear.ExampleName != "ExampleStore_Clear" {
+		t.Errorf("got %q created=%v, want it appended to the existing file", clear.ExampleName, clear.Created)
+	}
+	if clear.RunResult == nil || !clear.RunResult.Success {
+		t.Errorf("generated example did not run: %+v %s", clear.RunResult, clear.RunMessage)
+	}
+}
+
+func TestGenerateExampleErrors(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/cache")
+	writeTestFile(t, dir, "cache.go", exampleFixture)
+	callTool(t, ctx, GenerateExample, ExampleGeneratorInput{Path: "cache.go", Function: "Store.Clear"}, nil)
+
+	tests := []struct {
+		name  string
+		input ExampleGeneratorInput
+		want  string
+	}{
+		{"unexported", ExampleGeneratorInput{Path: "cache.go", Function: "evict"}, "not exported"},
+		{"duplicate", ExampleGeneratorInput{Path: "cache.go", Function: "Store.Clear"}, "already exists"},
+		{"bad suffix", ExampleGeneratorInput{Path: "cache.go", Function: "Put", Suffix: "Upper"}, "invalid suffix"},
+		{"test file", ExampleGeneratorInput{Path: "cache_test.go", Function: "Put"}, "non-test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateExample(ctx, mustMarshal(t, tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
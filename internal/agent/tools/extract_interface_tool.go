@@ -0,0 +1,172 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExtractInterfaceToolDefinition defines the extract_interface tool
+var ExtractInterfaceToolDefinition = ToolDefinition{
+	Name: "extract_interface",
+	Description: `Generate an interface declaration from the exported methods of a concrete Go type.
+The package containing the type is type-checked and every exported method of the type, including
+methods with pointer receivers and methods promoted from embedded fields, is listed with its exact
+signature. Types from other packages are qualified and the imports they need are reported.
+Set 'output_file' to append the interface to a file in the same package (created if missing).`,
+	InputSchema:           ExtractInterfaceInputSchema,
+	Function:              ExtractInterface,
+	CountsTowardLoopLimit: true,
+}
+
+// ExtractInterfaceInput defines the input parameters for the extract_interface tool
+type ExtractInterfaceInput struct {
+	Path       string `json:"path" jsonschema_required:"true" jsonschema_description:"Go file or package directory containing the type"`
+	Type       string `json:"type" jsonschema_required:"true" jsonschema_description:"Name of the concrete type to extract the interface from"`
+	Name       string `json:"name,omitempty" jsonschema_description:"Name of the generated interface. Defaults to the type name followed by 'Interface'." jsonschema_example:"Store"`
+	OutputFile string `json:"output_file,omitempty" jsonschema_description:"Optional file in the type's package to append the interface to"`
+}
+
+// ExtractInterfaceInputSchema is the JSON schema for the extract_interface tool
+var ExtractInterfaceInputSchema = GenerateSchema[ExtractInterfaceInput]()
+
+// ExtractInterfaceOutput represents the structured output of the extract_interface tool
+type ExtractInterfaceOutput struct {
+	Interface    string       `json:"interface"`
+	Type         string       `json:"type"`
+	Methods      []MethodStub `json:"methods"`
+	Source       string       `json:"source"`
+	Imports      []string     `json:"imports,omitempty"`
+	WrittenFile  string       `json:"written_file,omitempty"`
+	AddedImports []string     `json:"added_imports,omitempty"`
+}
+
+// ExtractInterface implements the extract_interface tool functionality
+func ExtractInterface(ctx context.Context, input json.RawMessage) (string, error) {
+	extractInput := ExtractInterfaceInput{}
+	err := json.Unmarshal(input, &extractInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if extractInput.Path == "" || extractInput.Type == "" {
+		return "", fmt.Errorf("path and type are required")
+	}
+
+	extractInput.Path, err = ResolvePath(ctx, extractInput.Path)
+	if err != nil {
+		return "", err
+	}
+
+	dir := extractInput.Path
+	if info, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("failed to stat %s: %w", dir, err)
+	} else if !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	fset := token.NewFileSet()
+	pkg, err := typeCheckDir(ctx, fset, dir)
+	if err != nil {
+		return "", err
+	}
+
+	typeName, ok := pkg.Scope().Lookup(extractInput.Type).(*types.TypeName)
+	if !ok {
+		return "", fmt.Errorf("type %s not found in package %s", extractInput.Type, pkg.Name())
+	}
+	if types.IsInterface(typeName.Type()) {
+		return "", fmt.Errorf("%s is already an interface", extractInput.Type)
+	}
+
+	name := extractInput.Name
+	if name == "" {
+		name = extractInput.Type + "Interface"
+	}
+	if !token.IsIdentifier(name) {
+		return "", fmt.Errorf("invalid interface name: %q", name)
+	}
+
+	// Qualify types from other packages by name, collecting the imports they need
+	imports := map[string]bool{}
+	qualifier := func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		imports[p.Path()] = true
+		return p.Name()
+	}
+
+	output := ExtractInterfaceOutput{
+		Interface: name,
+		Type:      extractInput.Type,
+		Methods:   []MethodStub{},
+	}
+
+	// The pointer method set holds every method callable on an addressable value
+	methodSet := types.NewMethodSet(types.NewPointer(typeName.Type()))
+	for i := 0; i < methodSet.Len(); i++ {
+		method := methodSet.At(i).Obj()
+		if !method.Exported() {
+			continue
+		}
+		output.Methods = append(output.Methods, MethodStub{
+			Name:      method.Name(),
+			Signature: signatureString(method.Name(), method.Type().(*types.Signature), qualifier),
+		})
+	}
+	if len(output.Methods) == 0 {
+		return "", fmt.Errorf("type %s has no exported methods", extractInput.Type)
+	}
+
+	var source strings.Builder
+	fmt.Fprintf(&source, "// %s is the set of exported methods of %s\ntype %s interface {\n", name, extractInput.Type, name)
+	for _, method := range output.Methods {
+		fmt.Fprintf(&source, "\t%s\n", method.Signature)
+	}
+	source.WriteString("}\n")
+	output.Source = source.String()
+
+	for path := range imports {
+		output.Imports = append(output.Imports, path)
+	}
+	sort.Strings(output.Imports)
+
+	if extractInput.OutputFile != "" {
+		file, err := ResolvePath(ctx, extractInput.OutputFile)
+		if err != nil {
+			return "", err
+		}
+		if !sameFile(filepath.Dir(file), dir) {
+			return "", fmt.Errorf("output_file must be in the package directory of %s (%s)", extractInput.Type, dir)
+		}
+		if pkg.Scope().Lookup(name) != nil {
+			return "", fmt.Errorf("%s is already declared in package %s", name, pkg.Name())
+		}
+
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			if err := os.WriteFile(file, []byte("package "+pkg.Name()+"\n"), 0644); err != nil {
+				return "", fmt.Errorf("failed to create %s: %w", file, err)
+			}
+		}
+		added, err := insertMethodStubs(file, "\n"+output.Source, output.Imports)
+		if err != nil {
+			return "", err
+		}
+		output.WrittenFile = file
+		output.AddedImports = added
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const extractInterfaceFixture = `package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Locker is embedded so its methods are promoted
+type Locker struct {
+	sync.Mutex
+}
+
+// Cache stores values with an expiry
+type Cache struct {
+	Locker
+	items map[string]string
+}
+
+func (c *Cache) Get(ctx context.Context, key string) (string, bool) { return c.items[key], true }
+
+func (c Cache) Len() int { return len(c.items) }
+
+func (c *Cache) Set(key, value string, ttl time.Duration) error { return nil }
+
+func (c *Cache) Keys(prefixes ...string) (keys []string) { return nil }
+
+func (c *Cache) evict() {}
+`
+
+func TestExtractInterfaceMethods(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/cache")
+	writeTestFile(t, dir, "cache.go", extractInterfaceFixture)
+
+	var output ExtractInterfaceOutput
+	callTool(t, ctx, ExtractInterface, ExtractInterfaceInput{Path: "cache.go", Type: "Cache", Name: "Store"}, &output)
+
+	want := []MethodStub{
+		{Name: "Get", Signature: "Get(ctx context.Context, key string) (string, bool)"},
+		{Name: "Keys", Signature: "Keys(prefixes ...string) (keys []string)"},
+		{Name: "Len", Signature: "Len() int"},
+		{Name: "Lock", Signature: "Lock()"},
+		{Name: "Set", Signature: "Set(key string, value string, ttl time.Duration) error"},
+		{Name: "TryLock", Signature: "TryLock() bool"},
+		{Name: "Unlock", Signature: "Unlock()"},
+	}
+	if !reflect.DeepEqual(output.Methods, want) {
+		t.Errorf("methods = %+v, want %+v", output.Methods, want)
+	}
+	if !reflect.DeepEqual(output.Imports, []string{"context", "time"}) {
+		t.Errorf("imports = %v, want context and time", output.Imports)
+	}
+	if !strings.HasPrefix(output.Source, "// Store is the set of exported methods of Cache\ntype Store interface {\n\tGet(") {
+		t.Errorf("unexpected source:\n%s", output.Source)
+	}
+	if output.WrittenFile != "" {
+		t.Errorf("nothing should be written without output_file, wrote %s", output.WrittenFile)
+	}
+}
+
+func TestExtractInterfaceWritesFile(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/cache")
+	writeTestFile(t, dir, "cache.go", extractInterfaceFixture)
+
+	var output ExtractInterfaceOutput
+	callTool(t, ctx, ExtractInterface, ExtractInterfaceInput{Path: ".", Type: "Cache", OutputFile: "iface.go"}, &output)
+
+	if output.Interface != "CacheInterface" {
+		t.Errorf("interface = %q, want the default name", output.Interface)
+	}
+	content := readTestFile(t, output.WrittenFile)
+	if !strings.HasPrefix(content, "package cache\n") || !strings.Contains(content, "type CacheInterface interface {") {
+		t.Errorf("unexpected file:\n%s", content)
+	}
+
+	// The written interface must compile and be satisfied by the type
+	writeTestFile(t, dir, "assert.go", "package cache\n\nvar _ CacheInterface = (*Cache)(nil)\n")
+	result, err := RunGoCommand(ctx, "build", "./...", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Success {
+		t.Errorf("package with the extracted interface doesn't compile: %s", result.Stderr)
+	}
+}
+
+func TestExtractInterfaceErrors(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/cache")
+	writeTestFile(t, dir, "cache.go", extractInterfaceFixture+"\ntype Getter interface{ Get() }\n\ntype empty struct{}\n")
+	writeTestFile(t, dir, "other/other.go", "package other\n")
+
+	tests := []struct {
+		name  string
+		input ExtractInterfaceInput
+		want  string
+	}{
+		{"unknown type", ExtractInterfaceInput{Path: ".", Type: "Missing"}, "not found"},
+		{"interface", ExtractInterfaceInput{Path: ".", Type: "Getter"}, "already an interface"},
+		{"no exported methods", ExtractInterfaceInput{Path: ".", Type: "empty"}, "no exported methods"},
+		{"existing name", ExtractInterfaceInput{Path: ".", Type: "Cache", Name: "Locker", OutputFile: "iface.go"}, "already declared"},
+		{"other package", ExtractInterfaceInput{Path: ".", Type: "Cache", OutputFile: "other/iface.go"}, "package directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ExtractInterface(ctx, mustMarshal(t, tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -1,12 +1,18 @@
 package tools
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
-	"path"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"text/template"
 )
 
 // FileEditorDefinition defines the improved edit_file tool
@@ -20,33 +26,40 @@ Multiple edit modes available:
 4. 'append': Append 'content' to the end of the file
 5. 'prepend': Prepend 'content' to the beginning of the file
 6. 'insert_at_line': Insert 'content' at line number specified by 'line_number'
-
-If the file doesn't exist and mode is not 'create', it will be created first.`,
-	InputSchema: FileEditorInputSchema,
-	Function:    EditFileContent,
+7. 'template': Render 'content' as a Go text/template with the values in 'data' and write the result
+   to the file, replacing any existing content (e.g. content 'package {{.Name}}', data {"Name": "foo"})
+8. 'replace_line': Replace the content of line 'line_number' with 'new_str', keeping the line ending
+
+If the file doesn't exist and mode is not 'create' or 'replace_line', it will be created first.
+Set 'expect_unchanged' to abort if the file was modified by someone else since it was last read.`,
+	InputSchema:           FileEditorInputSchema,
+	Function:              EditFileContent,
+	CountsTowardLoopLimit: true,
 }
 
 // FileEditorInput defines the enhanced input parameters for the edit_file tool
 type FileEditorInput struct {
-	Path       string `json:"path" jsonschema_description:"The path to the file"`
-	Mode       string `json:"mode" jsonschema_description:"Edit mode: 'replace', 'regex_replace', 'create', 'append', 'prepend', or 'insert_at_line'"`
-	OldStr     string `json:"old_str,omitempty" jsonschema_description:"Text to search for when using 'replace' mode - must match exactly"`
-	NewStr     string `json:"new_str,omitempty" jsonschema_description:"Text to replace old_str with in 'replace' or 'regex_replace' modes"`
-	Pattern    string `json:"pattern,omitempty" jsonschema_description:"Regular expression pattern for 'regex_replace' mode"`
-	Content    string `json:"content,omitempty" jsonschema_description:"Content to write in 'create', 'append', 'prepend', or 'insert_at_line' modes"`
-	LineNumber int    `json:"line_number,omitempty" jsonschema_description:"Line number for 'insert_at_line' mode (1-based indexing)"`
-	Limit      int    `json:"limit,omitempty" jsonschema_description:"Maximum number of replacements to make (0 means replace all occurrences)"`
+	Path            string                 `json:"path" jsonschema_required:"true" jsonschema_description:"The path to the file" jsonschema_example:"internal/agent/agent.go"`
+	Mode            string                 `json:"mode" jsonschema_required:"true" jsonschema_description:"Edit mode: 'replace', 'regex_replace', 'create', 'append', 'prepend', 'insert_at_line', 'template', or 'replace_line'" jsonschema_example:"replace"`
+	OldStr          string                 `json:"old_str,omitempty" jsonschema_description:"Text to search for when using 'replace' mode - must match exactly"`
+	NewStr          string                 `json:"new_str,omitempty" jsonschema_description:"Text to replace old_str with in 'replace' or 'regex_replace' modes, or the new line content in 'replace_line' mode"`
+	Pattern         string                 `json:"pattern,omitempty" jsonschema_description:"Regular expression pattern for 'regex_replace' mode"`
+	Content         string                 `json:"content,omitempty" jsonschema_description:"Content to write in 'create', 'append', 'prepend', or 'insert_at_line' modes, or the template for 'template' mode"`
+	LineNumber      int                    `json:"line_number,omitempty" jsonschema_description:"Line number for 'insert_at_line' and 'replace_line' modes (1-based indexing)"`
+	Limit           int                    `json:"limit,omitempty" jsonschema_description:"Maximum number of replacements to make (0 means replace all occurrences)"`
+	Data            map[string]interface{} `json:"data,omitempty" jsonschema_description:"Values available to the template in 'template' mode, e.g. {\"Name\": \"Parser\"}"`
+	ExpectUnchanged bool                   `json:"expect_unchanged,omitempty" jsonschema_description:"If true, abort when the file changed since it was last read with file_reader or written by one of the editing tools"`
 }
 
 // FileEditorInputSchema is the JSON schema for the edit_file tool
 var FileEditorInputSchema = GenerateSchema[FileEditorInput]()
 
 // EditFileContent implements the enhanced edit_file tool functionality
-func EditFileContent(input json.RawMessage) (string, error) {
+func EditFileContent(ctx context.Context, input json.RawMessage) (string, error) {
 	editFileInput := FileEditorInput{}
-	err := json.Unmarshal(input, &editFileInput)
+	err := DecodeInput(input, &editFileInput)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse input: %w", err)
+		return "", err
 	}
 
 	// Validate basic inputs
@@ -54,26 +67,111 @@ func EditFileContent(input json.RawMessage) (string, error) {
 		return "", fmt.Errorf("path cannot be empty")
 	}
 
+	editFileInput.Path, err = ResolvePath(ctx, editFileInput.Path)
+	if err != nil {
+		return "", err
+	}
+
+	// Make sure nobody else changed the file since we last saw it
+	if editFileInput.ExpectUnchanged {
+		if err := checkUnchangedSinceRead(editFileInput.Path); err != nil {
+			return "", err
+		}
+	}
+
+	// Compare against the content before the edit so a call that changes nothing, such as
+	// 'create' on an existing file, doesn't vouch for content the agent never saw
+	before, beforeErr := os.ReadFile(editFileInput.Path)
+
 	// Process based on mode
+	var result string
 	switch editFileInput.Mode {
 	case "create":
 		if editFileInput.Content == "" {
 			return "", fmt.Errorf("cannot create an empty file, content is required")
 		}
-		return createFile(editFileInput.Path, editFileInput.Content)
+		result, err = createFile(editFileInput.Path, editFileInput.Content)
 	case "replace":
-		return replaceInFile(editFileInput.Path, editFileInput.OldStr, editFileInput.NewStr, editFileInput.Limit)
+		result, err = replaceInFile(editFileInput.Path, editFileInput.OldStr, editFileInput.NewStr, editFileInput.Limit)
 	case "regex_replace":
-		return regexReplaceInFile(editFileInput.Path, editFileInput.Pattern, editFileInput.NewStr, editFileInput.Limit)
+		result, err = regexReplaceInFile(editFileInput.Path, editFileInput.Pattern, editFileInput.NewStr, editFileInput.Limit)
 	case "append":
-		return appendToFile(editFileInput.Path, editFileInput.Content)
+		result, err = appendToFile(editFileInput.Path, editFileInput.Content)
 	case "prepend":
-		return prependToFile(editFileInput.Path, editFileInput.Content)
+		result, err = prependToFile(editFileInput.Path, editFileInput.Content)
 	case "insert_at_line":
-		return insertAtLine(editFileInput.Path, editFileInput.Content, editFileInput.LineNumber)
+		result, err = insertAtLine(editFileInput.Path, editFileInput.Content, editFileInput.LineNumber)
+	case "template":
+		if editFileInput.Content == "" {
+			return "", fmt.Errorf("content is required for 'template' mode")
+		}
+		result, err = renderTemplateToFile(editFileInput.Path, editFileInput.Content, editFileInput.Data)
+	case "replace_line":
+		result, err = replaceLine(editFileInput.Path, editFileInput.NewStr, editFileInput.LineNumber)
 	default:
 		return "", fmt.Errorf("invalid mode: %s", editFileInput.Mode)
 	}
+	if err != nil {
+		return "", err
+	}
+
+	// Remember our own write so later edits don't mistake it for an external change
+	if content, err := os.ReadFile(editFileInput.Path); err == nil {
+		if beforeErr != nil || !bytes.Equal(before, content) {
+			recordReadHash(editFileInput.Path, content)
+		}
+		if warning := encodingWarning(content); warning != "" {
+			result += "\nWarning: " + warning
+		}
+	}
+
+	return result, nil
+}
+
+var (
+	// readHashes holds the content hash of each file as last read or written by the agent
+	readHashes      = make(map[string]string)
+	readHashesMutex sync.Mutex
+)
+
+// hashKey normalizes a path for use as a readHashes key
+func hashKey(filePath string) string {
+	if abs, err := filepath.Abs(filePath); err == nil {
+		return abs
+	}
+	return filepath.Clean(filePath)
+}
+
+// recordReadHash remembers the hash of content as the last known state of filePath
+func recordReadHash(filePath string, content []byte) {
+	sum := sha256.Sum256(content)
+
+	readHashesMutex.Lock()
+	defer readHashesMutex.Unlock()
+	readHashes[hashKey(filePath)] = hex.EncodeToString(sum[:])
+}
+
+// checkUnchangedSinceRead re-reads filePath and compares it against the last recorded hash.
+// On a mismatch the error includes the current content so the edit can be redone.
+func checkUnchangedSinceRead(filePath string) error {
+	readHashesMutex.Lock()
+	expected, ok := readHashes[hashKey(filePath)]
+	readHashesMutex.Unlock()
+	if !ok {
+		return fmt.Errorf("no recorded read of %s; read the file with file_reader before editing with expect_unchanged", filePath)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("file modified externally since read: %s can no longer be read: %w", filePath, err)
+	}
+
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != expected {
+		return fmt.Errorf("file modified externally since read: %s. Current content:\n%s", filePath, string(content))
+	}
+
+	return nil
 }
 
 // createFile creates a new file with the given content, creating parent directories if needed
@@ -84,7 +182,7 @@ func createFile(filePath, content string) (string, error) {
 		return fmt.Sprintf("File %s already exists. Use append, prepend, or replace modes to modify it.", filePath), nil
 	}
 
-	dir := path.Dir(filePath)
+	dir := filepath.Dir(filePath)
 	if dir != "." {
 		err := os.MkdirAll(dir, 0755)
 		if err != nil {
@@ -103,7 +201,7 @@ func createFile(filePath, content string) (string, error) {
 // ensureFileExists creates an empty file if it doesn't exist
 func ensureFileExists(filePath string) error {
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		dir := path.Dir(filePath)
+		dir := filepath.Dir(filePath)
 		if dir != "." {
 			if err := os.MkdirAll(dir, 0755); err != nil {
 				return fmt.Errorf("failed to create directory: %w", err)
@@ -288,6 +386,44 @@ func prependToFile(filePath, content string) (string, error) {
 	return fmt.Sprintf("Successfully prepended content to %s", filePath), nil
 }
 
+// renderTemplateToFile executes tmpl as a text/template with data and writes the result to filePath.
+// Missing keys are reported as errors rather than rendered as "<no value>".
+func renderTemplateToFile(filePath, tmpl string, data map[string]interface{}) (string, error) {
+	parsed, err := template.New(filepath.Base(filePath)).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var rendered bytes.Buffer
+	if err := parsed.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	info, statErr := os.Stat(filePath)
+	created := os.IsNotExist(statErr)
+
+	// Keep the permissions of a file being overwritten, e.g. an executable script
+	mode := os.FileMode(0644)
+	if statErr == nil {
+		mode = info.Mode().Perm()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(filePath, rendered.Bytes(), mode); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Chmod(filePath, mode); err != nil {
+		return "", fmt.Errorf("failed to set permissions: %w", err)
+	}
+
+	if created {
+		return fmt.Sprintf("Successfully rendered template into new file %s (%d bytes)", filePath, rendered.Len()), nil
+	}
+	return fmt.Sprintf("Successfully rendered template into %s, replacing its content (%d bytes)", filePath, rendered.Len()), nil
+}
+
 // insertAtLine inserts content at the specified line number
 func insertAtLine(filePath, content string, lineNumber int) (string, error) {
 	if lineNumber < 1 {
@@ -338,3 +474,47 @@ func insertAtLine(filePath, content string, lineNumber int) (string, error) {
 
 	return fmt.Sprintf("Successfully inserted content at line %d in %s", lineNumber, filePath), nil
 }
+
+// replaceLine replaces the content of an existing line, keeping its line ending
+func replaceLine(filePath, newStr string, lineNumber int) (string, error) {
+	if lineNumber < 1 {
+		return "", fmt.Errorf("line number must be at least 1")
+	}
+	if strings.ContainsAny(newStr, "\r\n") {
+		return "", fmt.Errorf("new_str must be a single line for 'replace_line' mode; use 'replace' to change several lines")
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat file: %w", err)
+	}
+	existingContent, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// A trailing newline terminates the last line rather than starting a new one
+	lines := strings.Split(string(existingContent), "\n")
+	lineCount := len(lines)
+	if strings.HasSuffix(string(existingContent), "\n") {
+		lineCount--
+	}
+	if lineNumber > lineCount {
+		return "", fmt.Errorf("line number %d exceeds file length (%d lines)", lineNumber, lineCount)
+	}
+
+	// CRLF files keep the '\r' that ends the replaced line
+	oldLine := lines[lineNumber-1]
+	if strings.HasSuffix(oldLine, "\r") {
+		lines[lineNumber-1] = newStr + "\r"
+	} else {
+		lines[lineNumber-1] = newStr
+	}
+
+	err = os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), info.Mode().Perm())
+	if err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return fmt.Sprintf("Successfully replaced line %d in %s", lineNumber, filePath), nil
+}
@@ -0,0 +1,211 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEditFileDetectsExternalModification(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "config.txt", "port=8080\n")
+
+	if _, err := ReadFileContent(ctx, mustMarshal(t, FileReaderInput{Path: "config.txt"})); err != nil {
+		t.Fatal(err)
+	}
+
+	// Someone else edits the file after the agent read it
+	if err := os.WriteFile(path, []byte("port=9090\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	edit := FileEditorInput{Path: "config.txt", Mode: "replace", OldStr: "port", NewStr: "listen_port", ExpectUnchanged: true}
+	_, err := EditFileContent(ctx, mustMarshal(t, edit))
+	if err == nil || !strings.Contains(err.Error(), "file modified externally since read") {
+		t.Fatalf("expected an external modification error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "port=9090") {
+		t.Errorf("error should include the current content: %v", err)
+	}
+	if got := readTestFile(t, path); got != "port=9090\n" {
+		t.Errorf("the refused edit clobbered the external change: %q", got)
+	}
+}
+
+func TestEditFileExpectUnchangedAfterOwnWrites(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "notes.txt", "one\n")
+
+	if _, err := ReadFileContent(ctx, mustMarshal(t, FileReaderInput{Path: "notes.txt"})); err != nil {
+		t.Fatal(err)
+	}
+	for _, content := range []string{"two\n", "three\n"} {
+		edit := FileEditorInput{Path: "notes.txt", Mode: "append", Content: content, ExpectUnchanged: true}
+		if _, err := EditFileContent(ctx, mustMarshal(t, edit)); err != nil {
+			t.Fatalf("the agent's own earlier write was treated as external: %v", err)
+		}
+	}
+	if got := readTestFile(t, path); got != "one\ntwo\nthree\n" {
+		t.Errorf("content = %q", got)
+	}
+}
+
+func TestEditFileExpectUnchangedRequiresRead(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "unread.txt", "content\n")
+
+	edit := FileEditorInput{Path: "unread.txt", Mode: "append", Content: "more\n", ExpectUnchanged: true}
+	if _, err := EditFileContent(ctx, mustMarshal(t, edit)); err == nil || !strings.Contains(err.Error(), "no recorded read") {
+		t.Errorf("expected an error for a file that was never read, got %v", err)
+	}
+}
+
+func TestEditFileNoOpCreateDoesNotRecordHash(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "existing.txt", "written by someone else\n")
+
+	// 'create' on an existing file changes nothing, so it must not vouch for the content
+	create := FileEditorInput{Path: "existing.txt", Mode: "create", Content: "new\n"}
+	if _, err := EditFileContent(ctx, mustMarshal(t, create)); err != nil {
+		t.Fatal(err)
+	}
+
+	edit := FileEditorInput{Path: "existing.txt", Mode: "append", Content: "more\n", ExpectUnchanged: true}
+	if _, err := EditFileContent(ctx, mustMarshal(t, edit)); err == nil || !strings.Contains(err.Error(), "no recorded read") {
+		t.Errorf("expected the unseen content to require a read first, got %v", err)
+	}
+}
+
+func TestEditFileTemplateCreatesFile(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+
+	edit := FileEditorInput{
+		Path:    "internal/parser/parser.go",
+		Mode:    "template",
+		Content: "package {{.Package}}\n\n{{range .Types}}type {{.}} struct{}\n{{end}}",
+		Data:    map[string]interface{}{"Package": "parser", "Types": []string{"Lexer", "Parser"}},
+	}
+	result, err := EditFileContent(ctx, mustMarshal(t, edit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "new file") {
+		t.Errorf("result should report a created file: %s", result)
+	}
+
+	want := "package parser\n\ntype Lexer struct{}\ntype Parser struct{}\n"
+	if got := readTestFile(t, filepath.Join(dir, "internal", "parser", "parser.go")); got != want {
+		t.Errorf("rendered file = %q, want %q", got, want)
+	}
+}
+
+func TestEditFileTemplatePreservesMode(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "run.sh", "#!/bin/sh\n")
+	if err := os.Chmod(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	edit := FileEditorInput{Path: "run.sh", Mode: "template", Content: "#!/bin/sh\necho {{.Name}}\n", Data: map[string]interface{}{"Name": "hi"}}
+	if _, err := EditFileContent(ctx, mustMarshal(t, edit)); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("mode = %v, want the original 0755", info.Mode().Perm())
+	}
+}
+
+func TestEditFileTemplateErrors(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "out.txt", "original\n")
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"parse error", "{{.Name", "failed to parse template"},
+		{"missing key", "hello {{.Missing}}", "failed to execute template"},
+		{"empty template", "", "content is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edit := FileEditorInput{Path: "out.txt", Mode: "template", Content: tt.content, Data: map[string]interface{}{"Name": "x"}}
+			_, err := EditFileContent(ctx, mustMarshal(t, edit))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+			if got := readTestFile(t, path); got != "original\n" {
+				t.Errorf("a failed render changed the file: %q", got)
+			}
+		})
+	}
+}
+
+func TestEditFileReplaceLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		want    string
+	}{
+		{"middle line", "one\ntwo\nthree\n", 2, "one\nTWO\nthree\n"},
+		{"first line", "one\ntwo\n", 1, "TWO\ntwo\n"},
+		{"last line without newline", "one\ntwo", 2, "one\nTWO"},
+		{"CRLF", "one\r\ntwo\r\nthree\r\n", 2, "one\r\nTWO\r\nthree\r\n"},
+		{"empty line", "one\n\nthree\n", 2, "one\nTWO\nthree\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, dir := newTestWorkspace(t)
+			path := writeTestFile(t, dir, "file.txt", tt.content)
+
+			edit := FileEditorInput{Path: "file.txt", Mode: "replace_line", LineNumber: tt.line, NewStr: "TWO"}
+			if _, err := EditFileContent(ctx, mustMarshal(t, edit)); err != nil {
+				t.Fatal(err)
+			}
+			if got := readTestFile(t, path); got != tt.want {
+				t.Errorf("file = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditFileReplaceLineErrors(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "file.txt", "one\ntwo\n")
+
+	tests := []struct {
+		name   string
+		path   string
+		line   int
+		newStr string
+		want   string
+	}{
+		{"past the end", "file.txt", 3, "x", "exceeds file length (2 lines)"},
+		{"zero", "file.txt", 0, "x", "at least 1"},
+		{"several lines", "file.txt", 1, "a\nb", "single line"},
+		{"missing file", "missing.txt", 1, "x", "failed to stat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edit := FileEditorInput{Path: tt.path, Mode: "replace_line", LineNumber: tt.line, NewStr: tt.newStr}
+			_, err := EditFileContent(ctx, mustMarshal(t, edit))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+	if got := readTestFile(t, path); got != "one\ntwo\n" {
+		t.Errorf("a failed replacement changed the file: %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "missing.txt")); !os.IsNotExist(err) {
+		t.Error("replace_line created a missing file")
+	}
+}
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -9,7 +10,7 @@ import (
 // FileListerDefinition defines the list_files tool
 var FileListerToolDefinition = ToolDefinition{
 	Name:        "file_lister",
-	Description: "List files and directories at a given path. If no path is provided, lists files in the current directory.",
+	Description: "List files and directories at a given path. If no path is provided, lists files in the current directory. Paths excluded by a .metamorphignore file are hidden.",
 	InputSchema: ListDirectoryContentsInputSchema,
 	Function:    ListDirectoryContents,
 }
@@ -23,18 +24,23 @@ type ListDirectoryContentsInput struct {
 var ListDirectoryContentsInputSchema = GenerateSchema[ListDirectoryContentsInput]()
 
 // ListDirectoryContents implements the list_files tool functionality
-func ListDirectoryContents(input json.RawMessage) (string, error) {
+func ListDirectoryContents(ctx context.Context, input json.RawMessage) (string, error) {
 	listFilesInput := ListDirectoryContentsInput{}
 	err := json.Unmarshal(input, &listFilesInput)
 	if err != nil {
 		return "", err
 	}
 
-	dir := "."
+	dir := workspaceDir(ctx)
 	if listFilesInput.Path != "" {
-		dir = listFilesInput.Path
+		dir, err = ResolvePath(ctx, listFilesInput.Path)
+		if err != nil {
+			return "", err
+		}
 	}
 
+	ignoreRules := LoadIgnoreRules(workspaceDir(ctx))
+
 	var files []string
 	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -46,6 +52,14 @@ func ListDirectoryContents(input json.RawMessage) (string, error) {
 			return err
 		}
 
+		// Skip anything excluded by .metamorphignore
+		if ignoreRules.IgnoredPath(path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		if relPath != "." {
 			if info.IsDir() {
 				files = append(files, relPath+"/")
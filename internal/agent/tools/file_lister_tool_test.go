@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestListFilesHonorsMetamorphIgnore(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, MetamorphIgnoreFile, "secrets/\n*.key\n")
+	writeTestFile(t, dir, "main.go", "package main\n")
+	writeTestFile(t, dir, "server.key", "private")
+	writeTestFile(t, dir, "secrets/token.txt", "token")
+	writeTestFile(t, dir, "pkg/util.go", "package pkg\n")
+	writeTestFile(t, dir, "pkg/client.key", "private")
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []string
+		missing []string
+	}{
+		{"workspace root", "", []string{"main.go", "pkg/", "pkg/util.go"}, []string{"server.key", "secrets/", "secrets/token.txt", "pkg/client.key"}},
+		{"subdirectory uses the root rules", "pkg", []string{"util.go"}, []string{"client.key"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ListDirectoryContents(ctx, mustMarshal(t, ListDirectoryContentsInput{Path: tt.path}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var files []string
+			if err := json.Unmarshal([]byte(result), &files); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !slices.Contains(files, want) {
+					t.Errorf("listing is missing %q: %v", want, files)
+				}
+			}
+			for _, hidden := range tt.missing {
+				if slices.Contains(files, hidden) {
+					t.Errorf("listing includes %q, which .metamorphignore excludes: %v", hidden, files)
+				}
+			}
+		})
+	}
+}
@@ -1,35 +1,47 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // FileOpsToolDefinition defines the tool for file operations like copy, move, and rename
 var FileOperationsToolDefinition = ToolDefinition{
-	Name:        "file_operations",
-	Description: "Perform file operations such as copying, moving, and renaming files and directories.",
-	InputSchema: FileOpsToolInputSchema,
-	Function:    FileOpsTool,
+	Name: "file_operations",
+	Description: `Perform file operations such as copying, moving, and renaming files and directories.
+- 'rename' changes a name within the same directory. It fails if the destination is in another
+  directory, to catch mistyped paths, and never replaces an existing file.
+- 'move' relocates a file or directory anywhere in the workspace. An existing destination is
+  only replaced when 'overwrite' is set.
+- 'copy' duplicates a file, or a directory when 'recursive' is set. If the copy fails partway,
+  everything it created is removed; set 'continue_on_error' to copy what it can and get a list
+  of the entries that failed instead.`,
+	InputSchema:           FileOpsToolInputSchema,
+	Function:              FileOpsTool,
+	CountsTowardLoopLimit: true,
 }
 
 // FileOpsToolInput defines the input parameters for the file operations tool
 type FileOpsToolInput struct {
-	Operation   string `json:"operation" jsonschema_description:"The operation to perform: 'copy', 'move', or 'rename'."`
-	Source      string `json:"source" jsonschema_description:"Source file or directory path."`
-	Destination string `json:"destination" jsonschema_description:"Destination file or directory path."`
-	Recursive   bool   `json:"recursive,omitempty" jsonschema_description:"Whether to recursively copy directories (only applicable for 'copy' operation)."`
-	CreateDirs  bool   `json:"create_dirs,omitempty" jsonschema_description:"Whether to create parent directories if they don't exist."`
+	Operation       string `json:"operation" jsonschema_required:"true" jsonschema_description:"The operation to perform: 'copy', 'move', or 'rename'."`
+	Source          string `json:"source" jsonschema_required:"true" jsonschema_description:"Source file or directory path."`
+	Destination     string `json:"destination" jsonschema_required:"true" jsonschema_description:"Destination file or directory path."`
+	Recursive       bool   `json:"recursive,omitempty" jsonschema_description:"Whether to recursively copy directories (only applicable for 'copy' operation)."`
+	CreateDirs      bool   `json:"create_dirs,omitempty" jsonschema_description:"Whether to create parent directories if they don't exist."`
+	Overwrite       bool   `json:"overwrite,omitempty" jsonschema_description:"Whether 'move' may replace an existing destination. 'rename' never overwrites."`
+	ContinueOnError bool   `json:"continue_on_error,omitempty" jsonschema_description:"For a recursive 'copy', keep copying after an entry fails and report the failures instead of aborting and removing the partial copy."`
 }
 
 // FileOpsToolInputSchema is the JSON schema for the file operations tool
 var FileOpsToolInputSchema = GenerateSchema[FileOpsToolInput]()
 
 // FileOpsTool implements file operations functionality
-func FileOpsTool(input json.RawMessage) (string, error) {
+func FileOpsTool(ctx context.Context, input json.RawMessage) (string, error) {
 	fileOpsInput := FileOpsToolInput{}
 	err := json.Unmarshal(input, &fileOpsInput)
 	if err != nil {
@@ -44,6 +56,22 @@ func FileOpsTool(input json.RawMessage) (string, error) {
 		return "", fmt.Errorf("destination path is required")
 	}
 
+	fileOpsInput.Source, err = ResolvePath(ctx, fileOpsInput.Source)
+	if err != nil {
+		return "", err
+	}
+
+	fileOpsInput.Destination, err = ResolvePath(ctx, fileOpsInput.Destination)
+	if err != nil {
+		return "", err
+	}
+
+	// A rename stays within one directory; changing directories is a move
+	if fileOpsInput.Operation == "rename" && filepath.Dir(fileOpsInput.Source) != filepath.Dir(fileOpsInput.Destination) {
+		return "", fmt.Errorf("rename destination '%s' is not in the same directory as '%s'; use the 'move' operation to change directories",
+			fileOpsInput.Destination, fileOpsInput.Source)
+	}
+
 	// Create parent directories if requested
 	if fileOpsInput.CreateDirs {
 		destDir := filepath.Dir(fileOpsInput.Destination)
@@ -52,13 +80,14 @@ func FileOpsTool(input json.RawMessage) (string, error) {
 		}
 	}
 
+	var failures []CopyFailure
 	switch fileOpsInput.Operation {
 	case "copy":
-		err = copyFileOrDir(fileOpsInput.Source, fileOpsInput.Destination, fileOpsInput.Recursive)
+		failures, err = copyFileOrDir(fileOpsInput.Source, fileOpsInput.Destination, fileOpsInput.Recursive, fileOpsInput.ContinueOnError)
 	case "move":
-		err = os.Rename(fileOpsInput.Source, fileOpsInput.Destination)
+		err = movePath(fileOpsInput.Source, fileOpsInput.Destination, fileOpsInput.Overwrite)
 	case "rename":
-		err = os.Rename(fileOpsInput.Source, fileOpsInput.Destination)
+		err = movePath(fileOpsInput.Source, fileOpsInput.Destination, false)
 	default:
 		return "", fmt.Errorf("invalid operation: %s. Must be 'copy', 'move', or 'rename'", fileOpsInput.Operation)
 	}
@@ -67,25 +96,63 @@ func FileOpsTool(input json.RawMessage) (string, error) {
 		return "", fmt.Errorf("file operation failed: %w", err)
 	}
 
+	if len(failures) > 0 {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Performed %s operation from '%s' to '%s' with %d failure(s):\n",
+			fileOpsInput.Operation, fileOpsInput.Source, fileOpsInput.Destination, len(failures))
+		for _, failure := range failures {
+			fmt.Fprintf(&sb, "- %s\n", failure.Error)
+		}
+		return sb.String(), nil
+	}
+
 	return fmt.Sprintf("Successfully performed %s operation from '%s' to '%s'",
 		fileOpsInput.Operation, fileOpsInput.Source, fileOpsInput.Destination), nil
 }
 
-// copyFileOrDir copies a file or directory from src to dst
-func copyFileOrDir(src, dst string, recursive bool) error {
+// movePath renames src to dst, refusing to replace an existing dst unless overwrite is set
+func movePath(src, dst string, overwrite bool) error {
+	if dstInfo, err := os.Lstat(dst); err == nil && !overwrite {
+		// A case-only rename on a case-insensitive filesystem sees src as dst
+		if srcInfo, err := os.Lstat(src); err != nil || !os.SameFile(srcInfo, dstInfo) {
+			return fmt.Errorf("destination '%s' already exists", dst)
+		}
+	}
+	return os.Rename(src, dst)
+}
+
+// CopyFailure records an entry that could not be copied when continuing on error
+type CopyFailure struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// copyFileOrDir copies a file or directory from src to dst. When continueOnError is set, entries
+// that fail to copy are returned as failures and the rest of the tree is still copied; otherwise
+// the first failure aborts the copy and removes everything it created.
+func copyFileOrDir(src, dst string, recursive, continueOnError bool) ([]CopyFailure, error) {
 	srcInfo, err := os.Stat(src)
 	if err != nil {
-		return fmt.Errorf("error getting source info: %w", err)
+		return nil, fmt.Errorf("error getting source info: %w", err)
+	}
+
+	if srcInfo.IsDir() && !recursive {
+		return nil, fmt.Errorf("source is a directory but recursive flag is not set")
 	}
 
+	c := &treeCopy{continueOnError: continueOnError}
 	if srcInfo.IsDir() {
-		if !recursive {
-			return fmt.Errorf("source is a directory but recursive flag is not set")
+		err = c.copyDir(src, dst)
+	} else {
+		err = c.copyFile(src, dst)
+	}
+	if err != nil {
+		if rollbackErr := c.rollback(); rollbackErr != nil {
+			return nil, fmt.Errorf("%w (cleanup of partial copy failed: %v)", err, rollbackErr)
 		}
-		return copyDir(src, dst)
+		return nil, err
 	}
-
-	return copyFile(src, dst)
+	return c.failures, nil
 }
 
 // copyFile copies a single file from src to dst
@@ -115,23 +182,53 @@ func copyFile(src, dst string) error {
 	return os.Chmod(dst, srcInfo.Mode())
 }
 
+// treeCopy copies files and directories, remembering every path it creates so a failed copy
+// can be undone
+type treeCopy struct {
+	continueOnError bool
+	created         []string
+	failures        []CopyFailure
+}
+
+// fail records err for path when continuing on error, or returns it to abort the copy
+func (c *treeCopy) fail(path string, err error) error {
+	if !c.continueOnError {
+		return err
+	}
+	c.failures = append(c.failures, CopyFailure{Path: path, Error: err.Error()})
+	return nil
+}
+
+// copyFile copies a single file, tracking dst if it did not exist before
+func (c *treeCopy) copyFile(src, dst string) error {
+	if _, err := os.Lstat(dst); os.IsNotExist(err) {
+		c.created = append(c.created, dst)
+	}
+	if err := copyFile(src, dst); err != nil {
+		return fmt.Errorf("failed to copy '%s': %w", src, err)
+	}
+	return nil
+}
+
 // copyDir recursively copies a directory from src to dst
-func copyDir(src, dst string) error {
+func (c *treeCopy) copyDir(src, dst string) error {
 	srcInfo, err := os.Stat(src)
 	if err != nil {
-		return fmt.Errorf("error getting source directory info: %w", err)
+		return fmt.Errorf("error getting source directory info for '%s': %w", src, err)
 	}
 
-	// Create destination directory
-	err = os.MkdirAll(dst, srcInfo.Mode())
-	if err != nil {
-		return fmt.Errorf("error creating destination directory: %w", err)
+	// Create destination directory, unless it is already there
+	if _, err := os.Lstat(dst); os.IsNotExist(err) {
+		if err := os.MkdirAll(dst, srcInfo.Mode()); err != nil {
+			return fmt.Errorf("error creating destination directory '%s': %w", dst, err)
+		}
+		c.created = append(c.created, dst)
 	}
 
 	// Read directory entries
 	entries, err := os.ReadDir(src)
 	if err != nil {
-		return fmt.Errorf("error reading source directory: %w", err)
+		return fmt.Errorf("error reading source directory '%s': %w", src, err)
 	}
 
 	// Copy each entry
@@ -140,11 +237,12 @@ func copyDir(src, dst string) error {
 		dstPath := filepath.Join(dst, entry.Name())
 
 		if entry.IsDir() {
-			if err = copyDir(srcPath, dstPath); err != nil {
-				return err
-			}
+			err = c.copyDir(srcPath, dstPath)
 		} else {
-			if err = copyFile(srcPath, dstPath); err != nil {
+			err = c.copyFile(srcPath, dstPath)
+		}
+		if err != nil {
+			if err = c.fail(srcPath, err); err != nil {
 				return err
 			}
 		}
@@ -152,3 +250,15 @@ func copyDir(src, dst string) error {
 
 	return nil
 }
+
+// rollback removes everything the copy created, newest first, leaving pre-existing paths alone
+func (c *treeCopy) rollback() error {
+	var firstErr error
+	for i := len(c.created) - 1; i >= 0; i-- {
+		if err := os.RemoveAll(c.created[i]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	c.created = nil
+	return firstErr
+}
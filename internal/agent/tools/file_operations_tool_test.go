@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileOpsRenameAcrossDirsFails(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	source := writeTestFile(t, dir, "a/notes.txt", "notes\n")
+	if err := os.MkdirAll(filepath.Join(dir, "b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := FileOpsTool(ctx, mustMarshal(t, FileOpsToolInput{Operation: "rename", Source: "a/notes.txt", Destination: "b/notes.txt"}))
+	if err == nil || !strings.Contains(err.Error(), "use the 'move' operation") {
+		t.Fatalf("expected rename across directories to fail, got %v", err)
+	}
+	if _, err := os.Stat(source); err != nil {
+		t.Errorf("the refused rename moved the source: %v", err)
+	}
+}
+
+func TestFileOpsMoveAcrossDirs(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "a/notes.txt", "notes\n")
+
+	if _, err := FileOpsTool(ctx, mustMarshal(t, FileOpsToolInput{Operation: "move", Source: "a/notes.txt", Destination: "b/c/notes.txt", CreateDirs: true})); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, filepath.Join(dir, "b", "c", "notes.txt")); got != "notes\n" {
+		t.Errorf("moved file = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a", "notes.txt")); !os.IsNotExist(err) {
+		t.Error("the source still exists after the move")
+	}
+}
+
+func TestFileOpsOverwrite(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation string
+		overwrite bool
+		wantErr   bool
+	}{
+		{"rename never overwrites", "rename", true, true},
+		{"move refuses without overwrite", "move", false, true},
+		{"move overwrites when asked", "move", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, dir := newTestWorkspace(t)
+			writeTestFile(t, dir, "new.txt", "new\n")
+			dst := writeTestFile(t, dir, "old.txt", "old\n")
+
+			input := FileOpsToolInput{Operation: tt.operation, Source: "new.txt", Destination: "old.txt", Overwrite: tt.overwrite}
+			_, err := FileOpsTool(ctx, mustMarshal(t, input))
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "already exists") {
+					t.Errorf("expected an already exists error, got %v", err)
+				}
+				if got := readTestFile(t, dst); got != "old\n" {
+					t.Errorf("destination was replaced: %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := readTestFile(t, dst); got != "new\n" {
+				t.Errorf("destination = %q, want the moved file", got)
+			}
+		})
+	}
+}
+
+// writeCopyFixture writes a source tree whose last entry, a dangling symlink, fails to copy
+func writeCopyFixture(t *testing.T, dir string) {
+	t.Helper()
+	writeTestFile(t, dir, "src/a.txt", "a\n")
+	writeTestFile(t, dir, "src/nested/b.txt", "b\n")
+	if err := os.Symlink(filepath.Join(dir, "missing"), filepath.Join(dir, "src", "z-broken")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+}
+
+func TestFileOpsCopyRollsBackOnFailure(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeCopyFixture(t, dir)
+
+	_, err := FileOpsTool(ctx, mustMarshal(t, FileOpsToolInput{Operation: "copy", Source: "src", Destination: "dst", Recursive: true}))
+	if err == nil || !strings.Contains(err.Error(), "z-broken") {
+		t.Fatalf("expected the copy to fail on the broken symlink, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dst")); !os.IsNotExist(err) {
+		t.Error("the partially copied destination was left behind")
+	}
+}
+
+func TestFileOpsCopyRollbackKeepsExistingFiles(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeCopyFixture(t, dir)
+	existing := writeTestFile(t, dir, "dst/keep.txt", "keep\n")
+	overwritten := writeTestFile(t, dir, "dst/a.txt", "old\n")
+
+	if _, err := FileOpsTool(ctx, mustMarshal(t, FileOpsToolInput{Operation: "copy", Source: "src", Destination: "dst", Recursive: true})); err == nil {
+		t.Fatal("expected the copy to fail")
+	}
+	if got := readTestFile(t, existing); got != "keep\n" {
+		t.Errorf("pre-existing file changed: %q", got)
+	}
+	if _, err := os.Stat(overwritten); err != nil {
+		t.Errorf("a pre-existing file was removed by the rollback: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dst", "nested")); !os.IsNotExist(err) {
+		t.Error("a directory created by the failed copy was left behind")
+	}
+}
+
+func TestFileOpsCopyContinueOnError(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeCopyFixture(t, dir)
+
+	input := FileOpsToolInput{Operation: "copy", Source: "src", Destination: "dst", Recursive: true, ContinueOnError: true}
+	result, err := FileOpsTool(ctx, mustMarshal(t, input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "with 1 failure(s)") || !strings.Contains(result, "z-broken") {
+		t.Errorf("result should list the failed entry: %s", result)
+	}
+	if got := readTestFile(t, filepath.Join(dir, "dst", "nested", "b.txt")); got != "b\n" {
+		t.Errorf("the rest of the tree wasn't copied: %q", got)
+	}
+}
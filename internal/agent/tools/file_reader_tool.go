@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -16,14 +17,14 @@ var FileReaderToolDefinition = ToolDefinition{
 
 // FileReaderInput defines the input parameters for the read_file tool
 type FileReaderInput struct {
-	Path string `json:"path" jsonschema_description:"The relative path of a file in the working directory."`
+	Path string `json:"path" jsonschema_required:"true" jsonschema_description:"The relative path of a file in the working directory." jsonschema_example:"internal/agent/agent.go"`
 }
 
 // FileReaderInputSchema is the JSON schema for the read_file tool
 var FileReaderInputSchema = GenerateSchema[FileReaderInput]()
 
 // ReadFileContent implements the read_file tool functionality
-func ReadFileContent(input json.RawMessage) (string, error) {
+func ReadFileContent(ctx context.Context, input json.RawMessage) (string, error) {
 	readFileInput := FileReaderInput{}
 	err := json.Unmarshal(input, &readFileInput)
 	if err != nil {
@@ -34,10 +35,19 @@ func ReadFileContent(input json.RawMessage) (string, error) {
 		return "", fmt.Errorf("path parameter is required")
 	}
 
+	readFileInput.Path, err = ResolvePath(ctx, readFileInput.Path)
+	if err != nil {
+		return "", err
+	}
+
 	content, err := os.ReadFile(readFileInput.Path)
 	if err != nil {
 		return "", fmt.Errorf("failed to read file '%s': %w", readFileInput.Path, err)
 	}
+	recordReadHash(readFileInput.Path, content)
 
+	if warning := encodingWarning(content); warning != "" {
+		return fmt.Sprintf("Warning: %s\n\n%s", warning, content), nil
+	}
 	return string(content), nil
 }
@@ -0,0 +1,299 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FileSummaryToolDefinition defines the summarize_file tool
+var FileSummaryToolDefinition = ToolDefinition{
+	Name: "summarize_file",
+	Description: `Return a structural overview of a file instead of its full content.
+- Go files: package name, imports, and every top-level declaration with its signature
+- Markdown files: the heading outline
+- JSON files: the top-level keys (or array length)
+- YAML files: the top-level keys
+Other files report their size and line count. Use this before file_reader on large files.`,
+	InputSchema: FileSummaryInputSchema,
+	Function:    SummarizeFile,
+}
+
+// FileSummaryInput defines the input parameters for the summarize_file tool
+type FileSummaryInput struct {
+	Path string `json:"path" jsonschema_required:"true" jsonschema_description:"The relative path of the file to summarize" jsonschema_example:"internal/agent/agent.go"`
+}
+
+// FileSummaryInputSchema is the JSON schema for the summarize_file tool
+var FileSummaryInputSchema = GenerateSchema[FileSummaryInput]()
+
+// FileDeclaration describes a top-level Go declaration
+type FileDeclaration struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+	Line      int    `json:"line"`
+}
+
+// FileSummaryOutput represents the structured output of the summarize_file tool
+type FileSummaryOutput struct {
+	Path         string            `json:"path"`
+	Kind         string            `json:"kind"`
+	Lines        int               `json:"lines"`
+	Bytes        int               `json:"bytes"`
+	Package      string            `json:"package,omitempty"`
+	Imports      []string          `json:"imports,omitempty"`
+	Declarations []FileDeclaration `json:"declarations,omitempty"`
+	Outline      []string          `json:"outline,omitempty"`
+}
+
+// SummarizeFile implements the summarize_file tool functionality
+func SummarizeFile(ctx context.Context, input json.RawMessage) (string, error) {
+	summaryInput := FileSummaryInput{}
+	err := json.Unmarshal(input, &summaryInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if summaryInput.Path == "" {
+		return "", fmt.Errorf("path parameter is required")
+	}
+
+	summaryInput.Path, err = ResolvePath(ctx, summaryInput.Path)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(summaryInput.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file '%s': %w", summaryInput.Path, err)
+	}
+
+	output := FileSummaryOutput{
+		Path:  summaryInput.Path,
+		Kind:  "text",
+		Lines: bytes.Count(content, []byte("\n")),
+		Bytes: len(content),
+	}
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		output.Lines++
+	}
+
+	switch strings.ToLower(filepath.Ext(summaryInput.Path)) {
+	case ".go":
+		output.Kind = "go"
+		if err := summarizeGoFile(summaryInput.Path, content, &output); err != nil {
+			return "", err
+		}
+	case ".md", ".markdown":
+		output.Kind = "markdown"
+		output.Outline = markdownOutline(string(content))
+	case ".json":
+		output.Kind = "json"
+		output.Outline = jsonOutline(content)
+	case ".yaml", ".yml":
+		output.Kind = "yaml"
+		output.Outline = yamlOutline(string(content))
+	default:
+		if isBinary(content) {
+			output.Kind = "binary"
+		}
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// summarizeGoFile fills in the package, imports, and declarations of a Go file
+func summarizeGoFile(path string, content []byte, output *FileSummaryOutput) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, content, parser.SkipObjectResolution)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	output.Package = file.Name.Name
+	for _, imp := range file.Imports {
+		output.Imports = append(output.Imports, strings.Trim(imp.Path.Value, `"`))
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			kind := "func"
+			name := d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				kind = "method"
+				name = receiverTypeName(d) + "." + name
+			}
+			// Print only the signature, not the body
+			signature := &ast.FuncDecl{Recv: d.Recv, Name: d.Name, Type: d.Type}
+			output.Declarations = append(output.Declarations, FileDeclaration{
+				Kind:      kind,
+				Name:      name,
+				Signature: nodeString(fset, signature),
+				Line:      fset.Position(d.Pos()).Line,
+			})
+
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					output.Declarations = append(output.Declarations, FileDeclaration{
+						Kind:      "type",
+						Name:      s.Name.Name,
+						Signature: "type " + s.Name.Name + " " + typeKind(s.Type),
+						Line:      fset.Position(s.Pos()).Line,
+					})
+				case *ast.ValueSpec:
+					kind := "var"
+					if d.Tok == token.CONST {
+						kind = "const"
+					}
+					for _, name := range s.Names {
+						signature := kind + " " + name.Name
+						if s.Type != nil {
+							signature += " " + exprString(fset, s.Type)
+						}
+						output.Declarations = append(output.Declarations, FileDeclaration{
+							Kind:      kind,
+							Name:      name.Name,
+							Signature: signature,
+							Line:      fset.Position(name.Pos()).Line,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// nodeString renders any AST node back to Go source
+func nodeString(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// typeKind summarizes a type expression without its full body
+func typeKind(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StructType:
+		return fmt.Sprintf("struct (%d fields)", t.Fields.NumFields())
+	case *ast.InterfaceType:
+		return fmt.Sprintf("interface (%d methods)", t.Methods.NumFields())
+	case *ast.FuncType:
+		return "func"
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			return pkg.Name + "." + t.Sel.Name
+		}
+	case *ast.MapType:
+		return "map"
+	case *ast.ArrayType:
+		return "slice"
+	case *ast.ChanType:
+		return "chan"
+	}
+	return "type"
+}
+
+// markdownHeadingPattern matches ATX-style Markdown headings
+var markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+// markdownOutline returns the headings of a Markdown document, indented by level
+func markdownOutline(content string) []string {
+	var outline []string
+	inFence := false
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if matches := markdownHeadingPattern.FindStringSubmatch(line); matches != nil {
+			indent := strings.Repeat("  ", len(matches[1])-1)
+			outline = append(outline, indent+matches[1]+" "+matches[2])
+		}
+	}
+
+	return outline
+}
+
+// jsonOutline returns the sorted top-level keys of a JSON object, or a description otherwise
+func jsonOutline(content []byte) []string {
+	var value interface{}
+	if err := json.Unmarshal(content, &value); err != nil {
+		return []string{fmt.Sprintf("invalid JSON: %v", err)}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key, item := range v {
+			keys = append(keys, fmt.Sprintf("%s (%s)", key, jsonKind(item)))
+		}
+		sort.Strings(keys)
+		return keys
+	case []interface{}:
+		return []string{fmt.Sprintf("array with %d element(s)", len(v))}
+	default:
+		return []string{jsonKind(v)}
+	}
+}
+
+// jsonKind names the JSON type of a decoded value
+func jsonKind(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return fmt.Sprintf("object, %d keys", len(v))
+	case []interface{}:
+		return fmt.Sprintf("array, %d items", len(v))
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	default:
+		return "null"
+	}
+}
+
+// yamlKeyPattern matches an unindented YAML mapping key
+var yamlKeyPattern = regexp.MustCompile(`^([A-Za-z0-9_."'-][^:#]*):(\s|$)`)
+
+// yamlOutline returns the top-level keys of a YAML document
+func yamlOutline(content string) []string {
+	var outline []string
+	for _, line := range strings.Split(content, "\n") {
+		if matches := yamlKeyPattern.FindStringSubmatch(line); matches != nil {
+			outline = append(outline, strings.TrimSpace(matches[1]))
+		}
+	}
+	return outline
+}
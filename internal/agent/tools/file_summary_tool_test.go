@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const summaryGoFixture = `package store
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotFound is returned for missing keys
+var ErrNotFound = errors.New("not found")
+
+const defaultSize int = 16
+
+// Store is a concurrency-safe map
+type Store struct {
+	mu    sync.Mutex
+	items map[string]string
+}
+
+// Get returns the value stored under key
+func (s *Store) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.items[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// New creates an empty store
+func New() *Store {
+	return &Store{items: make(map[string]string, defaultSize)}
+}
+`
+
+func TestSummarizeGoFile(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "store.go", summaryGoFixture)
+
+	var output FileSummaryOutput
+	callTool(t, ctx, SummarizeFile, FileSummaryInput{Path: "store.go"}, &output)
+
+	if output.Kind != "go" || output.Package != "store" {
+		t.Errorf("got kind %q package %q, want go/store", output.Kind, output.Package)
+	}
+	if !reflect.DeepEqual(output.Imports, []string{"errors", "sync"}) {
+		t.Errorf("imports = %v", output.Imports)
+	}
+
+	want := []FileDeclaration{
+		{Kind: "var", Name: "ErrNotFound", Signature: "var ErrNotFound", Line: 9},
+		{Kind: "const", Name: "defaultSize", Signature: "const defaultSize int", Line: 11},
+		{Kind: "type", Name: "Store", Signature: "type Store struct (2 fields)", Line: 14},
+		{Kind: "method", Name: "Store.Get", Signature: "func (s *Store) Get(key string) (string, error)", Line: 20},
+		{Kind: "func", Name: "New", Signature: "func New() *Store", Line: 31},
+	}
+	if !reflect.DeepEqual(output.Declarations, want) {
+		t.Errorf("declarations =\n%+v\nwant\n%+v", output.Declarations, want)
+	}
+	for _, decl := range output.Declarations {
+		if strings.Contains(decl.Signature, "Lock") {
+			t.Errorf("signature includes the function body: %q", decl.Signature)
+		}
+	}
+}
+
+func TestSummarizeMarkdownFile(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "README.md", "# Metamorph\n\nIntro.\n\n## Install\n\n```sh\n# not a heading\ngo install\n```\n\n### From source ###\n\n## Usage\n")
+
+	var output FileSummaryOutput
+	callTool(t, ctx, SummarizeFile, FileSummaryInput{Path: "README.md"}, &output)
+
+	want := []string{"# Metamorph", "  ## Install", "    ### From source", "  ## Usage"}
+	if output.Kind != "markdown" || !reflect.DeepEqual(output.Outline, want) {
+		t.Errorf("got kind %q outline %q, want %q", output.Kind, output.Outline, want)
+	}
+	if output.Lines != 14 {
+		t.Errorf("lines = %d, want 14", output.Lines)
+	}
+}
+
+func TestSummarizeDataFiles(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "config.json", `{"name": "app", "ports": [80, 443], "tls": {"enabled": true}}`)
+	writeTestFile(t, dir, "config.yaml", "name: app\nports:\n  - 80\n# comment: ignored\ntls:\n  enabled: true\n")
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"config.json", []string{"name (string)", "ports (array, 2 items)", "tls (object, 1 keys)"}},
+		{"config.yaml", []string{"name", "ports", "tls"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var output FileSummaryOutput
+			callTool(t, ctx, SummarizeFile, FileSummaryInput{Path: tt.path}, &output)
+			if !reflect.DeepEqual(output.Outline, tt.want) {
+				t.Errorf("outline = %q, want %q", output.Outline, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FindDuplicatesToolDefinition defines the find_duplicates tool
+var FindDuplicatesToolDefinition = ToolDefinition{
+	Name: "find_duplicates",
+	Description: `Find copy-pasted code in Go files: blocks of at least 'min_lines' lines that appear in more
+than one place. Files are tokenized, so formatting and comments don't matter; lines holding only
+closing brackets and the package and import declarations are not counted. Set 'normalize' to also
+match copies whose identifiers and literals were renamed or changed. Returns each duplicated block
+with all of its locations, largest first, as candidates for extracting a shared function.
+Test files are skipped unless 'include_tests' is set.`,
+	InputSchema: FindDuplicatesInputSchema,
+	Function:    FindDuplicates,
+}
+
+// FindDuplicatesInput defines the input parameters for the find_duplicates tool
+type FindDuplicatesInput struct {
+	Path         string `json:"path,omitempty" jsonschema_description:"File or directory to search. Defaults to the current directory."`
+	MinLines     int    `json:"min_lines,omitempty" jsonschema_description:"Minimum number of lines in a duplicated block. Defaults to 6; lower finds more, smaller duplicates."`
+	Normalize    bool   `json:"normalize,omitempty" jsonschema_description:"If true, ignore identifier names and literal values when comparing lines"`
+	IncludeTests bool   `json:"include_tests,omitempty" jsonschema_description:"If true, also search _test.go files"`
+	MaxResults   int    `json:"max_results,omitempty" jsonschema_description:"Maximum number of duplicated blocks to return. Defaults to 50."`
+}
+
+// FindDuplicatesInputSchema is the JSON schema for the find_duplicates tool
+var FindDuplicatesInputSchema = GenerateSchema[FindDuplicatesInput]()
+
+// DuplicateLocation is one occurrence of a duplicated block
+type DuplicateLocation struct {
+	File      string `json:"file"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+// DuplicateBlock is a block of code found in several places
+type DuplicateBlock struct {
+	Lines     int                 `json:"lines"`
+	Locations []DuplicateLocation `json:"locations"`
+}
+
+// FindDuplicatesOutput represents the structured output of the find_duplicates tool
+type FindDuplicatesOutput struct {
+	FilesScanned int              `json:"files_scanned"`
+	Duplicates   []DuplicateBlock `json:"duplicates"`
+	Truncated    bool             `json:"truncated,omitempty"`
+	Message      string           `json:"message"`
+}
+
+const (
+	defaultDuplicateMinLines   = 6
+	defaultDuplicateMaxResults = 50
+	// maxDuplicateOccurrences skips windows repeated more often, which are boilerplate rather
+	// than copies worth extracting and would make pairing them quadratic
+	maxDuplicateOccurrences = 20
+)
+
+// tokenLine is one source line reduced to its tokens
+type tokenLine struct {
+	line int
+	text string
+}
+
+// duplicateRange is a run of token lines in one file, by index into its token lines
+type duplicateRange struct {
+	file       int
+	start, end int
+}
+
+// FindDuplicates implements the find_duplicates tool functionality
+func FindDuplicates(ctx context.Context, input json.RawMessage) (string, error) {
+	dupInput := FindDuplicatesInput{}
+	err := DecodeInput(input, &dupInput)
+	if err != nil {
+		return "", err
+	}
+
+	root := workspaceDir(ctx)
+	if dupInput.Path != "" {
+		root, err = ResolvePath(ctx, dupInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+	minLines := dupInput.MinLines
+	if minLines <= 0 {
+		minLines = defaultDuplicateMinLines
+	}
+	maxResults := dupInput.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultDuplicateMaxResults
+	}
+
+	paths, err := goFilesUnder(ctx, root)
+	if err != nil {
+		return "", err
+	}
+	// Locations are reported relative to the searched directory
+	baseDir := root
+	if info, err := os.Stat(root); err == nil && !info.IsDir() {
+		baseDir = filepath.Dir(root)
+	}
+	var files []string
+	var lines [][]tokenLine
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") && !dupInput.IncludeTests {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if rel, err := filepath.Rel(baseDir, path); err == nil {
+			files = append(files, rel)
+		} else {
+			files = append(files, path)
+		}
+		lines = append(lines, tokenizeLines(content, dupInput.Normalize))
+	}
+
+	// Index every window of minLines consecutive lines by its content
+	windows := map[string][]duplicateRange{}
+	for file, fileLines := range lines {
+		for start := 0; start+minLines <= len(fileLines); start++ {
+			var key strings.Builder
+			for _, line := range fileLines[start : start+minLines] {
+				key.WriteString(line.text)
+				key.WriteByte('\n')
+			}
+			windows[key.String()] = append(windows[key.String()], duplicateRange{file: file, start: start, end: start + minLines - 1})
+		}
+	}
+
+	// Matching windows at a constant offset from each other form one longer duplicated run
+	type runKey struct{ fileA, fileB, offset int }
+	runs := map[runKey][]int{}
+	for _, occurrences := range windows {
+		if len(occurrences) < 2 || len(occurrences) > maxDuplicateOccurrences {
+			continue
+		}
+		for i, a := range occurrences {
+			for _, b := range occurrences[i+1:] {
+				key := runKey{a.file, b.file, b.start - a.start}
+				runs[key] = append(runs[key], a.start)
+			}
+		}
+	}
+
+	// Union the two sides of each run, so a block copied three times is reported once
+	parent := map[duplicateRange]duplicateRange{}
+	var find func(r duplicateRange) duplicateRange
+	find = func(r duplicateRange) duplicateRange {
+		if p, found := parent[r]; found && p != r {
+			root := find(p)
+			parent[r] = root
+			return root
+		}
+		parent[r] = r
+		return r
+	}
+	for key, starts := range runs {
+		sort.Ints(starts)
+		for i := 0; i < len(starts); {
+			j := i
+			for j+1 < len(starts) && starts[j+1] == starts[j]+1 {
+				j++
+			}
+			a := duplicateRange{file: key.fileA, start: starts[i], end: starts[j] + minLines - 1}
+			b := duplicateRange{file: key.fileB, start: a.start + key.offset, end: a.end + key.offset}
+			// Skip a block overlapping its own copy, as in runs of similar lines
+			if a.file != b.file || a.end < b.start {
+				parent[find(b)] = find(a)
+			}
+			i = j + 1
+		}
+	}
+	groups := map[duplicateRange][]duplicateRange{}
+	for r := range parent {
+		groups[find(r)] = append(groups[find(r)], r)
+	}
+
+	output := FindDuplicatesOutput{FilesScanned: len(files), Duplicates: []DuplicateBlock{}}
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		sort.Slice(members, func(i, j int) bool {
+			if members[i].file != members[j].file {
+				return files[members[i].file] < files[members[j].file]
+			}
+			return members[i].start < members[j].start
+		})
+		block := DuplicateBlock{Lines: members[0].end - members[0].start + 1}
+		for _, member := range members {
+			fileLines := lines[member.file]
+			block.Locations = append(block.Locations, DuplicateLocation{
+				File:      files[member.file],
+				StartLine: fileLines[member.start].line,
+				EndLine:   fileLines[member.end].line,
+			})
+		}
+		output.Duplicates = append(output.Duplicates, block)
+	}
+	sort.Slice(output.Duplicates, func(i, j int) bool {
+		a, b := output.Duplicates[i], output.Duplicates[j]
+		if a.Lines != b.Lines {
+			return a.Lines > b.Lines
+		}
+		if a.Locations[0].File != b.Locations[0].File {
+			return a.Locations[0].File < b.Locations[0].File
+		}
+		return a.Locations[0].StartLine < b.Locations[0].StartLine
+	})
+	found := len(output.Duplicates)
+	if found > maxResults {
+		output.Duplicates = output.Duplicates[:maxResults]
+		output.Truncated = true
+	}
+
+	switch {
+	case found == 0:
+		output.Message = fmt.Sprintf("No blocks of %d or more duplicated lines found in %d file(s).", minLines, len(files))
+	case output.Truncated:
+		output.Message = fmt.Sprintf("Found %d duplicated block(s) of %d or more lines in %d file(s); showing the %d largest.", found, minLines, len(files), maxResults)
+	default:
+		output.Message = fmt.Sprintf("Found %d duplicated block(s) of %d or more lines in %d file(s).", found, minLines, len(files))
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// tokenizeLines reduces Go source to its lines of tokens, without comments, the package clause,
+// import declarations, and lines holding only closing brackets. With normalize, identifiers and
+// literals are replaced by placeholders.
+func tokenizeLines(src []byte, normalize bool) []tokenLine {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, src, nil, 0)
+
+	var lines []tokenLine
+	var current []string
+	currentLine := 0
+	closingOnly := true
+	flush := func() {
+		if len(current) > 0 && !closingOnly {
+			lines = append(lines, tokenLine{line: currentLine, text: strings.Join(current, " ")})
+		}
+		current = nil
+		closingOnly = true
+	}
+
+	skipping := token.ILLEGAL // the keyword whose declaration is being skipped
+	depth := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		// Automatically inserted semicolons end lines but aren't part of them
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+
+		if tok == token.PACKAGE || tok == token.IMPORT {
+			skipping = tok
+			continue
+		}
+		if skipping != token.ILLEGAL {
+			switch tok {
+			case token.LPAREN:
+				depth++
+			case token.RPAREN:
+				depth--
+			}
+			if depth == 0 && (tok == token.IDENT && skipping == token.PACKAGE || tok == token.STRING || tok == token.RPAREN) {
+				skipping = token.ILLEGAL
+			}
+			continue
+		}
+
+		line := file.Line(pos)
+		if line != currentLine {
+			flush()
+			currentLine = line
+		}
+		text := lit
+		switch {
+		case normalize && tok == token.IDENT:
+			text = "$id"
+		case normalize && tok.IsLiteral():
+			text = "$lit"
+		case text == "":
+			text = tok.String()
+		}
+		current = append(current, text)
+		if tok != token.RBRACE && tok != token.RPAREN && tok != token.RBRACK && tok != token.COMMA && tok != token.SEMICOLON {
+			closingOnly = false
+		}
+	}
+	flush()
+	return lines
+}
@@ -0,0 +1,155 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const duplicateSumGo = `package dup
+
+import "fmt"
+
+// Sum adds up the non-negative values.
+func Sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		if v < 0 {
+			continue
+		}
+		total += v
+	}
+	fmt.Println(total)
+	return total
+}
+`
+
+// duplicateTotalGo copies the body of Sum with different formatting and comments
+const duplicateTotalGo = `package dup
+
+import (
+	"fmt"
+)
+
+func helper() {}
+
+// Total is a copy of Sum.
+func Total(values []int) int {
+	total := 0 // running total
+	for _, v := range values {
+		if v < 0 {
+			continue
+		}
+		total += v
+	}
+	fmt.Println(total)
+	return total
+}
+`
+
+func TestFindDuplicatesAcrossFiles(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "dup/sum.go", duplicateSumGo)
+	writeTestFile(t, dir, "dup/total.go", duplicateTotalGo)
+	writeTestFile(t, dir, "dup/other.go", "package dup\n\nfunc Other() int {\n\treturn 1\n}\n")
+
+	var output FindDuplicatesOutput
+	callTool(t, ctx, FindDuplicates, FindDuplicatesInput{Path: "dup"}, &output)
+
+	if output.FilesScanned != 3 {
+		t.Errorf("scanned %d files, want 3", output.FilesScanned)
+	}
+	want := []DuplicateBlock{{Lines: 7, Locations: []DuplicateLocation{
+		{File: "sum.go", StartLine: 7, EndLine: 15},
+		{File: "total.go", StartLine: 11, EndLine: 19},
+	}}}
+	if !reflect.DeepEqual(output.Duplicates, want) {
+		t.Errorf("got duplicates %+v, want %+v", output.Duplicates, want)
+	}
+
+	// A larger minimum block size than the copy finds nothing
+	output = FindDuplicatesOutput{}
+	callTool(t, ctx, FindDuplicates, FindDuplicatesInput{Path: "dup", MinLines: 8}, &output)
+	if len(output.Duplicates) != 0 || !strings.HasPrefix(output.Message, "No blocks of 8") {
+		t.Errorf("got %+v, want no duplicates", output)
+	}
+}
+
+func TestFindDuplicatesNormalize(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "sum.go", duplicateSumGo)
+	// The signature differs in its names, so it only matches when identifiers are ignored
+	writeTestFile(t, dir, "total.go", duplicateTotalGo)
+
+	var output FindDuplicatesOutput
+	callTool(t, ctx, FindDuplicates, FindDuplicatesInput{MinLines: 8}, &output)
+	if len(output.Duplicates) != 0 {
+		t.Fatalf("got %+v without normalize, want no duplicates", output.Duplicates)
+	}
+
+	callTool(t, ctx, FindDuplicates, FindDuplicatesInput{MinLines: 8, Normalize: true}, &output)
+	want := []DuplicateBlock{{Lines: 8, Locations: []DuplicateLocation{
+		{File: "sum.go", StartLine: 6, EndLine: 15},
+		{File: "total.go", StartLine: 10, EndLine: 19},
+	}}}
+	if !reflect.DeepEqual(output.Duplicates, want) {
+		t.Errorf("got duplicates %+v, want %+v", output.Duplicates, want)
+	}
+}
+
+func TestFindDuplicatesTestFiles(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "sum.go", duplicateSumGo)
+	writeTestFile(t, dir, "total.go", duplicateTotalGo)
+	writeTestFile(t, dir, "sum_test.go", strings.Replace(duplicateSumGo, "func Sum(", "func sumForTest(", 1))
+
+	var output FindDuplicatesOutput
+	callTool(t, ctx, FindDuplicates, FindDuplicatesInput{}, &output)
+	if output.FilesScanned != 2 || len(output.Duplicates) != 1 || len(output.Duplicates[0].Locations) != 2 {
+		t.Errorf("got %+v, want the test file skipped", output)
+	}
+
+	// A block copied three times is reported once with all its locations
+	output = FindDuplicatesOutput{}
+	callTool(t, ctx, FindDuplicates, FindDuplicatesInput{IncludeTests: true}, &output)
+	if len(output.Duplicates) != 1 {
+		t.Fatalf("got %+v, want one duplicated block", output.Duplicates)
+	}
+	var files []string
+	for _, location := range output.Duplicates[0].Locations {
+		files = append(files, location.File)
+	}
+	if want := []string{"sum.go", "sum_test.go", "total.go"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("got locations in %v, want %v", files, want)
+	}
+}
+
+func TestTokenizeLines(t *testing.T) {
+	lines := tokenizeLines([]byte(duplicateTotalGo), false)
+	var got []string
+	for _, line := range lines {
+		got = append(got, line.text)
+	}
+	want := []string{
+		"func helper ( ) { }",
+		"func Total ( values [ ] int ) int {",
+		"total := 0",
+		"for _ , v := range values {",
+		"if v < 0 {",
+		"continue",
+		"total += v",
+		"fmt . Println ( total )",
+		"return total",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got lines\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if lines[0].line != 7 || lines[len(lines)-1].line != 19 {
+		t.Errorf("got line numbers %d to %d, want 7 to 19", lines[0].line, lines[len(lines)-1].line)
+	}
+
+	normalized := tokenizeLines([]byte("package p\n\nvar x = \"a\" + 1\n"), true)
+	if len(normalized) != 1 || normalized[0].text != "var $id = $lit + $lit" {
+		t.Errorf("got %+v", normalized)
+	}
+}
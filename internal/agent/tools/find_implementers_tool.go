@@ -0,0 +1,207 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FindImplementersToolDefinition defines the find_implementers tool
+var FindImplementersToolDefinition = ToolDefinition{
+	Name: "find_implementers",
+	Description: `List the concrete types in the module that implement a Go interface.
+Every package under 'path' is type-checked and each named non-interface type is tested against
+the interface, with either a value or a pointer receiver. The interface may be declared in the
+module ('Store' or 'storage.Store') or elsewhere by import path ('io.Reader', 'net/http.Handler').
+Use it to see which types an interface change would affect.`,
+	InputSchema:           FindImplementersInputSchema,
+	Function:              FindImplementers,
+	CountsTowardLoopLimit: true,
+}
+
+// FindImplementersInput defines the input parameters for the find_implementers tool
+type FindImplementersInput struct {
+	Interface string `json:"interface" jsonschema_required:"true" jsonschema_description:"Interface name: 'Name', 'pkg.Name', or 'import/path.Name'" jsonschema_example:"io.Writer"`
+	Path      string `json:"path,omitempty" jsonschema_description:"Module root to search. Defaults to the current directory."`
+}
+
+// FindImplementersInputSchema is the JSON schema for the find_implementers tool
+var FindImplementersInputSchema = GenerateSchema[FindImplementersInput]()
+
+// Implementer is a type that satisfies the interface
+type Implementer struct {
+	Type            string `json:"type"`
+	Package         string `json:"package"`
+	File            string `json:"file"`
+	Line            int    `json:"line"`
+	PointerReceiver bool   `json:"pointer_receiver"`
+}
+
+// FindImplementersOutput represents the structured output of the find_implementers tool
+type FindImplementersOutput struct {
+	Interface    string        `json:"interface"`
+	Methods      []string      `json:"methods"`
+	Implementers []Implementer `json:"implementers"`
+}
+
+// FindImplementers implements the find_implementers tool functionality
+func FindImplementers(ctx context.Context, input json.RawMessage) (string, error) {
+	findInput := FindImplementersInput{}
+	err := json.Unmarshal(input, &findInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if findInput.Interface == "" {
+		return "", fmt.Errorf("interface parameter is required")
+	}
+
+	root := workspaceDir(ctx)
+	if findInput.Path != "" {
+		root, err = ResolvePath(ctx, findInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	modulePath, err := readModulePath(root)
+	if err != nil {
+		return "", err
+	}
+
+	fset := token.NewFileSet()
+	packages, err := parseModulePackages(ctx, fset, root, modulePath)
+	if err != nil {
+		return "", err
+	}
+
+	// Checking every package through one importer keeps type identities consistent across packages
+	moduleImports := newModuleImporter(fset, packages)
+	var checked []*types.Package
+	for _, pkg := range packages {
+		if strings.HasSuffix(pkg.importPath, "_test") {
+			continue
+		}
+		if typesPkg, err := moduleImports.Import(pkg.importPath); err == nil {
+			checked = append(checked, typesPkg)
+		}
+	}
+
+	ifaceName, iface, err := resolveInterface(moduleImports, checked, findInput.Interface)
+	if err != nil {
+		return "", err
+	}
+
+	output := FindImplementersOutput{
+		Interface:    ifaceName,
+		Methods:      []string{},
+		Implementers: []Implementer{},
+	}
+	for i := 0; i < iface.NumMethods(); i++ {
+		output.Methods = append(output.Methods, iface.Method(i).Name())
+	}
+
+	for _, pkg := range checked {
+		scope := pkg.Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || typeName.IsAlias() {
+				continue
+			}
+			named, ok := typeName.Type().(*types.Named)
+			if !ok || named.TypeParams().Len() > 0 || types.IsInterface(named) {
+				continue
+			}
+
+			pointer := false
+			if !types.Implements(named, iface) {
+				if !types.Implements(types.NewPointer(named), iface) {
+					continue
+				}
+				pointer = true
+			}
+
+			position := fset.Position(typeName.Pos())
+			file := position.Filename
+			if rel, err := filepath.Rel(root, file); err == nil {
+				file = filepath.ToSlash(rel)
+			}
+			output.Implementers = append(output.Implementers, Implementer{
+				Type:            name,
+				Package:         pkg.Path(),
+				File:            file,
+				Line:            position.Line,
+				PointerReceiver: pointer,
+			})
+		}
+	}
+	sort.Slice(output.Implementers, func(i, j int) bool {
+		a, b := output.Implementers[i], output.Implementers[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Type < b.Type
+	})
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// resolveInterface finds the interface named by name among the module packages, among the
+// predeclared types, or in the package named by its qualifier when that is an import path
+// outside the module
+func resolveInterface(importer types.Importer, packages []*types.Package, name string) (string, *types.Interface, error) {
+	qualifier, typeName := "", name
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		qualifier, typeName = name[:idx], name[idx+1:]
+	}
+
+	var matches []*types.TypeName
+	for _, pkg := range packages {
+		if qualifier != "" && qualifier != pkg.Path() && qualifier != pkg.Name() {
+			continue
+		}
+		if obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName); ok && types.IsInterface(obj.Type()) {
+			matches = append(matches, obj)
+		}
+	}
+
+	if len(matches) == 0 && qualifier == "" {
+		// Predeclared interfaces such as error
+		if obj, ok := types.Universe.Lookup(typeName).(*types.TypeName); ok && types.IsInterface(obj.Type()) {
+			return obj.Name(), obj.Type().Underlying().(*types.Interface), nil
+		}
+	}
+	if len(matches) == 0 && qualifier != "" {
+		// Not declared in the module, so try the qualifier as an import path
+		if pkg, err := importer.Import(qualifier); err == nil {
+			if obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName); ok && types.IsInterface(obj.Type()) {
+				matches = append(matches, obj)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", nil, fmt.Errorf("interface %s not found; qualify it with its package or import path", name)
+	case 1:
+		obj := matches[0]
+		return obj.Pkg().Path() + "." + obj.Name(), obj.Type().Underlying().(*types.Interface), nil
+	}
+
+	var candidates []string
+	for _, obj := range matches {
+		candidates = append(candidates, obj.Pkg().Path()+"."+obj.Name())
+	}
+	sort.Strings(candidates)
+	return "", nil, fmt.Errorf("interface %s is ambiguous; qualify it with its package: %s", name, strings.Join(candidates, ", "))
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"testing"
+)
+
+// writeImplementersFixture writes a module with a Store interface, two implementations
+// in different packages, and a type that only has some of the methods
+func writeImplementersFixture(t *testing.T, dir string) {
+	t.Helper()
+	testGoModule(t, dir, "example.com/app")
+	writeTestFile(t, dir, "storage/storage.go", `package storage
+
+type Store interface {
+	Get(key string) (string, error)
+	Put(key, value string) error
+}
+
+type MemoryStore struct{ data map[string]string }
+
+func (m MemoryStore) Get(key string) (string, error) { return m.data[key], nil }
+
+func (m MemoryStore) Put(key, value string) error {
+	m.data[key] = value
+	return nil
+}
+
+type ReadOnlyStore struct{}
+
+func (ReadOnlyStore) Get(key string) (string, error) { return "", nil }
+`)
+	writeTestFile(t, dir, "storage/disk/disk.go", `package disk
+
+type DiskStore struct{ path string }
+
+func (d *DiskStore) Get(key string) (string, error) { return "", nil }
+
+func (d *DiskStore) Put(key, value string) error { return nil }
+`)
+}
+
+func TestFindImplementers(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeImplementersFixture(t, dir)
+
+	var output FindImplementersOutput
+	callTool(t, ctx, FindImplementers, FindImplementersInput{Interface: "Store"}, &output)
+
+	if len(output.Methods) != 2 {
+		t.Errorf("methods = %v, want Get and Put", output.Methods)
+	}
+	found := map[string]Implementer{}
+	for _, impl := range output.Implementers {
+		found[impl.Type] = impl
+	}
+	if _, ok := found["ReadOnlyStore"]; ok {
+		t.Error("ReadOnlyStore lacks Put and shouldn't be reported")
+	}
+	memory, ok := found["MemoryStore"]
+	if !ok || memory.PointerReceiver || memory.File != "storage/storage.go" || memory.Line != 8 {
+		t.Errorf("MemoryStore = %+v, want a value receiver at storage/storage.go:8", memory)
+	}
+	disk, ok := found["DiskStore"]
+	if !ok || !disk.PointerReceiver {
+		t.Errorf("DiskStore = %+v, want it found with a pointer receiver", disk)
+	}
+	if len(output.Implementers) != 2 {
+		t.Errorf("implementers = %+v, want exactly two", output.Implementers)
+	}
+}
+
+func TestFindImplementersStandardInterface(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+	writeTestFile(t, dir, "buffer.go", `package app
+
+type Buffer struct{ data []byte }
+
+func (b *Buffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	return len(p), nil
+}
+
+type Counter int
+`)
+
+	var output FindImplementersOutput
+	callTool(t, ctx, FindImplementers, FindImplementersInput{Interface: "io.Writer"}, &output)
+	if len(output.Implementers) != 1 || output.Implementers[0].Type != "Buffer" {
+		t.Errorf("implementers = %+v, want only Buffer", output.Implementers)
+	}
+}
+
+func TestFindImplementersRejectsNonInterface(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeImplementersFixture(t, dir)
+
+	if _, err := FindImplementers(ctx, mustMarshal(t, FindImplementersInput{Interface: "MemoryStore"})); err == nil {
+		t.Error("expected an error for a type that isn't an interface")
+	}
+	if _, err := FindImplementers(ctx, mustMarshal(t, FindImplementersInput{Interface: "Missing"})); err == nil {
+		t.Error("expected an error for an unknown interface")
+	}
+}
@@ -0,0 +1,313 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FixImportsToolDefinition defines the fix_imports tool
+var FixImportsToolDefinition = ToolDefinition{
+	Name: "fix_imports",
+	Description: `Regroup the imports of a Go file: standard library first, then external modules, then packages
+of the current module, with a blank line between groups and imports sorted within each group.
+Separate import declarations are merged into one. The module prefix is read from the nearest go.mod
+unless 'module_prefix' is given. Returns a unified diff of the change; set 'dry_run' to preview it
+without writing the file. Files whose import block contains free-standing comments are refused.`,
+	InputSchema:           FixImportsInputSchema,
+	Function:              FixImports,
+	CountsTowardLoopLimit: true,
+}
+
+// FixImportsInput defines the input parameters for the fix_imports tool
+type FixImportsInput struct {
+	Path         string `json:"path" jsonschema_required:"true" jsonschema_description:"Go file whose imports should be regrouped"`
+	ModulePrefix string `json:"module_prefix,omitempty" jsonschema_description:"Import path prefix of intra-module packages. Defaults to the module path in the nearest go.mod." jsonschema_example:"github.com/acme/project"`
+	DryRun       bool   `json:"dry_run,omitempty" jsonschema_description:"If true, return the diff without writing the file"`
+}
+
+// FixImportsInputSchema is the JSON schema for the fix_imports tool
+var FixImportsInputSchema = GenerateSchema[FixImportsInput]()
+
+// FixImportsOutput represents the structured output of the fix_imports tool
+type FixImportsOutput struct {
+	Path         string `json:"path"`
+	ModulePrefix string `json:"module_prefix,omitempty"`
+	Changed      bool   `json:"changed"`
+	DryRun       bool   `json:"dry_run"`
+	Stdlib       int    `json:"stdlib"`
+	External     int    `json:"external"`
+	Internal     int    `json:"internal"`
+	Diff         string `json:"diff,omitempty"`
+	Message      string `json:"message"`
+}
+
+// importEntry is a single import spec along with the source text it occupies
+type importEntry struct {
+	name string
+	path string
+	text string
+}
+
+// FixImports implements the fix_imports tool functionality
+func FixImports(ctx context.Context, input json.RawMessage) (string, error) {
+	fixInput := FixImportsInput{}
+	err := DecodeInput(input, &fixInput)
+	if err != nil {
+		return "", err
+	}
+
+	if fixInput.Path == "" {
+		return "", fmt.Errorf("path parameter is required")
+	}
+	fixInput.Path, err = ResolvePath(ctx, fixInput.Path)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(fixInput.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat %s: %w", fixInput.Path, err)
+	}
+	if info.IsDir() || filepath.Ext(fixInput.Path) != ".go" {
+		return "", fmt.Errorf("%s is not a Go file", fixInput.Path)
+	}
+
+	content, err := os.ReadFile(fixInput.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	modulePrefix := strings.TrimSuffix(fixInput.ModulePrefix, "/")
+	if modulePrefix == "" {
+		// Without a go.mod every non-stdlib import is treated as external
+		modulePrefix, _ = nearestModulePath(filepath.Dir(fixInput.Path))
+	}
+
+	fixed, groups, err := regroupImports(content, modulePrefix)
+	if err != nil {
+		return "", fmt.Errorf("failed to regroup imports in %s: %w", fixInput.Path, err)
+	}
+
+	output := FixImportsOutput{
+		Path:         fixInput.Path,
+		ModulePrefix: modulePrefix,
+		Changed:      string(fixed) != string(content),
+		DryRun:       fixInput.DryRun,
+		Stdlib:       len(groups[0]),
+		External:     len(groups[1]),
+		Internal:     len(groups[2]),
+		Diff:         unifiedDiff(fixInput.Path, string(content), string(fixed)),
+	}
+
+	switch {
+	case !output.Changed:
+		output.Message = "Imports are already grouped correctly."
+	case fixInput.DryRun:
+		output.Message = "Dry run: imports would be regrouped. The file was not modified."
+	default:
+		if err := os.WriteFile(fixInput.Path, fixed, info.Mode().Perm()); err != nil {
+			return "", fmt.Errorf("failed to write file: %w", err)
+		}
+		recordReadHash(fixInput.Path, fixed)
+		output.Message = "Successfully regrouped imports."
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// regroupImports rewrites the import declarations of src as a single block grouped into
+// stdlib, external and intra-module imports, and returns the formatted source with the groups.
+// A cgo import "C" declaration is left where it is, since its doc comment is the cgo preamble.
+func regroupImports(src []byte, modulePrefix string) ([]byte, [3][]importEntry, error) {
+	var groups [3][]importEntry
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, groups, fmt.Errorf("failed to parse Go source: %w", err)
+	}
+
+	var decls []*ast.GenDecl
+	attached := map[*ast.CommentGroup]bool{}
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT || importsC(genDecl) {
+			continue
+		}
+		decls = append(decls, genDecl)
+		for _, spec := range genDecl.Specs {
+			importSpec := spec.(*ast.ImportSpec)
+			attached[importSpec.Doc] = true
+			attached[importSpec.Comment] = true
+		}
+	}
+	if len(decls) == 0 {
+		return src, groups, nil
+	}
+
+	// Comments not attached to an import would be lost or misplaced by the rewrite
+	for _, group := range file.Comments {
+		if attached[group] {
+			continue
+		}
+		for i, decl := range decls {
+			inside := group.Pos() > decl.Pos() && group.End() < decl.End()
+			if inside || (i > 0 && group == decl.Doc) {
+				return nil, groups, fmt.Errorf("import block contains a free-standing comment at line %d", fset.Position(group.Pos()).Line)
+			}
+		}
+	}
+
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+	seen := map[string]bool{}
+	for _, decl := range decls {
+		for _, spec := range decl.Specs {
+			importSpec := spec.(*ast.ImportSpec)
+			path, err := strconv.Unquote(importSpec.Path.Value)
+			if err != nil {
+				return nil, groups, fmt.Errorf("invalid import path %s: %w", importSpec.Path.Value, err)
+			}
+			entry := importEntry{path: path}
+			if importSpec.Name != nil {
+				entry.name = importSpec.Name.Name
+			}
+			key := entry.name + " " + path
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			start, end := importSpec.Pos(), importSpec.End()
+			var lines []string
+			if importSpec.Doc != nil {
+				for _, line := range strings.Split(string(src[offset(importSpec.Doc.Pos()):offset(importSpec.Doc.End())]), "\n") {
+					lines = append(lines, strings.TrimSpace(line))
+				}
+			}
+			if importSpec.Comment != nil {
+				end = importSpec.Comment.End()
+			}
+			lines = append(lines, string(src[offset(start):offset(end)]))
+			entry.text = strings.Join(lines, "\n\t")
+
+			group := importGroup(path, modulePrefix)
+			groups[group] = append(groups[group], entry)
+		}
+	}
+
+	var block strings.Builder
+	total := 0
+	for i := range groups {
+		sort.SliceStable(groups[i], func(a, b int) bool {
+			if groups[i][a].path != groups[i][b].path {
+				return groups[i][a].path < groups[i][b].path
+			}
+			return groups[i][a].name < groups[i][b].name
+		})
+		total += len(groups[i])
+	}
+	if total == 1 && !strings.Contains(firstEntry(groups).text, "\n") {
+		block.WriteString("import " + firstEntry(groups).text)
+	} else {
+		block.WriteString("import (\n")
+		written := false
+		for _, group := range groups {
+			if len(group) == 0 {
+				continue
+			}
+			if written {
+				block.WriteString("\n")
+			}
+			for _, entry := range group {
+				block.WriteString("\t" + entry.text + "\n")
+			}
+			written = true
+		}
+		block.WriteString(")")
+	}
+
+	// Replace the first declaration with the new block and drop the others, working backwards
+	result := append([]byte(nil), src...)
+	for i := len(decls) - 1; i >= 0; i-- {
+		start, end := offset(decls[i].Pos()), offset(decls[i].End())
+		replacement := block.String()
+		if i > 0 {
+			replacement = ""
+			if end < len(result) && result[end] == '\n' {
+				end++
+			}
+		}
+		result = append(result[:start], append([]byte(replacement), result[end:]...)...)
+	}
+
+	formatted, err := format.Source(result)
+	if err != nil {
+		return nil, groups, fmt.Errorf("regrouped source is invalid Go: %w", err)
+	}
+	return formatted, groups, nil
+}
+
+// importGroup classifies an import path as stdlib (0), external (1) or intra-module (2).
+// Like goimports, paths whose first element has no dot are considered standard library.
+func importGroup(path, modulePrefix string) int {
+	if modulePrefix != "" && (path == modulePrefix || strings.HasPrefix(path, modulePrefix+"/")) {
+		return 2
+	}
+	if first, _, _ := strings.Cut(path, "/"); !strings.Contains(first, ".") {
+		return 0
+	}
+	return 1
+}
+
+// importsC reports whether decl imports the cgo pseudo-package "C"
+func importsC(decl *ast.GenDecl) bool {
+	for _, spec := range decl.Specs {
+		if importSpec, ok := spec.(*ast.ImportSpec); ok && importSpec.Path.Value == `"C"` {
+			return true
+		}
+	}
+	return false
+}
+
+// firstEntry returns the first import in the first non-empty group
+func firstEntry(groups [3][]importEntry) importEntry {
+	for _, group := range groups {
+		if len(group) > 0 {
+			return group[0]
+		}
+	}
+	return importEntry{}
+}
+
+// nearestModulePath returns the module path from the closest go.mod at or above dir
+func nearestModulePath(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(absDir, "go.mod")); err == nil {
+			return readModulePath(absDir)
+		}
+		parent := filepath.Dir(absDir)
+		if parent == absDir {
+			return "", fmt.Errorf("go.mod not found above %s", dir)
+		}
+		absDir = parent
+	}
+}
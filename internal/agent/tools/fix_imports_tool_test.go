@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+const scrambledImports = `package app
+
+import (
+	"example.com/app/internal/store"
+	"os"
+	"github.com/rs/zerolog"
+
+	// fmt is used for printing
+	"fmt"
+	log "github.com/rs/zerolog/log" // aliased
+	"example.com/app/internal/api"
+)
+
+import "strings"
+
+func main() {
+	_ = store.New
+	_ = api.New
+	_ = os.Args
+	_ = zerolog.New
+	_ = log.Print
+	fmt.Println(strings.ToUpper("x"))
+}
+`
+
+const regroupedImports = `package app
+
+import (
+	// fmt is used for printing
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog"
+	log "github.com/rs/zerolog/log" // aliased
+
+	"example.com/app/internal/api"
+	"example.com/app/internal/store"
+)
+
+func main() {
+`
+
+func TestFixImportsRegroupsScrambledBlock(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+	path := writeTestFile(t, dir, "main.go", scrambledImports)
+
+	var output FixImportsOutput
+	callTool(t, ctx, FixImports, FixImportsInput{Path: "main.go"}, &output)
+
+	if !output.Changed || output.ModulePrefix != "example.com/app" {
+		t.Errorf("got %+v, want a change using the go.mod module prefix", output)
+	}
+	if output.Stdlib != 3 || output.External != 2 || output.Internal != 2 {
+		t.Errorf("groups = %d/%d/%d, want 3/2/2", output.Stdlib, output.External, output.Internal)
+	}
+	if !strings.Contains(output.Diff, "+\t\"strings\"") || !strings.Contains(output.Diff, "-import \"strings\"") {
+		t.Errorf("unexpected diff:\n%s", output.Diff)
+	}
+	if content := readTestFile(t, path); !strings.HasPrefix(content, regroupedImports) {
+		t.Errorf("regrouped file:\n%s\nwant it to start with:\n%s", content, regroupedImports)
+	}
+
+	// Running it again finds nothing to change
+	var again FixImportsOutput
+	callTool(t, ctx, FixImports, FixImportsInput{Path: "main.go"}, &again)
+	if again.Changed || again.Diff != "" {
+		t.Errorf("second run = %+v, want no change", again)
+	}
+}
+
+func TestFixImportsDryRunAndModulePrefix(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	src := "package app\n\nimport (\n\t\"example.com/app/internal/api\"\n\t\"fmt\"\n)\n\nvar _ = api.New\nvar _ = fmt.Sprint\n"
+	path := writeTestFile(t, dir, "main.go", src)
+
+	var output FixImportsOutput
+	callTool(t, ctx, FixImports, FixImportsInput{Path: "main.go", ModulePrefix: "example.com/app/", DryRun: true}, &output)
+
+	if !output.Changed || !output.DryRun || output.Internal != 1 {
+		t.Errorf("got %+v, want a previewed change with one intra-module import", output)
+	}
+	if readTestFile(t, path) != src {
+		t.Error("dry run modified the file")
+	}
+
+	// Without a go.mod or prefix, every non-stdlib import is external
+	callTool(t, ctx, FixImports, FixImportsInput{Path: "main.go", DryRun: true}, &output)
+	if output.External != 1 || output.Internal != 0 {
+		t.Errorf("got %+v, want the import counted as external", output)
+	}
+}
+
+func TestFixImportsKeepsCgoImport(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	src := "package app\n\n// #include <stdio.h>\nimport \"C\"\n\nimport (\n\t\"os\"\n\t\"fmt\"\n)\n\nvar _ = fmt.Sprint\nvar _ = os.Args\n"
+	path := writeTestFile(t, dir, "main.go", src)
+
+	var output FixImportsOutput
+	callTool(t, ctx, FixImports, FixImportsInput{Path: "main.go"}, &output)
+
+	want := "package app\n\n// #include <stdio.h>\nimport \"C\"\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n"
+	if content := readTestFile(t, path); !strings.HasPrefix(content, want) {
+		t.Errorf("got:\n%s\nwant the cgo preamble kept in place", content)
+	}
+}
+
+func TestFixImportsErrors(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "comment.go", "package app\n\nimport (\n\t\"os\"\n\n\t// tools\n\n\t\"fmt\"\n)\n")
+	writeTestFile(t, dir, "notes.txt", "text")
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"free-standing comment", "comment.go", "free-standing comment"},
+		{"not Go", "notes.txt", "not a Go file"},
+		{"missing", "missing.go", "failed to stat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FixImports(ctx, mustMarshal(t, FixImportsInput{Path: tt.path}))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportGroup(t *testing.T) {
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"fmt", 0},
+		{"net/http", 0},
+		{"github.com/rs/zerolog", 1},
+		{"example.com/app", 2},
+		{"example.com/app/internal", 2},
+		{"example.com/apple", 1},
+	}
+	for _, tt := range tests {
+		if got := importGroup(tt.path, "example.com/app"); got != tt.want {
+			t.Errorf("importGroup(%q) = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+}
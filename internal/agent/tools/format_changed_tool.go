@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FormatChangedToolDefinition defines the format_changed tool
+var FormatChangedToolDefinition = ToolDefinition{
+	Name: "format_changed",
+	Description: `Format only the Go files changed in git: modified or added in the working tree, staged, or
+untracked. Each file is run through gofmt (with 'simplify', gofmt -s) and, if 'imports' is set, has
+its imports regrouped like fix_imports. Files are rewritten unless 'dry_run' is set; the result for
+each file is 'formatted', 'unchanged' or 'error', with a diff of the change. Formatting a staged file
+changes only the working tree, so such files are marked 'staged' and need to be added again.`,
+	InputSchema:           FormatChangedInputSchema,
+	Function:              FormatChanged,
+	CountsTowardLoopLimit: true,
+}
+
+// FormatChangedInput defines the input parameters for the format_changed tool
+type FormatChangedInput struct {
+	Simplify bool `json:"simplify,omitempty" jsonschema_description:"If true, apply 'gofmt -s' code simplifications"`
+	Imports  bool `json:"imports,omitempty" jsonschema_description:"If true, also regroup imports into stdlib, external and module groups"`
+	DryRun   bool `json:"dry_run,omitempty" jsonschema_description:"If true, report the diffs without writing the files"`
+}
+
+// FormatChangedInputSchema is the JSON schema for the format_changed tool
+var FormatChangedInputSchema = GenerateSchema[FormatChangedInput]()
+
+// FormatChangedFile is the result of formatting one changed file
+type FormatChangedFile struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Staged bool   `json:"staged,omitempty"`
+	Diff   string `json:"diff,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// FormatChangedOutput represents the structured output of the format_changed tool
+type FormatChangedOutput struct {
+	DryRun  bool                `json:"dry_run"`
+	Files   []FormatChangedFile `json:"files"`
+	Message string              `json:"message"`
+}
+
+// FormatChanged implements the format_changed tool functionality
+func FormatChanged(ctx context.Context, input json.RawMessage) (string, error) {
+	formatInput := FormatChangedInput{}
+	err := DecodeInput(input, &formatInput)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := exec.LookPath("gofmt"); err != nil {
+		return "", fmt.Errorf("gofmt not found in PATH: %w", err)
+	}
+
+	files, staged, err := changedGoFiles(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	modulePrefix := ""
+	if formatInput.Imports {
+		modulePrefix, _ = nearestModulePath(workspaceDir(ctx))
+	}
+
+	output := FormatChangedOutput{DryRun: formatInput.DryRun, Files: []FormatChangedFile{}}
+	formatted := 0
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		result := FormatChangedFile{Path: file, Status: "unchanged", Staged: staged[file]}
+		path := filepath.Join(workspaceDir(ctx), file)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		// Files with syntax errors are reported rather than aborting the whole run
+		updated, err := runGofmt(ctx, content, formatInput.Simplify)
+		if err == nil && formatInput.Imports {
+			updated, _, err = regroupImports(updated, modulePrefix)
+		}
+		switch {
+		case err != nil:
+			result.Status = "error"
+			result.Error = err.Error()
+		case !bytes.Equal(updated, content):
+			result.Status = "formatted"
+			result.Diff = unifiedDiff(file, string(content), string(updated))
+			formatted++
+			if !formatInput.DryRun {
+				info, err := os.Stat(path)
+				if err != nil {
+					return "", fmt.Errorf("failed to stat %s: %w", file, err)
+				}
+				if err := os.WriteFile(path, updated, info.Mode().Perm()); err != nil {
+					return "", fmt.Errorf("failed to write %s: %w", file, err)
+				}
+				recordReadHash(path, updated)
+			}
+		}
+		output.Files = append(output.Files, result)
+	}
+
+	switch {
+	case len(files) == 0:
+		output.Message = "No changed Go files."
+	case formatted == 0:
+		output.Message = fmt.Sprintf("All %d changed file(s) are already formatted.", len(files))
+	case formatInput.DryRun:
+		output.Message = fmt.Sprintf("%d of %d changed file(s) need formatting. Run again without 'dry_run' to apply the changes.", formatted, len(files))
+	default:
+		output.Message = fmt.Sprintf("Formatted %d of %d changed file(s).", formatted, len(files))
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// changedGoFiles returns the Go files below the workspace that are modified or added in the
+// working tree, staged, or untracked, relative to the workspace, along with which are staged.
+// Deleted files are left out.
+func changedGoFiles(ctx context.Context) ([]string, map[string]bool, error) {
+	listings := [][]string{
+		{"diff", "--name-only", "--relative", "--diff-filter=ACMR", "--no-renames"},
+		{"diff", "--cached", "--name-only", "--relative", "--diff-filter=ACMR", "--no-renames"},
+		{"ls-files", "--others", "--exclude-standard"},
+	}
+
+	seen := map[string]bool{}
+	staged := map[string]bool{}
+	var files []string
+	for i, args := range listings {
+		out, err := gitCommand(ctx, args...).CombinedOutput()
+		if err != nil {
+			return nil, nil, fmt.Errorf("git %s failed: %s, %w", args[0], strings.TrimSpace(string(out)), err)
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			file := strings.TrimSpace(line)
+			if !strings.HasSuffix(file, ".go") {
+				continue
+			}
+			if i == 1 {
+				staged[file] = true
+			}
+			if seen[file] {
+				continue
+			}
+			seen[file] = true
+			if _, err := os.Stat(filepath.Join(workspaceDir(ctx), file)); err == nil {
+				files = append(files, file)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, staged, nil
+}
@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const unformattedGo = "package app\n\nfunc  A( ) int {\nreturn 1\n}\n"
+
+const formattedGo = "package app\n\nfunc A() int {\n\treturn 1\n}\n"
+
+func TestFormatChangedFormatsOnlyChangedFiles(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	testGoModule(t, dir, "example.com/app")
+	// A committed, untouched file stays as it is even though it isn't formatted
+	writeTestFile(t, dir, "committed.go", unformattedGo)
+	writeTestFile(t, dir, "modified.go", formattedGo)
+	commitTestFiles(t, dir, "initial")
+
+	writeTestFile(t, dir, "modified.go", unformattedGo)
+	writeTestFile(t, dir, "staged.go", unformattedGo)
+	runTestGit(t, dir, "add", "staged.go")
+	writeTestFile(t, dir, "untracked.go", formattedGo)
+	writeTestFile(t, dir, "notes.txt", "not go\n")
+
+	var output FormatChangedOutput
+	callTool(t, ctx, FormatChanged, FormatChangedInput{DryRun: true}, &output)
+	if readTestFile(t, filepath.Join(dir, "modified.go")) != unformattedGo {
+		t.Fatal("dry run rewrote modified.go")
+	}
+
+	output = FormatChangedOutput{}
+	callTool(t, ctx, FormatChanged, FormatChangedInput{}, &output)
+
+	var got []string
+	for _, file := range output.Files {
+		status := file.Path + " " + file.Status
+		if file.Staged {
+			status += " staged"
+		}
+		got = append(got, status)
+	}
+	want := "modified.go formatted, staged.go formatted staged, untracked.go unchanged"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("files = %q, want %q", got, want)
+	}
+	for name, content := range map[string]string{
+		"modified.go":  formattedGo,
+		"staged.go":    formattedGo,
+		"committed.go": unformattedGo,
+	} {
+		if readTestFile(t, filepath.Join(dir, name)) != content {
+			t.Errorf("unexpected content of %s", name)
+		}
+	}
+	if output.Message != "Formatted 2 of 3 changed file(s)." {
+		t.Errorf("message = %q", output.Message)
+	}
+}
+
+func TestFormatChangedReportsSyntaxErrors(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "broken.go", "package app\n\nfunc {\n")
+	writeTestFile(t, dir, "ok.go", unformattedGo)
+
+	var output FormatChangedOutput
+	callTool(t, ctx, FormatChanged, FormatChangedInput{}, &output)
+	if len(output.Files) != 2 || output.Files[0].Status != "error" || output.Files[0].Error == "" || output.Files[1].Status != "formatted" {
+		t.Errorf("files = %+v, want the broken file reported and the other formatted", output.Files)
+	}
+}
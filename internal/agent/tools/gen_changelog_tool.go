@@ -0,0 +1,216 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// GenChangelogToolDefinition defines the gen_changelog tool
+var GenChangelogToolDefinition = ToolDefinition{
+	Name: "gen_changelog",
+	Description: `Generate a Markdown changelog section from the commits since the last tag.
+Reads 'git log <last tag>..HEAD' (the whole history when the repository has no tags), parses each
+subject as a conventional commit ('type(scope)!: summary') and groups the entries by type under
+headings such as Features, Bug Fixes and Documentation. Commits marked with '!' or a
+'BREAKING CHANGE:' footer are also listed under Breaking Changes, and subjects that don't follow
+the convention are collected under Other. Merge commits are skipped. Set 'from' to start from a
+different ref and 'version' to title the section.`,
+	InputSchema: GenChangelogInputSchema,
+	Function:    GenChangelog,
+}
+
+// GenChangelogInput defines the input parameters for the gen_changelog tool
+type GenChangelogInput struct {
+	From    string `json:"from,omitempty" jsonschema_description:"Ref to start after. Defaults to the most recent tag reachable from 'to'." jsonschema_example:"v1.2.0"`
+	To      string `json:"to,omitempty" jsonschema_description:"Ref to end at. Defaults to HEAD."`
+	Version string `json:"version,omitempty" jsonschema_description:"Heading for the section. Defaults to 'Unreleased'." jsonschema_example:"v1.3.0"`
+}
+
+// GenChangelogInputSchema is the JSON schema for the gen_changelog tool
+var GenChangelogInputSchema = GenerateSchema[GenChangelogInput]()
+
+// ChangelogEntry is one commit in the changelog
+type ChangelogEntry struct {
+	Hash     string `json:"hash"`
+	Type     string `json:"type"`
+	Scope    string `json:"scope,omitempty"`
+	Summary  string `json:"summary"`
+	Breaking bool   `json:"breaking,omitempty"`
+}
+
+// GenChangelogOutput represents the structured output of the gen_changelog tool
+type GenChangelogOutput struct {
+	From     string                      `json:"from,omitempty"`
+	To       string                      `json:"to"`
+	Commits  int                         `json:"commits"`
+	Groups   map[string][]ChangelogEntry `json:"groups"`
+	Markdown string                      `json:"markdown"`
+}
+
+// changelogSection pairs a commit type with its heading in the changelog
+type changelogSection struct {
+	Type    string
+	Heading string
+}
+
+// changelogSections lists the known commit types in the order they appear in the changelog
+var changelogSections = []changelogSection{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance"},
+	{"refactor", "Refactoring"},
+	{"revert", "Reverts"},
+	{"docs", "Documentation"},
+	{"test", "Tests"},
+	{"build", "Build"},
+	{"ci", "CI"},
+	{"style", "Style"},
+	{"chore", "Chores"},
+	{"other", "Other"},
+}
+
+// conventionalSubjectPattern parses a conventional commit subject: type, optional scope, '!', summary
+var conventionalSubjectPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+
+// breakingFooterPattern matches a BREAKING CHANGE footer in a commit body
+var breakingFooterPattern = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE:`)
+
+// GenChangelog implements the gen_changelog tool functionality
+func GenChangelog(ctx context.Context, input json.RawMessage) (string, error) {
+	changelogInput := GenChangelogInput{}
+	err := DecodeInput(input, &changelogInput)
+	if err != nil {
+		return "", err
+	}
+
+	to := changelogInput.To
+	if to == "" {
+		to = "HEAD"
+	}
+	for _, ref := range []string{changelogInput.From, to} {
+		if strings.HasPrefix(ref, "-") || strings.ContainsAny(ref, " \t\n") {
+			return "", fmt.Errorf("invalid ref: %q", ref)
+		}
+	}
+	if output, err := gitCommand(ctx, "rev-parse", "--verify", "--quiet", to+"^{commit}").CombinedOutput(); err != nil {
+		return "", fmt.Errorf("unknown ref %q: %s", to, strings.TrimSpace(string(output)))
+	}
+
+	from := changelogInput.From
+	if from == "" {
+		// describe fails when no tag is reachable, in which case the whole history is used
+		if tag, err := gitCommand(ctx, "describe", "--tags", "--abbrev=0", to).Output(); err == nil {
+			from = strings.TrimSpace(string(tag))
+		}
+	} else if output, err := gitCommand(ctx, "rev-parse", "--verify", "--quiet", from+"^{commit}").CombinedOutput(); err != nil {
+		return "", fmt.Errorf("unknown ref %q: %s", from, strings.TrimSpace(string(output)))
+	}
+
+	revRange := to
+	if from != "" {
+		revRange = from + ".." + to
+	}
+	// Fields are separated by US and commits by RS so subjects and bodies can contain anything
+	logOutput, err := gitCommand(ctx, "log", "--no-merges", "--format=%h%x1f%s%x1f%b%x1e", revRange, "--").Output()
+	if err != nil {
+		return "", fmt.Errorf("git log failed: %w", err)
+	}
+
+	output := GenChangelogOutput{From: from, To: to, Groups: map[string][]ChangelogEntry{}}
+	for _, record := range strings.Split(string(logOutput), "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		entry := parseChangelogEntry(fields[0], fields[1], fields[2])
+		output.Groups[entry.Type] = append(output.Groups[entry.Type], entry)
+		output.Commits++
+	}
+
+	version := changelogInput.Version
+	if version == "" {
+		version = "Unreleased"
+	}
+	output.Markdown = renderChangelog(version, time.Now().Format("2006-01-02"), output.Groups)
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// parseChangelogEntry classifies a commit by its conventional-commit subject; subjects with an
+// unknown type or no type at all are classified as 'other'
+func parseChangelogEntry(hash, subject, body string) ChangelogEntry {
+	entry := ChangelogEntry{Hash: hash, Type: "other", Summary: strings.TrimSpace(subject)}
+	entry.Breaking = breakingFooterPattern.MatchString(body)
+
+	matches := conventionalSubjectPattern.FindStringSubmatch(entry.Summary)
+	if matches == nil {
+		return entry
+	}
+	commitType := strings.ToLower(matches[1])
+	for _, section := range changelogSections {
+		if section.Type == commitType {
+			entry.Type = commitType
+			entry.Scope = strings.TrimSpace(matches[2])
+			entry.Summary = matches[4]
+			entry.Breaking = entry.Breaking || matches[3] == "!"
+			break
+		}
+	}
+	return entry
+}
+
+// renderChangelog formats the grouped entries as a Markdown section, breaking changes first
+func renderChangelog(version, date string, groups map[string][]ChangelogEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s (%s)\n", version, date)
+
+	writeEntry := func(entry ChangelogEntry) {
+		b.WriteString("- ")
+		if entry.Scope != "" {
+			fmt.Fprintf(&b, "**%s:** ", entry.Scope)
+		}
+		fmt.Fprintf(&b, "%s (%s)\n", entry.Summary, entry.Hash)
+	}
+
+	var breaking []ChangelogEntry
+	for _, section := range changelogSections {
+		for _, entry := range groups[section.Type] {
+			if entry.Breaking {
+				breaking = append(breaking, entry)
+			}
+		}
+	}
+	if len(breaking) > 0 {
+		b.WriteString("\n### Breaking Changes\n\n")
+		for _, entry := range breaking {
+			writeEntry(entry)
+		}
+	}
+
+	empty := true
+	for _, section := range changelogSections {
+		entries := groups[section.Type]
+		if len(entries) == 0 {
+			continue
+		}
+		empty = false
+		fmt.Fprintf(&b, "\n### %s\n\n", section.Heading)
+		for _, entry := range entries {
+			writeEntry(entry)
+		}
+	}
+	if empty {
+		b.WriteString("\nNo changes.\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+// commitChangelogMessages makes one empty commit per message in dir
+func commitChangelogMessages(t *testing.T, dir string, messages ...string) {
+	t.Helper()
+	for _, message := range messages {
+		runTestGit(t, dir, "commit", "-q", "--allow-empty", "-m", message)
+	}
+}
+
+func TestGenChangelogGroupsCommitsSinceTag(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	commitChangelogMessages(t, dir, "feat: released feature")
+	runTestGit(t, dir, "tag", "v1.0.0")
+	commitChangelogMessages(t, dir,
+		"feat(api): add pagination",
+		"fix: handle empty responses",
+		"docs: describe pagination",
+		"feat!: drop the v0 endpoints",
+		"fix(db): close rows\n\nBREAKING CHANGE: Query now returns an error",
+		"update readme",
+		"wip: experiment",
+	)
+
+	var output GenChangelogOutput
+	callTool(t, ctx, GenChangelog, GenChangelogInput{Version: "v1.1.0"}, &output)
+
+	if output.From != "v1.0.0" || output.To != "HEAD" || output.Commits != 7 {
+		t.Errorf("from %q to %q with %d commits, want v1.0.0..HEAD with 7", output.From, output.To, output.Commits)
+	}
+	counts := map[string]int{}
+	for commitType, entries := range output.Groups {
+		counts[commitType] = len(entries)
+	}
+	if counts["feat"] != 2 || counts["fix"] != 2 || counts["docs"] != 1 || counts["other"] != 2 || len(counts) != 4 {
+		t.Errorf("groups = %v, want 2 feat, 2 fix, 1 docs and 2 other", counts)
+	}
+	if api := output.Groups["feat"][1]; api.Scope != "api" || api.Summary != "add pagination" || api.Breaking {
+		t.Errorf("feat(api) entry = %+v", api)
+	}
+
+	markdown := output.Markdown
+	if !strings.HasPrefix(markdown, "## v1.1.0 (") {
+		t.Errorf("markdown should start with the version heading:\n%s", markdown)
+	}
+	// Breaking changes come first, then the types in their fixed order
+	headings := []string{"### Breaking Changes", "### Features", "### Bug Fixes", "### Documentation", "### Other"}
+	last := -1
+	for _, heading := range headings {
+		index := strings.Index(markdown, heading)
+		if index <= last {
+			t.Errorf("heading %q is missing or out of order:\n%s", heading, markdown)
+		}
+		last = index
+	}
+	breaking := markdown[strings.Index(markdown, "### Breaking Changes"):strings.Index(markdown, "### Features")]
+	if !strings.Contains(breaking, "drop the v0 endpoints") || !strings.Contains(breaking, "**db:** close rows") {
+		t.Errorf("breaking section = %q, want both breaking commits", breaking)
+	}
+	if strings.Contains(markdown, "released feature") {
+		t.Error("commits before the tag should not be listed")
+	}
+}
+
+func TestGenChangelogWithoutTags(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	commitChangelogMessages(t, dir, "chore: initial commit", "feat: first feature")
+
+	var output GenChangelogOutput
+	callTool(t, ctx, GenChangelog, GenChangelogInput{}, &output)
+
+	if output.From != "" || output.Commits != 2 {
+		t.Errorf("from %q with %d commits, want the whole history", output.From, output.Commits)
+	}
+	if !strings.HasPrefix(output.Markdown, "## Unreleased (") || !strings.Contains(output.Markdown, "### Chores") {
+		t.Errorf("unexpected markdown:\n%s", output.Markdown)
+	}
+
+	// A range with no commits still produces a section
+	callTool(t, ctx, GenChangelog, GenChangelogInput{From: "HEAD"}, &output)
+	if output.Commits != 0 || !strings.Contains(output.Markdown, "No changes.") {
+		t.Errorf("got %+v, want an empty section", output)
+	}
+}
+
+func TestGenChangelogInvalidRefs(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	commitChangelogMessages(t, dir, "feat: one")
+
+	for _, input := range []GenChangelogInput{{From: "missing"}, {To: "missing"}, {From: "--all"}} {
+		if _, err := GenChangelog(ctx, mustMarshal(t, input)); err == nil {
+			t.Errorf("%+v: expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GenGitignoreToolDefinition defines the gen_gitignore tool
+var GenGitignoreToolDefinition = ToolDefinition{
+	Name: "gen_gitignore",
+	Description: `Generate a .gitignore for the languages and tools a project uses.
+The project type is detected from marker files in the directory: go.mod (Go), package.json (Node),
+pyproject.toml, setup.py, requirements.txt or Pipfile (Python), Cargo.toml (Rust), pom.xml or
+build.gradle (Java), *.tf (Terraform) and .env files. Editor and OS patterns are always included.
+An existing .gitignore is kept and only the missing patterns are appended, grouped under a
+comment per stack. Returns the detected stacks and the patterns that were added. Set 'dry_run'
+to preview the additions without writing.`,
+	InputSchema:           GenGitignoreInputSchema,
+	Function:              GenGitignore,
+	CountsTowardLoopLimit: true,
+}
+
+// GenGitignoreInput defines the input parameters for the gen_gitignore tool
+type GenGitignoreInput struct {
+	Path   string `json:"path,omitempty" jsonschema_description:"Project directory. Defaults to the current directory."`
+	DryRun bool   `json:"dry_run,omitempty" jsonschema_description:"If true, report the patterns that would be added without writing .gitignore"`
+}
+
+// GenGitignoreInputSchema is the JSON schema for the gen_gitignore tool
+var GenGitignoreInputSchema = GenerateSchema[GenGitignoreInput]()
+
+// GenGitignoreOutput represents the structured output of the gen_gitignore tool
+type GenGitignoreOutput struct {
+	Path     string   `json:"path"`
+	Detected []string `json:"detected"`
+	Added    []string `json:"added"`
+	Created  bool     `json:"created"`
+	DryRun   bool     `json:"dry_run,omitempty"`
+}
+
+// gitignoreStack describes the marker files of a language or tool and the patterns it needs
+type gitignoreStack struct {
+	Name     string
+	Markers  []string
+	Patterns []string
+}
+
+// gitignoreStacks lists the detectable stacks in the order their sections are written. A stack
+// without markers is always included.
+var gitignoreStacks = []gitignoreStack{
+	{
+		Name:     "Go",
+		Markers:  []string{"go.mod", "go.work"},
+		Patterns: []string{"*.exe", "*.dll", "*.so", "*.dylib", "*.test", "*.out", "coverage.*", "/bin/", "go.work.sum"},
+	},
+	{
+		Name:     "Node",
+		Markers:  []string{"package.json"},
+		Patterns: []string{"node_modules/", "dist/", "npm-debug.log*", "yarn-debug.log*", "yarn-error.log*", ".npm/", "coverage/"},
+	},
+	{
+		Name:     "Python",
+		Markers:  []string{"pyproject.toml", "setup.py", "setup.cfg", "requirements.txt", "Pipfile"},
+		Patterns: []string{"__pycache__/", "*.py[cod]", "*.egg-info/", ".venv/", "venv/", "build/", "dist/", ".pytest_cache/", ".mypy_cache/"},
+	},
+	{
+		Name:     "Rust",
+		Markers:  []string{"Cargo.toml"},
+		Patterns: []string{"/target/"},
+	},
+	{
+		Name:     "Java",
+		Markers:  []string{"pom.xml", "build.gradle", "build.gradle.kts"},
+		Patterns: []string{"*.class", "*.jar", "target/", "build/", ".gradle/"},
+	},
+	{
+		Name:     "Terraform",
+		Markers:  []string{"*.tf"},
+		Patterns: []string{".terraform/", "*.tfstate", "*.tfstate.*", "crash.log"},
+	},
+	{
+		Name:     "Environment",
+		Markers:  []string{".env", ".env.*"},
+		Patterns: []string{".env", ".env.local", ".env.*.local"},
+	},
+	{
+		Name:     "Editors and OS files",
+		Patterns: []string{".idea/", ".vscode/", "*.swp", ".DS_Store", "Thumbs.db"},
+	},
+}
+
+// GenGitignore implements the gen_gitignore tool functionality
+func GenGitignore(ctx context.Context, input json.RawMessage) (string, error) {
+	genInput := GenGitignoreInput{}
+	err := DecodeInput(input, &genInput)
+	if err != nil {
+		return "", err
+	}
+
+	dir := workspaceDir(ctx)
+	if genInput.Path != "" {
+		dir, err = ResolvePath(ctx, genInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+
+	gitignorePath := filepath.Join(dir, ".gitignore")
+	existing, err := os.ReadFile(gitignorePath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read %s: %w", gitignorePath, err)
+	}
+
+	present := map[string]bool{}
+	for _, line := range strings.Split(string(existing), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			present[line] = true
+		}
+	}
+
+	output := GenGitignoreOutput{
+		Path:     gitignorePath,
+		Detected: []string{},
+		Added:    []string{},
+		Created:  existing == nil,
+		DryRun:   genInput.DryRun,
+	}
+
+	var additions strings.Builder
+	for _, stack := range gitignoreStacks {
+		if len(stack.Markers) > 0 {
+			if !hasMarker(dir, stack.Markers) {
+				continue
+			}
+			output.Detected = append(output.Detected, stack.Name)
+		}
+
+		var missing []string
+		for _, pattern := range stack.Patterns {
+			// Several stacks share patterns such as build/ and dist/
+			if !present[pattern] {
+				present[pattern] = true
+				missing = append(missing, pattern)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		if additions.Len() > 0 {
+			additions.WriteString("\n")
+		}
+		fmt.Fprintf(&additions, "# %s\n%s\n", stack.Name, strings.Join(missing, "\n"))
+		output.Added = append(output.Added, missing...)
+	}
+
+	if !genInput.DryRun && len(output.Added) > 0 {
+		content := string(existing)
+		if content != "" {
+			if !strings.HasSuffix(content, "\n") {
+				content += "\n"
+			}
+			content += "\n"
+		}
+		content += additions.String()
+		if err := os.WriteFile(gitignorePath, []byte(content), 0644); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", gitignorePath, err)
+		}
+		recordReadHash(gitignorePath, []byte(content))
+	}
+	output.Created = output.Created && !genInput.DryRun && len(output.Added) > 0
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// hasMarker reports whether dir directly contains a file matching any of the marker patterns
+func hasMarker(dir string, markers []string) bool {
+	for _, marker := range markers {
+		matches, err := filepath.Glob(filepath.Join(dir, marker))
+		if err == nil && len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGenGitignoreForGoProject(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+
+	var output GenGitignoreOutput
+	callTool(t, ctx, GenGitignore, GenGitignoreInput{}, &output)
+
+	if !slices.Equal(output.Detected, []string{"Go"}) || !output.Created {
+		t.Errorf("detected %v created %v, want a new file for Go", output.Detected, output.Created)
+	}
+	content := readTestFile(t, output.Path)
+	if !strings.HasPrefix(content, "# Go\n*.exe\n") || !strings.Contains(content, "\n*.test\n") || !strings.Contains(content, "# Editors and OS files\n") {
+		t.Errorf("unexpected .gitignore:\n%s", content)
+	}
+	if strings.Contains(content, "node_modules/") {
+		t.Errorf("a Go project got Node patterns:\n%s", content)
+	}
+}
+
+func TestGenGitignoreMergesExistingFile(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+	writeTestFile(t, dir, "package.json", "{}")
+	writeTestFile(t, dir, "pyproject.toml", "")
+	path := writeTestFile(t, dir, ".gitignore", "# mine\n*.exe\n  .idea/  \nsecrets/")
+
+	var output GenGitignoreOutput
+	callTool(t, ctx, GenGitignore, GenGitignoreInput{}, &output)
+
+	if !slices.Equal(output.Detected, []string{"Go", "Node", "Python"}) || output.Created {
+		t.Errorf("detected %v created %v", output.Detected, output.Created)
+	}
+	content := readTestFile(t, path)
+	if !strings.HasPrefix(content, "# mine\n*.exe\n  .idea/  \nsecrets/\n\n# Go\n*.dll\n") {
+		t.Errorf("existing entries should be kept and followed by the additions:\n%s", content)
+	}
+	for _, pattern := range []string{"*.exe", ".idea/", "dist/"} {
+		if count := strings.Count("\n"+content+"\n", "\n"+pattern+"\n"); count > 1 {
+			t.Errorf("%s appears %d times:\n%s", pattern, count, content)
+		}
+	}
+	if slices.Contains(output.Added, "*.exe") || slices.Contains(output.Added, ".idea/") {
+		t.Errorf("added = %v, want existing patterns skipped", output.Added)
+	}
+
+	// A second run has nothing left to add
+	var again GenGitignoreOutput
+	callTool(t, ctx, GenGitignore, GenGitignoreInput{}, &again)
+	if len(again.Added) != 0 || readTestFile(t, path) != content {
+		t.Errorf("second run added %v", again.Added)
+	}
+}
+
+func TestGenGitignoreDryRun(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "main.tf", "")
+	writeTestFile(t, dir, ".env.production", "")
+
+	var output GenGitignoreOutput
+	callTool(t, ctx, GenGitignore, GenGitignoreInput{DryRun: true}, &output)
+
+	if !slices.Equal(output.Detected, []string{"Terraform", "Environment"}) || !slices.Contains(output.Added, "*.tfstate") {
+		t.Errorf("got %+v, want Terraform and Environment patterns", output)
+	}
+	if output.Created {
+		t.Error("a dry run should not report a created file")
+	}
+	if _, err := ReadFileContent(ctx, mustMarshal(t, FileReaderInput{Path: ".gitignore"})); err == nil {
+		t.Error("a dry run wrote .gitignore")
+	}
+}
@@ -1,34 +1,43 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
 // GitToolDefinition defines the git tool for common Git operations
 var GitOperationsToolDefinition = ToolDefinition{
-	Name:        "git_operations",
-	Description: "Execute common Git operations such as checking status, staging files, committing changes, pulling, pushing, viewing logs, creating branches, and more.",
-	InputSchema: GitToolInputSchema,
-	Function:    GitTool,
+	Name:                  "git_operations",
+	Description:           "Execute common Git operations such as checking status, staging files, committing changes, pulling, pushing, viewing logs, creating branches, and more. Use 'show' with 'revision' and 'path' to read a file as it was at a given commit. Set 'lint_message' to check a commit message against conventional-commit rules before committing, and 'scan_secrets' to refuse commits whose staged changes contain secrets. Use 'worktree_add' with 'path' and 'branch_name' (created from 'revision' if it does not exist) to check out another branch in a separate directory, 'worktree_list' to list worktrees, and 'worktree_remove' with 'path' to delete one.",
+	InputSchema:           GitToolInputSchema,
+	Function:              GitTool,
+	CountsTowardLoopLimit: true,
 }
 
 // GitToolInput defines the input parameters for the git tool
 type GitToolInput struct {
-	Command    string   `json:"command" jsonschema_description:"The Git command to execute (status, add, commit, push, pull, log, branch, checkout, etc.)."`
-	Args       []string `json:"args,omitempty" jsonschema_description:"Optional additional arguments for the Git command."`
-	Message    string   `json:"message,omitempty" jsonschema_description:"Commit message when using the 'commit' command."`
-	Files      []string `json:"files,omitempty" jsonschema_description:"Specific files to operate on (for add, checkout, etc.). Use ['.'] for all files."`
-	BranchName string   `json:"branch_name,omitempty" jsonschema_description:"Branch name when using branch-related commands."`
+	Command     string   `json:"command" jsonschema_required:"true" jsonschema_description:"The Git command to execute (status, add, commit, push, pull, log, branch, checkout, etc.)." jsonschema_example:"status"`
+	Args        []string `json:"args,omitempty" jsonschema_description:"Optional additional arguments for the Git command."`
+	Message     string   `json:"message,omitempty" jsonschema_description:"Commit message when using the 'commit' command."`
+	Files       []string `json:"files,omitempty" jsonschema_description:"Specific files to operate on (for add, checkout, etc.). Use ['.'] for all files."`
+	BranchName  string   `json:"branch_name,omitempty" jsonschema_description:"Branch name when using branch-related commands."`
+	Revision    string   `json:"revision,omitempty" jsonschema_description:"Commit, tag, or branch to read from when using the 'show' command with a path, or to start a new branch from with 'worktree_add'. Defaults to HEAD."`
+	Path        string   `json:"path,omitempty" jsonschema_description:"Repository-relative file path to read at 'revision' when using the 'show' command, or the worktree directory for 'worktree_add' and 'worktree_remove'."`
+	LintMessage bool     `json:"lint_message,omitempty" jsonschema_description:"If true, check the message with the lint_commit_message rules before 'commit' or 'stage_and_commit' and fail without committing on violations."`
+	ScanSecrets bool     `json:"scan_secrets,omitempty" jsonschema_description:"If true, scan the staged changes with scan_secrets before 'commit' or 'stage_and_commit' and fail without committing if any are found."`
 }
 
 // GitToolInputSchema is the JSON schema for the git tool
 var GitToolInputSchema = GenerateSchema[GitToolInput]()
 
 // GitTool implements Git operations functionality
-func GitTool(input json.RawMessage) (string, error) {
+func GitTool(ctx context.Context, input json.RawMessage) (string, error) {
 	gitInput := GitToolInput{}
 	err := json.Unmarshal(input, &gitInput)
 	if err != nil {
@@ -39,26 +48,46 @@ func GitTool(input json.RawMessage) (string, error) {
 		return "", fmt.Errorf("Git command is required")
 	}
 
+	// Bound every git invocation so a hung network operation can't block forever
+	ctx, cancel := context.WithTimeout(ctx, defaultGitTimeout)
+	defer cancel()
+
+	if gitInput.BranchName != "" {
+		if err := validateBranchName(ctx, gitInput.BranchName); err != nil {
+			return "", err
+		}
+	}
+
 	var cmd *exec.Cmd
 
 	switch strings.ToLower(gitInput.Command) {
 	case "status":
-		cmd = exec.Command("git", "status")
+		cmd = gitCommand(ctx, "status")
 
 	case "add":
 		if len(gitInput.Files) == 0 {
 			// Default to all files if none specified
-			cmd = exec.Command("git", "add", ".")
+			cmd = gitCommand(ctx, "add", ".")
 		} else {
 			args := append([]string{"add"}, gitInput.Files...)
-			cmd = exec.Command("git", args...)
+			cmd = gitCommand(ctx, args...)
 		}
 
 	case "commit":
 		if gitInput.Message == "" {
 			return "", fmt.Errorf("commit message is required for 'commit' command")
 		}
-		cmd = exec.Command("git", "commit", "-m", gitInput.Message)
+		if gitInput.LintMessage {
+			if err := commitLintError(lintCommitMessage(LintCommitMessageInput{Message: gitInput.Message})); err != nil {
+				return "", err
+			}
+		}
+		if gitInput.ScanSecrets {
+			if err := checkStagedSecrets(ctx); err != nil {
+				return "", err
+			}
+		}
+		cmd = gitCommand(ctx, "commit", "-m", gitInput.Message)
 
 	case "push":
 		args := []string{"push"}
@@ -68,14 +97,14 @@ func GitTool(input json.RawMessage) (string, error) {
 		if len(gitInput.Args) > 0 {
 			args = append(args, gitInput.Args...)
 		}
-		cmd = exec.Command("git", args...)
+		cmd = gitCommand(ctx, args...)
 
 	case "pull":
 		args := []string{"pull"}
 		if len(gitInput.Args) > 0 {
 			args = append(args, gitInput.Args...)
 		}
-		cmd = exec.Command("git", args...)
+		cmd = gitCommand(ctx, args...)
 
 	case "log":
 		args := []string{"log"}
@@ -85,7 +114,7 @@ func GitTool(input json.RawMessage) (string, error) {
 			// Default to a nicely formatted concise log
 			args = append(args, "--oneline", "--graph", "--decorate", "-n", "10")
 		}
-		cmd = exec.Command("git", args...)
+		cmd = gitCommand(ctx, args...)
 
 	case "branch":
 		args := []string{"branch"}
@@ -95,7 +124,7 @@ func GitTool(input json.RawMessage) (string, error) {
 		if len(gitInput.Args) > 0 {
 			args = append(args, gitInput.Args...)
 		}
-		cmd = exec.Command("git", args...)
+		cmd = gitCommand(ctx, args...)
 
 	case "checkout":
 		if gitInput.BranchName == "" && len(gitInput.Files) == 0 && len(gitInput.Args) == 0 {
@@ -112,34 +141,219 @@ func GitTool(input json.RawMessage) (string, error) {
 		if len(gitInput.Args) > 0 {
 			args = append(args, gitInput.Args...)
 		}
-		cmd = exec.Command("git", args...)
+		cmd = gitCommand(ctx, args...)
+
+	case "show":
+		if gitInput.Path == "" {
+			// Without a path, behave like a plain 'git show'
+			args := append([]string{"show"}, gitInput.Args...)
+			cmd = gitCommand(ctx, args...)
+			break
+		}
+
+		revision := gitInput.Revision
+		if revision == "" {
+			revision = "HEAD"
+		}
+		spec, err := gitShowSpec(ctx, revision, gitInput.Path)
+		if err != nil {
+			return "", err
+		}
+		cmd = gitCommand(ctx, "show", spec)
 
 	case "stage_and_commit":
 		// Convenience command to stage all and commit in one step
 		if gitInput.Message == "" {
 			return "", fmt.Errorf("commit message is required for 'stage_and_commit' command")
 		}
+		if gitInput.LintMessage {
+			if err := commitLintError(lintCommitMessage(LintCommitMessageInput{Message: gitInput.Message})); err != nil {
+				return "", err
+			}
+		}
 
 		// First stage all changes
-		stageCmd := exec.Command("git", "add", ".")
+		stageCmd := gitCommand(ctx, "add", ".")
 		stageOutput, err := stageCmd.CombinedOutput()
 		if err != nil {
 			return "", fmt.Errorf("failed to stage changes: %s, %w", string(stageOutput), err)
 		}
 
-		// Then commit
-		cmd = exec.Command("git", "commit", "-m", gitInput.Message)
+		// Check what was staged (it stays staged if secrets are found), then commit
+		if gitInput.ScanSecrets {
+			if err := checkStagedSecrets(ctx); err != nil {
+				return "", err
+			}
+		}
+		cmd = gitCommand(ctx, "commit", "-m", gitInput.Message)
+
+	case "worktree_add":
+		if gitInput.Path == "" || gitInput.BranchName == "" {
+			return "", fmt.Errorf("path and branch_name are required for 'worktree_add' command")
+		}
+		worktreePath, err := ResolvePath(ctx, gitInput.Path)
+		if err != nil {
+			return "", err
+		}
+
+		args := []string{"worktree", "add"}
+		verifyCmd := gitCommand(ctx, "rev-parse", "--verify", "--quiet", "refs/heads/"+gitInput.BranchName)
+		if verifyCmd.Run() == nil {
+			args = append(args, worktreePath, gitInput.BranchName)
+		} else {
+			// Create the branch as part of adding the worktree
+			args = append(args, "-b", gitInput.BranchName, worktreePath)
+			if gitInput.Revision != "" {
+				if strings.HasPrefix(gitInput.Revision, "-") {
+					return "", fmt.Errorf("invalid revision: %q", gitInput.Revision)
+				}
+				args = append(args, gitInput.Revision)
+			}
+		}
+		cmd = gitCommand(ctx, append(args, gitInput.Args...)...)
+
+	case "worktree_list":
+		listOutput, err := gitCommand(ctx, "worktree", "list", "--porcelain").CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("git command failed: %s, %w", string(listOutput), err)
+		}
+		jsonOutput, err := json.MarshalIndent(parseWorktreeList(string(listOutput)), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal output: %w", err)
+		}
+		return string(jsonOutput), nil
+
+	case "worktree_remove":
+		if gitInput.Path == "" {
+			return "", fmt.Errorf("path is required for 'worktree_remove' command")
+		}
+		worktreePath, err := ResolvePath(ctx, gitInput.Path)
+		if err != nil {
+			return "", err
+		}
+		args := append([]string{"worktree", "remove"}, gitInput.Args...)
+		cmd = gitCommand(ctx, append(args, worktreePath)...)
 
 	default:
 		// For any other Git commands, pass them through
 		args := append([]string{gitInput.Command}, gitInput.Args...)
-		cmd = exec.Command("git", args...)
+		cmd = gitCommand(ctx, args...)
 	}
 
 	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("git command timed out after %s: %s", defaultGitTimeout, string(output))
+	}
 	if err != nil {
 		return "", fmt.Errorf("git command failed: %s, %w", string(output), err)
 	}
 
 	return string(output), nil
 }
+
+// GitWorktree describes one worktree reported by 'git worktree list'
+type GitWorktree struct {
+	Path     string `json:"path"`
+	Head     string `json:"head,omitempty"`
+	Branch   string `json:"branch,omitempty"`
+	Detached bool   `json:"detached,omitempty"`
+	Bare     bool   `json:"bare,omitempty"`
+	Locked   bool   `json:"locked,omitempty"`
+	Prunable bool   `json:"prunable,omitempty"`
+}
+
+// parseWorktreeList parses 'git worktree list --porcelain' output, where each worktree
+// is a block of "key value" lines separated by a blank line
+func parseWorktreeList(output string) []GitWorktree {
+	worktrees := []GitWorktree{}
+	var current *GitWorktree
+	for _, line := range strings.Split(output, "\n") {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch key {
+		case "worktree":
+			worktrees = append(worktrees, GitWorktree{Path: value})
+			current = &worktrees[len(worktrees)-1]
+		case "HEAD":
+			if current != nil {
+				current.Head = value
+			}
+		case "branch":
+			if current != nil {
+				current.Branch = strings.TrimPrefix(value, "refs/heads/")
+			}
+		case "detached":
+			if current != nil {
+				current.Detached = true
+			}
+		case "bare":
+			if current != nil {
+				current.Bare = true
+			}
+		case "locked":
+			if current != nil {
+				current.Locked = true
+			}
+		case "prunable":
+			if current != nil {
+				current.Prunable = true
+			}
+		}
+	}
+	return worktrees
+}
+
+// checkStagedSecrets fails if the staged changes contain possible secrets
+func checkStagedSecrets(ctx context.Context) error {
+	scan, err := scanStagedSecrets(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return secretsError(scan.Findings)
+}
+
+// defaultGitTimeout bounds the runtime of a single git_operations call
+const defaultGitTimeout = 2 * time.Minute
+
+// gitCommand builds a git command bound to ctx that never waits on interactive prompts
+func gitCommand(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = workspaceDir(ctx)
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0", // fail instead of asking for credentials
+		"GCM_INTERACTIVE=never", // same for Git Credential Manager
+	)
+	if os.Getenv("GIT_SSH_COMMAND") == "" {
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND=ssh -o BatchMode=yes")
+	}
+	return cmd
+}
+
+// validateBranchName rejects branch names git would parse as options or refuse as refs
+func validateBranchName(ctx context.Context, name string) error {
+	if strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid branch name: %q", name)
+	}
+	if output, err := gitCommand(ctx, "check-ref-format", "--branch", name).CombinedOutput(); err != nil {
+		return fmt.Errorf("invalid branch name %q: %s", name, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// gitShowSpec validates a revision and path and returns the '<rev>:<path>' object spec
+func gitShowSpec(ctx context.Context, revision, path string) (string, error) {
+	if strings.HasPrefix(revision, "-") || strings.ContainsAny(revision, ": \t\n") {
+		return "", fmt.Errorf("invalid revision: %q", revision)
+	}
+
+	verifyCmd := gitCommand(ctx, "rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	if output, err := verifyCmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("unknown revision %q: %s", revision, strings.TrimSpace(string(output)))
+	}
+
+	cleanPath := filepath.ToSlash(filepath.Clean(path))
+	if filepath.IsAbs(path) || cleanPath == "." || cleanPath == ".." || strings.HasPrefix(cleanPath, "../") {
+		return "", fmt.Errorf("path must be relative to the repository root: %q", path)
+	}
+
+	return revision + ":" + cleanPath, nil
+}
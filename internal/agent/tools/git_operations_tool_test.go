@@ -0,0 +1,255 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGitShowFileAtRevision(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "notes/todo.txt", "first version\n")
+	first := commitTestFiles(t, dir, "first")
+	writeTestFile(t, dir, "notes/todo.txt", "second version\n")
+	commitTestFiles(t, dir, "second")
+
+	output, err := GitTool(ctx, mustMarshal(t, GitToolInput{Command: "show", Revision: first, Path: "notes/todo.txt"}))
+	if err != nil {
+		t.Fatalf("GitTool: %v", err)
+	}
+	if output != "first version\n" {
+		t.Errorf("show at the first commit = %q, want the old content", output)
+	}
+
+	output, err = GitTool(ctx, mustMarshal(t, GitToolInput{Command: "show", Path: "notes/todo.txt"}))
+	if err != nil {
+		t.Fatalf("GitTool: %v", err)
+	}
+	if output != "second version\n" {
+		t.Errorf("show without a revision = %q, want the HEAD content", output)
+	}
+}
+
+func TestGitShowRejectsBadRevisions(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "a.txt", "a\n")
+	commitTestFiles(t, dir, "initial")
+
+	tests := []struct {
+		name     string
+		revision string
+		path     string
+		wantErr  string
+	}{
+		{"option injection", "--output=/tmp/x", "a.txt", "invalid revision"},
+		{"unknown revision", "no-such-branch", "a.txt", "unknown revision"},
+		{"missing file", "HEAD", "missing.txt", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GitTool(ctx, mustMarshal(t, GitToolInput{Command: "show", Revision: tt.revision, Path: tt.path}))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGitPullDoesNotPromptForCredentials(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "a.txt", "a\n")
+	commitTestFiles(t, dir, "initial")
+
+	// Keep the user's credential helpers and askpass programs out of the way
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_ASKPASS", "")
+	t.Setenv("SSH_ASKPASS", "")
+
+	// A remote that demands credentials makes git ask for a username
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="private"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	runTestGit(t, dir, "remote", "add", "origin", server.URL+"/private.git")
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := GitTool(ctx, mustMarshal(t, GitToolInput{Command: "pull", Args: []string{"origin", "main"}}))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected the pull to fail without credentials")
+		}
+		if !strings.Contains(err.Error(), "terminal prompts disabled") {
+			t.Errorf("expected git to refuse to prompt, got %v", err)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("git pull blocked waiting for credentials")
+	}
+}
+
+func TestGitToolHonorsCancellation(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := GitTool(ctx, mustMarshal(t, GitToolInput{Command: "status"})); err == nil {
+		t.Error("expected a cancelled context to stop the git command")
+	}
+}
+
+func TestGitWorktreeAddAndList(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "README.md", "hello\n")
+	head := commitTestFiles(t, dir, "initial")
+
+	if _, err := GitTool(ctx, mustMarshal(t, GitToolInput{Command: "worktree_add", Path: "trees/feature", BranchName: "feature"})); err != nil {
+		t.Fatalf("worktree_add: %v", err)
+	}
+	worktreeDir := filepath.Join(dir, "trees", "feature")
+	if got := readTestFile(t, filepath.Join(worktreeDir, "README.md")); got != "hello\n" {
+		t.Errorf("worktree checkout has README.md = %q", got)
+	}
+
+	output, err := GitTool(ctx, mustMarshal(t, GitToolInput{Command: "worktree_list"}))
+	if err != nil {
+		t.Fatalf("worktree_list: %v", err)
+	}
+	var worktrees []GitWorktree
+	if err := json.Unmarshal([]byte(output), &worktrees); err != nil {
+		t.Fatalf("failed to decode %q: %v", output, err)
+	}
+	if len(worktrees) != 2 {
+		t.Fatalf("worktrees = %+v, want the main checkout and the new one", worktrees)
+	}
+	if worktrees[0].Branch != "main" || worktrees[1].Branch != "feature" || worktrees[1].Head != head {
+		t.Errorf("worktrees = %+v", worktrees)
+	}
+	if got, _ := filepath.EvalSymlinks(worktrees[1].Path); got != worktreeDir {
+		t.Errorf("worktree path = %q, want %q", worktrees[1].Path, worktreeDir)
+	}
+
+	if _, err := GitTool(ctx, mustMarshal(t, GitToolInput{Command: "worktree_remove", Path: "trees/feature"})); err != nil {
+		t.Fatalf("worktree_remove: %v", err)
+	}
+	if _, err := os.Stat(worktreeDir); !os.IsNotExist(err) {
+		t.Error("worktree directory still exists after removal")
+	}
+}
+
+func TestGitWorktreeAddExistingBranch(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "README.md", "hello\n")
+	commitTestFiles(t, dir, "initial")
+	runTestGit(t, dir, "branch", "release")
+
+	if _, err := GitTool(ctx, mustMarshal(t, GitToolInput{Command: "worktree_add", Path: "release", BranchName: "release"})); err != nil {
+		t.Fatalf("worktree_add with an existing branch: %v", err)
+	}
+	if got := runTestGit(t, filepath.Join(dir, "release"), "branch", "--show-current"); got != "release" {
+		t.Errorf("worktree is on %q, want release", got)
+	}
+
+	if _, err := GitTool(ctx, mustMarshal(t, GitToolInput{Command: "worktree_add", Path: "other"})); err == nil {
+		t.Error("expected an error without branch_name")
+	}
+}
+
+func TestGitRejectsOptionLikeBranchNames(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "README.md", "hello\n")
+	commitTestFiles(t, dir, "initial")
+
+	tests := []struct {
+		name  string
+		input GitToolInput
+	}{
+		{"worktree_add option", GitToolInput{Command: "worktree_add", Path: "wt", BranchName: "--detach"}},
+		{"worktree_add revision option", GitToolInput{Command: "worktree_add", Path: "wt", BranchName: "feature", Revision: "--orphan"}},
+		{"branch option", GitToolInput{Command: "branch", BranchName: "-D"}},
+		{"checkout option", GitToolInput{Command: "checkout", BranchName: "--orphan=evil"}},
+		{"push option", GitToolInput{Command: "push", BranchName: "--force"}},
+		{"malformed ref", GitToolInput{Command: "branch", BranchName: "bad..name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GitTool(ctx, mustMarshal(t, tt.input))
+			if err == nil || !strings.Contains(err.Error(), "invalid") {
+				t.Errorf("expected the input to be rejected, got %v", err)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(dir, "wt")); !os.IsNotExist(err) {
+		t.Error("a rejected worktree_add created the worktree")
+	}
+}
+
+func TestParseWorktreeList(t *testing.T) {
+	output := `worktree /repo
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /repo-detached
+HEAD 2222222222222222222222222222222222222222
+detached
+locked
+
+worktree /repo-gone
+HEAD 3333333333333333333333333333333333333333
+branch refs/heads/old
+prunable gitdir file points to non-existent location
+`
+	want := []GitWorktree{
+		{Path: "/repo", Head: "1111111111111111111111111111111111111111", Branch: "main"},
+		{Path: "/repo-detached", Head: "2222222222222222222222222222222222222222", Detached: true, Locked: true},
+		{Path: "/repo-gone", Head: "3333333333333333333333333333333333333333", Branch: "old", Prunable: true},
+	}
+	if got := parseWorktreeList(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseWorktreeList = %+v, want %+v", got, want)
+	}
+}
+
+func TestGitCommitLintsMessage(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	initTestRepo(t, dir)
+	writeTestFile(t, dir, "a.txt", "a\n")
+	runTestGit(t, dir, "add", "a.txt")
+
+	_, err := GitTool(ctx, mustMarshal(t, GitToolInput{Command: "commit", Message: "added a file", LintMessage: true}))
+	if err == nil || !strings.Contains(err.Error(), "(type)") {
+		t.Fatalf("got %v, want the missing type reported", err)
+	}
+	if out, _ := exec.Command("git", "-C", dir, "rev-parse", "--verify", "-q", "HEAD").Output(); len(out) != 0 {
+		t.Error("a message failing lint was committed")
+	}
+
+	if _, err := GitTool(ctx, mustMarshal(t, GitToolInput{Command: "commit", Message: "feat: add a file", LintMessage: true})); err != nil {
+		t.Fatalf("valid message: %v", err)
+	}
+	if subject := runTestGit(t, dir, "log", "-1", "--format=%s"); subject != "feat: add a file" {
+		t.Errorf("subject = %q", subject)
+	}
+}
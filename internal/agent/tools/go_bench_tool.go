@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GoBenchToolDefinition defines the go_bench tool
+var GoBenchToolDefinition = ToolDefinition{
+	Name: "go_bench",
+	Description: `Run Go benchmarks and return structured results.
+Runs 'go test -run ^$ -bench <pattern>' and parses each benchmark line into its name, iteration
+count, ns/op, B/op, and allocs/op. Results are sorted by name, or by ns/op when 'sort_by' is 'ns_per_op'.
+Set 'benchmem' to report memory allocations and 'count' to repeat each benchmark.`,
+	InputSchema:           GoBenchInputSchema,
+	Function:              GoBench,
+	CountsTowardLoopLimit: true,
+}
+
+// GoBenchInput defines the input parameters for the go_bench tool
+type GoBenchInput struct {
+	Pattern    string `json:"pattern,omitempty" jsonschema_description:"Regular expression selecting benchmarks to run. Defaults to '.' (all)."`
+	Path       string `json:"path,omitempty" jsonschema_description:"Package path or pattern to benchmark. Defaults to './...'."`
+	Benchmem   bool   `json:"benchmem,omitempty" jsonschema_description:"If true, pass -benchmem to report B/op and allocs/op"`
+	Count      int    `json:"count,omitempty" jsonschema_description:"Number of times to run each benchmark (-count)"`
+	SortBy     string `json:"sort_by,omitempty" jsonschema_description:"Sort order: 'name' (default) or 'ns_per_op'"`
+	WorkingDir string `json:"working_dir,omitempty" jsonschema_description:"Working directory (defaults to current directory if empty)"`
+}
+
+// GoBenchInputSchema is the JSON schema for the go_bench tool
+var GoBenchInputSchema = GenerateSchema[GoBenchInput]()
+
+// BenchmarkResult is a single parsed benchmark line
+type BenchmarkResult struct {
+	Name        string  `json:"name"`
+	Procs       int     `json:"procs,omitempty"`
+	Iterations  int64   `json:"iterations"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	BytesPerOp  int64   `json:"bytes_per_op,omitempty"`
+	AllocsPerOp int64   `json:"allocs_per_op,omitempty"`
+}
+
+// GoBenchOutput represents the structured output of the go_bench tool
+type GoBenchOutput struct {
+	Success      bool              `json:"success"`
+	Command      string            `json:"command"`
+	Results      []BenchmarkResult `json:"results"`
+	Stderr       string            `json:"stderr,omitempty"`
+	ErrorMessage string            `json:"error_message,omitempty"`
+}
+
+// GoBench implements the go_bench tool functionality
+func GoBench(ctx context.Context, input json.RawMessage) (string, error) {
+	benchInput := GoBenchInput{}
+	err := DecodeInput(input, &benchInput)
+	if err != nil {
+		return "", err
+	}
+
+	pattern := benchInput.Pattern
+	if pattern == "" {
+		pattern = "."
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", fmt.Errorf("invalid benchmark pattern: %w", err)
+	}
+
+	path := benchInput.Path
+	if path == "" {
+		path = "./..."
+	}
+
+	if benchInput.SortBy != "" && benchInput.SortBy != "name" && benchInput.SortBy != "ns_per_op" {
+		return "", fmt.Errorf("invalid sort_by: %s. Must be 'name' or 'ns_per_op'", benchInput.SortBy)
+	}
+
+	args := []string{"-run", "^$", "-bench", pattern}
+	if benchInput.Benchmem {
+		args = append(args, "-benchmem")
+	}
+	if benchInput.Count > 0 {
+		args = append(args, "-count", strconv.Itoa(benchInput.Count))
+	}
+
+	result, err := RunGoCommand(ctx, "test", path, args, benchInput.WorkingDir)
+	if err != nil {
+		return "", err
+	}
+
+	output := GoBenchOutput{
+		Success:      result.Success,
+		Command:      result.Command,
+		Results:      parseBenchmarkOutput(result.Stdout),
+		Stderr:       result.Stderr,
+		ErrorMessage: result.ErrorMessage,
+	}
+	sortBenchmarkResults(output.Results, benchInput.SortBy)
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// benchmarkLinePattern matches the name and iteration count at the start of a result line
+var benchmarkLinePattern = regexp.MustCompile(`^(Benchmark\S+?)(?:-(\d+))?\s+(\d+)\s+(.*)$`)
+
+// parseBenchmarkOutput extracts benchmark results from 'go test -bench' output
+func parseBenchmarkOutput(output string) []BenchmarkResult {
+	results := []BenchmarkResult{}
+
+	for _, line := range strings.Split(output, "\n") {
+		matches := benchmarkLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+
+		result := BenchmarkResult{Name: matches[1]}
+		result.Procs, _ = strconv.Atoi(matches[2])
+		result.Iterations, _ = strconv.ParseInt(matches[3], 10, 64)
+
+		// The remainder is a sequence of "<value> <unit>" pairs
+		fields := strings.Fields(matches[4])
+		for i := 0; i+1 < len(fields); i += 2 {
+			value, unit := fields[i], fields[i+1]
+			switch unit {
+			case "ns/op":
+				result.NsPerOp, _ = strconv.ParseFloat(value, 64)
+			case "B/op":
+				result.BytesPerOp, _ = strconv.ParseInt(value, 10, 64)
+			case "allocs/op":
+				result.AllocsPerOp, _ = strconv.ParseInt(value, 10, 64)
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// sortBenchmarkResults orders results by name (stable for repeated runs) or by ns/op
+func sortBenchmarkResults(results []BenchmarkResult, sortBy string) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if sortBy == "ns_per_op" {
+			return results[i].NsPerOp < results[j].NsPerOp
+		}
+		return results[i].Name < results[j].Name
+	})
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+const benchOutput = `goos: linux
+goarch: amd64
+pkg: example.com/bench
+cpu: Intel(R) Xeon(R) CPU @ 2.20GHz
+BenchmarkSort/small-8         	 1000000	      1052 ns/op	     256 B/op	       3 allocs/op
+BenchmarkSort/large-8         	    2000	    612340 ns/op	   81920 B/op	      12 allocs/op
+BenchmarkHash-8               	50000000	        23.4 ns/op
+BenchmarkSort/small-8         	 1200000	      1001 ns/op	     256 B/op	       3 allocs/op
+PASS
+ok  	example.com/bench	4.210s
+`
+
+func TestParseBenchmarkOutput(t *testing.T) {
+	results := parseBenchmarkOutput(benchOutput)
+
+	want := []BenchmarkResult{
+		{Name: "BenchmarkSort/small", Procs: 8, Iterations: 1000000, NsPerOp: 1052, BytesPerOp: 256, AllocsPerOp: 3},
+		{Name: "BenchmarkSort/large", Procs: 8, Iterations: 2000, NsPerOp: 612340, BytesPerOp: 81920, AllocsPerOp: 12},
+		{Name: "BenchmarkHash", Procs: 8, Iterations: 50000000, NsPerOp: 23.4},
+		{Name: "BenchmarkSort/small", Procs: 8, Iterations: 1200000, NsPerOp: 1001, BytesPerOp: 256, AllocsPerOp: 3},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("parseBenchmarkOutput() =\n%+v\nwant\n%+v", results, want)
+	}
+}
+
+func TestSortBenchmarkResults(t *testing.T) {
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{"name", []string{"BenchmarkHash", "BenchmarkSort/large", "BenchmarkSort/small", "BenchmarkSort/small"}},
+		{"ns_per_op", []string{"BenchmarkHash", "BenchmarkSort/small", "BenchmarkSort/small", "BenchmarkSort/large"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			results := parseBenchmarkOutput(benchOutput)
+			sortBenchmarkResults(results, tt.sortBy)
+
+			var names []string
+			for _, result := range results {
+				names = append(names, result.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("order = %v, want %v", names, tt.want)
+			}
+		})
+	}
+
+	// Repeated runs keep their original order when sorted by name
+	results := parseBenchmarkOutput(benchOutput)
+	sortBenchmarkResults(results, "name")
+	if results[2].NsPerOp != 1052 || results[3].NsPerOp != 1001 {
+		t.Errorf("repeated runs were reordered: %+v", results[2:])
+	}
+}
+
+func TestParseBenchmarkOutputWithoutBenchmarks(t *testing.T) {
+	if results := parseBenchmarkOutput("PASS\nok  \texample.com/bench\t0.004s\n"); len(results) != 0 {
+		t.Errorf("expected no results, got %+v", results)
+	}
+}
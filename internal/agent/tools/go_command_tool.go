@@ -2,12 +2,15 @@ package tools
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"metamorph/internal/logger"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // GoRunnerDefinition defines the run_go tool
@@ -22,17 +25,27 @@ Common commands:
 - 'vet': Report likely mistakes in packages
 - 'fmt': Format Go source code
 - 'mod tidy': Add missing and remove unused modules
+- 'work sync': Sync the go.work build list back to the workspace modules
+For 'build', set 'output' to choose where the binary is written (-o); the produced binaries and
+their sizes are then returned in 'artifacts'. An output ending in '/' or naming an existing
+directory receives one binary per main package, which suits multi-package builds like './cmd/...'.
+In a go.work workspace, './...' only matches the packages of the module in the working directory;
+set 'all_modules' to run the command in every module of the workspace. The results are then
+reported per module in 'modules', keyed by module directory, and succeed only if all modules do.
 `,
-	InputSchema: RunGoInputSchema,
-	Function:    RunGo,
+	InputSchema:           RunGoInputSchema,
+	Function:              RunGo,
+	CountsTowardLoopLimit: true,
 }
 
 // RunGoInput defines the input parameters for the run_go tool
 type RunGoInput struct {
-	Command    string   `json:"command" jsonschema_description:"Go command to run (build, run, test, fmt, vet, etc.)"`
-	Path       string   `json:"path" jsonschema_description:"Path to the Go file or directory to operate on"`
+	Command    string   `json:"command" jsonschema_required:"true" jsonschema_description:"Go command to run (build, run, test, fmt, vet, etc.)" jsonschema_example:"build"`
+	Path       string   `json:"path" jsonschema_description:"Path to the Go file, directory, or package pattern to operate on. File paths are resolved to their package for build, test, vet, install, and list." jsonschema_example:"./..."`
 	Args       []string `json:"args,omitempty" jsonschema_description:"Additional arguments to pass to the Go command"`
 	WorkingDir string   `json:"working_dir,omitempty" jsonschema_description:"Working directory (defaults to current directory if empty)"`
+	Output     string   `json:"output,omitempty" jsonschema_description:"For 'build' only: file or directory (ending in '/') to write binaries to, passed as -o" jsonschema_example:"bin/"`
+	AllModules bool     `json:"all_modules,omitempty" jsonschema_description:"If true, run the command in each module of the go.work workspace and report the results per module"`
 }
 
 // RunGoInputSchema is the JSON schema for the run_go tool
@@ -40,15 +53,24 @@ var RunGoInputSchema = GenerateSchema[RunGoInput]()
 
 // RunGoOutput represents the structured output of the run_go tool
 type RunGoOutput struct {
-	Success      bool   `json:"success"`
-	Stdout       string `json:"stdout"`
-	Stderr       string `json:"stderr"`
-	ErrorMessage string `json:"error_message,omitempty"`
-	Command      string `json:"command"`
+	Success      bool            `json:"success"`
+	Stdout       string          `json:"stdout"`
+	Stderr       string          `json:"stderr"`
+	ErrorMessage string          `json:"error_message,omitempty"`
+	Command      string          `json:"command"`
+	Artifacts    []BuildArtifact `json:"artifacts,omitempty"`
+	// Results per module directory, relative to the go.work file, when run with all_modules
+	Modules map[string]RunGoOutput `json:"modules,omitempty"`
+}
+
+// BuildArtifact is a binary produced by a build with an output path
+type BuildArtifact struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
 }
 
 // RunGo implements the run_go tool functionality
-func RunGo(input json.RawMessage) (string, error) {
+func RunGo(ctx context.Context, input json.RawMessage) (string, error) {
 	runGoInput := RunGoInput{}
 	err := json.Unmarshal(input, &runGoInput)
 	if err != nil {
@@ -60,15 +82,66 @@ func RunGo(input json.RawMessage) (string, error) {
 		return "", fmt.Errorf("command cannot be empty")
 	}
 
-	// Handle special case for 'mod' commands
+	// Set working directory
+	workingDir := workspaceDir(ctx)
+	if runGoInput.WorkingDir != "" {
+		workingDir, err = ResolvePath(ctx, runGoInput.WorkingDir)
+		if err != nil {
+			return "", err
+		}
+		// Create directory if it doesn't exist
+		if _, err := os.Stat(workingDir); os.IsNotExist(err) {
+			err = os.MkdirAll(workingDir, 0755)
+			if err != nil {
+				return "", fmt.Errorf("failed to create working directory: %w", err)
+			}
+		}
+	}
+
+	if runGoInput.AllModules {
+		return runGoAllModules(ctx, runGoInput, workingDir)
+	}
+
+	// Resolve the build output; a trailing separator means a directory even if it doesn't exist yet
+	var outputPath string
+	outputIsDir := false
+	if runGoInput.Output != "" {
+		if runGoInput.Command != "build" {
+			return "", fmt.Errorf("output is only supported for the build command")
+		}
+		for _, arg := range runGoInput.Args {
+			if arg == "-o" || strings.HasPrefix(arg, "-o=") {
+				return "", fmt.Errorf("set either output or -o in args, not both")
+			}
+		}
+		outputPath, err = ResolvePath(ctx, runGoInput.Output)
+		if err != nil {
+			return "", err
+		}
+		if info, err := os.Stat(outputPath); err == nil && info.IsDir() {
+			outputIsDir = true
+		}
+		if strings.HasSuffix(runGoInput.Output, "/") || strings.HasSuffix(runGoInput.Output, `\`) {
+			outputIsDir = true
+		}
+	}
+
+	// Handle special case for 'mod' and 'work' commands
 	var args []string
-	if strings.HasPrefix(runGoInput.Command, "mod ") {
+	if isGoSubcommand(runGoInput.Command) {
 		// For commands like "mod tidy", split into "mod" and "tidy"
 		parts := strings.SplitN(runGoInput.Command, " ", 2)
 		args = append([]string{parts[0], parts[1]}, runGoInput.Args...)
 	} else {
 		args = append([]string{runGoInput.Command}, runGoInput.Args...)
 	}
+	if outputPath != "" {
+		outputArg := outputPath
+		if outputIsDir {
+			outputArg += string(filepath.Separator)
+		}
+		args = append([]string{args[0], "-o", outputArg}, args[1:]...)
+	}
 
 	// Add path if provided and appropriate for the command
 	if runGoInput.Path != "" {
@@ -81,26 +154,13 @@ func RunGo(input json.RawMessage) (string, error) {
 		}
 
 		// Only add path for commands that operate on packages
-		if !skipPathCommands[runGoInput.Command] && !strings.HasPrefix(runGoInput.Command, "mod ") {
-			args = append(args, runGoInput.Path)
-		}
-	}
-
-	// Set working directory
-	workingDir := "."
-	if runGoInput.WorkingDir != "" {
-		workingDir = runGoInput.WorkingDir
-		// Create directory if it doesn't exist
-		if _, err := os.Stat(workingDir); os.IsNotExist(err) {
-			err = os.MkdirAll(workingDir, 0755)
-			if err != nil {
-				return "", fmt.Errorf("failed to create working directory: %w", err)
-			}
+		if !skipPathCommands[runGoInput.Command] && !isGoSubcommand(runGoInput.Command) {
+			args = append(args, resolveGoPath(runGoInput.Command, runGoInput.Path, workingDir))
 		}
 	}
 
 	// Run Go command
-	cmd := exec.Command("go", args...)
+	cmd := exec.CommandContext(ctx, "go", args...)
 	cmd.Dir = workingDir
 
 	// Capture stdout and stderr
@@ -109,18 +169,31 @@ func RunGo(input json.RawMessage) (string, error) {
 	cmd.Stderr = &stderr
 
 	// Execute the command
+	logger.FromContext(ctx).Debug().
+		Strs("args", logger.RedactStrings(args)).
+		Str("workingDir", workingDir).
+		Msg("Running go command")
+	started := time.Now()
 	cmdErr := cmd.Run()
+	if cmdErr != nil {
+		logger.FromContext(ctx).Debug().
+			Err(logger.RedactError(cmdErr)).
+			Strs("args", logger.RedactStrings(args)).
+			Msg("Go command failed")
+	}
 
 	// Prepare the output
 	output := RunGoOutput{
 		Success: cmdErr == nil,
 		Stdout:  stdout.String(),
 		Stderr:  stderr.String(),
-		Command: "go " + strings.Join(args, " "),
+		Command: QuoteShellCommand(append([]string{"go"}, args...)...),
 	}
 
 	if cmdErr != nil {
 		output.ErrorMessage = cmdErr.Error()
+	} else if outputPath != "" {
+		output.Artifacts = buildArtifacts(outputPath, outputIsDir, started)
 	}
 
 	// Convert to JSON
@@ -132,8 +205,164 @@ func RunGo(input json.RawMessage) (string, error) {
 	return string(jsonOutput), nil
 }
 
+// isGoSubcommand reports whether command is a two-word command such as 'mod tidy' or 'work sync',
+// which operates on the module or workspace rather than on packages
+func isGoSubcommand(command string) bool {
+	return strings.HasPrefix(command, "mod ") || strings.HasPrefix(command, "work ")
+}
+
+// runGoAllModules runs the command in each module of the go.work workspace containing
+// workingDir and combines the results, keyed by module directory
+func runGoAllModules(ctx context.Context, runGoInput RunGoInput, workingDir string) (string, error) {
+	if runGoInput.Output != "" {
+		return "", fmt.Errorf("output is not supported with all_modules, as each module would overwrite it")
+	}
+	workFile, moduleDirs, err := goWorkModules(ctx, workingDir)
+	if err != nil {
+		return "", err
+	}
+
+	output := RunGoOutput{Success: true, Modules: map[string]RunGoOutput{}}
+	var failed []string
+	for _, moduleDir := range moduleDirs {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		dir := moduleDir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(workFile), moduleDir)
+		}
+		if _, err := os.Stat(dir); err != nil {
+			return "", fmt.Errorf("module %s listed in %s: %w", moduleDir, workFile, err)
+		}
+
+		result, err := RunGoCommand(ctx, runGoInput.Command, runGoInput.Path, runGoInput.Args, dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to run go %s in %s: %w", runGoInput.Command, moduleDir, err)
+		}
+		output.Modules[moduleDir] = result
+		if !result.Success {
+			output.Success = false
+			failed = append(failed, moduleDir)
+		}
+	}
+
+	output.Command = fmt.Sprintf("go %s in %d module(s) of %s", runGoInput.Command, len(moduleDirs), workFile)
+	if len(failed) > 0 {
+		output.ErrorMessage = fmt.Sprintf("failed in %d of %d module(s): %s", len(failed), len(moduleDirs), strings.Join(failed, ", "))
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// goWorkModules returns the go.work file in effect for dir and the module directories it uses,
+// as written in the file, in order
+func goWorkModules(ctx context.Context, dir string) (string, []string, error) {
+	env, err := RunGoCommand(ctx, "env", "", []string{"GOWORK"}, dir)
+	if err != nil {
+		return "", nil, err
+	}
+	workFile := strings.TrimSpace(env.Stdout)
+	if !env.Success || workFile == "" || workFile == "off" {
+		return "", nil, fmt.Errorf("all_modules requires a go.work workspace, but none applies to %s", dir)
+	}
+
+	result, err := RunGoCommand(ctx, "work edit", "", []string{"-json", workFile}, dir)
+	if err != nil {
+		return "", nil, err
+	}
+	if !result.Success {
+		return "", nil, fmt.Errorf("failed to parse %s: %s", workFile, strings.TrimSpace(result.Stderr))
+	}
+	var work struct {
+		Use []struct{ DiskPath string }
+	}
+	if err := json.Unmarshal([]byte(result.Stdout), &work); err != nil {
+		return "", nil, fmt.Errorf("failed to parse %s: %w", workFile, err)
+	}
+	if len(work.Use) == 0 {
+		return "", nil, fmt.Errorf("%s does not use any modules", workFile)
+	}
+
+	var dirs []string
+	for _, use := range work.Use {
+		dirs = append(dirs, filepath.Clean(use.DiskPath))
+	}
+	return workFile, dirs, nil
+}
+
+// buildArtifacts lists the binaries a build wrote to outputPath. For a directory these are the
+// regular files in it modified since the build started, as go build writes one per main package.
+func buildArtifacts(outputPath string, outputIsDir bool, started time.Time) []BuildArtifact {
+	if !outputIsDir {
+		info, err := os.Stat(outputPath)
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		return []BuildArtifact{{Path: outputPath, Size: info.Size()}}
+	}
+
+	entries, err := os.ReadDir(outputPath)
+	if err != nil {
+		return nil
+	}
+	var artifacts []BuildArtifact
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.ModTime().Before(started.Truncate(time.Second)) {
+			continue
+		}
+		artifacts = append(artifacts, BuildArtifact{Path: filepath.Join(outputPath, entry.Name()), Size: info.Size()})
+	}
+	return artifacts
+}
+
+// packageCommands lists the Go commands that expect package paths rather than files
+var packageCommands = map[string]bool{
+	"build":   true,
+	"test":    true,
+	"vet":     true,
+	"install": true,
+	"list":    true,
+}
+
+// resolveGoPath converts a file path into its package for commands that expect packages.
+// Package patterns such as './...' and directories are passed through unchanged.
+func resolveGoPath(command, path, workingDir string) string {
+	if !packageCommands[command] || strings.Contains(path, "...") {
+		return path
+	}
+
+	fullPath := path
+	if !filepath.IsAbs(fullPath) {
+		fullPath = filepath.Join(workingDir, path)
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil || info.IsDir() {
+		// Not a local file: either a directory or an import path
+		return path
+	}
+
+	// Prefer the import path so the command resolves within its module
+	if pkg, err := GetGoPackage(fullPath); err == nil {
+		return pkg
+	}
+
+	dir := filepath.Dir(path)
+	if filepath.IsAbs(dir) || dir == "." || strings.HasPrefix(dir, ".") {
+		return dir
+	}
+	return "./" + dir
+}
+
 // Helper function to be used within other tools to run Go commands
-func RunGoCommand(command, path string, args []string, workingDir string) (RunGoOutput, error) {
+func RunGoCommand(ctx context.Context, command, path string, args []string, workingDir string) (RunGoOutput, error) {
 	input := RunGoInput{
 		Command:    command,
 		Path:       path,
@@ -146,7 +375,7 @@ func RunGoCommand(command, path string, args []string, workingDir string) (RunGo
 		return RunGoOutput{}, fmt.Errorf("failed to marshal input: %w", err)
 	}
 
-	outputStr, err := RunGo(inputJSON)
+	outputStr, err := RunGo(ctx, inputJSON)
 	if err != nil {
 		return RunGoOutput{}, err
 	}
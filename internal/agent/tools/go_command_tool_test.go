@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResolveGoPath(t *testing.T) {
+	_, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+	writeTestFile(t, dir, "pkg/add/add.go", "package add\n\nfunc Add(a, b int) int { return a + b }\n")
+
+	tests := []struct {
+		name    string
+		command string
+		path    string
+		want    string
+	}{
+		{"file for test", "test", "pkg/add/add.go", "example.com/app/pkg/add"},
+		{"file for vet", "vet", "./pkg/add/add.go", "example.com/app/pkg/add"},
+		{"directory", "test", "./pkg/add", "./pkg/add"},
+		{"pattern", "test", "./...", "./..."},
+		{"import path", "build", "example.com/app/pkg/add", "example.com/app/pkg/add"},
+		{"file for run", "run", "pkg/add/add.go", "pkg/add/add.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveGoPath(tt.command, tt.path, dir); got != tt.want {
+				t.Errorf("resolveGoPath(%q, %q) = %q, want %q", tt.command, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunGoTestWithFilePath(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+	writeTestFile(t, dir, "pkg/add/add.go", "package add\n\nfunc Add(a, b int) int { return a + b }\n")
+	writeTestFile(t, dir, "pkg/add/add_test.go", `package add
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	if Add(1, 2) != 3 {
+		t.Fatal("Add(1, 2) != 3")
+	}
+}
+`)
+
+	var output RunGoOutput
+	callTool(t, ctx, RunGo, RunGoInput{Command: "test", Path: "pkg/add/add.go"}, &output)
+
+	if !output.Success {
+		t.Fatalf("go test on a file path failed: %s %s", output.Stderr, output.ErrorMessage)
+	}
+	if !strings.Contains(output.Command, "example.com/app/pkg/add") || strings.Contains(output.Command, "add.go") {
+		t.Errorf("command = %q, want the file resolved to its package", output.Command)
+	}
+}
+
+func TestRunGoBuildReportsArtifacts(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+	writeTestFile(t, dir, "cmd/server/main.go", "package main\n\nfunc main() {}\n")
+	writeTestFile(t, dir, "cmd/worker/main.go", "package main\n\nfunc main() {}\n")
+
+	var output RunGoOutput
+	callTool(t, ctx, RunGo, RunGoInput{Command: "build", Path: "./cmd/server", Output: "bin/server"}, &output)
+	if !output.Success {
+		t.Fatalf("build failed: %s %s", output.Stderr, output.ErrorMessage)
+	}
+	if len(output.Artifacts) != 1 || output.Artifacts[0].Path != filepath.Join(dir, "bin", "server") || output.Artifacts[0].Size == 0 {
+		t.Errorf("artifacts = %+v, want bin/server with its size", output.Artifacts)
+	}
+
+	output = RunGoOutput{}
+	callTool(t, ctx, RunGo, RunGoInput{Command: "build", Path: "./cmd/...", Output: "out/"}, &output)
+	if !output.Success {
+		t.Fatalf("multi-package build failed: %s %s", output.Stderr, output.ErrorMessage)
+	}
+	var names []string
+	for _, artifact := range output.Artifacts {
+		names = append(names, filepath.Base(artifact.Path))
+	}
+	if !slices.Equal(names, []string{"server", "worker"}) {
+		t.Errorf("artifacts = %+v, want one binary per main package", output.Artifacts)
+	}
+}
+
+func TestRunGoOutputValidation(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+
+	inputs := []RunGoInput{
+		{Command: "test", Output: "bin/app"},
+		{Command: "build", Output: "bin/app", Args: []string{"-o", "elsewhere"}},
+	}
+	for _, input := range inputs {
+		if _, err := RunGo(ctx, mustMarshal(t, input)); err == nil {
+			t.Errorf("%+v: expected an error", input)
+		}
+	}
+}
+
+func TestRunGoAllModules(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	t.Setenv("GOWORK", "")
+	t.Setenv("GOFLAGS", "")
+	writeTestFile(t, dir, "go.work", "go 1.21\n\nuse (\n\t./api\n\t./worker\n)\n")
+	writeTestFile(t, dir, "api/go.mod", "module example.com/api\n\ngo 1.21\n")
+	writeTestFile(t, dir, "api/api.go", "package api\n\nfunc Version() string { return \"1\" }\n")
+	writeTestFile(t, dir, "worker/go.mod", "module example.com/worker\n\ngo 1.21\n")
+	writeTestFile(t, dir, "worker/worker.go", "package worker\n\nimport \"example.com/api\"\n\nvar version = api.Version()\n")
+
+	var output RunGoOutput
+	callTool(t, ctx, RunGo, RunGoInput{Command: "build", Path: "./...", AllModules: true}, &output)
+	if !output.Success || len(output.Modules) != 2 {
+		t.Fatalf("got %+v, want both modules built", output)
+	}
+	for _, module := range []string{"api", "worker"} {
+		if result, found := output.Modules[module]; !found || !result.Success {
+			t.Errorf("module %s: %+v", module, result)
+		}
+	}
+	if !strings.Contains(output.Command, "in 2 module(s)") {
+		t.Errorf("command = %q", output.Command)
+	}
+
+	// A failure in one module is reported without hiding the other
+	writeTestFile(t, dir, "worker/worker.go", "package worker\n\nvar version = missing\n")
+	output = RunGoOutput{}
+	callTool(t, ctx, RunGo, RunGoInput{Command: "build", Path: "./...", AllModules: true}, &output)
+	if output.Success || !output.Modules["api"].Success || output.Modules["worker"].Success || output.ErrorMessage != "failed in 1 of 2 module(s): worker" {
+		t.Errorf("got %+v, want only worker failing", output)
+	}
+
+	if _, err := RunGo(ctx, mustMarshal(t, RunGoInput{Command: "build", AllModules: true, Output: "bin/"})); err == nil {
+		t.Error("expected an error combining output with all_modules")
+	}
+}
+
+func TestRunGoAllModulesWithoutWorkspace(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	t.Setenv("GOWORK", "")
+	t.Setenv("GOFLAGS", "")
+	testGoModule(t, dir, "example.com/app")
+
+	if _, err := RunGo(ctx, mustMarshal(t, RunGoInput{Command: "build", AllModules: true})); err == nil || !strings.Contains(err.Error(), "requires a go.work workspace") {
+		t.Errorf("got %v, want the missing go.work reported", err)
+	}
+}
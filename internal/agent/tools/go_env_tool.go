@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// GoEnvToolDefinition defines the go_env tool
+var GoEnvToolDefinition = ToolDefinition{
+	Name: "go_env",
+	Description: `Show the effective Go environment and toolchain version as structured data.
+By default the most useful 'go env' values are returned (GOOS, GOARCH, GOPATH, GOMODCACHE, GOROOT,
+GOMOD, GOFLAGS, GOPROXY, CGO_ENABLED, ...). Pass 'keys' to request specific variables instead, or set
+'all' to return every variable. Values reflect the module in the current working directory.`,
+	InputSchema: GoEnvInputSchema,
+	Function:    GoEnv,
+}
+
+// GoEnvInput defines the input parameters for the go_env tool
+type GoEnvInput struct {
+	Keys []string `json:"keys,omitempty" jsonschema_description:"Environment variables to return. Defaults to a set of commonly useful keys." jsonschema_example:"[\"GOOS\", \"GOARCH\"]"`
+	All  bool     `json:"all,omitempty" jsonschema_description:"If true, return every 'go env' variable"`
+}
+
+// GoEnvInputSchema is the JSON schema for the go_env tool
+var GoEnvInputSchema = GenerateSchema[GoEnvInput]()
+
+// GoEnvOutput represents the structured output of the go_env tool
+type GoEnvOutput struct {
+	Version string            `json:"version"`
+	Env     map[string]string `json:"env"`
+}
+
+// defaultGoEnvKeys lists the variables reported when no keys are requested
+var defaultGoEnvKeys = []string{
+	"GOOS", "GOARCH", "GOVERSION", "GOROOT", "GOPATH", "GOMODCACHE", "GOCACHE", "GOMOD",
+	"GOWORK", "GOFLAGS", "GOPROXY", "GOPRIVATE", "GOTOOLCHAIN", "CGO_ENABLED",
+}
+
+// GoEnv implements the go_env tool functionality
+func GoEnv(ctx context.Context, input json.RawMessage) (string, error) {
+	envInput := GoEnvInput{}
+	err := DecodeInput(input, &envInput)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"env", "-json"}
+	if !envInput.All {
+		keys := defaultGoEnvKeys
+		if len(envInput.Keys) > 0 {
+			keys = nil
+			for _, key := range envInput.Keys {
+				if key = strings.ToUpper(strings.TrimSpace(key)); key != "" {
+					keys = append(keys, key)
+				}
+			}
+		}
+		args = append(args, keys...)
+	}
+
+	envJSON, err := runGoQuery(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	output := GoEnvOutput{}
+	if err := json.Unmarshal(envJSON, &output.Env); err != nil {
+		return "", fmt.Errorf("failed to parse go env output: %w", err)
+	}
+
+	version, err := runGoQuery(ctx, "version")
+	if err != nil {
+		return "", err
+	}
+	output.Version = strings.TrimSpace(string(version))
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// runGoQuery runs a read-only go subcommand in the workspace and returns its stdout
+func runGoQuery(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = workspaceDir(ctx)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
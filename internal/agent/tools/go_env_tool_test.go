@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGoEnvDefaults(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/env")
+
+	var output GoEnvOutput
+	callTool(t, ctx, GoEnv, GoEnvInput{}, &output)
+
+	if output.Env["GOOS"] != runtime.GOOS || output.Env["GOARCH"] != runtime.GOARCH {
+		t.Errorf("GOOS/GOARCH = %q/%q, want %s/%s", output.Env["GOOS"], output.Env["GOARCH"], runtime.GOOS, runtime.GOARCH)
+	}
+	if len(output.Env) != len(defaultGoEnvKeys) {
+		t.Errorf("got %d keys, want the %d default keys", len(output.Env), len(defaultGoEnvKeys))
+	}
+	// GOMOD reflects the workspace module, not the process's
+	if output.Env["GOMOD"] != filepath.Join(dir, "go.mod") {
+		t.Errorf("GOMOD = %q, want the workspace go.mod", output.Env["GOMOD"])
+	}
+	if !strings.HasPrefix(output.Version, "go version go") {
+		t.Errorf("version = %q", output.Version)
+	}
+}
+
+func TestGoEnvRequestedKeys(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+
+	var output GoEnvOutput
+	callTool(t, ctx, GoEnv, GoEnvInput{Keys: []string{"goos", " GOPATH ", ""}}, &output)
+
+	keys := make([]string, 0, len(output.Env))
+	for key := range output.Env {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"GOOS", "GOPATH"}) {
+		t.Errorf("keys = %v, want only GOOS and GOPATH", keys)
+	}
+	if output.Env["GOOS"] != runtime.GOOS {
+		t.Errorf("GOOS = %q, want %s", output.Env["GOOS"], runtime.GOOS)
+	}
+
+	var all GoEnvOutput
+	callTool(t, ctx, GoEnv, GoEnvInput{All: true, Keys: []string{"GOOS"}}, &all)
+	if len(all.Env) <= len(defaultGoEnvKeys) {
+		t.Errorf("all returned %d keys, want every variable", len(all.Env))
+	}
+}
@@ -1,9 +1,13 @@
 package tools
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -20,6 +24,10 @@ and suggest how to fix them. It can help identify and fix issues like:
 - Syntax errors
 
 The tool returns a structured analysis with suggested fixes that can be applied.
+Related errors are grouped into root causes (one undefined symbol, one missing import, the
+syntax errors of one file, ...) listed in the order they should be fixed, each with its
+locations and a few representative errors, so a single missing import shows up as one fix
+rather than dozens of lines.
 `,
 	InputSchema: FixGoErrorsInputSchema,
 	Function:    FixGoErrors,
@@ -27,7 +35,7 @@ The tool returns a structured analysis with suggested fixes that can be applied.
 
 // FixGoErrorsInput defines the input parameters for the fix_go_errors tool
 type FixGoErrorsInput struct {
-	ErrorOutput string `json:"error_output" jsonschema_description:"The stderr output from a Go command containing error messages"`
+	ErrorOutput string `json:"error_output" jsonschema_required:"true" jsonschema_description:"The stderr output from a Go command containing error messages"`
 }
 
 // FixGoErrorsInputSchema is the JSON schema for the fix_go_errors tool
@@ -42,21 +50,36 @@ type GoError struct {
 	ErrorType   string `json:"error_type"`
 	Suggestion  string `json:"suggestion"`
 	CodeSnippet string `json:"code_snippet,omitempty"`
+
+	sourceLine string // trimmed text of the offending line, if it could be read
+}
+
+// ErrorCluster groups the errors that likely share a root cause
+type ErrorCluster struct {
+	Cause     string    `json:"cause"`
+	ErrorType string    `json:"error_type"`
+	Fix       string    `json:"fix"`
+	Count     int       `json:"count"`
+	Locations []string  `json:"locations,omitempty"`
+	Examples  []GoError `json:"examples"`
+
+	priority int
 }
 
 // FixGoErrorsOutput represents the structured output of the fix_go_errors tool
 type FixGoErrorsOutput struct {
-	TotalErrors    int       `json:"total_errors"`
-	ParsedErrors   []GoError `json:"parsed_errors"`
-	OverallSummary string    `json:"overall_summary"`
+	TotalErrors    int            `json:"total_errors"`
+	RootCauses     []ErrorCluster `json:"root_causes"`
+	ParsedErrors   []GoError      `json:"parsed_errors"`
+	OverallSummary string         `json:"overall_summary"`
 }
 
 // FixGoErrors implements the fix_go_errors tool functionality
-func FixGoErrors(input json.RawMessage) (string, error) {
+func FixGoErrors(ctx context.Context, input json.RawMessage) (string, error) {
 	fixGoErrorsInput := FixGoErrorsInput{}
-	err := json.Unmarshal(input, &fixGoErrorsInput)
+	err := DecodeInput(input, &fixGoErrorsInput)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse input: %w", err)
+		return "", err
 	}
 
 	if fixGoErrorsInput.ErrorOutput == "" {
@@ -64,14 +87,18 @@ func FixGoErrors(input json.RawMessage) (string, error) {
 	}
 
 	// Parse the errors
-	parsedErrors := parseGoErrors(fixGoErrorsInput.ErrorOutput)
+	parsedErrors := parseGoErrors(ctx, fixGoErrorsInput.ErrorOutput)
+
+	// Group the errors by root cause
+	clusters := clusterGoErrors(parsedErrors)
 
 	// Generate overall summary
-	summary := generateErrorSummary(parsedErrors)
+	summary := generateErrorSummary(parsedErrors, clusters)
 
 	// Prepare output
 	output := FixGoErrorsOutput{
 		TotalErrors:    len(parsedErrors),
+		RootCauses:     clusters,
 		ParsedErrors:   parsedErrors,
 		OverallSummary: summary,
 	}
@@ -85,8 +112,9 @@ func FixGoErrors(input json.RawMessage) (string, error) {
 	return string(jsonOutput), nil
 }
 
-// parseGoErrors extracts structured error information from Go error output
-func parseGoErrors(errorOutput string) []GoError {
+// parseGoErrors extracts structured error information from Go error output. Source snippets are
+// read relative to the workspace root carried by ctx.
+func parseGoErrors(ctx context.Context, errorOutput string) []GoError {
 	var errors []GoError
 
 	// Split error output into lines
@@ -111,6 +139,7 @@ func parseGoErrors(errorOutput string) []GoError {
 		}
 
 		var goError GoError
+		sourceLine := ""
 
 		// Try to match file:line:col pattern
 		if matches := fileLineColPattern.FindStringSubmatch(line); matches != nil {
@@ -123,8 +152,11 @@ func parseGoErrors(errorOutput string) []GoError {
 			}
 			goError.Message = matches[4]
 
-			// Look ahead to get more context
-			if i+1 < len(lines) {
+			// Prefer the actual source around the error, falling back to the next output line
+			if snippet, source, err := readSourceContext(ctx, goError.File, goError.Line, sourceContextRadius); err == nil {
+				goError.CodeSnippet = snippet
+				sourceLine = source
+			} else if i+1 < len(lines) {
 				goError.CodeSnippet = strings.TrimSpace(lines[i+1])
 			}
 		} else if matches := fileLinePattern.FindStringSubmatch(line); matches != nil {
@@ -135,8 +167,11 @@ func parseGoErrors(errorOutput string) []GoError {
 			}
 			goError.Message = matches[3]
 
-			// Look ahead to get more context
-			if i+1 < len(lines) {
+			// Prefer the actual source around the error, falling back to the next output line
+			if snippet, source, err := readSourceContext(ctx, goError.File, goError.Line, sourceContextRadius); err == nil {
+				goError.CodeSnippet = snippet
+				sourceLine = source
+			} else if i+1 < len(lines) {
 				goError.CodeSnippet = strings.TrimSpace(lines[i+1])
 			}
 		} else if matches := packageErrorPattern.FindStringSubmatch(line); matches != nil {
@@ -190,6 +225,9 @@ func parseGoErrors(errorOutput string) []GoError {
 				goError.Suggestion = "Review the error message carefully and check the relevant code section."
 			}
 
+			goError.Suggestion = tailorSuggestion(goError, sourceLine, undefinedPattern)
+			goError.sourceLine = sourceLine
+
 			errors = append(errors, goError)
 		}
 
@@ -199,8 +237,192 @@ func parseGoErrors(errorOutput string) []GoError {
 	return errors
 }
 
+// sourceContextRadius is the number of lines shown before and after an error line
+const sourceContextRadius = 2
+
+// readSourceContext reads the lines surrounding line in file. It returns a numbered snippet
+// with the error line marked, and the trimmed text of the error line itself. Files outside the
+// workspace are not read.
+func readSourceContext(ctx context.Context, file string, line, radius int) (string, string, error) {
+	if file == "" || line < 1 {
+		return "", "", fmt.Errorf("no source location")
+	}
+
+	path, err := ResolvePath(ctx, file)
+	if err != nil {
+		return "", "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	start, end := line-radius, line+radius
+	var snippet strings.Builder
+	sourceLine := ""
+	found := false
+
+	scanner := bufio.NewScanner(f)
+	for current := 1; scanner.Scan() && current <= end; current++ {
+		if current < start {
+			continue
+		}
+		marker := "  "
+		if current == line {
+			marker = "> "
+			sourceLine = strings.TrimSpace(scanner.Text())
+			found = true
+		}
+		fmt.Fprintf(&snippet, "%s%4d | %s\n", marker, current, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", err
+	}
+	if !found {
+		return "", "", fmt.Errorf("line %d is beyond the end of %s", line, file)
+	}
+
+	return snippet.String(), sourceLine, nil
+}
+
+// tailorSuggestion refines a suggestion using the offending source line, when available
+func tailorSuggestion(goError GoError, sourceLine string, undefinedPattern *regexp.Regexp) string {
+	if sourceLine == "" {
+		return goError.Suggestion
+	}
+
+	switch goError.ErrorType {
+	case "Undefined Symbol":
+		if matches := undefinedPattern.FindStringSubmatch(goError.Message); matches != nil {
+			symbol := matches[1]
+			if strings.Contains(sourceLine, symbol) {
+				return fmt.Sprintf("%s It is used on line %d as: %s", goError.Suggestion, goError.Line, sourceLine)
+			}
+		}
+	case "Unused Declaration":
+		return fmt.Sprintf("%s The declaration is on line %d: %s", goError.Suggestion, goError.Line, sourceLine)
+	}
+
+	return fmt.Sprintf("%s Offending line %d: %s", goError.Suggestion, goError.Line, sourceLine)
+}
+
+// maxClusterExamples is the number of representative errors kept per cluster
+const maxClusterExamples = 3
+
+var (
+	clusterUndefinedPattern    = regexp.MustCompile(`undefined:\s+([^\s]+)`)
+	clusterImportPattern       = regexp.MustCompile(`(?i)could not import ([^\s]+)`)
+	clusterModulePattern       = regexp.MustCompile(`no required module provides package ([^\s;]+)`)
+	clusterUnusedImportPattern = regexp.MustCompile(`"([^"]+)" imported (?:as \S+ )?and not used`)
+)
+
+// clusterGoErrors groups errors by their likely root cause and orders the clusters by the order
+// they should be fixed in: errors that stop packages from loading first, then syntax errors,
+// which hide type errors, then undefined symbols, which cause follow-on type errors, and the
+// rest. Within a priority, clusters with more errors come first. Errors repeated at the same
+// location are counted once.
+func clusterGoErrors(errors []GoError) []ErrorCluster {
+	clusters := []ErrorCluster{}
+	byKey := map[string]int{}
+	seen := map[string]bool{}
+
+	for _, goError := range errors {
+		// '# package' lines only name the package the following errors belong to
+		if goError.File == "" && strings.HasPrefix(goError.Message, "# ") {
+			continue
+		}
+
+		location := goError.File
+		if goError.Line > 0 {
+			location = fmt.Sprintf("%s:%d", goError.File, goError.Line)
+			if goError.Column > 0 {
+				location = fmt.Sprintf("%s:%d", location, goError.Column)
+			}
+		}
+		if seen[location+" "+goError.Message] {
+			continue
+		}
+		seen[location+" "+goError.Message] = true
+
+		key, cause, fix, priority := errorRootCause(goError)
+		index, found := byKey[key]
+		if !found {
+			index = len(clusters)
+			byKey[key] = index
+			clusters = append(clusters, ErrorCluster{
+				Cause:     cause,
+				ErrorType: goError.ErrorType,
+				Fix:       fix,
+				Examples:  []GoError{},
+				priority:  priority,
+			})
+		}
+
+		cluster := &clusters[index]
+		cluster.Count++
+		if goError.Line > 0 {
+			cluster.Locations = append(cluster.Locations, location)
+		}
+		if len(cluster.Examples) < maxClusterExamples {
+			cluster.Examples = append(cluster.Examples, goError)
+		}
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].priority != clusters[j].priority {
+			return clusters[i].priority < clusters[j].priority
+		}
+		return clusters[i].Count > clusters[j].Count
+	})
+	return clusters
+}
+
+// errorRootCause returns the cluster key of an error with the description, fix and priority of
+// its root cause. Undefined symbols and imports are clustered across files, since one definition
+// or dependency fixes them all; other errors are clustered by file and type.
+func errorRootCause(goError GoError) (string, string, string, int) {
+	if matches := clusterUnusedImportPattern.FindStringSubmatch(goError.Message); matches != nil {
+		return "unused-import:" + goError.File + ":" + matches[1], fmt.Sprintf("unused import %q in %s", matches[1], goError.File),
+			fmt.Sprintf("Remove the import of %q or use it.", matches[1]), 5
+	}
+
+	switch goError.ErrorType {
+	case "Import Cycle":
+		return "cycle", "import cycle", "Break the import cycle by moving the shared code into a separate package or depending on an interface instead.", 0
+	case "Module Error":
+		if matches := clusterModulePattern.FindStringSubmatch(goError.Message); matches != nil {
+			return "module:" + matches[1], fmt.Sprintf("no module provides package %s", matches[1]),
+				fmt.Sprintf("Run 'go get %s' or 'go mod tidy', or correct the import path.", matches[1]), 1
+		}
+		return "module", "module errors", "Run 'go mod tidy' to add missing modules or fix your import statements to use the correct module paths.", 1
+	case "Missing Import":
+		if matches := clusterImportPattern.FindStringSubmatch(goError.Message); matches != nil {
+			return "import:" + matches[1], fmt.Sprintf("package %s cannot be imported", matches[1]),
+				fmt.Sprintf("Fix the import of %s: check the path and that the package builds.", matches[1]), 1
+		}
+	case "Syntax Error":
+		return "syntax:" + goError.File, fmt.Sprintf("syntax errors in %s", goError.File),
+			"Fix the first syntax error in the file; later ones and any type errors are often caused by it.", 2
+	case "Undefined Symbol":
+		if matches := clusterUndefinedPattern.FindStringSubmatch(goError.Message); matches != nil {
+			symbol := matches[1]
+			fix := fmt.Sprintf("Define '%s', correct its spelling, or import the package that provides it.", symbol)
+			if !strings.Contains(symbol, ".") && strings.Contains(goError.sourceLine, symbol+".") {
+				fix = fmt.Sprintf("'%s' is used as a package: add the import for it.", symbol)
+			}
+			return "undefined:" + symbol, fmt.Sprintf("undefined: %s", symbol), fix, 3
+		}
+	case "Unused Declaration":
+		return "unused:" + goError.File, fmt.Sprintf("unused declarations in %s", goError.File), goError.Suggestion, 5
+	case "Type Error", "Multiple Return Values", "Missing Return":
+		return "type:" + goError.File + ":" + goError.ErrorType, fmt.Sprintf("%s in %s", strings.ToLower(goError.ErrorType), goError.File), goError.Suggestion, 4
+	}
+	return "other:" + goError.File + ":" + goError.Message, goError.Message, goError.Suggestion, 6
+}
+
 // generateErrorSummary creates an overall summary of the errors and suggestions
-func generateErrorSummary(errors []GoError) string {
+func generateErrorSummary(errors []GoError, clusters []ErrorCluster) string {
 	if len(errors) == 0 {
 		return "No errors were found in the provided output."
 	}
@@ -212,7 +434,7 @@ func generateErrorSummary(errors []GoError) string {
 	}
 
 	var summary strings.Builder
-	summary.WriteString(fmt.Sprintf("Found %d error(s) of %d different type(s):\n", len(errors), len(errorTypeCounts)))
+	summary.WriteString(fmt.Sprintf("Found %d error(s) of %d different type(s) from %d root cause(s):\n", len(errors), len(errorTypeCounts), len(clusters)))
 
 	// List error types and counts
 	for errType, count := range errorTypeCounts {
@@ -242,5 +464,11 @@ func generateErrorSummary(errors []GoError) string {
 		summary.WriteString("6. Remove or use declared variables and imports\n")
 	}
 
+	// List the root causes, most important first
+	summary.WriteString("\nRoot causes in the order to fix them:\n")
+	for i, cluster := range clusters {
+		summary.WriteString(fmt.Sprintf("%d. %s (%d error(s)): %s\n", i+1, cluster.Cause, cluster.Count, cluster.Fix))
+	}
+
 	return summary.String()
 }
@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const brokenBuildFixture = `package main
+
+func main() {
+	total := 1
+	total = addOne(total)
+	println(total)
+}
+`
+
+func TestFixGoErrorsReadsSourceContext(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/broken")
+	writeTestFile(t, dir, "main.go", brokenBuildFixture)
+	t.Chdir(dir)
+
+	cmd := exec.Command("go", "build", "./...")
+	cmd.Dir = dir
+	buildOutput, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatal("expected the fixture to fail to build")
+	}
+
+	var output FixGoErrorsOutput
+	callTool(t, ctx, FixGoErrors, FixGoErrorsInput{ErrorOutput: string(buildOutput)}, &output)
+
+	var undefined *GoError
+	for i := range output.ParsedErrors {
+		if output.ParsedErrors[i].ErrorType == "Undefined Symbol" {
+			undefined = &output.ParsedErrors[i]
+		}
+	}
+	if undefined == nil {
+		t.Fatalf("no undefined symbol error parsed from %q: %+v", buildOutput, output.ParsedErrors)
+	}
+	if undefined.Line != 5 {
+		t.Errorf("error line = %d, want 5", undefined.Line)
+	}
+	for _, want := range []string{">    5 | \ttotal = addOne(total)", "     4 | \ttotal := 1", "     6 | \tprintln(total)"} {
+		if !strings.Contains(undefined.CodeSnippet, want) {
+			t.Errorf("snippet is missing %q:\n%s", want, undefined.CodeSnippet)
+		}
+	}
+	if !strings.Contains(undefined.Suggestion, "used on line 5 as: total = addOne(total)") {
+		t.Errorf("suggestion doesn't show the usage: %s", undefined.Suggestion)
+	}
+}
+
+func TestFixGoErrorsMissingSourceFile(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+
+	errorOutput := "./gone.go:3:2: undefined: helper\n\thelper()\n"
+	var output FixGoErrorsOutput
+	callTool(t, ctx, FixGoErrors, FixGoErrorsInput{ErrorOutput: errorOutput}, &output)
+
+	if len(output.ParsedErrors) == 0 {
+		t.Fatal("expected the error to be parsed")
+	}
+	got := output.ParsedErrors[0]
+	if got.CodeSnippet != "helper()" {
+		t.Errorf("snippet = %q, want the next output line", got.CodeSnippet)
+	}
+	if strings.Contains(got.Suggestion, "used on line") {
+		t.Errorf("suggestion shouldn't quote source it couldn't read: %s", got.Suggestion)
+	}
+}
+
+func TestReadSourceContextBounds(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "short.go", "package short\n\nvar x = 1\n")
+
+	snippet, source, err := readSourceContext(ctx, path, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source != "package short" || !strings.HasPrefix(snippet, ">    1 | package short\n") {
+		t.Errorf("got source %q and snippet:\n%s", source, snippet)
+	}
+	if _, _, err := readSourceContext(ctx, path, 10, 2); err == nil {
+		t.Error("expected an error for a line past the end of the file")
+	}
+}
+
+func TestFixGoErrorsSkipsSourceOutsideWorkspace(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	outside := writeTestFile(t, t.TempDir(), "secret.go", "package secret\n\nvar token = \"hunter2\"\n")
+
+	errorOutput := outside + ":3:5: undefined: token\n"
+	var output FixGoErrorsOutput
+	callTool(t, ctx, FixGoErrors, FixGoErrorsInput{ErrorOutput: errorOutput}, &output)
+
+	if len(output.ParsedErrors) == 0 {
+		t.Fatal("expected the error to be parsed")
+	}
+	if got := output.ParsedErrors[0]; strings.Contains(got.CodeSnippet, "hunter2") || strings.Contains(got.Suggestion, "hunter2") {
+		t.Errorf("read source outside the workspace: %+v", got)
+	}
+}
+
+const missingImportFixture = `package main
+
+func main() {
+	a := strings.ToUpper("a")
+	b := strings.ToLower("b")
+	c := strings.TrimSpace(" c ")
+	d := strings.Repeat("d", 2)
+	e := strings.TrimPrefix("ee", "e")
+	println(a, b, c, d, e, other)
+}
+`
+
+func TestFixGoErrorsClustersMissingImport(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/broken")
+	writeTestFile(t, dir, "main.go", missingImportFixture)
+	t.Chdir(dir)
+
+	cmd := exec.Command("go", "build", "./...")
+	cmd.Dir = dir
+	buildOutput, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatal("expected the fixture to fail to build")
+	}
+
+	var output FixGoErrorsOutput
+	callTool(t, ctx, FixGoErrors, FixGoErrorsInput{ErrorOutput: string(buildOutput)}, &output)
+
+	if len(output.RootCauses) != 2 {
+		t.Fatalf("root causes = %+v, want strings and other", output.RootCauses)
+	}
+	stringsCause := output.RootCauses[0]
+	if stringsCause.Cause != "undefined: strings" || stringsCause.Count != 5 || len(stringsCause.Locations) != 5 {
+		t.Errorf("first root cause = %+v, want the five undefined strings errors", stringsCause)
+	}
+	if len(stringsCause.Examples) != maxClusterExamples || !strings.Contains(stringsCause.Fix, "add the import") {
+		t.Errorf("examples %d, fix %q", len(stringsCause.Examples), stringsCause.Fix)
+	}
+	if other := output.RootCauses[1]; other.Cause != "undefined: other" || other.Count != 1 || strings.Contains(other.Fix, "used as a package") {
+		t.Errorf("second root cause = %+v", other)
+	}
+	if !strings.Contains(output.OverallSummary, "from 2 root cause(s)") || !strings.Contains(output.OverallSummary, "1. undefined: strings (5 error(s))") {
+		t.Errorf("summary:\n%s", output.OverallSummary)
+	}
+}
+
+func TestClusterGoErrorsOrder(t *testing.T) {
+	errorOutput := `# example.com/app
+./a.go:3:8: "fmt" imported and not used
+./b.go:7:2: undefined: helper
+./b.go:7:2: undefined: helper
+./c.go:4:1: syntax error: unexpected }
+./c.go:9:1: syntax error: unexpected )
+`
+	clusters := clusterGoErrors(parseGoErrors(context.Background(), errorOutput))
+
+	var causes []string
+	for _, cluster := range clusters {
+		causes = append(causes, fmt.Sprintf("%s (%d)", cluster.Cause, cluster.Count))
+	}
+	want := []string{"syntax errors in ./c.go (2)", "undefined: helper (1)", `unused import "fmt" in ./a.go (1)`}
+	if !reflect.DeepEqual(causes, want) {
+		t.Errorf("causes = %q, want %q", causes, want)
+	}
+}
@@ -0,0 +1,197 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GoFormatToolDefinition defines the go_format tool
+var GoFormatToolDefinition = ToolDefinition{
+	Name: "go_format",
+	Description: `Format Go files with gofmt and report the changes as unified diffs.
+'path' may be a single file or a directory, which is walked recursively (vendor and hidden directories
+are skipped). Set 'simplify' to also apply 'gofmt -s' simplifications such as x[a:len(x)] -> x[a:]
+and redundant composite literal types. Files are only rewritten when 'write' is true; otherwise the
+diffs are returned as a preview.`,
+	InputSchema:           GoFormatInputSchema,
+	Function:              GoFormat,
+	CountsTowardLoopLimit: true,
+}
+
+// GoFormatInput defines the input parameters for the go_format tool
+type GoFormatInput struct {
+	Path     string `json:"path,omitempty" jsonschema_description:"Go file or directory to format. Defaults to the current directory."`
+	Simplify bool   `json:"simplify,omitempty" jsonschema_description:"If true, apply 'gofmt -s' code simplifications"`
+	Write    bool   `json:"write,omitempty" jsonschema_description:"If true, write the formatted files; otherwise only report the diffs"`
+}
+
+// GoFormatInputSchema is the JSON schema for the go_format tool
+var GoFormatInputSchema = GenerateSchema[GoFormatInput]()
+
+// FormattedFile describes the changes gofmt makes to one file
+type FormattedFile struct {
+	Path string `json:"path"`
+	Diff string `json:"diff"`
+}
+
+// GoFormatOutput represents the structured output of the go_format tool
+type GoFormatOutput struct {
+	Simplify     bool            `json:"simplify"`
+	Written      bool            `json:"written"`
+	FilesChecked int             `json:"files_checked"`
+	Changed      []FormattedFile `json:"changed"`
+	Errors       []string        `json:"errors,omitempty"`
+	Message      string          `json:"message"`
+}
+
+// GoFormat implements the go_format tool functionality
+func GoFormat(ctx context.Context, input json.RawMessage) (string, error) {
+	formatInput := GoFormatInput{}
+	err := DecodeInput(input, &formatInput)
+	if err != nil {
+		return "", err
+	}
+
+	root := workspaceDir(ctx)
+	if formatInput.Path != "" {
+		root, err = ResolvePath(ctx, formatInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := exec.LookPath("gofmt"); err != nil {
+		return "", fmt.Errorf("gofmt not found in PATH: %w", err)
+	}
+
+	files, err := goFilesUnder(ctx, root)
+	if err != nil {
+		return "", err
+	}
+
+	output := GoFormatOutput{
+		Simplify:     formatInput.Simplify,
+		Written:      formatInput.Write,
+		FilesChecked: len(files),
+		Changed:      []FormattedFile{},
+	}
+
+	for _, path := range files {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		formatted, err := runGofmt(ctx, content, formatInput.Simplify)
+		if err != nil {
+			// Files with syntax errors are reported rather than aborting the whole run
+			output.Errors = append(output.Errors, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		if bytes.Equal(formatted, content) {
+			continue
+		}
+
+		output.Changed = append(output.Changed, FormattedFile{
+			Path: path,
+			Diff: unifiedDiff(path, string(content), string(formatted)),
+		})
+		if formatInput.Write {
+			info, err := os.Stat(path)
+			if err != nil {
+				return "", fmt.Errorf("failed to stat %s: %w", path, err)
+			}
+			if err := os.WriteFile(path, formatted, info.Mode().Perm()); err != nil {
+				return "", fmt.Errorf("failed to write %s: %w", path, err)
+			}
+			recordReadHash(path, formatted)
+		}
+	}
+
+	switch {
+	case len(output.Changed) == 0:
+		output.Message = fmt.Sprintf("All %d file(s) are already formatted.", len(files))
+	case formatInput.Write:
+		output.Message = fmt.Sprintf("Formatted %d of %d file(s).", len(output.Changed), len(files))
+	default:
+		output.Message = fmt.Sprintf("%d of %d file(s) need formatting. Set 'write' to apply the changes.", len(output.Changed), len(files))
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// runGofmt pipes content through gofmt, optionally with -s, and returns the formatted source
+func runGofmt(ctx context.Context, content []byte, simplify bool) ([]byte, error) {
+	var args []string
+	if simplify {
+		args = append(args, "-s")
+	}
+	cmd := exec.CommandContext(ctx, "gofmt", args...)
+	cmd.Stdin = bytes.NewReader(content)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// gofmt reports stdin positions as "<standard input>:line:col"
+		return nil, fmt.Errorf("%s", strings.TrimSpace(strings.ReplaceAll(stderr.String(), "<standard input>:", "")))
+	}
+	return stdout.Bytes(), nil
+}
+
+// goFilesUnder returns path itself if it is a file, or the Go files beneath it if it is a directory
+func goFilesUnder(ctx context.Context, path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		if filepath.Ext(path) != ".go" {
+			return nil, fmt.Errorf("%s is not a Go file", path)
+		}
+		return []string{path}, nil
+	}
+
+	var files []string
+	ignoreRules := ignoreRulesFor(ctx, path)
+	err = filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if ignoreRules.IgnoredPath(file, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if file != path && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Mode().IsRegular() && strings.HasSuffix(file, ".go") {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", path, err)
+	}
+	return files, nil
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+const simplifiableSource = `package shapes
+
+type Point struct{ X, Y int }
+
+func Tail(s []int) []int {
+	return s[1:len(s)]
+}
+
+var Corners = []Point{Point{0, 0}, Point{1, 1}}
+`
+
+func TestGoFormatSimplify(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "shapes.go", simplifiableSource)
+
+	// The source is already gofmt-clean, so only -s changes it
+	var plain GoFormatOutput
+	callTool(t, ctx, GoFormat, GoFormatInput{Path: "shapes.go"}, &plain)
+	if len(plain.Changed) != 0 {
+		t.Errorf("changed without simplify: %+v", plain.Changed)
+	}
+
+	var preview GoFormatOutput
+	callTool(t, ctx, GoFormat, GoFormatInput{Path: "shapes.go", Simplify: true}, &preview)
+	if len(preview.Changed) != 1 {
+		t.Fatalf("changed = %+v, want shapes.go", preview.Changed)
+	}
+	diff := preview.Changed[0].Diff
+	for _, want := range []string{"-\treturn s[1:len(s)]", "+\treturn s[1:]", "+var Corners = []Point{{0, 0}, {1, 1}}"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff is missing %q:\n%s", want, diff)
+		}
+	}
+	if readTestFile(t, path) != simplifiableSource {
+		t.Error("preview modified the file")
+	}
+
+	var written GoFormatOutput
+	callTool(t, ctx, GoFormat, GoFormatInput{Path: "shapes.go", Simplify: true, Write: true}, &written)
+	content := readTestFile(t, path)
+	if !written.Written || !strings.Contains(content, "return s[1:]") || strings.Contains(content, "Point{0, 0}") {
+		t.Errorf("written file was not simplified:\n%s", content)
+	}
+}
+
+func TestGoFormatDirectory(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "a.go", "package a\nfunc  A() {}\n")
+	writeTestFile(t, dir, "pkg/b.go", "package pkg\n\nfunc B() {}\n")
+	writeTestFile(t, dir, "pkg/broken.go", "package pkg\n\nfunc {\n")
+	writeTestFile(t, dir, "vendor/v/v.go", "package v\nfunc  V() {}\n")
+	writeTestFile(t, dir, ".cache/c.go", "package c\nfunc  C() {}\n")
+
+	var output GoFormatOutput
+	callTool(t, ctx, GoFormat, GoFormatInput{}, &output)
+
+	if output.FilesChecked != 3 {
+		t.Errorf("checked %d files, want 3 with vendor and hidden directories skipped", output.FilesChecked)
+	}
+	if len(output.Changed) != 1 || !strings.HasSuffix(output.Changed[0].Path, "a.go") {
+		t.Errorf("changed = %+v, want only a.go", output.Changed)
+	}
+	if len(output.Errors) != 1 || !strings.Contains(output.Errors[0], "broken.go") {
+		t.Errorf("errors = %v, want the syntax error in broken.go reported", output.Errors)
+	}
+}
+
+func TestGoFormatRejectsNonGoFile(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "notes.txt", "text")
+
+	if _, err := GoFormat(ctx, mustMarshal(t, GoFormatInput{Path: "notes.txt"})); err == nil {
+		t.Error("expected an error for a file that is not Go source")
+	}
+}
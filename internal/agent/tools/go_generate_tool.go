@@ -0,0 +1,297 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GoGenerateToolDefinition defines the go_generate tool
+var GoGenerateToolDefinition = ToolDefinition{
+	Name: "go_generate",
+	Description: `List or run the //go:generate directives in Go files.
+With 'action' 'list' (the default), returns each directive under 'path' with its file, line and
+command. With 'action' 'run', runs 'go generate' on the file or, for a directory, on all packages
+below it; 'filter' selects directives whose line matches a regular expression (-run) and
+'dry_run' prints the commands without running them (-n). The run is bounded by 'timeout_seconds'
+and reports its output and the files in the workspace it created, modified or deleted.`,
+	InputSchema:           GoGenerateInputSchema,
+	Function:              GoGenerate,
+	CountsTowardLoopLimit: true,
+}
+
+// GoGenerateInput defines the input parameters for the go_generate tool
+type GoGenerateInput struct {
+	Action         string `json:"action,omitempty" jsonschema_description:"'list' (default) to list directives or 'run' to run them"`
+	Path           string `json:"path,omitempty" jsonschema_description:"Go file or directory. Defaults to the current directory."`
+	Filter         string `json:"filter,omitempty" jsonschema_description:"Regular expression selecting the directives to list or run, matched against the whole directive line as go generate -run does" jsonschema_example:"stringer"`
+	DryRun         bool   `json:"dry_run,omitempty" jsonschema_description:"If true with 'run', print the commands without running them"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema_description:"Deadline for the run. Defaults to 300, capped at 1800."`
+}
+
+// GoGenerateInputSchema is the JSON schema for the go_generate tool
+var GoGenerateInputSchema = GenerateSchema[GoGenerateInput]()
+
+// GenerateDirective is one //go:generate line
+type GenerateDirective struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Command string `json:"command"`
+}
+
+// GoGenerateOutput represents the structured output of the go_generate tool
+type GoGenerateOutput struct {
+	Directives   []GenerateDirective `json:"directives"`
+	Command      string              `json:"command,omitempty"`
+	Success      bool                `json:"success"`
+	Stdout       string              `json:"stdout,omitempty"`
+	Stderr       string              `json:"stderr,omitempty"`
+	ErrorMessage string              `json:"error_message,omitempty"`
+	Created      []string            `json:"created,omitempty"`
+	Modified     []string            `json:"modified,omitempty"`
+	Deleted      []string            `json:"deleted,omitempty"`
+	Message      string              `json:"message"`
+}
+
+const (
+	defaultGenerateTimeout = 300 * time.Second
+	maxGenerateTimeout     = 1800 * time.Second
+)
+
+// generateDirectivePrefix starts a directive, which go generate only recognizes at the start of a line
+const generateDirectivePrefix = "//go:generate "
+
+// GoGenerate implements the go_generate tool functionality
+func GoGenerate(ctx context.Context, input json.RawMessage) (string, error) {
+	generateInput := GoGenerateInput{}
+	err := DecodeInput(input, &generateInput)
+	if err != nil {
+		return "", err
+	}
+
+	action := generateInput.Action
+	if action == "" {
+		action = "list"
+	}
+	if action != "list" && action != "run" {
+		return "", fmt.Errorf("invalid action: %s. Must be 'list' or 'run'", generateInput.Action)
+	}
+	var filter *regexp.Regexp
+	if generateInput.Filter != "" {
+		filter, err = regexp.Compile(generateInput.Filter)
+		if err != nil {
+			return "", fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+
+	path := workspaceDir(ctx)
+	if generateInput.Path != "" {
+		path, err = ResolvePath(ctx, generateInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	// Directives are reported relative to the searched directory
+	baseDir := path
+	if !info.IsDir() {
+		baseDir = filepath.Dir(path)
+	}
+	directives, err := generateDirectives(ctx, path, baseDir, filter)
+	if err != nil {
+		return "", err
+	}
+	output := GoGenerateOutput{Directives: directives, Success: true}
+	if action == "list" {
+		output.Message = fmt.Sprintf("Found %d //go:generate directive(s).", len(directives))
+		return marshalGoGenerateOutput(output)
+	}
+	if len(directives) == 0 {
+		output.Message = "No //go:generate directives to run."
+		return marshalGoGenerateOutput(output)
+	}
+
+	// go generate takes files relative to their package directory, or package patterns
+	dir, target := path, "./..."
+	if !info.IsDir() {
+		dir, target = filepath.Dir(path), filepath.Base(path)
+	}
+	args := []string{"-x"}
+	if generateInput.DryRun {
+		args = []string{"-n"}
+	}
+	if generateInput.Filter != "" {
+		args = append(args, "-run", generateInput.Filter)
+	}
+
+	timeout := defaultGenerateTimeout
+	if generateInput.TimeoutSeconds > 0 {
+		timeout = min(time.Duration(generateInput.TimeoutSeconds)*time.Second, maxGenerateTimeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Generators may write anywhere in the workspace, so compare all of it before and after
+	before, err := snapshotWorkspaceFiles(workspaceDir(ctx))
+	if err != nil {
+		return "", err
+	}
+	result, err := RunGoCommand(ctx, "generate", target, args, dir)
+	if err != nil {
+		return "", err
+	}
+	output.Command = result.Command
+	output.Success = result.Success
+	output.Stdout = result.Stdout
+	output.Stderr = result.Stderr
+	output.ErrorMessage = result.ErrorMessage
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		output.ErrorMessage = fmt.Sprintf("go generate timed out after %s", timeout)
+	}
+
+	after, err := snapshotWorkspaceFiles(workspaceDir(ctx))
+	if err != nil {
+		return "", err
+	}
+	for file, hash := range after {
+		previous, found := before[file]
+		switch {
+		case !found:
+			output.Created = append(output.Created, file)
+		case previous != hash:
+			output.Modified = append(output.Modified, file)
+		}
+	}
+	for file := range before {
+		if _, found := after[file]; !found {
+			output.Deleted = append(output.Deleted, file)
+		}
+	}
+	sort.Strings(output.Created)
+	sort.Strings(output.Modified)
+	sort.Strings(output.Deleted)
+
+	switch {
+	case !output.Success:
+		output.Message = "go generate failed; see stderr."
+	case generateInput.DryRun:
+		output.Message = "Dry run: the commands go generate would run are in stderr."
+	default:
+		output.Message = fmt.Sprintf("Ran go generate: %d file(s) created, %d modified, %d deleted.",
+			len(output.Created), len(output.Modified), len(output.Deleted))
+	}
+
+	return marshalGoGenerateOutput(output)
+}
+
+// generateDirectives returns the //go:generate directives in the Go files under path whose line
+// matches filter, if set, with file names relative to baseDir
+func generateDirectives(ctx context.Context, path, baseDir string, filter *regexp.Regexp) ([]GenerateDirective, error) {
+	files, err := goFilesUnder(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	directives := []GenerateDirective{}
+	for _, file := range files {
+		content, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", file, err)
+		}
+		name := file
+		if rel, err := filepath.Rel(baseDir, file); err == nil {
+			name = rel
+		}
+		scanner := bufio.NewScanner(content)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			text := strings.TrimRight(scanner.Text(), " \t\r")
+			command, found := strings.CutPrefix(text, generateDirectivePrefix)
+			if !found {
+				continue
+			}
+			if filter != nil && !filter.MatchString(text) {
+				continue
+			}
+			directives = append(directives, GenerateDirective{File: name, Line: line, Command: strings.TrimSpace(command)})
+		}
+		err = scanner.Err()
+		content.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+	}
+	return directives, nil
+}
+
+// snapshotWorkspaceFiles hashes the regular files under root, skipping hidden, vendor and
+// ignored paths, keyed by path relative to root
+func snapshotWorkspaceFiles(root string) (map[string][sha256.Size]byte, error) {
+	hashes := map[string][sha256.Size]byte{}
+	ignoreRules := LoadIgnoreRules(root)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if ignoreRules.IgnoredPath(path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		hasher := sha256.New()
+		if _, err := io.Copy(hasher, file); err != nil {
+			return err
+		}
+		var sum [sha256.Size]byte
+		copy(sum[:], hasher.Sum(nil))
+		hashes[relPath] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+	return hashes, nil
+}
+
+// marshalGoGenerateOutput renders the go_generate output as indented JSON
+func marshalGoGenerateOutput(output GoGenerateOutput) (string, error) {
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+	return string(jsonOutput), nil
+}
@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// writeGenerateFixture writes a module whose gen package copies, overwrites and removes files
+// when generated, and whose other package has a directive of its own
+func writeGenerateFixture(t *testing.T, dir string) {
+	t.Helper()
+	testGoModule(t, dir, "example.com/gen")
+	writeTestFile(t, dir, "gen/gen.go", `package gen
+
+//go:generate cp template.txt generated.txt
+//go:generate cp template.txt existing.txt
+
+// Gen is generated from template.txt.
+func Gen() {}
+
+// This is not a directive:
+// //go:generate false
+`)
+	writeTestFile(t, dir, "gen/clean.go", "package gen\n\n//go:generate rm stale.txt\n")
+	writeTestFile(t, dir, "gen/template.txt", "generated\n")
+	writeTestFile(t, dir, "gen/existing.txt", "old\n")
+	writeTestFile(t, dir, "gen/stale.txt", "stale\n")
+	writeTestFile(t, dir, "other/other.go", "package other\n\n//go:generate touch other.txt\n")
+}
+
+func TestGoGenerateList(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeGenerateFixture(t, dir)
+
+	var output GoGenerateOutput
+	callTool(t, ctx, GoGenerate, GoGenerateInput{}, &output)
+
+	want := []GenerateDirective{
+		{File: filepath.Join("gen", "clean.go"), Line: 3, Command: "rm stale.txt"},
+		{File: filepath.Join("gen", "gen.go"), Line: 3, Command: "cp template.txt generated.txt"},
+		{File: filepath.Join("gen", "gen.go"), Line: 4, Command: "cp template.txt existing.txt"},
+		{File: filepath.Join("other", "other.go"), Line: 3, Command: "touch other.txt"},
+	}
+	if !reflect.DeepEqual(output.Directives, want) {
+		t.Errorf("got directives %+v, want %+v", output.Directives, want)
+	}
+	if output.Command != "" || output.Message != "Found 4 //go:generate directive(s)." {
+		t.Errorf("listing ran a command or has the wrong message: %+v", output)
+	}
+
+	// A file path lists only its own directives, relative to its directory, and a filter narrows them
+	output = GoGenerateOutput{}
+	callTool(t, ctx, GoGenerate, GoGenerateInput{Path: "gen/gen.go", Filter: "existing"}, &output)
+	want = []GenerateDirective{{File: "gen.go", Line: 4, Command: "cp template.txt existing.txt"}}
+	if !reflect.DeepEqual(output.Directives, want) {
+		t.Errorf("got directives %+v, want %+v", output.Directives, want)
+	}
+}
+
+func TestGoGenerateRun(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeGenerateFixture(t, dir)
+
+	var output GoGenerateOutput
+	callTool(t, ctx, GoGenerate, GoGenerateInput{Action: "run", Path: "gen"}, &output)
+
+	if !output.Success {
+		t.Fatalf("go generate failed: %s\n%s", output.ErrorMessage, output.Stderr)
+	}
+	if content := readTestFile(t, filepath.Join(dir, "gen", "generated.txt")); content != "generated\n" {
+		t.Errorf("generated.txt = %q", content)
+	}
+	if got := []string{filepath.Join("gen", "generated.txt")}; !reflect.DeepEqual(output.Created, got) {
+		t.Errorf("created = %v, want %v", output.Created, got)
+	}
+	if got := []string{filepath.Join("gen", "existing.txt")}; !reflect.DeepEqual(output.Modified, got) {
+		t.Errorf("modified = %v, want %v", output.Modified, got)
+	}
+	if got := []string{filepath.Join("gen", "stale.txt")}; !reflect.DeepEqual(output.Deleted, got) {
+		t.Errorf("deleted = %v, want %v", output.Deleted, got)
+	}
+	// Packages outside the path are not generated
+	if _, err := os.Stat(filepath.Join(dir, "other", "other.txt")); err == nil {
+		t.Error("the other package's directive ran")
+	}
+}
+
+func TestGoGenerateRunFileWithFilter(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeGenerateFixture(t, dir)
+
+	var output GoGenerateOutput
+	callTool(t, ctx, GoGenerate, GoGenerateInput{Action: "run", Path: "gen/gen.go", Filter: "generated"}, &output)
+
+	if !output.Success {
+		t.Fatalf("go generate failed: %s\n%s", output.ErrorMessage, output.Stderr)
+	}
+	if !slices.Equal(output.Created, []string{filepath.Join("gen", "generated.txt")}) || len(output.Modified) != 0 || len(output.Deleted) != 0 {
+		t.Errorf("got created %v, modified %v, deleted %v; want only generated.txt created", output.Created, output.Modified, output.Deleted)
+	}
+}
+
+func TestGoGenerateDryRun(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeGenerateFixture(t, dir)
+
+	var output GoGenerateOutput
+	callTool(t, ctx, GoGenerate, GoGenerateInput{Action: "run", DryRun: true}, &output)
+
+	if !output.Success || len(output.Created)+len(output.Modified)+len(output.Deleted) != 0 {
+		t.Errorf("dry run changed files: %+v", output)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "gen", "stale.txt")); err != nil {
+		t.Errorf("dry run removed stale.txt: %v", err)
+	}
+}
+
+func TestGoGenerateInvalidInput(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	for _, input := range []GoGenerateInput{
+		{Action: "clean"},
+		{Filter: "("},
+		{Path: "missing"},
+	} {
+		if _, err := GoGenerate(ctx, mustMarshal(t, input)); err == nil {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GoImplementToolDefinition defines the go_implement tool
+var GoImplementToolDefinition = ToolDefinition{
+	Name: "go_implement",
+	Description: `Generate the methods a Go type is missing to satisfy an interface.
+The package containing the type is type-checked and every interface method the type lacks is
+returned as a stub with the exact signature and a 'panic("not implemented")' body. Methods that
+exist with a different signature are reported separately. The interface may be declared in the
+same package ('Handler'), an imported package ('io.Reader'), or by full import path
+('net/http.Handler'). Set 'insert' to append the stubs to the file declaring the type.`,
+	InputSchema:           GoImplementInputSchema,
+	Function:              GoImplement,
+	CountsTowardLoopLimit: true,
+}
+
+// GoImplementInput defines the input parameters for the go_implement tool
+type GoImplementInput struct {
+	Path          string `json:"path" jsonschema_required:"true" jsonschema_description:"Go file or package directory containing the type"`
+	Type          string `json:"type" jsonschema_required:"true" jsonschema_description:"Name of the type that should implement the interface"`
+	Interface     string `json:"interface" jsonschema_required:"true" jsonschema_description:"Interface to implement, e.g. 'io.Reader', 'net/http.Handler', or a local interface name" jsonschema_example:"io.ReadCloser"`
+	ValueReceiver bool   `json:"value_receiver,omitempty" jsonschema_description:"Generate value receivers instead of pointer receivers"`
+	Insert        bool   `json:"insert,omitempty" jsonschema_description:"If true, append the stubs (and any needed imports) to the file declaring the type"`
+}
+
+// GoImplementInputSchema is the JSON schema for the go_implement tool
+var GoImplementInputSchema = GenerateSchema[GoImplementInput]()
+
+// MethodStub is a generated method declaration for a missing interface method
+type MethodStub struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+}
+
+// GoImplementOutput represents the structured output of the go_implement tool
+type GoImplementOutput struct {
+	Type             string       `json:"type"`
+	Interface        string       `json:"interface"`
+	AlreadySatisfied bool         `json:"already_satisfied"`
+	Missing          []MethodStub `json:"missing"`
+	Mismatched       []string     `json:"mismatched,omitempty"`
+	Stubs            string       `json:"stubs,omitempty"`
+	Imports          []string     `json:"imports,omitempty"`
+	InsertedFile     string       `json:"inserted_file,omitempty"`
+	AddedImports     []string     `json:"added_imports,omitempty"`
+}
+
+// GoImplement implements the go_implement tool functionality
+func GoImplement(ctx context.Context, input json.RawMessage) (string, error) {
+	implInput := GoImplementInput{}
+	err := json.Unmarshal(input, &implInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if implInput.Path == "" || implInput.Type == "" || implInput.Interface == "" {
+		return "", fmt.Errorf("path, type, and interface are required")
+	}
+
+	implInput.Path, err = ResolvePath(ctx, implInput.Path)
+	if err != nil {
+		return "", err
+	}
+
+	dir := implInput.Path
+	if info, err := os.Stat(dir); err != nil {
+		return "", fmt.Errorf("failed to stat %s: %w", dir, err)
+	} else if !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	fset := token.NewFileSet()
+	pkg, err := typeCheckDir(ctx, fset, dir)
+	if err != nil {
+		return "", err
+	}
+
+	typeName, ok := pkg.Scope().Lookup(implInput.Type).(*types.TypeName)
+	if !ok {
+		return "", fmt.Errorf("type %s not found in package %s", implInput.Type, pkg.Name())
+	}
+	if _, isIface := typeName.Type().Underlying().(*types.Interface); isIface {
+		return "", fmt.Errorf("%s is an interface; methods can only be added to concrete types", implInput.Type)
+	}
+
+	iface, err := lookupInterface(fset, pkg, implInput.Interface)
+	if err != nil {
+		return "", err
+	}
+
+	var recvType types.Type = typeName.Type()
+	if !implInput.ValueReceiver {
+		recvType = types.NewPointer(recvType)
+	}
+
+	output := GoImplementOutput{
+		Type:      implInput.Type,
+		Interface: implInput.Interface,
+		Missing:   []MethodStub{},
+	}
+
+	// Qualify types from other packages by name, collecting the imports they need
+	imports := map[string]bool{}
+	qualifier := func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		imports[p.Path()] = true
+		return p.Name()
+	}
+
+	receiver := receiverName(implInput.Type)
+	if !implInput.ValueReceiver {
+		receiver += " *" + implInput.Type
+	} else {
+		receiver += " " + implInput.Type
+	}
+
+	var stubs strings.Builder
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		existing, _, _ := types.LookupFieldOrMethod(recvType, false, pkg, method.Name())
+		if existing != nil {
+			if fn, ok := existing.(*types.Func); !ok || !types.Identical(fn.Type(), method.Type()) {
+				output.Mismatched = append(output.Mismatched, fmt.Sprintf("%s: has %s, want %s",
+					method.Name(), types.ObjectString(existing, qualifier), signatureString(method.Name(), method.Type().(*types.Signature), qualifier)))
+			}
+			continue
+		}
+
+		signature := signatureString(method.Name(), method.Type().(*types.Signature), qualifier)
+		output.Missing = append(output.Missing, MethodStub{Name: method.Name(), Signature: signature})
+		fmt.Fprintf(&stubs, "\n// %s implements %s\nfunc (%s) %s {\n\tpanic(\"not implemented\")\n}\n",
+			method.Name(), implInput.Interface, receiver, signature)
+	}
+
+	output.AlreadySatisfied = len(output.Missing) == 0 && len(output.Mismatched) == 0
+	output.Stubs = strings.TrimPrefix(stubs.String(), "\n")
+	for path := range imports {
+		output.Imports = append(output.Imports, path)
+	}
+	sort.Strings(output.Imports)
+
+	if implInput.Insert && len(output.Missing) > 0 {
+		file := fset.Position(typeName.Pos()).Filename
+		added, err := insertMethodStubs(file, stubs.String(), output.Imports)
+		if err != nil {
+			return "", err
+		}
+		output.InsertedFile = file
+		output.AddedImports = added
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// typeCheckDir parses and type-checks the non-test Go files in dir that .metamorphignore doesn't
+// exclude. Type errors elsewhere in the package are tolerated so incomplete code can still be analyzed.
+func typeCheckDir(ctx context.Context, fset *token.FileSet, dir string) (*types.Package, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	var files []*ast.File
+	ignoreRules := ignoreRulesFor(ctx, dir)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if ignoreRules.IgnoredPath(filepath.Join(dir, name), false) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files found in %s", dir)
+	}
+
+	config := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	pkg, _ := config.Check(files[0].Name.Name, fset, files, nil)
+	if pkg == nil {
+		return nil, fmt.Errorf("failed to type-check package in %s", dir)
+	}
+
+	return pkg, nil
+}
+
+// lookupInterface resolves an interface name relative to pkg.
+// Qualified names may use an imported package's name or a full import path.
+func lookupInterface(fset *token.FileSet, pkg *types.Package, name string) (*types.Interface, error) {
+	var obj types.Object
+
+	dot := strings.LastIndex(name, ".")
+	if dot < 0 {
+		obj = pkg.Scope().Lookup(name)
+	} else {
+		pkgRef, ifaceName := name[:dot], name[dot+1:]
+		for _, imp := range pkg.Imports() {
+			if imp.Path() == pkgRef || (!strings.Contains(pkgRef, "/") && imp.Name() == pkgRef) {
+				obj = imp.Scope().Lookup(ifaceName)
+				break
+			}
+		}
+		if obj == nil {
+			imported, err := importer.ForCompiler(fset, "source", nil).Import(pkgRef)
+			if err != nil {
+				return nil, fmt.Errorf("failed to import %s: %w", pkgRef, err)
+			}
+			obj = imported.Scope().Lookup(ifaceName)
+		}
+	}
+
+	if obj == nil {
+		return nil, fmt.Errorf("interface %s not found", name)
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an interface", name)
+	}
+	return iface.Complete(), nil
+}
+
+// signatureString renders a method name and signature as it appears in a declaration
+func signatureString(name string, sig *types.Signature, qualifier types.Qualifier) string {
+	var params []string
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i)
+		paramName := param.Name()
+		if paramName == "" || paramName == "_" {
+			paramName = "p" + strconv.Itoa(i)
+		}
+		typ := types.TypeString(param.Type(), qualifier)
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			typ = "..." + types.TypeString(param.Type().(*types.Slice).Elem(), qualifier)
+		}
+		params = append(params, paramName+" "+typ)
+	}
+
+	var results []string
+	named := false
+	for i := 0; i < sig.Results().Len(); i++ {
+		result := sig.Results().At(i)
+		typ := types.TypeString(result.Type(), qualifier)
+		if result.Name() != "" && result.Name() != "_" {
+			named = true
+			typ = result.Name() + " " + typ
+		}
+		results = append(results, typ)
+	}
+
+	signature := name + "(" + strings.Join(params, ", ") + ")"
+	switch {
+	case len(results) == 1 && !named:
+		signature += " " + results[0]
+	case len(results) > 0:
+		signature += " (" + strings.Join(results, ", ") + ")"
+	}
+	return signature
+}
+
+// receiverName returns a conventional short receiver name for a type
+func receiverName(typeName string) string {
+	for _, r := range typeName {
+		return string(unicode.ToLower(r))
+	}
+	return "r"
+}
+
+// insertMethodStubs appends stubs to file, adding any missing imports, and formats the result.
+// It returns the imports that had to be added.
+func insertMethodStubs(file, stubs string, imports []string) ([]string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, file, content, parser.ImportsOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	existing := map[string]bool{}
+	for _, imp := range parsed.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		existing[path] = true
+	}
+
+	var added []string
+	for _, path := range imports {
+		if !existing[path] {
+			added = append(added, path)
+		}
+	}
+
+	var buf bytes.Buffer
+	if len(added) > 0 {
+		// Add a separate import declaration right after the package clause; gofmt keeps it valid
+		offset := fset.Position(parsed.Name.End()).Offset
+		buf.Write(content[:offset])
+		buf.WriteString("\n\nimport (\n")
+		for _, path := range added {
+			buf.WriteString("\t" + strconv.Quote(path) + "\n")
+		}
+		buf.WriteString(")")
+		buf.Write(content[offset:])
+	} else {
+		buf.Write(content)
+	}
+	buf.WriteString(stubs)
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s after inserting stubs: %w", file, err)
+	}
+
+	if err := os.WriteFile(file, formatted, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	recordReadHash(file, formatted)
+
+	return added, nil
+}
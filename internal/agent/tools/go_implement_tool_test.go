@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const implementFixture = `package store
+
+// Store keeps items in memory
+type Store struct {
+	items []string
+}
+
+// Close releases the store
+func (s *Store) Close() error {
+	return nil
+}
+
+// Len returns the wrong type for Sized
+func (s *Store) Len() int32 {
+	return int32(len(s.items))
+}
+
+// Sized reports a length
+type Sized interface {
+	Len() int
+}
+`
+
+func TestGoImplementMissingMethods(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/store")
+	writeTestFile(t, dir, "store.go", implementFixture)
+
+	var output GoImplementOutput
+	callTool(t, ctx, GoImplement, GoImplementInput{Path: "store.go", Type: "Store", Interface: "io.ReadWriteCloser"}, &output)
+
+	want := []MethodStub{
+		{Name: "Read", Signature: "Read(p []byte) (n int, err error)"},
+		{Name: "Write", Signature: "Write(p []byte) (n int, err error)"},
+	}
+	if !reflect.DeepEqual(output.Missing, want) {
+		t.Errorf("missing = %+v, want %+v", output.Missing, want)
+	}
+	if output.AlreadySatisfied {
+		t.Error("expected the interface not to be satisfied")
+	}
+	if !strings.Contains(output.Stubs, "func (s *Store) Read(p []byte) (n int, err error) {\n\tpanic(\"not implemented\")\n}") {
+		t.Errorf("unexpected stubs:\n%s", output.Stubs)
+	}
+}
+
+func TestGoImplementSkipsIgnoredFiles(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/store")
+	writeTestFile(t, dir, "store.go", implementFixture)
+	writeTestFile(t, dir, "store_gen.go", "package store\n\nfunc (s *Store) Read(p []byte) (int, error) {\n\treturn 0, nil\n}\n")
+	writeTestFile(t, dir, MetamorphIgnoreFile, "*_gen.go\n")
+
+	var output GoImplementOutput
+	callTool(t, ctx, GoImplement, GoImplementInput{Path: "store.go", Type: "Store", Interface: "io.ReadWriteCloser"}, &output)
+
+	if len(output.Missing) != 2 || output.Missing[0].Name != "Read" {
+		t.Errorf("missing = %+v, want Read and Write with the ignored file left out", output.Missing)
+	}
+}
+
+func TestGoImplementInsertedStubsCompile(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/store")
+	path := writeTestFile(t, dir, "store.go", implementFixture)
+
+	var output GoImplementOutput
+	callTool(t, ctx, GoImplement, GoImplementInput{Path: ".", Type: "Store", Interface: "io.WriterTo", Insert: true}, &output)
+
+	if !reflect.DeepEqual(output.AddedImports, []string{"io"}) {
+		t.Errorf("added imports = %v, want [io]", output.AddedImports)
+	}
+	content := readTestFile(t, path)
+	if !strings.Contains(content, "func (s *Store) WriteTo(w io.Writer) (n int64, err error) {") {
+		t.Errorf("stub was not inserted:\n%s", content)
+	}
+
+	// Prove the stub satisfies the interface by compiling an assertion against it
+	writeTestFile(t, dir, "assert.go", "package store\n\nimport \"io\"\n\nvar _ io.WriterTo = (*Store)(nil)\n")
+	result, err := RunGoCommand(ctx, "build", "./...", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Success {
+		t.Errorf("package with inserted stubs doesn't compile: %s", result.Stderr)
+	}
+
+	var again GoImplementOutput
+	callTool(t, ctx, GoImplement, GoImplementInput{Path: ".", Type: "Store", Interface: "io.WriterTo"}, &again)
+	if !again.AlreadySatisfied || len(again.Missing) != 0 {
+		t.Errorf("expected the interface to be satisfied after inserting: %+v", again)
+	}
+}
+
+func TestGoImplementMismatchedMethod(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/store")
+	writeTestFile(t, dir, "store.go", implementFixture)
+
+	var output GoImplementOutput
+	callTool(t, ctx, GoImplement, GoImplementInput{Path: "store.go", Type: "Store", Interface: "Sized"}, &output)
+
+	if len(output.Missing) != 0 || len(output.Mismatched) != 1 || !strings.Contains(output.Mismatched[0], "want Len() int") {
+		t.Errorf("expected Len to be reported as mismatched: %+v", output)
+	}
+	if output.AlreadySatisfied {
+		t.Error("a mismatched method doesn't satisfy the interface")
+	}
+}
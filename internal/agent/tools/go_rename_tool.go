@@ -0,0 +1,347 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GoRenameToolDefinition defines the go_rename tool
+var GoRenameToolDefinition = ToolDefinition{
+	Name: "go_rename",
+	Description: `Rename a Go identifier and every reference to it, with scope awareness.
+Unlike a textual replace, only the identifier that resolves to the chosen declaration is renamed,
+so renaming 'Get' leaves 'GetAll' and unrelated locals named 'Get' untouched. Name the symbol as
+'Name' for a package-level declaration or 'Type.Member' for a method or field; pass 'line' to pick
+a local variable or parameter declared on that line. Uses gopls when installed, which also updates
+other packages; otherwise the package containing 'path' (including its in-package tests) is rewritten.
+Returns the files changed.`,
+	InputSchema:           GoRenameInputSchema,
+	Function:              GoRename,
+	CountsTowardLoopLimit: true,
+}
+
+// GoRenameInput defines the input parameters for the go_rename tool
+type GoRenameInput struct {
+	Path    string `json:"path" jsonschema_required:"true" jsonschema_description:"Go file declaring the symbol"`
+	Symbol  string `json:"symbol" jsonschema_required:"true" jsonschema_description:"Symbol to rename: 'Name' or 'Type.Member'" jsonschema_example:"Server.Start"`
+	NewName string `json:"new_name" jsonschema_required:"true" jsonschema_description:"New identifier"`
+	Line    int    `json:"line,omitempty" jsonschema_description:"Line of the declaration, needed to rename a local variable or parameter"`
+}
+
+// GoRenameInputSchema is the JSON schema for the go_rename tool
+var GoRenameInputSchema = GenerateSchema[GoRenameInput]()
+
+// GoRenameOutput represents the structured output of the go_rename tool
+type GoRenameOutput struct {
+	Engine       string   `json:"engine"`
+	Symbol       string   `json:"symbol"`
+	NewName      string   `json:"new_name"`
+	References   int      `json:"references,omitempty"`
+	FilesChanged []string `json:"files_changed"`
+	Warnings     []string `json:"warnings,omitempty"`
+}
+
+// GoRename implements the go_rename tool functionality
+func GoRename(ctx context.Context, input json.RawMessage) (string, error) {
+	renameInput := GoRenameInput{}
+	if err := DecodeInput(input, &renameInput); err != nil {
+		return "", err
+	}
+
+	if renameInput.Path == "" || renameInput.Symbol == "" || renameInput.NewName == "" {
+		return "", fmt.Errorf("path, symbol, and new_name parameters are required")
+	}
+	if !token.IsIdentifier(renameInput.NewName) {
+		return "", fmt.Errorf("invalid new_name: %q is not a Go identifier", renameInput.NewName)
+	}
+	path, err := ResolvePath(ctx, renameInput.Path)
+	if err != nil {
+		return "", err
+	}
+
+	fset := token.NewFileSet()
+	info, err := typeCheckRenamePackage(ctx, fset, path)
+	if err != nil {
+		return "", err
+	}
+
+	obj, err := findRenameTarget(fset, info, path, renameInput.Symbol, renameInput.Line)
+	if err != nil {
+		return "", err
+	}
+	if obj.Name() == renameInput.NewName {
+		return "", fmt.Errorf("%s is already named %s", renameInput.Symbol, renameInput.NewName)
+	}
+	if err := checkRenameConflict(obj, renameInput.NewName); err != nil {
+		return "", err
+	}
+
+	output := GoRenameOutput{
+		Symbol:       renameInput.Symbol,
+		NewName:      renameInput.NewName,
+		FilesChanged: []string{},
+	}
+
+	if _, lookErr := exec.LookPath("gopls"); lookErr == nil {
+		output.Engine = "gopls"
+		output.FilesChanged, err = goplsRename(ctx, fset.Position(obj.Pos()), renameInput.NewName)
+	} else {
+		output.Engine = "go/types"
+		output.References, output.FilesChanged, err = rewriteIdentifiers(fset, info, obj, renameInput.NewName)
+		if (obj.Exported() && obj.Parent() == obj.Pkg().Scope()) || isExportedMember(obj) {
+			output.Warnings = append(output.Warnings,
+				"gopls is not installed, so only this package was updated; other packages and external test packages that use the symbol must be renamed separately")
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// typeCheckRenamePackage type-checks the package containing file together with its
+// in-package test files, recording definitions and uses of every identifier. Other files that
+// .metamorphignore excludes are left out.
+func typeCheckRenamePackage(ctx context.Context, fset *token.FileSet, file string) (*types.Info, error) {
+	target, err := parser.ParseFile(fset, file, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	dir := filepath.Dir(file)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	var files []*ast.File
+	ignoreRules := ignoreRulesFor(ctx, dir)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if path != filepath.Clean(file) && ignoreRules.IgnoredPath(path, false) {
+			continue
+		}
+		parsed, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		// External test packages (package foo_test) are checked separately by the compiler
+		if parsed.Name.Name == target.Name.Name {
+			files = append(files, parsed)
+		}
+	}
+
+	info := &types.Info{
+		Defs: map[*ast.Ident]types.Object{},
+		Uses: map[*ast.Ident]types.Object{},
+	}
+	config := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	if pkg, _ := config.Check(target.Name.Name, fset, files, info); pkg == nil {
+		return nil, fmt.Errorf("failed to type-check package in %s", dir)
+	}
+	return info, nil
+}
+
+// findRenameTarget resolves symbol to the object declared in file
+func findRenameTarget(fset *token.FileSet, info *types.Info, file, symbol string, line int) (types.Object, error) {
+	typeName, member, isMember := strings.Cut(symbol, ".")
+	name := typeName
+	if isMember {
+		name = member
+	}
+
+	var candidates []types.Object
+	for ident, obj := range info.Defs {
+		if obj == nil || ident.Name != name {
+			continue
+		}
+		position := fset.Position(ident.Pos())
+		if !sameFile(position.Filename, file) || (line > 0 && position.Line != line) {
+			continue
+		}
+
+		switch {
+		case isMember:
+			if owner := memberOwner(obj); owner == nil || owner.Obj().Name() != typeName {
+				continue
+			}
+		case line == 0:
+			// Without a line only package-level declarations are considered
+			if obj.Parent() == nil || obj.Parent() != obj.Pkg().Scope() {
+				continue
+			}
+		}
+		candidates = append(candidates, obj)
+	}
+
+	switch len(candidates) {
+	case 0:
+		if line > 0 {
+			return nil, fmt.Errorf("no declaration of %s found on line %d of %s", symbol, line, file)
+		}
+		return nil, fmt.Errorf("no declaration of %s found in %s", symbol, file)
+	case 1:
+		return candidates[0], nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Pos() < candidates[j].Pos() })
+	var lines []string
+	for _, candidate := range candidates {
+		lines = append(lines, fmt.Sprint(fset.Position(candidate.Pos()).Line))
+	}
+	return nil, fmt.Errorf("%s is declared more than once in %s (lines %s); pass line to choose one",
+		symbol, file, strings.Join(lines, ", "))
+}
+
+// memberOwner returns the named type a method or struct field belongs to, or nil
+func memberOwner(obj types.Object) *types.Named {
+	switch v := obj.(type) {
+	case *types.Func:
+		sig, ok := v.Type().(*types.Signature)
+		if !ok || sig.Recv() == nil {
+			return nil
+		}
+		recv := sig.Recv().Type()
+		if ptr, ok := recv.(*types.Pointer); ok {
+			recv = ptr.Elem()
+		}
+		named, _ := recv.(*types.Named)
+		return named
+	case *types.Var:
+		if !v.IsField() {
+			return nil
+		}
+		// Fields do not record their struct, so search the package's named types
+		scope := v.Pkg().Scope()
+		for _, name := range scope.Names() {
+			named, ok := scope.Lookup(name).Type().(*types.Named)
+			if !ok {
+				continue
+			}
+			if st, ok := named.Underlying().(*types.Struct); ok {
+				for i := 0; i < st.NumFields(); i++ {
+					if st.Field(i) == v {
+						return named
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isExportedMember reports whether obj is an exported method or field of an exported type
+func isExportedMember(obj types.Object) bool {
+	owner := memberOwner(obj)
+	return owner != nil && obj.Exported() && owner.Obj().Exported()
+}
+
+// checkRenameConflict rejects a rename that would collide with an existing declaration
+func checkRenameConflict(obj types.Object, newName string) error {
+	if owner := memberOwner(obj); owner != nil {
+		if existing, _, _ := types.LookupFieldOrMethod(owner, true, obj.Pkg(), newName); existing != nil {
+			return fmt.Errorf("cannot rename: %s already has a field or method named %s", owner.Obj().Name(), newName)
+		}
+		return nil
+	}
+	if obj.Parent() == nil {
+		return nil
+	}
+	if _, existing := obj.Parent().LookupParent(newName, obj.Pos()); existing != nil && existing.Parent() == obj.Parent() {
+		return fmt.Errorf("cannot rename: %s is already declared in the same scope", newName)
+	}
+	return nil
+}
+
+// rewriteIdentifiers renames every identifier that defines or uses obj and writes the
+// changed files, returning the number of identifiers renamed and the files changed
+func rewriteIdentifiers(fset *token.FileSet, info *types.Info, obj types.Object, newName string) (int, []string, error) {
+	offsets := map[string][]int{}
+	count := 0
+	collect := func(idents map[*ast.Ident]types.Object) {
+		for ident, target := range idents {
+			if target == obj {
+				position := fset.Position(ident.Pos())
+				offsets[position.Filename] = append(offsets[position.Filename], position.Offset)
+				count++
+			}
+		}
+	}
+	collect(info.Defs)
+	collect(info.Uses)
+
+	oldName := obj.Name()
+	var changed []string
+	for file, fileOffsets := range offsets {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to read file '%s': %w", file, err)
+		}
+		// Replace from the end so earlier offsets stay valid
+		sort.Sort(sort.Reverse(sort.IntSlice(fileOffsets)))
+		for _, offset := range fileOffsets {
+			if string(content[offset:offset+len(oldName)]) != oldName {
+				return 0, nil, fmt.Errorf("unexpected content at %s offset %d; is the file being edited?", file, offset)
+			}
+			content = append(content[:offset], append([]byte(newName), content[offset+len(oldName):]...)...)
+		}
+		if err := os.WriteFile(file, content, 0644); err != nil {
+			return 0, nil, fmt.Errorf("failed to write file: %w", err)
+		}
+		recordReadHash(file, content)
+		changed = append(changed, file)
+	}
+	sort.Strings(changed)
+	return count, changed, nil
+}
+
+// goplsRename runs 'gopls rename' on the declaration at position and returns the files it changed
+func goplsRename(ctx context.Context, position token.Position, newName string) ([]string, error) {
+	target := fmt.Sprintf("%s:%d:%d", position.Filename, position.Line, position.Column)
+	cmd := exec.CommandContext(ctx, "gopls", "rename", "-l", "-w", target, newName)
+	cmd.Dir = filepath.Dir(position.Filename)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("gopls rename failed: %s", strings.TrimSpace(string(out)))
+	}
+
+	changed := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			changed = append(changed, line)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// sameFile reports whether two paths refer to the same file
+func sameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const renameFixture = `package store
+
+type Store struct{ items map[string]string }
+
+func (s *Store) Get(key string) string { return s.items[key] }
+
+func (s *Store) GetAll() map[string]string { return s.items }
+
+// Get returns the value stored under key
+func Get(s *Store, key string) string {
+	return s.Get(key)
+}
+
+func lookup(s *Store) string {
+	Get := "shadowed"
+	return Get
+}
+`
+
+func TestGoRenameLeavesSimilarNames(t *testing.T) {
+	if _, err := exec.LookPath("gopls"); err == nil {
+		t.Skip("gopls is installed; this test covers the go/types engine")
+	}
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/store")
+	path := writeTestFile(t, dir, "store.go", renameFixture)
+	testPath := writeTestFile(t, dir, "store_test.go", `package store
+
+import "testing"
+
+func TestGet(t *testing.T) {
+	s := &Store{items: map[string]string{"a": "1"}}
+	if Get(s, "a") != s.GetAll()["a"] {
+		t.Fatal("mismatch")
+	}
+}
+`)
+
+	var output GoRenameOutput
+	callTool(t, ctx, GoRename, GoRenameInput{Path: "store.go", Symbol: "Get", NewName: "Fetch"}, &output)
+
+	if output.Engine != "go/types" || output.References != 2 {
+		t.Errorf("got %+v, want the declaration and one reference renamed", output)
+	}
+	if len(output.FilesChanged) != 2 {
+		t.Errorf("files changed = %v, want store.go and store_test.go", output.FilesChanged)
+	}
+
+	got := readTestFile(t, path)
+	for _, want := range []string{
+		"func Fetch(s *Store, key string) string {",
+		"func (s *Store) Get(key string) string",
+		"func (s *Store) GetAll() map[string]string",
+		"return s.Get(key)",
+		"Get := \"shadowed\"\n\treturn Get\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("store.go is missing %q:\n%s", want, got)
+		}
+	}
+	if got := readTestFile(t, testPath); !strings.Contains(got, "Fetch(s, \"a\") != s.GetAll()") {
+		t.Errorf("test file reference wasn't renamed:\n%s", got)
+	}
+
+	cmd := exec.Command("go", "vet", "./...")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("renamed package doesn't build: %v\n%s", err, out)
+	}
+}
+
+func TestGoRenameMethod(t *testing.T) {
+	if _, err := exec.LookPath("gopls"); err == nil {
+		t.Skip("gopls is installed; this test covers the go/types engine")
+	}
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/store")
+	path := writeTestFile(t, dir, "store.go", renameFixture)
+
+	var output GoRenameOutput
+	callTool(t, ctx, GoRename, GoRenameInput{Path: "store.go", Symbol: "Store.Get", NewName: "Lookup"}, &output)
+
+	got := readTestFile(t, path)
+	for _, want := range []string{"func (s *Store) Lookup(key string) string", "return s.Lookup(key)", "func Get(s *Store", "GetAll()"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("store.go is missing %q:\n%s", want, got)
+		}
+	}
+	if len(output.FilesChanged) != 1 || filepath.Base(output.FilesChanged[0]) != "store.go" {
+		t.Errorf("files changed = %v", output.FilesChanged)
+	}
+}
+
+func TestGoRenameRejectsConflictsAndBadNames(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/store")
+	writeTestFile(t, dir, "store.go", renameFixture)
+
+	tests := []struct {
+		name  string
+		input GoRenameInput
+		want  string
+	}{
+		{"invalid identifier", GoRenameInput{Path: "store.go", Symbol: "Get", NewName: "1st"}, "not a Go identifier"},
+		{"same name", GoRenameInput{Path: "store.go", Symbol: "Get", NewName: "Get"}, "already named"},
+		{"conflict", GoRenameInput{Path: "store.go", Symbol: "Store.Get", NewName: "GetAll"}, "GetAll"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GoRename(ctx, mustMarshal(t, tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// GoTestToolDefinition defines the go_test tool
+var GoTestToolDefinition = ToolDefinition{
+	Name: "go_test",
+	Description: `Run a single Go test, or one of its subtests, and report just that test's result.
+Runs 'go test -json -run ^Test$/^Sub$' so only the named test (and, with 'subtest', only that
+subtest) executes, then extracts the test's status, duration and output from the JSON events.
+Subtest names are given as they appear in t.Run; spaces are matched as the underscores go test
+reports. Separate the names of nested subtests with '/'.`,
+	InputSchema:           GoTestInputSchema,
+	Function:              GoTest,
+	CountsTowardLoopLimit: true,
+}
+
+// GoTestInput defines the input parameters for the go_test tool
+type GoTestInput struct {
+	Test       string `json:"test" jsonschema_required:"true" jsonschema_description:"Name of the top-level test function" jsonschema_example:"TestParse"`
+	Subtest    string `json:"subtest,omitempty" jsonschema_description:"Name of the subtest to run, with '/' between nested levels" jsonschema_example:"empty input"`
+	Path       string `json:"path,omitempty" jsonschema_description:"Package containing the test. Defaults to '.'."`
+	WorkingDir string `json:"working_dir,omitempty" jsonschema_description:"Working directory (defaults to current directory if empty)"`
+}
+
+// GoTestInputSchema is the JSON schema for the go_test tool
+var GoTestInputSchema = GenerateSchema[GoTestInput]()
+
+// GoTestOutput represents the structured output of the go_test tool
+type GoTestOutput struct {
+	Success       bool    `json:"success"`
+	Command       string  `json:"command"`
+	Test          string  `json:"test"`
+	Package       string  `json:"package,omitempty"`
+	Status        string  `json:"status"`
+	Elapsed       float64 `json:"elapsed_seconds,omitempty"`
+	Output        string  `json:"output,omitempty"`
+	PackageOutput string  `json:"package_output,omitempty"`
+	ErrorMessage  string  `json:"error_message,omitempty"`
+}
+
+// testEvent is one line of 'go test -json' output
+type testEvent struct {
+	Action  string  `json:"Action"`
+	Package string  `json:"Package"`
+	Test    string  `json:"Test"`
+	Output  string  `json:"Output"`
+	Elapsed float64 `json:"Elapsed"`
+}
+
+// testFuncPattern matches the name of a top-level test function
+var testFuncPattern = regexp.MustCompile(`^Test([^a-z].*)?$`)
+
+// GoTest implements the go_test tool functionality
+func GoTest(ctx context.Context, input json.RawMessage) (string, error) {
+	testInput := GoTestInput{}
+	err := DecodeInput(input, &testInput)
+	if err != nil {
+		return "", err
+	}
+
+	if !testFuncPattern.MatchString(testInput.Test) || !token.IsIdentifier(testInput.Test) {
+		return "", fmt.Errorf("invalid test name %q: expected a test function such as TestParse", testInput.Test)
+	}
+	runPattern, fullName, err := subtestRunPattern(testInput.Test, testInput.Subtest)
+	if err != nil {
+		return "", err
+	}
+
+	path := testInput.Path
+	if path == "" {
+		path = "."
+	}
+
+	result, err := RunGoCommand(ctx, "test", path, []string{"-json", "-run", runPattern}, testInput.WorkingDir)
+	if err != nil {
+		return "", err
+	}
+
+	output := GoTestOutput{
+		Command:      result.Command,
+		Test:         fullName,
+		Status:       "not_run",
+		ErrorMessage: result.ErrorMessage,
+	}
+	extractTestResult(result.Stdout, &output)
+	if output.Status == "not_run" && output.PackageOutput == "" {
+		output.PackageOutput = result.Stderr
+	}
+	output.Success = result.Success && output.Status == "pass"
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// subtestRunPattern builds the -run pattern selecting exactly test and, if set, the subtest path
+// below it, and returns it along with the full test name go test reports. Each level is quoted
+// and anchored so that names containing regexp metacharacters, or prefixes of other names, only
+// match themselves.
+func subtestRunPattern(test, subtest string) (string, string, error) {
+	parts := []string{"^" + regexp.QuoteMeta(test) + "$"}
+	fullName := test
+	if subtest == "" {
+		return parts[0], fullName, nil
+	}
+
+	for _, level := range strings.Split(subtest, "/") {
+		if strings.TrimSpace(level) == "" {
+			return "", "", fmt.Errorf("invalid subtest %q: names between '/' must not be empty", subtest)
+		}
+		// go test reports subtest names with whitespace replaced by underscores
+		level = strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return '_'
+			}
+			return r
+		}, level)
+		if !strings.ContainsFunc(level, unicode.IsPrint) {
+			return "", "", fmt.Errorf("invalid subtest %q: name has no printable characters", subtest)
+		}
+		parts = append(parts, "^"+regexp.QuoteMeta(level)+"$")
+		fullName += "/" + level
+	}
+	return strings.Join(parts, "/"), fullName, nil
+}
+
+// extractTestResult fills in the status, duration and output of output.Test from 'go test -json'
+// output. Package-level output, such as build errors, is collected separately.
+func extractTestResult(stdout string, output *GoTestOutput) {
+	var testOutput, packageOutput strings.Builder
+	for _, line := range strings.Split(stdout, "\n") {
+		var event testEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			if strings.TrimSpace(line) != "" {
+				packageOutput.WriteString(line + "\n")
+			}
+			continue
+		}
+
+		switch {
+		case event.Test == output.Test:
+			output.Package = event.Package
+			switch event.Action {
+			case "output":
+				testOutput.WriteString(event.Output)
+			case "pass", "fail", "skip":
+				output.Status = event.Action
+				output.Elapsed = event.Elapsed
+			}
+		case event.Test == "" && (event.Action == "output" || event.Action == "build-output"):
+			packageOutput.WriteString(event.Output)
+		}
+	}
+
+	output.Output = testOutput.String()
+	if output.Status != "pass" {
+		output.PackageOutput = packageOutput.String()
+	}
+}
@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubtestRunPattern(t *testing.T) {
+	tests := []struct {
+		test, subtest     string
+		wantRun, wantFull string
+	}{
+		{"TestParse", "", "^TestParse$", "TestParse"},
+		{"TestParse", "empty", "^TestParse$/^empty$", "TestParse/empty"},
+		{"TestParse", "empty input", "^TestParse$/^empty_input$", "TestParse/empty_input"},
+		{"TestParse", "a+b/(nested)", `^TestParse$/^a\+b$/^\(nested\)$`, "TestParse/a+b/(nested)"},
+	}
+	for _, tt := range tests {
+		run, full, err := subtestRunPattern(tt.test, tt.subtest)
+		if err != nil {
+			t.Fatalf("subtestRunPattern(%q, %q): %v", tt.test, tt.subtest, err)
+		}
+		if run != tt.wantRun || full != tt.wantFull {
+			t.Errorf("subtestRunPattern(%q, %q) = %q, %q; want %q, %q", tt.test, tt.subtest, run, full, tt.wantRun, tt.wantFull)
+		}
+	}
+
+	for _, subtest := range []string{"a//b", "a/ ", "/"} {
+		if _, _, err := subtestRunPattern("TestParse", subtest); err == nil {
+			t.Errorf("subtest %q: expected an error", subtest)
+		}
+	}
+}
+
+func TestExtractTestResult(t *testing.T) {
+	stdout := `{"Action":"run","Package":"example.com/app","Test":"TestParse"}
+{"Action":"run","Package":"example.com/app","Test":"TestParse/empty"}
+{"Action":"output","Package":"example.com/app","Test":"TestParse/empty","Output":"    parse_test.go:9: got 1\n"}
+{"Action":"fail","Package":"example.com/app","Test":"TestParse/empty","Elapsed":0.01}
+{"Action":"fail","Package":"example.com/app","Test":"TestParse","Elapsed":0.02}
+{"Action":"output","Package":"example.com/app","Output":"FAIL\n"}
+`
+	output := GoTestOutput{Test: "TestParse/empty", Status: "not_run"}
+	extractTestResult(stdout, &output)
+
+	if output.Status != "fail" || output.Elapsed != 0.01 || output.Package != "example.com/app" {
+		t.Errorf("got %+v", output)
+	}
+	if output.Output != "    parse_test.go:9: got 1\n" || output.PackageOutput != "FAIL\n" {
+		t.Errorf("output %q, package output %q", output.Output, output.PackageOutput)
+	}
+}
+
+func TestGoTestRunsOnlySubtest(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+	writeTestFile(t, dir, "app_test.go", `package app
+
+import "testing"
+
+func TestCases(t *testing.T) {
+	t.Run("passes here", func(t *testing.T) {})
+	t.Run("fails", func(t *testing.T) { t.Error("boom") })
+}
+`)
+
+	var output GoTestOutput
+	callTool(t, ctx, GoTest, GoTestInput{Test: "TestCases", Subtest: "passes here"}, &output)
+	if !output.Success || output.Status != "pass" || output.Test != "TestCases/passes_here" {
+		t.Errorf("got %+v, want the passing subtest alone", output)
+	}
+
+	output = GoTestOutput{}
+	callTool(t, ctx, GoTest, GoTestInput{Test: "TestCases", Subtest: "fails"}, &output)
+	if output.Success || output.Status != "fail" || !strings.Contains(output.Output, "boom") {
+		t.Errorf("got %+v, want the failing subtest with its output", output)
+	}
+
+	output = GoTestOutput{}
+	callTool(t, ctx, GoTest, GoTestInput{Test: "TestCases", Subtest: "missing"}, &output)
+	if output.Success || output.Status != "not_run" {
+		t.Errorf("got %+v, want an unknown subtest reported as not run", output)
+	}
+}
+
+func TestGoTestRejectsInvalidNames(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	for _, name := range []string{"", "Parse", "Testparse", "TestA.*"} {
+		if _, err := GoTest(ctx, mustMarshal(t, GoTestInput{Test: name})); err == nil {
+			t.Errorf("test %q: expected an error", name)
+		}
+	}
+}
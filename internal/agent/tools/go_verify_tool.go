@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// GoVerifyToolDefinition defines the go_verify tool
+var GoVerifyToolDefinition = ToolDefinition{
+	Name: "go_verify",
+	Description: `Check that downloaded modules match go.sum and report module download errors.
+Runs 'go mod verify' and lists every module whose cached copy fails verification, then runs
+'go mod download -json' and reports modules that cannot be downloaded or whose checksum does not
+match go.sum. Use it to diagnose go.sum mismatches, tampered module caches, and missing modules.`,
+	InputSchema:           GoVerifyInputSchema,
+	Function:              GoVerify,
+	CountsTowardLoopLimit: true,
+}
+
+// GoVerifyInput defines the input parameters for the go_verify tool
+type GoVerifyInput struct {
+	SkipDownload bool   `json:"skip_download,omitempty" jsonschema_description:"If true, only run 'go mod verify' and skip 'go mod download'"`
+	WorkingDir   string `json:"working_dir,omitempty" jsonschema_description:"Working directory (defaults to current directory if empty)"`
+}
+
+// GoVerifyInputSchema is the JSON schema for the go_verify tool
+var GoVerifyInputSchema = GenerateSchema[GoVerifyInput]()
+
+// ModuleProblem describes a module that failed verification or download
+type ModuleProblem struct {
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+	Error   string `json:"error"`
+}
+
+// GoVerifyOutput represents the structured output of the go_verify tool
+type GoVerifyOutput struct {
+	Verified       bool            `json:"verified"`
+	VerifyFailures []ModuleProblem `json:"verify_failures"`
+	DownloadErrors []ModuleProblem `json:"download_errors,omitempty"`
+	Downloaded     int             `json:"downloaded,omitempty"`
+	Output         string          `json:"output,omitempty"`
+}
+
+// verifyFailurePattern matches a 'go mod verify' failure such as
+// "golang.org/x/text v0.3.0: dir has been modified (/go/pkg/mod/golang.org/x/text@v0.3.0)"
+var verifyFailurePattern = regexp.MustCompile(`^(\S+) (v\S+): (.+)$`)
+
+// downloadedModule is the subset of 'go mod download -json' output used for reporting
+type downloadedModule struct {
+	Path    string
+	Version string
+	Error   string
+}
+
+// GoVerify implements the go_verify tool functionality
+func GoVerify(ctx context.Context, input json.RawMessage) (string, error) {
+	verifyInput := GoVerifyInput{}
+	err := json.Unmarshal(input, &verifyInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	result, err := RunGoCommand(ctx, "mod verify", "", nil, verifyInput.WorkingDir)
+	if err != nil {
+		return "", err
+	}
+
+	output := parseModVerifyOutput(result.Stdout+result.Stderr, result.Success)
+
+	if !verifyInput.SkipDownload {
+		download, err := RunGoCommand(ctx, "mod download", "", []string{"-json"}, verifyInput.WorkingDir)
+		if err != nil {
+			return "", err
+		}
+		modules, err := parseModDownloadOutput(download.Stdout)
+		if err != nil {
+			return "", err
+		}
+		output.DownloadErrors = []ModuleProblem{}
+		for _, module := range modules {
+			if module.Error != "" {
+				output.DownloadErrors = append(output.DownloadErrors, ModuleProblem{Path: module.Path, Version: module.Version, Error: module.Error})
+			} else {
+				output.Downloaded++
+			}
+		}
+		// Errors before any module is processed, such as a malformed go.mod, only reach stderr
+		if !download.Success && len(output.DownloadErrors) == 0 {
+			output.DownloadErrors = append(output.DownloadErrors, ModuleProblem{Path: "go.mod", Error: strings.TrimSpace(download.Stderr)})
+		}
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// parseModVerifyOutput interprets 'go mod verify' output. Lines that do not name a module
+// are kept in Output so unexpected errors are not lost.
+func parseModVerifyOutput(text string, success bool) GoVerifyOutput {
+	output := GoVerifyOutput{VerifyFailures: []ModuleProblem{}}
+	var other []string
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "all modules verified" {
+			continue
+		}
+		if matches := verifyFailurePattern.FindStringSubmatch(line); matches != nil {
+			output.VerifyFailures = append(output.VerifyFailures, ModuleProblem{Path: matches[1], Version: matches[2], Error: matches[3]})
+			continue
+		}
+		other = append(other, line)
+	}
+
+	output.Verified = success && len(output.VerifyFailures) == 0
+	output.Output = strings.Join(other, "\n")
+	return output
+}
+
+// parseModDownloadOutput decodes the concatenated JSON objects printed by 'go mod download -json'
+func parseModDownloadOutput(stdout string) ([]downloadedModule, error) {
+	var modules []downloadedModule
+	decoder := json.NewDecoder(strings.NewReader(stdout))
+	for {
+		var module downloadedModule
+		if err := decoder.Decode(&module); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse go mod download output: %w", err)
+		}
+		modules = append(modules, module)
+	}
+	return modules, nil
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseModVerifyOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		success  bool
+		verified bool
+		failures []ModuleProblem
+		other    string
+	}{
+		{
+			name:     "all verified",
+			text:     "all modules verified\n",
+			success:  true,
+			verified: true,
+			failures: []ModuleProblem{},
+		},
+		{
+			name: "modified module",
+			text: "golang.org/x/text v0.3.0: dir has been modified (/go/pkg/mod/golang.org/x/text@v0.3.0)\n" +
+				"github.com/rs/zerolog v1.33.0: zip has been modified (/go/pkg/mod/cache/download/github.com/rs/zerolog/@v/v1.33.0.zip)\n",
+			failures: []ModuleProblem{
+				{Path: "golang.org/x/text", Version: "v0.3.0", Error: "dir has been modified (/go/pkg/mod/golang.org/x/text@v0.3.0)"},
+				{Path: "github.com/rs/zerolog", Version: "v1.33.0", Error: "zip has been modified (/go/pkg/mod/cache/download/github.com/rs/zerolog/@v/v1.33.0.zip)"},
+			},
+		},
+		{
+			name:     "unrelated failure",
+			text:     "go: go.mod file not found in current directory or any parent directory\n",
+			failures: []ModuleProblem{},
+			other:    "go: go.mod file not found in current directory or any parent directory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := parseModVerifyOutput(tt.text, tt.success)
+			if output.Verified != tt.verified {
+				t.Errorf("verified = %v, want %v", output.Verified, tt.verified)
+			}
+			if !reflect.DeepEqual(output.VerifyFailures, tt.failures) {
+				t.Errorf("failures = %+v, want %+v", output.VerifyFailures, tt.failures)
+			}
+			if output.Output != tt.other {
+				t.Errorf("output = %q, want %q", output.Output, tt.other)
+			}
+		})
+	}
+}
+
+func TestParseModDownloadOutput(t *testing.T) {
+	stdout := `{
+	"Path": "github.com/rs/zerolog",
+	"Version": "v1.33.0",
+	"Dir": "/go/pkg/mod/github.com/rs/zerolog@v1.33.0"
+}
+{
+	"Path": "example.com/missing",
+	"Version": "v1.0.0",
+	"Error": "example.com/missing@v1.0.0: unrecognized import path"
+}
+`
+	modules, err := parseModDownloadOutput(stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []downloadedModule{
+		{Path: "github.com/rs/zerolog", Version: "v1.33.0"},
+		{Path: "example.com/missing", Version: "v1.0.0", Error: "example.com/missing@v1.0.0: unrecognized import path"},
+	}
+	if !reflect.DeepEqual(modules, want) {
+		t.Errorf("modules = %+v, want %+v", modules, want)
+	}
+
+	if _, err := parseModDownloadOutput("{broken"); err == nil {
+		t.Error("expected an error for malformed output")
+	}
+}
+
+func TestGoVerifyModuleWithoutDependencies(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+
+	var output GoVerifyOutput
+	callTool(t, ctx, GoVerify, GoVerifyInput{WorkingDir: dir}, &output)
+	if !output.Verified || len(output.VerifyFailures) != 0 || len(output.DownloadErrors) != 0 {
+		t.Errorf("got %+v, want a clean verification", output)
+	}
+}
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// newTestWorkspace creates a temporary directory and returns a context whose tool calls
+// resolve paths against it
+func newTestWorkspace(t *testing.T) (context.Context, string) {
+	t.Helper()
+	dir := t.TempDir()
+	root, err := ResolveWorkspaceRoot(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return WithWorkspaceRoot(context.Background(), root), root
+}
+
+// writeTestFile writes content to name below dir, creating parent directories, and returns its path
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// readTestFile returns the content of path, failing the test if it can't be read
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+// callTool runs a tool function with input marshalled from v and decodes its JSON output into out
+func callTool(t *testing.T, ctx context.Context, fn func(context.Context, json.RawMessage) (string, error), v interface{}, out interface{}) {
+	t.Helper()
+	input, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := fn(ctx, input)
+	if err != nil {
+		t.Fatalf("tool returned an error: %v", err)
+	}
+	if out != nil {
+		if err := json.Unmarshal([]byte(result), out); err != nil {
+			t.Fatalf("failed to decode tool output %q: %v", result, err)
+		}
+	}
+}
+
+// testGoModule writes a go.mod for module path into dir
+func testGoModule(t *testing.T, dir, path string) {
+	t.Helper()
+	writeTestFile(t, dir, "go.mod", "module "+path+"\n\ngo 1.21\n")
+}
+
+// mustMarshal returns v encoded as JSON tool input
+func mustMarshal(t *testing.T, v interface{}) json.RawMessage {
+	t.Helper()
+	input, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
+
+// runTestGit runs git with args in dir and returns its trimmed output
+func runTestGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return string(bytes.TrimSpace(output))
+}
+
+// initTestRepo creates a git repository in dir with a committer identity configured
+func initTestRepo(t *testing.T, dir string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	runTestGit(t, dir, "init", "-q", "-b", "main")
+	runTestGit(t, dir, "config", "user.name", "Test")
+	runTestGit(t, dir, "config", "user.email", "test@example.com")
+	runTestGit(t, dir, "config", "commit.gpgsign", "false")
+}
+
+// commitTestFiles stages everything in dir and commits it with message, returning the commit hash
+func commitTestFiles(t *testing.T, dir, message string) string {
+	t.Helper()
+	runTestGit(t, dir, "add", "-A")
+	runTestGit(t, dir, "commit", "-q", "-m", message)
+	return runTestGit(t, dir, "rev-parse", "HEAD")
+}
@@ -0,0 +1,190 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MetamorphIgnoreFile is the name of the file holding metamorph-specific excludes.
+// It uses gitignore syntax and applies to every file-scanning tool.
+const MetamorphIgnoreFile = ".metamorphignore"
+
+// ignoreRule is a single compiled gitignore-style pattern
+type ignoreRule struct {
+	regex   *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// IgnoreRules holds the ignore patterns loaded for a directory tree
+type IgnoreRules struct {
+	root  string
+	rules []ignoreRule
+}
+
+// LoadIgnoreRules reads the .metamorphignore file at root, if present.
+// A missing or unreadable file yields an empty rule set that ignores nothing.
+// Tools load the rules from the workspace root, whatever directory they scan,
+// and check paths with IgnoredPath so patterns stay relative to that root.
+func LoadIgnoreRules(root string) *IgnoreRules {
+	rules := &IgnoreRules{root: root}
+	if abs, err := filepath.Abs(root); err == nil {
+		rules.root = abs
+	}
+
+	file, err := os.Open(filepath.Join(root, MetamorphIgnoreFile))
+	if err != nil {
+		return rules
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		rules.Add(scanner.Text())
+	}
+
+	return rules
+}
+
+// ignoreRulesFor returns the rules that apply when scanning path: the workspace's rules, or for
+// trees outside the workspace, such as the git checkouts api_diff compares against, their own
+func ignoreRulesFor(ctx context.Context, path string) *IgnoreRules {
+	if !withinDir(workspaceDir(ctx), path) {
+		return LoadIgnoreRules(path)
+	}
+	return LoadIgnoreRules(workspaceDir(ctx))
+}
+
+// Add compiles a single gitignore-style pattern line and appends it to the rule set
+func (r *IgnoreRules) Add(line string) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+
+	rule := ignoreRule{}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	if line == "" {
+		return
+	}
+
+	// Patterns containing a slash (other than a trailing one) are anchored to the root
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	prefix := "^(?:.*/)?"
+	if anchored {
+		prefix = "^"
+	}
+
+	regex, err := regexp.Compile(prefix + globToRegex(line) + "$")
+	if err != nil {
+		return
+	}
+	rule.regex = regex
+	r.rules = append(r.rules, rule)
+}
+
+// Ignored reports whether relPath (relative to the root, using any separator) is excluded.
+// A path is also excluded when one of its parent directories is.
+func (r *IgnoreRules) Ignored(relPath string, isDir bool) bool {
+	if r == nil || len(r.rules) == 0 {
+		return false
+	}
+
+	relPath = filepath.ToSlash(filepath.Clean(relPath))
+	if relPath == "." {
+		return false
+	}
+
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		if r.match(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return r.match(relPath, isDir)
+}
+
+// IgnoredPath reports whether path, absolute or relative to the current directory, is excluded.
+// It is matched relative to the root the rules were loaded from; paths outside that root are never excluded.
+func (r *IgnoreRules) IgnoredPath(path string, isDir bool) bool {
+	if r == nil || len(r.rules) == 0 || !withinDir(r.root, path) {
+		return false
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	relPath, err := filepath.Rel(r.root, abs)
+	if err != nil {
+		return false
+	}
+	return r.Ignored(relPath, isDir)
+}
+
+// match applies the rules to a single path; the last matching rule wins
+func (r *IgnoreRules) match(path string, isDir bool) bool {
+	ignored := false
+	for _, rule := range r.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.regex.MatchString(path) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// globToRegex converts a gitignore glob into an equivalent regular expression
+func globToRegex(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end == -1 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	rules := &IgnoreRules{}
+	for _, line := range []string{
+		"# generated code",
+		"*.secret",
+		"build/",
+		"/docs/private",
+		"vendor/**/testdata",
+		"!keep.secret",
+	} {
+		rules.Add(line)
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"api.secret", false, true},
+		{"config/db.secret", false, true},
+		{"keep.secret", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"build/app", false, true},
+		{"cmd/build/main.go", false, true},
+		{"docs/private", true, true},
+		{"src/docs/private", true, false},
+		{"vendor/a/b/testdata", true, true},
+		{"main.go", false, false},
+		{".", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := rules.Ignored(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("Ignored(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIgnoredPathOutsideRoot(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, root, MetamorphIgnoreFile, "*.log\n")
+	rules := LoadIgnoreRules(root)
+
+	if !rules.IgnoredPath(filepath.Join(root, "sub", "app.log"), false) {
+		t.Error("expected a path below the root to be matched")
+	}
+	if rules.IgnoredPath(filepath.Join(t.TempDir(), "app.log"), false) {
+		t.Error("a path outside the root must never be ignored")
+	}
+}
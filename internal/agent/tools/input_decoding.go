@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DecodeInput unmarshals a tool input into v, a pointer to an input struct.
+// Numeric fields leniently accept numeric strings such as "5" in addition to JSON numbers,
+// since models occasionally quote numbers. Non-numeric strings produce an error naming the field.
+func DecodeInput(input json.RawMessage, v interface{}) error {
+	coerced, err := coerceNumericStrings(input, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(coerced, v); err != nil {
+		return fmt.Errorf("failed to parse input: %w", err)
+	}
+	return nil
+}
+
+// coerceNumericStrings rewrites quoted values of numeric struct fields into JSON numbers
+func coerceNumericStrings(input json.RawMessage, t reflect.Type) (json.RawMessage, error) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return input, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(input, &fields); err != nil {
+		// Let the real decode report the malformed input
+		return input, nil
+	}
+
+	changed := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || !isNumericKind(field.Type.Kind()) {
+			continue
+		}
+
+		raw, ok := fields[name]
+		if !ok || !bytes.HasPrefix(bytes.TrimSpace(raw), []byte(`"`)) {
+			continue
+		}
+
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %s", name, string(raw))
+		}
+		text = strings.TrimSpace(text)
+
+		var number interface{}
+		var err error
+		switch field.Type.Kind() {
+		case reflect.Float32, reflect.Float64:
+			number, err = strconv.ParseFloat(text, 64)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			number, err = strconv.ParseUint(text, 10, 64)
+		default:
+			number, err = strconv.ParseInt(text, 10, 64)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %q is not a valid %s", name, text, field.Type.Kind())
+		}
+
+		encoded, err := json.Marshal(number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		fields[name] = encoded
+		changed = true
+	}
+
+	if !changed {
+		return input, nil
+	}
+	return json.Marshal(fields)
+}
+
+// isNumericKind reports whether k is an integer or floating-point kind
+func isNumericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecodeInputNumericStrings(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  FileEditorInput
+	}{
+		{"json number", `{"path": "a.go", "line_number": 5}`, FileEditorInput{Path: "a.go", LineNumber: 5}},
+		{"stringified number", `{"path": "a.go", "line_number": "5"}`, FileEditorInput{Path: "a.go", LineNumber: 5}},
+		{"padded string", `{"path": "a.go", "line_number": " 7 ", "limit": "2"}`, FileEditorInput{Path: "a.go", LineNumber: 7, Limit: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got FileEditorInput
+			if err := DecodeInput(json.RawMessage(tt.input), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Path != tt.want.Path || got.LineNumber != tt.want.LineNumber || got.Limit != tt.want.Limit {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeInputStringFieldsUntouched(t *testing.T) {
+	var got WebSearchInput
+	if err := DecodeInput(json.RawMessage(`{"query": "42", "num_results": "3"}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Query != "42" || got.NumResults != 3 {
+		t.Errorf("got %+v, want query %q and 3 results", got, "42")
+	}
+}
+
+func TestDecodeInputRejectsNonNumeric(t *testing.T) {
+	var got FileEditorInput
+	err := DecodeInput(json.RawMessage(`{"line_number": "five"}`), &got)
+	if err == nil || !strings.Contains(err.Error(), "line_number") {
+		t.Errorf("expected an error naming line_number, got %v", err)
+	}
+	if err := DecodeInput(json.RawMessage(`{"line_number": `), &got); err == nil {
+		t.Error("expected an error for malformed JSON")
+	}
+}
@@ -0,0 +1,235 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// JSONArrayAppendToolDefinition defines the json_array_append tool
+var JSONArrayAppendToolDefinition = ToolDefinition{
+	Name: "json_array_append",
+	Description: `Append a value to an array inside a JSON file.
+The array is located by a dotted 'key' path; use numbers for array indexes, e.g. 'plugins' or
+'servers.0.aliases'. Leave 'key' empty when the whole document is an array. 'value' is JSON text
+such as '"name"', '42', or '{"id": 1}'; text that is not valid JSON is appended as a string.
+The file is written back formatted, with the order of object keys preserved.`,
+	InputSchema:           JSONArrayAppendInputSchema,
+	Function:              JSONArrayAppend,
+	CountsTowardLoopLimit: true,
+}
+
+// JSONArrayAppendInput defines the input parameters for the json_array_append tool
+type JSONArrayAppendInput struct {
+	Path  string `json:"path" jsonschema_required:"true" jsonschema_description:"Path to the JSON file"`
+	Key   string `json:"key,omitempty" jsonschema_description:"Dotted path of the array. Empty for a top-level array." jsonschema_example:"servers.0.aliases"`
+	Value string `json:"value" jsonschema_required:"true" jsonschema_description:"JSON value to append" jsonschema_example:"{\"name\": \"example\"}"`
+}
+
+// JSONArrayAppendInputSchema is the JSON schema for the json_array_append tool
+var JSONArrayAppendInputSchema = GenerateSchema[JSONArrayAppendInput]()
+
+// JSONArrayAppendOutput represents the structured output of the json_array_append tool
+type JSONArrayAppendOutput struct {
+	Path         string `json:"path"`
+	Key          string `json:"key"`
+	LengthBefore int    `json:"length_before"`
+	LengthAfter  int    `json:"length_after"`
+	Appended     string `json:"appended"`
+}
+
+// jsonMember is a key and raw value of a JSON object, kept in document order
+type jsonMember struct {
+	key   string
+	value json.RawMessage
+}
+
+// JSONArrayAppend implements the json_array_append tool functionality
+func JSONArrayAppend(ctx context.Context, input json.RawMessage) (string, error) {
+	appendInput := JSONArrayAppendInput{}
+	err := json.Unmarshal(input, &appendInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if appendInput.Path == "" {
+		return "", fmt.Errorf("path parameter is required")
+	}
+	appendInput.Path, err = ResolvePath(ctx, appendInput.Path)
+	if err != nil {
+		return "", err
+	}
+
+	value := json.RawMessage(strings.TrimSpace(appendInput.Value))
+	if !json.Valid(value) {
+		value, _ = json.Marshal(appendInput.Value)
+	}
+
+	content, err := os.ReadFile(appendInput.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file '%s': %w", appendInput.Path, err)
+	}
+	if !json.Valid(content) {
+		return "", fmt.Errorf("%s does not contain valid JSON", appendInput.Path)
+	}
+
+	var keys []string
+	if appendInput.Key != "" {
+		keys = strings.Split(appendInput.Key, ".")
+	}
+
+	output := JSONArrayAppendOutput{
+		Path:     appendInput.Path,
+		Key:      appendInput.Key,
+		Appended: string(value),
+	}
+
+	updated, err := updateJSONAtPath(content, keys, nil, func(raw json.RawMessage, location string) (json.RawMessage, error) {
+		var array []json.RawMessage
+		if err := json.Unmarshal(raw, &array); err != nil || bytes.HasPrefix(bytes.TrimSpace(raw), []byte("null")) {
+			return nil, fmt.Errorf("value at %s is not an array (found %s)", location, jsonRawKind(raw))
+		}
+		output.LengthBefore = len(array)
+		array = append(array, value)
+		output.LengthAfter = len(array)
+		return json.Marshal(array)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var formatted bytes.Buffer
+	if err := json.Indent(&formatted, updated, "", jsonIndent(string(content))); err != nil {
+		return "", fmt.Errorf("failed to format JSON: %w", err)
+	}
+	formatted.WriteString("\n")
+
+	if err := os.WriteFile(appendInput.Path, formatted.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	recordReadHash(appendInput.Path, formatted.Bytes())
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// updateJSONAtPath replaces the value at keys within raw with the result of update.
+// Objects along the path keep their key order; walked holds the keys already traversed.
+func updateJSONAtPath(raw json.RawMessage, keys, walked []string, update func(json.RawMessage, string) (json.RawMessage, error)) (json.RawMessage, error) {
+	location := strings.Join(walked, ".")
+	if location == "" {
+		location = "the document root"
+	}
+	if len(keys) == 0 {
+		return update(raw, location)
+	}
+
+	key := keys[0]
+	trimmed := bytes.TrimSpace(raw)
+
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		members, err := parseJSONObject(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		for i, member := range members {
+			if member.key == key {
+				members[i].value, err = updateJSONAtPath(member.value, keys[1:], append(walked, key), update)
+				if err != nil {
+					return nil, err
+				}
+				return marshalJSONObject(members)
+			}
+		}
+		return nil, fmt.Errorf("key %q not found under %s", key, location)
+
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		var array []json.RawMessage
+		if err := json.Unmarshal(trimmed, &array); err != nil {
+			return nil, fmt.Errorf("failed to parse array at %s: %w", location, err)
+		}
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(array) {
+			return nil, fmt.Errorf("invalid index %q for array at %s (length %d)", key, location, len(array))
+		}
+		array[index], err = updateJSONAtPath(array[index], keys[1:], append(walked, key), update)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(array)
+
+	default:
+		return nil, fmt.Errorf("cannot descend into %s at %s", jsonRawKind(trimmed), location)
+	}
+}
+
+// parseJSONObject decodes a JSON object into its members in document order
+func parseJSONObject(raw json.RawMessage) ([]jsonMember, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("failed to parse object: %w", err)
+	}
+
+	var members []jsonMember
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse object: %w", err)
+		}
+		member := jsonMember{key: token.(string)}
+		if err := decoder.Decode(&member.value); err != nil {
+			return nil, fmt.Errorf("failed to parse value of %q: %w", member.key, err)
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// marshalJSONObject encodes members as a JSON object, keeping their order
+func marshalJSONObject(members []jsonMember) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, member := range members {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, err := json.Marshal(member.key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(member.value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// jsonRawKind names the JSON type of a raw value for error messages
+func jsonRawKind(raw json.RawMessage) string {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "invalid JSON"
+	}
+	return jsonKind(value)
+}
+
+// jsonIndent returns the indentation used by a JSON document, defaulting to two spaces
+func jsonIndent(content string) string {
+	for _, line := range strings.Split(content, "\n")[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "  "
+}
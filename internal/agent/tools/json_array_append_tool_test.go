@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONArrayAppendTopLevel(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "list.json", `["a", "b"]`)
+
+	var output JSONArrayAppendOutput
+	callTool(t, ctx, JSONArrayAppend, JSONArrayAppendInput{Path: "list.json", Value: "c"}, &output)
+
+	if output.LengthBefore != 2 || output.LengthAfter != 3 || output.Appended != `"c"` {
+		t.Errorf("got %+v", output)
+	}
+	want := "[\n  \"a\",\n  \"b\",\n  \"c\"\n]\n"
+	if got := readTestFile(t, path); got != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
+
+func TestJSONArrayAppendNested(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "config.json", `{
+    "name": "api",
+    "servers": [
+        {"host": "a.example.com", "aliases": ["a"]}
+    ],
+    "debug": false
+}
+`)
+
+	var output JSONArrayAppendOutput
+	callTool(t, ctx, JSONArrayAppend, JSONArrayAppendInput{Path: "config.json", Key: "servers.0.aliases", Value: `"alpha"`}, &output)
+	callTool(t, ctx, JSONArrayAppend, JSONArrayAppendInput{Path: "config.json", Key: "servers", Value: `{"host": "b.example.com"}`}, &output)
+
+	want := `{
+    "name": "api",
+    "servers": [
+        {
+            "host": "a.example.com",
+            "aliases": [
+                "a",
+                "alpha"
+            ]
+        },
+        {
+            "host": "b.example.com"
+        }
+    ],
+    "debug": false
+}
+`
+	if got := readTestFile(t, path); got != want {
+		t.Errorf("file keeps key order and indentation, got:\n%s", got)
+	}
+	if output.LengthBefore != 1 || output.LengthAfter != 2 {
+		t.Errorf("got %+v", output)
+	}
+}
+
+func TestJSONArrayAppendRejectsNonArrays(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "config.json", `{"name": "api", "tags": null, "servers": []}`)
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"name", "is not an array"},
+		{"tags", "is not an array"},
+		{"missing", `key "missing" not found`},
+		{"servers.0", "invalid index"},
+		{"name.first", "cannot descend into"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			_, err := JSONArrayAppend(ctx, mustMarshal(t, JSONArrayAppendInput{Path: "config.json", Key: tt.key, Value: "1"}))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+	if got := readTestFile(t, path); got != `{"name": "api", "tags": null, "servers": []}` {
+		t.Errorf("failed appends changed the file: %s", got)
+	}
+}
@@ -0,0 +1,224 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONDiffToolDefinition defines the json_diff tool
+var JSONDiffToolDefinition = ToolDefinition{
+	Name: "json_diff",
+	Description: `Compare two JSON documents semantically and list the paths that differ.
+Each side is given either as JSON text ('left', 'right') or as a file ('left_file', 'right_file').
+Key order, whitespace, and number formatting are ignored; arrays are compared element by element.
+Every difference is reported with its dotted path (such as 'servers.0.port'), whether it was
+added, removed, or changed, and the values before and after. Use it to compare configs or API
+responses without the noise of a textual diff.`,
+	InputSchema: JSONDiffInputSchema,
+	Function:    JSONDiff,
+}
+
+// JSONDiffInput defines the input parameters for the json_diff tool
+type JSONDiffInput struct {
+	Left           string `json:"left,omitempty" jsonschema_description:"The original JSON document as text"`
+	Right          string `json:"right,omitempty" jsonschema_description:"The new JSON document as text"`
+	LeftFile       string `json:"left_file,omitempty" jsonschema_description:"Path to the original JSON document, instead of 'left'"`
+	RightFile      string `json:"right_file,omitempty" jsonschema_description:"Path to the new JSON document, instead of 'right'"`
+	MaxDifferences int    `json:"max_differences,omitempty" jsonschema_description:"Maximum number of differences to report. Defaults to 200."`
+}
+
+// JSONDiffInputSchema is the JSON schema for the json_diff tool
+var JSONDiffInputSchema = GenerateSchema[JSONDiffInput]()
+
+// JSONDifference is one path whose value differs between the documents
+type JSONDifference struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// JSONDiffOutput represents the structured output of the json_diff tool
+type JSONDiffOutput struct {
+	Equal       bool             `json:"equal"`
+	Added       int              `json:"added"`
+	Removed     int              `json:"removed"`
+	Changed     int              `json:"changed"`
+	Differences []JSONDifference `json:"differences"`
+	Truncated   bool             `json:"truncated,omitempty"`
+}
+
+const defaultJSONDiffMaxDifferences = 200
+
+// JSONDiff implements the json_diff tool functionality
+func JSONDiff(ctx context.Context, input json.RawMessage) (string, error) {
+	diffInput := JSONDiffInput{}
+	err := DecodeInput(input, &diffInput)
+	if err != nil {
+		return "", err
+	}
+
+	left, err := loadJSONDocument(ctx, "left", diffInput.Left, diffInput.LeftFile)
+	if err != nil {
+		return "", err
+	}
+	right, err := loadJSONDocument(ctx, "right", diffInput.Right, diffInput.RightFile)
+	if err != nil {
+		return "", err
+	}
+
+	output := JSONDiffOutput{Differences: []JSONDifference{}}
+	diffJSONValues("", left, right, &output.Differences)
+
+	for _, difference := range output.Differences {
+		switch difference.Change {
+		case "added":
+			output.Added++
+		case "removed":
+			output.Removed++
+		default:
+			output.Changed++
+		}
+	}
+	output.Equal = len(output.Differences) == 0
+
+	maxDifferences := diffInput.MaxDifferences
+	if maxDifferences <= 0 {
+		maxDifferences = defaultJSONDiffMaxDifferences
+	}
+	if len(output.Differences) > maxDifferences {
+		output.Differences = output.Differences[:maxDifferences]
+		output.Truncated = true
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// loadJSONDocument decodes one side of the comparison from text or from a file, keeping
+// numbers as json.Number so large integers are compared exactly
+func loadJSONDocument(ctx context.Context, side, text, file string) (any, error) {
+	switch {
+	case text != "" && file != "":
+		return nil, fmt.Errorf("only one of %s and %s_file may be set", side, side)
+	case file != "":
+		path, err := ResolvePath(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s_file: %w", side, err)
+		}
+		text = string(content)
+	case text == "":
+		return nil, fmt.Errorf("either %s or %s_file is required", side, side)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON in %s: %w", side, err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("invalid JSON in %s: unexpected data after the document", side)
+	}
+	return value, nil
+}
+
+// diffJSONValues appends the differences between before and after at path to differences
+func diffJSONValues(path string, before, after any, differences *[]JSONDifference) {
+	switch b := before.(type) {
+	case map[string]any:
+		if a, ok := after.(map[string]any); ok {
+			keys := make([]string, 0, len(b)+len(a))
+			for key := range b {
+				keys = append(keys, key)
+			}
+			for key := range a {
+				if _, ok := b[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				beforeValue, inBefore := b[key]
+				afterValue, inAfter := a[key]
+				switch {
+				case !inAfter:
+					*differences = append(*differences, JSONDifference{Path: joinJSONPath(path, key), Change: "removed", Before: beforeValue})
+				case !inBefore:
+					*differences = append(*differences, JSONDifference{Path: joinJSONPath(path, key), Change: "added", After: afterValue})
+				default:
+					diffJSONValues(joinJSONPath(path, key), beforeValue, afterValue, differences)
+				}
+			}
+			return
+		}
+
+	case []any:
+		if a, ok := after.([]any); ok {
+			for i := 0; i < len(b) || i < len(a); i++ {
+				elementPath := joinJSONPath(path, strconv.Itoa(i))
+				switch {
+				case i >= len(a):
+					*differences = append(*differences, JSONDifference{Path: elementPath, Change: "removed", Before: b[i]})
+				case i >= len(b):
+					*differences = append(*differences, JSONDifference{Path: elementPath, Change: "added", After: a[i]})
+				default:
+					diffJSONValues(elementPath, b[i], a[i], differences)
+				}
+			}
+			return
+		}
+
+	case json.Number:
+		// 1, 1.0, and 1e0 are the same number
+		if a, ok := after.(json.Number); ok && equalJSONNumbers(b, a) {
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		displayPath := path
+		if displayPath == "" {
+			displayPath = "."
+		}
+		*differences = append(*differences, JSONDifference{Path: displayPath, Change: "changed", Before: before, After: after})
+	}
+}
+
+// equalJSONNumbers compares two JSON numbers by value
+func equalJSONNumbers(a, b json.Number) bool {
+	if a == b {
+		return true
+	}
+	if ai, err := a.Int64(); err == nil {
+		if bi, err := b.Int64(); err == nil {
+			return ai == bi
+		}
+	}
+	af, errA := a.Float64()
+	bf, errB := b.Float64()
+	return errA == nil && errB == nil && af == bf
+}
+
+// joinJSONPath appends key to a dotted path
+func joinJSONPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
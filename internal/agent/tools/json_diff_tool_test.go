@@ -0,0 +1,95 @@
+package tools
+
+import "testing"
+
+func TestJSONDiffReorderedDocumentsAreEqual(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+
+	var output JSONDiffOutput
+	callTool(t, ctx, JSONDiff, JSONDiffInput{
+		Left:  `{"name": "api", "servers": [{"host": "a", "port": 80}], "ratio": 1}`,
+		Right: `{"servers":[{"port":80,"host":"a"}],"ratio":1.0,"name":"api"}`,
+	}, &output)
+
+	if !output.Equal || len(output.Differences) != 0 {
+		t.Errorf("got %+v, want reordered documents to be equal", output)
+	}
+}
+
+func TestJSONDiffReportsPaths(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+
+	var output JSONDiffOutput
+	callTool(t, ctx, JSONDiff, JSONDiffInput{
+		Left:  `{"name": "api", "debug": true, "servers": [{"host": "a", "port": 80}]}`,
+		Right: `{"name": "api", "servers": [{"host": "a", "port": 8080}, {"host": "b", "port": 80}], "timeout": 5}`,
+	}, &output)
+
+	if output.Equal {
+		t.Fatal("expected the documents to differ")
+	}
+	if output.Added != 2 || output.Removed != 1 || output.Changed != 1 {
+		t.Errorf("counts added=%d removed=%d changed=%d, want 2 1 1", output.Added, output.Removed, output.Changed)
+	}
+
+	byPath := make(map[string]JSONDifference)
+	for _, difference := range output.Differences {
+		byPath[difference.Path] = difference
+	}
+	port := byPath["servers.0.port"]
+	if port.Change != "changed" || port.Before != float64(80) || port.After != float64(8080) {
+		t.Errorf("servers.0.port = %+v, want changed from 80 to 8080", port)
+	}
+	if byPath["debug"].Change != "removed" {
+		t.Errorf("debug = %+v, want removed", byPath["debug"])
+	}
+	if byPath["servers.1"].Change != "added" || byPath["timeout"].Change != "added" {
+		t.Errorf("differences = %+v, want servers.1 and timeout added", output.Differences)
+	}
+}
+
+func TestJSONDiffFiles(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "old.json", `{"version": "1.0"}`)
+	writeTestFile(t, dir, "new.json", `{"version": "1.1"}`)
+
+	var output JSONDiffOutput
+	callTool(t, ctx, JSONDiff, JSONDiffInput{LeftFile: "old.json", RightFile: "new.json"}, &output)
+
+	if len(output.Differences) != 1 || output.Differences[0].Path != "version" {
+		t.Errorf("differences = %+v, want only version changed", output.Differences)
+	}
+}
+
+func TestJSONDiffTruncates(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+
+	var output JSONDiffOutput
+	callTool(t, ctx, JSONDiff, JSONDiffInput{Left: `[1, 2, 3]`, Right: `[4, 5, 6]`, MaxDifferences: 2}, &output)
+
+	if !output.Truncated || len(output.Differences) != 2 || output.Changed != 3 {
+		t.Errorf("got %+v, want two of three differences and truncated", output)
+	}
+}
+
+func TestJSONDiffErrors(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "doc.json", `{}`)
+
+	tests := []struct {
+		name  string
+		input JSONDiffInput
+	}{
+		{"text and file", JSONDiffInput{Left: `{}`, LeftFile: "doc.json", Right: `{}`}},
+		{"missing side", JSONDiffInput{Left: `{}`}},
+		{"invalid JSON", JSONDiffInput{Left: `{`, Right: `{}`}},
+		{"trailing data", JSONDiffInput{Left: `{} {}`, Right: `{}`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := JSONDiff(ctx, mustMarshal(t, tt.input)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
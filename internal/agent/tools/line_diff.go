@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change in a unified diff
+const diffContextLines = 3
+
+// unifiedDiff returns a unified diff between before and after labelled with path, or "" if they are equal.
+// Lines shared at the start and end are skipped before the LCS comparison so localized edits stay cheap.
+func unifiedDiff(path, before, after string) string {
+	if before == after {
+		return ""
+	}
+	oldLines := strings.Split(strings.TrimSuffix(before, "\n"), "\n")
+	newLines := strings.Split(strings.TrimSuffix(after, "\n"), "\n")
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	// ops holds one entry per line: ' ' unchanged, '-' removed, '+' added
+	type diffOp struct {
+		kind    byte
+		oldLine int
+		newLine int
+		text    string
+	}
+	var ops []diffOp
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{' ', i, i, oldLines[i]})
+	}
+
+	oldMid := oldLines[prefix : len(oldLines)-suffix]
+	newMid := newLines[prefix : len(newLines)-suffix]
+	lcs := make([][]int, len(oldMid)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newMid)+1)
+	}
+	for i := len(oldMid) - 1; i >= 0; i-- {
+		for j := len(newMid) - 1; j >= 0; j-- {
+			if oldMid[i] == newMid[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(oldMid) || j < len(newMid) {
+		switch {
+		case i < len(oldMid) && j < len(newMid) && oldMid[i] == newMid[j]:
+			ops = append(ops, diffOp{' ', prefix + i, prefix + j, oldMid[i]})
+			i++
+			j++
+		case i < len(oldMid) && (j == len(newMid) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', prefix + i, prefix + j, oldMid[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', prefix + i, prefix + j, newMid[j]})
+			j++
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		ops = append(ops, diffOp{' ', len(oldLines) - suffix + k, len(newLines) - suffix + k, oldLines[len(oldLines)-suffix+k]})
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", path, path)
+
+	// Group changes into hunks, merging those whose context would overlap
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+		hunkStart := max(start-diffContextLines, 0)
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*diffContextLines {
+				break
+			}
+			end = next
+		}
+		hunkEnd := min(end+diffContextLines, len(ops))
+
+		oldCount, newCount := 0, 0
+		for _, op := range ops[hunkStart:hunkEnd] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", ops[hunkStart].oldLine+1, oldCount, ops[hunkStart].newLine+1, newCount)
+		for _, op := range ops[hunkStart:hunkEnd] {
+			fmt.Fprintf(&b, "%c%s\n", op.kind, op.text)
+		}
+		start = hunkEnd
+	}
+
+	return b.String()
+}
@@ -0,0 +1,214 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// LintCommitMessageToolDefinition defines the lint_commit_message tool
+var LintCommitMessageToolDefinition = ToolDefinition{
+	Name: "lint_commit_message",
+	Description: `Check a commit message against conventional-commit rules before committing.
+The subject must start with an allowed type ('type(scope)!: summary'), fit within the maximum length,
+use the imperative mood ('add', not 'added' or 'adds') and not end with a period. A body must be
+separated from the subject by a blank line and wrapped at the maximum body line length. Returns
+each violation with its rule and line. Rules can be turned off with 'disable':
+type, subject_length, imperative, subject_period, blank_line, body_wrap.
+git_operations runs the same checks with default settings when 'lint_message' is set on a commit.`,
+	InputSchema: LintCommitMessageInputSchema,
+	Function:    LintCommitMessage,
+}
+
+// LintCommitMessageInput defines the input parameters for the lint_commit_message tool
+type LintCommitMessageInput struct {
+	Message           string   `json:"message" jsonschema_required:"true" jsonschema_description:"Full commit message: subject, optional blank line and body"`
+	Types             []string `json:"types,omitempty" jsonschema_description:"Allowed commit types. Defaults to feat, fix, perf, refactor, revert, docs, test, build, ci, style and chore."`
+	MaxSubjectLength  int      `json:"max_subject_length,omitempty" jsonschema_description:"Maximum subject length in characters. Defaults to 72."`
+	MaxBodyLineLength int      `json:"max_body_line_length,omitempty" jsonschema_description:"Maximum body line length in characters. Defaults to 72."`
+	Disable           []string `json:"disable,omitempty" jsonschema_description:"Rules to skip" jsonschema_example:"[\"imperative\"]"`
+}
+
+// LintCommitMessageInputSchema is the JSON schema for the lint_commit_message tool
+var LintCommitMessageInputSchema = GenerateSchema[LintCommitMessageInput]()
+
+// CommitLintViolation is one rule a commit message breaks
+type CommitLintViolation struct {
+	Rule    string `json:"rule"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// LintCommitMessageOutput represents the structured output of the lint_commit_message tool
+type LintCommitMessageOutput struct {
+	Valid      bool                  `json:"valid"`
+	Violations []CommitLintViolation `json:"violations"`
+}
+
+// defaultCommitTypes lists the commit types allowed when none are configured
+var defaultCommitTypes = []string{"feat", "fix", "perf", "refactor", "revert", "docs", "test", "build", "ci", "style", "chore"}
+
+// commitLintRules lists the rule names that can be disabled
+var commitLintRules = map[string]bool{
+	"type":           true,
+	"subject_length": true,
+	"imperative":     true,
+	"subject_period": true,
+	"blank_line":     true,
+	"body_wrap":      true,
+}
+
+// imperativeVerbs lists common commit verbs whose third-person form ('adds') the imperative
+// rule reports
+var imperativeVerbs = map[string]bool{
+	"add": true, "allow": true, "bump": true, "change": true, "clean": true, "create": true,
+	"delete": true, "drop": true, "ensure": true, "extract": true, "fix": true, "handle": true,
+	"implement": true, "improve": true, "introduce": true, "make": true, "merge": true, "move": true,
+	"remove": true, "rename": true, "replace": true, "return": true, "revert": true, "rewrite": true,
+	"set": true, "simplify": true, "support": true, "switch": true, "update": true, "upgrade": true,
+	"use": true,
+}
+
+// nonPastTenseEd lists imperative verbs ending in 'ed' that aren't past tense
+var nonPastTenseEd = map[string]bool{
+	"embed": true, "exceed": true, "feed": true, "need": true, "proceed": true, "seed": true,
+	"shed": true, "speed": true, "succeed": true,
+}
+
+// LintCommitMessage implements the lint_commit_message tool functionality
+func LintCommitMessage(ctx context.Context, input json.RawMessage) (string, error) {
+	lintInput := LintCommitMessageInput{}
+	err := DecodeInput(input, &lintInput)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(lintInput.Message) == "" {
+		return "", fmt.Errorf("message parameter is required")
+	}
+	for _, rule := range lintInput.Disable {
+		if !commitLintRules[rule] {
+			return "", fmt.Errorf("unknown rule: %s", rule)
+		}
+	}
+
+	violations := lintCommitMessage(lintInput)
+	output := LintCommitMessageOutput{Valid: len(violations) == 0, Violations: violations}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// lintCommitMessage returns the violations of the message in rules, applying default settings
+// for any left unset
+func lintCommitMessage(rules LintCommitMessageInput) []CommitLintViolation {
+	types := rules.Types
+	if len(types) == 0 {
+		types = defaultCommitTypes
+	}
+	maxSubject := rules.MaxSubjectLength
+	if maxSubject <= 0 {
+		maxSubject = 72
+	}
+	maxBodyLine := rules.MaxBodyLineLength
+	if maxBodyLine <= 0 {
+		maxBodyLine = 72
+	}
+	disabled := map[string]bool{}
+	for _, rule := range rules.Disable {
+		disabled[rule] = true
+	}
+
+	violations := []CommitLintViolation{}
+	report := func(rule string, line int, format string, args ...interface{}) {
+		if !disabled[rule] {
+			violations = append(violations, CommitLintViolation{Rule: rule, Line: line, Message: fmt.Sprintf(format, args...)})
+		}
+	}
+
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(rules.Message, "\r\n", "\n"), "\n"), "\n")
+	subject := lines[0]
+
+	if length := len([]rune(subject)); length > maxSubject {
+		report("subject_length", 1, "subject is %d characters long; keep it within %d", length, maxSubject)
+	}
+
+	summary := subject
+	if matches := conventionalSubjectPattern.FindStringSubmatch(subject); matches == nil {
+		report("type", 1, "subject must start with a type such as 'feat: ' or 'fix(scope): '; allowed types: %s", strings.Join(types, ", "))
+	} else {
+		summary = matches[4]
+		allowed := false
+		for _, t := range types {
+			if t == matches[1] {
+				allowed = true
+			}
+		}
+		if !allowed {
+			report("type", 1, "type %q is not allowed; use one of: %s", matches[1], strings.Join(types, ", "))
+		}
+	}
+
+	if word := firstWord(summary); !isImperative(word) {
+		report("imperative", 1, "use the imperative mood: %q reads as a description rather than a command", word)
+	}
+	if strings.HasSuffix(strings.TrimSpace(subject), ".") {
+		report("subject_period", 1, "subject must not end with a period")
+	}
+
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		report("blank_line", 2, "separate the subject from the body with a blank line")
+	}
+	for i, line := range lines[1:] {
+		// Long URLs can't be wrapped, so lines without spaces are allowed
+		if length := len([]rune(line)); length > maxBodyLine && strings.Contains(strings.TrimSpace(line), " ") {
+			report("body_wrap", i+2, "body line is %d characters long; wrap at %d", length, maxBodyLine)
+		}
+	}
+
+	return violations
+}
+
+// firstWord returns the lowercased first word of s
+func firstWord(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(strings.Trim(fields[0], ".,:;!?'\"`"))
+}
+
+// isImperative reports whether word plausibly starts an imperative sentence: past tense ('added'),
+// gerunds ('adding') and third-person forms of common verbs ('adds', 'fixes') are rejected
+func isImperative(word string) bool {
+	switch {
+	case word == "":
+		return true
+	case strings.HasSuffix(word, "ed") && !nonPastTenseEd[word]:
+		return false
+	case strings.HasSuffix(word, "ing") && len(word) > 5:
+		return false
+	case strings.HasSuffix(word, "es") && imperativeVerbs[strings.TrimSuffix(word, "es")]:
+		return false
+	case strings.HasSuffix(word, "s") && imperativeVerbs[strings.TrimSuffix(word, "s")]:
+		return false
+	}
+	return true
+}
+
+// commitLintError formats violations as an error, or returns nil when there are none
+func commitLintError(violations []CommitLintViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	var problems []string
+	for _, v := range violations {
+		problems = append(problems, fmt.Sprintf("line %d: %s (%s)", v.Line, v.Message, v.Rule))
+	}
+	return fmt.Errorf("commit message failed lint:\n%s", strings.Join(problems, "\n"))
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// violatedRules returns the rule of each violation in order
+func violatedRules(violations []CommitLintViolation) []string {
+	var rules []string
+	for _, v := range violations {
+		rules = append(rules, v.Rule)
+	}
+	return rules
+}
+
+func TestLintCommitMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		input LintCommitMessageInput
+		want  []string
+	}{
+		{"valid", LintCommitMessageInput{Message: "feat(parser): add support for tabs\n\nBody text."}, nil},
+		{"breaking change", LintCommitMessageInput{Message: "refactor!: drop the legacy API"}, nil},
+		{"missing type", LintCommitMessageInput{Message: "add support for tabs"}, []string{"type"}},
+		{"unknown type", LintCommitMessageInput{Message: "feature: add support for tabs"}, []string{"type"}},
+		{"custom types", LintCommitMessageInput{Message: "feature: add tabs", Types: []string{"feature"}}, nil},
+		{"too long subject", LintCommitMessageInput{Message: "fix: " + strings.Repeat("x", 70)}, []string{"subject_length"}},
+		{"custom subject length", LintCommitMessageInput{Message: "fix: handle empty input", MaxSubjectLength: 10}, []string{"subject_length"}},
+		{"past tense", LintCommitMessageInput{Message: "fix: added a nil check"}, []string{"imperative"}},
+		{"third person", LintCommitMessageInput{Message: "fix: fixes the nil check"}, []string{"imperative"}},
+		{"imperative ending in ed", LintCommitMessageInput{Message: "perf: speed up parsing"}, nil},
+		{"trailing period", LintCommitMessageInput{Message: "docs: update the readme."}, []string{"subject_period"}},
+		{"no blank line", LintCommitMessageInput{Message: "fix: handle errors\nBody directly below"}, []string{"blank_line"}},
+		{"long body line", LintCommitMessageInput{Message: "fix: handle errors\n\n" + strings.Repeat("word ", 20)}, []string{"body_wrap"}},
+		{"long url in body", LintCommitMessageInput{Message: "fix: handle errors\n\nhttps://example.com/" + strings.Repeat("a", 80)}, nil},
+		{"disabled rule", LintCommitMessageInput{Message: "add support for tabs", Disable: []string{"type"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output LintCommitMessageOutput
+			callTool(t, context.Background(), LintCommitMessage, tt.input, &output)
+			got := violatedRules(output.Violations)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("violations = %+v, want rules %v", output.Violations, tt.want)
+			}
+			if output.Valid != (len(tt.want) == 0) {
+				t.Errorf("valid = %v with violations %v", output.Valid, got)
+			}
+		})
+	}
+}
+
+func TestLintCommitMessageReportsLines(t *testing.T) {
+	violations := lintCommitMessage(LintCommitMessageInput{Message: "Added things\n\nshort\n" + strings.Repeat("long line ", 10)})
+	want := []CommitLintViolation{{Rule: "type", Line: 1}, {Rule: "imperative", Line: 1}, {Rule: "body_wrap", Line: 4}}
+	if len(violations) != len(want) {
+		t.Fatalf("violations = %+v, want %d", violations, len(want))
+	}
+	for i, v := range violations {
+		if v.Rule != want[i].Rule || v.Line != want[i].Line || v.Message == "" {
+			t.Errorf("violation %d = %+v, want rule %s on line %d", i, v, want[i].Rule, want[i].Line)
+		}
+	}
+}
+
+func TestLintCommitMessageRejectsBadInput(t *testing.T) {
+	ctx := context.Background()
+	for _, input := range []LintCommitMessageInput{{Message: " "}, {Message: "fix: x", Disable: []string{"spelling"}}} {
+		if _, err := LintCommitMessage(ctx, mustMarshal(t, input)); err == nil {
+			t.Errorf("%+v: expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MacroToolDefinition defines the macro tool
+var MacroToolDefinition = ToolDefinition{
+	Name: "macro",
+	Description: `Run a named, predefined sequence of tool calls in a single step.
+Steps run in order and stop at the first failure, so a macro such as 'check' (gofmt, build,
+then test) replaces several round trips. Macros take string arguments through 'args'; steps
+reference them as {{name}} and unset arguments fall back to the macro's defaults.
+Call with 'list' set to see the available macros and their arguments.`,
+	InputSchema:           MacroInputSchema,
+	Function:              RunMacro,
+	CountsTowardLoopLimit: true,
+}
+
+// MacroInput defines the input parameters for the macro tool
+type MacroInput struct {
+	Name string            `json:"name,omitempty" jsonschema_description:"Name of the macro to run" jsonschema_example:"check"`
+	Args map[string]string `json:"args,omitempty" jsonschema_description:"Arguments substituted into the macro steps" jsonschema_example:"{\"path\": \"./...\"}"`
+	List bool              `json:"list,omitempty" jsonschema_description:"If true, list the available macros instead of running one"`
+}
+
+// MacroInputSchema is the JSON schema for the macro tool
+var MacroInputSchema = GenerateSchema[MacroInput]()
+
+// MacroStep is a single tool call within a macro.
+// String values in Input may reference macro arguments as {{name}}.
+type MacroStep struct {
+	Tool  string                 `json:"tool"`
+	Input map[string]interface{} `json:"input"`
+}
+
+// Macro is a named sequence of tool calls
+type Macro struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Defaults    map[string]string `json:"defaults,omitempty"`
+	Steps       []MacroStep       `json:"steps"`
+}
+
+// MacroStepResult is the outcome of one macro step
+type MacroStepResult struct {
+	Tool    string          `json:"tool"`
+	Input   json.RawMessage `json:"input"`
+	Success bool            `json:"success"`
+	Output  string          `json:"output,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// MacroOutput represents the structured output of the macro tool
+type MacroOutput struct {
+	Macro     string            `json:"macro,omitempty"`
+	Success   bool              `json:"success"`
+	Steps     []MacroStepResult `json:"steps,omitempty"`
+	Skipped   int               `json:"skipped,omitempty"`
+	Available []Macro           `json:"available,omitempty"`
+}
+
+var (
+	macros      = map[string]Macro{}
+	macrosMutex sync.RWMutex
+
+	// macroTools returns the tools macro steps may call outside an agent. It is assigned in init
+	// because GetAllTools refers back to MacroToolDefinition.
+	macroTools func() []ToolDefinition
+)
+
+var macroPlaceholderPattern = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+func init() {
+	macroTools = GetAllTools
+	if err := RegisterMacros(defaultMacros()); err != nil {
+		panic(err)
+	}
+}
+
+// defaultMacros returns the macros available without any configuration
+func defaultMacros() []Macro {
+	goStep := func(command string) MacroStep {
+		return MacroStep{Tool: "go_command", Input: map[string]interface{}{"command": command, "path": "{{path}}"}}
+	}
+	return []Macro{
+		{
+			Name:        "check",
+			Description: "Format, build, and test the packages at path",
+			Defaults:    map[string]string{"path": "./..."},
+			Steps:       []MacroStep{goStep("fmt"), goStep("build"), goStep("test")},
+		},
+		{
+			Name:        "verify",
+			Description: "Build, vet, and test the packages at path without modifying files",
+			Defaults:    map[string]string{"path": "./..."},
+			Steps:       []MacroStep{goStep("build"), goStep("vet"), goStep("test")},
+		},
+	}
+}
+
+// RegisterMacros validates and adds macros, replacing any existing macro with the same name
+func RegisterMacros(defs []Macro) error {
+	for _, macro := range defs {
+		if macro.Name == "" {
+			return fmt.Errorf("macro name cannot be empty")
+		}
+		if len(macro.Steps) == 0 {
+			return fmt.Errorf("macro %q has no steps", macro.Name)
+		}
+		for i, step := range macro.Steps {
+			if step.Tool == "" {
+				return fmt.Errorf("macro %q step %d has no tool", macro.Name, i+1)
+			}
+			if step.Tool == MacroToolDefinition.Name {
+				return fmt.Errorf("macro %q step %d cannot call another macro", macro.Name, i+1)
+			}
+		}
+	}
+
+	macrosMutex.Lock()
+	defer macrosMutex.Unlock()
+	for _, macro := range defs {
+		macros[macro.Name] = macro
+	}
+	return nil
+}
+
+// LoadMacros reads macro definitions from a JSON file containing an array of macros
+func LoadMacros(path string) ([]Macro, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read macros file '%s': %w", path, err)
+	}
+	var defs []Macro
+	if err := json.Unmarshal(content, &defs); err != nil {
+		return nil, fmt.Errorf("failed to parse macros file '%s': %w", path, err)
+	}
+	return defs, nil
+}
+
+// lookupMacro returns the registered macro with the given name
+func lookupMacro(name string) (Macro, bool) {
+	macrosMutex.RLock()
+	defer macrosMutex.RUnlock()
+	macro, ok := macros[name]
+	return macro, ok
+}
+
+// listMacros returns all registered macros sorted by name
+func listMacros() []Macro {
+	macrosMutex.RLock()
+	defer macrosMutex.RUnlock()
+	list := make([]Macro, 0, len(macros))
+	for _, macro := range macros {
+		list = append(list, macro)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// RunMacro implements the macro tool functionality
+func RunMacro(ctx context.Context, input json.RawMessage) (string, error) {
+	macroInput := MacroInput{}
+	err := json.Unmarshal(input, &macroInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	output := MacroOutput{Macro: macroInput.Name}
+
+	if macroInput.List {
+		output.Success = true
+		output.Available = listMacros()
+	} else {
+		if macroInput.Name == "" {
+			return "", fmt.Errorf("name parameter is required unless list is set")
+		}
+		macro, ok := lookupMacro(macroInput.Name)
+		if !ok {
+			return "", fmt.Errorf("unknown macro: %s. Call with list to see the available macros", macroInput.Name)
+		}
+
+		steps, err := expandMacroSteps(macro, macroInput.Args)
+		if err != nil {
+			return "", err
+		}
+
+		run := toolRunnerFrom(ctx)
+		output.Success = true
+		for i, step := range steps {
+			result := MacroStepResult{Tool: step.Tool, Input: step.Input}
+			result.Output, err = run(ctx, step.Tool, step.Input)
+			if err != nil {
+				result.Error = err.Error()
+			}
+			result.Success = result.Error == "" && !reportsFailure(result.Output)
+			output.Steps = append(output.Steps, result)
+
+			if !result.Success {
+				output.Success = false
+				output.Skipped = len(steps) - i - 1
+				break
+			}
+		}
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// ToolRunner runs a tool call by name. The agent passes its own runner to tools through the
+// context so macro steps go through the same checks, limits, logging and metrics as direct calls.
+type ToolRunner func(ctx context.Context, name string, input json.RawMessage) (string, error)
+
+// toolRunnerKey is the context key under which the agent's ToolRunner is stored
+type toolRunnerKey struct{}
+
+// WithToolRunner returns a copy of ctx whose macro steps are run by run
+func WithToolRunner(ctx context.Context, run ToolRunner) context.Context {
+	return context.WithValue(ctx, toolRunnerKey{}, run)
+}
+
+// toolRunnerFrom returns the ToolRunner stored in ctx. Without one, as when a macro runs
+// outside an agent, steps call the tool functions directly after checking their required fields.
+func toolRunnerFrom(ctx context.Context) ToolRunner {
+	if run, ok := ctx.Value(toolRunnerKey{}).(ToolRunner); ok && run != nil {
+		return run
+	}
+	return func(ctx context.Context, name string, input json.RawMessage) (string, error) {
+		for _, tool := range macroTools() {
+			if tool.Name != name {
+				continue
+			}
+			if missing := MissingRequiredFields(tool, input); len(missing) > 0 {
+				return "", fmt.Errorf("missing required field(s) for %s: %s", name, strings.Join(missing, ", "))
+			}
+			return tool.Function(ctx, input)
+		}
+		return "", fmt.Errorf("tool not found: %s", name)
+	}
+}
+
+// expandedMacroStep is a macro step with its arguments substituted
+type expandedMacroStep struct {
+	Tool  string
+	Input json.RawMessage
+}
+
+// expandMacroSteps substitutes args, falling back to the macro defaults, into every step input
+func expandMacroSteps(macro Macro, args map[string]string) ([]expandedMacroStep, error) {
+	values := map[string]string{}
+	for name, value := range macro.Defaults {
+		values[name] = value
+	}
+	for name, value := range args {
+		values[name] = value
+	}
+
+	steps := make([]expandedMacroStep, 0, len(macro.Steps))
+	for i, step := range macro.Steps {
+		substituted, err := substituteMacroArgs(step.Input, values)
+		if err != nil {
+			return nil, fmt.Errorf("macro %q step %d: %w", macro.Name, i+1, err)
+		}
+		if substituted == nil {
+			substituted = map[string]interface{}{}
+		}
+		input, err := json.Marshal(substituted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode input for macro %q step %d: %w", macro.Name, i+1, err)
+		}
+		steps = append(steps, expandedMacroStep{Tool: step.Tool, Input: input})
+	}
+	return steps, nil
+}
+
+// substituteMacroArgs replaces {{name}} placeholders in every string within value
+func substituteMacroArgs(value interface{}, values map[string]string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		var missing string
+		replaced := macroPlaceholderPattern.ReplaceAllStringFunc(v, func(match string) string {
+			name := macroPlaceholderPattern.FindStringSubmatch(match)[1]
+			arg, ok := values[name]
+			if !ok && missing == "" {
+				missing = name
+			}
+			return arg
+		})
+		if missing != "" {
+			return nil, fmt.Errorf("argument %q is not set and has no default", missing)
+		}
+		return replaced, nil
+
+	case map[string]interface{}:
+		substituted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			s, err := substituteMacroArgs(item, values)
+			if err != nil {
+				return nil, err
+			}
+			substituted[key] = s
+		}
+		return substituted, nil
+
+	case []interface{}:
+		substituted := make([]interface{}, len(v))
+		for i, item := range v {
+			s, err := substituteMacroArgs(item, values)
+			if err != nil {
+				return nil, err
+			}
+			substituted[i] = s
+		}
+		return substituted, nil
+	}
+	return value, nil
+}
+
+// reportsFailure reports whether a tool's JSON output carries "success": false,
+// as go_command does when the command exits with an error
+func reportsFailure(output string) bool {
+	if !strings.HasPrefix(strings.TrimSpace(output), "{") {
+		return false
+	}
+	var result struct {
+		Success *bool `json:"success"`
+	}
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return false
+	}
+	return result.Success != nil && !*result.Success
+}
+
+// isMutatingMacroCall reports whether any step of the macro named in input may mutate
+func isMutatingMacroCall(input json.RawMessage) bool {
+	macroInput := MacroInput{}
+	if err := json.Unmarshal(input, &macroInput); err != nil {
+		return true
+	}
+	if macroInput.List {
+		return false
+	}
+	macro, ok := lookupMacro(macroInput.Name)
+	if !ok {
+		return false
+	}
+	steps, err := expandMacroSteps(macro, macroInput.Args)
+	if err != nil {
+		return false
+	}
+	for _, step := range steps {
+		if IsMutatingToolCall(step.Tool, step.Input) {
+			return true
+		}
+	}
+	return false
+}
+
+// MacroStepTools returns the names of the tools a macro call would run, or nil if the call
+// lists macros or names an unknown macro
+func MacroStepTools(input json.RawMessage) []string {
+	macroInput := MacroInput{}
+	if err := json.Unmarshal(input, &macroInput); err != nil || macroInput.List {
+		return nil
+	}
+	macro, ok := lookupMacro(macroInput.Name)
+	if !ok {
+		return nil
+	}
+	var names []string
+	for _, step := range macro.Steps {
+		names = append(names, step.Tool)
+	}
+	return names
+}
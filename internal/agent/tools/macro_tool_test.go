@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// registerTestMacro registers macro for the duration of the test
+func registerTestMacro(t *testing.T, macro Macro) {
+	t.Helper()
+	if err := RegisterMacros([]Macro{macro}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		macrosMutex.Lock()
+		delete(macros, macro.Name)
+		macrosMutex.Unlock()
+	})
+}
+
+// writeThenReadMacro creates a file and then reads it back, so the second step only succeeds after the first
+var writeThenReadMacro = Macro{
+	Name:     "test_write_read",
+	Defaults: map[string]string{"content": "hello"},
+	Steps: []MacroStep{
+		{Tool: "file_editor", Input: map[string]interface{}{"path": "{{file}}", "mode": "create", "content": "{{content}}\n"}},
+		{Tool: "file_reader", Input: map[string]interface{}{"path": "{{file}}"}},
+	},
+}
+
+func TestRunMacroDependentSteps(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	registerTestMacro(t, writeThenReadMacro)
+
+	var output MacroOutput
+	callTool(t, ctx, RunMacro, MacroInput{Name: "test_write_read", Args: map[string]string{"file": "notes.txt"}}, &output)
+
+	if !output.Success || len(output.Steps) != 2 {
+		t.Fatalf("expected both steps to succeed: %+v", output)
+	}
+	if !strings.Contains(output.Steps[1].Output, "hello") {
+		t.Errorf("second step didn't read what the first wrote: %s", output.Steps[1].Output)
+	}
+	if got := readTestFile(t, filepath.Join(dir, "notes.txt")); got != "hello\n" {
+		t.Errorf("file = %q", got)
+	}
+}
+
+func TestRunMacroStopsAtFirstFailure(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	registerTestMacro(t, Macro{
+		Name: "test_read_first",
+		Steps: []MacroStep{
+			{Tool: "file_reader", Input: map[string]interface{}{"path": "missing.txt"}},
+			{Tool: "file_editor", Input: map[string]interface{}{"path": "missing.txt", "mode": "create", "content": "x"}},
+		},
+	})
+
+	var output MacroOutput
+	callTool(t, ctx, RunMacro, MacroInput{Name: "test_read_first"}, &output)
+
+	if output.Success || len(output.Steps) != 1 || output.Skipped != 1 {
+		t.Fatalf("expected the macro to stop after the failed read: %+v", output)
+	}
+	if output.Steps[0].Error == "" {
+		t.Error("the failed step should carry its error")
+	}
+}
+
+func TestRunMacroUsesContextRunner(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	registerTestMacro(t, writeThenReadMacro)
+
+	var calls []string
+	ctx = WithToolRunner(ctx, func(ctx context.Context, name string, input json.RawMessage) (string, error) {
+		calls = append(calls, name+" "+string(input))
+		return `{"success": true}`, nil
+	})
+
+	var output MacroOutput
+	callTool(t, ctx, RunMacro, MacroInput{Name: "test_write_read", Args: map[string]string{"file": "a.txt", "content": "hi"}}, &output)
+
+	want := []string{
+		`file_editor {"content":"hi\n","mode":"create","path":"a.txt"}`,
+		`file_reader {"path":"a.txt"}`,
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("runner calls = %q, want %q", calls, want)
+	}
+}
+
+func TestRunMacroMissingArgument(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	registerTestMacro(t, writeThenReadMacro)
+
+	_, err := RunMacro(ctx, mustMarshal(t, MacroInput{Name: "test_write_read"}))
+	if err == nil || !strings.Contains(err.Error(), `argument "file" is not set`) {
+		t.Errorf("got %v, want a missing argument error", err)
+	}
+}
+
+func TestRegisterMacrosValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		macro Macro
+		want  string
+	}{
+		{"no name", Macro{Steps: []MacroStep{{Tool: "file_reader"}}}, "name cannot be empty"},
+		{"no steps", Macro{Name: "empty"}, "has no steps"},
+		{"no tool", Macro{Name: "blank", Steps: []MacroStep{{}}}, "has no tool"},
+		{"nested", Macro{Name: "nested", Steps: []MacroStep{{Tool: "macro"}}}, "cannot call another macro"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterMacros([]Macro{tt.macro})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMacros(t *testing.T) {
+	_, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "macros.json", `[{"name": "lint", "steps": [{"tool": "run_linter", "input": {}}]}]`)
+
+	defs, err := LoadMacros(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defs) != 1 || defs[0].Name != "lint" || defs[0].Steps[0].Tool != "run_linter" {
+		t.Errorf("got %+v", defs)
+	}
+}
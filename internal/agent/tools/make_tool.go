@@ -0,0 +1,274 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"metamorph/internal/logger"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MakeToolDefinition defines the make tool
+var MakeToolDefinition = ToolDefinition{
+	Name: "make",
+	Description: `List the targets of the project's Makefile, or run one of them.
+Without a target, returns the targets defined in the Makefile with their prerequisites, whether
+they are .PHONY and their description (a '## text' comment on the rule line, or a comment on the
+line above). With a target, runs 'make <target>' in the Makefile's directory and captures its
+output like go_command. Set 'dry_run' to print the commands without running them (make -n).
+Prefer the project's targets over hand-written commands: they are its canonical build entrypoints.`,
+	InputSchema:           MakeInputSchema,
+	Function:              Make,
+	CountsTowardLoopLimit: true,
+}
+
+// MakeInput defines the input parameters for the make tool
+type MakeInput struct {
+	Target         string            `json:"target,omitempty" jsonschema_description:"Target to run. Leave empty to list the targets." jsonschema_example:"test"`
+	Path           string            `json:"path,omitempty" jsonschema_description:"Path to the Makefile or its directory. Defaults to the current directory."`
+	Variables      map[string]string `json:"variables,omitempty" jsonschema_description:"Variables to pass on the command line as NAME=value" jsonschema_example:"{\"VERBOSE\": \"1\"}"`
+	DryRun         bool              `json:"dry_run,omitempty" jsonschema_description:"If true, print the commands the target would run without running them"`
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty" jsonschema_description:"Deadline for the run. Defaults to 300, capped at 1800."`
+}
+
+// MakeInputSchema is the JSON schema for the make tool
+var MakeInputSchema = GenerateSchema[MakeInput]()
+
+// MakeTarget is a target defined in a Makefile
+type MakeTarget struct {
+	Name          string `json:"name"`
+	Prerequisites string `json:"prerequisites,omitempty"`
+	Phony         bool   `json:"phony,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Line          int    `json:"line"`
+}
+
+// MakeOutput represents the structured output of the make tool
+type MakeOutput struct {
+	Makefile     string       `json:"makefile"`
+	Targets      []MakeTarget `json:"targets,omitempty"`
+	Command      string       `json:"command,omitempty"`
+	Success      bool         `json:"success"`
+	ExitCode     int          `json:"exit_code,omitempty"`
+	Stdout       string       `json:"stdout,omitempty"`
+	Stderr       string       `json:"stderr,omitempty"`
+	ErrorMessage string       `json:"error_message,omitempty"`
+}
+
+const (
+	defaultMakeTimeout = 300 * time.Second
+	maxMakeTimeout     = 1800 * time.Second
+)
+
+// makefileNames are the files make reads by default, in its order of preference
+var makefileNames = []string{"GNUmakefile", "makefile", "Makefile"}
+
+// makeIncludePattern matches an include directive, which may define further targets
+var makeIncludePattern = regexp.MustCompile(`^-?s?include\s`)
+
+// makeVariableNamePattern matches a variable name that is safe to pass as NAME=value
+var makeVariableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Make implements the make tool functionality
+func Make(ctx context.Context, input json.RawMessage) (string, error) {
+	makeInput := MakeInput{}
+	err := DecodeInput(input, &makeInput)
+	if err != nil {
+		return "", err
+	}
+
+	makefile, err := findMakefile(ctx, makeInput.Path)
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(makefile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", makefile, err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	targets := makefileTargets(lines)
+
+	output := MakeOutput{Makefile: makefile}
+	if makeInput.Target == "" {
+		output.Targets = targets
+		output.Success = true
+		return marshalMakeOutput(output)
+	}
+
+	if strings.HasPrefix(makeInput.Target, "-") || strings.ContainsAny(makeInput.Target, " \t=") {
+		return "", fmt.Errorf("invalid target %q", makeInput.Target)
+	}
+	// Targets from included files can't be checked, so let make report those
+	if !hasMakeTarget(targets, makeInput.Target) && !hasMakeInclude(lines) {
+		var names []string
+		for _, target := range targets {
+			names = append(names, target.Name)
+		}
+		return "", fmt.Errorf("target %s not found in %s; available targets: %s", makeInput.Target, makefile, strings.Join(names, ", "))
+	}
+	if _, err := exec.LookPath("make"); err != nil {
+		return "", fmt.Errorf("make not found in PATH: %w", err)
+	}
+
+	args := []string{"-f", filepath.Base(makefile)}
+	if makeInput.DryRun {
+		args = append(args, "-n")
+	}
+	args = append(args, makeInput.Target)
+	var names []string
+	for name := range makeInput.Variables {
+		if !makeVariableNamePattern.MatchString(name) {
+			return "", fmt.Errorf("invalid variable name %q", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, name+"="+makeInput.Variables[name])
+	}
+
+	timeout := defaultMakeTimeout
+	if makeInput.TimeoutSeconds > 0 {
+		timeout = min(time.Duration(makeInput.TimeoutSeconds)*time.Second, maxMakeTimeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "make", args...)
+	cmd.Dir = filepath.Dir(makefile)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = backgroundStopTimeout
+
+	logger.FromContext(ctx).Debug().
+		Strs("args", logger.RedactStrings(args)).
+		Str("workingDir", cmd.Dir).
+		Msg("Running make")
+	cmdErr := cmd.Run()
+
+	output.Command = QuoteShellCommand(append([]string{"make"}, args...)...)
+	output.Success = cmdErr == nil
+	output.Stdout = stdout.String()
+	output.Stderr = stderr.String()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		output.ExitCode = -1
+		output.ErrorMessage = fmt.Sprintf("make timed out after %s", timeout)
+	case errors.As(cmdErr, &exitErr):
+		output.ExitCode = exitErr.ExitCode()
+		output.ErrorMessage = cmdErr.Error()
+	case cmdErr != nil:
+		return "", fmt.Errorf("failed to run make: %w", cmdErr)
+	}
+
+	return marshalMakeOutput(output)
+}
+
+// findMakefile resolves path to a Makefile: a file is used as is, and a directory (the
+// workspace by default) is searched for the names make reads by default
+func findMakefile(ctx context.Context, path string) (string, error) {
+	dir := workspaceDir(ctx)
+	if path != "" {
+		resolved, err := ResolvePath(ctx, path)
+		if err != nil {
+			return "", err
+		}
+		info, err := os.Stat(resolved)
+		if err != nil {
+			return "", fmt.Errorf("failed to stat %s: %w", resolved, err)
+		}
+		if !info.IsDir() {
+			return resolved, nil
+		}
+		dir = resolved
+	}
+
+	for _, name := range makefileNames {
+		candidate := filepath.Join(dir, name)
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no Makefile found in %s", dir)
+}
+
+// makefileTargets returns the targets a user can run: special targets such as .PHONY and
+// pattern rules are left out, and a target split over several rules is listed once
+func makefileTargets(lines []string) []MakeTarget {
+	rules, _ := parseMakefile(lines)
+	targets := []MakeTarget{}
+	seen := map[string]int{}
+	for _, rule := range rules {
+		for _, name := range rule.targets {
+			if strings.HasPrefix(name, ".") || strings.ContainsAny(name, "%$") {
+				continue
+			}
+			if index, found := seen[name]; found {
+				if targets[index].Description == "" {
+					targets[index].Description = makeRuleDescription(lines, rule.start)
+				}
+				continue
+			}
+			seen[name] = len(targets)
+			targets = append(targets, MakeTarget{
+				Name:          name,
+				Prerequisites: rule.prerequisites,
+				Phony:         isPhonyTarget(rules, name),
+				Description:   makeRuleDescription(lines, rule.start),
+				Line:          rule.start + 1,
+			})
+		}
+	}
+	return targets
+}
+
+// makeRuleDescription returns the '## text' comment on a rule header, or else the comment on the
+// line above it
+func makeRuleDescription(lines []string, header int) string {
+	if _, comment, found := strings.Cut(lines[header], "##"); found {
+		return strings.TrimSpace(comment)
+	}
+	if header > 0 && strings.HasPrefix(lines[header-1], "#") {
+		return strings.TrimSpace(strings.TrimLeft(lines[header-1], "#"))
+	}
+	return ""
+}
+
+// hasMakeTarget reports whether targets includes name
+func hasMakeTarget(targets []MakeTarget, name string) bool {
+	for _, target := range targets {
+		if target.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasMakeInclude reports whether the Makefile includes other files
+func hasMakeInclude(lines []string) bool {
+	for _, line := range lines {
+		if makeIncludePattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// marshalMakeOutput renders the make output as indented JSON
+func marshalMakeOutput(output MakeOutput) (string, error) {
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+	return string(jsonOutput), nil
+}
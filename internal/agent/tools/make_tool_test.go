@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const makeToolFixture = `GREETING ?= hello
+
+.PHONY: build greet fail
+
+# Compile everything
+build:
+	@echo building
+
+greet: build ## Print a greeting
+	@echo $(GREETING) > greeting.txt
+
+fail:
+	@exit 3
+
+%.o: %.c
+	cc -c $<
+`
+
+func TestMakeListsTargets(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "Makefile", makeToolFixture)
+
+	var output MakeOutput
+	callTool(t, ctx, Make, MakeInput{}, &output)
+
+	want := []MakeTarget{
+		{Name: "build", Phony: true, Description: "Compile everything", Line: 6},
+		{Name: "greet", Prerequisites: "build", Phony: true, Description: "Print a greeting", Line: 9},
+		{Name: "fail", Phony: true, Line: 12},
+	}
+	if !reflect.DeepEqual(output.Targets, want) {
+		t.Errorf("targets = %+v, want %+v", output.Targets, want)
+	}
+}
+
+func TestMakeRunsTarget(t *testing.T) {
+	if _, err := exec.LookPath("make"); err != nil {
+		t.Skip("make is not installed")
+	}
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "sub/Makefile", makeToolFixture)
+
+	var output MakeOutput
+	callTool(t, ctx, Make, MakeInput{Path: "sub", Target: "greet", Variables: map[string]string{"GREETING": "hi there"}}, &output)
+	if !output.Success || output.Stdout != "building\n" {
+		t.Errorf("got %+v, want a successful run", output)
+	}
+	if content := readTestFile(t, filepath.Join(dir, "sub", "greeting.txt")); content != "hi there\n" {
+		t.Errorf("greeting.txt = %q", content)
+	}
+
+	output = MakeOutput{}
+	callTool(t, ctx, Make, MakeInput{Path: "sub/Makefile", Target: "fail"}, &output)
+	if output.Success || output.ExitCode != 2 || output.ErrorMessage == "" {
+		t.Errorf("got %+v, want make's failure exit code", output)
+	}
+
+	output = MakeOutput{}
+	callTool(t, ctx, Make, MakeInput{Path: "sub", Target: "fail", DryRun: true}, &output)
+	if !output.Success || !strings.Contains(output.Stdout, "exit 3") {
+		t.Errorf("got %+v, want the recipe printed without running it", output)
+	}
+}
+
+func TestMakeRejections(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+
+	if _, err := Make(ctx, mustMarshal(t, MakeInput{})); err == nil || !strings.Contains(err.Error(), "no Makefile found") {
+		t.Errorf("got %v, want a missing Makefile reported", err)
+	}
+
+	writeTestFile(t, dir, "Makefile", makeToolFixture)
+	_, err := Make(ctx, mustMarshal(t, MakeInput{Target: "deploy"}))
+	if err == nil || !strings.Contains(err.Error(), "available targets: build, greet, fail") {
+		t.Errorf("got %v, want the missing target reported with the available ones", err)
+	}
+	for _, input := range []MakeInput{{Target: "-j"}, {Target: "X=1"}, {Target: "build", Variables: map[string]string{"A B": "1"}}} {
+		if _, err := Make(ctx, mustMarshal(t, input)); err == nil {
+			t.Errorf("%+v: expected an error", input)
+		}
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if _, err := Make(ctx, mustMarshal(t, MakeInput{Target: "build"})); err == nil || !strings.Contains(err.Error(), "make not found") {
+		t.Errorf("got %v, want the missing make binary reported", err)
+	}
+}
@@ -0,0 +1,388 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MakefileEditToolDefinition defines the makefile_edit tool
+var MakefileEditToolDefinition = ToolDefinition{
+	Name: "makefile_edit",
+	Description: `Add, update or remove a Makefile target, always indenting recipe lines with the tab make requires.
+Editing a Makefile with file_editor easily turns recipe tabs into spaces, which make rejects with
+"missing separator". Give the target with its prerequisites and recipe lines (leading whitespace is
+replaced by a tab); an existing rule for the target is replaced, otherwise the rule is appended.
+When updating, omitted prerequisites or recipe keep the existing ones. Set 'phony' to declare the
+target .PHONY. Without a target the Makefile is only checked: recipe lines indented with spaces are
+reported, and converted to tabs if 'fix_indentation' is set. The check runs after every edit too, and
+after a removal it also reports the rules that still list the removed target as a prerequisite.`,
+	InputSchema:           MakefileEditInputSchema,
+	Function:              MakefileEdit,
+	CountsTowardLoopLimit: true,
+}
+
+// MakefileEditInput defines the input parameters for the makefile_edit tool
+type MakefileEditInput struct {
+	Path           string   `json:"path,omitempty" jsonschema_description:"Path to the Makefile. Defaults to 'Makefile'."`
+	Target         string   `json:"target,omitempty" jsonschema_description:"Target to add, update or remove. Leave empty to only check the Makefile." jsonschema_example:"lint"`
+	Prerequisites  []string `json:"prerequisites,omitempty" jsonschema_description:"Prerequisites of the target" jsonschema_example:"[\"build\"]"`
+	Recipe         []string `json:"recipe,omitempty" jsonschema_description:"Recipe lines, without the leading tab" jsonschema_example:"[\"go vet ./...\", \"golangci-lint run\"]"`
+	Phony          bool     `json:"phony,omitempty" jsonschema_description:"If true, declare the target .PHONY"`
+	Remove         bool     `json:"remove,omitempty" jsonschema_description:"If true, remove the target's rule and .PHONY entry"`
+	FixIndentation bool     `json:"fix_indentation,omitempty" jsonschema_description:"If true, replace the leading spaces of recipe lines with a tab"`
+}
+
+// MakefileEditInputSchema is the JSON schema for the makefile_edit tool
+var MakefileEditInputSchema = GenerateSchema[MakefileEditInput]()
+
+// MakefileIssue is a line make would reject or that refers to a removed target
+type MakefileIssue struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// MakefileEditOutput represents the structured output of the makefile_edit tool
+type MakefileEditOutput struct {
+	Path   string          `json:"path"`
+	Action string          `json:"action"`
+	Issues []MakefileIssue `json:"issues"`
+	Fixed  int             `json:"fixed,omitempty"`
+	Diff   string          `json:"diff,omitempty"`
+}
+
+// makeRule is a rule in a Makefile: its header line and recipe lines span lines [start, end)
+type makeRule struct {
+	targets       []string
+	prerequisites string
+	start         int
+	end           int
+}
+
+// makeRuleHeaderPattern matches a rule header such as 'build test: deps', or a variable
+// assignment like 'X := y' that isMakeRuleHeader excludes
+var makeRuleHeaderPattern = regexp.MustCompile(`^[^:#=\s][^:#=]*:`)
+
+// makeDefinePattern matches the start of a multi-line variable definition
+var makeDefinePattern = regexp.MustCompile(`^(?:(?:override|export)\s+)*define(?:\s|$)`)
+
+// makeRecipePrefixPattern matches an assignment changing the recipe prefix from a tab
+var makeRecipePrefixPattern = regexp.MustCompile(`^\.RECIPEPREFIX\s*[:+?]?=`)
+
+// parseMakefile returns the rules of a Makefile and the recipe lines indented with spaces
+func parseMakefile(lines []string) ([]makeRule, []MakefileIssue) {
+	var rules []makeRule
+	var issues []MakefileIssue
+	current := -1
+	inDefine := false
+	continued := false
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		wasContinued := continued
+		continued = strings.HasSuffix(line, `\`)
+
+		switch {
+		case wasContinued:
+			if current >= 0 {
+				rules[current].end = i + 1
+			}
+		case inDefine:
+			inDefine = !strings.HasPrefix(trimmed, "endef")
+		case makeDefinePattern.MatchString(trimmed):
+			inDefine = true
+			current = -1
+		case strings.HasPrefix(line, "\t"):
+			if current >= 0 {
+				rules[current].end = i + 1
+			}
+		case strings.HasPrefix(line, " ") && trimmed != "":
+			if current >= 0 {
+				issues = append(issues, MakefileIssue{Line: i + 1, Message: "recipe line is indented with spaces; make requires a tab"})
+				rules[current].end = i + 1
+			}
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			// Blank lines and comments don't end a recipe
+		case isMakeRuleHeader(line):
+			header := strings.TrimSuffix(line, `\`)
+			colon := strings.Index(header, ":")
+			prerequisites := strings.TrimLeft(header[colon+1:], ":")
+			if end := strings.IndexAny(prerequisites, ";#"); end >= 0 {
+				prerequisites = prerequisites[:end]
+			}
+			rules = append(rules, makeRule{
+				targets:       strings.Fields(header[:colon]),
+				prerequisites: strings.TrimSpace(prerequisites),
+				start:         i,
+				end:           i + 1,
+			})
+			current = len(rules) - 1
+		default:
+			current = -1
+		}
+	}
+	return rules, issues
+}
+
+// isMakeRuleHeader reports whether line starts a rule rather than assigning a variable
+func isMakeRuleHeader(line string) bool {
+	if !makeRuleHeaderPattern.MatchString(line) {
+		return false
+	}
+	rest := line[strings.Index(line, ":"):]
+	return !strings.HasPrefix(strings.TrimLeft(rest, ":"), "=")
+}
+
+// MakefileEdit implements the makefile_edit tool functionality
+func MakefileEdit(ctx context.Context, input json.RawMessage) (string, error) {
+	editInput := MakefileEditInput{}
+	err := DecodeInput(input, &editInput)
+	if err != nil {
+		return "", err
+	}
+
+	if editInput.Path == "" {
+		editInput.Path = "Makefile"
+	}
+	path, err := ResolvePath(ctx, editInput.Path)
+	if err != nil {
+		return "", err
+	}
+	target := strings.TrimSpace(editInput.Target)
+	if strings.ContainsAny(target, " \t:=#") {
+		return "", fmt.Errorf("invalid target %q", editInput.Target)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !(os.IsNotExist(err) && target != "" && !editInput.Remove) {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	before := string(content)
+	lines := []string{}
+	if before != "" {
+		lines = strings.Split(strings.TrimSuffix(before, "\n"), "\n")
+	}
+	for _, line := range lines {
+		if makeRecipePrefixPattern.MatchString(strings.TrimSpace(line)) {
+			return "", fmt.Errorf("%s sets .RECIPEPREFIX, so recipes need not start with a tab; edit it with file_editor", path)
+		}
+	}
+
+	output := MakefileEditOutput{Path: path, Action: "checked"}
+	switch {
+	case target != "" && editInput.Remove:
+		lines, err = removeMakeTarget(lines, target)
+		output.Action = "removed"
+	case target != "":
+		lines, output.Action, err = setMakeTarget(lines, editInput, target)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	_, issues := parseMakefile(lines)
+	if editInput.FixIndentation {
+		for _, issue := range issues {
+			lines[issue.Line-1] = "\t" + strings.TrimLeft(lines[issue.Line-1], " \t")
+		}
+		output.Fixed = len(issues)
+		_, issues = parseMakefile(lines)
+	}
+	if target != "" && editInput.Remove {
+		issues = append(issues, danglingMakePrerequisites(lines, target)...)
+	}
+	output.Issues = issues
+	if output.Issues == nil {
+		output.Issues = []MakefileIssue{}
+	}
+
+	after := ""
+	if len(lines) > 0 {
+		after = strings.Join(lines, "\n") + "\n"
+	}
+	if after != before {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", fmt.Errorf("failed to create directory for %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, []byte(after), 0644); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		recordReadHash(path, []byte(after))
+		output.Diff = unifiedDiff(editInput.Path, before, after)
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// findMakeRule returns the index of the rule defining target alone, preferring one with a
+// recipe, or -1 if there is none. A rule defining target along with other targets is an error,
+// since replacing it would change the others too.
+func findMakeRule(rules []makeRule, target string) (int, error) {
+	found := -1
+	for i, rule := range rules {
+		for _, name := range rule.targets {
+			if name != target {
+				continue
+			}
+			if len(rule.targets) > 1 {
+				return -1, fmt.Errorf("%s is defined together with other targets on line %d; edit that rule with file_editor", target, rule.start+1)
+			}
+			if found < 0 || (rules[found].end-rules[found].start == 1 && rule.end-rule.start > 1) {
+				found = i
+			}
+		}
+	}
+	return found, nil
+}
+
+// isPhonyTarget reports whether a .PHONY rule lists target
+func isPhonyTarget(rules []makeRule, target string) bool {
+	for _, rule := range rules {
+		if len(rule.targets) == 1 && rule.targets[0] == ".PHONY" {
+			for _, name := range strings.Fields(rule.prerequisites) {
+				if name == target {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// setMakeTarget replaces the rule for target, or appends one, and returns the new lines and
+// whether the target was added or updated
+func setMakeTarget(lines []string, editInput MakefileEditInput, target string) ([]string, string, error) {
+	rules, _ := parseMakefile(lines)
+	index, err := findMakeRule(rules, target)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prerequisites := strings.Join(editInput.Prerequisites, " ")
+	var recipe []string
+	for _, entry := range editInput.Recipe {
+		for _, line := range strings.Split(entry, "\n") {
+			if line = strings.TrimLeft(line, " \t"); line != "" {
+				recipe = append(recipe, "\t"+line)
+			}
+		}
+	}
+
+	var rule makeRule
+	if index >= 0 {
+		rule = rules[index]
+		if len(editInput.Prerequisites) == 0 {
+			prerequisites = rule.prerequisites
+		}
+		if len(recipe) == 0 {
+			recipe = lines[rule.start+1 : rule.end]
+		}
+	}
+
+	header := target + ":"
+	if prerequisites != "" {
+		header += " " + prerequisites
+	}
+	block := []string{}
+	if editInput.Phony && !isPhonyTarget(rules, target) {
+		block = append(block, ".PHONY: "+target)
+	}
+	block = append(block, header)
+	block = append(block, recipe...)
+
+	if index < 0 {
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		return append(lines, block...), "added", nil
+	}
+
+	updated := append([]string{}, lines[:rule.start]...)
+	updated = append(updated, block...)
+	updated = append(updated, lines[rule.end:]...)
+	return updated, "updated", nil
+}
+
+// removeMakeTarget removes the rules for target and its .PHONY entries
+func removeMakeTarget(lines []string, target string) ([]string, error) {
+	rules, _ := parseMakefile(lines)
+	if index, err := findMakeRule(rules, target); err != nil {
+		return nil, err
+	} else if index < 0 {
+		return nil, fmt.Errorf("target %s not found", target)
+	}
+
+	removed := map[int]bool{}
+	for _, rule := range rules {
+		switch {
+		case len(rule.targets) == 1 && rule.targets[0] == target:
+			for i := rule.start; i < rule.end; i++ {
+				removed[i] = true
+			}
+		case len(rule.targets) == 1 && rule.targets[0] == ".PHONY" && rule.end-rule.start == 1:
+			var remaining []string
+			for _, name := range strings.Fields(rule.prerequisites) {
+				if name != target {
+					remaining = append(remaining, name)
+				}
+			}
+			if len(remaining) == 0 {
+				removed[rule.start] = true
+			} else {
+				lines[rule.start] = ".PHONY: " + strings.Join(remaining, " ")
+			}
+		}
+	}
+
+	var kept []string
+	dropped := false
+	for i, line := range lines {
+		if removed[i] {
+			dropped = true
+			continue
+		}
+		blank := strings.TrimSpace(line) == ""
+		// Don't leave two blank lines, or a leading one, where a rule was
+		if blank && dropped && (len(kept) == 0 || strings.TrimSpace(kept[len(kept)-1]) == "") {
+			continue
+		}
+		if !blank {
+			dropped = false
+		}
+		kept = append(kept, line)
+	}
+	for len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+		kept = kept[:len(kept)-1]
+	}
+	return kept, nil
+}
+
+// danglingMakePrerequisites reports the rules that still list the removed target as a prerequisite
+func danglingMakePrerequisites(lines []string, target string) []MakefileIssue {
+	rules, _ := parseMakefile(lines)
+	var issues []MakefileIssue
+	for _, rule := range rules {
+		if len(rule.targets) == 1 && rule.targets[0] == ".PHONY" {
+			continue
+		}
+		for _, name := range strings.Fields(rule.prerequisites) {
+			if name == target {
+				issues = append(issues, MakefileIssue{
+					Line:    rule.start + 1,
+					Message: fmt.Sprintf("%s still depends on the removed target %s", strings.Join(rule.targets, " "), target),
+				})
+				break
+			}
+		}
+	}
+	return issues
+}
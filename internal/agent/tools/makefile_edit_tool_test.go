@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const makefileFixture = `GO := go
+
+.PHONY: build test
+
+build:
+	$(GO) build ./...
+
+test: build
+	$(GO) test ./...
+`
+
+func TestMakefileEditAddsTargetWithTabs(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "Makefile", makefileFixture)
+
+	var output MakefileEditOutput
+	callTool(t, ctx, MakefileEdit, MakefileEditInput{
+		Target:        "lint",
+		Prerequisites: []string{"build"},
+		Recipe:        []string{"    go vet ./...", "golangci-lint run\n  staticcheck ./..."},
+		Phony:         true,
+	}, &output)
+
+	if output.Action != "added" || len(output.Issues) != 0 {
+		t.Errorf("got %+v, want the target added without issues", output)
+	}
+	want := makefileFixture + "\n.PHONY: lint\nlint: build\n\tgo vet ./...\n\tgolangci-lint run\n\tstaticcheck ./...\n"
+	if content := readTestFile(t, path); content != want {
+		t.Errorf("Makefile:\n%q\nwant:\n%q", content, want)
+	}
+}
+
+func TestMakefileEditUpdatesTarget(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "Makefile", makefileFixture)
+
+	var output MakefileEditOutput
+	callTool(t, ctx, MakefileEdit, MakefileEditInput{Target: "test", Recipe: []string{"$(GO) test -race ./..."}, Phony: true}, &output)
+
+	if output.Action != "updated" {
+		t.Errorf("action = %q, want updated", output.Action)
+	}
+	// Prerequisites are kept and the target is already phony
+	want := strings.Replace(makefileFixture, "\t$(GO) test ./...", "\t$(GO) test -race ./...", 1)
+	if content := readTestFile(t, path); content != want {
+		t.Errorf("Makefile:\n%s", content)
+	}
+}
+
+func TestMakefileEditCreatesFile(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+
+	callTool(t, ctx, MakefileEdit, MakefileEditInput{Path: "sub/Makefile", Target: "all", Recipe: []string{"echo hi"}}, nil)
+	// A new Makefile doesn't start with a blank line
+	if content := readTestFile(t, filepath.Join(dir, "sub", "Makefile")); content != "all:\n\techo hi\n" {
+		t.Errorf("Makefile = %q", content)
+	}
+}
+
+func TestMakefileEditRemovesTarget(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "Makefile", makefileFixture)
+
+	var output MakefileEditOutput
+	callTool(t, ctx, MakefileEdit, MakefileEditInput{Target: "build", Remove: true}, &output)
+
+	want := "GO := go\n\n.PHONY: test\n\ntest: build\n\t$(GO) test ./...\n"
+	if content := readTestFile(t, path); content != want {
+		t.Errorf("Makefile:\n%q\nwant:\n%q", content, want)
+	}
+	if len(output.Issues) != 1 || output.Issues[0].Line != 5 || !strings.Contains(output.Issues[0].Message, "test still depends on the removed target build") {
+		t.Errorf("issues = %+v, want the dangling prerequisite reported", output.Issues)
+	}
+
+	if _, err := MakefileEdit(ctx, mustMarshal(t, MakefileEditInput{Target: "missing", Remove: true})); err == nil {
+		t.Error("expected an error removing an unknown target")
+	}
+}
+
+func TestMakefileEditChecksIndentation(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	broken := "build:\n    go build ./...\n\ndefine HELP\n    not a recipe\nendef\n"
+	path := writeTestFile(t, dir, "Makefile", broken)
+
+	var output MakefileEditOutput
+	callTool(t, ctx, MakefileEdit, MakefileEditInput{}, &output)
+	if len(output.Issues) != 1 || output.Issues[0].Line != 2 || output.Diff != "" {
+		t.Errorf("got %+v, want line 2 reported without changes", output)
+	}
+
+	output = MakefileEditOutput{}
+	callTool(t, ctx, MakefileEdit, MakefileEditInput{FixIndentation: true}, &output)
+	if output.Fixed != 1 || len(output.Issues) != 0 {
+		t.Errorf("got %+v, want the line fixed", output)
+	}
+	if content := readTestFile(t, path); content != strings.Replace(broken, "    go build", "\tgo build", 1) {
+		t.Errorf("Makefile = %q", content)
+	}
+}
+
+func TestMakefileEditRejections(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "Makefile", "build test:\n\tgo build ./...\n")
+	writeTestFile(t, dir, "Prefixed.mk", ".RECIPEPREFIX = >\nall:\n> echo hi\n")
+
+	inputs := []MakefileEditInput{
+		{Target: "build", Recipe: []string{"true"}},
+		{Target: "a b"},
+		{Path: "Prefixed.mk", Target: "all", Recipe: []string{"echo"}},
+		{Path: "Missing.mk"},
+	}
+	for _, input := range inputs {
+		if _, err := MakefileEdit(ctx, mustMarshal(t, input)); err == nil {
+			t.Errorf("%+v: expected an error", input)
+		}
+	}
+}
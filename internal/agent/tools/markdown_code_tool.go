@@ -0,0 +1,215 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MarkdownCodeToolDefinition defines the verify_markdown_code tool
+var MarkdownCodeToolDefinition = ToolDefinition{
+	Name: "verify_markdown_code",
+	Description: `Check that the Go code examples in a Markdown file compile.
+Every fenced code block tagged 'go' (or 'golang') is extracted and built in its own temporary module
+with 'go build' (default) or 'go vet'. Blocks without a package clause are wrapped automatically:
+declarations go into 'package main', anything else becomes the body of main().
+Blocks in other languages are reported as skipped. Returns the status and compiler output of each block.`,
+	InputSchema:           MarkdownCodeInputSchema,
+	Function:              VerifyMarkdownCode,
+	CountsTowardLoopLimit: true,
+}
+
+// MarkdownCodeInput defines the input parameters for the verify_markdown_code tool
+type MarkdownCodeInput struct {
+	Path    string `json:"path" jsonschema_required:"true" jsonschema_description:"Path to the Markdown file" jsonschema_example:"README.md"`
+	Command string `json:"command,omitempty" jsonschema_description:"Go command used to check each block: 'build' (default) or 'vet'"`
+}
+
+// MarkdownCodeInputSchema is the JSON schema for the verify_markdown_code tool
+var MarkdownCodeInputSchema = GenerateSchema[MarkdownCodeInput]()
+
+// MarkdownCodeBlock reports the result of checking one fenced code block
+type MarkdownCodeBlock struct {
+	Index    int    `json:"index"`
+	Line     int    `json:"line"`
+	Language string `json:"language"`
+	Status   string `json:"status"`
+	Output   string `json:"output,omitempty"`
+}
+
+// MarkdownCodeOutput represents the structured output of the verify_markdown_code tool
+type MarkdownCodeOutput struct {
+	Path    string              `json:"path"`
+	Command string              `json:"command"`
+	Passed  int                 `json:"passed"`
+	Failed  int                 `json:"failed"`
+	Skipped int                 `json:"skipped"`
+	Blocks  []MarkdownCodeBlock `json:"blocks"`
+}
+
+// fencedBlock is a fenced code block extracted from a Markdown document
+type fencedBlock struct {
+	language string
+	line     int
+	code     string
+}
+
+// VerifyMarkdownCode implements the verify_markdown_code tool functionality
+func VerifyMarkdownCode(ctx context.Context, input json.RawMessage) (string, error) {
+	mdInput := MarkdownCodeInput{}
+	err := json.Unmarshal(input, &mdInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if mdInput.Path == "" {
+		return "", fmt.Errorf("path parameter is required")
+	}
+	mdInput.Path, err = ResolvePath(ctx, mdInput.Path)
+	if err != nil {
+		return "", err
+	}
+
+	command := mdInput.Command
+	if command == "" {
+		command = "build"
+	}
+	if command != "build" && command != "vet" {
+		return "", fmt.Errorf("invalid command: %s. Must be 'build' or 'vet'", command)
+	}
+
+	content, err := os.ReadFile(mdInput.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file '%s': %w", mdInput.Path, err)
+	}
+
+	output := MarkdownCodeOutput{
+		Path:    mdInput.Path,
+		Command: command,
+		Blocks:  []MarkdownCodeBlock{},
+	}
+
+	for i, block := range extractFencedBlocks(string(content)) {
+		result := MarkdownCodeBlock{Index: i + 1, Line: block.line, Language: block.language}
+
+		if block.language != "go" && block.language != "golang" {
+			result.Status = "skipped"
+			output.Skipped++
+		} else if out, err := checkGoSnippet(ctx, command, block.code); err != nil {
+			result.Status = "failed"
+			result.Output = out
+			if result.Output == "" {
+				result.Output = err.Error()
+			}
+			output.Failed++
+		} else {
+			result.Status = "passed"
+			output.Passed++
+		}
+
+		output.Blocks = append(output.Blocks, result)
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// fenceOpenPattern matches the opening line of a fenced code block and its info string
+var fenceOpenPattern = regexp.MustCompile("^\\s{0,3}(```+|~~~+)\\s*([^\\s`]*)")
+
+// extractFencedBlocks returns the fenced code blocks of a Markdown document in order
+func extractFencedBlocks(content string) []fencedBlock {
+	var blocks []fencedBlock
+	var current *fencedBlock
+	var fence string
+	var code []string
+
+	for i, line := range strings.Split(content, "\n") {
+		if current == nil {
+			if matches := fenceOpenPattern.FindStringSubmatch(line); matches != nil {
+				fence = matches[1]
+				current = &fencedBlock{language: strings.ToLower(matches[2]), line: i + 1}
+				code = nil
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			current.code = strings.Join(code, "\n")
+			blocks = append(blocks, *current)
+			current = nil
+			continue
+		}
+		code = append(code, line)
+	}
+
+	return blocks
+}
+
+var (
+	// packageClausePattern matches a package clause at the start of a line
+	packageClausePattern = regexp.MustCompile(`(?m)^package\s+\w+`)
+	// topLevelDeclPattern matches lines that start a top-level Go declaration
+	topLevelDeclPattern = regexp.MustCompile(`(?m)^(func|type|var|const|import)\b`)
+	// mainFuncPattern matches the declaration of func main
+	mainFuncPattern = regexp.MustCompile(`(?m)^func main\(\)`)
+)
+
+// wrapGoSnippet turns a code block into a complete Go source file
+func wrapGoSnippet(code string) string {
+	if packageClausePattern.MatchString(code) {
+		return code
+	}
+	if topLevelDeclPattern.MatchString(code) {
+		source := "package main\n\n" + code + "\n"
+		if !mainFuncPattern.MatchString(code) {
+			source += "\nfunc main() {}\n"
+		}
+		return source
+	}
+	return "package main\n\nfunc main() {\n" + code + "\n}\n"
+}
+
+// checkGoSnippet writes code to a throwaway module and runs the go command on it.
+// It returns the command output and a non-nil error when the check fails.
+func checkGoSnippet(ctx context.Context, command, code string) (string, error) {
+	dir, err := os.MkdirTemp("", "metamorph-mdcode-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module mdexample\n\ngo 1.21\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write go.mod: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(wrapGoSnippet(code)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write snippet: %w", err)
+	}
+
+	args := []string{command}
+	if command == "build" {
+		args = append(args, "-o", os.DevNull)
+	}
+	args = append(args, ".")
+
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	var combined bytes.Buffer
+	cmd.Stdout = &combined
+	cmd.Stderr = &combined
+
+	err = cmd.Run()
+	// Report positions relative to the snippet rather than the temporary directory
+	return strings.ReplaceAll(combined.String(), dir+string(filepath.Separator), ""), err
+}
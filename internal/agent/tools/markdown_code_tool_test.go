@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+const markdownCodeFixture = "# Usage\n" +
+	"\n" +
+	"```go\n" +
+	"package main\n" +
+	"\n" +
+	"import \"fmt\"\n" +
+	"\n" +
+	"func main() {\n" +
+	"\tfmt.Println(\"hello\")\n" +
+	"}\n" +
+	"```\n" +
+	"\n" +
+	"Install it with:\n" +
+	"\n" +
+	"```sh\n" +
+	"go install example.com/tool@latest\n" +
+	"```\n" +
+	"\n" +
+	"```go\n" +
+	"x := undefinedHelper()\n" +
+	"```\n"
+
+func TestVerifyMarkdownCode(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "README.md", markdownCodeFixture)
+
+	var output MarkdownCodeOutput
+	callTool(t, ctx, VerifyMarkdownCode, MarkdownCodeInput{Path: "README.md"}, &output)
+
+	if output.Passed != 1 || output.Failed != 1 || output.Skipped != 1 {
+		t.Fatalf("got passed=%d failed=%d skipped=%d, want 1 each: %+v", output.Passed, output.Failed, output.Skipped, output.Blocks)
+	}
+	want := []struct {
+		line     int
+		language string
+		status   string
+	}{
+		{3, "go", "passed"},
+		{15, "sh", "skipped"},
+		{19, "go", "failed"},
+	}
+	for i, w := range want {
+		block := output.Blocks[i]
+		if block.Line != w.line || block.Language != w.language || block.Status != w.status {
+			t.Errorf("block %d = %+v, want line %d %s %s", i+1, block, w.line, w.language, w.status)
+		}
+	}
+	if !strings.Contains(output.Blocks[2].Output, "undefinedHelper") {
+		t.Errorf("failed block output doesn't name the error: %q", output.Blocks[2].Output)
+	}
+}
+
+func TestWrapGoSnippet(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want string
+	}{
+		{"complete file", "package lib\n\nvar X = 1", "package lib\n\nvar X = 1"},
+		{"declarations", "func double(n int) int { return n * 2 }", "package main\n\nfunc double(n int) int { return n * 2 }\n\nfunc main() {}\n"},
+		{"declarations with main", "func main() {}", "package main\n\nfunc main() {}\n"},
+		{"statements", "n := 1\n_ = n", "package main\n\nfunc main() {\nn := 1\n_ = n\n}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapGoSnippet(tt.code); got != tt.want {
+				t.Errorf("wrapGoSnippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractFencedBlocks(t *testing.T) {
+	content := "~~~~Go\nfmt.Println(1)\n~~~\nstill code\n~~~~\n\n```\nplain\n```\n"
+	blocks := extractFencedBlocks(content)
+
+	if len(blocks) != 2 {
+		t.Fatalf("got %d blocks, want 2: %+v", len(blocks), blocks)
+	}
+	if blocks[0].language != "go" || blocks[0].code != "fmt.Println(1)\n~~~\nstill code" {
+		t.Errorf("a shorter fence must not close the block: %+v", blocks[0])
+	}
+	if blocks[1].language != "" || blocks[1].line != 7 || blocks[1].code != "plain" {
+		t.Errorf("unexpected untagged block: %+v", blocks[1])
+	}
+}
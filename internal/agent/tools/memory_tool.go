@@ -0,0 +1,175 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryToolDefinition defines the memory tool
+var MemoryToolDefinition = ToolDefinition{
+	Name: "memory",
+	Description: `A key-value scratchpad for recording plans, decisions, file lists, and TODOs.
+Use 'set' to store a value under a key, 'get' to read it back, 'list' to see stored keys
+(optionally filtered by 'prefix'), and 'delete' to remove a key. Values survive for the whole
+session, and across sessions when a memory file is configured. Keys such as 'plan' or
+'todo/auth' keep related notes easy to find with a prefix.`,
+	InputSchema:           MemoryInputSchema,
+	Function:              Memory,
+	CountsTowardLoopLimit: true,
+}
+
+// MemoryInput defines the input parameters for the memory tool
+type MemoryInput struct {
+	Operation string `json:"operation" jsonschema_required:"true" jsonschema_description:"Operation to perform: 'set', 'get', 'list', or 'delete'" jsonschema_example:"set"`
+	Key       string `json:"key,omitempty" jsonschema_description:"Key to set, get, or delete"`
+	Value     string `json:"value,omitempty" jsonschema_description:"Value to store with 'set'"`
+	Prefix    string `json:"prefix,omitempty" jsonschema_description:"Only list keys starting with this prefix"`
+}
+
+// MemoryInputSchema is the JSON schema for the memory tool
+var MemoryInputSchema = GenerateSchema[MemoryInput]()
+
+// MemoryEntry is a stored value and when it was last written
+type MemoryEntry struct {
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MemoryOutput represents the structured output of the memory tool
+type MemoryOutput struct {
+	Operation string                 `json:"operation"`
+	Key       string                 `json:"key,omitempty"`
+	Found     bool                   `json:"found,omitempty"`
+	Entry     *MemoryEntry           `json:"entry,omitempty"`
+	Entries   map[string]MemoryEntry `json:"entries,omitempty"`
+	Count     int                    `json:"count"`
+	Persisted bool                   `json:"persisted"`
+}
+
+var (
+	memoryEntries = map[string]MemoryEntry{}
+	memoryFile    string
+	memoryMutex   sync.Mutex
+)
+
+// LoadMemory persists the memory store to path, first loading any entries already saved there.
+// A missing file starts an empty store that is created on the first write.
+func LoadMemory(path string) error {
+	memoryMutex.Lock()
+	defer memoryMutex.Unlock()
+
+	entries := map[string]MemoryEntry{}
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read memory file '%s': %w", path, err)
+	}
+	if err == nil && len(strings.TrimSpace(string(content))) > 0 {
+		if err := json.Unmarshal(content, &entries); err != nil {
+			return fmt.Errorf("failed to parse memory file '%s': %w", path, err)
+		}
+	}
+
+	memoryEntries = entries
+	memoryFile = path
+	return nil
+}
+
+// saveMemory writes the store to the memory file, if one is configured.
+// The caller must hold memoryMutex.
+func saveMemory() error {
+	if memoryFile == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(memoryEntries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode memory: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated store
+	if err := os.MkdirAll(filepath.Dir(memoryFile), 0755); err != nil {
+		return fmt.Errorf("failed to create memory directory: %w", err)
+	}
+	tmp := memoryFile + ".tmp"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+	if err := os.Rename(tmp, memoryFile); err != nil {
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+	return nil
+}
+
+// Memory implements the memory tool functionality
+func Memory(ctx context.Context, input json.RawMessage) (string, error) {
+	memoryInput := MemoryInput{}
+	err := json.Unmarshal(input, &memoryInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	operation := strings.ToLower(memoryInput.Operation)
+	if operation != "list" && memoryInput.Key == "" {
+		return "", fmt.Errorf("key parameter is required for '%s'", memoryInput.Operation)
+	}
+
+	memoryMutex.Lock()
+	defer memoryMutex.Unlock()
+
+	output := MemoryOutput{
+		Operation: operation,
+		Key:       memoryInput.Key,
+		Persisted: memoryFile != "",
+	}
+
+	switch operation {
+	case "set":
+		entry := MemoryEntry{Value: memoryInput.Value, UpdatedAt: time.Now().UTC()}
+		memoryEntries[memoryInput.Key] = entry
+		if err := saveMemory(); err != nil {
+			return "", err
+		}
+		output.Found = true
+		output.Entry = &entry
+
+	case "get":
+		if entry, ok := memoryEntries[memoryInput.Key]; ok {
+			output.Found = true
+			output.Entry = &entry
+		}
+
+	case "delete":
+		if _, ok := memoryEntries[memoryInput.Key]; ok {
+			delete(memoryEntries, memoryInput.Key)
+			if err := saveMemory(); err != nil {
+				return "", err
+			}
+			output.Found = true
+		}
+
+	case "list":
+		output.Entries = map[string]MemoryEntry{}
+		for key, entry := range memoryEntries {
+			if strings.HasPrefix(key, memoryInput.Prefix) {
+				output.Entries[key] = entry
+			}
+		}
+
+	default:
+		return "", fmt.Errorf("invalid operation: %s. Must be 'set', 'get', 'list', or 'delete'", memoryInput.Operation)
+	}
+	output.Count = len(memoryEntries)
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
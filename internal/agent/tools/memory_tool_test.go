@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// resetMemory gives the test an empty, unpersisted memory store
+func resetMemory(t *testing.T) {
+	t.Helper()
+	memoryMutex.Lock()
+	memoryEntries, memoryFile = map[string]MemoryEntry{}, ""
+	memoryMutex.Unlock()
+	t.Cleanup(func() {
+		memoryMutex.Lock()
+		memoryEntries, memoryFile = map[string]MemoryEntry{}, ""
+		memoryMutex.Unlock()
+	})
+}
+
+// callMemory runs the memory tool with input and decodes a fresh output
+func callMemory(t *testing.T, ctx context.Context, input MemoryInput) MemoryOutput {
+	t.Helper()
+	var output MemoryOutput
+	callTool(t, ctx, Memory, input, &output)
+	return output
+}
+
+func TestMemoryRoundTrip(t *testing.T) {
+	resetMemory(t)
+	ctx, _ := newTestWorkspace(t)
+
+	callMemory(t, ctx, MemoryInput{Operation: "set", Key: "plan", Value: "split the parser"})
+	output := callMemory(t, ctx, MemoryInput{Operation: "set", Key: "todo/auth", Value: "add tests"})
+	if output.Count != 2 || output.Persisted {
+		t.Errorf("after set got %+v", output)
+	}
+
+	output = callMemory(t, ctx, MemoryInput{Operation: "get", Key: "plan"})
+	if !output.Found || output.Entry == nil || output.Entry.Value != "split the parser" {
+		t.Errorf("get plan = %+v", output)
+	}
+
+	output = callMemory(t, ctx, MemoryInput{Operation: "list", Prefix: "todo/"})
+	if len(output.Entries) != 1 || output.Entries["todo/auth"].Value != "add tests" {
+		t.Errorf("list todo/ = %+v", output.Entries)
+	}
+
+	output = callMemory(t, ctx, MemoryInput{Operation: "delete", Key: "plan"})
+	if !output.Found || output.Count != 1 {
+		t.Errorf("delete = %+v", output)
+	}
+	output = callMemory(t, ctx, MemoryInput{Operation: "get", Key: "plan"})
+	if output.Found || output.Entry != nil {
+		t.Errorf("deleted key is still found: %+v", output)
+	}
+}
+
+func TestMemoryPersistsAcrossReload(t *testing.T) {
+	resetMemory(t)
+	ctx, dir := newTestWorkspace(t)
+	path := filepath.Join(dir, "state", "memory.json")
+
+	if err := LoadMemory(path); err != nil {
+		t.Fatalf("loading a missing file should start an empty store: %v", err)
+	}
+	output := callMemory(t, ctx, MemoryInput{Operation: "set", Key: "files", Value: "a.go,b.go"})
+	if !output.Persisted {
+		t.Error("set should report the store as persisted")
+	}
+
+	// Simulate a new session: drop the in-memory state and load the file again
+	resetMemory(t)
+	if err := LoadMemory(path); err != nil {
+		t.Fatal(err)
+	}
+	output = callMemory(t, ctx, MemoryInput{Operation: "get", Key: "files"})
+	if !output.Found || output.Entry.Value != "a.go,b.go" {
+		t.Errorf("value didn't survive the reload: %+v", output)
+	}
+}
+
+func TestLoadMemoryRejectsCorruptFile(t *testing.T) {
+	resetMemory(t)
+	_, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "memory.json", "{not json")
+
+	if err := LoadMemory(path); err == nil {
+		t.Error("expected an error for a corrupt memory file")
+	}
+}
+
+func TestMemoryConcurrentSets(t *testing.T) {
+	resetMemory(t)
+	ctx, _ := newTestWorkspace(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := Memory(ctx, mustMarshal(t, MemoryInput{Operation: "set", Key: string(rune('a' + i)), Value: "x"})); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	output := callMemory(t, ctx, MemoryInput{Operation: "list"})
+	if output.Count != 20 {
+		t.Errorf("count = %d, want 20", output.Count)
+	}
+}
+
+func TestMemoryErrors(t *testing.T) {
+	resetMemory(t)
+	ctx, _ := newTestWorkspace(t)
+
+	if _, err := Memory(ctx, mustMarshal(t, MemoryInput{Operation: "get"})); err == nil {
+		t.Error("expected an error for get without a key")
+	}
+	if _, err := Memory(ctx, mustMarshal(t, MemoryInput{Operation: "clear", Key: "a"})); err == nil {
+		t.Error("expected an error for an unknown operation")
+	}
+}
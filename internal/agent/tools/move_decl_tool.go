@@ -0,0 +1,470 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MoveDeclToolDefinition defines the move_decl tool
+var MoveDeclToolDefinition = ToolDefinition{
+	Name: "move_decl",
+	Description: `Move a top-level declaration from one Go file to another file of the same package, e.g. to
+split a large file. 'name' is a function, type, variable or constant, or 'Type.Method' for a method;
+set 'include_methods' to move a type together with its methods in the source file. The declaration
+is cut with its doc comment and appended to the target, which is created if needed. Imports it uses
+are added to the target and removed from the source when nothing else there uses them. Both files
+are gofmt'ed and must still parse. Set 'dry_run' to get the diffs without writing the files.`,
+	InputSchema:           MoveDeclInputSchema,
+	Function:              MoveDecl,
+	CountsTowardLoopLimit: true,
+}
+
+// MoveDeclInput defines the input parameters for the move_decl tool
+type MoveDeclInput struct {
+	Path           string `json:"path" jsonschema_required:"true" jsonschema_description:"Go file containing the declaration"`
+	Name           string `json:"name" jsonschema_required:"true" jsonschema_description:"Name of the declaration, or Type.Method for a method" jsonschema_example:"parseConfig"`
+	Target         string `json:"target" jsonschema_required:"true" jsonschema_description:"Go file in the same directory to move the declaration to" jsonschema_example:"config.go"`
+	IncludeMethods bool   `json:"include_methods,omitempty" jsonschema_description:"If true and name is a type, also move its methods declared in the source file"`
+	DryRun         bool   `json:"dry_run,omitempty" jsonschema_description:"If true, return the diffs without writing the files"`
+}
+
+// MoveDeclInputSchema is the JSON schema for the move_decl tool
+var MoveDeclInputSchema = GenerateSchema[MoveDeclInput]()
+
+// MoveDeclOutput represents the structured output of the move_decl tool
+type MoveDeclOutput struct {
+	Source         string   `json:"source"`
+	Target         string   `json:"target"`
+	Moved          []string `json:"moved"`
+	CreatedTarget  bool     `json:"created_target,omitempty"`
+	ImportsAdded   []string `json:"imports_added,omitempty"`
+	ImportsRemoved []string `json:"imports_removed,omitempty"`
+	DryRun         bool     `json:"dry_run"`
+	SourceDiff     string   `json:"source_diff"`
+	TargetDiff     string   `json:"target_diff"`
+}
+
+// movedDecl is the source text of a declaration being moved, and the byte range it is cut from
+type movedDecl struct {
+	name  string
+	node  ast.Node
+	text  string
+	start int
+	end   int
+}
+
+// MoveDecl implements the move_decl tool functionality
+func MoveDecl(ctx context.Context, input json.RawMessage) (string, error) {
+	moveInput := MoveDeclInput{}
+	err := DecodeInput(input, &moveInput)
+	if err != nil {
+		return "", err
+	}
+
+	if moveInput.Path == "" || moveInput.Name == "" || moveInput.Target == "" {
+		return "", fmt.Errorf("path, name and target parameters are required")
+	}
+	sourcePath, err := ResolvePath(ctx, moveInput.Path)
+	if err != nil {
+		return "", err
+	}
+	targetPath, err := ResolvePath(ctx, moveInput.Target)
+	if err != nil {
+		return "", err
+	}
+	if filepath.Ext(targetPath) != ".go" {
+		return "", fmt.Errorf("%s is not a Go file", moveInput.Target)
+	}
+	if filepath.Clean(sourcePath) == filepath.Clean(targetPath) {
+		return "", fmt.Errorf("source and target are the same file")
+	}
+	if filepath.Dir(filepath.Clean(sourcePath)) != filepath.Dir(filepath.Clean(targetPath)) {
+		return "", fmt.Errorf("target must be in the same directory as %s to stay in its package", moveInput.Path)
+	}
+
+	source, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", sourcePath, err)
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, sourcePath, source, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", sourcePath, err)
+	}
+
+	decls, err := findMovedDecls(fset, file, source, moveInput.Name, moveInput.IncludeMethods)
+	if err != nil {
+		return "", err
+	}
+
+	// Imports the moved code refers to, by local name
+	imports := map[string]string{}
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		name := assumedPackageName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name != "_" && name != "." {
+			imports[name] = importPath
+		}
+	}
+	needed := map[string]string{}
+	for _, decl := range decls {
+		for name := range packageQualifiers(decl.node) {
+			if importPath, found := imports[name]; found {
+				needed[name] = importPath
+			}
+		}
+	}
+
+	// Cut the declarations from the source, last first so earlier offsets stay valid
+	newSource := append([]byte{}, source...)
+	var texts []string
+	for i := len(decls) - 1; i >= 0; i-- {
+		newSource = append(newSource[:decls[i].start:decls[i].start], newSource[decls[i].end:]...)
+	}
+	output := MoveDeclOutput{Source: sourcePath, Target: targetPath, DryRun: moveInput.DryRun, Moved: []string{}}
+	for _, decl := range decls {
+		texts = append(texts, decl.text)
+		output.Moved = append(output.Moved, decl.name)
+	}
+
+	// Drop the imports only the moved code used
+	remaining, err := parser.ParseFile(token.NewFileSet(), sourcePath, newSource, parser.ParseComments)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s after removing %s: %w", sourcePath, moveInput.Name, err)
+	}
+	stillUsed := packageQualifiers(remaining)
+	unused := map[string]bool{}
+	for name, importPath := range needed {
+		if !stillUsed[name] {
+			unused[importPath] = true
+			output.ImportsRemoved = append(output.ImportsRemoved, importPath)
+		}
+	}
+	sort.Strings(output.ImportsRemoved)
+	newSource, err = removeImportSpecs(newSource, unused)
+	if err != nil {
+		return "", fmt.Errorf("failed to update imports of %s: %w", sourcePath, err)
+	}
+
+	// Append the declarations to the target, creating it if needed
+	target, err := os.ReadFile(targetPath)
+	if os.IsNotExist(err) {
+		target = nil
+		output.CreatedTarget = true
+	} else if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", targetPath, err)
+	}
+	newTarget := []byte("package " + file.Name.Name + "\n")
+	present := map[string]bool{}
+	if !output.CreatedTarget {
+		targetFile, err := parser.ParseFile(token.NewFileSet(), targetPath, target, parser.ImportsOnly)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", targetPath, err)
+		}
+		if targetFile.Name.Name != file.Name.Name {
+			return "", fmt.Errorf("%s is in package %s, not %s", targetPath, targetFile.Name.Name, file.Name.Name)
+		}
+		for _, spec := range targetFile.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			present[importPath] = true
+		}
+		newTarget = append([]byte{}, target...)
+	}
+	var added []string
+	for name, importPath := range needed {
+		if present[importPath] {
+			continue
+		}
+		output.ImportsAdded = append(output.ImportsAdded, importPath)
+		if name != assumedPackageName(importPath) {
+			added = append(added, name+" "+strconv.Quote(importPath))
+		} else {
+			added = append(added, strconv.Quote(importPath))
+		}
+	}
+	sort.Strings(output.ImportsAdded)
+	sort.Strings(added)
+	newTarget, err = addImportSpecs(newTarget, added)
+	if err != nil {
+		return "", fmt.Errorf("failed to update imports of %s: %w", targetPath, err)
+	}
+	newTarget = append(bytes.TrimRight(newTarget, "\n"), []byte("\n\n"+strings.Join(texts, "\n\n")+"\n")...)
+
+	// Both files must still be valid Go
+	formattedSource, err := format.Source(newSource)
+	if err != nil {
+		return "", fmt.Errorf("%s would not parse after the move: %w", sourcePath, err)
+	}
+	formattedTarget, err := format.Source(newTarget)
+	if err != nil {
+		return "", fmt.Errorf("%s would not parse after the move: %w", targetPath, err)
+	}
+
+	output.SourceDiff = unifiedDiff(moveInput.Path, string(source), string(formattedSource))
+	output.TargetDiff = unifiedDiff(moveInput.Target, string(target), string(formattedTarget))
+	if !moveInput.DryRun {
+		info, err := os.Stat(sourcePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to stat %s: %w", sourcePath, err)
+		}
+		if err := os.WriteFile(targetPath, formattedTarget, info.Mode().Perm()); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", targetPath, err)
+		}
+		if err := os.WriteFile(sourcePath, formattedSource, info.Mode().Perm()); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", sourcePath, err)
+		}
+		recordReadHash(targetPath, formattedTarget)
+		recordReadHash(sourcePath, formattedSource)
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// findMovedDecls locates the declaration called name in file, and with includeMethods the
+// methods of a type, in source order. A spec declared in a parenthesized group is moved out of
+// the group on its own, except for constants, whose values may depend on their position.
+func findMovedDecls(fset *token.FileSet, file *ast.File, source []byte, name string, includeMethods bool) ([]movedDecl, error) {
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+	typeName, methodName, isMethod := strings.Cut(name, ".")
+
+	// extent returns the range of node with its doc comment and any comment trailing it on the same line
+	extent := func(doc *ast.CommentGroup, node ast.Node) (int, int) {
+		start, end := node.Pos(), node.End()
+		if doc != nil {
+			start = doc.Pos()
+		}
+		endLine := fset.Position(end).Line
+		for _, group := range file.Comments {
+			if group.Pos() >= end && fset.Position(group.Pos()).Line == endLine {
+				end = group.End()
+			}
+		}
+		return offset(start), offset(end)
+	}
+
+	var decls []movedDecl
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			receiver := receiverTypeName(decl)
+			matches := (!isMethod && receiver == "" && decl.Name.Name == name) ||
+				(isMethod && receiver == typeName && decl.Name.Name == methodName) ||
+				(!isMethod && includeMethods && receiver == name)
+			if !matches {
+				continue
+			}
+			start, end := extent(decl.Doc, decl)
+			label := decl.Name.Name
+			if receiver != "" {
+				label = receiver + "." + label
+			}
+			decls = append(decls, movedDecl{name: label, node: decl, text: string(source[start:end]), start: start, end: end})
+
+		case *ast.GenDecl:
+			if isMethod || decl.Tok == token.IMPORT {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				var names []*ast.Ident
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					names = []*ast.Ident{spec.Name}
+				case *ast.ValueSpec:
+					names = spec.Names
+				}
+				found := false
+				for _, ident := range names {
+					found = found || ident.Name == name
+				}
+				if !found {
+					continue
+				}
+				if len(names) > 1 {
+					return nil, fmt.Errorf("%s is declared together with other names on line %d; split the declaration first", name, fset.Position(spec.Pos()).Line)
+				}
+
+				if !decl.Lparen.IsValid() || len(decl.Specs) == 1 {
+					start, end := extent(decl.Doc, decl)
+					decls = append(decls, movedDecl{name: name, node: decl, text: string(source[start:end]), start: start, end: end})
+					continue
+				}
+				if decl.Tok == token.CONST {
+					return nil, fmt.Errorf("constant %s is part of a const group on line %d, where its value may depend on its position; move the whole group with file_editor", name, fset.Position(decl.Pos()).Line)
+				}
+
+				var doc *ast.CommentGroup
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					doc = spec.Doc
+				case *ast.ValueSpec:
+					doc = spec.Doc
+				}
+				start, end := extent(doc, spec)
+				text := decl.Tok.String() + " " + string(source[offset(spec.Pos()):end])
+				if doc != nil {
+					text = string(source[start:offset(spec.Pos())]) + text
+				}
+				decls = append(decls, movedDecl{name: name, node: spec, text: text, start: start, end: end})
+			}
+		}
+	}
+
+	if len(decls) == 0 {
+		return nil, fmt.Errorf("declaration %s not found", name)
+	}
+	if isMethod && len(decls) > 1 {
+		return nil, fmt.Errorf("method %s is declared more than once", name)
+	}
+
+	// Cut whole lines, so no indentation or blank remains where a declaration was
+	for i := range decls {
+		for decls[i].start > 0 && (source[decls[i].start-1] == ' ' || source[decls[i].start-1] == '\t') {
+			decls[i].start--
+		}
+		if decls[i].end < len(source) && source[decls[i].end] == '\n' {
+			decls[i].end++
+		}
+	}
+	return decls, nil
+}
+
+// packageQualifiers returns the names used as package qualifiers (the x in x.Name) in node.
+// Identifiers bound to a local object are variables, not packages.
+func packageQualifiers(node ast.Node) map[string]bool {
+	names := map[string]bool{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Obj == nil {
+				names[ident.Name] = true
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// addImportSpecs adds import specs, such as "strings" or yaml "gopkg.in/yaml.v3", to the first
+// import declaration of src, or a new one after the package clause
+func addImportSpecs(src []byte, specs []string) ([]byte, error) {
+	if len(specs) == 0 {
+		return src, nil
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+	lines := "\t" + strings.Join(specs, "\n\t") + "\n"
+
+	var decl *ast.GenDecl
+	for _, d := range file.Decls {
+		if genDecl, ok := d.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			decl = genDecl
+			break
+		}
+	}
+
+	var result []byte
+	switch {
+	case decl == nil:
+		at := offset(file.Name.End())
+		result = append(result, src[:at]...)
+		result = append(result, "\n\nimport (\n"+lines+")"...)
+		result = append(result, src[at:]...)
+	case decl.Lparen.IsValid():
+		at := offset(decl.Rparen)
+		before := bytes.TrimRight(src[:at], " \t")
+		if len(before) > 0 && before[len(before)-1] != '\n' {
+			lines = "\n" + lines
+		}
+		result = append(result, src[:at]...)
+		result = append(result, lines...)
+		result = append(result, src[at:]...)
+	default:
+		existing := string(src[offset(decl.Specs[0].Pos()):offset(decl.Specs[0].End())])
+		result = append(result, src[:offset(decl.Pos())]...)
+		result = append(result, "import (\n\t"+existing+"\n"+lines+")"...)
+		result = append(result, src[offset(decl.End()):]...)
+	}
+	return result, nil
+}
+
+// removeImportSpecs removes the imports of the given paths from src, dropping import
+// declarations that become empty
+func removeImportSpecs(src []byte, paths map[string]bool) ([]byte, error) {
+	if len(paths) == 0 {
+		return src, nil
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+
+	type cut struct{ start, end int }
+	var cuts []cut
+	for _, d := range file.Decls {
+		decl, ok := d.(*ast.GenDecl)
+		if !ok || decl.Tok != token.IMPORT {
+			continue
+		}
+		var removed []cut
+		for _, spec := range decl.Specs {
+			importSpec := spec.(*ast.ImportSpec)
+			importPath, _ := strconv.Unquote(importSpec.Path.Value)
+			if !paths[importPath] {
+				continue
+			}
+			start, end := importSpec.Pos(), importSpec.End()
+			if importSpec.Doc != nil {
+				start = importSpec.Doc.Pos()
+			}
+			if importSpec.Comment != nil {
+				end = importSpec.Comment.End()
+			}
+			removed = append(removed, cut{offset(start), offset(end)})
+		}
+		if len(removed) == len(decl.Specs) {
+			start := decl.Pos()
+			if decl.Doc != nil {
+				start = decl.Doc.Pos()
+			}
+			removed = []cut{{offset(start), offset(decl.End())}}
+		}
+		cuts = append(cuts, removed...)
+	}
+
+	result := append([]byte{}, src...)
+	for i := len(cuts) - 1; i >= 0; i-- {
+		start, end := cuts[i].start, cuts[i].end
+		for start > 0 && (result[start-1] == ' ' || result[start-1] == '\t') {
+			start--
+		}
+		if end < len(result) && result[end] == '\n' {
+			end++
+		}
+		result = append(result[:start:start], result[end:]...)
+	}
+	return result, nil
+}
@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const moveDeclSource = `package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Config holds the settings
+type Config struct {
+	Name string
+}
+
+// Describe renders the config
+func (c Config) Describe() string {
+	return fmt.Sprintf("config %s", c.Name)
+}
+
+// parseConfig reads a config from a file
+func parseConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	return Config{Name: strings.TrimSpace(string(data))}, nil
+}
+
+func main() {
+	cfg, err := parseConfig("app.conf")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(cfg.Describe())
+}
+`
+
+// buildTestPackage runs go build in dir and fails the test if it doesn't compile
+func buildTestPackage(t *testing.T, dir string) {
+	t.Helper()
+	cmd := exec.Command("go", "build", "./...")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("package doesn't compile: %v\n%s", err, output)
+	}
+}
+
+func TestMoveDeclMovesFunctionWithImports(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+	sourcePath := writeTestFile(t, dir, "main.go", strings.Replace(moveDeclSource, "package app", "package main", 1))
+
+	var output MoveDeclOutput
+	callTool(t, ctx, MoveDecl, MoveDeclInput{Path: "main.go", Name: "parseConfig", Target: "config.go"}, &output)
+
+	if !output.CreatedTarget || !reflect.DeepEqual(output.Moved, []string{"parseConfig"}) {
+		t.Errorf("got %+v, want parseConfig moved to a new file", output)
+	}
+	if !reflect.DeepEqual(output.ImportsAdded, []string{"os", "strings"}) || !reflect.DeepEqual(output.ImportsRemoved, []string{"os", "strings"}) {
+		t.Errorf("imports added %v, removed %v", output.ImportsAdded, output.ImportsRemoved)
+	}
+
+	source := readTestFile(t, sourcePath)
+	target := readTestFile(t, filepath.Join(dir, "config.go"))
+	if strings.Contains(source, "parseConfig reads") || strings.Contains(source, `"os"`) || !strings.Contains(source, `"fmt"`) {
+		t.Errorf("source after the move:\n%s", source)
+	}
+	if !strings.HasPrefix(target, "package main\n") || !strings.Contains(target, "// parseConfig reads a config from a file\nfunc parseConfig") {
+		t.Errorf("target after the move:\n%s", target)
+	}
+	buildTestPackage(t, dir)
+}
+
+func TestMoveDeclTypeWithMethods(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+	writeTestFile(t, dir, "app.go", moveDeclSource)
+	writeTestFile(t, dir, "types.go", "package app\n\nimport \"fmt\"\n\nvar _ = fmt.Sprint\n")
+
+	var output MoveDeclOutput
+	callTool(t, ctx, MoveDecl, MoveDeclInput{Path: "app.go", Name: "Config", Target: "types.go", IncludeMethods: true}, &output)
+
+	if !reflect.DeepEqual(output.Moved, []string{"Config", "Config.Describe"}) || output.CreatedTarget {
+		t.Errorf("got %+v, want the type and its method moved to the existing file", output)
+	}
+	// fmt is still used in both files, and was already imported by the target
+	if len(output.ImportsAdded) != 0 || len(output.ImportsRemoved) != 0 {
+		t.Errorf("imports added %v, removed %v", output.ImportsAdded, output.ImportsRemoved)
+	}
+	buildTestPackage(t, dir)
+}
+
+func TestMoveDeclDryRunAndErrors(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "app.go", moveDeclSource)
+	writeTestFile(t, dir, "other.go", "package other\n")
+
+	var output MoveDeclOutput
+	callTool(t, ctx, MoveDecl, MoveDeclInput{Path: "app.go", Name: "Config.Describe", Target: "describe.go", DryRun: true}, &output)
+	if output.SourceDiff == "" || output.TargetDiff == "" || readTestFile(t, path) != moveDeclSource {
+		t.Errorf("got %+v, want diffs without writing", output)
+	}
+
+	inputs := []MoveDeclInput{
+		{Path: "app.go", Name: "missing", Target: "x.go"},
+		{Path: "app.go", Name: "main", Target: "app.go"},
+		{Path: "app.go", Name: "main", Target: "sub/x.go"},
+		{Path: "app.go", Name: "main", Target: "x.txt"},
+		{Path: "app.go", Name: "main", Target: "other.go"},
+	}
+	for _, input := range inputs {
+		if _, err := MoveDecl(ctx, mustMarshal(t, input)); err == nil {
+			t.Errorf("%+v: expected an error", input)
+		}
+	}
+}
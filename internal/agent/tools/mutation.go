@@ -0,0 +1,197 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// mutatingTools lists tools that always modify the workspace or repository
+var mutatingTools = map[string]bool{
+	"download_file":     true,
+	"commit_series":     true,
+	"file_editor":       true,
+	"file_operations":   true,
+	"gen_examples":      true,
+	"gen_table_test":    true,
+	"go_rename":         true,
+	"json_array_append": true,
+	"new_project":       true,
+	"run_background":    true,
+	"run_until":         true,
+	"truncate_file":     true,
+	"yaml_edit":         true,
+}
+
+// readOnlyGitCommands lists git_operations commands that never modify the repository
+var readOnlyGitCommands = map[string]bool{
+	"status":        true,
+	"log":           true,
+	"show":          true,
+	"diff":          true,
+	"blame":         true,
+	"grep":          true,
+	"worktree_list": true,
+}
+
+// mutatingGoCommands lists go_command commands that rewrite files or the module
+var mutatingGoCommands = map[string]bool{
+	"fmt":        true,
+	"fix":        true,
+	"get":        true,
+	"generate":   true,
+	"install":    true,
+	"mod tidy":   true,
+	"mod init":   true,
+	"mod edit":   true,
+	"mod vendor": true,
+	"work":       true,
+}
+
+// IsMutatingToolCall reports whether invoking the named tool with input may modify
+// files, the repository, or remote state. Unparseable inputs are treated as mutating.
+func IsMutatingToolCall(name string, input json.RawMessage) bool {
+	if mutatingTools[name] {
+		return true
+	}
+
+	switch name {
+	case "git_operations":
+		gitInput := GitToolInput{}
+		if err := DecodeInput(input, &gitInput); err != nil {
+			return true
+		}
+		return !readOnlyGitCommands[strings.ToLower(gitInput.Command)]
+
+	case "go_command":
+		runGoInput := RunGoInput{}
+		if err := DecodeInput(input, &runGoInput); err != nil {
+			return true
+		}
+		return mutatingGoCommands[runGoInput.Command] || strings.HasPrefix(runGoInput.Command, "work ") || runGoInput.Output != ""
+
+	case "replace_in_repo":
+		replaceInput := RepoReplaceInput{}
+		if err := DecodeInput(input, &replaceInput); err != nil {
+			return true
+		}
+		return replaceInput.Apply
+
+	case "replace_in_func":
+		replaceInput := ReplaceInFuncInput{}
+		if err := DecodeInput(input, &replaceInput); err != nil {
+			return true
+		}
+		return !replaceInput.DryRun
+
+	case "go_implement":
+		implInput := GoImplementInput{}
+		if err := DecodeInput(input, &implInput); err != nil {
+			return true
+		}
+		return implInput.Insert
+
+	case "extract_interface":
+		extractInput := ExtractInterfaceInput{}
+		if err := DecodeInput(input, &extractInput); err != nil {
+			return true
+		}
+		return extractInput.OutputFile != ""
+
+	case "fix_imports":
+		fixInput := FixImportsInput{}
+		if err := DecodeInput(input, &fixInput); err != nil {
+			return true
+		}
+		return !fixInput.DryRun
+
+	case "format_changed":
+		formatInput := FormatChangedInput{}
+		if err := DecodeInput(input, &formatInput); err != nil {
+			return true
+		}
+		return !formatInput.DryRun
+
+	case "go_generate":
+		generateInput := GoGenerateInput{}
+		if err := DecodeInput(input, &generateInput); err != nil {
+			return true
+		}
+		return generateInput.Action == "run" && !generateInput.DryRun
+
+	case "move_decl":
+		moveInput := MoveDeclInput{}
+		if err := DecodeInput(input, &moveInput); err != nil {
+			return true
+		}
+		return !moveInput.DryRun
+
+	case "go_format":
+		formatInput := GoFormatInput{}
+		if err := DecodeInput(input, &formatInput); err != nil {
+			return true
+		}
+		return formatInput.Write
+
+	case "encoding":
+		encodingInput := EncodingInput{}
+		if err := DecodeInput(input, &encodingInput); err != nil {
+			return true
+		}
+		return encodingInput.Convert
+
+	case "memory":
+		// set and delete rewrite the memory file when one is configured
+		memoryInput := MemoryInput{}
+		if err := DecodeInput(input, &memoryInput); err != nil {
+			return true
+		}
+		return memoryInput.Operation == "set" || memoryInput.Operation == "delete"
+
+	case "gen_gitignore":
+		genInput := GenGitignoreInput{}
+		if err := DecodeInput(input, &genInput); err != nil {
+			return true
+		}
+		return !genInput.DryRun
+
+	case "tidy_preview":
+		tidyInput := TidyPreviewInput{}
+		if err := DecodeInput(input, &tidyInput); err != nil {
+			return true
+		}
+		return tidyInput.Apply
+
+	case "makefile_edit":
+		editInput := MakefileEditInput{}
+		if err := DecodeInput(input, &editInput); err != nil {
+			return true
+		}
+		return editInput.Target != "" || editInput.FixIndentation
+
+	case "make":
+		makeInput := MakeInput{}
+		if err := DecodeInput(input, &makeInput); err != nil {
+			return true
+		}
+		return makeInput.Target != "" && !makeInput.DryRun
+
+	case "chmod":
+		chmodInput := ChmodInput{}
+		if err := DecodeInput(input, &chmodInput); err != nil {
+			return true
+		}
+		return chmodInput.Mode != ""
+
+	case "macro":
+		return isMutatingMacroCall(input)
+
+	case "refactoring_workflow":
+		workflowInput := WorkflowInput{}
+		if err := DecodeInput(input, &workflowInput); err != nil {
+			return true
+		}
+		return workflowInput.Stage == "implement"
+	}
+
+	return false
+}
@@ -0,0 +1,36 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIsMutatingToolCall(t *testing.T) {
+	tests := []struct {
+		name  string
+		tool  string
+		input string
+		want  bool
+	}{
+		{"file editor", "file_editor", `{"path": "a.go", "mode": "append"}`, true},
+		{"file reader", "file_reader", `{"path": "a.go"}`, false},
+		{"git status", "git_operations", `{"command": "status"}`, false},
+		{"git commit", "git_operations", `{"command": "commit", "message": "x"}`, true},
+		{"git push", "git_operations", `{"command": "push"}`, true},
+		{"go vet", "go_command", `{"command": "vet", "path": "./..."}`, false},
+		{"go mod tidy", "go_command", `{"command": "mod tidy"}`, true},
+		{"go build with output", "go_command", `{"command": "build", "output": "bin/"}`, true},
+		{"replace preview", "replace_in_repo", `{"pattern": "a", "replacement": "b"}`, false},
+		{"replace apply", "replace_in_repo", `{"pattern": "a", "replacement": "b", "apply": true}`, true},
+		{"memory get", "memory", `{"operation": "get", "key": "k"}`, false},
+		{"memory set", "memory", `{"operation": "set", "key": "k", "value": "v"}`, true},
+		{"unparseable input", "go_command", `{"command": 1}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMutatingToolCall(tt.tool, json.RawMessage(tt.input)); got != tt.want {
+				t.Errorf("IsMutatingToolCall(%q, %s) = %v, want %v", tt.tool, tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// NewProjectToolDefinition defines the new_project tool
+var NewProjectToolDefinition = ToolDefinition{
+	Name: "new_project",
+	Description: `Initialize a new Go module with a minimal project skeleton in one step.
+Runs 'go mod init <module_path>' in the target directory and creates:
+- 'cli' template (default): main.go with a runnable main function
+- 'library' template: pkg/<name>/<name>.go with a package doc comment
+Both templates also get a .gitignore. Fails if the directory already contains a go.mod.`,
+	InputSchema:           NewProjectInputSchema,
+	Function:              NewProject,
+	CountsTowardLoopLimit: true,
+}
+
+// NewProjectInput defines the input parameters for the new_project tool
+type NewProjectInput struct {
+	ModulePath string `json:"module_path" jsonschema_required:"true" jsonschema_description:"Module path for go mod init, e.g. 'github.com/user/project'" jsonschema_example:"github.com/user/project"`
+	Template   string `json:"template,omitempty" jsonschema_description:"Project template: 'cli' (default) or 'library'"`
+	Directory  string `json:"directory,omitempty" jsonschema_description:"Directory to create the project in. Defaults to the current directory."`
+}
+
+// NewProjectInputSchema is the JSON schema for the new_project tool
+var NewProjectInputSchema = GenerateSchema[NewProjectInput]()
+
+// NewProjectOutput represents the structured output of the new_project tool
+type NewProjectOutput struct {
+	Success      bool     `json:"success"`
+	ModulePath   string   `json:"module_path"`
+	Template     string   `json:"template"`
+	Directory    string   `json:"directory"`
+	CreatedFiles []string `json:"created_files"`
+	ErrorMessage string   `json:"error_message,omitempty"`
+}
+
+// modulePathElementPattern matches a single valid element of a module path
+var modulePathElementPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._~-]*$`)
+
+// newProjectGitignore is the .gitignore written for every template
+const newProjectGitignore = `# Binaries
+*.exe
+*.dll
+*.so
+*.dylib
+/bin/
+
+# Test output
+*.test
+*.out
+coverage.*
+
+# Editors and OS files
+.idea/
+.vscode/
+.DS_Store
+`
+
+// newProjectMainTemplate is the main.go written for the 'cli' template
+const newProjectMainTemplate = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("Hello from %s")
+}
+`
+
+// newProjectLibraryTemplate is the package file written for the 'library' template
+const newProjectLibraryTemplate = `// Package %s provides the core functionality of %s.
+package %s
+
+// Hello returns a greeting for name
+func Hello(name string) string {
+	return "Hello, " + name
+}
+`
+
+// projectFile is a file created by the new_project tool, relative to the project directory
+type projectFile struct {
+	path    string
+	content string
+}
+
+// NewProject implements the new_project tool functionality
+func NewProject(ctx context.Context, input json.RawMessage) (string, error) {
+	projectInput := NewProjectInput{}
+	err := json.Unmarshal(input, &projectInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if err := validateModulePath(projectInput.ModulePath); err != nil {
+		return "", err
+	}
+
+	template := projectInput.Template
+	if template == "" {
+		template = "cli"
+	}
+	if template != "cli" && template != "library" {
+		return "", fmt.Errorf("invalid template: %s. Must be 'cli' or 'library'", template)
+	}
+
+	dir := workspaceDir(ctx)
+	if projectInput.Directory != "" {
+		dir, err = ResolvePath(ctx, projectInput.Directory)
+		if err != nil {
+			return "", err
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+		return "", fmt.Errorf("%s already contains a go.mod", dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	output := NewProjectOutput{
+		ModulePath:   projectInput.ModulePath,
+		Template:     template,
+		Directory:    dir,
+		CreatedFiles: []string{},
+	}
+
+	result, err := RunGoCommand(ctx, "mod init", "", []string{projectInput.ModulePath}, dir)
+	if err != nil {
+		return "", err
+	}
+	if !result.Success {
+		output.ErrorMessage = strings.TrimSpace(result.Stderr)
+		if output.ErrorMessage == "" {
+			output.ErrorMessage = result.ErrorMessage
+		}
+		return marshalNewProjectOutput(output)
+	}
+	output.CreatedFiles = append(output.CreatedFiles, "go.mod")
+
+	name := projectPackageName(projectInput.ModulePath)
+	files := []projectFile{{path: ".gitignore", content: newProjectGitignore}}
+	if template == "cli" {
+		files = append(files, projectFile{
+			path:    "main.go",
+			content: fmt.Sprintf(newProjectMainTemplate, name),
+		})
+	} else {
+		files = append(files, projectFile{
+			path:    filepath.Join("pkg", name, name+".go"),
+			content: fmt.Sprintf(newProjectLibraryTemplate, name, projectInput.ModulePath, name),
+		})
+	}
+
+	for _, file := range files {
+		rel := file.path
+		path, err := SafeJoin(dir, rel)
+		if err != nil {
+			return "", err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", fmt.Errorf("failed to create directory for %s: %w", rel, err)
+		}
+		if err := os.WriteFile(path, []byte(file.content), 0644); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", rel, err)
+		}
+		output.CreatedFiles = append(output.CreatedFiles, filepath.ToSlash(rel))
+	}
+
+	output.Success = true
+	return marshalNewProjectOutput(output)
+}
+
+// marshalNewProjectOutput renders the new_project output as indented JSON
+func marshalNewProjectOutput(output NewProjectOutput) (string, error) {
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+	return string(jsonOutput), nil
+}
+
+// validateModulePath checks that path is a plausible Go module path
+func validateModulePath(path string) error {
+	if path == "" {
+		return fmt.Errorf("module_path parameter is required")
+	}
+	if strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") {
+		return fmt.Errorf("invalid module path %q: must not begin or end with '/'", path)
+	}
+	for _, element := range strings.Split(path, "/") {
+		if element == "" {
+			return fmt.Errorf("invalid module path %q: contains an empty path element", path)
+		}
+		if !modulePathElementPattern.MatchString(element) || strings.HasSuffix(element, ".") {
+			return fmt.Errorf("invalid module path %q: invalid path element %q", path, element)
+		}
+	}
+	return nil
+}
+
+// projectPackageName derives a Go package name from the last element of a module path
+func projectPackageName(modulePath string) string {
+	base := modulePath[strings.LastIndex(modulePath, "/")+1:]
+	// Drop a major version suffix such as /v2
+	if len(base) > 1 && base[0] == 'v' && strings.Trim(base[1:], "0123456789") == "" {
+		if i := strings.LastIndex(modulePath, "/"); i > 0 {
+			return projectPackageName(modulePath[:i])
+		}
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(base) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "project" + name
+	}
+	return name
+}
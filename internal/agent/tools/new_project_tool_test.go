@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewProjectCompiles(t *testing.T) {
+	tests := []struct {
+		template string
+		files    []string
+	}{
+		{"cli", []string{"go.mod", ".gitignore", "main.go"}},
+		{"library", []string{"go.mod", ".gitignore", "pkg/widget/widget.go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			ctx, dir := newTestWorkspace(t)
+
+			var output NewProjectOutput
+			callTool(t, ctx, NewProject, NewProjectInput{ModulePath: "example.com/acme/widget", Template: tt.template, Directory: "widget"}, &output)
+			if !output.Success {
+				t.Fatalf("new_project failed: %s", output.ErrorMessage)
+			}
+			if !reflect.DeepEqual(output.CreatedFiles, tt.files) {
+				t.Errorf("created files = %v, want %v", output.CreatedFiles, tt.files)
+			}
+
+			projectDir := filepath.Join(dir, "widget")
+			for _, file := range tt.files {
+				if _, err := os.Stat(filepath.Join(projectDir, file)); err != nil {
+					t.Errorf("expected %s to exist: %v", file, err)
+				}
+			}
+			if goMod := readTestFile(t, filepath.Join(projectDir, "go.mod")); !strings.HasPrefix(goMod, "module example.com/acme/widget\n") {
+				t.Errorf("unexpected go.mod:\n%s", goMod)
+			}
+
+			result, err := RunGoCommand(ctx, "vet", "./...", nil, "widget")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.Success {
+				t.Errorf("generated project doesn't build: %s", result.Stderr)
+			}
+		})
+	}
+}
+
+func TestNewProjectRefusesExistingModule(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/existing")
+
+	_, err := NewProject(ctx, mustMarshal(t, NewProjectInput{ModulePath: "example.com/other"}))
+	if err == nil || !strings.Contains(err.Error(), "already contains a go.mod") {
+		t.Errorf("expected an existing go.mod to be refused, got %v", err)
+	}
+}
+
+func TestValidateModulePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"github.com/user/project", false},
+		{"example.com/tool/v2", false},
+		{"local", false},
+		{"", true},
+		{"/abs/path", true},
+		{"trailing/", true},
+		{"double//slash", true},
+		{"has space/x", true},
+		{"dot./x", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if err := validateModulePath(tt.path); (err != nil) != tt.wantErr {
+				t.Errorf("validateModulePath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProjectPackageName(t *testing.T) {
+	tests := map[string]string{
+		"github.com/user/my-project": "myproject",
+		"example.com/tool/v2":        "tool",
+		"Widget":                     "widget",
+	}
+	for modulePath, want := range tests {
+		if got := projectPackageName(modulePath); got != want {
+			t.Errorf("projectPackageName(%q) = %q, want %q", modulePath, got, want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// workspaceRootKey is the context key under which the workspace root is stored
+type workspaceRootKey struct{}
+
+// ResolveWorkspaceRoot validates dir as a workspace root and returns its absolute path
+func ResolveWorkspaceRoot(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve working directory %s: %w", dir, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", fmt.Errorf("invalid working directory: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("working directory %s is not a directory", dir)
+	}
+	return abs, nil
+}
+
+// WithWorkspaceRoot returns a copy of ctx whose tool calls resolve relative paths and run
+// commands in root, a directory returned by ResolveWorkspaceRoot, instead of the process's
+// current directory. An empty root keeps the current directory.
+func WithWorkspaceRoot(ctx context.Context, root string) context.Context {
+	return context.WithValue(ctx, workspaceRootKey{}, root)
+}
+
+// workspaceDir returns the workspace root stored in ctx, or "." for the current directory.
+// Tools use it as their default directory and as the directory commands run in.
+func workspaceDir(ctx context.Context) string {
+	if root, _ := ctx.Value(workspaceRootKey{}).(string); root != "" {
+		return root
+	}
+	return "."
+}
+
+// ResolvePath normalizes a tool-supplied path so every file-touching tool treats it the same way.
+// Both '/' and '\' are accepted as separators, the path is cleaned, and relative paths are
+// resolved against the workspace root (the current directory unless ctx carries WithWorkspaceRoot).
+// Paths that escape the workspace root, lexically or through a symlink, are rejected unless they
+// lie inside a workspace created by the temp_workspace tool. Relative inputs stay relative to the current directory root, and are
+// joined onto a configured root; absolute inputs are returned cleaned.
+func ResolvePath(ctx context.Context, p string) (string, error) {
+	root := workspaceDir(ctx)
+	if root == "." {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine workspace root: %w", err)
+		}
+		return resolvePathIn(cwd, p)
+	}
+
+	resolved, err := resolvePathIn(root, p)
+	if err != nil || filepath.IsAbs(resolved) {
+		return resolved, err
+	}
+	return filepath.Join(root, resolved), nil
+}
+
+// SafeJoin joins elems onto root and rejects the result if it would lie outside root, either
+// lexically or by following a symlink
+func SafeJoin(root string, elems ...string) (string, error) {
+	joined := filepath.Join(append([]string{root}, normalizeSeparators(elems)...)...)
+	if !withinDir(root, joined) || !withinDirResolved(root, joined) {
+		return "", fmt.Errorf("path %s escapes %s", filepath.Join(elems...), root)
+	}
+	return joined, nil
+}
+
+// resolvePathIn implements ResolvePath against an explicit workspace root
+func resolvePathIn(root, p string) (string, error) {
+	if p == "" {
+		return "", fmt.Errorf("path cannot be empty")
+	}
+
+	cleaned := filepath.Clean(normalizeSeparators([]string{p})[0])
+	abs := cleaned
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(root, cleaned)
+	}
+
+	if !withinDirResolved(root, abs) && !inTempWorkspace(abs) {
+		return "", fmt.Errorf("path %s is outside the workspace root %s", p, root)
+	}
+	return cleaned, nil
+}
+
+// normalizeSeparators converts both '/' and '\' to the OS path separator
+func normalizeSeparators(paths []string) []string {
+	normalized := make([]string, len(paths))
+	for i, p := range paths {
+		normalized[i] = filepath.FromSlash(strings.ReplaceAll(p, `\`, "/"))
+	}
+	return normalized
+}
+
+// withinDir reports whether path is dir itself or lies beneath it
+func withinDir(dir, path string) bool {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	rel, err := filepath.Rel(absDir, absPath)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// withinDirResolved is withinDir after resolving symlinks in both paths, so a link inside dir
+// that points elsewhere doesn't count as inside it
+func withinDirResolved(dir, path string) bool {
+	realDir, err := resolveSymlinks(dir)
+	if err != nil {
+		return false
+	}
+	realPath, err := resolveSymlinks(path)
+	if err != nil {
+		return false
+	}
+	return withinDir(realDir, realPath)
+}
+
+// maxSymlinkHops bounds how many dangling links resolveSymlinks follows
+const maxSymlinkHops = 40
+
+// resolveSymlinks returns the absolute form of path with the symlinks in its longest existing
+// prefix resolved, so a path that doesn't exist yet is judged by where it would be created.
+// A dangling link is followed to its target, since writing through it creates the target.
+func resolveSymlinks(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	existing, rest := abs, ""
+	for hops := 0; ; {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		if info, lerr := os.Lstat(existing); lerr == nil && info.Mode()&os.ModeSymlink != 0 {
+			if hops++; hops > maxSymlinkHops {
+				return "", fmt.Errorf("too many links resolving %s", path)
+			}
+			target, err := os.Readlink(existing)
+			if err != nil {
+				return "", err
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(existing), target)
+			}
+			existing = filepath.Clean(target)
+			continue
+		}
+
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return filepath.Join(existing, rest), nil
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+}
+
+// inTempWorkspace reports whether path lies inside a workspace created by temp_workspace
+func inTempWorkspace(path string) bool {
+	for _, dir := range listTempWorkspaces() {
+		if withinDirResolved(dir, path) {
+			return true
+		}
+	}
+	return false
+}
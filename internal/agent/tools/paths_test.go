@@ -0,0 +1,198 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolvePathSeparators(t *testing.T) {
+	root := t.TempDir()
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"posix", "internal/agent/agent.go", filepath.Join("internal", "agent", "agent.go"), false},
+		{"windows", `internal\agent\agent.go`, filepath.Join("internal", "agent", "agent.go"), false},
+		{"mixed", `internal/agent\agent.go`, filepath.Join("internal", "agent", "agent.go"), false},
+		{"dot segments", `./internal/../internal\agent/./agent.go`, filepath.Join("internal", "agent", "agent.go"), false},
+		{"trailing separator", `internal\agent\`, filepath.Join("internal", "agent"), false},
+		{"root itself", ".", ".", false},
+		{"absolute inside root", filepath.Join(root, "main.go"), filepath.Join(root, "main.go"), false},
+		{"posix escape", "../secrets.txt", "", true},
+		{"windows escape", `..\..\secrets.txt`, "", true},
+		{"hidden escape", `internal/../../secrets.txt`, "", true},
+		{"absolute outside root", filepath.Join(filepath.Dir(root), "other", "main.go"), "", true},
+		{"empty", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolvePathIn(root, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolvePathIn(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolvePathIn(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePathDefaultsToCurrentDirectory(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	posix, err := ResolvePath(context.Background(), "pkg/file.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	windows, err := ResolvePath(context.Background(), `pkg\file.go`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if posix != windows || posix != filepath.Join("pkg", "file.go") {
+		t.Errorf("got %q and %q, want both to be %q", posix, windows, filepath.Join("pkg", "file.go"))
+	}
+	if _, err := ResolvePath(context.Background(), "../outside.go"); err == nil {
+		t.Error("expected a path above the current directory to be rejected")
+	}
+}
+
+func TestSafeJoin(t *testing.T) {
+	root := t.TempDir()
+
+	tests := []struct {
+		name    string
+		elems   []string
+		want    string
+		wantErr bool
+	}{
+		{"nested", []string{"pkg", "name.go"}, filepath.Join(root, "pkg", "name.go"), false},
+		{"windows separators", []string{`pkg\sub\name.go`}, filepath.Join(root, "pkg", "sub", "name.go"), false},
+		{"escape", []string{"..", "name.go"}, "", true},
+		{"windows escape", []string{`..\name.go`}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SafeJoin(root, tt.elems...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SafeJoin(%q) error = %v, wantErr %v", tt.elems, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("SafeJoin(%q) = %q, want %q", tt.elems, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePathAgainstWorkspaceRoot(t *testing.T) {
+	t.Chdir(t.TempDir())
+	ctx, root := newTestWorkspace(t)
+
+	got, err := ResolvePath(ctx, `pkg\file.go`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, "pkg", "file.go"); got != want {
+		t.Errorf("ResolvePath = %q, want %q joined onto the configured root", got, want)
+	}
+	if _, err := ResolvePath(ctx, "../outside.go"); err == nil {
+		t.Error("expected a path above the configured root to be rejected")
+	}
+	if workspaceDir(context.Background()) != "." || workspaceDir(ctx) != root {
+		t.Errorf("workspaceDir = %q and %q, want . and %q", workspaceDir(context.Background()), workspaceDir(ctx), root)
+	}
+}
+
+func TestResolveWorkspaceRoot(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	file := writeTestFile(t, dir, "file.txt", "")
+	if err := os.Mkdir("sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := ResolveWorkspaceRoot("sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !filepath.IsAbs(root) || filepath.Base(root) != "sub" {
+		t.Errorf("root = %q, want an absolute path to sub", root)
+	}
+	if _, err := ResolveWorkspaceRoot("missing"); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+	if _, err := ResolveWorkspaceRoot(file); err == nil {
+		t.Error("expected an error for a file")
+	}
+}
+
+func TestFileToolsUseWorkspaceRoot(t *testing.T) {
+	t.Chdir(t.TempDir())
+	ctx, root := newTestWorkspace(t)
+	writeTestFile(t, root, "docs/readme.md", "inside the workspace\n")
+
+	content, err := ReadFileContent(ctx, mustMarshal(t, FileReaderInput{Path: "docs/readme.md"}))
+	if err != nil || !strings.Contains(content, "inside the workspace") {
+		t.Errorf("read = %q, %v, want the file below the configured root", content, err)
+	}
+
+	listing, err := ListDirectoryContents(ctx, mustMarshal(t, ListDirectoryContentsInput{}))
+	if err != nil || !strings.Contains(listing, "readme.md") {
+		t.Errorf("list = %q, %v, want the configured root listed", listing, err)
+	}
+
+	if _, err := EditFileContent(ctx, mustMarshal(t, FileEditorInput{Path: "notes.txt", Mode: "create", Content: "hello\n"})); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, filepath.Join(root, "notes.txt")); got != "hello\n" {
+		t.Errorf("created file = %q", got)
+	}
+	if _, err := os.Stat("notes.txt"); !os.IsNotExist(err) {
+		t.Error("the file was created in the process's current directory")
+	}
+}
+
+func TestResolvePathSymlinks(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeTestFile(t, outside, "secret.txt", "secret")
+	writeTestFile(t, root, "pkg/file.go", "package pkg\n")
+	for link, target := range map[string]string{
+		"out":      outside,
+		"dangling": filepath.Join(outside, "new.txt"),
+		"inner":    filepath.Join(root, "pkg"),
+	} {
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Skipf("symlinks unsupported: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"link to outside directory", "out/secret.txt", true},
+		{"new file below outside link", "out/sub/new.txt", true},
+		{"dangling link to outside", "dangling", true},
+		{"link within root", "inner/file.go", false},
+		{"new file below inner link", "inner/new.go", false},
+		{"plain new file", "pkg/sub/new.go", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := resolvePathIn(root, tt.path); (err != nil) != tt.wantErr {
+				t.Errorf("resolvePathIn(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if _, err := SafeJoin(root, tt.path); (err != nil) != tt.wantErr {
+				t.Errorf("SafeJoin(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PlanCheckToolDefinition defines the plan_check tool
+var PlanCheckToolDefinition = ToolDefinition{
+	Name: "plan_check",
+	Description: `Check a plan of next actions for patterns that usually mean the agent is looping.
+Pass the actions you intend to take, in order, each with an action and a target such as a file path
+or a command. The plan is compared with itself and with the history recorded by action_limiter,
+and the tool flags repeated edits to the same target, edits that alternate between two targets,
+commands re-run without any change in between, and plans that would exceed the action limits.
+Call it before a long sequence of edits; if it reports risks, rethink the approach first.`,
+	InputSchema: PlanCheckInputSchema,
+	Function:    PlanCheck,
+}
+
+// PlannedAction is one step of a proposed plan
+type PlannedAction struct {
+	Action string `json:"action" jsonschema_required:"true" jsonschema_description:"The action to perform (e.g., 'edit_file', 'run_command')"`
+	Target string `json:"target,omitempty" jsonschema_description:"The target of the action (e.g., file path or command)"`
+}
+
+// PlanCheckInput defines the input parameters for the plan_check tool
+type PlanCheckInput struct {
+	Actions []PlannedAction `json:"actions" jsonschema_required:"true" jsonschema_description:"The planned actions, in the order they will be taken"`
+}
+
+// PlanCheckInputSchema is the JSON schema for the plan_check tool
+var PlanCheckInputSchema = GenerateSchema[PlanCheckInput]()
+
+// PlanIssue is a loop risk found in a plan
+type PlanIssue struct {
+	Kind    string `json:"kind"`
+	Steps   []int  `json:"steps,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Message string `json:"message"`
+}
+
+// PlanCheckOutput represents the structured output of the plan_check tool
+type PlanCheckOutput struct {
+	LoopRisk bool        `json:"loop_risk"`
+	Issues   []PlanIssue `json:"issues"`
+	Advice   []string    `json:"advice"`
+}
+
+// planRepeatThreshold is how often one action may target the same thing in a plan before it is flagged
+const planRepeatThreshold = 3
+
+// PlanCheck implements the plan_check tool functionality
+func PlanCheck(ctx context.Context, input json.RawMessage) (string, error) {
+	planInput := PlanCheckInput{}
+	err := DecodeInput(input, &planInput)
+	if err != nil {
+		return "", err
+	}
+
+	if len(planInput.Actions) == 0 {
+		return "", fmt.Errorf("actions parameter is required")
+	}
+
+	output := checkPlan(planInput.Actions, actionHistory())
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// actionHistory returns a copy of the action_limiter stats
+func actionHistory() ActionStats {
+	statsMutex.Lock()
+	defer statsMutex.Unlock()
+
+	history := ActionStats{
+		TotalActions:    stats.TotalActions,
+		ActionsByTarget: map[string]int{},
+		ConsecutiveSame: stats.ConsecutiveSame,
+		LastAction:      stats.LastAction,
+		LastTarget:      stats.LastTarget,
+	}
+	for target, count := range stats.ActionsByTarget {
+		history.ActionsByTarget[target] = count
+	}
+	return history
+}
+
+// checkPlan flags loop patterns in plan, taking the actions already recorded in history into account.
+// Steps are reported 1-based, in plan order.
+func checkPlan(plan []PlannedAction, history ActionStats) PlanCheckOutput {
+	output := PlanCheckOutput{Issues: []PlanIssue{}, Advice: []string{}}
+	advice := map[string]bool{}
+	addIssue := func(issue PlanIssue, hint string) {
+		output.Issues = append(output.Issues, issue)
+		if !advice[hint] {
+			advice[hint] = true
+			output.Advice = append(output.Advice, hint)
+		}
+	}
+
+	// The same action on the same target, several times in one plan
+	steps := map[PlannedAction][]int{}
+	var order []PlannedAction
+	for i, action := range plan {
+		if _, seen := steps[action]; !seen {
+			order = append(order, action)
+		}
+		steps[action] = append(steps[action], i+1)
+	}
+	for _, action := range order {
+		if len(steps[action]) >= planRepeatThreshold && isEditAction(action.Action) {
+			addIssue(PlanIssue{
+				Kind:    "repeated_edit",
+				Steps:   steps[action],
+				Target:  action.Target,
+				Message: fmt.Sprintf("%s on %s is planned %d times", action.Action, action.Target, len(steps[action])),
+			}, "Make all the changes to a file in one edit instead of revisiting it step by step.")
+		}
+	}
+
+	// Edits that bounce between two targets: A, B, A, B
+	for i := 3; i < len(plan); i++ {
+		a, b := plan[i-3], plan[i-2]
+		if a.Target != b.Target && plan[i-1] == a && plan[i] == b && isEditAction(a.Action) && isEditAction(b.Action) {
+			addIssue(PlanIssue{
+				Kind:    "alternating_edits",
+				Steps:   []int{i - 2, i - 1, i, i + 1},
+				Target:  a.Target + ", " + b.Target,
+				Message: fmt.Sprintf("edits alternate between %s and %s", a.Target, b.Target),
+			}, "Alternating edits often undo each other; decide on the final state of both targets before editing.")
+			i += 2
+		}
+	}
+
+	// A command re-run with nothing changed in between, including the last recorded action
+	previous := PlannedAction{Action: history.LastAction, Target: history.LastTarget}
+	previousStep := 0
+	for i, action := range plan {
+		if isRunAction(action.Action) && action == previous {
+			issue := PlanIssue{
+				Kind:    "rerun_without_change",
+				Steps:   []int{i + 1},
+				Target:  action.Target,
+				Message: fmt.Sprintf("%s is run again without any change since the previous run", action.Target),
+			}
+			if previousStep > 0 {
+				issue.Steps = []int{previousStep, i + 1}
+			} else {
+				issue.Message = fmt.Sprintf("%s was the last action taken and is run again without any change", action.Target)
+			}
+			addIssue(issue, "Running the same command again without a change gives the same result; fix the cause of the failure first.")
+		}
+		previous, previousStep = action, i+1
+	}
+
+	// Limits that the plan would exceed, counting the recorded history
+	if total := history.TotalActions + len(plan); total >= maxSessionActions {
+		addIssue(PlanIssue{
+			Kind:    "session_limit",
+			Message: fmt.Sprintf("the plan brings the session to %d actions; the limit is %d", total, maxSessionActions),
+		}, "Split the work into smaller steps and check in with the user.")
+	}
+	targetCounts := map[string]int{}
+	for _, action := range plan {
+		targetCounts[action.Target]++
+	}
+	for _, action := range order {
+		if count := history.ActionsByTarget[action.Target] + targetCounts[action.Target]; count >= maxTargetActions && targetCounts[action.Target] > 0 {
+			addIssue(PlanIssue{
+				Kind:    "target_limit",
+				Target:  action.Target,
+				Message: fmt.Sprintf("%s would reach %d actions; the limit is %d", action.Target, count, maxTargetActions),
+			}, "A target touched this often is a sign of trial and error; step back and reconsider the approach.")
+			targetCounts[action.Target] = 0
+		}
+	}
+	consecutive, flagged := 0, false
+	if len(plan) > 0 && plan[0].Action == history.LastAction && plan[0].Target == history.LastTarget {
+		consecutive = history.ConsecutiveSame
+	}
+	for i, action := range plan {
+		if i > 0 && action != plan[i-1] {
+			consecutive, flagged = 0, false
+		}
+		consecutive++
+		if consecutive >= maxConsecutiveActions && !flagged {
+			flagged = true
+			addIssue(PlanIssue{
+				Kind:    "consecutive_limit",
+				Steps:   []int{i + 1},
+				Target:  action.Target,
+				Message: fmt.Sprintf("%s on %s would be repeated %d times in a row", action.Action, action.Target, consecutive),
+			}, "Repeating an identical action will not change its outcome; try something different.")
+		}
+	}
+
+	output.LoopRisk = len(output.Issues) > 0
+	return output
+}
+
+// isEditAction reports whether action names a file change
+func isEditAction(action string) bool {
+	action = strings.ToLower(action)
+	for _, verb := range []string{"edit", "write", "modify", "replace", "create", "update", "patch", "file_editor"} {
+		if strings.Contains(action, verb) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRunAction reports whether action names running a command, build, or test
+func isRunAction(action string) bool {
+	action = strings.ToLower(action)
+	for _, verb := range []string{"run", "exec", "build", "test", "command", "go_command"} {
+		if strings.Contains(action, verb) {
+			return true
+		}
+	}
+	return false
+}
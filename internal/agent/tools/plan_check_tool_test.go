@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+// planIssueKinds returns the kinds of the issues found, in order
+func planIssueKinds(output PlanCheckOutput) []string {
+	kinds := []string{}
+	for _, issue := range output.Issues {
+		kinds = append(kinds, issue.Kind)
+	}
+	return kinds
+}
+
+func TestCheckPlanFlagsRepeatedEditTarget(t *testing.T) {
+	plan := []PlannedAction{
+		{Action: "edit_file", Target: "parser.go"},
+		{Action: "run_command", Target: "go build ./..."},
+		{Action: "edit_file", Target: "parser.go"},
+		{Action: "run_command", Target: "go test ./..."},
+		{Action: "edit_file", Target: "parser.go"},
+	}
+
+	output := checkPlan(plan, ActionStats{ActionsByTarget: map[string]int{}})
+	if !output.LoopRisk || len(output.Issues) != 1 {
+		t.Fatalf("got %+v, want a single repeated edit issue", output)
+	}
+	issue := output.Issues[0]
+	if issue.Kind != "repeated_edit" || issue.Target != "parser.go" || !reflect.DeepEqual(issue.Steps, []int{1, 3, 5}) {
+		t.Errorf("issue = %+v", issue)
+	}
+	if len(output.Advice) != 1 {
+		t.Errorf("advice = %v, want one hint", output.Advice)
+	}
+}
+
+func TestCheckPlanPatterns(t *testing.T) {
+	edit := func(target string) PlannedAction { return PlannedAction{Action: "edit_file", Target: target} }
+	run := func(target string) PlannedAction { return PlannedAction{Action: "run_command", Target: target} }
+
+	tests := []struct {
+		name    string
+		plan    []PlannedAction
+		history ActionStats
+		want    []string
+	}{
+		{
+			name: "clean plan",
+			plan: []PlannedAction{edit("a.go"), run("go test"), edit("b.go"), run("go test")},
+			want: []string{},
+		},
+		{
+			name: "alternating edits",
+			plan: []PlannedAction{edit("a.go"), edit("b.go"), edit("a.go"), edit("b.go")},
+			want: []string{"alternating_edits"},
+		},
+		{
+			name: "rerun in plan",
+			plan: []PlannedAction{run("go test"), run("go test")},
+			want: []string{"rerun_without_change"},
+		},
+		{
+			name:    "rerun of the last recorded action",
+			plan:    []PlannedAction{run("go test")},
+			history: ActionStats{LastAction: "run_command", LastTarget: "go test", ConsecutiveSame: 1},
+			want:    []string{"rerun_without_change"},
+		},
+		{
+			name:    "session limit",
+			plan:    []PlannedAction{edit("a.go"), run("go test")},
+			history: ActionStats{TotalActions: maxSessionActions - 1},
+			want:    []string{"session_limit"},
+		},
+		{
+			name:    "target limit",
+			plan:    []PlannedAction{edit("a.go")},
+			history: ActionStats{ActionsByTarget: map[string]int{"a.go": maxTargetActions - 1}},
+			want:    []string{"target_limit"},
+		},
+		{
+			name:    "consecutive limit",
+			plan:    []PlannedAction{{Action: "read_file", Target: "a.go"}, {Action: "read_file", Target: "a.go"}},
+			history: ActionStats{LastAction: "read_file", LastTarget: "a.go", ConsecutiveSame: maxConsecutiveActions - 2},
+			want:    []string{"consecutive_limit"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := checkPlan(tt.plan, tt.history)
+			if got := planIssueKinds(output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %v, want %v (%+v)", got, tt.want, output.Issues)
+			}
+			if output.LoopRisk != (len(tt.want) > 0) {
+				t.Errorf("loop risk = %v", output.LoopRisk)
+			}
+		})
+	}
+}
+
+func TestPlanCheckRequiresActions(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	if _, err := PlanCheck(ctx, mustMarshal(t, PlanCheckInput{})); err == nil {
+		t.Error("expected an error for an empty plan")
+	}
+}
@@ -0,0 +1,332 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/google/pprof/profile"
+)
+
+// PprofSummaryToolDefinition defines the pprof_summary tool
+var PprofSummaryToolDefinition = ToolDefinition{
+	Name: "pprof_summary",
+	Description: `Profile Go benchmarks and summarize the hotspots.
+Runs 'go test -run ^$ -bench <pattern>' on one package with -cpuprofile and/or -memprofile, then
+returns the top functions of each profile by flat value (time or allocations in the function itself)
+or, with 'sort_by' 'cum', by cumulative value (including its callees), with their share of the total.
+The memory profile reports 'alloc_space' by default; set 'sample_type' to alloc_objects, inuse_space
+or inuse_objects instead. Set 'profile_file' to summarize an existing profile without running anything.`,
+	InputSchema:           PprofSummaryInputSchema,
+	Function:              PprofSummary,
+	CountsTowardLoopLimit: true,
+}
+
+// PprofSummaryInput defines the input parameters for the pprof_summary tool
+type PprofSummaryInput struct {
+	Pattern     string `json:"pattern,omitempty" jsonschema_description:"Regular expression selecting benchmarks to run. Defaults to '.' (all)."`
+	Path        string `json:"path,omitempty" jsonschema_description:"Package to benchmark; profiling supports a single package. Defaults to '.'."`
+	Profile     string `json:"profile,omitempty" jsonschema_description:"Profile to collect: 'cpu' (default), 'mem' or 'both'"`
+	Benchtime   string `json:"benchtime,omitempty" jsonschema_description:"Run time or iteration count per benchmark (-benchtime)" jsonschema_example:"2s"`
+	Top         int    `json:"top,omitempty" jsonschema_description:"Number of functions to report per profile. Defaults to 10."`
+	SortBy      string `json:"sort_by,omitempty" jsonschema_description:"Sort order: 'flat' (default) or 'cum'"`
+	SampleType  string `json:"sample_type,omitempty" jsonschema_description:"Sample type to summarize, e.g. alloc_space or inuse_objects for memory profiles"`
+	ProfileFile string `json:"profile_file,omitempty" jsonschema_description:"Existing profile to summarize instead of running benchmarks"`
+	WorkingDir  string `json:"working_dir,omitempty" jsonschema_description:"Working directory (defaults to current directory if empty)"`
+}
+
+// PprofSummaryInputSchema is the JSON schema for the pprof_summary tool
+var PprofSummaryInputSchema = GenerateSchema[PprofSummaryInput]()
+
+// ProfileFunction is one function's share of a profile
+type ProfileFunction struct {
+	Function    string  `json:"function"`
+	File        string  `json:"file,omitempty"`
+	Flat        int64   `json:"flat"`
+	FlatPercent float64 `json:"flat_percent"`
+	Cum         int64   `json:"cum"`
+	CumPercent  float64 `json:"cum_percent"`
+	Display     string  `json:"display"`
+}
+
+// ProfileSummary is the hotspot summary of one profile
+type ProfileSummary struct {
+	Profile    string            `json:"profile"`
+	SampleType string            `json:"sample_type"`
+	Unit       string            `json:"unit"`
+	Total      int64             `json:"total"`
+	Display    string            `json:"display"`
+	Top        []ProfileFunction `json:"top"`
+}
+
+// PprofSummaryOutput represents the structured output of the pprof_summary tool
+type PprofSummaryOutput struct {
+	Success      bool              `json:"success"`
+	Command      string            `json:"command,omitempty"`
+	Benchmarks   []BenchmarkResult `json:"benchmarks,omitempty"`
+	Profiles     []ProfileSummary  `json:"profiles"`
+	Stderr       string            `json:"stderr,omitempty"`
+	ErrorMessage string            `json:"error_message,omitempty"`
+}
+
+// defaultProfileTop is how many functions are reported per profile by default
+const defaultProfileTop = 10
+
+// PprofSummary implements the pprof_summary tool functionality
+func PprofSummary(ctx context.Context, input json.RawMessage) (string, error) {
+	pprofInput := PprofSummaryInput{}
+	err := DecodeInput(input, &pprofInput)
+	if err != nil {
+		return "", err
+	}
+
+	top := pprofInput.Top
+	if top <= 0 {
+		top = defaultProfileTop
+	}
+	if pprofInput.SortBy != "" && pprofInput.SortBy != "flat" && pprofInput.SortBy != "cum" {
+		return "", fmt.Errorf("invalid sort_by: %s. Must be 'flat' or 'cum'", pprofInput.SortBy)
+	}
+
+	output := PprofSummaryOutput{Profiles: []ProfileSummary{}}
+	if pprofInput.ProfileFile != "" {
+		path, err := ResolvePath(ctx, pprofInput.ProfileFile)
+		if err != nil {
+			return "", err
+		}
+		summary, err := summarizeProfileFile(path, "", pprofInput.SampleType, top, pprofInput.SortBy)
+		if err != nil {
+			return "", err
+		}
+		output.Success = true
+		output.Profiles = append(output.Profiles, summary)
+		return marshalPprofSummaryOutput(output)
+	}
+
+	kinds := map[string][]string{"": {"cpu"}, "cpu": {"cpu"}, "mem": {"mem"}, "both": {"cpu", "mem"}}[pprofInput.Profile]
+	if kinds == nil {
+		return "", fmt.Errorf("invalid profile: %s. Must be 'cpu', 'mem' or 'both'", pprofInput.Profile)
+	}
+	pattern := pprofInput.Pattern
+	if pattern == "" {
+		pattern = "."
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", fmt.Errorf("invalid benchmark pattern: %w", err)
+	}
+	path := pprofInput.Path
+	if path == "" {
+		path = "."
+	}
+
+	// Keep the profiles and the test binary go test leaves behind out of the workspace
+	tmpDir, err := os.MkdirTemp("", "metamorph-pprof-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	args := []string{"-run", "^$", "-bench", pattern, "-o", filepath.Join(tmpDir, "bench.test")}
+	if pprofInput.Benchtime != "" {
+		args = append(args, "-benchtime", pprofInput.Benchtime)
+	}
+	for _, kind := range kinds {
+		args = append(args, "-"+kind+"profile", filepath.Join(tmpDir, kind+".pprof"))
+		if kind == "mem" {
+			args = append(args, "-benchmem")
+		}
+	}
+
+	result, err := RunGoCommand(ctx, "test", path, args, pprofInput.WorkingDir)
+	if err != nil {
+		return "", err
+	}
+	output.Success = result.Success
+	output.Command = result.Command
+	output.Benchmarks = parseBenchmarkOutput(result.Stdout)
+	output.Stderr = result.Stderr
+	output.ErrorMessage = result.ErrorMessage
+	if !result.Success {
+		return marshalPprofSummaryOutput(output)
+	}
+
+	for _, kind := range kinds {
+		sampleType := ""
+		if kind == "mem" {
+			sampleType = pprofInput.SampleType
+			if sampleType == "" {
+				sampleType = "alloc_space"
+			}
+		}
+		summary, err := summarizeProfileFile(filepath.Join(tmpDir, kind+".pprof"), kind, sampleType, top, pprofInput.SortBy)
+		if err != nil {
+			return "", err
+		}
+		output.Profiles = append(output.Profiles, summary)
+	}
+
+	return marshalPprofSummaryOutput(output)
+}
+
+// summarizeProfileFile parses the profile at path and summarizes its top functions
+func summarizeProfileFile(path, kind, sampleType string, top int, sortBy string) (ProfileSummary, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ProfileSummary{}, fmt.Errorf("failed to open profile: %w", err)
+	}
+	defer file.Close()
+
+	prof, err := profile.Parse(file)
+	if err != nil {
+		return ProfileSummary{}, fmt.Errorf("failed to parse profile %s: %w", path, err)
+	}
+	summary, err := summarizeProfile(prof, sampleType, top, sortBy)
+	if err != nil {
+		return ProfileSummary{}, err
+	}
+	if kind != "" {
+		summary.Profile = kind
+	}
+	return summary, nil
+}
+
+// summarizeProfile attributes each sample's value to the function it was taken in (flat) and to
+// every function on its stack (cum), and returns the top functions with their share of the total
+func summarizeProfile(prof *profile.Profile, sampleType string, top int, sortBy string) (ProfileSummary, error) {
+	if len(prof.SampleType) == 0 {
+		return ProfileSummary{}, fmt.Errorf("profile has no sample types")
+	}
+	// Without a requested type use the profile's default, or else its last, which for CPU
+	// profiles is the time rather than the sample count
+	index := len(prof.SampleType) - 1
+	if sampleType == "" {
+		sampleType = prof.DefaultSampleType
+	}
+	if sampleType != "" {
+		index = -1
+		var available []string
+		for i, st := range prof.SampleType {
+			available = append(available, st.Type)
+			if st.Type == sampleType {
+				index = i
+			}
+		}
+		if index < 0 {
+			return ProfileSummary{}, fmt.Errorf("sample type %s not in profile; available: %v", sampleType, available)
+		}
+	}
+	st := prof.SampleType[index]
+
+	summary := ProfileSummary{Profile: st.Type, SampleType: st.Type, Unit: st.Unit}
+	if prof.PeriodType != nil && prof.PeriodType.Type == "cpu" {
+		summary.Profile = "cpu"
+	}
+
+	type functionKey struct{ name, file string }
+	flat := map[functionKey]int64{}
+	cum := map[functionKey]int64{}
+	for _, sample := range prof.Sample {
+		value := sample.Value[index]
+		if value == 0 {
+			continue
+		}
+		summary.Total += value
+
+		seen := map[functionKey]bool{}
+		for i, location := range sample.Location {
+			// Lines are listed innermost first, so inlined calls precede their callers
+			for j, line := range location.Line {
+				key := functionKey{name: fmt.Sprintf("0x%x", location.Address)}
+				if line.Function != nil {
+					key = functionKey{name: line.Function.Name, file: line.Function.Filename}
+				}
+				if i == 0 && j == 0 {
+					flat[key] += value
+				}
+				if !seen[key] {
+					seen[key] = true
+					cum[key] += value
+				}
+			}
+		}
+	}
+
+	functions := []ProfileFunction{}
+	for key, cumValue := range cum {
+		functions = append(functions, ProfileFunction{
+			Function:    key.name,
+			File:        key.file,
+			Flat:        flat[key],
+			FlatPercent: profilePercent(flat[key], summary.Total),
+			Cum:         cumValue,
+			CumPercent:  profilePercent(cumValue, summary.Total),
+		})
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		a, b := functions[i], functions[j]
+		if sortBy == "cum" && a.Cum != b.Cum {
+			return a.Cum > b.Cum
+		}
+		if a.Flat != b.Flat {
+			return a.Flat > b.Flat
+		}
+		if a.Cum != b.Cum {
+			return a.Cum > b.Cum
+		}
+		return a.Function < b.Function
+	})
+	if len(functions) > top {
+		functions = functions[:top]
+	}
+	for i := range functions {
+		functions[i].Display = fmt.Sprintf("flat %s (%.1f%%), cum %s (%.1f%%)",
+			formatProfileValue(functions[i].Flat, st.Unit), functions[i].FlatPercent,
+			formatProfileValue(functions[i].Cum, st.Unit), functions[i].CumPercent)
+	}
+	summary.Top = functions
+	summary.Display = formatProfileValue(summary.Total, st.Unit)
+	return summary, nil
+}
+
+// profilePercent returns value as a percentage of total, rounded to one decimal
+func profilePercent(value, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(value*1000/total) / 10
+}
+
+// formatProfileValue renders a sample value in a readable form for its unit
+func formatProfileValue(value int64, unit string) string {
+	switch unit {
+	case "nanoseconds":
+		return time.Duration(value).Round(time.Microsecond).String()
+	case "bytes":
+		const unitSize = 1024
+		if value < unitSize {
+			return fmt.Sprintf("%dB", value)
+		}
+		size, suffix := float64(value), "KMGTPE"
+		for i := 0; i < len(suffix); i++ {
+			size /= unitSize
+			if size < unitSize || i == len(suffix)-1 {
+				return fmt.Sprintf("%.2f%cB", size, suffix[i])
+			}
+		}
+	}
+	return fmt.Sprintf("%d %s", value, unit)
+}
+
+// marshalPprofSummaryOutput renders the pprof_summary output as indented JSON
+func marshalPprofSummaryOutput(output PprofSummaryOutput) (string, error) {
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+	return string(jsonOutput), nil
+}
@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/pprof/profile"
+)
+
+// cannedCPUProfile builds a CPU profile in which main.work calls main.hash (600ms) and
+// main.encode (300ms), and main.main spends 100ms itself
+func cannedCPUProfile() *profile.Profile {
+	locations := map[string]*profile.Location{}
+	prof := &profile.Profile{
+		SampleType: []*profile.ValueType{{Type: "samples", Unit: "count"}, {Type: "cpu", Unit: "nanoseconds"}},
+		PeriodType: &profile.ValueType{Type: "cpu", Unit: "nanoseconds"},
+		Period:     10000000,
+	}
+	for i, name := range []string{"main.main", "main.work", "main.hash", "main.encode"} {
+		fn := &profile.Function{ID: uint64(i + 1), Name: name, Filename: "main.go"}
+		locations[name] = &profile.Location{ID: uint64(i + 1), Address: uint64(0x1000 * (i + 1)), Line: []profile.Line{{Function: fn, Line: int64(10 * (i + 1))}}}
+		prof.Function = append(prof.Function, fn)
+		prof.Location = append(prof.Location, locations[name])
+	}
+	stack := func(names ...string) []*profile.Location {
+		var stack []*profile.Location
+		for _, name := range names {
+			stack = append(stack, locations[name])
+		}
+		return stack
+	}
+	prof.Sample = []*profile.Sample{
+		{Location: stack("main.hash", "main.work", "main.main"), Value: []int64{60, 600000000}},
+		{Location: stack("main.encode", "main.work", "main.main"), Value: []int64{30, 300000000}},
+		{Location: stack("main.main"), Value: []int64{10, 100000000}},
+	}
+	return prof
+}
+
+func TestPprofSummaryCannedProfile(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	file, err := os.Create(filepath.Join(dir, "cpu.pprof"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cannedCPUProfile().Write(file); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	var output PprofSummaryOutput
+	callTool(t, ctx, PprofSummary, PprofSummaryInput{ProfileFile: "cpu.pprof", Top: 3}, &output)
+
+	if !output.Success || len(output.Profiles) != 1 {
+		t.Fatalf("got %+v, want one profile", output)
+	}
+	summary := output.Profiles[0]
+	if summary.Profile != "cpu" || summary.SampleType != "cpu" || summary.Total != 1000000000 || summary.Display != "1s" {
+		t.Errorf("got summary %+v, want 1s of cpu time", summary)
+	}
+	want := []struct {
+		function    string
+		flatPercent float64
+		cumPercent  float64
+	}{
+		{"main.hash", 60, 60},
+		{"main.encode", 30, 30},
+		{"main.main", 10, 100},
+	}
+	if len(summary.Top) != len(want) {
+		t.Fatalf("got %d functions, want %d: %+v", len(summary.Top), len(want), summary.Top)
+	}
+	for i, w := range want {
+		got := summary.Top[i]
+		if got.Function != w.function || got.FlatPercent != w.flatPercent || got.CumPercent != w.cumPercent {
+			t.Errorf("top[%d] = %+v, want %s with flat %.0f%% and cum %.0f%%", i, got, w.function, w.flatPercent, w.cumPercent)
+		}
+	}
+	if got := summary.Top[0].Display; got != "flat 600ms (60.0%), cum 600ms (60.0%)" {
+		t.Errorf("display = %q", got)
+	}
+}
+
+func TestSummarizeProfileSortByCum(t *testing.T) {
+	summary, err := summarizeProfile(cannedCPUProfile(), "", 2, "cum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.Top) != 2 || summary.Top[0].Function != "main.main" || summary.Top[1].Function != "main.work" {
+		t.Errorf("got %+v, want main.main then main.work", summary.Top)
+	}
+	if summary.Top[1].Flat != 0 || summary.Top[1].Cum != 900000000 {
+		t.Errorf("main.work = %+v, want no flat time and 900ms cum", summary.Top[1])
+	}
+
+	// The sample count can be chosen instead of the time
+	summary, err = summarizeProfile(cannedCPUProfile(), "samples", 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Total != 100 || summary.Top[0].Display != "flat 60 count (60.0%), cum 60 count (60.0%)" {
+		t.Errorf("got %+v", summary)
+	}
+
+	if _, err := summarizeProfile(cannedCPUProfile(), "alloc_space", 1, ""); err == nil {
+		t.Error("expected an error for a sample type not in the profile")
+	}
+}
+
+func TestFormatProfileValue(t *testing.T) {
+	tests := []struct {
+		value int64
+		unit  string
+		want  string
+	}{
+		{1500000, "nanoseconds", "1.5ms"},
+		{512, "bytes", "512B"},
+		{3 * 1024 * 1024, "bytes", "3.00MB"},
+		{42, "count", "42 count"},
+	}
+	for _, tt := range tests {
+		if got := formatProfileValue(tt.value, tt.unit); got != tt.want {
+			t.Errorf("formatProfileValue(%d, %s) = %q, want %q", tt.value, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestPprofSummaryRunsBenchmark(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/bench")
+	writeTestFile(t, dir, "bench_test.go", `package bench
+
+import (
+	"strings"
+	"testing"
+)
+
+var sink string
+
+func BenchmarkRepeat(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		sink = strings.Repeat("x", 1024)
+	}
+}
+`)
+
+	var output PprofSummaryOutput
+	callTool(t, ctx, PprofSummary, PprofSummaryInput{Profile: "both", Benchtime: "1000x", WorkingDir: dir}, &output)
+
+	if !output.Success {
+		t.Fatalf("benchmark failed: %s\n%s", output.ErrorMessage, output.Stderr)
+	}
+	if len(output.Benchmarks) != 1 || output.Benchmarks[0].Name != "BenchmarkRepeat" {
+		t.Errorf("got benchmarks %+v", output.Benchmarks)
+	}
+	if len(output.Profiles) != 2 || output.Profiles[0].Profile != "cpu" || output.Profiles[1].Profile != "mem" {
+		t.Fatalf("got profiles %+v, want cpu and mem", output.Profiles)
+	}
+	if mem := output.Profiles[1]; mem.SampleType != "alloc_space" || mem.Unit != "bytes" {
+		t.Errorf("got memory profile %+v, want alloc_space in bytes", mem)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("profiling left files in the workspace: %v", entries)
+	}
+}
+
+func TestPprofSummaryInvalidInput(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	for _, input := range []PprofSummaryInput{
+		{Profile: "block"},
+		{SortBy: "name"},
+		{Pattern: "("},
+		{ProfileFile: "missing.pprof"},
+	} {
+		if _, err := PprofSummary(ctx, mustMarshal(t, input)); err == nil {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+}
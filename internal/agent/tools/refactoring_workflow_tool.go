@@ -1,11 +1,22 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
 	"strings"
 )
 
+// Default size limits above which the 'long_functions' analysis flags a function
+const (
+	defaultMaxFunctionLines      = 50
+	defaultMaxFunctionStatements = 40
+)
+
 // WorkflowDefinition defines the workflow tool
 var RefactoringWorkflowToolDefinition = ToolDefinition{
 	Name: "refactoring_workflow",
@@ -16,17 +27,22 @@ This tool helps implement a disciplined approach to refactoring that prevents co
 - Making incompatible changes
 - Breaking the build
 
-It provides a structured workflow with checkpoints to ensure each change is validated before proceeding.`,
-	InputSchema: WorkflowInputSchema,
-	Function:    ExecuteWorkflow,
+It provides a structured workflow with checkpoints to ensure each change is validated before proceeding.
+The 'analyze' stage's 'long_functions' operation lists functions under 'path' longer than 'max_lines'
+lines or 'max_statements' statements, largest first, as refactoring candidates.`,
+	InputSchema:           WorkflowInputSchema,
+	Function:              ExecuteWorkflow,
+	CountsTowardLoopLimit: true,
 }
 
 // WorkflowInput defines the input parameters for the workflow tool
 type WorkflowInput struct {
-	Stage     string `json:"stage" jsonschema_description:"The current refactoring stage (analyze, plan, implement, test, verify)"`
-	Operation string `json:"operation,omitempty" jsonschema_description:"The specific operation to perform within the stage"`
-	Path      string `json:"path,omitempty" jsonschema_description:"The path to the file or directory for the operation"`
-	Details   string `json:"details,omitempty" jsonschema_description:"Additional details or content for the operation"`
+	Stage         string `json:"stage" jsonschema_required:"true" jsonschema_description:"The current refactoring stage (analyze, plan, implement, test, verify)"`
+	Operation     string `json:"operation,omitempty" jsonschema_description:"The specific operation to perform within the stage"`
+	Path          string `json:"path,omitempty" jsonschema_description:"The path to the file or directory for the operation"`
+	Details       string `json:"details,omitempty" jsonschema_description:"Additional details or content for the operation"`
+	MaxLines      int    `json:"max_lines,omitempty" jsonschema_description:"Line count above which 'long_functions' flags a function. Defaults to 50."`
+	MaxStatements int    `json:"max_statements,omitempty" jsonschema_description:"Statement count above which 'long_functions' flags a function. Defaults to 40."`
 }
 
 // WorkflowInputSchema is the JSON schema for the workflow tool
@@ -34,19 +50,29 @@ var WorkflowInputSchema = GenerateSchema[WorkflowInput]()
 
 // WorkflowOutput represents the structured output of the workflow tool
 type WorkflowOutput struct {
-	Stage       string `json:"stage"`
-	Status      string `json:"status"`
-	Message     string `json:"message"`
-	NextSteps   string `json:"next_steps,omitempty"`
-	BuildStatus bool   `json:"build_status,omitempty"`
+	Stage         string         `json:"stage"`
+	Status        string         `json:"status"`
+	Message       string         `json:"message"`
+	NextSteps     string         `json:"next_steps,omitempty"`
+	BuildStatus   bool           `json:"build_status,omitempty"`
+	LongFunctions []LongFunction `json:"long_functions,omitempty"`
+}
+
+// LongFunction describes a function that exceeds the configured size limits
+type LongFunction struct {
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Function   string `json:"function"`
+	Lines      int    `json:"lines"`
+	Statements int    `json:"statements"`
 }
 
 // ExecuteWorkflow implements the workflow tool functionality
-func ExecuteWorkflow(input json.RawMessage) (string, error) {
+func ExecuteWorkflow(ctx context.Context, input json.RawMessage) (string, error) {
 	workflowInput := WorkflowInput{}
-	err := json.Unmarshal(input, &workflowInput)
+	err := DecodeInput(input, &workflowInput)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse input: %w", err)
+		return "", err
 	}
 
 	// Validate input
@@ -60,15 +86,15 @@ func ExecuteWorkflow(input json.RawMessage) (string, error) {
 	// Execute the appropriate stage
 	switch workflowInput.Stage {
 	case "analyze":
-		output = executeAnalyzeStage(workflowInput)
+		output = executeAnalyzeStage(ctx, workflowInput)
 	case "plan":
-		output = executePlanStage(workflowInput)
+		output = executePlanStage(ctx, workflowInput)
 	case "implement":
-		output = executeImplementStage(workflowInput)
+		output = executeImplementStage(ctx, workflowInput)
 	case "test":
-		output = executeTestStage(workflowInput)
+		output = executeTestStage(ctx, workflowInput)
 	case "verify":
-		output = executeVerifyStage(workflowInput)
+		output = executeVerifyStage(ctx, workflowInput)
 	default:
 		return "", fmt.Errorf("invalid stage: %s", workflowInput.Stage)
 	}
@@ -83,7 +109,7 @@ func ExecuteWorkflow(input json.RawMessage) (string, error) {
 }
 
 // executeAnalyzeStage handles the analysis phase of refactoring
-func executeAnalyzeStage(input WorkflowInput) WorkflowOutput {
+func executeAnalyzeStage(ctx context.Context, input WorkflowInput) WorkflowOutput {
 	output := WorkflowOutput{
 		Stage: "analyze",
 	}
@@ -97,7 +123,7 @@ func executeAnalyzeStage(input WorkflowInput) WorkflowOutput {
 
 	case "dependencies":
 		// Check dependencies with go mod
-		result, err := RunGoCommand("mod", "", []string{"graph"}, "")
+		result, err := RunGoCommand(ctx, "mod", "", []string{"graph"}, "")
 		if err != nil {
 			output.Status = "error"
 			output.Message = fmt.Sprintf("Failed to analyze dependencies: %v", err)
@@ -113,7 +139,7 @@ func executeAnalyzeStage(input WorkflowInput) WorkflowOutput {
 
 	case "code_quality":
 		// Run golint or other code quality tools
-		result, err := RunGoCommand("vet", "./...", nil, "")
+		result, err := RunGoCommand(ctx, "vet", "./...", nil, "")
 		if err != nil {
 			output.Status = "error"
 			output.Message = fmt.Sprintf("Failed to analyze code quality: %v", err)
@@ -128,6 +154,24 @@ func executeAnalyzeStage(input WorkflowInput) WorkflowOutput {
 		}
 		output.NextSteps = "Move to 'plan' stage to create a refactoring plan."
 
+	case "long_functions":
+		longFunctions, err := findLongFunctions(ctx, input)
+		if err != nil {
+			output.Status = "error"
+			output.Message = fmt.Sprintf("Failed to analyze function sizes: %v", err)
+			return output
+		}
+
+		output.Status = "success"
+		output.LongFunctions = longFunctions
+		if len(longFunctions) == 0 {
+			output.Message = "No functions exceed the size limits."
+			output.NextSteps = "Move to 'plan' stage to create a refactoring plan."
+		} else {
+			output.Message = fmt.Sprintf("Found %d function(s) exceeding the size limits.", len(longFunctions))
+			output.NextSteps = "Move to 'plan' stage to plan splitting the largest functions first."
+		}
+
 	default:
 		output.Status = "error"
 		output.Message = fmt.Sprintf("Unknown analyze operation: %s", input.Operation)
@@ -136,8 +180,97 @@ func executeAnalyzeStage(input WorkflowInput) WorkflowOutput {
 	return output
 }
 
+// findLongFunctions parses the non-test Go files under input.Path and returns the functions
+// exceeding the line or statement limit, longest first
+func findLongFunctions(ctx context.Context, input WorkflowInput) ([]LongFunction, error) {
+	maxLines := input.MaxLines
+	if maxLines <= 0 {
+		maxLines = defaultMaxFunctionLines
+	}
+	maxStatements := input.MaxStatements
+	if maxStatements <= 0 {
+		maxStatements = defaultMaxFunctionStatements
+	}
+
+	root := workspaceDir(ctx)
+	if input.Path != "" {
+		var err error
+		root, err = ResolvePath(ctx, input.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := goFilesUnder(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	longFunctions := []LongFunction{}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			// Unparseable files are not worth failing the whole analysis for
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+
+			start := fset.Position(funcDecl.Pos())
+			lines := fset.Position(funcDecl.End()).Line - start.Line + 1
+			statements := countStatements(funcDecl.Body)
+			if lines <= maxLines && statements <= maxStatements {
+				continue
+			}
+
+			name := funcDecl.Name.Name
+			if receiver := receiverTypeName(funcDecl); receiver != "" {
+				name = receiver + "." + name
+			}
+			longFunctions = append(longFunctions, LongFunction{
+				File:       path,
+				Line:       start.Line,
+				Function:   name,
+				Lines:      lines,
+				Statements: statements,
+			})
+		}
+	}
+
+	sort.SliceStable(longFunctions, func(i, j int) bool {
+		if longFunctions[i].Lines != longFunctions[j].Lines {
+			return longFunctions[i].Lines > longFunctions[j].Lines
+		}
+		return longFunctions[i].Statements > longFunctions[j].Statements
+	})
+	return longFunctions, nil
+}
+
+// countStatements counts the statements in body, including those nested in blocks and closures.
+// Blocks themselves are not counted, only the statements they contain.
+func countStatements(body *ast.BlockStmt) int {
+	count := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		if _, ok := n.(ast.Stmt); ok {
+			if _, isBlock := n.(*ast.BlockStmt); !isBlock {
+				count++
+			}
+		}
+		return true
+	})
+	return count
+}
+
 // executePlanStage handles the planning phase of refactoring
-func executePlanStage(input WorkflowInput) WorkflowOutput {
+func executePlanStage(ctx context.Context, input WorkflowInput) WorkflowOutput {
 	output := WorkflowOutput{
 		Stage: "plan",
 	}
@@ -164,7 +297,7 @@ func executePlanStage(input WorkflowInput) WorkflowOutput {
 }
 
 // executeImplementStage handles the implementation phase of refactoring
-func executeImplementStage(input WorkflowInput) WorkflowOutput {
+func executeImplementStage(ctx context.Context, input WorkflowInput) WorkflowOutput {
 	output := WorkflowOutput{
 		Stage: "implement",
 	}
@@ -186,7 +319,7 @@ func executeImplementStage(input WorkflowInput) WorkflowOutput {
 		}
 
 		editJSON, _ := json.Marshal(editInput)
-		result, err := EditFileContent(editJSON)
+		result, err := EditFileContent(ctx, editJSON)
 		if err != nil {
 			output.Status = "error"
 			output.Message = fmt.Sprintf("Failed to edit file: %v", err)
@@ -219,7 +352,7 @@ func executeImplementStage(input WorkflowInput) WorkflowOutput {
 }
 
 // executeTestStage handles the testing phase of refactoring
-func executeTestStage(input WorkflowInput) WorkflowOutput {
+func executeTestStage(ctx context.Context, input WorkflowInput) WorkflowOutput {
 	output := WorkflowOutput{
 		Stage: "test",
 	}
@@ -227,7 +360,7 @@ func executeTestStage(input WorkflowInput) WorkflowOutput {
 	switch input.Operation {
 	case "build":
 		// Build the project
-		buildResult, err := RunGoCommand("build", "./...", nil, "")
+		buildResult, err := RunGoCommand(ctx, "build", "./...", nil, "")
 		if err != nil {
 			output.Status = "error"
 			output.Message = "Build failed. See errors below:"
@@ -243,7 +376,7 @@ func executeTestStage(input WorkflowInput) WorkflowOutput {
 
 	case "unit_test":
 		// Run unit tests
-		testResult, err := RunGoCommand("test", "./...", nil, "")
+		testResult, err := RunGoCommand(ctx, "test", "./...", nil, "")
 		if err != nil {
 			output.Status = "error"
 			output.Message = "Tests failed. See errors below:"
@@ -264,7 +397,7 @@ func executeTestStage(input WorkflowInput) WorkflowOutput {
 }
 
 // executeVerifyStage handles the verification phase of refactoring
-func executeVerifyStage(input WorkflowInput) WorkflowOutput {
+func executeVerifyStage(ctx context.Context, input WorkflowInput) WorkflowOutput {
 	output := WorkflowOutput{
 		Stage: "verify",
 	}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+// longFunctionSource returns a function named name whose body has n assignment statements
+func longFunctionSource(name string, n int) string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "func %s() int {\n\tx := 0\n", name)
+	for i := 0; i < n; i++ {
+		body.WriteString("\tx++\n")
+	}
+	body.WriteString("\treturn x\n}\n")
+	return body.String()
+}
+
+func TestWorkflowFlagsLongFunctions(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "big.go", "package big\n\ntype Parser struct{}\n\n"+
+		longFunctionSource("Short", 3)+"\n"+
+		longFunctionSource("Long", 30)+"\n"+
+		strings.Replace(longFunctionSource("Parse", 25), "func Parse", "func (p *Parser) Parse", 1))
+	writeTestFile(t, dir, "big_test.go", "package big\n\n"+longFunctionSource("helper", 60))
+
+	var output WorkflowOutput
+	callTool(t, ctx, ExecuteWorkflow, WorkflowInput{Stage: "analyze", Operation: "long_functions", MaxLines: 20}, &output)
+
+	if output.Status != "success" || len(output.LongFunctions) != 2 {
+		t.Fatalf("got %+v, want Long and Parser.Parse flagged", output)
+	}
+	long, parse := output.LongFunctions[0], output.LongFunctions[1]
+	if long.Function != "Long" || long.Lines != 34 || long.Statements != 32 || long.Line != 13 {
+		t.Errorf("first = %+v, want Long at line 13 with 34 lines and 32 statements", long)
+	}
+	if parse.Function != "Parser.Parse" || parse.Lines != 29 || !strings.HasSuffix(parse.File, "big.go") {
+		t.Errorf("second = %+v, want Parser.Parse with 29 lines", parse)
+	}
+	if !strings.Contains(output.Message, "Found 2 function(s)") {
+		t.Errorf("message = %q", output.Message)
+	}
+}
+
+func TestWorkflowLongFunctionsStatementLimit(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "dense.go", "package dense\n\nfunc Dense() { a, b := 1, 2; a, b = b, a; _ = a; _ = b }\n")
+
+	var output WorkflowOutput
+	callTool(t, ctx, ExecuteWorkflow, WorkflowInput{Stage: "analyze", Operation: "long_functions", Path: ".", MaxStatements: 3}, &output)
+	if len(output.LongFunctions) != 1 || output.LongFunctions[0].Lines != 1 || output.LongFunctions[0].Statements != 4 {
+		t.Errorf("got %+v, want the one-line function flagged for its 4 statements", output.LongFunctions)
+	}
+
+	// The defaults leave a small function alone
+	var defaults WorkflowOutput
+	callTool(t, ctx, ExecuteWorkflow, WorkflowInput{Stage: "analyze", Operation: "long_functions"}, &defaults)
+	if len(defaults.LongFunctions) != 0 || !strings.Contains(defaults.Message, "No functions exceed") {
+		t.Errorf("got %+v, want nothing flagged", defaults)
+	}
+}
+
+func TestCountStatements(t *testing.T) {
+	src := `package p
+
+func f(items []int) {
+	total := 0
+	for _, item := range items {
+		if item > 0 {
+			total += item
+		}
+	}
+	go func() {
+		println(total)
+	}()
+}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// total :=, range, if, +=, go, println
+	if got := countStatements(file.Decls[0].(*ast.FuncDecl).Body); got != 6 {
+		t.Errorf("countStatements = %d, want 6", got)
+	}
+}
@@ -0,0 +1,239 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ReplaceInFuncToolDefinition defines the replace_in_func tool
+var ReplaceInFuncToolDefinition = ToolDefinition{
+	Name: "replace_in_func",
+	Description: `Replace text only inside the bodies of functions with a given name, across every Go file in a tree.
+The function is named 'Name' (any function or method called Name) or 'Type.Method'. Matches outside
+those function bodies are left untouched, and the rest of each file is written back byte for byte;
+a replacement that would leave the file unparseable is rejected. By
+default 'pattern' is literal text; set 'regex' to use a regular expression with $1-style group
+references in 'replacement'. Set 'dry_run' to preview the changes without writing them.`,
+	InputSchema:           ReplaceInFuncInputSchema,
+	Function:              ReplaceInFunc,
+	CountsTowardLoopLimit: true,
+}
+
+// ReplaceInFuncInput defines the input parameters for the replace_in_func tool
+type ReplaceInFuncInput struct {
+	Function    string `json:"function" jsonschema_required:"true" jsonschema_description:"Function name, or 'Type.Method' for a method" jsonschema_example:"Server.handleRequest"`
+	Pattern     string `json:"pattern" jsonschema_required:"true" jsonschema_description:"Text to search for, or a regular expression when 'regex' is set"`
+	Replacement string `json:"replacement" jsonschema_description:"Replacement text"`
+	Regex       bool   `json:"regex,omitempty" jsonschema_description:"Treat 'pattern' as a regular expression"`
+	Path        string `json:"path,omitempty" jsonschema_description:"Root directory to search. Defaults to the current directory."`
+	DryRun      bool   `json:"dry_run,omitempty" jsonschema_description:"If true, report the changes without writing any files"`
+}
+
+// ReplaceInFuncInputSchema is the JSON schema for the replace_in_func tool
+var ReplaceInFuncInputSchema = GenerateSchema[ReplaceInFuncInput]()
+
+// FuncReplacement describes the replacements made (or planned) in one function
+type FuncReplacement struct {
+	File         string `json:"file"`
+	Function     string `json:"function"`
+	Line         int    `json:"line"`
+	Replacements int    `json:"replacements"`
+}
+
+// ReplaceInFuncOutput represents the structured output of the replace_in_func tool
+type ReplaceInFuncOutput struct {
+	DryRun            bool              `json:"dry_run"`
+	MatchedFunctions  int               `json:"matched_functions"`
+	TotalReplacements int               `json:"total_replacements"`
+	Functions         []FuncReplacement `json:"functions"`
+	SampleDiff        string            `json:"sample_diff,omitempty"`
+	Message           string            `json:"message"`
+}
+
+// ReplaceInFunc implements the replace_in_func tool functionality
+func ReplaceInFunc(ctx context.Context, input json.RawMessage) (string, error) {
+	replaceInput := ReplaceInFuncInput{}
+	err := DecodeInput(input, &replaceInput)
+	if err != nil {
+		return "", err
+	}
+
+	if replaceInput.Function == "" {
+		return "", fmt.Errorf("function parameter is required")
+	}
+	if replaceInput.Pattern == "" {
+		return "", fmt.Errorf("pattern cannot be empty")
+	}
+
+	expr := replaceInput.Pattern
+	if !replaceInput.Regex {
+		expr = regexp.QuoteMeta(expr)
+	}
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return "", fmt.Errorf("invalid regex pattern: %w", err)
+	}
+	replacement := replaceInput.Replacement
+	if !replaceInput.Regex {
+		// Literal replacements must not expand '$'
+		replacement = strings.ReplaceAll(replacement, "$", "$$")
+	}
+
+	typeName, funcName := "", replaceInput.Function
+	if idx := strings.LastIndex(funcName, "."); idx >= 0 {
+		typeName, funcName = funcName[:idx], funcName[idx+1:]
+	}
+
+	root := workspaceDir(ctx)
+	if replaceInput.Path != "" {
+		root, err = ResolvePath(ctx, replaceInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	type pendingChange struct {
+		path    string
+		mode    os.FileMode
+		content []byte
+	}
+
+	var changes []pendingChange
+	output := ReplaceInFuncOutput{
+		DryRun:    replaceInput.DryRun,
+		Functions: []FuncReplacement{},
+	}
+	var sample strings.Builder
+	ignoreRules := LoadIgnoreRules(workspaceDir(ctx))
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if ignoreRules.IgnoredPath(path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, content, parser.SkipObjectResolution)
+		if err != nil {
+			// Files that do not parse cannot be edited safely by AST bounds
+			return nil
+		}
+
+		var newContent []byte
+		var found []FuncReplacement
+		last := 0
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil || funcDecl.Name.Name != funcName {
+				continue
+			}
+			receiver := receiverTypeName(funcDecl)
+			if typeName != "" && receiver != typeName {
+				continue
+			}
+
+			start := fset.Position(funcDecl.Body.Lbrace).Offset + 1
+			end := fset.Position(funcDecl.Body.Rbrace).Offset
+			body := content[start:end]
+			count := len(regex.FindAllIndex(body, -1))
+			if count == 0 {
+				continue
+			}
+
+			newContent = append(newContent, content[last:start]...)
+			newContent = append(newContent, regex.ReplaceAll(body, []byte(replacement))...)
+			last = end
+
+			name := funcDecl.Name.Name
+			if receiver != "" {
+				name = receiver + "." + name
+			}
+			found = append(found, FuncReplacement{
+				File:         path,
+				Function:     name,
+				Line:         fset.Position(funcDecl.Pos()).Line,
+				Replacements: count,
+			})
+		}
+		if len(found) == 0 {
+			return nil
+		}
+		newContent = append(newContent, content[last:]...)
+
+		// Only the function bodies were rewritten, so imports and the rest of the file keep their
+		// exact bytes; parsing checks the result is still valid Go without reformatting it
+		if _, err := parser.ParseFile(token.NewFileSet(), path, newContent, parser.SkipObjectResolution); err != nil {
+			return fmt.Errorf("replacement in %s produces invalid Go: %w", path, err)
+		}
+		if string(newContent) == string(content) {
+			return nil
+		}
+
+		changes = append(changes, pendingChange{path: path, mode: info.Mode(), content: newContent})
+		for _, fn := range found {
+			output.TotalReplacements += fn.Replacements
+		}
+		output.Functions = append(output.Functions, found...)
+		if len(changes) <= repoReplaceSampleFiles {
+			sample.WriteString(sampleLineDiff(path, string(content), string(newContent), repoReplaceSampleLines))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	output.MatchedFunctions = len(output.Functions)
+	output.SampleDiff = sample.String()
+
+	switch {
+	case len(changes) == 0:
+		output.Message = fmt.Sprintf("Pattern not matched in the body of any function named %s.", replaceInput.Function)
+	case replaceInput.DryRun:
+		output.Message = fmt.Sprintf("Dry run: %d replacement(s) in %d function(s) across %d file(s). No files were modified.",
+			output.TotalReplacements, output.MatchedFunctions, len(changes))
+	default:
+		for _, change := range changes {
+			if err := os.WriteFile(change.path, change.content, change.mode.Perm()); err != nil {
+				return "", fmt.Errorf("failed to write %s: %w", change.path, err)
+			}
+			recordReadHash(change.path, change.content)
+		}
+		output.Message = fmt.Sprintf("Successfully replaced %d occurrence(s) in %d function(s) across %d file(s).",
+			output.TotalReplacements, output.MatchedFunctions, len(changes))
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
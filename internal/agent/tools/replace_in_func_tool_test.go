@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+const replaceInFuncFixture = `package server
+
+import "log"
+
+// timeout is also 30 outside the function
+const timeout = 30
+
+func connect() {
+	log.Println("retry after", 30)
+}
+
+func (s *Server) connect() {
+	log.Println("retry after", 30)
+}
+
+func serve() {
+	log.Println("retry after", 30)
+}
+
+type Server struct{}
+`
+
+func TestReplaceInFuncOnlyInsideBody(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "server/server.go", replaceInFuncFixture)
+	other := writeTestFile(t, dir, "client/client.go", "package client\n\nfunc connect() int { return 30 }\n")
+
+	var output ReplaceInFuncOutput
+	callTool(t, ctx, ReplaceInFunc, ReplaceInFuncInput{Function: "connect", Pattern: "30", Replacement: "60"}, &output)
+
+	if output.MatchedFunctions != 3 || output.TotalReplacements != 3 {
+		t.Errorf("got %d functions and %d replacements, want 3 of each: %+v", output.MatchedFunctions, output.TotalReplacements, output.Functions)
+	}
+	got := readTestFile(t, path)
+	if strings.Count(got, "retry after\", 60") != 2 || !strings.Contains(got, "func serve() {\n\tlog.Println(\"retry after\", 30)") {
+		t.Errorf("only the connect bodies should change:\n%s", got)
+	}
+	if !strings.Contains(got, "// timeout is also 30 outside the function\nconst timeout = 30\n") {
+		t.Errorf("text outside the function changed:\n%s", got)
+	}
+	if got := readTestFile(t, other); got != "package client\n\nfunc connect() int { return 60 }\n" {
+		t.Errorf("connect in another package wasn't updated: %q", got)
+	}
+}
+
+func TestReplaceInFuncMethodOnly(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "server.go", replaceInFuncFixture)
+
+	var output ReplaceInFuncOutput
+	callTool(t, ctx, ReplaceInFunc, ReplaceInFuncInput{Function: "Server.connect", Pattern: `"retry after", (\d+)`, Replacement: `"retrying in", $1`, Regex: true}, &output)
+
+	got := readTestFile(t, path)
+	if strings.Count(got, `"retrying in", 30`) != 1 || !strings.Contains(got, "func (s *Server) connect() {\n\tlog.Println(\"retrying in\", 30)") {
+		t.Errorf("only the method should change:\n%s", got)
+	}
+	if len(output.Functions) != 1 || output.Functions[0].Function != "Server.connect" || output.Functions[0].Line != 12 {
+		t.Errorf("functions = %+v", output.Functions)
+	}
+}
+
+func TestReplaceInFuncDryRunAndInvalidResult(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "server.go", replaceInFuncFixture)
+
+	var output ReplaceInFuncOutput
+	callTool(t, ctx, ReplaceInFunc, ReplaceInFuncInput{Function: "serve", Pattern: "30", Replacement: "45", DryRun: true}, &output)
+	if !output.DryRun || output.TotalReplacements != 1 || output.SampleDiff == "" {
+		t.Errorf("dry run output = %+v", output)
+	}
+	if got := readTestFile(t, path); got != replaceInFuncFixture {
+		t.Errorf("dry run modified the file:\n%s", got)
+	}
+
+	// Removing the closing parenthesis would leave the file unparseable
+	if _, err := ReplaceInFunc(ctx, mustMarshal(t, ReplaceInFuncInput{Function: "serve", Pattern: "30)", Replacement: "30"})); err == nil {
+		t.Error("expected a replacement that breaks the syntax to be rejected")
+	}
+	if got := readTestFile(t, path); got != replaceInFuncFixture {
+		t.Errorf("the rejected replacement modified the file:\n%s", got)
+	}
+}
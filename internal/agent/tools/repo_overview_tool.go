@@ -0,0 +1,211 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RepoOverviewToolDefinition defines the repo_overview tool
+var RepoOverviewToolDefinition = ToolDefinition{
+	Name: "repo_overview",
+	Description: `Get a quick first look at a repository.
+Walks the tree (skipping hidden directories and anything in .metamorphignore) and returns
+file counts and total sizes by extension, the detected primary language, which key project
+files are present (go.mod, Makefile, Dockerfile, README, ...), the top-level directories,
+and the maximum directory depth. Cheaper than listing every file.`,
+	InputSchema: RepoOverviewInputSchema,
+	Function:    RepoOverview,
+}
+
+// RepoOverviewInput defines the input parameters for the repo_overview tool
+type RepoOverviewInput struct {
+	Path string `json:"path,omitempty" jsonschema_description:"Root directory of the repository. Defaults to the current directory."`
+}
+
+// RepoOverviewInputSchema is the JSON schema for the repo_overview tool
+var RepoOverviewInputSchema = GenerateSchema[RepoOverviewInput]()
+
+// ExtensionStats holds the file count and total size for one file extension
+type ExtensionStats struct {
+	Extension string `json:"extension"`
+	Files     int    `json:"files"`
+	Bytes     int64  `json:"bytes"`
+}
+
+// RepoOverviewOutput represents the structured output of the repo_overview tool
+type RepoOverviewOutput struct {
+	Root            string           `json:"root"`
+	TotalFiles      int              `json:"total_files"`
+	TotalBytes      int64            `json:"total_bytes"`
+	Directories     int              `json:"directories"`
+	MaxDepth        int              `json:"max_depth"`
+	PrimaryLanguage string           `json:"primary_language"`
+	Languages       map[string]int64 `json:"languages"`
+	Extensions      []ExtensionStats `json:"extensions"`
+	KeyFiles        []string         `json:"key_files"`
+	TopLevelDirs    []string         `json:"top_level_dirs"`
+}
+
+// languageExtensions maps file extensions to the language they indicate
+var languageExtensions = map[string]string{
+	".go":    "Go",
+	".py":    "Python",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".rs":    "Rust",
+	".java":  "Java",
+	".kt":    "Kotlin",
+	".rb":    "Ruby",
+	".php":   "PHP",
+	".c":     "C",
+	".h":     "C",
+	".cc":    "C++",
+	".cpp":   "C++",
+	".hpp":   "C++",
+	".cs":    "C#",
+	".swift": "Swift",
+	".scala": "Scala",
+	".sh":    "Shell",
+}
+
+// repoKeyFiles lists root-level files and directories that hint at how a project is built
+var repoKeyFiles = []string{
+	"go.mod",
+	"go.work",
+	"Makefile",
+	"Dockerfile",
+	"docker-compose.yml",
+	"README.md",
+	"LICENSE",
+	"package.json",
+	"Cargo.toml",
+	"pyproject.toml",
+	"requirements.txt",
+	".github/workflows",
+	".gitlab-ci.yml",
+	MetamorphIgnoreFile,
+}
+
+// RepoOverview implements the repo_overview tool functionality
+func RepoOverview(ctx context.Context, input json.RawMessage) (string, error) {
+	overviewInput := RepoOverviewInput{}
+	err := json.Unmarshal(input, &overviewInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	root := workspaceDir(ctx)
+	if overviewInput.Path != "" {
+		root, err = ResolvePath(ctx, overviewInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	output := RepoOverviewOutput{
+		Root:         root,
+		Languages:    map[string]int64{},
+		Extensions:   []ExtensionStats{},
+		KeyFiles:     []string{},
+		TopLevelDirs: []string{},
+	}
+	extensions := map[string]*ExtensionStats{}
+	ignoreRules := LoadIgnoreRules(workspaceDir(ctx))
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+		if ignoreRules.IgnoredPath(path, info.IsDir()) || (info.IsDir() && strings.HasPrefix(info.Name(), ".")) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		depth := strings.Count(relPath, string(filepath.Separator)) + 1
+		if info.IsDir() {
+			output.Directories++
+			if depth > output.MaxDepth {
+				output.MaxDepth = depth
+			}
+			if depth == 1 {
+				output.TopLevelDirs = append(output.TopLevelDirs, relPath)
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(info.Name()))
+		if ext == "" {
+			ext = "(none)"
+		}
+		stats, ok := extensions[ext]
+		if !ok {
+			stats = &ExtensionStats{Extension: ext}
+			extensions[ext] = stats
+		}
+		stats.Files++
+		stats.Bytes += info.Size()
+
+		output.TotalFiles++
+		output.TotalBytes += info.Size()
+		if language, ok := languageExtensions[ext]; ok {
+			output.Languages[language] += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+
+	for _, stats := range extensions {
+		output.Extensions = append(output.Extensions, *stats)
+	}
+	sort.Slice(output.Extensions, func(i, j int) bool {
+		if output.Extensions[i].Files != output.Extensions[j].Files {
+			return output.Extensions[i].Files > output.Extensions[j].Files
+		}
+		return output.Extensions[i].Extension < output.Extensions[j].Extension
+	})
+
+	// The primary language is the one with the most source bytes
+	var primaryBytes int64
+	for language, size := range output.Languages {
+		if size > primaryBytes || (size == primaryBytes && language < output.PrimaryLanguage) {
+			output.PrimaryLanguage, primaryBytes = language, size
+		}
+	}
+	if output.PrimaryLanguage == "" {
+		output.PrimaryLanguage = "unknown"
+	}
+
+	for _, name := range repoKeyFiles {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(name))); err == nil {
+			output.KeyFiles = append(output.KeyFiles, name)
+		}
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRepoOverview(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/app")
+	writeTestFile(t, dir, "Makefile", "build:\n\tgo build ./...\n")
+	writeTestFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+	writeTestFile(t, dir, "internal/store/store.go", "package store\n")
+	writeTestFile(t, dir, "internal/store/store_test.go", "package store\n")
+	writeTestFile(t, dir, "scripts/setup.sh", "#!/bin/sh\n")
+	writeTestFile(t, dir, "docs/guide.md", "# Guide\n")
+	writeTestFile(t, dir, ".git/config", "[core]\n")
+	writeTestFile(t, dir, MetamorphIgnoreFile, "generated/\n")
+	writeTestFile(t, dir, "generated/big.go", "package generated\n\n// "+strings.Repeat("x", 4096)+"\n")
+
+	var output RepoOverviewOutput
+	callTool(t, ctx, RepoOverview, RepoOverviewInput{}, &output)
+
+	if output.PrimaryLanguage != "Go" {
+		t.Errorf("primary language = %q, want Go", output.PrimaryLanguage)
+	}
+	if output.TotalFiles != 8 {
+		t.Errorf("total files = %d, want 8 (hidden and ignored paths excluded)", output.TotalFiles)
+	}
+	if output.Directories != 4 || output.MaxDepth != 2 {
+		t.Errorf("got %d directories at depth %d, want 4 at depth 2", output.Directories, output.MaxDepth)
+	}
+	if want := []string{"docs", "internal", "scripts"}; !reflect.DeepEqual(output.TopLevelDirs, want) {
+		t.Errorf("top-level dirs = %v, want %v", output.TopLevelDirs, want)
+	}
+	if want := []string{"go.mod", "Makefile", MetamorphIgnoreFile}; !reflect.DeepEqual(output.KeyFiles, want) {
+		t.Errorf("key files = %v, want %v", output.KeyFiles, want)
+	}
+
+	if len(output.Extensions) == 0 || output.Extensions[0].Extension != ".go" || output.Extensions[0].Files != 3 {
+		t.Fatalf("expected .go to lead the breakdown with 3 files: %+v", output.Extensions)
+	}
+	counts := make(map[string]int)
+	for _, stats := range output.Extensions {
+		counts[stats.Extension] = stats.Files
+	}
+	for ext, want := range map[string]int{".md": 1, ".sh": 1, ".mod": 1, "(none)": 1} {
+		if counts[ext] != want {
+			t.Errorf("%s files = %d, want %d", ext, counts[ext], want)
+		}
+	}
+	if _, ok := output.Languages["Shell"]; !ok {
+		t.Errorf("languages = %v, want Shell to be detected", output.Languages)
+	}
+}
+
+func TestRepoOverviewUnknownLanguage(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "notes.txt", "just notes\n")
+
+	var output RepoOverviewOutput
+	callTool(t, ctx, RepoOverview, RepoOverviewInput{}, &output)
+	if output.PrimaryLanguage != "unknown" || output.TotalFiles != 1 {
+		t.Errorf("got %+v, want one file in an unknown language", output)
+	}
+}
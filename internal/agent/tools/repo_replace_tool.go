@@ -0,0 +1,292 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RepoReplaceToolDefinition defines the replace_in_repo tool
+var RepoReplaceToolDefinition = ToolDefinition{
+	Name: "replace_in_repo",
+	Description: `Replace text matching a regular expression across all files in a directory tree.
+This tool is a dry run by default: it returns the number of affected files and a sample diff
+without modifying anything. Review the preview, then call it again with the same arguments and
+'apply' set to true to actually write the changes. An apply without a matching preview is refused,
+as is an apply that would touch more than 'max_files' files.`,
+	InputSchema:           RepoReplaceInputSchema,
+	Function:              ReplaceInRepo,
+	CountsTowardLoopLimit: true,
+}
+
+// RepoReplaceInput defines the input parameters for the replace_in_repo tool
+type RepoReplaceInput struct {
+	Pattern     string `json:"pattern" jsonschema_required:"true" jsonschema_description:"Regular expression to search for"`
+	Replacement string `json:"replacement" jsonschema_description:"Replacement text (supports $1-style group references)"`
+	Path        string `json:"path,omitempty" jsonschema_description:"Root directory to search. Defaults to the current directory."`
+	Include     string `json:"include,omitempty" jsonschema_description:"Optional file name glob to restrict matching files (e.g. '*.go')"`
+	Apply       bool   `json:"apply,omitempty" jsonschema_description:"Set to true to write the changes. Defaults to false (dry run)."`
+	MaxFiles    int    `json:"max_files,omitempty" jsonschema_description:"Maximum number of files an apply may modify. Defaults to 50."`
+}
+
+// RepoReplaceInputSchema is the JSON schema for the replace_in_repo tool
+var RepoReplaceInputSchema = GenerateSchema[RepoReplaceInput]()
+
+// RepoReplaceFile describes the replacements made (or planned) in a single file
+type RepoReplaceFile struct {
+	Path         string `json:"path"`
+	Replacements int    `json:"replacements"`
+}
+
+// RepoReplaceOutput represents the structured output of the replace_in_repo tool
+type RepoReplaceOutput struct {
+	DryRun            bool              `json:"dry_run"`
+	AffectedFiles     int               `json:"affected_files"`
+	TotalReplacements int               `json:"total_replacements"`
+	Files             []RepoReplaceFile `json:"files"`
+	SampleDiff        string            `json:"sample_diff,omitempty"`
+	Message           string            `json:"message"`
+}
+
+const (
+	defaultRepoReplaceMaxFiles = 50
+	repoReplaceSampleFiles     = 3
+	repoReplaceSampleLines     = 5
+)
+
+var (
+	// previewedReplacements records dry runs so an apply can require a prior preview
+	previewedReplacements      = make(map[string]bool)
+	previewedReplacementsMutex sync.Mutex
+)
+
+// replacementKey identifies a replacement request independently of the apply flag
+func replacementKey(input RepoReplaceInput, root string) string {
+	return strings.Join([]string{input.Pattern, input.Replacement, root, input.Include}, "\x00")
+}
+
+// ReplaceInRepo implements the replace_in_repo tool functionality
+func ReplaceInRepo(ctx context.Context, input json.RawMessage) (string, error) {
+	replaceInput := RepoReplaceInput{}
+	err := DecodeInput(input, &replaceInput)
+	if err != nil {
+		return "", err
+	}
+
+	if replaceInput.Pattern == "" {
+		return "", fmt.Errorf("pattern cannot be empty")
+	}
+
+	regex, err := regexp.Compile(replaceInput.Pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid regex pattern: %w", err)
+	}
+
+	root := workspaceDir(ctx)
+	if replaceInput.Path != "" {
+		root, err = ResolvePath(ctx, replaceInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	key := replacementKey(replaceInput, root)
+	if replaceInput.Apply {
+		previewedReplacementsMutex.Lock()
+		previewed := previewedReplacements[key]
+		previewedReplacementsMutex.Unlock()
+		if !previewed {
+			return "", fmt.Errorf("no dry run found for this replacement; call replace_in_repo without 'apply' first to preview the changes")
+		}
+	}
+
+	maxFiles := replaceInput.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = defaultRepoReplaceMaxFiles
+	}
+
+	type pendingChange struct {
+		path    string
+		mode    os.FileMode
+		content []byte
+	}
+
+	var changes []pendingChange
+	output := RepoReplaceOutput{
+		DryRun: !replaceInput.Apply,
+		Files:  []RepoReplaceFile{},
+	}
+	var sample strings.Builder
+	sampledFiles := 0
+	ignoreRules := LoadIgnoreRules(workspaceDir(ctx))
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if ignoreRules.IgnoredPath(path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if path != root && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if replaceInput.Include != "" {
+			if matched, _ := filepath.Match(replaceInput.Include, info.Name()); !matched {
+				return nil
+			}
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		// Skip binary files
+		if isBinary(content) {
+			return nil
+		}
+
+		matches := regex.FindAllIndex(content, -1)
+		if len(matches) == 0 {
+			return nil
+		}
+
+		newContent := regex.ReplaceAll(content, []byte(replaceInput.Replacement))
+		if bytes.Equal(content, newContent) {
+			return nil
+		}
+
+		changes = append(changes, pendingChange{path: path, mode: info.Mode(), content: newContent})
+		output.Files = append(output.Files, RepoReplaceFile{Path: path, Replacements: len(matches)})
+		output.TotalReplacements += len(matches)
+
+		if sampledFiles < repoReplaceSampleFiles {
+			sample.WriteString(sampleLineDiff(path, string(content), string(newContent), repoReplaceSampleLines))
+			sampledFiles++
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	output.AffectedFiles = len(changes)
+	output.SampleDiff = sample.String()
+
+	switch {
+	case len(changes) == 0:
+		output.Message = "Pattern not matched in any file."
+	case !replaceInput.Apply:
+		previewedReplacementsMutex.Lock()
+		previewedReplacements[key] = true
+		previewedReplacementsMutex.Unlock()
+		output.Message = fmt.Sprintf("Dry run: %d replacement(s) in %d file(s). No files were modified. Call again with 'apply': true to write the changes.",
+			output.TotalReplacements, output.AffectedFiles)
+		if len(changes) > maxFiles {
+			output.Message = fmt.Sprintf("Dry run: %d replacement(s) in %d file(s). No files were modified. Warning: this exceeds max_files (%d), so an apply will be refused; narrow the pattern, path, or include glob, or raise max_files.",
+				output.TotalReplacements, output.AffectedFiles, maxFiles)
+		}
+	case len(changes) > maxFiles:
+		return "", fmt.Errorf("refusing to modify %d files, which exceeds max_files (%d); narrow the pattern, path, or include glob",
+			len(changes), maxFiles)
+	default:
+		// Stage every file before touching any of them so a write error leaves the tree unchanged
+		staged := make([]string, 0, len(changes))
+		defer func() {
+			for _, tmp := range staged {
+				os.Remove(tmp)
+			}
+		}()
+		for _, change := range changes {
+			tmp, err := stageReplacement(change.path, change.content, change.mode)
+			if err != nil {
+				return "", fmt.Errorf("failed to write %s: %w; no files were modified", change.path, err)
+			}
+			staged = append(staged, tmp)
+		}
+
+		var written []string
+		for i, change := range changes {
+			if err := os.Rename(staged[i], change.path); err != nil {
+				if len(written) == 0 {
+					return "", fmt.Errorf("failed to replace %s: %w; no files were modified", change.path, err)
+				}
+				return "", fmt.Errorf("failed to replace %s: %w; these files were already rewritten: %s",
+					change.path, err, strings.Join(written, ", "))
+			}
+			written = append(written, change.path)
+			recordReadHash(change.path, change.content)
+		}
+		previewedReplacementsMutex.Lock()
+		delete(previewedReplacements, key)
+		previewedReplacementsMutex.Unlock()
+		output.Message = fmt.Sprintf("Successfully replaced %d occurrence(s) in %d file(s).",
+			output.TotalReplacements, output.AffectedFiles)
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// stageReplacement writes content to a temporary file next to path and returns its name
+func stageReplacement(path string, content []byte, mode os.FileMode) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".replace-*")
+	if err != nil {
+		return "", err
+	}
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), mode.Perm())
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// sampleLineDiff returns a short line-oriented diff of the changed lines in a file
+func sampleLineDiff(path, before, after string, maxLines int) string {
+	oldLines := strings.Split(before, "\n")
+	newLines := strings.Split(after, "\n")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", path, path)
+
+	// Replacements may change line counts, so only compare the overlapping range
+	shown := 0
+	for i := 0; i < len(oldLines) && i < len(newLines); i++ {
+		if oldLines[i] == newLines[i] {
+			continue
+		}
+		if shown == maxLines {
+			b.WriteString("...\n")
+			break
+		}
+		fmt.Fprintf(&b, "@@ line %d @@\n-%s\n+%s\n", i+1, oldLines[i], newLines[i])
+		shown++
+	}
+
+	return b.String()
+}
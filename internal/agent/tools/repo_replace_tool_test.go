@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReplaceInRepoPreviewThenApply(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	a := writeTestFile(t, dir, "a.txt", "hello world\n")
+	b := writeTestFile(t, dir, "sub/b.txt", "hello again\n")
+
+	input := RepoReplaceInput{Pattern: "hello", Replacement: "goodbye"}
+
+	var preview RepoReplaceOutput
+	callTool(t, ctx, ReplaceInRepo, input, &preview)
+	if !preview.DryRun || preview.AffectedFiles != 2 || preview.TotalReplacements != 2 {
+		t.Errorf("unexpected preview: %+v", preview)
+	}
+	if readTestFile(t, a) != "hello world\n" || readTestFile(t, b) != "hello again\n" {
+		t.Fatal("the dry run modified files")
+	}
+
+	input.Apply = true
+	var applied RepoReplaceOutput
+	callTool(t, ctx, ReplaceInRepo, input, &applied)
+	if applied.DryRun || applied.AffectedFiles != 2 {
+		t.Errorf("unexpected apply result: %+v", applied)
+	}
+	if readTestFile(t, a) != "goodbye world\n" || readTestFile(t, b) != "goodbye again\n" {
+		t.Error("the apply did not write the replacements")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".replace-") {
+			t.Errorf("staged file %s was left behind", entry.Name())
+		}
+	}
+}
+
+func TestReplaceInRepoApplyRequiresPreview(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	a := writeTestFile(t, dir, "a.txt", "alpha\n")
+
+	_, err := ReplaceInRepo(ctx, []byte(`{"pattern": "alpha", "replacement": "beta", "apply": true}`))
+	if err == nil || !strings.Contains(err.Error(), "dry run") {
+		t.Errorf("expected an apply without a preview to be refused, got %v", err)
+	}
+	if readTestFile(t, a) != "alpha\n" {
+		t.Error("the refused apply modified the file")
+	}
+}
+
+func TestReplaceInRepoMaxFiles(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	a := writeTestFile(t, dir, "a.txt", "token\n")
+	writeTestFile(t, dir, "b.txt", "token\n")
+	writeTestFile(t, dir, "c.txt", "token\n")
+
+	input := RepoReplaceInput{Pattern: "token", Replacement: "value", MaxFiles: 2}
+	var preview RepoReplaceOutput
+	callTool(t, ctx, ReplaceInRepo, input, &preview)
+	if !strings.Contains(preview.Message, "exceeds max_files (2)") {
+		t.Errorf("expected the dry run to warn about max_files, got %q", preview.Message)
+	}
+
+	input.Apply = true
+	if _, err := ReplaceInRepo(ctx, mustMarshal(t, input)); err == nil {
+		t.Error("expected an apply touching more than max_files files to be refused")
+	}
+	if got := readTestFile(t, a); got != "token\n" {
+		t.Errorf("refused apply modified a.txt: %q", got)
+	}
+}
@@ -0,0 +1,371 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunBackgroundToolDefinition defines the run_background tool
+var RunBackgroundToolDefinition = ToolDefinition{
+	Name: "run_background",
+	Description: `Start a long-running command, such as a server, in the background and return a handle.
+The command runs without a shell: pass the program in 'command' and its arguments in 'args'.
+Its combined stdout and stderr are captured; use read_output with the handle to read new output
+and to stop the process. Background processes still running when the session ends are killed.`,
+	InputSchema:           RunBackgroundInputSchema,
+	Function:              RunBackground,
+	CountsTowardLoopLimit: true,
+}
+
+// ReadOutputToolDefinition defines the read_output tool
+var ReadOutputToolDefinition = ToolDefinition{
+	Name: "read_output",
+	Description: `Read the output of a process started with run_background.
+Each call returns only the lines written since the previous read, optionally filtered by the
+regular expression in 'filter', along with whether the process is still running and its exit
+code once it has finished. Set 'stop' to kill the process after reading. Without a 'handle',
+the background processes of this session are listed.`,
+	InputSchema: ReadOutputInputSchema,
+	Function:    ReadOutput,
+}
+
+// RunBackgroundInput defines the input parameters for the run_background tool
+type RunBackgroundInput struct {
+	Command    string   `json:"command" jsonschema_required:"true" jsonschema_description:"Program to run" jsonschema_example:"go"`
+	Args       []string `json:"args,omitempty" jsonschema_description:"Arguments for the program" jsonschema_example:"[\"run\", \"./cmd/server\"]"`
+	WorkingDir string   `json:"working_dir,omitempty" jsonschema_description:"Working directory (defaults to current directory if empty)"`
+}
+
+// RunBackgroundInputSchema is the JSON schema for the run_background tool
+var RunBackgroundInputSchema = GenerateSchema[RunBackgroundInput]()
+
+// ReadOutputInput defines the input parameters for the read_output tool
+type ReadOutputInput struct {
+	Handle      string `json:"handle,omitempty" jsonschema_description:"Handle returned by run_background. Leave empty to list background processes."`
+	Filter      string `json:"filter,omitempty" jsonschema_description:"Only return lines matching this regular expression"`
+	WaitSeconds int    `json:"wait_seconds,omitempty" jsonschema_description:"Wait up to this many seconds for new output if there is none yet. Defaults to 0, at most 300."`
+	Stop        bool   `json:"stop,omitempty" jsonschema_description:"Kill the process after reading its output"`
+}
+
+// ReadOutputInputSchema is the JSON schema for the read_output tool
+var ReadOutputInputSchema = GenerateSchema[ReadOutputInput]()
+
+// BackgroundProcessStatus describes a background process
+type BackgroundProcessStatus struct {
+	Handle   string   `json:"handle"`
+	Command  string   `json:"command"`
+	PID      int      `json:"pid"`
+	Running  bool     `json:"running"`
+	ExitCode *int     `json:"exit_code,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Started  string   `json:"started"`
+	Lines    []string `json:"lines,omitempty"`
+	Dropped  int      `json:"dropped_bytes,omitempty"`
+	Filtered int      `json:"filtered_lines,omitempty"`
+	Stopped  bool     `json:"stopped,omitempty"`
+}
+
+// maxBackgroundOutput caps the unread output kept per process; older output is dropped
+const maxBackgroundOutput = 1 << 20
+
+// backgroundProcess is a command started by run_background and its captured output
+type backgroundProcess struct {
+	handle  string
+	command string
+	cmd     *exec.Cmd
+	started time.Time
+	done    chan struct{}
+
+	mu       sync.Mutex
+	unread   []byte
+	dropped  int
+	exitErr  error
+	finished bool
+	notify   chan struct{}
+}
+
+// Write appends process output, dropping the oldest unread bytes beyond maxBackgroundOutput
+func (p *backgroundProcess) Write(data []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.unread = append(p.unread, data...)
+	if excess := len(p.unread) - maxBackgroundOutput; excess > 0 {
+		p.unread = p.unread[excess:]
+		p.dropped += excess
+	}
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+	return len(data), nil
+}
+
+// status reports the process state, taking the complete lines of unread output when read is set.
+// A trailing partial line is kept for the next read unless the process has finished.
+func (p *backgroundProcess) status(read bool) BackgroundProcessStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := BackgroundProcessStatus{
+		Handle:  p.handle,
+		Command: p.command,
+		PID:     p.cmd.Process.Pid,
+		Running: !p.finished,
+		Started: p.started.Format(time.RFC3339),
+	}
+	if p.finished {
+		code := p.cmd.ProcessState.ExitCode()
+		status.ExitCode = &code
+		var exitErr *exec.ExitError
+		if p.exitErr != nil && !errors.As(p.exitErr, &exitErr) {
+			status.Error = p.exitErr.Error()
+		}
+	}
+	if !read {
+		return status
+	}
+
+	end := len(p.unread)
+	if !p.finished {
+		end = strings.LastIndexByte(string(p.unread), '\n') + 1
+	}
+	text := strings.TrimSuffix(string(p.unread[:end]), "\n")
+	p.unread = append([]byte(nil), p.unread[end:]...)
+	status.Dropped, p.dropped = p.dropped, 0
+	if text != "" {
+		status.Lines = strings.Split(text, "\n")
+	}
+	return status
+}
+
+var (
+	backgroundProcesses      = make(map[string]*backgroundProcess)
+	backgroundProcessesMutex sync.Mutex
+	backgroundProcessCount   int
+)
+
+// RunBackground implements the run_background tool functionality
+func RunBackground(ctx context.Context, input json.RawMessage) (string, error) {
+	runInput := RunBackgroundInput{}
+	err := DecodeInput(input, &runInput)
+	if err != nil {
+		return "", err
+	}
+
+	if runInput.Command == "" {
+		return "", fmt.Errorf("command parameter is required")
+	}
+
+	// The process outlives this call, so it is not bound to ctx
+	cmd := exec.Command(runInput.Command, runInput.Args...)
+	cmd.Dir = workspaceDir(ctx)
+	if runInput.WorkingDir != "" {
+		cmd.Dir, err = ResolvePath(ctx, runInput.WorkingDir)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	backgroundProcessesMutex.Lock()
+	backgroundProcessCount++
+	handle := fmt.Sprintf("proc-%d", backgroundProcessCount)
+	backgroundProcessesMutex.Unlock()
+
+	process := &backgroundProcess{
+		handle:  handle,
+		command: strings.Join(append([]string{runInput.Command}, runInput.Args...), " "),
+		cmd:     cmd,
+		done:    make(chan struct{}),
+		notify:  make(chan struct{}, 1),
+	}
+	cmd.Stdout = process
+	cmd.Stderr = process
+	// Children that inherit the output pipes must not keep Wait from returning after a kill
+	cmd.WaitDelay = backgroundStopTimeout
+
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start %s: %w", runInput.Command, err)
+	}
+	process.started = time.Now()
+
+	go func() {
+		err := cmd.Wait()
+		process.mu.Lock()
+		process.exitErr = err
+		process.finished = true
+		process.mu.Unlock()
+		close(process.done)
+	}()
+
+	backgroundProcessesMutex.Lock()
+	backgroundProcesses[handle] = process
+	backgroundProcessesMutex.Unlock()
+
+	jsonOutput, err := json.MarshalIndent(process.status(false), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// ReadOutput implements the read_output tool functionality
+func ReadOutput(ctx context.Context, input json.RawMessage) (string, error) {
+	readInput := ReadOutputInput{}
+	err := DecodeInput(input, &readInput)
+	if err != nil {
+		return "", err
+	}
+
+	if readInput.Handle == "" {
+		jsonOutput, err := json.MarshalIndent(listBackgroundProcesses(), "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal output: %w", err)
+		}
+		return string(jsonOutput), nil
+	}
+
+	backgroundProcessesMutex.Lock()
+	process, ok := backgroundProcesses[readInput.Handle]
+	backgroundProcessesMutex.Unlock()
+	if !ok {
+		return "", fmt.Errorf("unknown handle: %s", readInput.Handle)
+	}
+
+	var filter *regexp.Regexp
+	if readInput.Filter != "" {
+		filter, err = regexp.Compile(readInput.Filter)
+		if err != nil {
+			return "", fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+
+	if wait := readOutputWait(readInput.WaitSeconds); wait > 0 && !hasUnreadLine(process) {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+	wait:
+		for !hasUnreadLine(process) {
+			select {
+			case <-process.notify:
+			case <-process.done:
+				break wait
+			case <-timer.C:
+				break wait
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+	}
+
+	if readInput.Stop {
+		stopBackgroundProcess(process)
+	}
+
+	status := process.status(true)
+	status.Stopped = readInput.Stop
+	if filter != nil {
+		var matched []string
+		for _, line := range status.Lines {
+			if filter.MatchString(line) {
+				matched = append(matched, line)
+			}
+		}
+		status.Filtered = len(status.Lines) - len(matched)
+		status.Lines = matched
+	}
+
+	jsonOutput, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// hasUnreadLine reports whether the process has a complete line of output waiting
+func hasUnreadLine(process *backgroundProcess) bool {
+	process.mu.Lock()
+	defer process.mu.Unlock()
+	return strings.IndexByte(string(process.unread), '\n') >= 0
+}
+
+// listBackgroundProcesses returns the status of every background process, by handle
+func listBackgroundProcesses() []BackgroundProcessStatus {
+	backgroundProcessesMutex.Lock()
+	processes := make([]*backgroundProcess, 0, len(backgroundProcesses))
+	for _, process := range backgroundProcesses {
+		processes = append(processes, process)
+	}
+	backgroundProcessesMutex.Unlock()
+
+	sort.Slice(processes, func(i, j int) bool {
+		return processes[i].started.Before(processes[j].started)
+	})
+	statuses := []BackgroundProcessStatus{}
+	for _, process := range processes {
+		statuses = append(statuses, process.status(false))
+	}
+	return statuses
+}
+
+// maxReadOutputWait bounds how long a single read_output call waits for new output
+const maxReadOutputWait = 300 * time.Second
+
+// readOutputWait converts the requested wait to a duration, capped at maxReadOutputWait
+func readOutputWait(seconds int) time.Duration {
+	// Compare in seconds so huge values can't overflow the duration
+	if seconds > int(maxReadOutputWait/time.Second) {
+		return maxReadOutputWait
+	}
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// backgroundStopTimeout bounds how long stopping waits for a killed process to exit
+const backgroundStopTimeout = 5 * time.Second
+
+// stopBackgroundProcess kills the process if it is still running and waits for it to exit
+func stopBackgroundProcess(process *backgroundProcess) {
+	select {
+	case <-process.done:
+		return
+	default:
+	}
+	_ = process.cmd.Process.Kill()
+	select {
+	case <-process.done:
+	case <-time.After(backgroundStopTimeout):
+	}
+}
+
+// StopBackgroundProcesses kills every process started by run_background that is still running.
+// It returns the handles of the processes it stopped.
+func StopBackgroundProcesses() []string {
+	backgroundProcessesMutex.Lock()
+	processes := make([]*backgroundProcess, 0, len(backgroundProcesses))
+	for _, process := range backgroundProcesses {
+		processes = append(processes, process)
+	}
+	backgroundProcessesMutex.Unlock()
+
+	var stopped []string
+	for _, process := range processes {
+		if process.status(false).Running {
+			stopBackgroundProcess(process)
+			stopped = append(stopped, process.handle)
+		}
+	}
+	sort.Strings(stopped)
+	return stopped
+}
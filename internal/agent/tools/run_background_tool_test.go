@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// startTestProcess runs script with sh in the background and stops it when the test ends
+func startTestProcess(t *testing.T, ctx context.Context, script string) string {
+	t.Helper()
+	var started BackgroundProcessStatus
+	callTool(t, ctx, RunBackground, RunBackgroundInput{Command: "sh", Args: []string{"-c", script}}, &started)
+	if !started.Running || started.PID == 0 {
+		t.Fatalf("got %+v, want a running process", started)
+	}
+	t.Cleanup(func() {
+		backgroundProcessesMutex.Lock()
+		process := backgroundProcesses[started.Handle]
+		delete(backgroundProcesses, started.Handle)
+		backgroundProcessesMutex.Unlock()
+		if process != nil {
+			stopBackgroundProcess(process)
+		}
+	})
+	return started.Handle
+}
+
+// readTestLines reads the process output until want lines have arrived
+func readTestLines(t *testing.T, ctx context.Context, input ReadOutputInput, want int) []string {
+	t.Helper()
+	var lines []string
+	deadline := time.Now().Add(10 * time.Second)
+	for len(lines) < want && time.Now().Before(deadline) {
+		var status BackgroundProcessStatus
+		input.WaitSeconds = 1
+		callTool(t, ctx, ReadOutput, input, &status)
+		lines = append(lines, status.Lines...)
+	}
+	return lines
+}
+
+func TestBackgroundOutputIsReadIncrementally(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	handle := startTestProcess(t, ctx, `echo first; echo second
+while [ ! -f release ]; do sleep 0.05; done
+echo third; echo noise; echo thirty
+exec sleep 30`)
+
+	if got := readTestLines(t, ctx, ReadOutputInput{Handle: handle}, 2); !reflect.DeepEqual(got, []string{"first", "second"}) {
+		t.Fatalf("first read = %v, want first and second", got)
+	}
+
+	writeTestFile(t, dir, "release", "")
+	if got := readTestLines(t, ctx, ReadOutputInput{Handle: handle, Filter: "^thir"}, 2); !reflect.DeepEqual(got, []string{"third", "thirty"}) {
+		t.Errorf("filtered read = %v, want only the new lines matching the filter", got)
+	}
+
+	var listed []BackgroundProcessStatus
+	callTool(t, ctx, ReadOutput, ReadOutputInput{}, &listed)
+	if len(listed) != 1 || listed[0].Handle != handle || !listed[0].Running {
+		t.Errorf("listed = %+v, want the running process", listed)
+	}
+
+	var stopped BackgroundProcessStatus
+	callTool(t, ctx, ReadOutput, ReadOutputInput{Handle: handle, Stop: true}, &stopped)
+	if !stopped.Stopped || stopped.Running || stopped.ExitCode == nil {
+		t.Errorf("got %+v, want the process stopped with an exit code", stopped)
+	}
+}
+
+func TestBackgroundProcessExitCode(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	handle := startTestProcess(t, ctx, "printf 'done'; exit 3")
+
+	if got := readTestLines(t, ctx, ReadOutputInput{Handle: handle}, 1); !reflect.DeepEqual(got, []string{"done"}) {
+		t.Errorf("lines = %v, want the trailing partial line once the process exits", got)
+	}
+	var status BackgroundProcessStatus
+	callTool(t, ctx, ReadOutput, ReadOutputInput{Handle: handle}, &status)
+	if status.Running || status.ExitCode == nil || *status.ExitCode != 3 {
+		t.Errorf("got %+v, want exit code 3", status)
+	}
+}
+
+func TestStopBackgroundProcesses(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	handle := startTestProcess(t, ctx, "exec sleep 30")
+
+	if stopped := StopBackgroundProcesses(); !reflect.DeepEqual(stopped, []string{handle}) {
+		t.Errorf("stopped = %v, want %s", stopped, handle)
+	}
+	if stopped := StopBackgroundProcesses(); len(stopped) != 0 {
+		t.Errorf("stopped = %v, want nothing left running", stopped)
+	}
+}
+
+func TestReadOutputUnknownHandle(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+
+	if _, err := ReadOutput(ctx, mustMarshal(t, ReadOutputInput{Handle: "proc-missing"})); err == nil {
+		t.Error("expected an error for an unknown handle")
+	}
+}
+
+func TestReadOutputWaitIsCapped(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    time.Duration
+	}{
+		{0, 0},
+		{-5, 0},
+		{30, 30 * time.Second},
+		{300, maxReadOutputWait},
+		{86400, maxReadOutputWait},
+		{math.MaxInt, maxReadOutputWait},
+	}
+	for _, tt := range tests {
+		if got := readOutputWait(tt.seconds); got != tt.want {
+			t.Errorf("readOutputWait(%d) = %s, want %s", tt.seconds, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunLinterToolDefinition defines the run_linter tool
+var RunLinterToolDefinition = ToolDefinition{
+	Name: "run_linter",
+	Description: `Run a configured linter and return its findings in one structured list.
+Linters are defined in configuration as a command template and an output format, so any linter
+can be plugged in: 'golangci-json' (golangci-lint's JSON output), 'checkstyle' (checkstyle XML, as
+produced by revive and many others), or 'text' (file:line:col: message lines, as go vet prints).
+Arguments may use {{path}}, which is replaced by 'path' (default './...'). If the linter's binary
+is not installed, the result says so instead of failing. Call with 'list' set to see the
+configured linters.`,
+	InputSchema:           RunLinterInputSchema,
+	Function:              RunLinter,
+	CountsTowardLoopLimit: true,
+}
+
+// RunLinterInput defines the input parameters for the run_linter tool
+type RunLinterInput struct {
+	Linter         string `json:"linter,omitempty" jsonschema_description:"Name of the configured linter to run" jsonschema_example:"golangci-lint"`
+	Path           string `json:"path,omitempty" jsonschema_description:"Package pattern or path substituted for {{path}}. Defaults to './...'."`
+	List           bool   `json:"list,omitempty" jsonschema_description:"If true, list the configured linters instead of running one"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema_description:"Deadline for the linter run. Defaults to 300, capped at 1800."`
+}
+
+// RunLinterInputSchema is the JSON schema for the run_linter tool
+var RunLinterInputSchema = GenerateSchema[RunLinterInput]()
+
+// Linter describes how to run a linter and read its output
+type Linter struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Command     []string `json:"command"`
+	Format      string   `json:"format"`
+}
+
+// LinterFinding is a single issue reported by a linter
+type LinterFinding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	Rule     string `json:"rule,omitempty"`
+	Message  string `json:"message"`
+}
+
+// RunLinterOutput represents the structured output of the run_linter tool
+type RunLinterOutput struct {
+	Linter    string          `json:"linter,omitempty"`
+	Command   string          `json:"command,omitempty"`
+	Installed bool            `json:"installed"`
+	Findings  []LinterFinding `json:"findings"`
+	ExitCode  int             `json:"exit_code,omitempty"`
+	Stderr    string          `json:"stderr,omitempty"`
+	Message   string          `json:"message,omitempty"`
+	Available []Linter        `json:"available,omitempty"`
+}
+
+// linterFormats lists the output formats run_linter can parse
+var linterFormats = map[string]bool{
+	"golangci-json": true,
+	"checkstyle":    true,
+	"text":          true,
+}
+
+const (
+	defaultLinterTimeout = 300 * time.Second
+	maxLinterTimeout     = 1800 * time.Second
+)
+
+var (
+	linters      = map[string]Linter{}
+	lintersMutex sync.RWMutex
+)
+
+// textFindingPattern matches file:line[:col]: message lines
+var textFindingPattern = regexp.MustCompile(`^(.+?\.\w+):(\d+)(?::(\d+))?:\s*(.+)$`)
+
+func init() {
+	if err := RegisterLinters(defaultLinters()); err != nil {
+		panic(err)
+	}
+}
+
+// defaultLinters returns the linters available without any configuration
+func defaultLinters() []Linter {
+	return []Linter{
+		{
+			Name:        "golangci-lint",
+			Description: "golangci-lint with the project's configuration",
+			Command:     []string{"golangci-lint", "run", "--output.json.path=stdout", "--show-stats=false", "{{path}}"},
+			Format:      "golangci-json",
+		},
+		{
+			Name:        "revive",
+			Description: "revive with its default rules",
+			Command:     []string{"revive", "-formatter", "checkstyle", "{{path}}"},
+			Format:      "checkstyle",
+		},
+		{
+			Name:        "go-vet",
+			Description: "go vet",
+			Command:     []string{"go", "vet", "{{path}}"},
+			Format:      "text",
+		},
+	}
+}
+
+// RegisterLinters validates and adds linters, replacing any existing linter with the same name
+func RegisterLinters(defs []Linter) error {
+	for _, linter := range defs {
+		if linter.Name == "" {
+			return fmt.Errorf("linter name cannot be empty")
+		}
+		if len(linter.Command) == 0 || linter.Command[0] == "" {
+			return fmt.Errorf("linter %q has no command", linter.Name)
+		}
+		if !linterFormats[linter.Format] {
+			return fmt.Errorf("linter %q has unknown format %q. Must be 'golangci-json', 'checkstyle', or 'text'", linter.Name, linter.Format)
+		}
+	}
+
+	lintersMutex.Lock()
+	defer lintersMutex.Unlock()
+	for _, linter := range defs {
+		linters[linter.Name] = linter
+	}
+	return nil
+}
+
+// LoadLinters reads linter definitions from a JSON file containing an array of linters
+func LoadLinters(path string) ([]Linter, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read linters file '%s': %w", path, err)
+	}
+	var defs []Linter
+	if err := json.Unmarshal(content, &defs); err != nil {
+		return nil, fmt.Errorf("failed to parse linters file '%s': %w", path, err)
+	}
+	return defs, nil
+}
+
+// lookupLinter returns the registered linter with the given name
+func lookupLinter(name string) (Linter, bool) {
+	lintersMutex.RLock()
+	defer lintersMutex.RUnlock()
+	linter, ok := linters[name]
+	return linter, ok
+}
+
+// listLinters returns all registered linters sorted by name
+func listLinters() []Linter {
+	lintersMutex.RLock()
+	defer lintersMutex.RUnlock()
+	list := make([]Linter, 0, len(linters))
+	for _, linter := range linters {
+		list = append(list, linter)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// RunLinter implements the run_linter tool functionality
+func RunLinter(ctx context.Context, input json.RawMessage) (string, error) {
+	linterInput := RunLinterInput{}
+	err := DecodeInput(input, &linterInput)
+	if err != nil {
+		return "", err
+	}
+
+	output := RunLinterOutput{Linter: linterInput.Linter, Findings: []LinterFinding{}}
+	if linterInput.List {
+		output.Available = listLinters()
+		return marshalRunLinterOutput(output)
+	}
+
+	if linterInput.Linter == "" {
+		return "", fmt.Errorf("linter parameter is required unless list is set")
+	}
+	linter, ok := lookupLinter(linterInput.Linter)
+	if !ok {
+		return "", fmt.Errorf("unknown linter: %s. Call with list to see the configured linters", linterInput.Linter)
+	}
+
+	path := linterInput.Path
+	if path == "" {
+		path = "./..."
+	}
+	if strings.HasPrefix(path, "-") {
+		return "", fmt.Errorf("invalid path: %s", path)
+	}
+	if dir := strings.TrimSuffix(path, "..."); dir != "" {
+		if _, err := ResolvePath(ctx, dir); err != nil {
+			return "", err
+		}
+	}
+	args := make([]string, len(linter.Command))
+	for i, arg := range linter.Command {
+		args[i] = strings.ReplaceAll(arg, "{{path}}", path)
+	}
+	output.Command = QuoteShellCommand(args...)
+
+	// A missing linter is reported, not treated as a failure
+	if _, err := exec.LookPath(args[0]); err != nil {
+		output.Message = fmt.Sprintf("%s is not installed or not on PATH; install it or configure another linter", args[0])
+		return marshalRunLinterOutput(output)
+	}
+	output.Installed = true
+
+	timeout := defaultLinterTimeout
+	if linterInput.TimeoutSeconds > 0 {
+		timeout = min(time.Duration(linterInput.TimeoutSeconds)*time.Second, maxLinterTimeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = workspaceDir(ctx)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("%s timed out after %s", linter.Name, timeout)
+	}
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		return "", fmt.Errorf("failed to run %s: %w", linter.Name, runErr)
+	}
+	output.ExitCode = cmd.ProcessState.ExitCode()
+
+	// Linters exit non-zero when they report findings, so only unparseable output is an error
+	switch linter.Format {
+	case "golangci-json":
+		output.Findings, err = parseGolangciJSON(stdout.Bytes())
+	case "checkstyle":
+		output.Findings, err = parseCheckstyle(stdout.Bytes())
+	case "text":
+		output.Findings = parseTextFindings(stdout.String() + "\n" + stderr.String())
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s exited with code %d and its output could not be parsed: %w\n%s",
+			linter.Name, output.ExitCode, err, strings.TrimSpace(stderr.String()))
+	}
+	if linter.Format != "text" {
+		output.Stderr = strings.TrimSpace(stderr.String())
+	}
+	output.Message = fmt.Sprintf("%s reported %d finding(s)", linter.Name, len(output.Findings))
+
+	return marshalRunLinterOutput(output)
+}
+
+// marshalRunLinterOutput renders the run_linter output as indented JSON
+func marshalRunLinterOutput(output RunLinterOutput) (string, error) {
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+	return string(jsonOutput), nil
+}
+
+// golangciReport is the part of golangci-lint's JSON output run_linter reads
+type golangciReport struct {
+	Issues []struct {
+		FromLinter string `json:"FromLinter"`
+		Text       string `json:"Text"`
+		Severity   string `json:"Severity"`
+		Pos        struct {
+			Filename string `json:"Filename"`
+			Line     int    `json:"Line"`
+			Column   int    `json:"Column"`
+		} `json:"Pos"`
+	} `json:"Issues"`
+}
+
+// parseGolangciJSON converts golangci-lint JSON output into findings
+func parseGolangciJSON(data []byte) ([]LinterFinding, error) {
+	findings := []LinterFinding{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return findings, nil
+	}
+
+	// Some versions print the JSON report followed by a text summary
+	var report golangciReport
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&report); err != nil {
+		return nil, fmt.Errorf("invalid golangci-lint JSON: %w", err)
+	}
+	for _, issue := range report.Issues {
+		findings = append(findings, LinterFinding{
+			File:     issue.Pos.Filename,
+			Line:     issue.Pos.Line,
+			Column:   issue.Pos.Column,
+			Severity: issue.Severity,
+			Rule:     issue.FromLinter,
+			Message:  issue.Text,
+		})
+	}
+	return findings, nil
+}
+
+// checkstyleReport is a checkstyle XML report
+type checkstyleReport struct {
+	Files []struct {
+		Name   string `xml:"name,attr"`
+		Errors []struct {
+			Line     string `xml:"line,attr"`
+			Column   string `xml:"column,attr"`
+			Severity string `xml:"severity,attr"`
+			Message  string `xml:"message,attr"`
+			Source   string `xml:"source,attr"`
+		} `xml:"error"`
+	} `xml:"file"`
+}
+
+// parseCheckstyle converts a checkstyle XML report into findings
+func parseCheckstyle(data []byte) ([]LinterFinding, error) {
+	findings := []LinterFinding{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return findings, nil
+	}
+
+	var report checkstyleReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("invalid checkstyle XML: %w", err)
+	}
+	for _, file := range report.Files {
+		for _, e := range file.Errors {
+			line, _ := strconv.Atoi(e.Line)
+			column, _ := strconv.Atoi(e.Column)
+			findings = append(findings, LinterFinding{
+				File:     file.Name,
+				Line:     line,
+				Column:   column,
+				Severity: e.Severity,
+				Rule:     e.Source,
+				Message:  e.Message,
+			})
+		}
+	}
+	return findings, nil
+}
+
+// parseTextFindings extracts file:line[:col]: message lines, skipping everything else
+func parseTextFindings(text string) []LinterFinding {
+	findings := []LinterFinding{}
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		match := textFindingPattern.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(match[2])
+		column, _ := strconv.Atoi(match[3])
+		findings = append(findings, LinterFinding{
+			File:    strings.TrimPrefix(match[1], "vet: "),
+			Line:    line,
+			Column:  column,
+			Message: match[4],
+		})
+	}
+	return findings
+}
@@ -0,0 +1,158 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+const golangciOutput = `{"Issues":[
+{"FromLinter":"errcheck","Text":"Error return value of ` + "`f.Close`" + ` is not checked","Severity":"","Pos":{"Filename":"internal/store/store.go","Offset":120,"Line":14,"Column":9}},
+{"FromLinter":"unused","Text":"func ` + "`helper`" + ` is unused","Severity":"warning","Pos":{"Filename":"main.go","Offset":40,"Line":3,"Column":6}}
+],"Report":{"Linters":[{"Name":"errcheck","Enabled":true}]}}
+0 issues.`
+
+func TestParseGolangciJSON(t *testing.T) {
+	findings, err := parseGolangciJSON([]byte(golangciOutput))
+	if err != nil {
+		t.Fatalf("parseGolangciJSON: %v", err)
+	}
+
+	want := []LinterFinding{
+		{File: "internal/store/store.go", Line: 14, Column: 9, Rule: "errcheck", Message: "Error return value of `f.Close` is not checked"},
+		{File: "main.go", Line: 3, Column: 6, Severity: "warning", Rule: "unused", Message: "func `helper` is unused"},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("got %d findings, want %d: %+v", len(findings), len(want), findings)
+	}
+	for i := range want {
+		if findings[i] != want[i] {
+			t.Errorf("finding %d = %+v, want %+v", i, findings[i], want[i])
+		}
+	}
+}
+
+func TestParseGolangciJSONEmptyAndInvalid(t *testing.T) {
+	findings, err := parseGolangciJSON([]byte("  \n"))
+	if err != nil || len(findings) != 0 {
+		t.Errorf("empty output: got %v, %v; want no findings", findings, err)
+	}
+	findings, err = parseGolangciJSON([]byte(`{"Issues":null}`))
+	if err != nil || len(findings) != 0 {
+		t.Errorf("no issues: got %v, %v; want no findings", findings, err)
+	}
+	if _, err := parseGolangciJSON([]byte("level=error msg=\"no go files\"")); err == nil {
+		t.Error("expected an error for non-JSON output")
+	}
+}
+
+func TestParseCheckstyle(t *testing.T) {
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="5.0">
+  <file name="pkg/a.go">
+    <error line="7" column="2" message="exported function Run should have comment" severity="warning" source="revive/exported"></error>
+  </file>
+  <file name="pkg/b.go"></file>
+</checkstyle>`
+
+	findings, err := parseCheckstyle([]byte(report))
+	if err != nil {
+		t.Fatalf("parseCheckstyle: %v", err)
+	}
+	want := LinterFinding{File: "pkg/a.go", Line: 7, Column: 2, Severity: "warning", Rule: "revive/exported", Message: "exported function Run should have comment"}
+	if len(findings) != 1 || findings[0] != want {
+		t.Errorf("got %+v, want [%+v]", findings, want)
+	}
+}
+
+func TestParseTextFindings(t *testing.T) {
+	text := "# metamorph/pkg\nvet: pkg/a.go:12:3: unreachable code\npkg/b.go:4: printf format %d has arg of wrong type\nexit status 1\n"
+
+	findings := parseTextFindings(text)
+	want := []LinterFinding{
+		{File: "pkg/a.go", Line: 12, Column: 3, Message: "unreachable code"},
+		{File: "pkg/b.go", Line: 4, Message: "printf format %d has arg of wrong type"},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("got %+v, want %+v", findings, want)
+	}
+	for i := range want {
+		if findings[i] != want[i] {
+			t.Errorf("finding %d = %+v, want %+v", i, findings[i], want[i])
+		}
+	}
+}
+
+func TestRegisterLintersRejectsInvalidDefinitions(t *testing.T) {
+	tests := []struct {
+		name   string
+		linter Linter
+	}{
+		{"missing name", Linter{Command: []string{"lint"}, Format: "text"}},
+		{"missing command", Linter{Name: "lint", Format: "text"}},
+		{"unknown format", Linter{Name: "lint", Command: []string{"lint"}, Format: "sarif"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterLinters([]Linter{tt.linter}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestRunLinterMissingBinary(t *testing.T) {
+	if err := RegisterLinters([]Linter{{Name: "test-missing", Command: []string{"metamorph-no-such-linter", "{{path}}"}, Format: "text"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := RunLinter(context.Background(), json.RawMessage(`{"linter": "test-missing"}`))
+	if err != nil {
+		t.Fatalf("RunLinter returned an error for a missing binary: %v", err)
+	}
+	var output RunLinterOutput
+	if err := json.Unmarshal([]byte(result), &output); err != nil {
+		t.Fatal(err)
+	}
+	if output.Installed || output.Message == "" {
+		t.Errorf("expected a not-installed result, got %+v", output)
+	}
+}
+
+func TestRunLinterParsesConfiguredCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the linter")
+	}
+
+	// A fake linter that prints golangci-lint JSON and exits non-zero, as real linters do
+	bin := t.TempDir()
+	script := "#!/bin/sh\ncat <<'EOF'\n" + golangciOutput + "\nEOF\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(bin, "fake-golangci"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	if err := RegisterLinters([]Linter{{Name: "test-fake", Command: []string{"fake-golangci", "run", "{{path}}"}, Format: "golangci-json"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := RunLinter(context.Background(), json.RawMessage(`{"linter": "test-fake"}`))
+	if err != nil {
+		t.Fatalf("RunLinter: %v", err)
+	}
+	var output RunLinterOutput
+	if err := json.Unmarshal([]byte(result), &output); err != nil {
+		t.Fatal(err)
+	}
+	if !output.Installed || output.ExitCode != 1 {
+		t.Errorf("got installed=%v exit=%d, want installed with exit code 1", output.Installed, output.ExitCode)
+	}
+	if output.Command != "fake-golangci run ./..." {
+		t.Errorf("command = %q, want {{path}} replaced by ./...", output.Command)
+	}
+	if len(output.Findings) != 2 || output.Findings[0].Rule != "errcheck" {
+		t.Errorf("unexpected findings: %+v", output.Findings)
+	}
+}
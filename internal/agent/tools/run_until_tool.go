@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// RunUntilToolDefinition defines the run_until tool
+var RunUntilToolDefinition = ToolDefinition{
+	Name: "run_until",
+	Description: `Run a command repeatedly until it succeeds, e.g. to wait for a service to come up.
+The command runs without a shell: pass the program in 'command' and its arguments in 'args'.
+It is retried every 'interval_seconds' (default 2) until it exits with status 0, 'max_attempts'
+(default 10, capped at 100) attempts have been made, or 'timeout_seconds' (default 60, capped at 600)
+has elapsed. Returns the number of attempts and the output and exit code of the last attempt.`,
+	InputSchema:           RunUntilInputSchema,
+	Function:              RunUntil,
+	CountsTowardLoopLimit: true,
+}
+
+// RunUntilInput defines the input parameters for the run_until tool
+type RunUntilInput struct {
+	Command         string   `json:"command" jsonschema_required:"true" jsonschema_description:"Program to run" jsonschema_example:"curl"`
+	Args            []string `json:"args,omitempty" jsonschema_description:"Arguments for the program" jsonschema_example:"[\"-sf\", \"http://localhost:8080/health\"]"`
+	WorkingDir      string   `json:"working_dir,omitempty" jsonschema_description:"Working directory (defaults to current directory if empty)"`
+	IntervalSeconds float64  `json:"interval_seconds,omitempty" jsonschema_description:"Delay between attempts. Defaults to 2."`
+	MaxAttempts     int      `json:"max_attempts,omitempty" jsonschema_description:"Maximum number of attempts. Defaults to 10, capped at 100."`
+	TimeoutSeconds  int      `json:"timeout_seconds,omitempty" jsonschema_description:"Overall deadline for all attempts. Defaults to 60, capped at 600."`
+}
+
+// RunUntilInputSchema is the JSON schema for the run_until tool
+var RunUntilInputSchema = GenerateSchema[RunUntilInput]()
+
+// RunUntilOutput represents the structured output of the run_until tool
+type RunUntilOutput struct {
+	Command    string `json:"command"`
+	Success    bool   `json:"success"`
+	Attempts   int    `json:"attempts"`
+	ExitCode   int    `json:"exit_code"`
+	Output     string `json:"output"`
+	Elapsed    string `json:"elapsed"`
+	StopReason string `json:"stop_reason"`
+}
+
+const (
+	defaultRunUntilInterval    = 2 * time.Second
+	defaultRunUntilMaxAttempts = 10
+	maxRunUntilMaxAttempts     = 100
+	defaultRunUntilTimeout     = 60 * time.Second
+	maxRunUntilTimeout         = 600 * time.Second
+	maxRunUntilOutput          = 64 * 1024
+)
+
+// RunUntil implements the run_until tool functionality
+func RunUntil(ctx context.Context, input json.RawMessage) (string, error) {
+	runInput := RunUntilInput{}
+	err := DecodeInput(input, &runInput)
+	if err != nil {
+		return "", err
+	}
+
+	if runInput.Command == "" {
+		return "", fmt.Errorf("command parameter is required")
+	}
+
+	dir := workspaceDir(ctx)
+	if runInput.WorkingDir != "" {
+		dir, err = ResolvePath(ctx, runInput.WorkingDir)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	interval := defaultRunUntilInterval
+	if runInput.IntervalSeconds > 0 {
+		interval = time.Duration(runInput.IntervalSeconds * float64(time.Second))
+	}
+	maxAttempts := defaultRunUntilMaxAttempts
+	if runInput.MaxAttempts > 0 {
+		maxAttempts = min(runInput.MaxAttempts, maxRunUntilMaxAttempts)
+	}
+	timeout := defaultRunUntilTimeout
+	if runInput.TimeoutSeconds > 0 {
+		timeout = min(time.Duration(runInput.TimeoutSeconds)*time.Second, maxRunUntilTimeout)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output := RunUntilOutput{
+		Command: QuoteShellCommand(append([]string{runInput.Command}, runInput.Args...)...),
+	}
+	start := time.Now()
+
+retry:
+	for {
+		output.Attempts++
+		output.ExitCode, output.Output, err = runAttempt(ctx, dir, runInput.Command, runInput.Args)
+		if err != nil {
+			return "", err
+		}
+		if output.ExitCode == 0 {
+			output.Success = true
+			output.StopReason = "succeeded"
+			break
+		}
+		if ctx.Err() != nil {
+			output.StopReason = stopReasonFor(ctx)
+			break
+		}
+		if output.Attempts >= maxAttempts {
+			output.StopReason = "max_attempts"
+			break
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			output.StopReason = stopReasonFor(ctx)
+			break retry
+		case <-timer.C:
+		}
+	}
+	output.Elapsed = time.Since(start).Round(time.Millisecond).String()
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// runAttempt runs the command once and returns its exit code and combined output, keeping only
+// the tail of very long output. Failing to start the program is returned as an error.
+func runAttempt(ctx context.Context, dir, command string, args []string) (int, string, error) {
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = dir
+	var combined bytes.Buffer
+	cmd.Stdout = &combined
+	cmd.Stderr = &combined
+	cmd.WaitDelay = backgroundStopTimeout
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) && ctx.Err() == nil {
+		return 0, "", fmt.Errorf("failed to run %s: %w", command, err)
+	}
+
+	out := combined.Bytes()
+	if len(out) > maxRunUntilOutput {
+		out = out[len(out)-maxRunUntilOutput:]
+	}
+	exitCode := -1
+	if cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+	return exitCode, string(out), nil
+}
+
+// stopReasonFor explains why ctx ended: the deadline passed or the caller cancelled
+func stopReasonFor(ctx context.Context) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "timeout"
+	}
+	return "cancelled"
+}
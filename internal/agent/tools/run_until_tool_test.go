@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// flakyScript fails until it has been run three times, counting attempts in a file
+const flakyScript = `n=$(cat attempts 2>/dev/null || echo 0); n=$((n+1)); echo $n > attempts; echo "attempt $n"; [ $n -ge 3 ]`
+
+func TestRunUntilRetriesUntilSuccess(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+
+	var output RunUntilOutput
+	callTool(t, ctx, RunUntil, RunUntilInput{Command: "sh", Args: []string{"-c", flakyScript}, IntervalSeconds: 0.01}, &output)
+
+	if !output.Success || output.Attempts != 3 || output.StopReason != "succeeded" {
+		t.Errorf("got %+v, want success on the third attempt", output)
+	}
+	if output.ExitCode != 0 || output.Output != "attempt 3\n" {
+		t.Errorf("exit code %d output %q, want the final attempt's result", output.ExitCode, output.Output)
+	}
+}
+
+func TestRunUntilStopsAtMaxAttempts(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+
+	var output RunUntilOutput
+	callTool(t, ctx, RunUntil, RunUntilInput{Command: "sh", Args: []string{"-c", flakyScript}, IntervalSeconds: 0.01, MaxAttempts: 2}, &output)
+
+	if output.Success || output.Attempts != 2 || output.StopReason != "max_attempts" || output.ExitCode != 1 {
+		t.Errorf("got %+v, want two failed attempts", output)
+	}
+	if got := readTestFile(t, filepath.Join(dir, "attempts")); got != "2\n" {
+		t.Errorf("the command ran %q times, want 2", got)
+	}
+}
+
+func TestRunUntilTimeout(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+
+	var output RunUntilOutput
+	callTool(t, ctx, RunUntil, RunUntilInput{Command: "false", IntervalSeconds: 0.2, MaxAttempts: 100, TimeoutSeconds: 1}, &output)
+
+	if output.Success || output.StopReason != "timeout" || output.Attempts < 2 || output.Attempts > 6 {
+		t.Errorf("got %+v, want the deadline to end the retries", output)
+	}
+}
+
+func TestRunUntilHonorsCancellation(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	var output RunUntilOutput
+	callTool(t, ctx, RunUntil, RunUntilInput{Command: "sleep", Args: []string{"5"}}, &output)
+
+	if output.Success || output.Attempts != 1 || output.StopReason != "cancelled" {
+		t.Errorf("got %+v, want a single cancelled attempt", output)
+	}
+}
+
+func TestRunUntilMissingProgram(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+
+	_, err := RunUntil(ctx, mustMarshal(t, RunUntilInput{Command: "metamorph-no-such-program"}))
+	if err == nil || !strings.Contains(err.Error(), "failed to run") {
+		t.Errorf("got %v, want a start failure reported as an error", err)
+	}
+}
@@ -0,0 +1,308 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ScanSecretsToolDefinition defines the scan_secrets tool
+var ScanSecretsToolDefinition = ToolDefinition{
+	Name: "scan_secrets",
+	Description: `Scan files, or the lines added in the staged diff, for secrets before they are committed.
+Detects known credential formats (AWS access keys and secret keys, private key blocks, GitHub, Slack,
+Stripe and Google API tokens, JWTs), passwords and tokens assigned to suggestively named variables,
+and long high-entropy string literals. Each finding has its file, line, type and a masked value.
+Values or paths matching an 'allowlist' regular expression are skipped, as are lines containing
+'secrets:allow'. git_operations runs this scan on the staged diff when 'scan_secrets' is set on a commit.`,
+	InputSchema: ScanSecretsInputSchema,
+	Function:    ScanSecrets,
+}
+
+// ScanSecretsInput defines the input parameters for the scan_secrets tool
+type ScanSecretsInput struct {
+	Path      string   `json:"path,omitempty" jsonschema_description:"File or directory to scan recursively. Defaults to the current directory. Ignored when 'staged' is set."`
+	Staged    bool     `json:"staged,omitempty" jsonschema_description:"If true, scan only the lines added in 'git diff --cached'"`
+	Allowlist []string `json:"allowlist,omitempty" jsonschema_description:"Regular expressions; findings whose value or file path matches one are skipped" jsonschema_example:"[\"testdata/\", \"^sk_test_\"]"`
+}
+
+// ScanSecretsInputSchema is the JSON schema for the scan_secrets tool
+var ScanSecretsInputSchema = GenerateSchema[ScanSecretsInput]()
+
+// SecretFinding is a likely secret found in a file
+type SecretFinding struct {
+	File  string `json:"file"`
+	Line  int    `json:"line"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// ScanSecretsOutput represents the structured output of the scan_secrets tool
+type ScanSecretsOutput struct {
+	FilesScanned int             `json:"files_scanned"`
+	Findings     []SecretFinding `json:"findings"`
+}
+
+// secretPattern is a known secret format; the value is submatch Group of Pattern
+type secretPattern struct {
+	Type    string
+	Pattern *regexp.Regexp
+	Group   int
+}
+
+// secretPatterns lists the known secret formats, most specific first
+var secretPatterns = []secretPattern{
+	{"private_key", regexp.MustCompile(`-----BEGIN ((?:[A-Z]+ )*PRIVATE KEY(?: BLOCK)?)-----`), 1},
+	{"aws_access_key_id", regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`), 1},
+	{"aws_secret_access_key", regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|key).{0,20}?['"]?\s*[:=]\s*['"]?([A-Za-z0-9/+=]{40})\b`), 1},
+	{"github_token", regexp.MustCompile(`\b((?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{60,})\b`), 1},
+	{"slack_token", regexp.MustCompile(`\b(xox[abposr]-[A-Za-z0-9-]{10,})`), 1},
+	{"stripe_key", regexp.MustCompile(`\b((?:sk|rk)_live_[0-9A-Za-z]{24,})\b`), 1},
+	{"google_api_key", regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})\b`), 1},
+	{"jwt", regexp.MustCompile(`\b(eyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,})`), 1},
+	{"password_assignment", regexp.MustCompile(`(?i)(?:password|passwd|secret|token|api_?key|access_?key)["']?\s*(?::=|=|:)\s*["']([^"'\s]{8,})["']`), 1},
+}
+
+// stringLiteralPattern matches quoted strings that may hold a high-entropy secret
+var stringLiteralPattern = regexp.MustCompile("[\"'`]([A-Za-z0-9+/=_-]{24,})[\"'`]")
+
+// defaultSecretAllowlist skips common placeholders and documentation examples
+var defaultSecretAllowlist = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)example|placeholder|dummy|changeme|your[_-]?`),
+}
+
+// secretScanSkippedFiles lists files full of checksums that are never secrets
+var secretScanSkippedFiles = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"Cargo.lock":        true,
+	"poetry.lock":       true,
+}
+
+// secretAllowMarker on a line suppresses findings on it
+const secretAllowMarker = "secrets:allow"
+
+// minSecretEntropy is the Shannon entropy in bits per character above which a string literal
+// is reported as a possible secret
+const minSecretEntropy = 4.5
+
+// ScanSecrets implements the scan_secrets tool functionality
+func ScanSecrets(ctx context.Context, input json.RawMessage) (string, error) {
+	scanInput := ScanSecretsInput{}
+	err := DecodeInput(input, &scanInput)
+	if err != nil {
+		return "", err
+	}
+
+	var allowlist []*regexp.Regexp
+	for _, expr := range scanInput.Allowlist {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return "", fmt.Errorf("invalid allowlist pattern %q: %w", expr, err)
+		}
+		allowlist = append(allowlist, re)
+	}
+
+	var output ScanSecretsOutput
+	if scanInput.Staged {
+		output, err = scanStagedSecrets(ctx, allowlist)
+	} else {
+		root := workspaceDir(ctx)
+		if scanInput.Path != "" {
+			root, err = ResolvePath(ctx, scanInput.Path)
+			if err != nil {
+				return "", err
+			}
+		}
+		output, err = scanFileSecrets(ctx, root, allowlist)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// scanFileSecrets scans root, a file or a directory walked recursively
+func scanFileSecrets(ctx context.Context, root string, allowlist []*regexp.Regexp) (ScanSecretsOutput, error) {
+	output := ScanSecretsOutput{Findings: []SecretFinding{}}
+	ignoreRules := LoadIgnoreRules(workspaceDir(ctx))
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if ignoreRules.IgnoredPath(path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor" || info.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || secretScanSkippedFiles[info.Name()] {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if isBinary(content) {
+			return nil
+		}
+		output.FilesScanned++
+		for i, line := range strings.Split(string(content), "\n") {
+			output.Findings = append(output.Findings, scanLineSecrets(path, i+1, line, allowlist)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return ScanSecretsOutput{}, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+	return output, nil
+}
+
+// stagedHunkPattern captures the first new line number of a unified diff hunk header
+var stagedHunkPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// scanStagedSecrets scans the lines added in the staged diff
+func scanStagedSecrets(ctx context.Context, allowlist []*regexp.Regexp) (ScanSecretsOutput, error) {
+	diff, err := gitCommand(ctx, "diff", "--cached", "--no-color", "--no-ext-diff", "-U0").CombinedOutput()
+	if err != nil {
+		return ScanSecretsOutput{}, fmt.Errorf("git diff failed: %s, %w", string(diff), err)
+	}
+
+	output := ScanSecretsOutput{Findings: []SecretFinding{}}
+	file := ""
+	lineNumber := 0
+	for _, line := range strings.Split(string(diff), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			if file == "/dev/null" || secretScanSkippedFiles[filepath.Base(file)] {
+				file = ""
+			} else {
+				output.FilesScanned++
+			}
+		case strings.HasPrefix(line, "@@"):
+			if matches := stagedHunkPattern.FindStringSubmatch(line); matches != nil {
+				lineNumber, _ = strconv.Atoi(matches[1])
+			}
+		case strings.HasPrefix(line, "+") && file != "":
+			output.Findings = append(output.Findings, scanLineSecrets(file, lineNumber, line[1:], allowlist)...)
+			lineNumber++
+		}
+	}
+	return output, nil
+}
+
+// scanLineSecrets returns the secrets found on one line, at most one per value
+func scanLineSecrets(file string, lineNumber int, line string, allowlist []*regexp.Regexp) []SecretFinding {
+	if strings.Contains(line, secretAllowMarker) {
+		return nil
+	}
+
+	var findings []SecretFinding
+	seen := map[string]bool{}
+	report := func(kind, value string) {
+		if seen[value] || secretAllowed(file, value, allowlist) {
+			return
+		}
+		seen[value] = true
+		findings = append(findings, SecretFinding{File: file, Line: lineNumber, Type: kind, Value: maskSecret(value)})
+	}
+
+	for _, pattern := range secretPatterns {
+		for _, matches := range pattern.Pattern.FindAllStringSubmatch(line, -1) {
+			report(pattern.Type, matches[pattern.Group])
+		}
+	}
+	for _, matches := range stringLiteralPattern.FindAllStringSubmatch(line, -1) {
+		if value := matches[1]; !seen[value] && shannonEntropy(value) >= minSecretEntropy {
+			report("high_entropy_string", value)
+		}
+	}
+	return findings
+}
+
+// secretAllowed reports whether value, found in file, matches the default or the given allowlist
+func secretAllowed(file, value string, allowlist []*regexp.Regexp) bool {
+	// Values such as 'xxxxxxxx' or '********' are placeholders
+	if shannonEntropy(value) < 1 {
+		return true
+	}
+	for _, re := range defaultSecretAllowlist {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	for _, re := range allowlist {
+		if re.MatchString(value) || re.MatchString(filepath.ToSlash(file)) {
+			return true
+		}
+	}
+	return false
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// maskSecret keeps the first four characters of value so it can be recognized, hiding the rest
+func maskSecret(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 8 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:4]) + strings.Repeat("*", min(len(runes)-4, 16))
+}
+
+// secretsError formats findings as an error, or returns nil when there are none
+func secretsError(findings []SecretFinding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	var problems []string
+	for _, f := range findings {
+		problems = append(problems, fmt.Sprintf("%s:%d: %s %s", f.File, f.Line, f.Type, f.Value))
+	}
+	return fmt.Errorf("staged changes contain possible secrets; remove them or mark the line with '%s':\n%s",
+		secretAllowMarker, strings.Join(problems, "\n"))
+}
@@ -2,10 +2,12 @@ package tools
 
 import (
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"metamorph/internal/logger"
 	"net/http"
 	"net/url"
 	"os"
@@ -14,16 +16,18 @@ import (
 
 // SearchWebToolDefinition defines the web search tool, currently implemented using Brave's Search API
 var SearchWebToolDefinition = ToolDefinition{
-	Name:        "search_web",
-	Description: "Search the web using Brave Search API. Requires BRAVE_API_KEY environment variable. Returns search results as a JSON string with title, URL, and description.",
-	InputSchema: WebSearchInputSchema,
-	Function:    SearchWeb,
+	Name:                  "search_web",
+	Description:           "Search the web using Brave Search API. Requires BRAVE_API_KEY environment variable. Returns search results as a JSON string with title, URL, and description. Set format to 'text' for a compact readable list, or 'both' for the list followed by the JSON.",
+	InputSchema:           WebSearchInputSchema,
+	Function:              SearchWeb,
+	CountsTowardLoopLimit: true,
 }
 
 // WebSearchInput defines the input parameters for the search_web tool
 type WebSearchInput struct {
-	Query      string `json:"query" jsonschema_description:"Search query."`
+	Query      string `json:"query" jsonschema_required:"true" jsonschema_description:"Search query." jsonschema_example:"golang context cancellation"`
 	NumResults int    `json:"num_results,omitempty" jsonschema_description:"Optional number of results to return. Default is 5, maximum is 20."`
+	Format     string `json:"format,omitempty" jsonschema_description:"Optional output format: 'json' (default), 'text' for a readable list, or 'both'."`
 }
 
 // WebSearchInputSchema is the JSON schema for the search_web tool
@@ -46,18 +50,23 @@ type SearchResponse struct {
 }
 
 // SearchWeb implements the search_web tool functionality using Brave Search API
-func SearchWeb(input json.RawMessage) (string, error) {
+func SearchWeb(ctx context.Context, input json.RawMessage) (string, error) {
 	// Parse input
 	searchInput := WebSearchInput{}
-	err := json.Unmarshal(input, &searchInput)
+	err := DecodeInput(input, &searchInput)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse search input: %v", err)
+		return "", err
 	}
 
 	// Validate input
 	if searchInput.Query == "" {
 		return "", errors.New("search query cannot be empty")
 	}
+	switch searchInput.Format {
+	case "", "json", "text", "both":
+	default:
+		return "", fmt.Errorf("invalid format: %s. Must be 'json', 'text', or 'both'", searchInput.Format)
+	}
 
 	// Set default number of results if not specified or if it exceeds maximum
 	if searchInput.NumResults <= 0 {
@@ -83,8 +92,13 @@ func SearchWeb(input json.RawMessage) (string, error) {
 
 	requestURL := baseURL + "?" + params.Encode()
 
+	logger.FromContext(ctx).Debug().
+		Str("query", searchInput.Query).
+		Int("numResults", searchInput.NumResults).
+		Msg("Searching the web")
+
 	// Create a new request
-	req, err := http.NewRequest("GET", requestURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 	if err != nil {
 		return createErrorResponse(searchInput.Query, fmt.Sprintf("Failed to create request: %v", err)), nil
 	}
@@ -105,6 +119,10 @@ func SearchWeb(input json.RawMessage) (string, error) {
 	// Check for HTTP errors
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
+		logger.FromContext(ctx).Warn().
+			Int("statusCode", resp.StatusCode).
+			Str("query", searchInput.Query).
+			Msg("Search API returned an error")
 		return createErrorResponse(searchInput.Query, fmt.Sprintf("Search API returned error code %d: %s", resp.StatusCode, string(bodyBytes))), nil
 	}
 
@@ -251,9 +269,37 @@ func SearchWeb(input json.RawMessage) (string, error) {
 		return createErrorResponse(searchInput.Query, fmt.Sprintf("Failed to format results: %v", err)), nil
 	}
 
+	switch searchInput.Format {
+	case "text":
+		return formatSearchResults(searchResponse), nil
+	case "both":
+		return formatSearchResults(searchResponse) + "\n" + string(resultJSON), nil
+	}
+
 	return string(resultJSON), nil
 }
 
+// formatSearchResults renders a search response as a numbered, human-readable list
+func formatSearchResults(response SearchResponse) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Search results for %q (%d):\n", response.Query, response.TotalResults)
+	if response.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", response.Error)
+	}
+
+	for i, result := range response.Results {
+		fmt.Fprintf(&b, "\n%d. %s\n   %s\n", i+1, result.Title, result.URL)
+		if result.Source != "" {
+			fmt.Fprintf(&b, "   Source: %s\n", result.Source)
+		}
+		if result.Description != "" {
+			fmt.Fprintf(&b, "   %s\n", truncateString(result.Description, 200))
+		}
+	}
+
+	return b.String()
+}
+
 // Helper function to create error responses
 func createErrorResponse(query string, errorMsg string) string {
 	errorResponse := SearchResponse{
@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFormatSearchResults(t *testing.T) {
+	response := SearchResponse{
+		Query: "go generics",
+		Results: []SearchResult{
+			{Title: "Tutorial: Getting started with generics", URL: "https://go.dev/doc/tutorial/generics", Description: "This tutorial introduces the basics of generics in Go."},
+			{Title: "Go 1.18 is released", URL: "https://go.dev/blog/go1.18", Description: strings.Repeat("x", 300), Source: "go.dev"},
+		},
+		TotalResults: 2,
+	}
+
+	text := formatSearchResults(response)
+	for _, want := range []string{
+		`Search results for "go generics" (2):`,
+		"1. Tutorial: Getting started with generics\n   https://go.dev/doc/tutorial/generics\n",
+		"2. Go 1.18 is released\n   https://go.dev/blog/go1.18\n",
+		"   Source: go.dev\n",
+		"   " + strings.Repeat("x", 200) + "...\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatted results are missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, strings.Repeat("x", 201)) {
+		t.Error("long descriptions should be truncated")
+	}
+}
+
+func TestFormatSearchResultsError(t *testing.T) {
+	text := formatSearchResults(SearchResponse{Query: "nothing", Error: "No results found in the API response"})
+	if !strings.Contains(text, "Error: No results found in the API response") {
+		t.Errorf("formatted results don't report the error:\n%s", text)
+	}
+}
+
+func TestSearchWebRejectsUnknownFormat(t *testing.T) {
+	_, err := SearchWeb(context.Background(), []byte(`{"query": "go", "format": "xml"}`))
+	if err == nil || !strings.Contains(err.Error(), "invalid format") {
+		t.Errorf("expected an invalid format error, got %v", err)
+	}
+}
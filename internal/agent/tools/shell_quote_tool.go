@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ShellQuoteToolDefinition defines the shell_quote tool
+var ShellQuoteToolDefinition = ToolDefinition{
+	Name: "shell_quote",
+	Description: `Quote and escape arguments so they are passed literally to a shell.
+Given a list of arguments, returns each one quoted for the target shell plus the joined command line.
+Arguments containing spaces, quotes, '$', backticks, globs, or other special characters are
+wrapped so the shell performs no expansion on them. Supported shells: 'posix' (sh, bash, zsh; default)
+and 'powershell'.`,
+	InputSchema: ShellQuoteInputSchema,
+	Function:    ShellQuote,
+}
+
+// ShellQuoteInput defines the input parameters for the shell_quote tool
+type ShellQuoteInput struct {
+	Args  []string `json:"args" jsonschema_required:"true" jsonschema_description:"Arguments to quote" jsonschema_example:"[\"grep\",\"-r\",\"hello world\",\".\"]"`
+	Shell string   `json:"shell,omitempty" jsonschema_description:"Target shell: 'posix' (default) or 'powershell'"`
+}
+
+// ShellQuoteInputSchema is the JSON schema for the shell_quote tool
+var ShellQuoteInputSchema = GenerateSchema[ShellQuoteInput]()
+
+// ShellQuoteOutput represents the structured output of the shell_quote tool
+type ShellQuoteOutput struct {
+	Shell   string   `json:"shell"`
+	Quoted  []string `json:"quoted"`
+	Command string   `json:"command"`
+}
+
+// ShellQuote implements the shell_quote tool functionality
+func ShellQuote(ctx context.Context, input json.RawMessage) (string, error) {
+	quoteInput := ShellQuoteInput{}
+	err := json.Unmarshal(input, &quoteInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if len(quoteInput.Args) == 0 {
+		return "", fmt.Errorf("args cannot be empty")
+	}
+
+	shell := quoteInput.Shell
+	if shell == "" {
+		shell = "posix"
+	}
+	if shell != "posix" && shell != "powershell" {
+		return "", fmt.Errorf("invalid shell: %s. Must be 'posix' or 'powershell'", shell)
+	}
+
+	output := ShellQuoteOutput{Shell: shell, Quoted: make([]string, len(quoteInput.Args))}
+	for i, arg := range quoteInput.Args {
+		output.Quoted[i] = QuoteShellArg(arg, shell)
+	}
+	output.Command = strings.Join(output.Quoted, " ")
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// shellSafeChars are characters that never need quoting in any supported shell
+const shellSafeChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=+,@%"
+
+// QuoteShellArg quotes arg so the given shell ('posix' or 'powershell') treats it literally.
+// Arguments made only of safe characters are returned unchanged.
+func QuoteShellArg(arg, shell string) string {
+	if arg != "" && strings.Trim(arg, shellSafeChars) == "" {
+		return arg
+	}
+
+	// Both shells take single-quoted strings literally; they differ in how a quote is escaped
+	if shell == "powershell" {
+		return "'" + strings.ReplaceAll(arg, "'", "''") + "'"
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// QuoteShellCommand joins args into a single POSIX shell command line
+func QuoteShellCommand(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = QuoteShellArg(arg, "posix")
+	}
+	return strings.Join(quoted, " ")
+}
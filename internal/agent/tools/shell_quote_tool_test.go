@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+)
+
+func TestQuoteShellArg(t *testing.T) {
+	tests := []struct {
+		name  string
+		arg   string
+		shell string
+		want  string
+	}{
+		{"safe", "./cmd/app", "posix", "./cmd/app"},
+		{"empty", "", "posix", "''"},
+		{"spaces", "hello world", "posix", "'hello world'"},
+		{"single quote", "it's", "posix", `'it'\''s'`},
+		{"double quotes", `say "hi"`, "posix", `'say "hi"'`},
+		{"dollar", "$HOME", "posix", "'$HOME'"},
+		{"command substitution", "$(rm -rf /)", "posix", "'$(rm -rf /)'"},
+		{"powershell quote", "it's $env:PATH", "powershell", "'it''s $env:PATH'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QuoteShellArg(tt.arg, tt.shell); got != tt.want {
+				t.Errorf("QuoteShellArg(%q, %q) = %s, want %s", tt.arg, tt.shell, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteShellArgRoundTrip(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not installed")
+	}
+
+	for _, arg := range []string{"hello world", `it's "quoted"`, "$HOME and `date`", "*.go; echo pwned", ""} {
+		output, err := exec.Command("sh", "-c", "printf %s "+QuoteShellArg(arg, "posix")).Output()
+		if err != nil {
+			t.Fatalf("sh failed for %q: %v", arg, err)
+		}
+		if string(output) != arg {
+			t.Errorf("sh received %q, want %q", output, arg)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	var output ShellQuoteOutput
+	callTool(t, context.Background(), ShellQuote, ShellQuoteInput{Args: []string{"grep", "-r", "hello world", "$PATH"}}, &output)
+
+	if output.Shell != "posix" || output.Command != "grep -r 'hello world' '$PATH'" {
+		t.Errorf("unexpected output: %+v", output)
+	}
+
+	if _, err := ShellQuote(context.Background(), []byte(`{"args": ["x"], "shell": "fish"}`)); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
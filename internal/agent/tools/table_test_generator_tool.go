@@ -0,0 +1,394 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TableTestGeneratorToolDefinition defines the gen_table_test tool
+var TableTestGeneratorToolDefinition = ToolDefinition{
+	Name: "gen_table_test",
+	Description: `Scaffold an idiomatic table-driven test for a Go function or method.
+The parameter and return types are inferred from the function signature and the test is
+written to the corresponding _test.go file (created if it doesn't exist). The generated test
+contains a 'tests := []struct{...}' table and a loop calling t.Run for each case.
+Use 'Type.Method' as the function name to target a method. Set 'run' to compile and run the new test.`,
+	InputSchema:           TableTestGeneratorInputSchema,
+	Function:              GenerateTableTest,
+	CountsTowardLoopLimit: true,
+}
+
+// TableTestGeneratorInput defines the input parameters for the gen_table_test tool
+type TableTestGeneratorInput struct {
+	Path     string `json:"path" jsonschema_required:"true" jsonschema_description:"Path to the Go source file containing the function"`
+	Function string `json:"function" jsonschema_required:"true" jsonschema_description:"Name of the function to test, or 'Type.Method' for a method" jsonschema_example:"Parser.Parse"`
+	Run      bool   `json:"run,omitempty" jsonschema_description:"If true, run the generated test with 'go test -run' after writing it"`
+}
+
+// TableTestGeneratorInputSchema is the JSON schema for the gen_table_test tool
+var TableTestGeneratorInputSchema = GenerateSchema[TableTestGeneratorInput]()
+
+// TableTestGeneratorOutput represents the structured output of the gen_table_test tool
+type TableTestGeneratorOutput struct {
+	TestFile   string       `json:"test_file"`
+	TestName   string       `json:"test_name"`
+	Created    bool         `json:"created"`
+	Code       string       `json:"code"`
+	RunResult  *RunGoOutput `json:"run_result,omitempty"`
+	RunMessage string       `json:"run_message,omitempty"`
+}
+
+// testParam describes a single parameter or result of the function under test
+type testParam struct {
+	Name     string
+	Type     string
+	Variadic bool
+}
+
+// testTarget holds the signature details needed to scaffold a test
+type testTarget struct {
+	Package      string
+	Receiver     string
+	ReceiverType string
+	Name         string
+	Params       []testParam
+	Results      []testParam
+	HasError     bool
+}
+
+// GenerateTableTest implements the gen_table_test tool functionality
+func GenerateTableTest(ctx context.Context, input json.RawMessage) (string, error) {
+	genInput := TableTestGeneratorInput{}
+	err := json.Unmarshal(input, &genInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if genInput.Path == "" {
+		return "", fmt.Errorf("path cannot be empty")
+	}
+
+	genInput.Path, err = ResolvePath(ctx, genInput.Path)
+	if err != nil {
+		return "", err
+	}
+	if genInput.Function == "" {
+		return "", fmt.Errorf("function cannot be empty")
+	}
+	if strings.HasSuffix(genInput.Path, "_test.go") {
+		return "", fmt.Errorf("path must be a non-test Go source file")
+	}
+
+	target, err := findTestTarget(genInput.Path, genInput.Function)
+	if err != nil {
+		return "", err
+	}
+
+	testName := tableTestName(target)
+
+	testFile := strings.TrimSuffix(genInput.Path, ".go") + "_test.go"
+	code, created, err := writeTableTest(testFile, testName, target)
+	if err != nil {
+		return "", err
+	}
+
+	output := TableTestGeneratorOutput{
+		TestFile: testFile,
+		TestName: testName,
+		Created:  created,
+		Code:     code,
+	}
+
+	if genInput.Run {
+		result, err := RunGoCommand(ctx, "test", ".", []string{"-run", "^" + testName + "$"}, filepath.Dir(genInput.Path))
+		if err != nil {
+			output.RunMessage = fmt.Sprintf("Failed to run test: %v", err)
+		} else {
+			output.RunResult = &result
+		}
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// findTestTarget parses the file and extracts the signature of the requested function
+func findTestTarget(filePath, function string) (testTarget, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, nil, 0)
+	if err != nil {
+		return testTarget{}, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	receiver, name := "", function
+	if parts := strings.SplitN(function, ".", 2); len(parts) == 2 {
+		receiver, name = parts[0], parts[1]
+	}
+
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Name.Name != name {
+			continue
+		}
+		if receiverTypeName(funcDecl) != receiver {
+			continue
+		}
+		if funcDecl.Type.TypeParams != nil && len(funcDecl.Type.TypeParams.List) > 0 {
+			return testTarget{}, fmt.Errorf("generic function %s is not supported", function)
+		}
+
+		target := testTarget{
+			Package:      file.Name.Name,
+			ReceiverType: receiver,
+			Name:         name,
+		}
+		if funcDecl.Recv != nil {
+			target.Receiver = exprString(fset, funcDecl.Recv.List[0].Type)
+		}
+		target.Params = collectParams(fset, funcDecl.Type.Params, "arg")
+		target.Results = collectParams(fset, funcDecl.Type.Results, "want")
+
+		// Treat a trailing error result as a wantErr flag
+		if n := len(target.Results); n > 0 && target.Results[n-1].Type == "error" {
+			target.HasError = true
+			target.Results = target.Results[:n-1]
+		}
+
+		return target, nil
+	}
+
+	return testTarget{}, fmt.Errorf("function %s not found in %s", function, filePath)
+}
+
+// receiverTypeName returns the bare type name of a method receiver, or "" for functions
+func receiverTypeName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+	expr := funcDecl.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// collectParams flattens a field list into named parameters
+func collectParams(fset *token.FileSet, fields *ast.FieldList, prefix string) []testParam {
+	var params []testParam
+	if fields == nil {
+		return params
+	}
+
+	for _, field := range fields.List {
+		typeExpr := field.Type
+		variadic := false
+		if ellipsis, ok := typeExpr.(*ast.Ellipsis); ok {
+			variadic = true
+			typeExpr = &ast.ArrayType{Elt: ellipsis.Elt}
+		}
+		typeStr := exprString(fset, typeExpr)
+
+		if len(field.Names) == 0 {
+			params = append(params, testParam{Name: fmt.Sprintf("%s%d", prefix, len(params)), Type: typeStr, Variadic: variadic})
+			continue
+		}
+		for _, name := range field.Names {
+			paramName := name.Name
+			if paramName == "_" || prefix == "want" {
+				paramName = fmt.Sprintf("%s%d", prefix, len(params))
+			}
+			params = append(params, testParam{Name: paramName, Type: typeStr, Variadic: variadic})
+		}
+	}
+
+	return params
+}
+
+// exprString renders an AST expression back to Go source
+func exprString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		return "interface{}"
+	}
+	return buf.String()
+}
+
+// tableTestName names the test for target. go test only runs Test functions whose name does not
+// continue with a lowercase letter, so unexported targets are separated by an underscore.
+func tableTestName(target testTarget) string {
+	name := target.Name
+	if target.ReceiverType != "" {
+		name = target.ReceiverType + "_" + target.Name
+	}
+	if r, _ := utf8.DecodeRuneInString(name); unicode.IsLower(r) {
+		name = "_" + name
+	}
+	return "Test" + name
+}
+
+// renderTableTest produces the source of the table-driven test function
+func renderTableTest(testName string, target testTarget) (string, bool) {
+	var b strings.Builder
+	usesReflect := false
+
+	fmt.Fprintf(&b, "func %s(t *testing.T) {\n", testName)
+	if len(target.Params) > 0 {
+		b.WriteString("\ttype args struct {\n")
+		for _, p := range target.Params {
+			fmt.Fprintf(&b, "\t\t%s %s\n", p.Name, p.Type)
+		}
+		b.WriteString("\t}\n")
+	}
+
+	b.WriteString("\ttests := []struct {\n\t\tname string\n")
+	if target.Receiver != "" {
+		fmt.Fprintf(&b, "\t\treceiver %s\n", target.Receiver)
+	}
+	if len(target.Params) > 0 {
+		b.WriteString("\t\targs args\n")
+	}
+	for _, r := range target.Results {
+		fmt.Fprintf(&b, "\t\t%s %s\n", r.Name, r.Type)
+	}
+	if target.HasError {
+		b.WriteString("\t\twantErr bool\n")
+	}
+	b.WriteString("\t}{\n\t\t// TODO: Add test cases.\n\t}\n")
+
+	b.WriteString("\tfor _, tt := range tests {\n\t\tt.Run(tt.name, func(t *testing.T) {\n\t\t\t")
+
+	var gots []string
+	for i := range target.Results {
+		gots = append(gots, fmt.Sprintf("got%d", i))
+	}
+	if target.HasError {
+		gots = append(gots, "err")
+	}
+	if len(gots) > 0 {
+		b.WriteString(strings.Join(gots, ", ") + " := ")
+	}
+
+	callee := target.Name
+	if target.Receiver != "" {
+		callee = "tt.receiver." + target.Name
+	}
+	var callArgs []string
+	for _, p := range target.Params {
+		arg := "tt.args." + p.Name
+		if p.Variadic {
+			arg += "..."
+		}
+		callArgs = append(callArgs, arg)
+	}
+	fmt.Fprintf(&b, "%s(%s)\n", callee, strings.Join(callArgs, ", "))
+
+	if target.HasError {
+		b.WriteString("\t\t\tif (err != nil) != tt.wantErr {\n")
+		fmt.Fprintf(&b, "\t\t\t\tt.Fatalf(\"%s() error = %%v, wantErr %%v\", err, tt.wantErr)\n", target.Name)
+		b.WriteString("\t\t\t}\n")
+	}
+	for i, r := range target.Results {
+		usesReflect = true
+		fmt.Fprintf(&b, "\t\t\tif !reflect.DeepEqual(got%d, tt.%s) {\n", i, r.Name)
+		fmt.Fprintf(&b, "\t\t\t\tt.Errorf(\"%s() got%d = %%v, want %%v\", got%d, tt.%s)\n", target.Name, i, i, r.Name)
+		b.WriteString("\t\t\t}\n")
+	}
+
+	b.WriteString("\t\t})\n\t}\n}\n")
+	return b.String(), usesReflect
+}
+
+// writeTableTest writes the generated test into testFile, creating or extending it
+// Returns the formatted test code and whether the file was newly created
+func writeTableTest(testFile, testName string, target testTarget) (string, bool, error) {
+	testCode, usesReflect := renderTableTest(testName, target)
+	imports := []string{"testing"}
+	if usesReflect {
+		imports = append(imports, "reflect")
+	}
+
+	created, err := writeTestFunc(testFile, testName, target.Package, testCode, imports)
+	if err != nil {
+		return "", false, err
+	}
+	return testCode, created, nil
+}
+
+// writeTestFunc appends the function funcName with source code to testFile, creating the file in
+// package pkg if needed and adding any of imports it lacks. Reports whether the file was created.
+func writeTestFunc(testFile, funcName, pkg, code string, imports []string) (bool, error) {
+	var source string
+	created := false
+
+	existing, err := os.ReadFile(testFile)
+	switch {
+	case os.IsNotExist(err):
+		created = true
+		var b strings.Builder
+		fmt.Fprintf(&b, "package %s\n\nimport (\n", pkg)
+		for _, imp := range imports {
+			fmt.Fprintf(&b, "\t%q\n", imp)
+		}
+		b.WriteString(")\n\n")
+		b.WriteString(code)
+		source = b.String()
+	case err != nil:
+		return false, fmt.Errorf("failed to read test file: %w", err)
+	default:
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, testFile, existing, parser.ImportsOnly)
+		if err != nil {
+			return false, fmt.Errorf("failed to parse existing test file: %w", err)
+		}
+		if bytes.Contains(existing, []byte("func "+funcName+"(")) {
+			return false, fmt.Errorf("%s already exists in %s", funcName, testFile)
+		}
+
+		// Add any missing imports as separate declarations right after the package clause
+		present := make(map[string]bool)
+		for _, imp := range file.Imports {
+			present[strings.Trim(imp.Path.Value, `"`)] = true
+		}
+		var missing strings.Builder
+		for _, imp := range imports {
+			if !present[imp] {
+				fmt.Fprintf(&missing, "\nimport %q\n", imp)
+			}
+		}
+
+		pkgEnd := fset.Position(file.Name.End()).Offset
+		source = string(existing[:pkgEnd]) + "\n" + missing.String() + string(existing[pkgEnd:])
+		source = strings.TrimRight(source, "\n") + "\n\n" + code
+	}
+
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		return false, fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	if err := os.WriteFile(testFile, formatted, 0644); err != nil {
+		return false, fmt.Errorf("failed to write test file: %w", err)
+	}
+	recordReadHash(testFile, formatted)
+
+	return created, nil
+}
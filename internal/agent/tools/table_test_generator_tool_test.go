@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+const tableTestFixture = `package calc
+
+import "errors"
+
+// Divide returns a divided by b
+func Divide(a, b int) (int, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return a / b, nil
+}
+
+type store struct{ items map[string]int }
+
+func (s *store) get(key string) int { return s.items[key] }
+
+func count(words ...string) int { return len(words) }
+`
+
+func TestGenerateTableTest(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/calc")
+	writeTestFile(t, dir, "calc.go", tableTestFixture)
+
+	var output TableTestGeneratorOutput
+	callTool(t, ctx, GenerateTableTest, TableTestGeneratorInput{Path: "calc.go", Function: "Divide", Run: true}, &output)
+
+	if output.TestName != "TestDivide" || !output.Created {
+		t.Errorf("got test %q created=%v, want a new TestDivide", output.TestName, output.Created)
+	}
+	for _, want := range []string{"tests := []struct", "t.Run(tt.name", "Divide(tt.args.a, tt.args.b)", "wantErr"} {
+		if !strings.Contains(output.Code, want) {
+			t.Errorf("generated code is missing %q:\n%s", want, output.Code)
+		}
+	}
+	if output.RunResult == nil || !output.RunResult.Success {
+		t.Fatalf("generated test did not compile and pass: %+v %s", output.RunResult, output.RunMessage)
+	}
+	if !strings.Contains(output.RunResult.Stdout+output.RunResult.Stderr, "ok") {
+		t.Errorf("unexpected go test output: %+v", output.RunResult)
+	}
+}
+
+func TestGenerateTableTestAppendsToExistingFile(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/calc")
+	writeTestFile(t, dir, "calc.go", tableTestFixture)
+
+	callTool(t, ctx, GenerateTableTest, TableTestGeneratorInput{Path: "calc.go", Function: "Divide"}, nil)
+	var output TableTestGeneratorOutput
+	callTool(t, ctx, GenerateTableTest, TableTestGeneratorInput{Path: "calc.go", Function: "store.get", Run: true}, &output)
+
+	if output.Created {
+		t.Error("expected the second test to be appended to the existing file")
+	}
+	content := readTestFile(t, output.TestFile)
+	if !strings.Contains(content, "func TestDivide(") || !strings.Contains(content, "func Test_store_get(") {
+		t.Errorf("test file is missing a test:\n%s", content)
+	}
+	if output.RunResult == nil || !output.RunResult.Success {
+		t.Errorf("appended test did not compile: %+v %s", output.RunResult, output.RunMessage)
+	}
+}
+
+func TestTableTestName(t *testing.T) {
+	tests := []struct {
+		name   string
+		target testTarget
+		want   string
+	}{
+		{"exported function", testTarget{Name: "Divide"}, "TestDivide"},
+		{"unexported function", testTarget{Name: "count"}, "Test_count"},
+		{"exported method", testTarget{ReceiverType: "Store", Name: "Get"}, "TestStore_Get"},
+		{"unexported receiver", testTarget{ReceiverType: "store", Name: "get"}, "Test_store_get"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tableTestName(tt.target); got != tt.want {
+				t.Errorf("tableTestName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateTableTestUnknownFunction(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "calc.go", tableTestFixture)
+
+	if _, err := GenerateTableTest(ctx, []byte(`{"path": "calc.go", "function": "Multiply"}`)); err == nil {
+		t.Error("expected an error for a function that doesn't exist")
+	}
+}
@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// TempWorkspaceToolDefinition defines the temp_workspace tool
+var TempWorkspaceToolDefinition = ToolDefinition{
+	Name: "temp_workspace",
+	Description: `Manage scratch directories for experiments that can be freely modified and thrown away.
+Operations:
+- 'create': Create a new temporary directory and return its path
+- 'cleanup': Remove a temporary directory created by this tool ('path'), or all of them ('all')
+- 'list': List the temporary directories created in this session
+
+Only directories created by this tool can be cleaned up. All remaining ones are removed on exit.`,
+	InputSchema:           TempWorkspaceInputSchema,
+	Function:              TempWorkspace,
+	CountsTowardLoopLimit: true,
+}
+
+// TempWorkspaceInput defines the input parameters for the temp_workspace tool
+type TempWorkspaceInput struct {
+	Operation string `json:"operation" jsonschema_required:"true" jsonschema_description:"The operation to perform: 'create', 'cleanup', or 'list'."`
+	Prefix    string `json:"prefix,omitempty" jsonschema_description:"Optional name prefix for 'create'. Defaults to 'metamorph-'."`
+	Path      string `json:"path,omitempty" jsonschema_description:"Workspace path to remove for 'cleanup'."`
+	All       bool   `json:"all,omitempty" jsonschema_description:"Remove every workspace created in this session for 'cleanup'."`
+}
+
+// TempWorkspaceInputSchema is the JSON schema for the temp_workspace tool
+var TempWorkspaceInputSchema = GenerateSchema[TempWorkspaceInput]()
+
+// TempWorkspaceOutput represents the structured output of the temp_workspace tool
+type TempWorkspaceOutput struct {
+	Operation  string   `json:"operation"`
+	Path       string   `json:"path,omitempty"`
+	Removed    []string `json:"removed,omitempty"`
+	Workspaces []string `json:"workspaces"`
+}
+
+var (
+	tempWorkspaces      = make(map[string]bool)
+	tempWorkspacesMutex sync.Mutex
+)
+
+// TempWorkspace implements the temp_workspace tool functionality
+func TempWorkspace(ctx context.Context, input json.RawMessage) (string, error) {
+	workspaceInput := TempWorkspaceInput{}
+	err := json.Unmarshal(input, &workspaceInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	output := TempWorkspaceOutput{Operation: workspaceInput.Operation}
+
+	switch workspaceInput.Operation {
+	case "create":
+		prefix := workspaceInput.Prefix
+		if prefix == "" {
+			prefix = "metamorph-"
+		}
+		if filepath.Base(prefix) != prefix {
+			return "", fmt.Errorf("prefix must not contain path separators")
+		}
+
+		dir, err := os.MkdirTemp("", prefix)
+		if err != nil {
+			return "", fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+
+		tempWorkspacesMutex.Lock()
+		tempWorkspaces[dir] = true
+		tempWorkspacesMutex.Unlock()
+		output.Path = dir
+
+	case "cleanup":
+		if workspaceInput.All {
+			removed, err := CleanupTempWorkspaces()
+			output.Removed = removed
+			if err != nil {
+				return "", err
+			}
+			break
+		}
+		if workspaceInput.Path == "" {
+			return "", fmt.Errorf("path or all is required for 'cleanup'")
+		}
+
+		dir := filepath.Clean(workspaceInput.Path)
+		tempWorkspacesMutex.Lock()
+		owned := tempWorkspaces[dir]
+		tempWorkspacesMutex.Unlock()
+		if !owned {
+			return "", fmt.Errorf("refusing to remove %s: it was not created by temp_workspace", dir)
+		}
+
+		if err := os.RemoveAll(dir); err != nil {
+			return "", fmt.Errorf("failed to remove %s: %w", dir, err)
+		}
+		tempWorkspacesMutex.Lock()
+		delete(tempWorkspaces, dir)
+		tempWorkspacesMutex.Unlock()
+		output.Removed = []string{dir}
+
+	case "list":
+
+	default:
+		return "", fmt.Errorf("invalid operation: %s. Must be 'create', 'cleanup', or 'list'", workspaceInput.Operation)
+	}
+
+	output.Workspaces = listTempWorkspaces()
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// listTempWorkspaces returns the workspaces created in this session, sorted
+func listTempWorkspaces() []string {
+	tempWorkspacesMutex.Lock()
+	defer tempWorkspacesMutex.Unlock()
+
+	dirs := make([]string, 0, len(tempWorkspaces))
+	for dir := range tempWorkspaces {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// CleanupTempWorkspaces removes every workspace created by the temp_workspace tool.
+// It returns the removed paths and the first error encountered, if any.
+func CleanupTempWorkspaces() ([]string, error) {
+	tempWorkspacesMutex.Lock()
+	defer tempWorkspacesMutex.Unlock()
+
+	var removed []string
+	var firstErr error
+	for dir := range tempWorkspaces {
+		if err := os.RemoveAll(dir); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to remove %s: %w", dir, err)
+			}
+			continue
+		}
+		delete(tempWorkspaces, dir)
+		removed = append(removed, dir)
+	}
+	sort.Strings(removed)
+
+	return removed, firstErr
+}
@@ -0,0 +1,80 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTempWorkspaceLifecycle(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Cleanup(func() { CleanupTempWorkspaces() })
+	ctx := context.Background()
+
+	var created TempWorkspaceOutput
+	callTool(t, ctx, TempWorkspace, TempWorkspaceInput{Operation: "create", Prefix: "scratch-"}, &created)
+	if !strings.HasPrefix(filepath.Base(created.Path), "scratch-") {
+		t.Errorf("path %q doesn't use the prefix", created.Path)
+	}
+
+	// The workspace is a normal directory the agent can modify
+	scratchFile := writeTestFile(t, created.Path, "sub/experiment.txt", "trial\n")
+	if readTestFile(t, scratchFile) != "trial\n" {
+		t.Fatal("failed to use the temporary workspace")
+	}
+
+	var listed TempWorkspaceOutput
+	callTool(t, ctx, TempWorkspace, TempWorkspaceInput{Operation: "list"}, &listed)
+	if !slices.Contains(listed.Workspaces, created.Path) {
+		t.Errorf("list = %v, want it to include %s", listed.Workspaces, created.Path)
+	}
+
+	var cleaned TempWorkspaceOutput
+	callTool(t, ctx, TempWorkspace, TempWorkspaceInput{Operation: "cleanup", Path: created.Path}, &cleaned)
+	if !slices.Equal(cleaned.Removed, []string{created.Path}) || slices.Contains(cleaned.Workspaces, created.Path) {
+		t.Errorf("unexpected cleanup result: %+v", cleaned)
+	}
+	if _, err := os.Stat(created.Path); !os.IsNotExist(err) {
+		t.Errorf("workspace still exists after cleanup: %v", err)
+	}
+}
+
+func TestTempWorkspaceCleanupRefusesForeignDirs(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := TempWorkspace(context.Background(), mustMarshal(t, TempWorkspaceInput{Operation: "cleanup", Path: dir}))
+	if err == nil || !strings.Contains(err.Error(), "not created by temp_workspace") {
+		t.Errorf("expected cleanup of a foreign directory to be refused, got %v", err)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("foreign directory was removed: %v", err)
+	}
+}
+
+func TestCleanupTempWorkspaces(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ctx := context.Background()
+
+	var first, second TempWorkspaceOutput
+	callTool(t, ctx, TempWorkspace, TempWorkspaceInput{Operation: "create"}, &first)
+	callTool(t, ctx, TempWorkspace, TempWorkspaceInput{Operation: "create"}, &second)
+
+	removed, err := CleanupTempWorkspaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{first.Path, second.Path} {
+		if !slices.Contains(removed, dir) {
+			t.Errorf("removed = %v, want it to include %s", removed, dir)
+		}
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("%s still exists after cleanup", dir)
+		}
+	}
+	if remaining := listTempWorkspaces(); len(remaining) != 0 {
+		t.Errorf("workspaces still tracked after cleanup: %v", remaining)
+	}
+}
@@ -0,0 +1,253 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TidyPreviewToolDefinition defines the tidy_preview tool
+var TidyPreviewToolDefinition = ToolDefinition{
+	Name: "tidy_preview",
+	Description: `Preview what 'go mod tidy' would change before letting it touch go.mod and go.sum.
+Tidy runs against temporary copies of the module's go.mod and go.sum (via -modfile), so the real
+files are left alone; the tool returns the requires tidy would add, remove and change, a diff of
+go.mod and the number of go.sum lines added and removed. Review the preview, then call again with
+'apply' set to write the tidied files.`,
+	InputSchema:           TidyPreviewInputSchema,
+	Function:              TidyPreview,
+	CountsTowardLoopLimit: true,
+}
+
+// TidyPreviewInput defines the input parameters for the tidy_preview tool
+type TidyPreviewInput struct {
+	Path  string `json:"path,omitempty" jsonschema_description:"Directory containing go.mod. Defaults to the current directory."`
+	Apply bool   `json:"apply,omitempty" jsonschema_description:"If true, write the tidied go.mod and go.sum after computing the preview"`
+}
+
+// TidyPreviewInputSchema is the JSON schema for the tidy_preview tool
+var TidyPreviewInputSchema = GenerateSchema[TidyPreviewInput]()
+
+// TidyRequire is a module requirement in go.mod
+type TidyRequire struct {
+	Path     string `json:"path"`
+	Version  string `json:"version"`
+	Indirect bool   `json:"indirect,omitempty"`
+}
+
+// TidyRequireChange is a requirement whose version or indirect marking tidy would change
+type TidyRequireChange struct {
+	Path   string `json:"path"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// TidyPreviewOutput represents the structured output of the tidy_preview tool
+type TidyPreviewOutput struct {
+	Module     string              `json:"module"`
+	Changed    bool                `json:"changed"`
+	Added      []TidyRequire       `json:"added"`
+	Removed    []TidyRequire       `json:"removed"`
+	Updated    []TidyRequireChange `json:"updated"`
+	GoModDiff  string              `json:"go_mod_diff,omitempty"`
+	SumAdded   int                 `json:"go_sum_lines_added"`
+	SumRemoved int                 `json:"go_sum_lines_removed"`
+	Applied    bool                `json:"applied"`
+}
+
+// TidyPreview implements the tidy_preview tool functionality
+func TidyPreview(ctx context.Context, input json.RawMessage) (string, error) {
+	tidyInput := TidyPreviewInput{}
+	err := DecodeInput(input, &tidyInput)
+	if err != nil {
+		return "", err
+	}
+
+	moduleDir := workspaceDir(ctx)
+	if tidyInput.Path != "" {
+		moduleDir, err = ResolvePath(ctx, tidyInput.Path)
+		if err != nil {
+			return "", err
+		}
+	}
+	goModPath := filepath.Join(moduleDir, "go.mod")
+	goSumPath := filepath.Join(moduleDir, "go.sum")
+
+	oldMod, err := os.ReadFile(goModPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	oldSum, err := os.ReadFile(goSumPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read go.sum: %w", err)
+	}
+
+	// With -modfile=dir/go.mod, go reads and writes dir/go.sum alongside it
+	tmpDir, err := os.MkdirTemp("", "metamorph-tidy-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpMod := filepath.Join(tmpDir, "go.mod")
+	tmpSum := filepath.Join(tmpDir, "go.sum")
+	if err := os.WriteFile(tmpMod, oldMod, 0644); err != nil {
+		return "", fmt.Errorf("failed to copy go.mod: %w", err)
+	}
+	if oldSum != nil {
+		if err := os.WriteFile(tmpSum, oldSum, 0644); err != nil {
+			return "", fmt.Errorf("failed to copy go.sum: %w", err)
+		}
+	}
+
+	result, err := RunGoCommand(ctx, "mod tidy", "", []string{"-modfile=" + tmpMod}, moduleDir)
+	if err != nil {
+		return "", err
+	}
+	if !result.Success {
+		return "", fmt.Errorf("go mod tidy failed: %s", strings.TrimSpace(result.Stderr+"\n"+result.ErrorMessage))
+	}
+
+	newMod, err := os.ReadFile(tmpMod)
+	if err != nil {
+		return "", fmt.Errorf("failed to read tidied go.mod: %w", err)
+	}
+	newSum, err := os.ReadFile(tmpSum)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read tidied go.sum: %w", err)
+	}
+
+	module, oldRequires, err := moduleRequires(ctx, goModPath, moduleDir)
+	if err != nil {
+		return "", err
+	}
+	_, newRequires, err := moduleRequires(ctx, tmpMod, moduleDir)
+	if err != nil {
+		return "", err
+	}
+
+	output := TidyPreviewOutput{
+		Module:    module,
+		Added:     []TidyRequire{},
+		Removed:   []TidyRequire{},
+		Updated:   []TidyRequireChange{},
+		GoModDiff: unifiedDiff("go.mod", string(oldMod), string(newMod)),
+	}
+	output.Added, output.Removed, output.Updated = compareRequires(oldRequires, newRequires)
+	output.SumAdded, output.SumRemoved = countLineChanges(string(oldSum), string(newSum))
+	output.Changed = output.GoModDiff != "" || string(oldSum) != string(newSum)
+
+	if tidyInput.Apply && output.Changed {
+		if err := os.WriteFile(goModPath, newMod, 0644); err != nil {
+			return "", fmt.Errorf("failed to write go.mod: %w", err)
+		}
+		if newSum != nil {
+			err = os.WriteFile(goSumPath, newSum, 0644)
+		} else if oldSum != nil {
+			err = os.Remove(goSumPath)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to update go.sum: %w", err)
+		}
+		recordReadHash(goModPath, newMod)
+		if newSum != nil {
+			recordReadHash(goSumPath, newSum)
+		}
+		output.Applied = true
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// moduleRequires returns the module path and requirements of the go.mod file at path, as
+// reported by 'go mod edit -json'
+func moduleRequires(ctx context.Context, path, moduleDir string) (string, map[string]TidyRequire, error) {
+	result, err := RunGoCommand(ctx, "mod edit", "", []string{"-json", path}, moduleDir)
+	if err != nil {
+		return "", nil, err
+	}
+	if !result.Success {
+		return "", nil, fmt.Errorf("failed to parse %s: %s", path, strings.TrimSpace(result.Stderr))
+	}
+
+	var modFile struct {
+		Module  struct{ Path string }
+		Require []TidyRequire
+	}
+	if err := json.Unmarshal([]byte(result.Stdout), &modFile); err != nil {
+		return "", nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	requires := map[string]TidyRequire{}
+	for _, require := range modFile.Require {
+		requires[require.Path] = require
+	}
+	return modFile.Module.Path, requires, nil
+}
+
+// compareRequires returns the requirements added, removed and changed between before and after,
+// sorted by module path
+func compareRequires(before, after map[string]TidyRequire) ([]TidyRequire, []TidyRequire, []TidyRequireChange) {
+	added, removed, updated := []TidyRequire{}, []TidyRequire{}, []TidyRequireChange{}
+	for path, require := range after {
+		old, found := before[path]
+		switch {
+		case !found:
+			added = append(added, require)
+		case old != require:
+			updated = append(updated, TidyRequireChange{Path: path, Before: requireVersion(old), After: requireVersion(require)})
+		}
+	}
+	for path, require := range before {
+		if _, found := after[path]; !found {
+			removed = append(removed, require)
+		}
+	}
+
+	sort.Slice(added, func(i, j int) bool { return added[i].Path < added[j].Path })
+	sort.Slice(removed, func(i, j int) bool { return removed[i].Path < removed[j].Path })
+	sort.Slice(updated, func(i, j int) bool { return updated[i].Path < updated[j].Path })
+	return added, removed, updated
+}
+
+// requireVersion renders the version of a requirement as it reads in go.mod
+func requireVersion(require TidyRequire) string {
+	if require.Indirect {
+		return require.Version + " // indirect"
+	}
+	return require.Version
+}
+
+// countLineChanges counts the lines of after that are not in before and the lines of before that
+// are not in after. go.sum is sorted, so line order is ignored.
+func countLineChanges(before, after string) (int, int) {
+	lines := map[string]int{}
+	for _, line := range strings.Split(before, "\n") {
+		if line != "" {
+			lines[line]--
+		}
+	}
+	for _, line := range strings.Split(after, "\n") {
+		if line != "" {
+			lines[line]++
+		}
+	}
+
+	added, removed := 0, 0
+	for _, count := range lines {
+		if count > 0 {
+			added += count
+		} else {
+			removed -= count
+		}
+	}
+	return added, removed
+}
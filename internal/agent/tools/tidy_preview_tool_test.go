@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// tidyFixtureGoMod requires a module the code doesn't import and misses the one it does;
+// both are replaced by local directories so tidy runs offline
+const tidyFixtureGoMod = `module example.com/app
+
+go 1.21
+
+require example.com/unused v1.0.0
+
+replace example.com/unused => ../unused
+
+replace example.com/dep => ../dep
+`
+
+// writeTidyFixture writes the app module and its two local dependencies, returning the app directory
+func writeTidyFixture(t *testing.T, dir string) string {
+	t.Helper()
+	t.Setenv("GOPROXY", "off")
+	writeTestFile(t, dir, "dep/go.mod", "module example.com/dep\n\ngo 1.21\n")
+	writeTestFile(t, dir, "dep/dep.go", "package dep\n\nfunc Value() int { return 1 }\n")
+	writeTestFile(t, dir, "unused/go.mod", "module example.com/unused\n\ngo 1.21\n")
+	writeTestFile(t, dir, "unused/unused.go", "package unused\n")
+	writeTestFile(t, dir, "app/go.mod", tidyFixtureGoMod)
+	writeTestFile(t, dir, "app/main.go", "package main\n\nimport \"example.com/dep\"\n\nfunc main() { println(dep.Value()) }\n")
+	return filepath.Join(dir, "app")
+}
+
+func TestTidyPreviewLeavesGoModUnchanged(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	appDir := writeTidyFixture(t, dir)
+
+	var output TidyPreviewOutput
+	callTool(t, ctx, TidyPreview, TidyPreviewInput{Path: "app"}, &output)
+
+	if output.Module != "example.com/app" || !output.Changed || output.Applied {
+		t.Errorf("got %+v, want an unapplied change to example.com/app", output)
+	}
+	if want := []TidyRequire{{Path: "example.com/dep", Version: "v0.0.0-00010101000000-000000000000"}}; !reflect.DeepEqual(output.Added, want) {
+		t.Errorf("added = %+v, want %+v", output.Added, want)
+	}
+	if want := []TidyRequire{{Path: "example.com/unused", Version: "v1.0.0"}}; !reflect.DeepEqual(output.Removed, want) {
+		t.Errorf("removed = %+v, want %+v", output.Removed, want)
+	}
+	if !strings.Contains(output.GoModDiff, "-require example.com/unused v1.0.0") {
+		t.Errorf("diff:\n%s", output.GoModDiff)
+	}
+	if content := readTestFile(t, filepath.Join(appDir, "go.mod")); content != tidyFixtureGoMod {
+		t.Errorf("preview changed go.mod:\n%s", content)
+	}
+
+	output = TidyPreviewOutput{}
+	callTool(t, ctx, TidyPreview, TidyPreviewInput{Path: "app", Apply: true}, &output)
+	if !output.Applied {
+		t.Fatal("expected the tidied files to be written")
+	}
+	content := readTestFile(t, filepath.Join(appDir, "go.mod"))
+	if !strings.Contains(content, "require example.com/dep") || strings.Contains(content, "require example.com/unused") {
+		t.Errorf("applied go.mod:\n%s", content)
+	}
+
+	// Once tidy there is nothing left to change
+	output = TidyPreviewOutput{}
+	callTool(t, ctx, TidyPreview, TidyPreviewInput{Path: "app"}, &output)
+	if output.Changed || len(output.Added)+len(output.Removed)+len(output.Updated) != 0 {
+		t.Errorf("second preview = %+v, want no changes", output)
+	}
+}
+
+func TestCompareRequires(t *testing.T) {
+	before := map[string]TidyRequire{
+		"a": {Path: "a", Version: "v1.0.0"},
+		"b": {Path: "b", Version: "v1.0.0", Indirect: true},
+	}
+	after := map[string]TidyRequire{
+		"b": {Path: "b", Version: "v1.0.0"},
+		"c": {Path: "c", Version: "v0.2.0"},
+	}
+	added, removed, updated := compareRequires(before, after)
+	if len(added) != 1 || added[0].Path != "c" || len(removed) != 1 || removed[0].Path != "a" {
+		t.Errorf("added %+v, removed %+v", added, removed)
+	}
+	if want := []TidyRequireChange{{Path: "b", Before: "v1.0.0 // indirect", After: "v1.0.0"}}; !reflect.DeepEqual(updated, want) {
+		t.Errorf("updated = %+v, want %+v", updated, want)
+	}
+}
+
+func TestCountLineChanges(t *testing.T) {
+	added, removed := countLineChanges("a h1\nb h1\n", "b h1\nc h1\nd h1\n")
+	if added != 2 || removed != 1 {
+		t.Errorf("got +%d -%d, want +2 -1", added, removed)
+	}
+}
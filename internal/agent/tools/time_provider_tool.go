@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 )
@@ -23,7 +24,7 @@ type GetTimeInput struct {
 var GetTimeInputSchema = GenerateSchema[GetTimeInput]()
 
 // GetTime implements the get_time tool functionality
-func GetTime(input json.RawMessage) (string, error) {
+func GetTime(ctx context.Context, input json.RawMessage) (string, error) {
 	getTimeInput := GetTimeInput{}
 	err := json.Unmarshal(input, &getTimeInput)
 	if err != nil {
@@ -4,7 +4,11 @@
 package tools
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"reflect"
+	"strings"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/invopop/jsonschema"
@@ -24,10 +28,17 @@ type ToolDefinition struct {
 	InputSchema anthropic.ToolInputSchemaParam `json:"input_schema"`
 
 	// Function is the actual implementation that will be executed when the tool is used
-	Function func(input json.RawMessage) (string, error)
+	Function func(ctx context.Context, input json.RawMessage) (string, error)
+
+	// CountsTowardLoopLimit marks expensive or mutating tools whose use counts toward the
+	// consecutive and same-tool loop protection limits. Benign read-only tools leave it false;
+	// the agent counts every call IsMutatingToolCall reports as mutating regardless.
+	CountsTowardLoopLimit bool `json:"-"`
 }
 
-// GenerateSchema creates a JSON schema for the given type
+// GenerateSchema creates a JSON schema for the given type.
+// Fields tagged `jsonschema_required:"true"` are listed in the schema's required array, and
+// a `jsonschema_example` tag adds a sample value (parsed as JSON when possible, otherwise a string).
 func GenerateSchema[T any]() anthropic.ToolInputSchemaParam {
 	reflector := jsonschema.Reflector{
 		AllowAdditionalProperties: false,
@@ -38,9 +49,91 @@ func GenerateSchema[T any]() anthropic.ToolInputSchemaParam {
 
 	schema := reflector.Reflect(v)
 
-	return anthropic.ToolInputSchemaParam{
+	inputSchema := anthropic.ToolInputSchemaParam{
 		Properties: schema.Properties,
 	}
+
+	required := applySchemaTags(reflect.TypeOf(v), schema)
+	if len(required) > 0 {
+		inputSchema.WithExtraFields(map[string]interface{}{"required": required})
+	}
+
+	return inputSchema
+}
+
+// applySchemaTags adds examples from jsonschema_example tags to the schema's properties
+// and returns the JSON names of fields tagged jsonschema_required
+func applySchemaTags(t reflect.Type, schema *jsonschema.Schema) []string {
+	if t == nil || t.Kind() != reflect.Struct || schema.Properties == nil {
+		return nil
+	}
+
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		if field.Tag.Get("jsonschema_required") == "true" {
+			required = append(required, name)
+		}
+
+		example, ok := field.Tag.Lookup("jsonschema_example")
+		if !ok {
+			continue
+		}
+		property, found := schema.Properties.Get(name)
+		if !found {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(example), &value); err != nil {
+			value = example
+		}
+		property.Examples = append(property.Examples, value)
+	}
+
+	return required
+}
+
+// RequiredFields returns the JSON names of the fields listed as required in the tool's input schema
+func (t ToolDefinition) RequiredFields() []string {
+	encoded, err := json.Marshal(t.InputSchema)
+	if err != nil {
+		return nil
+	}
+	var schema struct {
+		Required []string `json:"required"`
+	}
+	if err := json.Unmarshal(encoded, &schema); err != nil {
+		return nil
+	}
+	return schema.Required
+}
+
+// MissingRequiredFields returns the required fields of the tool that are absent or null in input,
+// in schema order. Inputs that are not JSON objects are left for the tool itself to reject.
+func MissingRequiredFields(t ToolDefinition, input json.RawMessage) []string {
+	required := t.RequiredFields()
+	if len(required) == 0 {
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(input, &fields); err != nil {
+		return nil
+	}
+
+	var missing []string
+	for _, name := range required {
+		value, ok := fields[name]
+		if !ok || string(bytes.TrimSpace(value)) == "null" {
+			missing = append(missing, name)
+		}
+	}
+	return missing
 }
 
 // GetAllTools returns all available tools
@@ -57,5 +150,72 @@ func GetAllTools() []ToolDefinition {
 		GitOperationsToolDefinition,
 		FileOperationsToolDefinition,
 		SearchWebToolDefinition,
+		TableTestGeneratorToolDefinition,
+		RepoReplaceToolDefinition,
+		WatchBuildToolDefinition,
+		CommitMessageToolDefinition,
+		DirDiffToolDefinition,
+		TempWorkspaceToolDefinition,
+		GoBenchToolDefinition,
+		ShellQuoteToolDefinition,
+		FileSummaryToolDefinition,
+		NewProjectToolDefinition,
+		GoImplementToolDefinition,
+		MarkdownCodeToolDefinition,
+		RepoOverviewToolDefinition,
+		YamlEditToolDefinition,
+		DepDiagramToolDefinition,
+		DeadCodeToolDefinition,
+		JSONArrayAppendToolDefinition,
+		MacroToolDefinition,
+		GoRenameToolDefinition,
+		DockerfileCheckToolDefinition,
+		CallGraphToolDefinition,
+		CoverageGapsToolDefinition,
+		MemoryToolDefinition,
+		GoVerifyToolDefinition,
+		FindImplementersToolDefinition,
+		RunLinterToolDefinition,
+		ChmodToolDefinition,
+		ReplaceInFuncToolDefinition,
+		PlanCheckToolDefinition,
+		JSONDiffToolDefinition,
+		RunBackgroundToolDefinition,
+		ReadOutputToolDefinition,
+		CheckStructTagsToolDefinition,
+		ExtractInterfaceToolDefinition,
+		FixImportsToolDefinition,
+		GoFormatToolDefinition,
+		AnalyzeUsageToolDefinition,
+		RunUntilToolDefinition,
+		GoEnvToolDefinition,
+		ExampleGeneratorToolDefinition,
+		ConcurrencyCheckToolDefinition,
+		CheckTemplatesToolDefinition,
+		APIDiffToolDefinition,
+		GenChangelogToolDefinition,
+		EncodingToolDefinition,
+		GenGitignoreToolDefinition,
+		LintCommitMessageToolDefinition,
+		DownloadFileToolDefinition,
+		ConversationSummaryToolDefinition,
+		GoTestToolDefinition,
+		ScanSecretsToolDefinition,
+		CheckAPIStabilityToolDefinition,
+		TidyPreviewToolDefinition,
+		ChangedSinceToolDefinition,
+		MakefileEditToolDefinition,
+		MakeToolDefinition,
+		ErrorHandlingCheckToolDefinition,
+		FormatChangedToolDefinition,
+		MoveDeclToolDefinition,
+		CheckPrereqsToolDefinition,
+		APIFingerprintToolDefinition,
+		TruncateFileToolDefinition,
+		AssertFileToolDefinition,
+		CommitSeriesToolDefinition,
+		PprofSummaryToolDefinition,
+		FindDuplicatesToolDefinition,
+		GoGenerateToolDefinition,
 	}
 }
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type schemaFixtureInput struct {
+	Path    string   `json:"path" jsonschema_required:"true" jsonschema_description:"File to read" jsonschema_example:"main.go"`
+	Count   int      `json:"count,omitempty" jsonschema_example:"3"`
+	Tags    []string `json:"tags,omitempty" jsonschema_example:"[\"a\",\"b\"]"`
+	Mode    string   `json:"mode" jsonschema_required:"true"`
+	Verbose bool     `json:"verbose,omitempty"`
+	Ignored string   `json:"-" jsonschema_required:"true"`
+}
+
+// decodeSchema round-trips a tool input schema through JSON, as it is sent to the API
+func decodeSchema(t *testing.T, schema interface{}) map[string]interface{} {
+	t.Helper()
+	encoded, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestGenerateSchemaRequiredAndExamples(t *testing.T) {
+	schema := decodeSchema(t, GenerateSchema[schemaFixtureInput]())
+
+	if required := schema["required"]; !reflect.DeepEqual(required, []interface{}{"path", "mode"}) {
+		t.Errorf("required = %v, want [path mode]", required)
+	}
+
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("schema has no properties: %v", schema)
+	}
+	tests := []struct {
+		property string
+		want     interface{}
+	}{
+		{"path", []interface{}{"main.go"}},
+		{"count", []interface{}{float64(3)}},
+		{"tags", []interface{}{[]interface{}{"a", "b"}}},
+		{"verbose", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.property, func(t *testing.T) {
+			property, ok := properties[tt.property].(map[string]interface{})
+			if !ok {
+				t.Fatalf("missing property %q", tt.property)
+			}
+			if got := property["examples"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("examples = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSchemaWithoutRequiredFields(t *testing.T) {
+	schema := decodeSchema(t, GenerateSchema[struct {
+		Name string `json:"name,omitempty"`
+	}]())
+	if _, ok := schema["required"]; ok {
+		t.Errorf("expected no required array: %v", schema)
+	}
+}
+
+func TestRegisteredToolSchemas(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, tool := range GetAllTools() {
+		if seen[tool.Name] {
+			t.Errorf("tool %s is registered twice", tool.Name)
+		}
+		seen[tool.Name] = true
+
+		properties, _ := decodeSchema(t, tool.InputSchema)["properties"].(map[string]interface{})
+		for _, name := range tool.RequiredFields() {
+			if _, ok := properties[name]; !ok {
+				t.Errorf("%s requires %q, which is not a property", tool.Name, name)
+			}
+		}
+	}
+}
+
+func TestMissingRequiredFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"both missing", `{"content": "x"}`, []string{"path", "mode"}},
+		{"null counts as missing", `{"path": null, "mode": "create"}`, []string{"path"}},
+		{"all present", `{"path": "a.txt", "mode": "create"}`, nil},
+		{"not an object", `"a.txt"`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MissingRequiredFields(FileEditorToolDefinition, json.RawMessage(tt.input))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("MissingRequiredFields(%s) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// TruncateFileToolDefinition defines the truncate_file tool
+var TruncateFileToolDefinition = ToolDefinition{
+	Name: "truncate_file",
+	Description: `Trim a file to its last 'lines' lines or its last 'bytes' bytes, e.g. to keep a log file from
+growing without bound. With 'bytes', the kept part starts at the first complete line within that many
+bytes, so no partial line is left at the top. The trimmed file is written to a temporary file and
+renamed into place, so it is never left half-written; anything another process appends during the
+rename is lost. Returns how many lines and bytes were removed and kept.`,
+	InputSchema:           TruncateFileInputSchema,
+	Function:              TruncateFile,
+	CountsTowardLoopLimit: true,
+}
+
+// TruncateFileInput defines the input parameters for the truncate_file tool
+type TruncateFileInput struct {
+	Path  string `json:"path" jsonschema_required:"true" jsonschema_description:"File to truncate" jsonschema_example:"logs/agent.log"`
+	Lines int    `json:"lines,omitempty" jsonschema_description:"Number of trailing lines to keep" jsonschema_example:"100"`
+	Bytes int64  `json:"bytes,omitempty" jsonschema_description:"Maximum number of trailing bytes to keep, rounded down to whole lines"`
+}
+
+// TruncateFileInputSchema is the JSON schema for the truncate_file tool
+var TruncateFileInputSchema = GenerateSchema[TruncateFileInput]()
+
+// TruncateFileOutput represents the structured output of the truncate_file tool
+type TruncateFileOutput struct {
+	Path         string `json:"path"`
+	LinesRemoved int    `json:"lines_removed"`
+	LinesKept    int    `json:"lines_kept"`
+	BytesRemoved int64  `json:"bytes_removed"`
+	BytesKept    int64  `json:"bytes_kept"`
+	Message      string `json:"message"`
+}
+
+// truncateChunkSize is how much of the file is read at a time when searching backwards for lines
+const truncateChunkSize = 64 * 1024
+
+// lineCounter counts the lines written to it; a final line without a newline counts too
+type lineCounter struct {
+	lines int
+	last  byte
+}
+
+func (c *lineCounter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		c.lines += bytes.Count(p, []byte("\n"))
+		c.last = p[len(p)-1]
+	}
+	return len(p), nil
+}
+
+// count returns the number of lines written, counting an unterminated final line
+func (c *lineCounter) count() int {
+	if c.last != 0 && c.last != '\n' {
+		return c.lines + 1
+	}
+	return c.lines
+}
+
+// TruncateFile implements the truncate_file tool functionality
+func TruncateFile(ctx context.Context, input json.RawMessage) (string, error) {
+	truncateInput := TruncateFileInput{}
+	err := DecodeInput(input, &truncateInput)
+	if err != nil {
+		return "", err
+	}
+
+	if (truncateInput.Lines > 0) == (truncateInput.Bytes > 0) {
+		return "", fmt.Errorf("set exactly one of lines or bytes to a positive number")
+	}
+	path, err := ResolvePath(ctx, truncateInput.Path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a regular file", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	size := info.Size()
+	var keepFrom int64
+	if truncateInput.Lines > 0 {
+		keepFrom, err = lastLinesOffset(file, size, truncateInput.Lines)
+	} else {
+		keepFrom, err = lastBytesOffset(file, size, truncateInput.Bytes)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	output := TruncateFileOutput{Path: path, BytesRemoved: keepFrom, BytesKept: size - keepFrom}
+	removed := &lineCounter{}
+	if _, err := io.Copy(removed, io.NewSectionReader(file, 0, keepFrom)); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	output.LinesRemoved = removed.count()
+
+	// Write the kept part next to the file and rename it into place
+	kept := &lineCounter{}
+	if keepFrom > 0 {
+		tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".truncate-*")
+		if err != nil {
+			return "", fmt.Errorf("failed to create temporary file: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+
+		_, err = io.Copy(io.MultiWriter(tmp, kept), io.NewSectionReader(file, keepFrom, size-keepFrom))
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to write truncated file: %w", err)
+		}
+		if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+			return "", fmt.Errorf("failed to set permissions: %w", err)
+		}
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			return "", fmt.Errorf("failed to move truncated file into place: %w", err)
+		}
+	} else if _, err := io.Copy(kept, io.NewSectionReader(file, 0, size)); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	output.LinesKept = kept.count()
+
+	if keepFrom == 0 {
+		output.Message = fmt.Sprintf("%s is already within the limit (%d line(s), %d bytes); nothing was removed.", truncateInput.Path, output.LinesKept, output.BytesKept)
+	} else {
+		output.Message = fmt.Sprintf("Removed %d line(s) (%d bytes) from %s, keeping the last %d line(s) (%d bytes).",
+			output.LinesRemoved, output.BytesRemoved, truncateInput.Path, output.LinesKept, output.BytesKept)
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// lastLinesOffset returns the offset at which the last n lines of file start, reading backwards
+// from the end in chunks so large files are not loaded whole
+func lastLinesOffset(file io.ReaderAt, size int64, n int) (int64, error) {
+	end := size
+	// A newline ending the file terminates the last line rather than starting another
+	if size > 0 {
+		last := make([]byte, 1)
+		if _, err := io.ReadFull(io.NewSectionReader(file, size-1, 1), last); err != nil {
+			return 0, err
+		}
+		if last[0] == '\n' {
+			end--
+		}
+	}
+
+	buf := make([]byte, truncateChunkSize)
+	found := 0
+	for end > 0 {
+		start := max(end-truncateChunkSize, 0)
+		chunk := buf[:end-start]
+		if _, err := io.ReadFull(io.NewSectionReader(file, start, int64(len(chunk))), chunk); err != nil {
+			return 0, err
+		}
+		for i := len(chunk) - 1; i >= 0; i-- {
+			if chunk[i] != '\n' {
+				continue
+			}
+			found++
+			if found == n {
+				return start + int64(i) + 1, nil
+			}
+		}
+		end = start
+	}
+	return 0, nil
+}
+
+// lastBytesOffset returns the offset of the first complete line within the last n bytes of file
+func lastBytesOffset(file io.ReaderAt, size, n int64) (int64, error) {
+	if n >= size {
+		return 0, nil
+	}
+	start := size - n
+	// The kept part starts a line if the byte before it ends one
+	buf := make([]byte, truncateChunkSize)
+	for offset := start - 1; offset < size; offset += truncateChunkSize {
+		chunk := buf[:min(truncateChunkSize, size-offset)]
+		if _, err := io.ReadFull(io.NewSectionReader(file, offset, int64(len(chunk))), chunk); err != nil {
+			return 0, err
+		}
+		if i := bytes.IndexByte(chunk, '\n'); i >= 0 {
+			return offset + int64(i) + 1, nil
+		}
+	}
+	return size, nil
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// numberedLines returns lines "line 1" through "line n", each ending in a newline
+func numberedLines(from, to int) string {
+	var b strings.Builder
+	for i := from; i <= to; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	return b.String()
+}
+
+func TestTruncateFileKeepsLastLines(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "logs/agent.log", numberedLines(1, 1000))
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var output TruncateFileOutput
+	callTool(t, ctx, TruncateFile, TruncateFileInput{Path: "logs/agent.log", Lines: 100}, &output)
+
+	want := numberedLines(901, 1000)
+	if content := readTestFile(t, path); content != want {
+		t.Errorf("kept content starts %q, want line 901 onwards", content[:min(len(content), 20)])
+	}
+	if output.LinesRemoved != 900 || output.LinesKept != 100 || output.BytesKept != int64(len(want)) {
+		t.Errorf("got %+v, want 900 lines removed and 100 kept", output)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("permissions were not kept: %v %v", info.Mode(), err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("temporary files were left behind: %v", entries)
+	}
+
+	// A file already within the limit is left alone
+	output = TruncateFileOutput{}
+	callTool(t, ctx, TruncateFile, TruncateFileInput{Path: "logs/agent.log", Lines: 500}, &output)
+	if output.LinesRemoved != 0 || output.LinesKept != 100 || !strings.Contains(output.Message, "nothing was removed") {
+		t.Errorf("got %+v", output)
+	}
+}
+
+func TestLastLinesOffsetAcrossChunks(t *testing.T) {
+	_, dir := newTestWorkspace(t)
+	// Long lines make the kept part span several read chunks; the last line has no newline
+	long := strings.Repeat("x", truncateChunkSize/3)
+	content := strings.Repeat(long+"\n", 10) + "tail"
+	path := writeTestFile(t, dir, "big.log", content)
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	offset, err := lastLinesOffset(file, int64(len(content)), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(7 * (len(long) + 1)); offset != want {
+		t.Errorf("offset = %d, want %d", offset, want)
+	}
+}
+
+func TestTruncateFileKeepsLastBytes(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "out.txt", "first line\nsecond\nthird\n")
+
+	var output TruncateFileOutput
+	// The last 10 bytes start within "second", so only "third" is kept
+	callTool(t, ctx, TruncateFile, TruncateFileInput{Path: "out.txt", Bytes: 10}, &output)
+	if content := readTestFile(t, path); content != "third\n" {
+		t.Errorf("content = %q, want only the complete last line", content)
+	}
+	if output.LinesRemoved != 2 || output.LinesKept != 1 || output.BytesRemoved != 18 {
+		t.Errorf("got %+v", output)
+	}
+}
+
+func TestTruncateFileRejections(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "a.log", "a\n")
+
+	inputs := []TruncateFileInput{
+		{Path: "a.log"},
+		{Path: "a.log", Lines: 1, Bytes: 1},
+		{Path: "missing.log", Lines: 1},
+		{Path: ".", Lines: 1},
+		{Path: "../a.log", Lines: 1},
+	}
+	for _, input := range inputs {
+		if _, err := TruncateFile(ctx, mustMarshal(t, input)); err == nil {
+			t.Errorf("%+v: expected an error", input)
+		}
+	}
+}
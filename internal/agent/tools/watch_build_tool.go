@@ -0,0 +1,235 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchBuildToolDefinition defines the watch_build tool
+var WatchBuildToolDefinition = ToolDefinition{
+	Name: "watch_build",
+	Description: `Watch a directory for file changes and rebuild on every change.
+Changes are debounced, then 'go build' or 'go test' is run and the result recorded.
+The watch stops after 'max_builds' rebuilds or when 'max_duration_seconds' elapses,
+whichever comes first, and returns every result collected so far.
+Use this for a tight edit-and-verify loop while another process edits files.`,
+	InputSchema:           WatchBuildInputSchema,
+	Function:              WatchBuild,
+	CountsTowardLoopLimit: true,
+}
+
+// WatchBuildInput defines the input parameters for the watch_build tool
+type WatchBuildInput struct {
+	Path               string `json:"path,omitempty" jsonschema_description:"Directory to watch recursively. Defaults to the current directory."`
+	Command            string `json:"command,omitempty" jsonschema_description:"Go command to run on change: 'build' or 'test'. Defaults to 'build'."`
+	DebounceMillis     int    `json:"debounce_ms,omitempty" jsonschema_description:"Quiet period after the last change before rebuilding. Defaults to 500."`
+	MaxDurationSeconds int    `json:"max_duration_seconds,omitempty" jsonschema_description:"Maximum time to watch. Defaults to 60, capped at 600."`
+	MaxBuilds          int    `json:"max_builds,omitempty" jsonschema_description:"Stop after this many rebuilds. Defaults to 1."`
+}
+
+// WatchBuildInputSchema is the JSON schema for the watch_build tool
+var WatchBuildInputSchema = GenerateSchema[WatchBuildInput]()
+
+// WatchBuildResult describes a single rebuild triggered by file changes
+type WatchBuildResult struct {
+	Trigger  []string `json:"trigger"`
+	Success  bool     `json:"success"`
+	Output   string   `json:"output,omitempty"`
+	Duration string   `json:"duration"`
+}
+
+// WatchBuildOutput represents the structured output of the watch_build tool
+type WatchBuildOutput struct {
+	Command    string             `json:"command"`
+	Results    []WatchBuildResult `json:"results"`
+	StopReason string             `json:"stop_reason"`
+}
+
+const (
+	defaultWatchDebounce    = 500 * time.Millisecond
+	defaultWatchMaxDuration = 60 * time.Second
+	maxWatchMaxDuration     = 600 * time.Second
+)
+
+// WatchBuild implements the watch_build tool functionality
+func WatchBuild(ctx context.Context, input json.RawMessage) (string, error) {
+	watchInput := WatchBuildInput{}
+	err := DecodeInput(input, &watchInput)
+	if err != nil {
+		return "", err
+	}
+
+	maxDuration := defaultWatchMaxDuration
+	if watchInput.MaxDurationSeconds > 0 {
+		maxDuration = time.Duration(watchInput.MaxDurationSeconds) * time.Second
+	}
+	if maxDuration > maxWatchMaxDuration {
+		maxDuration = maxWatchMaxDuration
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, maxDuration)
+	defer cancel()
+
+	output, err := watchAndBuild(ctx, watchInput)
+	if err != nil {
+		return "", err
+	}
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// watchAndBuild watches the requested directory and rebuilds on change until ctx is done
+// or the build limit is reached
+func watchAndBuild(ctx context.Context, watchInput WatchBuildInput) (WatchBuildOutput, error) {
+	root := workspaceDir(ctx)
+	if watchInput.Path != "" {
+		resolved, err := ResolvePath(ctx, watchInput.Path)
+		if err != nil {
+			return WatchBuildOutput{}, err
+		}
+		root = resolved
+	}
+
+	command := "build"
+	if watchInput.Command != "" {
+		command = watchInput.Command
+	}
+	if command != "build" && command != "test" {
+		return WatchBuildOutput{}, fmt.Errorf("invalid command: %s. Must be 'build' or 'test'", command)
+	}
+
+	debounce := defaultWatchDebounce
+	if watchInput.DebounceMillis > 0 {
+		debounce = time.Duration(watchInput.DebounceMillis) * time.Millisecond
+	}
+
+	maxBuilds := watchInput.MaxBuilds
+	if maxBuilds <= 0 {
+		maxBuilds = 1
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return WatchBuildOutput{}, fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	ignoreRules := ignoreRulesFor(ctx, root)
+	if err := addWatchDirs(watcher, root, ignoreRules); err != nil {
+		return WatchBuildOutput{}, err
+	}
+
+	output := WatchBuildOutput{
+		Command: "go " + command + " ./...",
+		Results: []WatchBuildResult{},
+	}
+
+	// A stopped timer that is reset on every change
+	timer := time.NewTimer(debounce)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
+	pending := make(map[string]bool)
+
+	for {
+		select {
+		case <-ctx.Done():
+			output.StopReason = fmt.Sprintf("watch ended: %v", ctx.Err())
+			return output, nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				output.StopReason = "watcher closed"
+				return output, nil
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			info, statErr := os.Stat(event.Name)
+			isDir := statErr == nil && info.IsDir()
+			if ignoreRules.IgnoredPath(event.Name, isDir) {
+				continue
+			}
+			if event.Has(fsnotify.Create) && isDir {
+				_ = addWatchDirs(watcher, event.Name, ignoreRules)
+			}
+			pending[event.Name] = true
+			timer.Reset(debounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				output.StopReason = "watcher closed"
+				return output, nil
+			}
+			output.StopReason = fmt.Sprintf("watcher error: %v", err)
+			return output, nil
+
+		case <-timer.C:
+			trigger := make([]string, 0, len(pending))
+			for name := range pending {
+				trigger = append(trigger, name)
+			}
+			pending = make(map[string]bool)
+
+			output.Results = append(output.Results, runWatchCommand(ctx, root, command, trigger))
+			if len(output.Results) >= maxBuilds {
+				output.StopReason = fmt.Sprintf("reached max_builds (%d)", maxBuilds)
+				return output, nil
+			}
+		}
+	}
+}
+
+// addWatchDirs registers dir and all of its non-hidden, non-ignored subdirectories with the watcher
+func addWatchDirs(watcher *fsnotify.Watcher, dir string, ignoreRules *IgnoreRules) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != dir && (strings.HasPrefix(info.Name(), ".") || ignoreRules.IgnoredPath(path, true)) {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// runWatchCommand runs the Go command for a single rebuild, honoring cancellation
+func runWatchCommand(ctx context.Context, root, command string, trigger []string) WatchBuildResult {
+	start := time.Now()
+
+	cmd := exec.CommandContext(ctx, "go", command, "./...")
+	cmd.Dir = root
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	return WatchBuildResult{
+		Trigger:  trigger,
+		Success:  err == nil,
+		Output:   out.String(),
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+}
@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestWatchBuildRebuildsOnChange(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/watch")
+	path := writeTestFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	type watchResult struct {
+		output WatchBuildOutput
+		err    error
+	}
+	done := make(chan watchResult, 1)
+	go func() {
+		output, err := watchAndBuild(ctx, WatchBuildInput{DebounceMillis: 50})
+		done <- watchResult{output, err}
+	}()
+
+	// The watcher registers asynchronously, so keep touching the file until a rebuild is reported
+	broken := []byte("package main\n\nfunc main() { undefinedCall() }\n")
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	var result watchResult
+wait:
+	for {
+		select {
+		case result = <-done:
+			break wait
+		case <-ticker.C:
+			if err := os.WriteFile(path, broken, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if result.err != nil {
+		t.Fatal(result.err)
+	}
+	if len(result.output.Results) != 1 {
+		t.Fatalf("got %d results, want 1 (stop reason %q)", len(result.output.Results), result.output.StopReason)
+	}
+	rebuild := result.output.Results[0]
+	if rebuild.Success || !strings.Contains(rebuild.Output, "undefinedCall") {
+		t.Errorf("expected a failed rebuild mentioning undefinedCall, got %+v", rebuild)
+	}
+	if len(rebuild.Trigger) == 0 || !strings.HasSuffix(rebuild.Trigger[0], "main.go") {
+		t.Errorf("trigger = %v, want main.go", rebuild.Trigger)
+	}
+	if !strings.Contains(result.output.StopReason, "max_builds") {
+		t.Errorf("stop reason = %q, want max_builds", result.output.StopReason)
+	}
+}
+
+func TestWatchBuildStopsOnCancel(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	testGoModule(t, dir, "example.com/watch")
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	output, err := watchAndBuild(ctx, WatchBuildInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(output.Results) != 0 || !strings.Contains(output.StopReason, "canceled") {
+		t.Errorf("got %+v, want no results and a cancellation stop reason", output)
+	}
+}
+
+func TestAddWatchDirsSkipsIgnoredDirectories(t *testing.T) {
+	_, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, MetamorphIgnoreFile, "dist/\n")
+	writeTestFile(t, dir, "pkg/lib.go", "package pkg\n")
+	writeTestFile(t, dir, "dist/out/app", "binary")
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+	if err := addWatchDirs(watcher, dir, LoadIgnoreRules(dir)); err != nil {
+		t.Fatal(err)
+	}
+
+	watched := watcher.WatchList()
+	sort.Strings(watched)
+	want := []string{dir, filepath.Join(dir, "pkg")}
+	if !reflect.DeepEqual(watched, want) {
+		t.Errorf("watched %v, want %v", watched, want)
+	}
+}
+
+func TestWatchBuildRejectsUnknownCommand(t *testing.T) {
+	ctx, _ := newTestWorkspace(t)
+
+	if _, err := WatchBuild(ctx, []byte(`{"command": "run"}`)); err == nil {
+		t.Error("expected an error for a command other than build or test")
+	}
+}
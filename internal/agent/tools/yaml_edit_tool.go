@@ -0,0 +1,287 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YamlEditToolDefinition defines the yaml_edit tool
+var YamlEditToolDefinition = ToolDefinition{
+	Name: "yaml_edit",
+	Description: `Set a value in a YAML file while preserving comments and key order.
+The key is a dotted path; use numbers for sequence indexes, e.g. 'jobs.build.steps.0.name'.
+The value is parsed as YAML, so '3', 'true', '[a, b]', or '{k: v}' produce typed values; quote it
+to force a string. Set 'create' to add missing mapping keys along the path.
+Replacing a single-line scalar rewrites only that value; other edits re-encode the file, which keeps
+comments and key order but may drop blank lines and normalize indentation and quoting.
+Prefer this over file_editor for CI and config files. Returns the value before and after the edit.`,
+	InputSchema:           YamlEditInputSchema,
+	Function:              YamlEdit,
+	CountsTowardLoopLimit: true,
+}
+
+// YamlEditInput defines the input parameters for the yaml_edit tool
+type YamlEditInput struct {
+	Path   string `json:"path" jsonschema_required:"true" jsonschema_description:"Path to the YAML file"`
+	Key    string `json:"key" jsonschema_required:"true" jsonschema_description:"Dotted path of the value to set" jsonschema_example:"jobs.build.runs-on"`
+	Value  string `json:"value" jsonschema_required:"true" jsonschema_description:"New value, written as YAML"`
+	Create bool   `json:"create,omitempty" jsonschema_description:"If true, create missing mapping keys along the path"`
+}
+
+// YamlEditInputSchema is the JSON schema for the yaml_edit tool
+var YamlEditInputSchema = GenerateSchema[YamlEditInput]()
+
+// YamlEditOutput represents the structured output of the yaml_edit tool
+type YamlEditOutput struct {
+	Path    string `json:"path"`
+	Key     string `json:"key"`
+	Before  string `json:"before"`
+	After   string `json:"after"`
+	Created bool   `json:"created"`
+}
+
+// YamlEdit implements the yaml_edit tool functionality
+func YamlEdit(ctx context.Context, input json.RawMessage) (string, error) {
+	editInput := YamlEditInput{}
+	err := json.Unmarshal(input, &editInput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if editInput.Path == "" {
+		return "", fmt.Errorf("path parameter is required")
+	}
+	if editInput.Key == "" {
+		return "", fmt.Errorf("key parameter is required")
+	}
+	editInput.Path, err = ResolvePath(ctx, editInput.Path)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(editInput.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file '%s': %w", editInput.Path, err)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return "", fmt.Errorf("failed to parse YAML in %s: %w", editInput.Path, err)
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return "", fmt.Errorf("%s does not contain a YAML document", editInput.Path)
+	}
+
+	var parsedValue yaml.Node
+	if err := yaml.Unmarshal([]byte(editInput.Value), &parsedValue); err != nil {
+		return "", fmt.Errorf("failed to parse value as YAML: %w", err)
+	}
+	newValue := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	if len(parsedValue.Content) > 0 {
+		newValue = parsedValue.Content[0]
+	}
+
+	target, created, err := findYamlNode(document.Content[0], strings.Split(editInput.Key, "."), editInput.Create)
+	if err != nil {
+		return "", err
+	}
+
+	output := YamlEditOutput{
+		Path:    editInput.Path,
+		Key:     editInput.Key,
+		Created: created,
+	}
+	if !created {
+		output.Before = yamlNodeString(target)
+	}
+
+	// Replacing one scalar with another only rewrites that value in place, so blank lines
+	// and formatting elsewhere in the file survive untouched
+	updated, spliced := spliceYamlScalar(content, target, newValue, created)
+
+	// Swap in the new value but keep the comments attached to the old one
+	headComment, lineComment, footComment := target.HeadComment, target.LineComment, target.FootComment
+	*target = *newValue
+	if target.HeadComment == "" {
+		target.HeadComment = headComment
+	}
+	if target.LineComment == "" {
+		target.LineComment = lineComment
+	}
+	if target.FootComment == "" {
+		target.FootComment = footComment
+	}
+	output.After = yamlNodeString(target)
+
+	if !spliced {
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(yamlIndent(string(content)))
+		if err := encoder.Encode(&document); err != nil {
+			return "", fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return "", fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		updated = buf.Bytes()
+	}
+
+	if err := os.WriteFile(editInput.Path, updated, 0644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	recordReadHash(editInput.Path, updated)
+
+	jsonOutput, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	return string(jsonOutput), nil
+}
+
+// spliceYamlScalar rewrites the single-line scalar target in content as newValue, leaving every
+// other byte of the file as it was. It reports false when the edit isn't a one-line scalar
+// replacement, or the result doesn't parse back as expected, and the caller re-encodes instead.
+func spliceYamlScalar(content []byte, target, newValue *yaml.Node, created bool) ([]byte, bool) {
+	if created || target.Kind != yaml.ScalarNode || newValue.Kind != yaml.ScalarNode ||
+		target.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 || newValue.LineComment != "" || target.Line < 1 || target.Column < 1 {
+		return nil, false
+	}
+
+	rendered, err := yaml.Marshal(newValue)
+	if err != nil {
+		return nil, false
+	}
+	replacement := strings.TrimSuffix(string(rendered), "\n")
+	if strings.Contains(replacement, "\n") {
+		return nil, false
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	if target.Line > len(lines) {
+		return nil, false
+	}
+	line := []rune(lines[target.Line-1])
+	start := target.Column - 1
+	if start > len(line) {
+		return nil, false
+	}
+
+	// The old scalar runs to the end of the line or up to its trailing comment
+	rest := string(line[start:])
+	body := strings.TrimRight(rest, "\r\n")
+	end := len(body)
+	if target.LineComment != "" {
+		end = strings.LastIndex(body, target.LineComment)
+		if end < 0 {
+			return nil, false
+		}
+	}
+	old := strings.TrimRight(body[:end], " \t")
+
+	// Make sure exactly the old scalar was found, not part of a flow collection or a multi-line value
+	var oldNode yaml.Node
+	if err := yaml.Unmarshal([]byte(old), &oldNode); err != nil || len(oldNode.Content) != 1 ||
+		oldNode.Content[0].Kind != yaml.ScalarNode || oldNode.Content[0].Value != target.Value {
+		return nil, false
+	}
+
+	lines[target.Line-1] = string(line[:start]) + replacement + rest[len(old):]
+	updated := []byte(strings.Join(lines, ""))
+
+	// The spliced file must still be valid YAML
+	var check yaml.Node
+	if err := yaml.Unmarshal(updated, &check); err != nil {
+		return nil, false
+	}
+	return updated, true
+}
+
+// findYamlNode walks keys from node and returns the value node at the end of the path.
+// With create, missing mapping keys are added and the returned bool reports that the value is new.
+func findYamlNode(node *yaml.Node, keys []string, create bool) (*yaml.Node, bool, error) {
+	created := false
+	for i, key := range keys {
+		walked := strings.Join(keys[:i], ".")
+		if walked == "" {
+			walked = "the document root"
+		}
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			var next *yaml.Node
+			for j := 0; j+1 < len(node.Content); j += 2 {
+				if node.Content[j].Value == key {
+					next = node.Content[j+1]
+					break
+				}
+			}
+			if next == nil {
+				if !create {
+					return nil, false, fmt.Errorf("key %q not found under %s", key, walked)
+				}
+				next = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, next)
+				created = true
+			}
+			node = next
+
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node.Content) {
+				return nil, false, fmt.Errorf("invalid index %q for sequence at %s (length %d)", key, walked, len(node.Content))
+			}
+			node = node.Content[index]
+
+		default:
+			if create && node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+				// An empty value such as 'key:' can be turned into a mapping
+				*node = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				next := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, next)
+				node = next
+				created = true
+				continue
+			}
+			return nil, false, fmt.Errorf("cannot descend into scalar value at %s", walked)
+		}
+	}
+	return node, created, nil
+}
+
+// yamlNodeString renders a node as compact YAML for reporting
+func yamlNodeString(node *yaml.Node) string {
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	copied := *node
+	copied.HeadComment, copied.LineComment, copied.FootComment = "", "", ""
+	out, err := yaml.Marshal(&copied)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// yamlIndent guesses the indentation width of a YAML document, defaulting to 2 spaces
+func yamlIndent(content string) int {
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		if indent == 0 || trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+		if indent >= 2 && indent <= 8 {
+			return indent
+		}
+		break
+	}
+	return 2
+}
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+const workflowFixture = `name: ci
+
+on: push
+
+jobs:
+  build:
+    # Pin the runner so builds are reproducible
+    runs-on: ubuntu-22.04 # keep in sync with release
+    timeout: 10
+    steps:
+      - name: checkout
+      - name: test
+`
+
+func TestYamlEditPreservesCommentsAndOrder(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "ci.yml", workflowFixture)
+
+	var output YamlEditOutput
+	callTool(t, ctx, YamlEdit, YamlEditInput{Path: "ci.yml", Key: "jobs.build.runs-on", Value: "ubuntu-24.04"}, &output)
+
+	if output.Before != "ubuntu-22.04" || output.After != "ubuntu-24.04" {
+		t.Errorf("before/after = %q/%q", output.Before, output.After)
+	}
+	want := strings.Replace(workflowFixture, "ubuntu-22.04", "ubuntu-24.04", 1)
+	if got := readTestFile(t, path); got != want {
+		t.Errorf("scalar edit should only touch the value, got:\n%s", got)
+	}
+}
+
+func TestYamlEditReencodesStructuredValues(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "ci.yml", workflowFixture)
+
+	var output YamlEditOutput
+	callTool(t, ctx, YamlEdit, YamlEditInput{Path: "ci.yml", Key: "jobs.build.env.GOFLAGS", Value: "-mod=mod", Create: true}, &output)
+	if !output.Created || output.Before != "" {
+		t.Errorf("expected a created key, got %+v", output)
+	}
+
+	got := readTestFile(t, path)
+	for _, want := range []string{"# Pin the runner so builds are reproducible", "# keep in sync with release", "GOFLAGS: -mod=mod"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("edited file is missing %q:\n%s", want, got)
+		}
+	}
+	order := []string{"name:", "on:", "jobs:", "runs-on:", "timeout:", "steps:", "env:"}
+	last := -1
+	for _, key := range order {
+		index := strings.Index(got, key)
+		if index < last {
+			t.Errorf("key %s moved out of order:\n%s", key, got)
+		}
+		last = index
+	}
+}
+
+func TestYamlEditSequenceIndex(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	path := writeTestFile(t, dir, "ci.yml", workflowFixture)
+
+	var output YamlEditOutput
+	callTool(t, ctx, YamlEdit, YamlEditInput{Path: "ci.yml", Key: "jobs.build.steps.1.name", Value: "unit tests"}, &output)
+	if output.Before != "test" {
+		t.Errorf("before = %q, want test", output.Before)
+	}
+	if got := readTestFile(t, path); !strings.Contains(got, "- name: unit tests\n") || !strings.Contains(got, "\non: push\n\njobs:") {
+		t.Errorf("unexpected result:\n%s", got)
+	}
+}
+
+func TestYamlEditErrors(t *testing.T) {
+	ctx, dir := newTestWorkspace(t)
+	writeTestFile(t, dir, "ci.yml", workflowFixture)
+
+	tests := []struct {
+		name  string
+		input YamlEditInput
+		want  string
+	}{
+		{"missing key", YamlEditInput{Path: "ci.yml", Key: "jobs.lint", Value: "x"}, `key "lint" not found under jobs`},
+		{"bad index", YamlEditInput{Path: "ci.yml", Key: "jobs.build.steps.5", Value: "x"}, "invalid index"},
+		{"into scalar", YamlEditInput{Path: "ci.yml", Key: "name.first", Value: "x"}, "cannot descend into scalar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := YamlEdit(ctx, mustMarshal(t, tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
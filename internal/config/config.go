@@ -3,11 +3,14 @@ package config
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"metamorph/internal/agent/tools"
 	"metamorph/internal/logger"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/anthropics/anthropic-sdk-go"
 )
@@ -19,12 +22,31 @@ type Config struct {
 	Model           string
 	MaxTokens       int64
 
+	// Tool settings
+	MaxToolInputSize int
+	ReadOnly         bool
+	MemoryFile       string
+	ToolCallDelay    time.Duration
+	AllowedTools     []string
+	StrictAllowlist  bool
+	WorkingDir       string
+
 	// User interface settings
 	GetUserMessage func() (string, bool)
+	IdleTimeout    time.Duration
+	MessagePrefix  string
+	MessageSuffix  string
 
 	// Agent settings
-	Client *anthropic.Client
-	Tools  []tools.ToolDefinition
+	Client   *anthropic.Client
+	Tools    []tools.ToolDefinition
+	MaxTurns int
+	Macros   []tools.Macro
+	Linters  []tools.Linter
+
+	// Observability settings
+	MetricsAddr    string
+	RedactPatterns []string
 }
 
 // LoadFromEnv loads configuration from environment variables
@@ -35,9 +57,19 @@ func LoadFromEnv() (*Config, error) {
 	config := &Config{
 		AnthropicAPIKey: os.Getenv("ANTHROPIC_API_KEY"),
 		Model:           getEnvOrDefault("CLAUDE_MODEL", anthropic.ModelClaude3_5HaikuLatest),
+		MetricsAddr:     os.Getenv("METRICS_ADDR"),
+		ReadOnly:        os.Getenv("READ_ONLY") == "true",
+		MessagePrefix:   os.Getenv("MESSAGE_PREFIX"),
+		MessageSuffix:   os.Getenv("MESSAGE_SUFFIX"),
+		MemoryFile:      os.Getenv("MEMORY_FILE"),
+		WorkingDir:      os.Getenv("WORKDIR"),
 	}
 
 	log.Debug().Str("model", config.Model).Msg("Loaded model configuration")
+	log.Debug().Bool("readOnly", config.ReadOnly).Msg("Loaded read-only configuration")
+	if config.WorkingDir != "" {
+		log.Debug().Str("workingDir", config.WorkingDir).Msg("Loaded working directory configuration")
+	}
 
 	// Parse max tokens
 	maxTokensStr := getEnvOrDefault("MAX_TOKENS", "1024")
@@ -49,6 +81,95 @@ func LoadFromEnv() (*Config, error) {
 	log.Debug().Int64("maxTokens", maxTokens).Msg("Loaded max tokens configuration")
 	config.MaxTokens = maxTokens
 
+	// Parse max tool input size (0 uses the agent default)
+	maxInputStr := getEnvOrDefault("MAX_TOOL_INPUT_BYTES", "0")
+	maxInputSize, err := strconv.Atoi(maxInputStr)
+	if err != nil || maxInputSize < 0 {
+		log.Error().Err(err).Str("value", maxInputStr).Msg("Invalid MAX_TOOL_INPUT_BYTES value")
+		return nil, fmt.Errorf("invalid MAX_TOOL_INPUT_BYTES value: %q", maxInputStr)
+	}
+	config.MaxToolInputSize = maxInputSize
+	log.Debug().Int("maxToolInputSize", maxInputSize).Msg("Loaded max tool input size configuration")
+
+	// Parse idle timeout (0 disables it)
+	idleTimeoutStr := getEnvOrDefault("IDLE_TIMEOUT_SECONDS", "0")
+	idleTimeoutSeconds, err := strconv.ParseInt(idleTimeoutStr, 10, 64)
+	if err != nil || idleTimeoutSeconds < 0 {
+		log.Error().Err(err).Str("value", idleTimeoutStr).Msg("Invalid IDLE_TIMEOUT_SECONDS value")
+		return nil, fmt.Errorf("invalid IDLE_TIMEOUT_SECONDS value: %q", idleTimeoutStr)
+	}
+	config.IdleTimeout = time.Duration(idleTimeoutSeconds) * time.Second
+	log.Debug().Dur("idleTimeout", config.IdleTimeout).Msg("Loaded idle timeout configuration")
+
+	// Parse minimum delay between tool calls (0 disables it)
+	toolCallDelayStr := getEnvOrDefault("TOOL_CALL_DELAY_MS", "0")
+	toolCallDelayMs, err := strconv.ParseInt(toolCallDelayStr, 10, 64)
+	if err != nil || toolCallDelayMs < 0 {
+		log.Error().Err(err).Str("value", toolCallDelayStr).Msg("Invalid TOOL_CALL_DELAY_MS value")
+		return nil, fmt.Errorf("invalid TOOL_CALL_DELAY_MS value: %q", toolCallDelayStr)
+	}
+	config.ToolCallDelay = time.Duration(toolCallDelayMs) * time.Millisecond
+	log.Debug().Dur("toolCallDelay", config.ToolCallDelay).Msg("Loaded tool call delay configuration")
+
+	// Parse max conversation turns (0 means unlimited)
+	maxTurnsStr := getEnvOrDefault("MAX_TURNS", "0")
+	maxTurns, err := strconv.Atoi(maxTurnsStr)
+	if err != nil || maxTurns < 0 {
+		log.Error().Err(err).Str("value", maxTurnsStr).Msg("Invalid MAX_TURNS value")
+		return nil, fmt.Errorf("invalid MAX_TURNS value: %q", maxTurnsStr)
+	}
+	config.MaxTurns = maxTurns
+	log.Debug().Int("maxTurns", maxTurns).Msg("Loaded max turns configuration")
+
+	// Parse extra redaction patterns: a JSON array of regular expressions, or a single one
+	if redactStr := os.Getenv("REDACT_PATTERNS"); redactStr != "" {
+		patterns := []string{redactStr}
+		if strings.HasPrefix(strings.TrimSpace(redactStr), "[") {
+			if err := json.Unmarshal([]byte(redactStr), &patterns); err != nil {
+				log.Error().Err(err).Msg("Invalid REDACT_PATTERNS value")
+				return nil, fmt.Errorf("invalid REDACT_PATTERNS value: %w", err)
+			}
+		}
+		config.RedactPatterns = patterns
+		log.Debug().Int("redactPatterns", len(patterns)).Msg("Loaded redaction configuration")
+	}
+
+	// Parse the tool allowlist: comma-separated tool names
+	if allowedStr := os.Getenv("ALLOWED_TOOLS"); allowedStr != "" {
+		for _, name := range strings.Split(allowedStr, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				config.AllowedTools = append(config.AllowedTools, name)
+			}
+		}
+		config.StrictAllowlist = os.Getenv("STRICT_TOOL_ALLOWLIST") == "true"
+		log.Debug().
+			Strs("allowedTools", config.AllowedTools).
+			Bool("strict", config.StrictAllowlist).
+			Msg("Loaded tool allowlist configuration")
+	}
+
+	// Load macro definitions if a file is configured
+	if macrosFile := os.Getenv("MACROS_FILE"); macrosFile != "" {
+		macros, err := tools.LoadMacros(macrosFile)
+		if err != nil {
+			log.Error().Err(err).Str("file", macrosFile).Msg("Invalid MACROS_FILE")
+			return nil, fmt.Errorf("invalid MACROS_FILE: %w", err)
+		}
+		config.Macros = macros
+		log.Debug().Int("macros", len(macros)).Msg("Loaded macro configuration")
+	}
+
+	// Load linter definitions if a file is configured
+	if lintersFile := os.Getenv("LINTERS_FILE"); lintersFile != "" {
+		linters, err := tools.LoadLinters(lintersFile)
+		if err != nil {
+			log.Error().Err(err).Str("file", lintersFile).Msg("Invalid LINTERS_FILE")
+			return nil, fmt.Errorf("invalid LINTERS_FILE: %w", err)
+		}
+		config.Linters = linters
+		log.Debug().Int("linters", len(linters)).Msg("Loaded linter configuration")
+	}
+
 	// Validate required config
 	if config.AnthropicAPIKey == "" {
 		log.Error().Msg("ANTHROPIC_API_KEY environment variable is not set")
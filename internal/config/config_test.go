@@ -0,0 +1,164 @@
+package config
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLoadFromEnvIdleTimeout(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+
+	t.Setenv("IDLE_TIMEOUT_SECONDS", "")
+	cfg, err := LoadFromEnv()
+	if err != nil {
+		t.Fatalf("LoadFromEnv: %v", err)
+	}
+	if cfg.IdleTimeout != 0 {
+		t.Errorf("unset IDLE_TIMEOUT_SECONDS gave %v, want 0 (disabled)", cfg.IdleTimeout)
+	}
+
+	t.Setenv("IDLE_TIMEOUT_SECONDS", "90")
+	cfg, err = LoadFromEnv()
+	if err != nil {
+		t.Fatalf("LoadFromEnv: %v", err)
+	}
+	if cfg.IdleTimeout != 90*time.Second {
+		t.Errorf("IdleTimeout = %v, want 90s", cfg.IdleTimeout)
+	}
+
+	for _, invalid := range []string{"-1", "soon"} {
+		t.Setenv("IDLE_TIMEOUT_SECONDS", invalid)
+		if _, err := LoadFromEnv(); err == nil {
+			t.Errorf("IDLE_TIMEOUT_SECONDS=%q: expected an error", invalid)
+		}
+	}
+}
+
+func TestLoadFromEnvReadOnly(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+
+	for value, want := range map[string]bool{"true": true, "": false, "1": false} {
+		t.Setenv("READ_ONLY", value)
+		cfg, err := LoadFromEnv()
+		if err != nil {
+			t.Fatalf("LoadFromEnv: %v", err)
+		}
+		if cfg.ReadOnly != want {
+			t.Errorf("READ_ONLY=%q gave ReadOnly %v, want %v", value, cfg.ReadOnly, want)
+		}
+	}
+}
+
+func TestLoadFromEnvMaxTurns(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+
+	t.Setenv("MAX_TURNS", "25")
+	cfg, err := LoadFromEnv()
+	if err != nil {
+		t.Fatalf("LoadFromEnv: %v", err)
+	}
+	if cfg.MaxTurns != 25 {
+		t.Errorf("MaxTurns = %d, want 25", cfg.MaxTurns)
+	}
+
+	for _, invalid := range []string{"-3", "many"} {
+		t.Setenv("MAX_TURNS", invalid)
+		if _, err := LoadFromEnv(); err == nil {
+			t.Errorf("MAX_TURNS=%q: expected an error", invalid)
+		}
+	}
+}
+
+func TestLoadFromEnvRedactPatterns(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{`internal-[0-9]+`, []string{`internal-[0-9]+`}},
+		{`["internal-[0-9]+", "tok_[a-z]+"]`, []string{`internal-[0-9]+`, `tok_[a-z]+`}},
+	}
+	for _, tt := range tests {
+		t.Setenv("REDACT_PATTERNS", tt.value)
+		cfg, err := LoadFromEnv()
+		if err != nil {
+			t.Fatalf("REDACT_PATTERNS=%q: %v", tt.value, err)
+		}
+		if !slices.Equal(cfg.RedactPatterns, tt.want) {
+			t.Errorf("REDACT_PATTERNS=%q gave %q, want %q", tt.value, cfg.RedactPatterns, tt.want)
+		}
+	}
+
+	t.Setenv("REDACT_PATTERNS", `["unterminated`)
+	if _, err := LoadFromEnv(); err == nil {
+		t.Error("expected an error for a malformed JSON array")
+	}
+}
+
+func TestLoadFromEnvToolCallDelay(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+
+	t.Setenv("TOOL_CALL_DELAY_MS", "")
+	cfg, err := LoadFromEnv()
+	if err != nil {
+		t.Fatalf("LoadFromEnv: %v", err)
+	}
+	if cfg.ToolCallDelay != 0 {
+		t.Errorf("unset TOOL_CALL_DELAY_MS gave %v, want 0 (disabled)", cfg.ToolCallDelay)
+	}
+
+	t.Setenv("TOOL_CALL_DELAY_MS", "250")
+	cfg, err = LoadFromEnv()
+	if err != nil {
+		t.Fatalf("LoadFromEnv: %v", err)
+	}
+	if cfg.ToolCallDelay != 250*time.Millisecond {
+		t.Errorf("ToolCallDelay = %v, want 250ms", cfg.ToolCallDelay)
+	}
+
+	for _, invalid := range []string{"-5", "fast"} {
+		t.Setenv("TOOL_CALL_DELAY_MS", invalid)
+		if _, err := LoadFromEnv(); err == nil {
+			t.Errorf("TOOL_CALL_DELAY_MS=%q: expected an error", invalid)
+		}
+	}
+}
+
+func TestLoadFromEnvAllowedTools(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	t.Setenv("ALLOWED_TOOLS", " file_reader, ,list_files ")
+	t.Setenv("STRICT_TOOL_ALLOWLIST", "true")
+
+	cfg, err := LoadFromEnv()
+	if err != nil {
+		t.Fatalf("LoadFromEnv: %v", err)
+	}
+	if !slices.Equal(cfg.AllowedTools, []string{"file_reader", "list_files"}) || !cfg.StrictAllowlist {
+		t.Errorf("AllowedTools = %v, strict %v", cfg.AllowedTools, cfg.StrictAllowlist)
+	}
+
+	t.Setenv("ALLOWED_TOOLS", "")
+	cfg, err = LoadFromEnv()
+	if err != nil {
+		t.Fatalf("LoadFromEnv: %v", err)
+	}
+	if cfg.AllowedTools != nil || cfg.StrictAllowlist {
+		t.Errorf("unset ALLOWED_TOOLS gave %v, strict %v", cfg.AllowedTools, cfg.StrictAllowlist)
+	}
+}
+
+func TestLoadFromEnvWorkingDir(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	t.Setenv("WORKDIR", "/srv/project")
+
+	cfg, err := LoadFromEnv()
+	if err != nil {
+		t.Fatalf("LoadFromEnv: %v", err)
+	}
+	if cfg.WorkingDir != "/srv/project" {
+		t.Errorf("WorkingDir = %q, want /srv/project", cfg.WorkingDir)
+	}
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"os"
 	"time"
 
@@ -32,3 +33,27 @@ func Initialize(debug bool) {
 func Get() *zerolog.Logger {
 	return &log.Logger
 }
+
+// sessionIDKey is the context key under which the session ID is stored
+type sessionIDKey struct{}
+
+// WithSessionID returns a copy of ctx carrying the given session ID
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey{}, sessionID)
+}
+
+// SessionID returns the session ID stored in ctx, or "" if there is none
+func SessionID(ctx context.Context) string {
+	sessionID, _ := ctx.Value(sessionIDKey{}).(string)
+	return sessionID
+}
+
+// FromContext returns the global logger annotated with the session ID from ctx, if any
+func FromContext(ctx context.Context) *zerolog.Logger {
+	sessionID := SessionID(ctx)
+	if sessionID == "" {
+		return Get()
+	}
+	l := log.Logger.With().Str("sessionID", sessionID).Logger()
+	return &l
+}
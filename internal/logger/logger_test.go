@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// captureLogs redirects the global logger into a buffer for the duration of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := log.Logger
+	log.Logger = zerolog.New(zerolog.SyncWriter(&buf))
+	t.Cleanup(func() { log.Logger = previous })
+	return &buf
+}
+
+func TestFromContextConcurrentSessions(t *testing.T) {
+	buf := captureLogs(t)
+
+	sessions := []string{"session-a", "session-b"}
+	var wg sync.WaitGroup
+	for _, sessionID := range sessions {
+		wg.Add(1)
+		go func(sessionID string) {
+			defer wg.Done()
+			ctx := WithSessionID(context.Background(), sessionID)
+			for i := 0; i < 50; i++ {
+				FromContext(ctx).Info().Str("owner", sessionID).Msg(fmt.Sprintf("call %d", i))
+			}
+		}(sessionID)
+	}
+	wg.Wait()
+
+	counts := make(map[string]int)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		if entry["sessionID"] != entry["owner"] {
+			t.Errorf("log line from %v carries session %v", entry["owner"], entry["sessionID"])
+		}
+		counts[fmt.Sprint(entry["sessionID"])]++
+	}
+	for _, sessionID := range sessions {
+		if counts[sessionID] != 50 {
+			t.Errorf("got %d lines for %s, want 50", counts[sessionID], sessionID)
+		}
+	}
+}
+
+func TestFromContextWithoutSession(t *testing.T) {
+	buf := captureLogs(t)
+
+	FromContext(context.Background()).Info().Msg("no session")
+	if strings.Contains(buf.String(), "sessionID") {
+		t.Errorf("expected no sessionID field: %s", buf.String())
+	}
+	if SessionID(context.Background()) != "" {
+		t.Error("expected an empty session ID from a bare context")
+	}
+}
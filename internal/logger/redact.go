@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// redactedValue replaces secrets in redacted text
+const redactedValue = "[REDACTED]"
+
+// defaultRedactionPatterns match common credentials. When a pattern has a group named
+// "secret", only that group is masked so the surrounding key or header name stays readable.
+var defaultRedactionPatterns = []string{
+	`sk-ant-[A-Za-z0-9_\-]{10,}`,
+	`\bsk-[A-Za-z0-9_\-]{20,}`,
+	`\bgh[pousr]_[A-Za-z0-9]{20,}`,
+	`\bgithub_pat_[A-Za-z0-9_]{20,}`,
+	`\bxox[abprs]-[A-Za-z0-9\-]{10,}`,
+	`\bAKIA[0-9A-Z]{16}\b`,
+	`(?i)authorization["']?\s*[:=]\s*["']?(?:(?:bearer|basic|token)\s+)?(?P<secret>[^\s"',\\]+)`,
+	`(?i)(?:api[_-]?key|access[_-]?token|auth[_-]?token|secret|password|passwd)["']?\s*[:=]\s*["']?(?P<secret>[^\s"',\\]{8,})`,
+}
+
+var (
+	redactionPatterns []*regexp.Regexp
+	redactionMutex    sync.RWMutex
+)
+
+func init() {
+	if err := SetRedactionPatterns(nil); err != nil {
+		panic(err)
+	}
+}
+
+// SetRedactionPatterns replaces the configured redaction patterns with the defaults plus extra.
+// Each pattern is a regular expression; see defaultRedactionPatterns for the "secret" group.
+func SetRedactionPatterns(extra []string) error {
+	var compiled []*regexp.Regexp
+	for _, pattern := range append(append([]string{}, defaultRedactionPatterns...), extra...) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+
+	redactionMutex.Lock()
+	defer redactionMutex.Unlock()
+	redactionPatterns = compiled
+	return nil
+}
+
+// Redact masks every secret in text that matches a redaction pattern
+func Redact(text string) string {
+	redactionMutex.RLock()
+	defer redactionMutex.RUnlock()
+
+	for _, re := range redactionPatterns {
+		group := re.SubexpIndex("secret")
+		if group < 0 {
+			text = re.ReplaceAllString(text, redactedValue)
+			continue
+		}
+		text = re.ReplaceAllStringFunc(text, func(match string) string {
+			indexes := re.FindStringSubmatchIndex(match)
+			if indexes == nil || indexes[2*group] < 0 {
+				return redactedValue
+			}
+			return match[:indexes[2*group]] + redactedValue + match[indexes[2*group+1]:]
+		})
+	}
+	return text
+}
+
+// RedactStrings masks secrets in each of values, such as the arguments of a logged command
+func RedactStrings(values []string) []string {
+	redacted := make([]string, len(values))
+	for i, value := range values {
+		redacted[i] = Redact(value)
+	}
+	return redacted
+}
+
+// RedactError returns err with secrets masked in its message, for logging with Err.
+// A nil error stays nil.
+func RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return errors.New(Redact(err.Error()))
+}
+
+// RedactJSON masks secrets in a JSON document. Secrets never include quotes or backslashes,
+// so the result stays valid JSON; if it somehow does not, the value is re-encoded as a string.
+func RedactJSON(data json.RawMessage) json.RawMessage {
+	redacted := Redact(string(data))
+	if json.Valid([]byte(redacted)) {
+		return json.RawMessage(redacted)
+	}
+	encoded, _ := json.Marshal(redacted)
+	return encoded
+}
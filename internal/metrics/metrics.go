@@ -0,0 +1,113 @@
+// Package metrics provides session counters exposed in the Prometheus text format
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry holds the counters recorded during a session
+type Registry struct {
+	mu              sync.Mutex
+	toolInvocations map[string]int64
+	toolErrors      map[string]int64
+	tokens          map[string]int64
+	apiRetries      int64
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		toolInvocations: make(map[string]int64),
+		toolErrors:      make(map[string]int64),
+		tokens:          make(map[string]int64),
+	}
+}
+
+var defaultRegistry = NewRegistry()
+
+// Get returns the global metrics registry
+func Get() *Registry {
+	return defaultRegistry
+}
+
+// RecordToolInvocation counts a single invocation of the named tool
+func (r *Registry) RecordToolInvocation(tool string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.toolInvocations[tool]++
+}
+
+// RecordToolError counts a failed invocation of the named tool
+func (r *Registry) RecordToolError(tool string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.toolErrors[tool]++
+}
+
+// RecordTokens adds consumed input and output tokens
+func (r *Registry) RecordTokens(input, output int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens["input"] += input
+	r.tokens["output"] += output
+}
+
+// RecordAPIRetry counts a retried API request
+func (r *Registry) RecordAPIRetry() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apiRetries++
+}
+
+// WriteTo writes all counters to w in the Prometheus text exposition format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+	writeLabeledCounter(&b, "metamorph_tool_invocations_total", "Total number of tool invocations.", "tool", r.toolInvocations)
+	writeLabeledCounter(&b, "metamorph_tool_errors_total", "Total number of tool invocations that returned an error.", "tool", r.toolErrors)
+	writeLabeledCounter(&b, "metamorph_tokens_total", "Total number of tokens consumed.", "type", r.tokens)
+	fmt.Fprintf(&b, "# HELP metamorph_api_retries_total Total number of retried Anthropic API requests.\n")
+	fmt.Fprintf(&b, "# TYPE metamorph_api_retries_total counter\n")
+	fmt.Fprintf(&b, "metamorph_api_retries_total %d\n", r.apiRetries)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// writeLabeledCounter writes a counter with one label, sorted by label value
+func writeLabeledCounter(b *strings.Builder, name, help, label string, values map[string]int64) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s counter\n", name)
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(b, "%s{%s=%q} %d\n", name, label, k, values[k])
+	}
+}
+
+// Handler returns an HTTP handler serving the registry's metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = r.WriteTo(w)
+	})
+}
+
+// Serve exposes the registry on addr at /metrics. It blocks until the server stops.
+func (r *Registry) Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r.Handler())
+	return http.ListenAndServe(addr, mux)
+}
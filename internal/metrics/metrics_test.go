@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandlerReportsRecordedActivity(t *testing.T) {
+	registry := NewRegistry()
+	registry.RecordToolInvocation("file_reader")
+	registry.RecordToolInvocation("file_reader")
+	registry.RecordToolInvocation("file_editor")
+	registry.RecordToolError("file_editor")
+	registry.RecordTokens(120, 30)
+	registry.RecordTokens(80, 20)
+	registry.RecordAPIRetry()
+
+	server := httptest.NewServer(registry.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", contentType)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`metamorph_tool_invocations_total{tool="file_editor"} 1`,
+		`metamorph_tool_invocations_total{tool="file_reader"} 2`,
+		`metamorph_tool_errors_total{tool="file_editor"} 1`,
+		`metamorph_tokens_total{type="input"} 200`,
+		`metamorph_tokens_total{type="output"} 50`,
+		`metamorph_api_retries_total 1`,
+		`# TYPE metamorph_tool_invocations_total counter`,
+	} {
+		if !strings.Contains(string(body), want+"\n") {
+			t.Errorf("scrape is missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestWriteToEmptyRegistry(t *testing.T) {
+	var b strings.Builder
+	if _, err := NewRegistry().WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "metamorph_api_retries_total 0\n") {
+		t.Errorf("empty registry should report zero retries:\n%s", b.String())
+	}
+	if strings.Contains(b.String(), "{") {
+		t.Errorf("empty registry should have no labeled samples:\n%s", b.String())
+	}
+}
@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"metamorph/internal/agent"
+	"metamorph/internal/agent/tools"
 	"metamorph/internal/config"
 	"metamorph/internal/logger"
+	"metamorph/internal/metrics"
 	"os"
 	"time"
 )
@@ -22,6 +24,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Mask secrets in logged tool inputs, including any configured patterns
+	if err := logger.SetRedactionPatterns(cfg.RedactPatterns); err != nil {
+		logger.Get().Fatal().Err(err).Msg("Invalid redaction patterns")
+		os.Exit(1)
+	}
+
 	// Apply defaults and validate
 	cfg = cfg.WithDefaults()
 	if err := cfg.Validate(); err != nil {
@@ -29,6 +37,48 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Register configured macros alongside the built-in ones
+	if err := tools.RegisterMacros(cfg.Macros); err != nil {
+		logger.Get().Fatal().Err(err).Msg("Invalid macro configuration")
+		os.Exit(1)
+	}
+
+	// Register configured linters alongside the built-in ones
+	if err := tools.RegisterLinters(cfg.Linters); err != nil {
+		logger.Get().Fatal().Err(err).Msg("Invalid linter configuration")
+		os.Exit(1)
+	}
+
+	// Restore the memory scratchpad if it is persisted
+	if cfg.MemoryFile != "" {
+		if err := tools.LoadMemory(cfg.MemoryFile); err != nil {
+			logger.Get().Fatal().Err(err).Msg("Failed to load memory file")
+			os.Exit(1)
+		}
+	}
+
+	// Fail fast if a required binary is missing, and warn about the optional ones
+	prereqs, err := tools.Preflight(context.Background())
+	for _, prereq := range prereqs {
+		if !prereq.Required && prereq.Status != "ok" {
+			logger.Get().Warn().Str("binary", prereq.Name).Strs("usedBy", prereq.UsedBy).Msg(prereq.Message)
+		}
+	}
+	if err != nil {
+		logger.Get().Fatal().Err(err).Msg("Preflight check failed")
+		os.Exit(1)
+	}
+
+	// Expose metrics if an address is configured
+	if cfg.MetricsAddr != "" {
+		go func() {
+			logger.Get().Info().Str("addr", cfg.MetricsAddr).Msg("Serving metrics")
+			if err := metrics.Get().Serve(cfg.MetricsAddr); err != nil {
+				logger.Get().Error().Err(err).Msg("Metrics server stopped")
+			}
+		}()
+	}
+
 	// Configure loop protection
 	loopProtection := agent.NewLoopProtection()
 	loopProtection.MaxConsecutiveToolUses = 100
@@ -38,18 +88,42 @@ func main() {
 
 	// Create and start the agent
 	agentConfig := agent.Config{
-		Client:         cfg.Client,
-		GetUserMessage: cfg.GetUserMessage,
-		Tools:          cfg.Tools,
-		Model:          cfg.Model,
-		MaxTokens:      cfg.MaxTokens,
-		LoopProtection: &loopProtection,
+		Client:          cfg.Client,
+		GetUserMessage:  cfg.GetUserMessage,
+		Tools:           cfg.Tools,
+		Model:           cfg.Model,
+		MaxTokens:       cfg.MaxTokens,
+		LoopProtection:  &loopProtection,
+		IdleTimeout:     cfg.IdleTimeout,
+		MaxInputSize:    cfg.MaxToolInputSize,
+		ReadOnly:        cfg.ReadOnly,
+		MessagePrefix:   cfg.MessagePrefix,
+		MessageSuffix:   cfg.MessageSuffix,
+		MaxTurns:        cfg.MaxTurns,
+		ToolCallDelay:   cfg.ToolCallDelay,
+		AllowedTools:    cfg.AllowedTools,
+		StrictAllowlist: cfg.StrictAllowlist,
+		WorkingDir:      cfg.WorkingDir,
 	}
 
 	agentInstance := agent.New(agentConfig)
 
-	if err := agentInstance.Run(context.Background()); err != nil {
-		logger.Get().Fatal().Err(err).Msg("Agent run failed")
+	runErr := agentInstance.Run(context.Background())
+
+	// Remove any scratch directories the agent left behind
+	if removed, err := tools.CleanupTempWorkspaces(); err != nil {
+		logger.Get().Error().Err(err).Msg("Failed to clean up temporary workspaces")
+	} else if len(removed) > 0 {
+		logger.Get().Info().Strs("workspaces", removed).Msg("Cleaned up temporary workspaces")
+	}
+
+	// Kill any background processes that are still running
+	if stopped := tools.StopBackgroundProcesses(); len(stopped) > 0 {
+		logger.Get().Info().Strs("processes", stopped).Msg("Stopped background processes")
+	}
+
+	if runErr != nil {
+		logger.Get().Fatal().Err(runErr).Msg("Agent run failed")
 		os.Exit(1)
 	}
 }